@@ -0,0 +1,27 @@
+//! Build script: records tree-sitter grammar versions from Cargo.lock
+//! so the analysis cache is invalidated when a grammar is upgraded.
+
+use std::fs;
+
+fn main() {
+    println!("cargo:rerun-if-changed=Cargo.lock");
+    
+    let lock = fs::read_to_string("Cargo.lock").unwrap_or_default();
+    let mut versions = Vec::new();
+    let mut current_name: Option<String> = None;
+    
+    for line in lock.lines() {
+        if let Some(name) = line.strip_prefix("name = ") {
+            current_name = Some(name.trim_matches('"').to_string());
+        } else if let Some(version) = line.strip_prefix("version = ") {
+            if let Some(name) = current_name.take() {
+                if name.starts_with("tree-sitter") {
+                    versions.push(format!("{}={}", name, version.trim_matches('"')));
+                }
+            }
+        }
+    }
+    
+    versions.sort();
+    println!("cargo:rustc-env=NEKOCODE_GRAMMAR_VERSIONS={}", versions.join(","));
+}
@@ -0,0 +1,20 @@
+//! Post-analysis shared by the tree-sitter analyzers
+
+use tree_sitter::Tree;
+
+use crate::core::types::AnalysisResult;
+use crate::metrics::magic_numbers::MagicNumberRules;
+use crate::metrics::{annotate_line_metrics, apply_metric_plugins, collect_magic_numbers, collect_markers};
+
+/// Run the tree-based metrics on a file whose symbols are extracted, then refresh its statistics
+///
+/// Comment markers (TODO, FIXME, ...) are attributed to the enclosing function,
+/// line metrics cover functions and file totals, magic numbers become `smells`
+/// and registered plugins fill `custom` on each function.
+pub fn finish_analysis(tree: &Tree, source: &str, result: &mut AnalysisResult, magic_rules: &MagicNumberRules) {
+    collect_markers(tree, source, result);
+    annotate_line_metrics(tree, source, result);
+    collect_magic_numbers(tree, source, result, magic_rules);
+    apply_metric_plugins(tree, source, result);
+    result.update_statistics();
+}
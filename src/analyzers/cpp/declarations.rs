@@ -0,0 +1,103 @@
+//! C++ declarations and definitions
+//!
+//! A C++ function is often declared in one place (`double area() const;` in a
+//! header's class, `int parse(const char*);` at namespace scope) and defined in
+//! another. The analyzer keeps prototypes apart from `functions` as
+//! `declarations`, so each function is counted once, at its definition. Both
+//! sides carry the same signature, the qualified name plus parameter types
+//! (`geo::Shape::area() const`). This pass points each side at the other as
+//! `path:line`: `defined_at` on the declaration, `declared_at` metadata on the
+//! definition.
+
+use std::collections::HashMap;
+use std::path::Path;
+
+use crate::core::types::{AnalysisResult, Language};
+
+/// Link C++ declarations and definitions across the given files
+pub fn link_declarations(files: &mut [AnalysisResult]) {
+    // Signature -> first definition / declaration, in file order
+    let mut definitions: HashMap<String, String> = HashMap::new();
+    for file in files.iter().filter(|f| f.language == Language::Cpp) {
+        let methods = file.classes.iter().flat_map(|c| c.methods.iter());
+        for func in file.functions.iter().chain(methods) {
+            if let Some(signature) = func.metadata.get("signature") {
+                definitions.entry(signature.clone())
+                    .or_insert_with(|| location(&file.file_info.path, func.start_line));
+            }
+        }
+    }
+
+    let mut declarations: HashMap<String, String> = HashMap::new();
+    for file in files.iter_mut().filter(|f| f.language == Language::Cpp) {
+        for declaration in &mut file.declarations {
+            declaration.defined_at = definitions.get(&declaration.signature).cloned();
+            declarations.entry(declaration.signature.clone())
+                .or_insert_with(|| location(&file.file_info.path, declaration.line_number));
+        }
+    }
+
+    // Recomputed each time, so linking per file and then per directory is idempotent
+    for file in files.iter_mut().filter(|f| f.language == Language::Cpp) {
+        let methods = file.classes.iter_mut().flat_map(|c| c.methods.iter_mut());
+        for func in file.functions.iter_mut().chain(methods) {
+            let declared_at = func.metadata.get("signature").and_then(|s| declarations.get(s)).cloned();
+            match declared_at {
+                Some(at) => { func.metadata.insert("declared_at".to_string(), at); }
+                None => { func.metadata.remove("declared_at"); }
+            }
+        }
+    }
+}
+
+/// Helper: `path:line` of a symbol
+fn location(path: &Path, line: u32) -> String {
+    format!("{}:{}", path.display(), line)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{DeclarationInfo, FileInfo, FunctionInfo};
+    use std::path::PathBuf;
+
+    fn declaration(name: &str, signature: &str, line_number: u32) -> DeclarationInfo {
+        DeclarationInfo {
+            name: name.to_string(),
+            signature: signature.to_string(),
+            line_number,
+            type_params: Vec::new(),
+            defined_at: None,
+        }
+    }
+
+    fn definition(name: &str, signature: &str, start_line: u32) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = start_line;
+        func.metadata.insert("signature".to_string(), signature.to_string());
+        func
+    }
+
+    #[test]
+    fn test_link_header_and_source() {
+        let mut header = AnalysisResult::new(FileInfo::new(PathBuf::from("shape.hpp")), Language::Cpp);
+        header.declarations = vec![
+            declaration("area", "geo::Shape::area() const", 4),
+            declaration("scale", "geo::Shape::scale(double)", 5),
+        ];
+        let mut source = AnalysisResult::new(FileInfo::new(PathBuf::from("shape.cpp")), Language::Cpp);
+        source.functions = vec![
+            definition("area", "geo::Shape::area() const", 3),
+            definition("helper", "geo::helper()", 7),
+        ];
+        let mut files = vec![header, source];
+
+        link_declarations(&mut files);
+        link_declarations(&mut files);
+
+        assert_eq!(files[0].declarations[0].defined_at.as_deref(), Some("shape.cpp:3"));
+        assert_eq!(files[0].declarations[1].defined_at, None);
+        assert_eq!(files[1].functions[0].metadata.get("declared_at").map(String::as_str), Some("shape.hpp:4"));
+        assert!(!files[1].functions[1].metadata.contains_key("declared_at"));
+    }
+}
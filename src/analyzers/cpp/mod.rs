@@ -1,6 +1,8 @@
 pub mod analyzer;
 pub mod tree_sitter_analyzer;
+pub mod declarations;
 // Grammar is embedded in analyzer.rs via pest_derive
 
 pub use analyzer::CppAnalyzer;
-pub use tree_sitter_analyzer::TreeSitterCppAnalyzer;
\ No newline at end of file
+pub use tree_sitter_analyzer::TreeSitterCppAnalyzer;
+pub use declarations::link_declarations;
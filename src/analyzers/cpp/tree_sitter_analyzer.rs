@@ -1,21 +1,296 @@
 //! 🚀 Tree-sitter based C++ analyzer
 //! 100x faster than PEST implementation!
+//!
+//! Declarations are walked in scope order, so every symbol knows its enclosing
+//! `namespace`, class and `template <...>` header:
+//!
+//! - functions and classes carry their template parameters as `type_params`;
+//!   non-type and concept-constrained parameters keep their type as constraint
+//! - names are stable: constructors take the class name, destructors `~Shape`,
+//!   operators `operator==` / `operator()` / `operator new`, conversions `operator bool`
+//! - an out-of-class definition (`double geo::Shape::area() const`) is a method
+//!   of `Shape`; a scope not opened as a `namespace` in the file is taken for a class
+//! - prototypes without a body are `declarations`, not functions, and share a
+//!   signature with their definition for `link_declarations`
+//! - calls keep their scope (`geo::area()`, `Shape::create()`) or object
+//!   (`obj.area()`, `ptr->area()`); calls on `this` carry the enclosing class
 
 use anyhow::Result;
+use std::collections::HashSet;
 use tree_sitter::{Parser, Query, QueryCursor, Node};
 use async_trait::async_trait;
 
 use crate::core::types::{
-    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, 
-    Language, ComplexityInfo, ImportType
+    AnalysisResult, ClassInfo, DeclarationInfo, FileInfo, FunctionCall, FunctionInfo, ImportInfo,
+    Language, ComplexityInfo, ImportType, TypeParameter
 };
 use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
 use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::cpp::declarations::link_declarations;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, magic_numbers, max_nesting_depth, node_span, raw_hash};
 
 pub struct TreeSitterCppAnalyzer {
     parser: Parser,
 }
 
+/// Enclosing namespaces and classes of a declaration
+#[derive(Debug, Clone, Default)]
+struct Scope {
+    namespaces: Vec<String>,
+    classes: Vec<String>,
+}
+
+/// A function's stable name and where it belongs
+struct Callable {
+    name: String,
+    namespaces: Vec<String>,
+    classes: Vec<String>,
+    /// `constructor`, `destructor`, `operator`, `method` or `function`
+    kind: &'static str,
+    signature: String,
+}
+
+impl Callable {
+    /// Helper: `geo::Shape::area`
+    fn qualified_name(&self) -> String {
+        let mut parts: Vec<&str> = self.namespaces.iter().chain(&self.classes).map(String::as_str).collect();
+        parts.push(&self.name);
+        parts.join("::")
+    }
+}
+
+/// Declaration walk over one file: classes and prototypes are collected here,
+/// definitions go to the function list of the scope being walked
+struct Walker<'a> {
+    source: &'a str,
+    /// Names opened as `namespace` in the file, and `std`
+    namespaces: &'a HashSet<String>,
+    classes: Vec<ClassInfo>,
+    declarations: Vec<DeclarationInfo>,
+}
+
+impl<'a> Walker<'a> {
+    /// Walk the items of a translation unit, namespace, linkage block or class body
+    fn items(&mut self, node: Node, scope: &Scope, functions: &mut Vec<FunctionInfo>) {
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            self.item(child, scope, &[], functions);
+        }
+    }
+    
+    /// Walk one item; `template` holds the parameters of an enclosing `template <...>` header
+    fn item(&mut self, node: Node, scope: &Scope, template: &[TypeParameter], functions: &mut Vec<FunctionInfo>) {
+        match node.kind() {
+            "namespace_definition" => {
+                let mut inner = scope.clone();
+                // `namespace a::b`; anonymous namespaces add nothing
+                if let Some(name) = node.child_by_field_name("name") {
+                    inner.namespaces.extend(text(name, self.source).split("::").map(|s| s.trim().to_string()).filter(|s| !s.is_empty()));
+                }
+                if let Some(body) = node.child_by_field_name("body") {
+                    self.items(body, &inner, functions);
+                }
+            }
+            // extern "C" { ... } / extern "C" void f();
+            "linkage_specification" => {
+                if let Some(body) = node.child_by_field_name("body") {
+                    if body.kind() == "declaration_list" {
+                        self.items(body, scope, functions);
+                    } else {
+                        self.item(body, scope, template, functions);
+                    }
+                }
+            }
+            "template_declaration" => {
+                // Member templates of class templates have both headers' parameters
+                let mut params = template.to_vec();
+                if let Some(list) = node.child_by_field_name("parameters") {
+                    params.extend(template_params(list, self.source));
+                }
+                let mut cursor = node.walk();
+                for child in node.named_children(&mut cursor) {
+                    if child.kind() != "template_parameter_list" {
+                        self.item(child, scope, &params, functions);
+                    }
+                }
+            }
+            "function_definition" => {
+                // `Shape(const Shape&) = delete;` declares without defining
+                let mut cursor = node.walk();
+                if node.children(&mut cursor).any(|c| c.kind() == "delete_method_clause") {
+                    if let Some(declarator) = node.child_by_field_name("declarator") {
+                        self.declaration(node, declarator, scope, template);
+                    }
+                } else if let Some(func_info) = self.function(node, scope, template) {
+                    functions.push(func_info);
+                }
+            }
+            "declaration" | "field_declaration" => {
+                // class Point { ... } origin; and nested classes in a class body
+                if let Some(type_node) = node.child_by_field_name("type") {
+                    if matches!(type_node.kind(), "class_specifier" | "struct_specifier") {
+                        self.class(type_node, scope, template);
+                    }
+                }
+                let mut cursor = node.walk();
+                let declarators: Vec<Node> = node.children_by_field_name("declarator", &mut cursor).collect();
+                for declarator in declarators {
+                    self.declaration(node, declarator, scope, template);
+                }
+            }
+            "class_specifier" | "struct_specifier" => self.class(node, scope, template),
+            // Include guards and other conditional blocks
+            kind if kind.starts_with("preproc_") || kind == "ERROR" => self.items(node, scope, functions),
+            _ => {}
+        }
+    }
+    
+    /// Helper: Build FunctionInfo for a function definition in `scope`
+    fn function(&self, node: Node, scope: &Scope, template: &[TypeParameter]) -> Option<FunctionInfo> {
+        let (name_node, declarator) = function_declarator(node.child_by_field_name("declarator")?)?;
+        let callable = self.callable(name_node, declarator, scope)?;
+        
+        let mut func_info = FunctionInfo::new(callable.name.clone());
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, self.source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.parameters = extract_parameters(declarator, self.source);
+        func_info.type_params = template.to_vec();
+        func_info.is_async = false; // C++ doesn't have async/await like JS/Python
+        
+        // Check for inline, virtual, static keywords
+        func_info.metadata.extend(extract_function_modifiers(node, declarator, self.source));
+        func_info.metadata.insert("type".to_string(), callable.kind.to_string());
+        func_info.metadata.insert("qualified_name".to_string(), callable.qualified_name());
+        func_info.metadata.insert("signature".to_string(), callable.signature.clone());
+        if !callable.namespaces.is_empty() {
+            func_info.metadata.insert("namespace".to_string(), callable.namespaces.join("::"));
+        }
+        match callable.kind {
+            "constructor" => { func_info.metadata.insert("is_constructor".to_string(), "true".to_string()); }
+            "destructor" => { func_info.metadata.insert("is_destructor".to_string(), "true".to_string()); }
+            _ => {}
+        }
+        // Out-of-class definition: `void Shape::draw() { ... }`
+        if scope.classes.is_empty() {
+            if let Some(class_name) = callable.classes.last() {
+                func_info.metadata.insert("class_name".to_string(), class_name.clone());
+            }
+        }
+        
+        func_info.complexity = ComplexityInfo::default();
+        func_info.complexity.cognitive_complexity = cognitive_complexity(node, self.source, &cognitive::CPP);
+        func_info.complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::CPP);
+        func_info.body_hash = body_hash(node, self.source);
+        func_info.raw_hash = raw_hash(node, self.source);
+        func_info.clone_tokens = clone_tokens(node);
+        
+        Some(func_info)
+    }
+    
+    /// Helper: Record a prototype (`declarator` of a declaration without a body)
+    fn declaration(&mut self, node: Node, declarator: Node, scope: &Scope, template: &[TypeParameter]) {
+        let Some((name_node, function)) = function_declarator(declarator) else { return };
+        let Some(callable) = self.callable(name_node, function, scope) else { return };
+        self.declarations.push(DeclarationInfo {
+            name: callable.name,
+            signature: callable.signature,
+            line_number: node.start_position().row as u32 + 1,
+            type_params: template.to_vec(),
+            defined_at: None,
+        });
+    }
+    
+    /// Helper: Build ClassInfo for a class or struct with a body, then walk its members
+    fn class(&mut self, node: Node, scope: &Scope, template: &[TypeParameter]) {
+        // Forward declarations (`class Shape;`) and anonymous structs are not classes of their own
+        let (Some(name_node), Some(body)) = (node.child_by_field_name("name"), node.child_by_field_name("body")) else { return };
+        let name = base_name(name_node, self.source);
+        
+        let mut class_info = ClassInfo::new(name.clone());
+        class_info.start_line = node.start_position().row as u32 + 1;
+        class_info.span = node_span(node, self.source);
+        class_info.end_line = node.end_position().row as u32 + 1;
+        class_info.type_params = template.to_vec();
+        
+        // Check if it's a struct
+        if node.kind() == "struct_specifier" {
+            class_info.metadata.insert("type".to_string(), "struct".to_string());
+        } else {
+            class_info.metadata.insert("type".to_string(), "class".to_string());
+        }
+        if !scope.namespaces.is_empty() {
+            class_info.metadata.insert("namespace".to_string(), scope.namespaces.join("::"));
+        }
+        let mut inner = scope.clone();
+        inner.classes.push(name);
+        class_info.metadata.insert("qualified_name".to_string(), inner.namespaces.iter().chain(&inner.classes).cloned().collect::<Vec<_>>().join("::"));
+        
+        // Extract base classes (inheritance)
+        let base_classes = extract_base_classes(node, self.source);
+        if !base_classes.is_empty() {
+            class_info.parent_class = Some(base_classes[0].clone());
+            if base_classes.len() > 1 {
+                class_info.metadata.insert("multiple_inheritance".to_string(), base_classes.join(", "));
+            }
+        }
+        
+        // Outer classes come before the classes nested in them
+        let index = self.classes.len();
+        self.classes.push(class_info);
+        
+        let mut methods = Vec::new();
+        self.items(body, &inner, &mut methods);
+        for method in &mut methods {
+            method.metadata.insert("is_class_method".to_string(), "true".to_string());
+        }
+        self.classes[index].methods = methods;
+    }
+    
+    /// Helper: Stable name, owner and signature of a function declarator's name in `scope`
+    fn callable(&self, name_node: Node, declarator: Node, scope: &Scope) -> Option<Callable> {
+        let (segments, name) = split_name(name_node, self.source)?;
+        
+        // `geo::Shape::area`: leading scopes opened as namespaces extend the
+        // namespace, the rest name the class (always, for `Shape::Shape` / `Shape::~Shape`)
+        let mut split = if scope.classes.is_empty() {
+            segments.iter().take_while(|s| self.namespaces.contains(*s)).count()
+        } else {
+            0
+        };
+        if segments.last().map_or(false, |s| *s == name || name.starts_with('~')) {
+            split = split.min(segments.len() - 1);
+        }
+        let mut namespaces = scope.namespaces.clone();
+        namespaces.extend(segments[..split].iter().cloned());
+        let mut classes = scope.classes.clone();
+        classes.extend(segments[split..].iter().cloned());
+        
+        let kind = if classes.last() == Some(&name) {
+            "constructor"
+        } else if name.starts_with('~') {
+            "destructor"
+        } else if is_operator(&name) {
+            "operator"
+        } else if !classes.is_empty() {
+            "method"
+        } else {
+            "function"
+        };
+        
+        let mut callable = Callable { name, namespaces, classes, kind, signature: String::new() };
+        let types = declarator.child_by_field_name("parameters")
+            .map(|params| parameter_types(params, self.source))
+            .unwrap_or_default();
+        let mut cursor = declarator.walk();
+        let is_const = declarator.children(&mut cursor)
+            .any(|c| c.kind() == "type_qualifier" && text(c, self.source) == "const");
+        callable.signature = format!("{}({}){}", callable.qualified_name(), types.join(","), if is_const { " const" } else { "" });
+        Some(callable)
+    }
+}
+
 impl TreeSitterCppAnalyzer {
     pub fn new() -> Result<Self> {
         let mut parser = Parser::new();
@@ -25,21 +300,25 @@ impl TreeSitterCppAnalyzer {
         Ok(Self { parser })
     }
     
-    /// Extract functions using tree-sitter query
-    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+    /// Extract function definitions, classes and prototypes by walking declarations in scope
+    fn extract_symbols(&self, tree: &tree_sitter::Tree, source: &str, namespaces: &HashSet<String>) -> (Vec<FunctionInfo>, Vec<ClassInfo>, Vec<DeclarationInfo>) {
+        let mut walker = Walker {
+            source,
+            namespaces,
+            classes: Vec::new(),
+            declarations: Vec::new(),
+        };
         let mut functions = Vec::new();
+        walker.items(tree.root_node(), &Scope::default(), &mut functions);
+        (functions, walker.classes, walker.declarations)
+    }
+    
+    /// Names opened as `namespace` anywhere in the file, and `std`
+    fn extract_namespaces(&self, tree: &tree_sitter::Tree, source: &str) -> Result<HashSet<String>> {
+        let mut namespaces: HashSet<String> = HashSet::from(["std".to_string()]);
         
-        // Query for function definitions
         let query_str = r#"
-            [
-              (function_definition
-                declarator: (function_declarator 
-                  declarator: (identifier) @name)) @function
-              (function_definition
-                declarator: (function_declarator 
-                  declarator: (qualified_identifier 
-                    name: (identifier) @name))) @function
-            ]
+            (namespace_definition name: (_) @name)
         "#;
         
         let query = Query::new(&tree_sitter_cpp::LANGUAGE.into(), query_str)?;
@@ -47,54 +326,21 @@ impl TreeSitterCppAnalyzer {
         let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
         
         for mat in matches {
-            let mut func_info = FunctionInfo::new(String::new());
-            let mut func_node = None;
-            
             for capture in mat.captures {
-                match query.capture_names()[capture.index as usize].as_ref() {
-                    "name" => {
-                        func_info.name = capture.node.utf8_text(source.as_bytes())?.to_string();
-                    }
-                    "function" => {
-                        func_node = Some(capture.node);
-                        func_info.start_line = capture.node.start_position().row as u32 + 1;
-                        func_info.end_line = capture.node.end_position().row as u32 + 1;
-                    }
-                    _ => {}
-                }
-            }
-            
-            // Extract parameters if we have a function node
-            if let Some(node) = func_node {
-                func_info.parameters = self.extract_parameters(node, source)?;
-                func_info.is_async = false; // C++ doesn't have async/await like JS/Python
-                
-                // Check for inline, virtual, static keywords
-                func_info.metadata.extend(self.extract_function_modifiers(node, source)?);
+                let name = capture.node.utf8_text(source.as_bytes())?;
+                namespaces.extend(name.split("::").map(|s| s.trim().to_string()).filter(|s| !s.is_empty()));
             }
-            
-            // Set default complexity (will be calculated separately)
-            func_info.complexity = ComplexityInfo::default();
-            
-            functions.push(func_info);
         }
         
-        Ok(functions)
+        Ok(namespaces)
     }
     
-    /// Extract classes using tree-sitter query
-    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ClassInfo>> {
-        let mut classes = Vec::new();
+    /// Extract calls: `f()`, `geo::area()`, `Shape::create()`, `obj.area()`, `ptr->area()`
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str, namespaces: &HashSet<String>) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
         
         let query_str = r#"
-            [
-              (class_specifier 
-                name: (type_identifier) @name
-                body: (field_declaration_list)? @body) @class
-              (struct_specifier 
-                name: (type_identifier) @name
-                body: (field_declaration_list)? @body) @class
-            ]
+            (call_expression function: (_) @callee)
         "#;
         
         let query = Query::new(&tree_sitter_cpp::LANGUAGE.into(), query_str)?;
@@ -102,50 +348,41 @@ impl TreeSitterCppAnalyzer {
         let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
         
         for mat in matches {
-            let mut class_info = ClassInfo::new(String::new());
-            let mut class_node = None;
-            
             for capture in mat.captures {
-                match query.capture_names()[capture.index as usize].as_ref() {
-                    "name" => {
-                        class_info.name = capture.node.utf8_text(source.as_bytes())?.to_string();
-                    }
-                    "class" => {
-                        class_node = Some(capture.node);
-                        class_info.start_line = capture.node.start_position().row as u32 + 1;
-                        class_info.end_line = capture.node.end_position().row as u32 + 1;
+                let callee = capture.node;
+                let line_number = callee.start_position().row as u32 + 1;
+                
+                let function_call = match callee.kind() {
+                    // obj.area() / ptr->area() / this->area()
+                    "field_expression" => {
+                        let (Some(object), Some(field)) = (callee.child_by_field_name("argument"), callee.child_by_field_name("field")) else { continue };
+                        let Some((_, name)) = split_name(field, source) else { continue };
                         
-                        // Check if it's a struct
-                        if capture.node.kind() == "struct_specifier" {
-                            class_info.metadata.insert("type".to_string(), "struct".to_string());
-                        } else {
-                            class_info.metadata.insert("type".to_string(), "class".to_string());
+                        let mut call = FunctionCall::new(name, line_number);
+                        call.object_name = Some(object.utf8_text(source.as_bytes())?.to_string());
+                        call.is_method_call = true;
+                        if object.kind() == "this" {
+                            call.receiver_type = enclosing_class(callee, source);
                         }
+                        call
                     }
-                    _ => {}
-                }
-            }
-            
-            // Extract methods and inheritance
-            if let Some(node) = class_node {
-                class_info.methods = self.extract_class_methods(node, source)?;
-                
-                // Extract base classes (inheritance)
-                if let Some(base_clause) = node.child_by_field_name("base_class_clause") {
-                    let base_classes = self.extract_base_classes(base_clause, source)?;
-                    if !base_classes.is_empty() {
-                        class_info.parent_class = Some(base_classes[0].clone());
-                        if base_classes.len() > 1 {
-                            class_info.metadata.insert("multiple_inheritance".to_string(), base_classes.join(", "));
+                    _ => {
+                        let Some((scope, name)) = split_name(callee, source) else { continue };
+                        
+                        let mut call = FunctionCall::new(name, line_number);
+                        if let Some(last) = scope.last() {
+                            // `geo::area()` calls a free function, `Shape::create()` a static method
+                            call.is_method_call = !namespaces.contains(last);
+                            call.object_name = Some(scope.join("::"));
                         }
+                        call
                     }
-                }
+                };
+                function_calls.push(function_call);
             }
-            
-            classes.push(class_info);
         }
         
-        Ok(classes)
+        Ok(function_calls)
     }
     
     /// Extract includes using tree-sitter query
@@ -184,125 +421,7 @@ impl TreeSitterCppAnalyzer {
         Ok(imports)
     }
     
-    /// Helper: Extract parameters from a function node
-    fn extract_parameters(&self, node: Node, source: &str) -> Result<Vec<String>> {
-        let mut params = Vec::new();
-        
-        // Look for parameter_list in the function_declarator
-        if let Some(declarator) = node.child_by_field_name("declarator") {
-            if let Some(param_list) = declarator.child_by_field_name("parameters") {
-                let mut cursor = param_list.walk();
-                for child in param_list.children(&mut cursor) {
-                    if child.kind() == "parameter_declaration" {
-                        if let Some(declarator) = child.child_by_field_name("declarator") {
-                            if let Ok(param_text) = declarator.utf8_text(source.as_bytes()) {
-                                params.push(param_text.to_string());
-                            }
-                        } else if let Some(type_node) = child.child_by_field_name("type") {
-                            // For unnamed parameters, use the type
-                            if let Ok(type_text) = type_node.utf8_text(source.as_bytes()) {
-                                params.push(format!("unnamed_{}", type_text));
-                            }
-                        }
-                    }
-                }
-            }
-        }
-        
-        Ok(params)
-    }
-    
-    /// Extract function modifiers (virtual, static, inline, etc.)
-    fn extract_function_modifiers(&self, node: Node, _source: &str) -> Result<std::collections::HashMap<String, String>> {
-        let mut metadata = std::collections::HashMap::new();
-        let mut modifiers = Vec::new();
-        
-        // Look for storage class specifiers and other modifiers
-        if let Some(parent) = node.parent() {
-            let mut cursor = parent.walk();
-            for child in parent.children(&mut cursor) {
-                match child.kind() {
-                    "virtual" => modifiers.push("virtual".to_string()),
-                    "static" => modifiers.push("static".to_string()),
-                    "inline" => modifiers.push("inline".to_string()),
-                    "explicit" => modifiers.push("explicit".to_string()),
-                    "const" => modifiers.push("const".to_string()),
-                    _ => {}
-                }
-            }
-        }
-        
-        if !modifiers.is_empty() {
-            metadata.insert("modifiers".to_string(), modifiers.join(" "));
-        }
-        
-        Ok(metadata)
-    }
-    
-    /// Extract base classes from base class clause
-    fn extract_base_classes(&self, node: Node, source: &str) -> Result<Vec<String>> {
-        let mut base_classes = Vec::new();
-        
-        let mut cursor = node.walk();
-        for child in node.children(&mut cursor) {
-            if child.kind() == "base_class_clause" {
-                let mut subchild_cursor = child.walk();
-                for subchild in child.children(&mut subchild_cursor) {
-                    if subchild.kind() == "type_identifier" {
-                        if let Ok(class_name) = subchild.utf8_text(source.as_bytes()) {
-                            base_classes.push(class_name.to_string());
-                        }
-                    }
-                }
-            }
-        }
-        
-        Ok(base_classes)
-    }
-    
-    /// Extract methods from a class node
-    fn extract_class_methods(&self, class_node: Node, source: &str) -> Result<Vec<FunctionInfo>> {
-        let mut methods = Vec::new();
-        
-        if let Some(body) = class_node.child_by_field_name("body") {
-            let mut cursor = body.walk();
-            for child in body.children(&mut cursor) {
-                if child.kind() == "function_definition" {
-                    let mut method = FunctionInfo::new(String::new());
-                    
-                    if let Some(declarator) = child.child_by_field_name("declarator") {
-                        if let Some(func_declarator) = declarator.child_by_field_name("declarator") {
-                            if let Ok(name) = func_declarator.utf8_text(source.as_bytes()) {
-                                method.name = name.to_string();
-                            }
-                        }
-                    }
-                    
-                    method.start_line = child.start_position().row as u32 + 1;
-                    method.end_line = child.end_position().row as u32 + 1;
-                    method.parameters = self.extract_parameters(child, source)?;
-                    method.metadata.insert("is_class_method".to_string(), "true".to_string());
-                    
-                    // Check for special methods (constructor, destructor)
-                    if method.name.starts_with('~') {
-                        method.metadata.insert("is_destructor".to_string(), "true".to_string());
-                    } else if method.name == class_node.child_by_field_name("name")
-                        .and_then(|n| n.utf8_text(source.as_bytes()).ok())
-                        .unwrap_or("") {
-                        method.metadata.insert("is_constructor".to_string(), "true".to_string());
-                    }
-                    
-                    // Extract method modifiers
-                    method.metadata.extend(self.extract_function_modifiers(child, source)?);
-                    
-                    methods.push(method);
-                }
-            }
-        }
-        
-        Ok(methods)
-    }
-    
+
     /// Build AST from tree-sitter CST
     fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
         let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
@@ -363,6 +482,276 @@ impl TreeSitterCppAnalyzer {
     }
 }
 
+
+/// Helper: Source text of a node
+fn text<'a>(node: Node, source: &'a str) -> &'a str {
+    node.utf8_text(source.as_bytes()).unwrap_or("")
+}
+
+/// Helper: Collapse whitespace, keeping a space only between two words
+/// (`const  std::string &` is `const std::string&`)
+fn collapse(text: &str) -> String {
+    let is_word = |c: Option<char>| c.map_or(false, |c| c.is_alphanumeric() || c == '_');
+    let mut out = String::new();
+    for word in text.split_whitespace() {
+        if is_word(out.chars().last()) && is_word(word.chars().next()) {
+            out.push(' ');
+        }
+        out.push_str(word);
+    }
+    out
+}
+
+/// Helper: Name of a type or scope without template arguments (`Shape<T>` is `Shape`)
+fn base_name(node: Node, source: &str) -> String {
+    let node = if node.kind() == "template_type" { node.child_by_field_name("name").unwrap_or(node) } else { node };
+    collapse(text(node, source).split('<').next().unwrap_or(""))
+}
+
+/// Helper: Stable operator name: `operator ==` is `operator==`, `operator  new[]` is `operator new[]`
+fn operator_name(text: &str) -> String {
+    let symbol = collapse(text.trim().trim_start_matches("operator"));
+    if symbol.starts_with(|c: char| c.is_alphanumeric() || c == '_') {
+        format!("operator {}", symbol)
+    } else {
+        format!("operator{}", symbol)
+    }
+}
+
+/// Helper: Stable names from `operator_name` (not identifiers like `operators`)
+fn is_operator(name: &str) -> bool {
+    name.strip_prefix("operator")
+        .map_or(false, |rest| !rest.starts_with(|c: char| c.is_alphanumeric() || c == '_'))
+}
+
+/// Helper: Scope segments and stable name of a declarator or callee name
+/// (`geo::Shape<T>::operator ==` is `["geo", "Shape"]`, `operator==`)
+fn split_name(node: Node, source: &str) -> Option<(Vec<String>, String)> {
+    match node.kind() {
+        "identifier" | "field_identifier" | "type_identifier" => Some((Vec::new(), text(node, source).to_string())),
+        "destructor_name" => Some((Vec::new(), format!("~{}", text(node, source).trim_start_matches('~').trim()))),
+        "operator_name" => Some((Vec::new(), operator_name(text(node, source)))),
+        // operator bool() / operator const char*(): the type up to the parameter list
+        "operator_cast" => {
+            let params = cast_parameters(node)?;
+            Some((Vec::new(), operator_name(&source[node.start_byte()..params.start_byte()])))
+        }
+        "template_function" | "template_method" => split_name(node.child_by_field_name("name")?, source),
+        "qualified_identifier" | "qualified_operator_cast_identifier" => {
+            let (mut scope, name) = split_name(node.child_by_field_name("name")?, source)?;
+            if let Some(segment) = node.child_by_field_name("scope") {
+                scope.insert(0, base_name(segment, source));
+            }
+            Some((scope, name))
+        }
+        _ => None,
+    }
+}
+
+/// Helper: Name node and the declarator holding the parameter list of a
+/// function declarator, through pointer / reference return types (`Shape* make()`)
+fn function_declarator(node: Node) -> Option<(Node, Node)> {
+    let mut current = node;
+    loop {
+        match current.kind() {
+            "function_declarator" => return Some((current.child_by_field_name("declarator")?, current)),
+            "operator_cast" => return Some((current, cast_parameters(current)?.parent()?)),
+            // Shape::operator bool() (the grammar aliases it to `qualified_identifier`)
+            "qualified_identifier" | "qualified_operator_cast_identifier" => {
+                let mut cast = current;
+                while matches!(cast.kind(), "qualified_identifier" | "qualified_operator_cast_identifier") {
+                    cast = cast.child_by_field_name("name")?;
+                }
+                if cast.kind() != "operator_cast" {
+                    return None;
+                }
+                return Some((current, cast_parameters(cast)?.parent()?));
+            }
+            kind if kind.ends_with("_declarator") => {
+                current = current.child_by_field_name("declarator").or_else(|| current.named_child(0))?;
+            }
+            _ => return None,
+        }
+    }
+}
+
+/// Helper: Parameter list of a conversion operator's abstract declarator
+fn cast_parameters(cast: Node) -> Option<Node> {
+    let mut declarator = cast.child_by_field_name("declarator")?;
+    loop {
+        if let Some(params) = declarator.child_by_field_name("parameters") {
+            return Some(params);
+        }
+        declarator = declarator.child_by_field_name("declarator")?;
+    }
+}
+
+/// Helper: The identifier a parameter or template parameter declares
+fn declared_name(node: Node) -> Option<Node> {
+    match node.kind() {
+        "identifier" | "field_identifier" | "type_identifier" => Some(node),
+        _ => declared_name(node.child_by_field_name("declarator").or_else(|| node.named_child(0))?),
+    }
+}
+
+/// Helper: Parameter names of a function declarator (`unnamed_<type>` for unnamed ones)
+fn extract_parameters(declarator: Node, source: &str) -> Vec<String> {
+    let mut params = Vec::new();
+    
+    if let Some(param_list) = declarator.child_by_field_name("parameters") {
+        let mut cursor = param_list.walk();
+        for child in param_list.children(&mut cursor) {
+            if matches!(child.kind(), "parameter_declaration" | "optional_parameter_declaration") {
+                if let Some(declarator) = child.child_by_field_name("declarator") {
+                    params.push(text(declarator, source).to_string());
+                } else if let Some(type_node) = child.child_by_field_name("type") {
+                    // For unnamed parameters, use the type
+                    params.push(format!("unnamed_{}", text(type_node, source)));
+                }
+            }
+        }
+    }
+    
+    params
+}
+
+/// Helper: Parameter types of a parameter list, names and default values dropped
+/// (`(const std::string &name, int n = 0)` is `const std::string&`, `int`)
+fn parameter_types(param_list: Node, source: &str) -> Vec<String> {
+    let mut types = Vec::new();
+    
+    let mut cursor = param_list.walk();
+    for param in param_list.children(&mut cursor) {
+        match param.kind() {
+            "parameter_declaration" | "optional_parameter_declaration" | "variadic_parameter_declaration" => {
+                let end = param.child_by_field_name("default_value").map_or(param.end_byte(), |d| d.start_byte());
+                let name = param.child_by_field_name("declarator").and_then(declared_name);
+                let without_name = match name {
+                    Some(name) => format!("{} {}", &source[param.start_byte()..name.start_byte()], &source[name.end_byte()..end]),
+                    None => source[param.start_byte()..end].to_string(),
+                };
+                types.push(collapse(without_name.trim_end().trim_end_matches('=')));
+            }
+            // C variadics: printf(const char*, ...)
+            "..." | "variadic_parameter" => types.push("...".to_string()),
+            _ => {}
+        }
+    }
+    
+    types
+}
+
+/// Helper: Parameters of a `template <...>` header
+fn template_params(param_list: Node, source: &str) -> Vec<TypeParameter> {
+    let mut params = Vec::new();
+    
+    let mut cursor = param_list.walk();
+    for param in param_list.named_children(&mut cursor) {
+        let (name, constraint) = match param.kind() {
+            // typename T, class T = int, typename... Ts
+            "type_parameter_declaration" | "optional_type_parameter_declaration" | "variadic_type_parameter_declaration" => {
+                let mut inner = param.walk();
+                let name = param.child_by_field_name("name")
+                    .or_else(|| param.named_children(&mut inner).find(|c| c.kind() == "type_identifier"));
+                let Some(name) = name else { continue };
+                let variadic = if param.kind() == "variadic_type_parameter_declaration" { "..." } else { "" };
+                (format!("{}{}", text(name, source), variadic), String::new())
+            }
+            // int N, std::integral T, int... Ns
+            "parameter_declaration" | "optional_parameter_declaration" | "variadic_parameter_declaration" => {
+                let Some(name) = param.child_by_field_name("declarator").and_then(declared_name) else { continue };
+                let variadic = if param.kind() == "variadic_parameter_declaration" { "..." } else { "" };
+                let constraint = param.child_by_field_name("type").map(|t| collapse(text(t, source))).unwrap_or_default();
+                (format!("{}{}", text(name, source), variadic), constraint)
+            }
+            // template <typename> class Container
+            "template_template_parameter_declaration" => {
+                let mut inner = param.walk();
+                let name = param.named_children(&mut inner)
+                    .filter(|c| c.kind() != "template_parameter_list")
+                    .find_map(|c| declared_name(c).or_else(|| c.named_child(0)));
+                let Some(name) = name else { continue };
+                (text(name, source).to_string(), "template".to_string())
+            }
+            _ => continue,
+        };
+        params.push(TypeParameter { name, constraint });
+    }
+    
+    params
+}
+
+/// Helper: Extract function modifiers (virtual, static, inline, const, override, ...)
+fn extract_function_modifiers(node: Node, declarator: Node, source: &str) -> std::collections::HashMap<String, String> {
+    let mut metadata = std::collections::HashMap::new();
+    let mut modifiers = Vec::new();
+    
+    // Specifiers before the declarator; qualifiers after the parameter list
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        match child.kind() {
+            "storage_class_specifier" => modifiers.push(text(child, source).to_string()),
+            "virtual" | "virtual_function_specifier" => modifiers.push("virtual".to_string()),
+            "explicit_function_specifier" => modifiers.push("explicit".to_string()),
+            _ => {}
+        }
+    }
+    let mut cursor = declarator.walk();
+    for child in declarator.children(&mut cursor) {
+        if matches!(child.kind(), "type_qualifier" | "virtual_specifier") {
+            modifiers.push(text(child, source).to_string());
+        }
+    }
+    
+    if !modifiers.is_empty() {
+        metadata.insert("modifiers".to_string(), modifiers.join(" "));
+    }
+    
+    metadata
+}
+
+/// Helper: Base classes of a class specifier (`class Circle : public Shape, private Logger`)
+fn extract_base_classes(node: Node, source: &str) -> Vec<String> {
+    let mut base_classes = Vec::new();
+    
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        if child.kind() == "base_class_clause" {
+            let mut subchild_cursor = child.walk();
+            for subchild in child.named_children(&mut subchild_cursor) {
+                if matches!(subchild.kind(), "type_identifier" | "qualified_type_identifier" | "template_type") {
+                    base_classes.push(base_name(subchild, source));
+                }
+            }
+        }
+    }
+    
+    base_classes
+}
+
+/// Helper: Class whose method contains `node`: the class body the definition
+/// sits in, or the scope of an out-of-class definition (`void Shape::draw()`)
+fn enclosing_class(node: Node, source: &str) -> Option<String> {
+    let mut current = node.parent();
+    while let Some(ancestor) = current {
+        if ancestor.kind() == "function_definition" {
+            break;
+        }
+        current = ancestor.parent();
+    }
+    let func = current?;
+    
+    let mut container = func.parent()?;
+    if container.kind() == "template_declaration" {
+        container = container.parent()?;
+    }
+    if container.kind() == "field_declaration_list" {
+        return container.parent()?.child_by_field_name("name").map(|n| base_name(n, source));
+    }
+    let (name_node, _) = function_declarator(func.child_by_field_name("declarator")?)?;
+    split_name(name_node, source)?.0.pop()
+}
+
 #[async_trait]
 impl LanguageAnalyzer for TreeSitterCppAnalyzer {
     fn get_language(&self) -> Language {
@@ -416,9 +805,13 @@ impl LanguageAnalyzer for TreeSitterCppAnalyzer {
         
         // Extract all constructs
         let extract_start = std::time::Instant::now();
-        result.functions = self.extract_functions(&tree, content)?;
-        result.classes = self.extract_classes(&tree, content)?;
+        let namespaces = self.extract_namespaces(&tree, content)?;
+        let (functions, classes, declarations) = self.extract_symbols(&tree, content, &namespaces);
+        result.functions = functions;
+        result.classes = classes;
+        result.declarations = declarations;
         result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content, &namespaces)?;
         let extract_duration = extract_start.elapsed();
         
         if std::env::var("NEKOCODE_DEBUG").is_ok() {
@@ -438,8 +831,10 @@ impl LanguageAnalyzer for TreeSitterCppAnalyzer {
             eprintln!("⚡ [TREE-SITTER C++] AST build took: {:.3}ms", ast_duration.as_secs_f64() * 1000.0);
         }
         
-        // Update statistics
-        result.update_statistics();
+        // Prototypes defined in the same file (headers are linked per directory)
+        link_declarations(std::slice::from_mut(&mut result));
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::CPP);
         
         Ok(result)
     }
@@ -2,15 +2,18 @@
 //! 100x faster than PEST implementation!
 
 use anyhow::Result;
+use std::collections::HashMap;
 use tree_sitter::{Parser, Query, QueryCursor, Node};
 use async_trait::async_trait;
 
 use crate::core::types::{
     AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, 
-    Language, ComplexityInfo, ImportType
+    Language, ComplexityInfo, ImportType, MemberVariable, Annotation
 };
 use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
 use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, magic_numbers, max_nesting_depth, node_span, raw_hash};
 
 pub struct TreeSitterCSharpAnalyzer {
     parser: Parser,
@@ -57,18 +60,21 @@ impl TreeSitterCSharpAnalyzer {
                     "method" => {
                         func_node = Some(capture.node);
                         func_info.start_line = capture.node.start_position().row as u32 + 1;
+                        func_info.span = node_span(capture.node, source);
                         func_info.end_line = capture.node.end_position().row as u32 + 1;
                         func_info.metadata.insert("type".to_string(), "method".to_string());
                     }
                     "constructor" => {
                         func_node = Some(capture.node);
                         func_info.start_line = capture.node.start_position().row as u32 + 1;
+                        func_info.span = node_span(capture.node, source);
                         func_info.end_line = capture.node.end_position().row as u32 + 1;
                         func_info.metadata.insert("type".to_string(), "constructor".to_string());
                     }
                     "local_function" => {
                         func_node = Some(capture.node);
                         func_info.start_line = capture.node.start_position().row as u32 + 1;
+                        func_info.span = node_span(capture.node, source);
                         func_info.end_line = capture.node.end_position().row as u32 + 1;
                         func_info.metadata.insert("type".to_string(), "local_function".to_string());
                     }
@@ -83,10 +89,24 @@ impl TreeSitterCSharpAnalyzer {
                 
                 // Extract modifiers
                 func_info.metadata.extend(self.extract_method_modifiers(node, source)?);
+                func_info.annotations = self.attributes(node, source);
+                if let Some(return_type) = self.return_type(node, source) {
+                    func_info.metadata.insert("return_type".to_string(), return_type);
+                }
+                if let Some(owner) = self.enclosing_type_name(node, source) {
+                    func_info.metadata.insert("class_name".to_string(), owner);
+                }
             }
             
             // Set default complexity (will be calculated separately)
             func_info.complexity = ComplexityInfo::default();
+            if let Some(node) = func_node {
+                func_info.complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::CSHARP);
+                func_info.complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::CSHARP);
+                func_info.body_hash = body_hash(node, source);
+                func_info.raw_hash = raw_hash(node, source);
+                func_info.clone_tokens = clone_tokens(node);
+            }
             
             functions.push(func_info);
         }
@@ -108,6 +128,8 @@ impl TreeSitterCSharpAnalyzer {
                 name: (identifier) @name) @interface
               (enum_declaration
                 name: (identifier) @name) @enum
+              (record_declaration
+                name: (identifier) @name) @record
             ]
         "#;
         
@@ -127,37 +149,53 @@ impl TreeSitterCSharpAnalyzer {
                     "class" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "class".to_string());
                     }
                     "struct" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "struct".to_string());
                     }
                     "interface" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "interface".to_string());
                     }
                     "enum" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "enum".to_string());
                     }
+                    "record" => {
+                        class_node = Some(capture.node);
+                        class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
+                        class_info.end_line = capture.node.end_position().row as u32 + 1;
+                        class_info.metadata.insert("type".to_string(), "record".to_string());
+                    }
                     _ => {}
                 }
             }
             
-            // Extract methods and inheritance
+            // Extract members and inheritance
             if let Some(node) = class_node {
-                class_info.methods = self.extract_class_methods(node, source)?;
+                if let Some(body) = node.child_by_field_name("body") {
+                    class_info.methods = self.extract_class_methods(body, source)?;
+                    self.extract_class_members(body, source, &mut class_info)?;
+                }
                 
-                // Extract base classes (inheritance)
-                if let Some(base_list) = node.child_by_field_name("base_list") {
+                // Extract base classes (inheritance); base_list is not a named field
+                let mut cursor = node.walk();
+                let base_list = node.children(&mut cursor).find(|c| c.kind() == "base_list");
+                if let Some(base_list) = base_list {
                     let base_classes = self.extract_base_classes(base_list, source)?;
                     if !base_classes.is_empty() {
                         class_info.parent_class = Some(base_classes[0].clone());
@@ -169,6 +207,13 @@ impl TreeSitterCSharpAnalyzer {
                 
                 // Extract modifiers
                 class_info.metadata.extend(self.extract_type_modifiers(node, source)?);
+                class_info.annotations = self.attributes(node, source);
+                if let Some(namespace) = self.namespace_of(node, tree.root_node(), source) {
+                    class_info.metadata.insert("namespace".to_string(), namespace);
+                }
+                if let Some(outer) = self.enclosing_type_name(node, source) {
+                    class_info.metadata.insert("outer_class".to_string(), outer);
+                }
             }
             
             classes.push(class_info);
@@ -177,14 +222,54 @@ impl TreeSitterCSharpAnalyzer {
         Ok(classes)
     }
     
-    /// Extract using statements using tree-sitter query
-    fn extract_imports(&self, _tree: &tree_sitter::Tree, _source: &str) -> Result<Vec<ImportInfo>> {
-        // Temporarily disable C# import extraction to avoid query syntax issues
-        // TODO: Fix Tree-sitter query for C# using directives
-        Ok(Vec::new())
+    /// Extract using directives (walked rather than queried: their shape varies by form)
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        let mut stack = vec![tree.root_node()];
+        
+        while let Some(node) = stack.pop() {
+            if node.kind() != "using_directive" {
+                let mut cursor = node.walk();
+                stack.extend(node.children(&mut cursor));
+                continue;
+            }
+            
+            // global using static System.Math; / using Json = System.Text.Json;
+            let text = node.utf8_text(source.as_bytes())?.trim().trim_end_matches(';');
+            let mut words: Vec<&str> = text.split_whitespace().collect();
+            let is_global = words.first() == Some(&"global");
+            words.retain(|w| !matches!(*w, "global" | "using" | "static" | "unsafe"));
+            let is_static = text.split_whitespace().any(|w| w == "static");
+            let directive = words.join(" ");
+            
+            let (alias, path) = match directive.split_once('=') {
+                Some((alias, path)) => (Some(alias.trim().to_string()), path.trim().to_string()),
+                None => (None, directive.trim().to_string()),
+            };
+            if path.is_empty() {
+                continue;
+            }
+            
+            let mut import_info = ImportInfo::new(ImportType::CSharpUsing, path.clone());
+            import_info.line_number = node.start_position().row as u32 + 1;
+            import_info.alias = alias;
+            if is_static {
+                if let Some((_, name)) = path.rsplit_once('.') {
+                    import_info.imported_names.push(name.to_string());
+                }
+                import_info.metadata.insert("is_static".to_string(), "true".to_string());
+            }
+            if is_global {
+                import_info.metadata.insert("is_global".to_string(), "true".to_string());
+            }
+            imports.push(import_info);
+        }
+        
+        imports.sort_by_key(|i| i.line_number);
+        Ok(imports)
     }
     
-    /// Helper: Extract parameters from a method node
+    /// Helper: Extract parameters (`type name`) from a method node
     fn extract_parameters(&self, node: Node, source: &str) -> Result<Vec<String>> {
         let mut params = Vec::new();
         
@@ -192,32 +277,18 @@ impl TreeSitterCSharpAnalyzer {
             let mut cursor = param_list.walk();
             for child in param_list.children(&mut cursor) {
                 if child.kind() == "parameter" {
-                    let mut param_text = String::new();
-                    
-                    let mut param_cursor = child.walk();
-                    for param_child in child.children(&mut param_cursor) {
-                        match param_child.kind() {
-                            "identifier" => {
-                                if let Ok(name) = param_child.utf8_text(source.as_bytes()) {
-                                    param_text = name.to_string();
-                                }
-                            }
-                            "predefined_type" | "generic_name" => {
-                                if let Ok(type_text) = param_child.utf8_text(source.as_bytes()) {
-                                    if param_text.is_empty() {
-                                        param_text = type_text.to_string();
-                                    } else {
-                                        param_text = format!("{} {}", type_text, param_text);
-                                    }
-                                }
-                            }
-                            _ => {}
-                        }
-                    }
+                    let name = child.child_by_field_name("name")
+                        .and_then(|n| n.utf8_text(source.as_bytes()).ok());
+                    let param_type = child.child_by_field_name("type")
+                        .and_then(|t| t.utf8_text(source.as_bytes()).ok());
                     
-                    if !param_text.is_empty() {
-                        params.push(param_text);
-                    }
+                    let param_text = match (param_type, name) {
+                        (Some(param_type), Some(name)) => format!("{} {}", param_type, name),
+                        (None, Some(name)) => name.to_string(),
+                        (Some(param_type), None) => param_type.to_string(),
+                        (None, None) => continue,
+                    };
+                    params.push(param_text);
                 }
             }
         }
@@ -225,61 +296,142 @@ impl TreeSitterCSharpAnalyzer {
         Ok(params)
     }
     
-    /// Helper: Check if method is async
-    fn is_async_method(&self, node: Node, source: &str) -> bool {
-        // Look for async modifier in the parent or siblings
-        if let Some(parent) = node.parent() {
-            let mut cursor = parent.walk();
-            for child in parent.children(&mut cursor) {
-                if child.kind() == "modifier" {
-                    if let Ok(modifier_text) = child.utf8_text(source.as_bytes()) {
-                        if modifier_text == "async" {
-                            return true;
-                        }
-                    }
-                }
+    /// Helper: `modifier` nodes are direct children of the declaration
+    fn modifiers(&self, node: Node, source: &str) -> Vec<String> {
+        let mut cursor = node.walk();
+        node.children(&mut cursor)
+            .filter(|c| c.kind() == "modifier")
+            .filter_map(|c| c.utf8_text(source.as_bytes()).ok())
+            .map(|m| m.to_string())
+            .collect()
+    }
+    
+    /// Helper: Attributes of a declaration, every `[...]` list in order (`[HttpGet("{id}")]` is `HttpGet`)
+    fn attributes(&self, node: Node, source: &str) -> Vec<Annotation> {
+        let text = |n: Node| n.utf8_text(source.as_bytes()).unwrap_or("");
+        let mut attributes = Vec::new();
+        
+        let mut cursor = node.walk();
+        for list in node.children(&mut cursor).filter(|c| c.kind() == "attribute_list") {
+            let mut list_cursor = list.walk();
+            for attribute in list.named_children(&mut list_cursor).filter(|c| c.kind() == "attribute") {
+                let Some(name) = attribute.child_by_field_name("name") else { continue };
+                let mut argument_cursor = attribute.walk();
+                let arguments = attribute.children(&mut argument_cursor)
+                    .find(|c| c.kind() == "attribute_argument_list")
+                    .map_or("", text);
+                attributes.push(Annotation::new(text(name), arguments, attribute.start_position().row as u32 + 1));
             }
         }
-        false
+        
+        attributes
+    }
+    
+    /// Helper: Check if method is async
+    fn is_async_method(&self, node: Node, source: &str) -> bool {
+        self.modifiers(node, source).iter().any(|m| m == "async")
     }
     
     /// Extract method modifiers (public, private, static, etc.)
-    fn extract_method_modifiers(&self, node: Node, source: &str) -> Result<std::collections::HashMap<String, String>> {
-        let mut metadata = std::collections::HashMap::new();
-        let mut modifiers = Vec::new();
-        
-        if let Some(parent) = node.parent() {
-            let mut cursor = parent.walk();
-            for child in parent.children(&mut cursor) {
-                if child.kind() == "modifier" {
-                    if let Ok(modifier_text) = child.utf8_text(source.as_bytes()) {
-                        modifiers.push(modifier_text.to_string());
-                    }
-                }
-            }
-        }
+    fn extract_method_modifiers(&self, node: Node, source: &str) -> Result<HashMap<String, String>> {
+        let mut metadata = HashMap::new();
+        let modifiers = self.modifiers(node, source);
         
         if !modifiers.is_empty() {
             metadata.insert("modifiers".to_string(), modifiers.join(" "));
         }
+        if modifiers.iter().any(|m| m == "static") {
+            metadata.insert("is_static".to_string(), "true".to_string());
+        }
         
         Ok(metadata)
     }
     
-    /// Extract type modifiers for classes
-    fn extract_type_modifiers(&self, node: Node, source: &str) -> Result<std::collections::HashMap<String, String>> {
-        let mut metadata = std::collections::HashMap::new();
-        let mut modifiers = Vec::new();
+    /// Helper: Declared return type of a method (`returns` field; older grammars used `type`)
+    fn return_type(&self, node: Node, source: &str) -> Option<String> {
+        if node.kind() == "constructor_declaration" {
+            return None;
+        }
+        node.child_by_field_name("returns")
+            .or_else(|| node.child_by_field_name("type"))
+            .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+            .map(|t| t.to_string())
+    }
+    
+    /// Helper: Name of the innermost type declaration containing `node`
+    fn enclosing_type_name(&self, node: Node, source: &str) -> Option<String> {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if matches!(parent.kind(), "class_declaration" | "struct_declaration" | "interface_declaration" | "record_declaration") {
+                return parent.child_by_field_name("name")
+                    .and_then(|n| n.utf8_text(source.as_bytes()).ok())
+                    .map(|n| n.to_string());
+            }
+            current = parent.parent();
+        }
+        None
+    }
+    
+    /// Helper: Dotted namespace of a declaration (block-scoped or file-scoped)
+    fn namespace_of(&self, node: Node, root: Node, source: &str) -> Option<String> {
+        let mut parts = Vec::new();
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if matches!(parent.kind(), "namespace_declaration" | "file_scoped_namespace_declaration") {
+                if let Some(name) = parent.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                    parts.push(name.to_string());
+                }
+            }
+            current = parent.parent();
+        }
         
-        let mut cursor = node.walk();
-        for child in node.children(&mut cursor) {
-            if child.kind() == "modifier" {
-                if let Ok(modifier_text) = child.utf8_text(source.as_bytes()) {
-                    modifiers.push(modifier_text.to_string());
+        // `namespace App;` may precede the declarations as a sibling
+        if parts.is_empty() {
+            let mut cursor = root.walk();
+            let file_scoped = root.children(&mut cursor)
+                .find(|c| c.kind() == "file_scoped_namespace_declaration" && c.start_byte() < node.start_byte());
+            if let Some(name) = file_scoped.and_then(|n| n.child_by_field_name("name")) {
+                parts.push(name.utf8_text(source.as_bytes()).ok()?.to_string());
+            }
+        }
+        
+        if parts.is_empty() {
+            None
+        } else {
+            parts.reverse();
+            Some(parts.join("."))
+        }
+    }
+    
+    /// Helper: All namespaces declared in the file
+    fn extract_namespaces(&self, tree: &tree_sitter::Tree, source: &str) -> Vec<String> {
+        let mut namespaces = Vec::new();
+        let mut stack = vec![tree.root_node()];
+        
+        while let Some(node) = stack.pop() {
+            if matches!(node.kind(), "namespace_declaration" | "file_scoped_namespace_declaration") {
+                if let Some(body) = node.child_by_field_name("body") {
+                    if let Some(namespace) = self.namespace_of(body, tree.root_node(), source) {
+                        namespaces.push(namespace);
+                    }
+                } else if let Some(name) = node.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                    namespaces.push(name.to_string());
                 }
             }
+            let mut cursor = node.walk();
+            stack.extend(node.children(&mut cursor));
         }
         
+        namespaces.sort();
+        namespaces.dedup();
+        namespaces
+    }
+    
+    /// Extract type modifiers for classes
+    fn extract_type_modifiers(&self, node: Node, source: &str) -> Result<HashMap<String, String>> {
+        let mut metadata = HashMap::new();
+        let modifiers = self.modifiers(node, source);
+        
         if !modifiers.is_empty() {
             metadata.insert("modifiers".to_string(), modifiers.join(" "));
         }
@@ -292,9 +444,15 @@ impl TreeSitterCSharpAnalyzer {
         let mut base_classes = Vec::new();
         
         let mut cursor = node.walk();
-        for child in node.children(&mut cursor) {
-            if child.kind() == "identifier" || child.kind() == "generic_name" {
-                if let Ok(class_name) = child.utf8_text(source.as_bytes()) {
+        for child in node.named_children(&mut cursor) {
+            // record Point(int X) : Base(X) wraps the base type with its arguments
+            let base = if child.kind() == "primary_constructor_base_type" {
+                child.child_by_field_name("type").unwrap_or(child)
+            } else {
+                child
+            };
+            if matches!(base.kind(), "identifier" | "generic_name" | "qualified_name") {
+                if let Ok(class_name) = base.utf8_text(source.as_bytes()) {
                     base_classes.push(class_name.to_string());
                 }
             }
@@ -303,12 +461,12 @@ impl TreeSitterCSharpAnalyzer {
         Ok(base_classes)
     }
     
-    /// Extract methods from a class node
-    fn extract_class_methods(&self, class_node: Node, source: &str) -> Result<Vec<FunctionInfo>> {
+    /// Extract methods from a type body (`declaration_list`)
+    fn extract_class_methods(&self, body: Node, source: &str) -> Result<Vec<FunctionInfo>> {
         let mut methods = Vec::new();
         
-        let mut cursor = class_node.walk();
-        for child in class_node.children(&mut cursor) {
+        let mut cursor = body.walk();
+        for child in body.children(&mut cursor) {
             match child.kind() {
                 "method_declaration" | "constructor_declaration" => {
                     let mut method = FunctionInfo::new(String::new());
@@ -318,6 +476,7 @@ impl TreeSitterCSharpAnalyzer {
                     }
                     
                     method.start_line = child.start_position().row as u32 + 1;
+                    method.span = node_span(child, source);
                     method.end_line = child.end_position().row as u32 + 1;
                     method.parameters = self.extract_parameters(child, source)?;
                     method.is_async = self.is_async_method(child, source);
@@ -329,6 +488,10 @@ impl TreeSitterCSharpAnalyzer {
                     
                     // Extract method modifiers
                     method.metadata.extend(self.extract_method_modifiers(child, source)?);
+                    method.annotations = self.attributes(child, source);
+                    if let Some(return_type) = self.return_type(child, source) {
+                        method.metadata.insert("return_type".to_string(), return_type);
+                    }
                     
                     methods.push(method);
                 }
@@ -339,6 +502,64 @@ impl TreeSitterCSharpAnalyzer {
         Ok(methods)
     }
     
+    /// Extract properties and fields from a type body
+    fn extract_class_members(&self, body: Node, source: &str, class_info: &mut ClassInfo) -> Result<()> {
+        let mut cursor = body.walk();
+        for child in body.children(&mut cursor) {
+            match child.kind() {
+                "property_declaration" => {
+                    if let Some(name) = child.child_by_field_name("name") {
+                        class_info.properties.push(name.utf8_text(source.as_bytes())?.to_string());
+                    }
+                }
+                "field_declaration" => {
+                    let modifiers = self.modifiers(child, source);
+                    let attributes = self.attributes(child, source);
+                    let mut field_cursor = child.walk();
+                    let declaration = child.children(&mut field_cursor).find(|c| c.kind() == "variable_declaration");
+                    let Some(declaration) = declaration else { continue };
+                    
+                    let var_type = declaration.child_by_field_name("type")
+                        .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+                        .unwrap_or("")
+                        .to_string();
+                    let mut declarator_cursor = declaration.walk();
+                    for declarator in declaration.children(&mut declarator_cursor).filter(|c| c.kind() == "variable_declarator") {
+                        let name = declarator.child_by_field_name("name")
+                            .or_else(|| declarator.named_child(0))
+                            .and_then(|n| n.utf8_text(source.as_bytes()).ok());
+                        if let Some(name) = name {
+                            let mut member = MemberVariable::new(name.to_string(), var_type.clone(), child.start_position().row as u32 + 1);
+                            member.span = node_span(child, source);
+                            member.is_static = modifiers.iter().any(|m| m == "static");
+                            member.is_const = modifiers.iter().any(|m| m == "const" || m == "readonly");
+                            member.access_modifier = modifiers.iter()
+                                .find(|m| matches!(m.as_str(), "public" | "protected" | "internal" | "private"))
+                                .cloned()
+                                .unwrap_or_else(|| "private".to_string());
+                            member.annotations = attributes.clone();
+                            class_info.member_variables.push(member);
+                        }
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Value of an extraction step, or its default with the error recorded
+    fn recover<T: Default>(step: &str, outcome: Result<T>, errors: &mut Vec<String>) -> T {
+        outcome.unwrap_or_else(|e| {
+            if std::env::var("NEKOCODE_DEBUG").is_ok() {
+                eprintln!("⚠️ [TREE-SITTER C#] {} extraction failed: {:#}", step, e);
+            }
+            errors.push(format!("{} extraction failed: {:#}", step, e));
+            T::default()
+        })
+    }
+    
     /// Build AST from tree-sitter CST
     fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
         let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
@@ -351,7 +572,7 @@ impl TreeSitterCSharpAnalyzer {
         // Map tree-sitter node types to our AST types
         let ast_type = match node.kind() {
             "method_declaration" | "constructor_declaration" | "local_function_statement" => ASTNodeType::Function,
-            "class_declaration" | "struct_declaration" | "interface_declaration" | "enum_declaration" => ASTNodeType::Class,
+            "class_declaration" | "struct_declaration" | "interface_declaration" | "enum_declaration" | "record_declaration" => ASTNodeType::Class,
             "if_statement" => ASTNodeType::IfStatement,
             "for_statement" | "foreach_statement" | "while_statement" => ASTNodeType::ForLoop,
             "using_directive" => ASTNodeType::Import,
@@ -436,8 +657,15 @@ impl LanguageAnalyzer for TreeSitterCSharpAnalyzer {
         
         // 🚀 Parse with tree-sitter (ULTRA FAST!)
         let parse_start = std::time::Instant::now();
-        let tree = self.parser.parse(content, None)
-            .ok_or_else(|| anyhow::anyhow!("Failed to parse C# file"))?;
+        let tree = match self.parser.parse(content, None) {
+            Some(tree) => tree,
+            None => {
+                // One unparsable file must not abort a whole batch
+                result.errors.push("Failed to parse C# file".to_string());
+                result.update_statistics();
+                return Ok(result);
+            }
+        };
         let parse_duration = parse_start.elapsed();
         
         if std::env::var("NEKOCODE_DEBUG").is_ok() {
@@ -445,10 +673,18 @@ impl LanguageAnalyzer for TreeSitterCSharpAnalyzer {
         }
         
         // Extract all constructs
+        // A failing step (e.g. a query the grammar rejects) leaves its symbols empty
         let extract_start = std::time::Instant::now();
-        result.functions = self.extract_functions(&tree, content)?;
-        result.classes = self.extract_classes(&tree, content)?;
-        result.imports = self.extract_imports(&tree, content)?;
+        let functions = self.extract_functions(&tree, content);
+        result.functions = Self::recover("function", functions, &mut result.errors);
+        let classes = self.extract_classes(&tree, content);
+        result.classes = Self::recover("class", classes, &mut result.errors);
+        let imports = self.extract_imports(&tree, content);
+        result.imports = Self::recover("import", imports, &mut result.errors);
+        let namespaces = self.extract_namespaces(&tree, content);
+        if !namespaces.is_empty() {
+            result.metadata.insert("namespaces".to_string(), namespaces.join(","));
+        }
         let extract_duration = extract_start.elapsed();
         
         if std::env::var("NEKOCODE_DEBUG").is_ok() {
@@ -468,8 +704,7 @@ impl LanguageAnalyzer for TreeSitterCSharpAnalyzer {
             eprintln!("⚡ [TREE-SITTER C#] AST build took: {:.3}ms", ast_duration.as_secs_f64() * 1000.0);
         }
         
-        // Update statistics
-        result.update_statistics();
+        finish_analysis(&tree, content, &mut result, &magic_numbers::CSHARP);
         
         Ok(result)
     }
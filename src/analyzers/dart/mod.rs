@@ -0,0 +1,3 @@
+pub mod tree_sitter_analyzer;
+
+pub use tree_sitter_analyzer::TreeSitterDartAnalyzer;
@@ -0,0 +1,823 @@
+//! 🚀 Tree-sitter based Dart analyzer
+//! Classes, mixins, enums, extensions and extension types with their members;
+//! top-level functions, getters and setters; generative, `const` and `factory`
+//! constructors; positional, optional (`[...]`) and named (`{...}`) parameters;
+//! imports with `as` / `show` / `hide`; calls, method calls and cascades.
+//!
+//! The grammar keeps a signature and its `function_body` as siblings, so a
+//! function's span runs from the first modifier to the end of the body.
+//! `extends` is the parent class, `implements` the interfaces, and `with`
+//! mixins are recorded like embedded types. Names starting with `_` are
+//! library-private.
+
+use anyhow::Result;
+use tree_sitter::{Parser, Node};
+use async_trait::async_trait;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable, ParameterInfo, Span
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::tree_sitter_util::{child_of_kind, has_keyword};
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Signature node kinds of functions, accessors, operators and constructors
+const SIGNATURE_KINDS: &[&str] = &[
+    "function_signature", "getter_signature", "setter_signature", "operator_signature",
+    "constructor_signature", "constant_constructor_signature",
+    "factory_constructor_signature", "redirecting_factory_constructor_signature",
+];
+
+/// Modifier words that may precede a declared type
+const MODIFIERS: &[&str] = &[
+    "static", "external", "abstract", "const", "final", "late", "var", "covariant", "factory", "required",
+];
+
+pub struct TreeSitterDartAnalyzer {
+    parser: Parser,
+}
+
+/// A function-like declaration of a program or type body
+struct Callable<'t> {
+    /// `method_signature` / `declaration` wrapper, or the signature itself at top level
+    outer: Node<'t>,
+    signature: Node<'t>,
+    /// `None` for abstract, external and redirecting members
+    body: Option<Node<'t>>,
+    /// `@override`, `@deprecated`, ... written before the declaration
+    annotations: Vec<String>,
+}
+
+impl TreeSitterDartAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_dart::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Dart language: {:?}", e))?;
+
+        Ok(Self { parser })
+    }
+
+    /// Extract top-level functions, getters and setters
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+        for callable in Self::callables(tree.root_node(), source) {
+            functions.push(self.build_function_info(&callable, None, source)?);
+        }
+        Ok(functions)
+    }
+
+    /// Extract classes, mixins, enums, extensions and extension types with their members
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+        let root = tree.root_node();
+
+        let mut cursor = root.walk();
+        for node in root.named_children(&mut cursor) {
+            let kind = match node.kind() {
+                "class_definition" => "class",
+                "mixin_declaration" => "mixin",
+                "enum_declaration" => "enum",
+                "extension_declaration" => "extension",
+                "extension_type_declaration" => "extension_type",
+                _ => continue,
+            };
+            classes.push(self.build_class_info(node, kind, source)?);
+        }
+
+        Ok(classes)
+    }
+
+    /// Helper: Build ClassInfo for a type declaration
+    fn build_class_info(&self, node: Node, kind: &str, source: &str) -> Result<ClassInfo> {
+        let name_node = node.child_by_field_name("name")
+            .or_else(|| child_of_kind(node, "identifier"));
+        let name = match name_node {
+            Some(name) => name.utf8_text(source.as_bytes())?.to_string(),
+            // extension on String { ... }
+            None => format!("extension on {}", Self::text_after(node, "on", &["extension_body", "class_body"], source).unwrap_or_default()),
+        };
+
+        let mut class_info = ClassInfo::new(name);
+        class_info.start_line = node.start_position().row as u32 + 1;
+        class_info.end_line = node.end_position().row as u32 + 1;
+        class_info.span = node_span(node, source);
+        class_info.metadata.insert("type".to_string(), kind.to_string());
+        let visibility = if class_info.name.starts_with('_') { "private" } else { "public" };
+        class_info.metadata.insert("visibility".to_string(), visibility.to_string());
+
+        // abstract / sealed / base / interface / final / mixin class
+        let head_end = name_node.map_or(node.start_byte(), |n| n.start_byte());
+        let modifiers: Vec<&str> = source[node.start_byte()..head_end].split_whitespace()
+            .filter(|word| matches!(*word, "abstract" | "sealed" | "base" | "interface" | "final" | "mixin"))
+            .filter(|word| !(kind == "mixin" && *word == "mixin"))
+            .collect();
+        if modifiers.contains(&"abstract") {
+            class_info.metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+        if !modifiers.is_empty() {
+            class_info.metadata.insert("modifiers".to_string(), modifiers.join(" "));
+        }
+
+        // class A extends B with M, N implements I
+        if let Some(superclass) = node.child_by_field_name("superclass").or_else(|| child_of_kind(node, "superclass")) {
+            class_info.parent_class = Self::text_after(superclass, "extends", &["mixins"], source);
+            if let Some(mixins) = child_of_kind(superclass, "mixins") {
+                class_info.embeds.extend(Self::clause_types(mixins, "with", source));
+            }
+        }
+        if let Some(mixins) = child_of_kind(node, "mixins") {
+            class_info.embeds.extend(Self::clause_types(mixins, "with", source));
+        }
+        if let Some(interfaces) = node.child_by_field_name("interfaces").or_else(|| child_of_kind(node, "interfaces")) {
+            class_info.implements.extend(Self::clause_types(interfaces, "implements", source));
+        }
+        // mixin M on Base / extension E on String
+        if matches!(kind, "mixin" | "extension") {
+            if let Some(target) = Self::text_after(node, "on", &["interfaces", "class_body", "extension_body"], source) {
+                class_info.metadata.insert("on".to_string(), target);
+            }
+        }
+
+        // extension type UserId(int value)
+        if let Some(representation) = child_of_kind(node, "representation_declaration") {
+            if let Some((name, var_type)) = Self::declared_name(representation, source) {
+                let mut member = MemberVariable::new(name, var_type, representation.start_position().row as u32 + 1);
+                member.span = node_span(representation, source);
+                member.access_modifier = Self::visibility_of(&member.name).to_string();
+                member.is_const = true;
+                member.metadata.insert("representation".to_string(), "true".to_string());
+                class_info.member_variables.push(member);
+            }
+        }
+
+        let body = node.child_by_field_name("body").or_else(|| {
+            let mut cursor = node.walk();
+            let found = node.named_children(&mut cursor)
+                .find(|c| matches!(c.kind(), "class_body" | "enum_body" | "extension_body"));
+            found
+        });
+        if let Some(body) = body {
+            self.extract_members(body, &mut class_info, source)?;
+        }
+
+        Ok(class_info)
+    }
+
+    /// Helper: Methods, constructors, fields and enum constants of a type body
+    fn extract_members(&self, body: Node, class_info: &mut ClassInfo, source: &str) -> Result<()> {
+        let mut cursor = body.walk();
+        let children: Vec<Node> = body.named_children(&mut cursor).collect();
+        for child in &children {
+            match child.kind() {
+                "enum_constant" => {
+                    let Some(name) = child.child_by_field_name("name").or_else(|| child_of_kind(*child, "identifier")) else { continue };
+                    let mut constant = MemberVariable::new(name.utf8_text(source.as_bytes())?.to_string(), class_info.name.clone(), child.start_position().row as u32 + 1);
+                    constant.span = node_span(*child, source);
+                    constant.access_modifier = "public".to_string();
+                    constant.is_static = true;
+                    constant.is_const = true;
+                    constant.metadata.insert("enum_constant".to_string(), "true".to_string());
+                    class_info.member_variables.push(constant);
+                }
+                "declaration" => class_info.member_variables.extend(self.extract_fields(*child, source)?),
+                _ => {}
+            }
+        }
+
+        for callable in Self::callables(body, source) {
+            let method = self.build_function_info(&callable, Some(&class_info.name), source)?;
+            class_info.methods.push(method);
+        }
+
+        Ok(())
+    }
+
+    /// Helper: Fields of a member declaration (`static const int a = 1, b = 2;` yields two)
+    fn extract_fields(&self, declaration: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut cursor = declaration.walk();
+        let Some(list) = declaration.named_children(&mut cursor)
+            .find(|c| matches!(c.kind(), "initialized_identifier_list" | "static_final_declaration_list")) else {
+            return Ok(Vec::new());
+        };
+
+        let words: Vec<&str> = source[declaration.start_byte()..list.start_byte()].split_whitespace().collect();
+        let var_type: Vec<&str> = words.iter().copied().filter(|w| !MODIFIERS.contains(w)).collect();
+        let is_static = words.contains(&"static");
+
+        let mut fields = Vec::new();
+        let mut entries = list.walk();
+        for entry in list.named_children(&mut entries) {
+            let name = if entry.kind() == "identifier" { Some(entry) } else { child_of_kind(entry, "identifier") };
+            let Some(name) = name else { continue };
+            let mut member = MemberVariable::new(name.utf8_text(source.as_bytes())?.to_string(), var_type.join(" "), entry.start_position().row as u32 + 1);
+            member.span = node_span(declaration, source);
+            member.access_modifier = Self::visibility_of(&member.name).to_string();
+            member.is_static = is_static;
+            member.is_const = words.contains(&"const") || words.contains(&"final");
+            if words.contains(&"late") {
+                member.metadata.insert("late".to_string(), "true".to_string());
+            }
+            fields.push(member);
+        }
+        Ok(fields)
+    }
+
+    /// Helper: Function-like declarations directly inside a program or type body
+    fn callables<'t>(container: Node<'t>, source: &str) -> Vec<Callable<'t>> {
+        let mut cursor = container.walk();
+        let children: Vec<Node> = container.named_children(&mut cursor).collect();
+
+        let mut callables = Vec::new();
+        for (i, child) in children.iter().enumerate() {
+            let signature = match child.kind() {
+                kind if SIGNATURE_KINDS.contains(&kind) => Some(*child),
+                "method_signature" | "declaration" => {
+                    let mut inner = child.walk();
+                    let found = child.named_children(&mut inner).find(|c| SIGNATURE_KINDS.contains(&c.kind()));
+                    found
+                }
+                _ => None,
+            };
+            let Some(signature) = signature else { continue };
+
+            let body = children.get(i + 1).filter(|n| n.kind() == "function_body").copied();
+            let annotations = children[..i].iter().rev()
+                .take_while(|n| matches!(n.kind(), "annotation" | "marker_annotation"))
+                .filter_map(|n| n.utf8_text(source.as_bytes()).ok())
+                .map(|text| text.trim_start_matches('@').split('(').next().unwrap_or("").trim().to_string())
+                .collect::<Vec<_>>()
+                .into_iter()
+                .rev()
+                .collect();
+            callables.push(Callable { outer: *child, signature, body, annotations });
+        }
+        callables
+    }
+
+    /// Helper: Build FunctionInfo for a function, accessor, operator or constructor
+    fn build_function_info(&self, callable: &Callable, owner: Option<&str>, source: &str) -> Result<FunctionInfo> {
+        let signature = callable.signature;
+        let parameters = Self::parameter_list(signature);
+        let is_constructor = signature.kind().contains("constructor");
+        let kind = match signature.kind() {
+            "getter_signature" => "getter",
+            "setter_signature" => "setter",
+            "operator_signature" => "operator",
+            "factory_constructor_signature" | "redirecting_factory_constructor_signature" => "factory_constructor",
+            _ if is_constructor => "constructor",
+            _ if owner.is_some() => "method",
+            _ => "function",
+        };
+
+        // Head: everything before the parameters (or the end of a getter)
+        let head_end = parameters.map_or(signature.end_byte(), |p| p.start_byte());
+        let head = &source[signature.start_byte()..head_end];
+        let (name, return_type) = if is_constructor {
+            // Counter / const Counter / factory Counter.fromJson
+            let name: String = head.split_whitespace().filter(|w| !MODIFIERS.contains(w)).collect();
+            (name, None)
+        } else if kind == "operator" {
+            let (return_type, operator) = head.split_once("operator").unwrap_or(("", head));
+            (format!("operator {}", operator.trim()), Some(return_type.trim().to_string()))
+        } else {
+            let name_node = signature.child_by_field_name("name").or_else(|| {
+                let mut cursor = signature.walk();
+                let last = signature.named_children(&mut cursor)
+                    .filter(|c| c.kind() == "identifier" && c.end_byte() <= head_end)
+                    .last();
+                last
+            });
+            let name_node = name_node.ok_or_else(|| anyhow::anyhow!("Dart signature without a name at line {}", signature.start_position().row + 1))?;
+            let return_type: Vec<&str> = source[signature.start_byte()..name_node.start_byte()].split_whitespace()
+                .filter(|w| !MODIFIERS.contains(w) && *w != "get" && *w != "set")
+                .collect();
+            (name_node.utf8_text(source.as_bytes())?.to_string(), Some(return_type.join(" ")))
+        };
+
+        let mut func_info = FunctionInfo::new(match owner {
+            Some(owner) if is_constructor && name.is_empty() => owner.to_string(),
+            _ => name,
+        });
+        let end = callable.body.unwrap_or(callable.outer);
+        func_info.start_line = callable.outer.start_position().row as u32 + 1;
+        func_info.end_line = end.end_position().row as u32 + 1;
+        func_info.span = Self::span_between(callable.outer, end, source);
+        if let Some(body) = callable.body {
+            func_info.complexity = self.calculate_complexity(body, source);
+            func_info.body_hash = body_hash(body, source);
+            func_info.raw_hash = raw_hash(body, source);
+            func_info.clone_tokens = clone_tokens(body);
+
+            // async / async* / sync*, `=> expr;`
+            let body_text = body.utf8_text(source.as_bytes())?.trim_start();
+            if body_text.starts_with("async*") || body_text.starts_with("sync*") {
+                func_info.metadata.insert("is_generator".to_string(), "true".to_string());
+            }
+            func_info.is_async = body_text.starts_with("async");
+            let after_modifier = body_text.trim_start_matches(|c: char| c.is_ascii_alphabetic() || c == '*').trim_start();
+            if after_modifier.starts_with("=>") {
+                func_info.metadata.insert("expression_body".to_string(), "true".to_string());
+            }
+        }
+        if let Some(return_type) = return_type.filter(|t| !t.is_empty()) {
+            func_info.metadata.insert("return_type".to_string(), return_type);
+        }
+
+        if let Some(parameters) = parameters {
+            self.extract_parameters(parameters, &mut func_info, source)?;
+        }
+
+        // Modifiers sit on the wrapper (`static`, `external`) or in the signature (`const`, `factory`)
+        let modifiers: Vec<&str> = source[callable.outer.start_byte()..head_end].split_whitespace()
+            .filter(|w| MODIFIERS.contains(w))
+            .collect();
+        if modifiers.contains(&"static") {
+            func_info.metadata.insert("is_static".to_string(), "true".to_string());
+        }
+        if modifiers.contains(&"external") {
+            func_info.metadata.insert("is_external".to_string(), "true".to_string());
+        }
+        if is_constructor && modifiers.contains(&"const") {
+            func_info.metadata.insert("is_const".to_string(), "true".to_string());
+        }
+        if kind == "factory_constructor" {
+            func_info.metadata.insert("is_factory".to_string(), "true".to_string());
+        }
+        if signature.kind() == "redirecting_factory_constructor_signature" {
+            // factory Foo() = _FooImpl;
+            if let Some((_, target)) = signature.utf8_text(source.as_bytes())?.rsplit_once('=') {
+                func_info.metadata.insert("redirects_to".to_string(), target.trim().to_string());
+            }
+        }
+        if callable.body.is_none() && owner.is_some() && !is_constructor && !modifiers.contains(&"external") {
+            func_info.metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+        if !callable.annotations.is_empty() {
+            func_info.metadata.insert("annotations".to_string(), callable.annotations.join(","));
+        }
+
+        func_info.metadata.insert("type".to_string(), kind.to_string());
+        let simple_name = func_info.name.rsplit('.').next().unwrap_or(&func_info.name);
+        func_info.metadata.insert("visibility".to_string(), Self::visibility_of(simple_name).to_string());
+        if let Some(owner) = owner {
+            func_info.metadata.insert("is_method".to_string(), "true".to_string());
+            func_info.metadata.insert("class_name".to_string(), owner.to_string());
+        }
+
+        Ok(func_info)
+    }
+
+    /// Helper: Parameters of a `formal_parameter_list`; named (`{}`) and optional
+    /// positional (`[]`) ones are listed in metadata
+    fn extract_parameters(&self, list: Node, func_info: &mut FunctionInfo, source: &str) -> Result<()> {
+        let mut named = Vec::new();
+        let mut required_named = Vec::new();
+        let mut optional = Vec::new();
+        let mut fields = Vec::new();
+
+        let mut groups = vec![(list, "positional")];
+        let mut cursor = list.walk();
+        for child in list.named_children(&mut cursor) {
+            if child.kind() == "optional_formal_parameters" {
+                let text = child.utf8_text(source.as_bytes())?;
+                groups.push((child, if text.starts_with('{') { "named" } else { "optional" }));
+            }
+        }
+
+        for (group, section) in groups {
+            let mut cursor = group.walk();
+            let children: Vec<Node> = group.children(&mut cursor).collect();
+            let mut required = false;
+            for (i, child) in children.iter().enumerate() {
+                if child.utf8_text(source.as_bytes())? == "required" {
+                    required = true;
+                    continue;
+                }
+                if child.kind() != "formal_parameter" {
+                    continue;
+                }
+                let Some((name, param_type)) = Self::declared_name(*child, source) else { continue };
+
+                // `int max = 100` / legacy `int max: 100`
+                let default = match (children.get(i + 1), children.get(i + 2)) {
+                    (Some(op), Some(value)) if matches!(op.kind(), "=" | ":") => Some(value.utf8_text(source.as_bytes())?),
+                    _ => None,
+                };
+                let mut text = String::new();
+                if required {
+                    text.push_str("required ");
+                }
+                text.push_str(child.utf8_text(source.as_bytes())?);
+                if let Some(default) = default {
+                    text.push_str(" = ");
+                    text.push_str(default);
+                }
+
+                match section {
+                    "named" => {
+                        named.push(name.clone());
+                        if required {
+                            required_named.push(name.clone());
+                        }
+                    }
+                    "optional" => optional.push(name.clone()),
+                    _ => {}
+                }
+                // this.initial / super.key
+                if child_of_kind(*child, "constructor_param").is_some() {
+                    fields.push(name.clone());
+                }
+                func_info.parameters.push(text);
+                func_info.params.push(ParameterInfo { name, param_type, variadic: false });
+                required = false;
+            }
+        }
+
+        for (key, names) in [
+            ("named_parameters", named),
+            ("required_named_parameters", required_named),
+            ("optional_parameters", optional),
+            ("field_parameters", fields),
+        ] {
+            if !names.is_empty() {
+                func_info.metadata.insert(key.to_string(), names.join(","));
+            }
+        }
+        Ok(())
+    }
+
+    /// Helper: (name, declared type) of a parameter or representation declaration
+    fn declared_name(node: Node, source: &str) -> Option<(String, String)> {
+        // this.x / super.x: the name is inside the wrapper
+        let holder = {
+            let mut cursor = node.walk();
+            let wrapper = node.named_children(&mut cursor)
+                .find(|c| matches!(c.kind(), "constructor_param" | "super_formal_parameter"));
+            wrapper.unwrap_or(node)
+        };
+
+        // The last identifier before a function-typed parameter's own parameters
+        let mut cursor = holder.walk();
+        let mut name = None;
+        for child in holder.named_children(&mut cursor) {
+            match child.kind() {
+                "identifier" => name = Some(child),
+                "formal_parameter_list" | "formal_parameter_part" => break,
+                _ => {}
+            }
+        }
+        let name = name?;
+
+        let param_type: Vec<&str> = source[node.start_byte()..name.start_byte()].split_whitespace()
+            .filter(|w| !MODIFIERS.contains(w) && !w.starts_with('@') && *w != "(")
+            .map(|w| w.trim_start_matches('(').trim_end_matches("this.").trim_end_matches("super."))
+            .filter(|w| !w.is_empty())
+            .collect();
+        Some((name.utf8_text(source.as_bytes()).ok()?.to_string(), param_type.join(" ")))
+    }
+
+    /// Helper: The `formal_parameter_list` of a signature
+    fn parameter_list(signature: Node) -> Option<Node> {
+        if let Some(list) = child_of_kind(signature, "formal_parameter_list") {
+            return Some(list);
+        }
+        // Generic functions wrap type parameters and parameters together
+        child_of_kind(signature, "formal_parameter_part")
+            .and_then(|part| child_of_kind(part, "formal_parameter_list"))
+    }
+
+    /// Extract `import` directives with their prefix and combinators
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        let mut stack = vec![tree.root_node()];
+
+        while let Some(node) = stack.pop() {
+            if node.kind() != "import_specification" {
+                // Directives only appear at the top of a library
+                if matches!(node.kind(), "program" | "import_or_export" | "library_import") {
+                    let mut cursor = node.walk();
+                    let children: Vec<Node> = node.named_children(&mut cursor).collect();
+                    stack.extend(children.into_iter().rev());
+                }
+                continue;
+            }
+
+            let Some(uri) = Self::first_descendant(node, "string_literal") else { continue };
+            let module = uri.utf8_text(source.as_bytes())?.trim_matches(|c| c == '"' || c == '\'').to_string();
+            let mut import_info = ImportInfo::new(ImportType::DartImport, module);
+            import_info.line_number = node.start_position().row as u32 + 1;
+
+            let mut hidden = Vec::new();
+            let mut cursor = node.walk();
+            for child in node.named_children(&mut cursor) {
+                match child.kind() {
+                    // import '...' as foundation
+                    "identifier" => import_info.alias = Some(child.utf8_text(source.as_bytes())?.to_string()),
+                    // show A, B / hide C
+                    "combinator" => {
+                        let is_hide = child.utf8_text(source.as_bytes())?.starts_with("hide");
+                        let mut names = child.walk();
+                        for name in child.named_children(&mut names).filter(|n| n.kind() == "identifier") {
+                            let name = name.utf8_text(source.as_bytes())?.to_string();
+                            if is_hide { hidden.push(name) } else { import_info.imported_names.push(name) }
+                        }
+                    }
+                    _ => {}
+                }
+            }
+            if !hidden.is_empty() {
+                import_info.metadata.insert("hide".to_string(), hidden.join(","));
+            }
+            if has_keyword(node, "deferred") {
+                import_info.metadata.insert("deferred".to_string(), "true".to_string());
+            }
+            imports.push(import_info);
+        }
+
+        Ok(imports)
+    }
+
+    /// Extract calls: `f()`, `obj.method()`, `Type.named()`, `..cascade()` and `new T()`
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str, classes: &[ClassInfo]) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        let mut stack = vec![tree.root_node()];
+
+        while let Some(node) = stack.pop() {
+            let mut cursor = node.walk();
+            let children: Vec<Node> = node.named_children(&mut cursor).collect();
+            stack.extend(children.iter().rev().copied());
+
+            match node.kind() {
+                // new Foo() / const Foo()
+                "new_expression" | "const_object_expression" => {
+                    if let Some(type_name) = child_of_kind(node, "type_identifier") {
+                        function_calls.push(FunctionCall::new(type_name.utf8_text(source.as_bytes())?.to_string(), node.start_position().row as u32 + 1));
+                    }
+                    continue;
+                }
+                // ..add(x)
+                "cascade_section" => {
+                    let name = child_of_kind(node, "cascade_selector").and_then(|s| child_of_kind(s, "identifier"));
+                    if let (Some(name), Some(_)) = (name, child_of_kind(node, "argument_part")) {
+                        let mut call = FunctionCall::new(name.utf8_text(source.as_bytes())?.to_string(), node.start_position().row as u32 + 1);
+                        call.is_method_call = true;
+                        function_calls.push(call);
+                    }
+                    continue;
+                }
+                _ => {}
+            }
+
+            // Calls are a callee followed by a `selector` holding the arguments
+            for (i, selector) in children.iter().enumerate() {
+                if selector.kind() != "selector" || child_of_kind(*selector, "argument_part").is_none() || i == 0 {
+                    continue;
+                }
+                let callee = children[i - 1];
+                let line = selector.start_position().row as u32 + 1;
+
+                let member = child_of_kind(callee, "unconditional_assignable_selector")
+                    .or_else(|| child_of_kind(callee, "conditional_assignable_selector"))
+                    .and_then(|s| child_of_kind(s, "identifier"));
+                let call = match (callee.kind(), member) {
+                    ("identifier", _) => FunctionCall::new(callee.utf8_text(source.as_bytes())?.to_string(), line),
+                    // receiver.method(...)
+                    ("selector", Some(member)) => {
+                        let mut call = FunctionCall::new(member.utf8_text(source.as_bytes())?.to_string(), line);
+                        call.is_method_call = true;
+                        let receiver = if i >= 2 { Some(children[i - 2]) } else { None };
+                        if let Some(receiver) = receiver.filter(|r| matches!(r.kind(), "identifier" | "this" | "super")) {
+                            let receiver = receiver.utf8_text(source.as_bytes())?.to_string();
+                            // this.notify() inside _CounterState, Counter.fromJson()
+                            call.receiver_type = if receiver == "this" {
+                                Self::enclosing_class(*selector, classes)
+                            } else if classes.iter().any(|c| c.name == receiver) {
+                                Some(receiver.clone())
+                            } else {
+                                None
+                            };
+                            call.object_name = Some(receiver);
+                        }
+                        call
+                    }
+                    _ => continue,
+                };
+                function_calls.push(call);
+            }
+        }
+
+        function_calls.sort_by_key(|call| call.line_number);
+        Ok(function_calls)
+    }
+
+    /// Helper: Name of the type declaration containing a node
+    fn enclosing_class(node: Node, classes: &[ClassInfo]) -> Option<String> {
+        let line = node.start_position().row as u32 + 1;
+        classes.iter()
+            .find(|c| c.start_line <= line && line <= c.end_line)
+            .map(|c| c.name.clone())
+    }
+
+    /// Helper: Types of an `extends` / `with` / `implements` clause, split at top-level commas
+    fn clause_types(node: Node, keyword: &str, source: &str) -> Vec<String> {
+        let text = node.utf8_text(source.as_bytes()).unwrap_or("");
+        let text = text.trim().strip_prefix(keyword).unwrap_or(text);
+
+        let mut types = Vec::new();
+        let mut depth = 0;
+        let mut current = String::new();
+        for c in text.chars() {
+            match c {
+                '<' | '(' => depth += 1,
+                '>' | ')' => depth -= 1,
+                ',' if depth == 0 => {
+                    types.push(current.trim().to_string());
+                    current.clear();
+                    continue;
+                }
+                _ => {}
+            }
+            current.push(c);
+        }
+        types.push(current.trim().to_string());
+        types.retain(|t| !t.is_empty());
+        types
+    }
+
+    /// Helper: Text between a keyword token and the first following child of the given kinds
+    fn text_after(node: Node, keyword: &str, until: &[&str], source: &str) -> Option<String> {
+        let mut cursor = node.walk();
+        let children: Vec<Node> = node.children(&mut cursor).collect();
+        let position = children.iter().position(|c| !c.is_named() && c.kind() == keyword)?;
+        let start = children[position].end_byte();
+        let end = children[position + 1..].iter()
+            .find(|c| until.contains(&c.kind()))
+            .map_or(node.end_byte(), |c| c.start_byte());
+        let text = source.get(start..end)?.trim();
+        if text.is_empty() { None } else { Some(text.to_string()) }
+    }
+
+    /// Helper: Span from the start of one node to the end of a later sibling
+    fn span_between(first: Node, last: Node, source: &str) -> Span {
+        let end = node_span(last, source);
+        Span {
+            end_byte: end.end_byte,
+            end_col: end.end_col,
+            end_col_utf16: end.end_col_utf16,
+            ..node_span(first, source)
+        }
+    }
+
+    /// Helper: "private" for library-private (`_name`) declarations, else "public"
+    fn visibility_of(name: &str) -> &'static str {
+        if name.starts_with('_') { "private" } else { "public" }
+    }
+
+    /// Helper: First descendant of the given kind (depth first)
+    fn first_descendant<'t>(node: Node<'t>, kind: &str) -> Option<Node<'t>> {
+        let mut stack = vec![node];
+        while let Some(current) = stack.pop() {
+            if current.kind() == kind {
+                return Some(current);
+            }
+            let mut cursor = current.walk();
+            let children: Vec<Node> = current.named_children(&mut cursor).collect();
+            stack.extend(children.into_iter().rev());
+        }
+        None
+    }
+
+    /// Calculate cyclomatic complexity for a function body
+    fn calculate_complexity(&self, body: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+
+        // Base complexity 1 + one per decision point in the body
+        complexity.decision_points = decision_points(body, source, &cyclomatic::DART);
+        complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        complexity.cognitive_complexity = cognitive_complexity(body, source, &cognitive::DART);
+        complexity.max_nesting_depth = max_nesting_depth(body, &cognitive::DART);
+
+        complexity.update_rating();
+        complexity
+    }
+
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let in_type = node.parent().map_or(false, |p| p.kind() == "method_signature");
+        let ast_type = match node.kind() {
+            "class_definition" | "mixin_declaration" | "extension_declaration" | "extension_type_declaration" => ASTNodeType::Class,
+            "enum_declaration" => ASTNodeType::Enum,
+            "function_signature" if in_type => ASTNodeType::Method,
+            "function_signature" => ASTNodeType::Function,
+            "getter_signature" => ASTNodeType::Getter,
+            "setter_signature" => ASTNodeType::Setter,
+            "constructor_signature" | "constant_constructor_signature" |
+            "factory_constructor_signature" | "redirecting_factory_constructor_signature" => ASTNodeType::Constructor,
+            "if_statement" => ASTNodeType::IfStatement,
+            "for_statement" | "while_statement" | "do_statement" => ASTNodeType::ForLoop,
+            "local_variable_declaration" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+
+            if let Some(name) = node.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                ast_node.name = name.to_string();
+            }
+
+            parent.add_child(ast_node);
+
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterDartAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Dart
+    }
+
+    fn get_language_name(&self) -> &'static str {
+        "Dart (Tree-sitter)"
+    }
+
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".dart"]
+    }
+
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Dart);
+
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Dart file"))?;
+        let parse_duration = parse_start.elapsed();
+
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER DART] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+
+        // Extract all constructs; methods are listed with the functions too
+        let extract_start = std::time::Instant::now();
+        result.classes = self.extract_classes(&tree, content)?;
+        result.functions = self.extract_functions(&tree, content)?;
+        for class in &result.classes {
+            result.functions.extend(class.methods.iter().cloned());
+        }
+        result.functions.sort_by_key(|f| f.start_line);
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content, &result.classes)?;
+        let extract_duration = extract_start.elapsed();
+
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER DART] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+
+        finish_analysis(&tree, content, &mut result, &magic_numbers::DART);
+
+        Ok(result)
+    }
+}
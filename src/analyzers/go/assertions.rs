@@ -0,0 +1,101 @@
+//! Type assertions and type switches of Go functions
+//!
+//! `w.(*bufio.Writer)` and `case *Server:` in a type switch depend on the
+//! named type's method set: if the type stops implementing the interface the
+//! value was stored as, the assertion fails at run time with no compile error.
+//! Each named type (package-qualified, pointer or generic) is recorded with
+//! its line; predeclared types (`error`, `string`, ...) and type literals
+//! (`interface{ Close() error }`, `[]byte`) are not, since no project change
+//! can alter them.
+
+use tree_sitter::Node;
+
+use crate::core::types::{TypeAssertion, TypeAssertionKind};
+
+/// Predeclared Go types, never defined by the project
+pub const PREDECLARED: &[&str] = &[
+    "any", "bool", "byte", "comparable", "complex64", "complex128", "error",
+    "float32", "float64", "int", "int8", "int16", "int32", "int64", "rune",
+    "string", "uint", "uint8", "uint16", "uint32", "uint64", "uintptr",
+];
+
+/// Named types asserted in a function / method body, in source order
+pub fn type_assertions(func: Node, source: &str) -> Vec<TypeAssertion> {
+    let mut assertions = Vec::new();
+    if let Some(body) = func.child_by_field_name("body") {
+        collect(body, source, &mut assertions);
+    }
+    assertions
+}
+
+/// Helper: Walk a subtree for `x.(T)` expressions and type switch arms
+fn collect(node: Node, source: &str, assertions: &mut Vec<TypeAssertion>) {
+    match node.kind() {
+        "type_assertion_expression" => {
+            if let Some(type_node) = node.child_by_field_name("type") {
+                push(type_node, source, TypeAssertionKind::Assertion, assertions);
+            }
+        }
+        "type_case" => {
+            let mut cursor = node.walk();
+            let types: Vec<Node> = node.children_by_field_name("type", &mut cursor).collect();
+            for type_node in types {
+                push(type_node, source, TypeAssertionKind::TypeSwitch, assertions);
+            }
+        }
+        _ => {}
+    }
+
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        collect(child, source, assertions);
+    }
+}
+
+/// Helper: Record `type_node` if it names a type the project could define
+fn push(type_node: Node, source: &str, kind: TypeAssertionKind, assertions: &mut Vec<TypeAssertion>) {
+    let text = |n: Node| n.utf8_text(source.as_bytes()).unwrap_or("").to_string();
+
+    let mut node = type_node;
+    let mut pointer = false;
+    // *T, (*T), T[int]
+    loop {
+        match node.kind() {
+            "pointer_type" if !pointer => {
+                pointer = true;
+                let Some(inner) = node.named_child(0) else { return };
+                node = inner;
+            }
+            "parenthesized_type" => {
+                let Some(inner) = node.named_child(0) else { return };
+                node = inner;
+            }
+            "generic_type" => {
+                let Some(inner) = node.child_by_field_name("type") else { return };
+                node = inner;
+            }
+            _ => break,
+        }
+    }
+
+    let (package, type_name) = match node.kind() {
+        "type_identifier" => (None, text(node)),
+        "qualified_type" => {
+            let (Some(package), Some(name)) = (node.child_by_field_name("package"), node.child_by_field_name("name")) else { return };
+            (Some(text(package)), text(name))
+        }
+        _ => return,
+    };
+    // `case nil:` parses as a type name
+    if package.is_none() && (PREDECLARED.contains(&type_name.as_str()) || type_name == "nil") {
+        return;
+    }
+
+    assertions.push(TypeAssertion {
+        type_name,
+        package,
+        pointer,
+        kind,
+        line_number: type_node.start_position().row as u32 + 1,
+    });
+}
@@ -0,0 +1,277 @@
+//! Go build constraints
+//!
+//! A file's `//go:build` line (or the legacy `// +build` lines it replaced)
+//! decides which builds include it, so symbols of `net_linux.go` and
+//! `net_windows.go` never coexist. The expression is recorded on the file
+//! result as `build_tags`, and `--build-tags linux,amd64` skips files whose
+//! constraints the tag set does not satisfy, the way `go build -tags` would:
+//!
+//! - the tag set is complete, so name the GOOS and GOARCH (`linux,amd64`)
+//! - release tags (`go1.21`) are always satisfied, and `unix` is satisfied by any Unix GOOS
+//! - `_GOOS`, `_GOARCH` and `_GOOS_GOARCH` file name suffixes constrain like a `//go:build` line
+
+use std::collections::HashSet;
+use std::path::Path;
+
+/// Operating systems recognized in file name suffixes (`GOOS`)
+const KNOWN_OS: &[&str] = &[
+    "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios", "js",
+    "linux", "nacl", "netbsd", "openbsd", "plan9", "solaris", "wasip1", "windows", "zos",
+];
+
+/// Operating systems satisfying the `unix` tag
+const UNIX_OS: &[&str] = &[
+    "aix", "android", "darwin", "dragonfly", "freebsd", "hurd", "illumos", "ios",
+    "linux", "netbsd", "openbsd", "solaris",
+];
+
+/// Architectures recognized in file name suffixes (`GOARCH`)
+const KNOWN_ARCH: &[&str] = &[
+    "386", "amd64", "amd64p32", "arm", "arm64", "arm64be", "armbe", "loong64", "mips",
+    "mips64", "mips64le", "mips64p32", "mips64p32le", "mipsle", "ppc", "ppc64", "ppc64le",
+    "riscv", "riscv64", "s390", "s390x", "sparc", "sparc64", "wasm",
+];
+
+/// Build constraint of a Go source file in `//go:build` syntax
+///
+/// `//go:build` wins over `// +build`; legacy lines are combined with `&&`
+/// (space-separated options are `||`, comma-separated terms `&&`).
+pub fn build_constraint(source: &str) -> Option<String> {
+    let mut go_build = None;
+    let mut plus_build = Vec::new();
+    
+    // Constraints precede the package clause, among blank lines and line comments
+    for line in source.lines() {
+        let line = line.trim();
+        if line.is_empty() {
+            continue;
+        }
+        let Some(comment) = line.strip_prefix("//") else { break };
+        
+        if let Some(expression) = comment.strip_prefix("go:build") {
+            if go_build.is_none() && (expression.is_empty() || expression.starts_with(char::is_whitespace)) {
+                go_build = Some(expression.trim().to_string());
+            }
+        } else if let Some(options) = comment.trim_start().strip_prefix("+build") {
+            if options.is_empty() || options.starts_with(char::is_whitespace) {
+                plus_build.push(legacy_expression(options));
+            }
+        }
+    }
+    
+    if go_build.is_some() {
+        return go_build;
+    }
+    match plus_build.len() {
+        0 => None,
+        1 => plus_build.pop(),
+        _ => Some(plus_build.iter()
+            .map(|line| if line.contains("||") { format!("({})", line) } else { line.clone() })
+            .collect::<Vec<_>>()
+            .join(" && ")),
+    }
+}
+
+/// Helper: One `// +build` line as a `//go:build` expression
+fn legacy_expression(options: &str) -> String {
+    let options: Vec<&str> = options.split_whitespace().collect();
+    options.iter()
+        .map(|option| {
+            let terms: Vec<&str> = option.split(',').filter(|t| !t.is_empty()).collect();
+            let and = terms.join(" && ");
+            if terms.len() > 1 && options.len() > 1 { format!("({})", and) } else { and }
+        })
+        .collect::<Vec<_>>()
+        .join(" || ")
+}
+
+/// Tag set of `--build-tags`
+#[derive(Debug, Clone, Default)]
+pub struct BuildTags {
+    tags: HashSet<String>,
+}
+
+impl BuildTags {
+    pub fn new(tags: &[String]) -> Self {
+        Self {
+            tags: tags.iter()
+                .map(|tag| tag.trim().to_string())
+                .filter(|tag| !tag.is_empty())
+                .collect(),
+        }
+    }
+    
+    /// Whether a single tag is satisfied
+    pub fn is_satisfied(&self, tag: &str) -> bool {
+        if self.tags.contains(tag) || tag.starts_with("go1.") {
+            return true;
+        }
+        tag == "unix" && UNIX_OS.iter().any(|os| self.tags.contains(*os))
+    }
+    
+    /// Whether a `//go:build` expression is satisfied (malformed expressions are)
+    pub fn satisfies(&self, expression: &str) -> bool {
+        evaluate(expression, &|tag| self.is_satisfied(tag)).unwrap_or(true)
+    }
+    
+    /// Whether a Go file belongs to the build, by file name and constraint lines
+    pub fn includes(&self, path: &Path, source: &str) -> bool {
+        let name = path.file_stem().and_then(|s| s.to_str()).unwrap_or("");
+        let name = name.strip_suffix("_test").unwrap_or(name);
+        
+        let parts: Vec<&str> = name.split('_').collect();
+        // `linux.go` is not constrained; `net_linux.go` and `net_linux_amd64.go` are
+        let filename_ok = match parts.as_slice() {
+            [_, .., os, arch] if KNOWN_OS.contains(os) && KNOWN_ARCH.contains(arch) => {
+                self.is_satisfied(os) && self.is_satisfied(arch)
+            }
+            [_, .., last] if KNOWN_OS.contains(last) || KNOWN_ARCH.contains(last) => {
+                self.is_satisfied(last)
+            }
+            _ => true,
+        };
+        
+        filename_ok && build_constraint(source).map_or(true, |expression| self.satisfies(&expression))
+    }
+}
+
+/// Evaluate a `//go:build` expression (`!`, `&&`, `||`, parentheses); `None` if malformed
+pub fn evaluate(expression: &str, is_set: &dyn Fn(&str) -> bool) -> Option<bool> {
+    let tokens = tokenize(expression)?;
+    let mut parser = ExpressionParser { tokens: &tokens, position: 0, is_set };
+    let value = parser.or()?;
+    if parser.position == tokens.len() { Some(value) } else { None }
+}
+
+/// Helper: Split an expression into tags and operators
+fn tokenize(expression: &str) -> Option<Vec<String>> {
+    let mut tokens = Vec::new();
+    let chars: Vec<char> = expression.chars().collect();
+    let mut i = 0;
+    
+    while i < chars.len() {
+        let c = chars[i];
+        if c.is_whitespace() {
+            i += 1;
+        } else if c == '!' || c == '(' || c == ')' {
+            tokens.push(c.to_string());
+            i += 1;
+        } else if (c == '&' || c == '|') && chars.get(i + 1) == Some(&c) {
+            tokens.push(format!("{}{}", c, c));
+            i += 2;
+        } else if c.is_alphanumeric() || c == '_' || c == '.' {
+            let start = i;
+            while i < chars.len() && (chars[i].is_alphanumeric() || chars[i] == '_' || chars[i] == '.') {
+                i += 1;
+            }
+            tokens.push(chars[start..i].iter().collect());
+        } else {
+            return None;
+        }
+    }
+    
+    Some(tokens)
+}
+
+/// Recursive descent over the tokens: `||` binds looser than `&&`, which binds looser than `!`
+struct ExpressionParser<'a> {
+    tokens: &'a [String],
+    position: usize,
+    is_set: &'a dyn Fn(&str) -> bool,
+}
+
+impl ExpressionParser<'_> {
+    fn peek(&self) -> Option<&str> {
+        self.tokens.get(self.position).map(String::as_str)
+    }
+    
+    fn or(&mut self) -> Option<bool> {
+        let mut value = self.and()?;
+        while self.peek() == Some("||") {
+            self.position += 1;
+            value |= self.and()?;
+        }
+        Some(value)
+    }
+    
+    fn and(&mut self) -> Option<bool> {
+        let mut value = self.not()?;
+        while self.peek() == Some("&&") {
+            self.position += 1;
+            value &= self.not()?;
+        }
+        Some(value)
+    }
+    
+    fn not(&mut self) -> Option<bool> {
+        if self.peek() == Some("!") {
+            self.position += 1;
+            return self.not().map(|value| !value);
+        }
+        self.atom()
+    }
+    
+    fn atom(&mut self) -> Option<bool> {
+        let token = self.peek()?.to_string();
+        self.position += 1;
+        match token.as_str() {
+            "(" => {
+                let value = self.or()?;
+                if self.peek() != Some(")") {
+                    return None;
+                }
+                self.position += 1;
+                Some(value)
+            }
+            ")" | "&&" | "||" => None,
+            tag => Some((self.is_set)(tag)),
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    #[test]
+    fn test_build_constraint() {
+        assert_eq!(build_constraint("//go:build linux && !cgo\n\npackage net\n").as_deref(), Some("linux && !cgo"));
+        // //go:build wins over the legacy lines gofmt keeps next to it
+        assert_eq!(
+            build_constraint("// Copyright\n\n//go:build linux\n// +build linux\n\npackage net\n").as_deref(),
+            Some("linux")
+        );
+        assert_eq!(build_constraint("// +build linux,amd64 darwin\n\npackage net\n").as_deref(), Some("(linux && amd64) || darwin"));
+        assert_eq!(build_constraint("// +build linux darwin\n// +build !cgo\n\npackage net\n").as_deref(), Some("(linux || darwin) && !cgo"));
+        // Only the file header counts
+        assert_eq!(build_constraint("package net\n\n//go:build linux\n"), None);
+        assert_eq!(build_constraint("//go:buildx linux\npackage net\n"), None);
+    }
+    
+    #[test]
+    fn test_satisfies() {
+        let tags = BuildTags::new(&["linux".to_string(), "amd64".to_string()]);
+        
+        assert!(tags.satisfies("linux && amd64"));
+        assert!(tags.satisfies("(darwin || linux) && !cgo"));
+        assert!(!tags.satisfies("windows"));
+        assert!(!tags.satisfies("linux && !amd64"));
+        assert!(tags.satisfies("unix && go1.21"));
+        assert!(tags.satisfies("linux &&"));
+        assert_eq!(evaluate("linux &&", &|_| true), None);
+        assert_eq!(evaluate("!(a || b) && c", &|tag| tag == "c"), Some(true));
+    }
+    
+    #[test]
+    fn test_includes() {
+        let tags = BuildTags::new(&["linux".to_string(), "amd64".to_string()]);
+        
+        assert!(tags.includes(Path::new("net_linux.go"), "package net\n"));
+        assert!(tags.includes(Path::new("net_linux_amd64_test.go"), "package net\n"));
+        assert!(!tags.includes(Path::new("net_windows.go"), "package net\n"));
+        assert!(!tags.includes(Path::new("net_linux_arm64.go"), "package net\n"));
+        // No prefix: an ordinary file named after the OS
+        assert!(tags.includes(Path::new("windows.go"), "package net\n"));
+        assert!(!tags.includes(Path::new("net.go"), "//go:build darwin\n\npackage net\n"));
+    }
+}
@@ -0,0 +1,134 @@
+//! Ignored `error` results of Go calls (heuristic)
+//!
+//! Without type checking only callees defined in the analyzed set are known,
+//! so a call is flagged when it resolves to a function or method of the set
+//! whose last result is `error`, and the call site either drops all results
+//! (`store.Save(x)`) or assigns that position to `_` (`v, _ := Load()`).
+//! Standard library and third-party calls are never reported; neither are
+//! `defer` / `go` statements, whose results cannot be used at all.
+
+use std::collections::HashMap;
+use std::path::PathBuf;
+
+use tree_sitter::Node;
+
+use crate::analyzers::go::packages::{ImportTable, PackageIndex};
+use crate::core::types::{AnalysisResult, DiscardedResults, FunctionCall, Language, UncheckedError};
+
+/// Results a call site throws away, or `None` if it uses them
+pub fn discarded_results(call: Node, source: &str) -> Option<DiscardedResults> {
+    let parent = call.parent()?;
+    match parent.kind() {
+        "expression_statement" => Some(DiscardedResults::All),
+        // The call must be the only value on the right: `v, _ := f()`
+        "expression_list" if parent.named_child_count() == 1 => {
+            let statement = parent.parent()?;
+            if !matches!(statement.kind(), "assignment_statement" | "short_var_declaration")
+                || statement.child_by_field_name("right")?.id() != parent.id()
+            {
+                return None;
+            }
+            let left = statement.child_by_field_name("left")?;
+            let mut cursor = left.walk();
+            let targets: Vec<Node> = left.named_children(&mut cursor).collect();
+            let positions: Vec<u32> = targets.iter()
+                .enumerate()
+                .filter(|(_, target)| target.utf8_text(source.as_bytes()) == Ok("_"))
+                .map(|(i, _)| i as u32)
+                .collect();
+            (!positions.is_empty()).then_some(DiscardedResults::Blank { positions, arity: targets.len() as u32 })
+        }
+        _ => None,
+    }
+}
+
+/// Fill `unchecked_errors` on the Go files of the analyzed set
+pub fn link_unchecked_errors(files: &mut [AnalysisResult]) {
+    let packages = PackageIndex::build(files);
+
+    // Result counts of the functions / methods whose last result is `error`
+    let mut functions: HashMap<(PathBuf, u32), usize> = HashMap::new();
+    let mut methods: HashMap<(String, String), usize> = HashMap::new();
+    for file in files.iter().filter(|f| f.language == Language::Go) {
+        for func in file.functions.iter().filter(|f| f.returns.last().map(String::as_str) == Some("error")) {
+            match func.metadata.get("receiver_type") {
+                Some(receiver_type) => {
+                    methods.insert((receiver_type.clone(), func.name.clone()), func.returns.len());
+                }
+                None => {
+                    functions.insert((file.file_info.path.clone(), func.start_line), func.returns.len());
+                }
+            }
+        }
+    }
+
+    let findings: Vec<Vec<UncheckedError>> = files.iter()
+        .map(|file| match file.language {
+            Language::Go => unchecked_in(file, &packages, &functions, &methods),
+            _ => Vec::new(),
+        })
+        .collect();
+    for (file, unchecked) in files.iter_mut().zip(findings) {
+        file.unchecked_errors = unchecked;
+    }
+}
+
+/// Helper: Calls of one file that drop a resolved callee's `error`
+fn unchecked_in(
+    file: &AnalysisResult,
+    packages: &PackageIndex,
+    functions: &HashMap<(PathBuf, u32), usize>,
+    methods: &HashMap<(String, String), usize>,
+) -> Vec<UncheckedError> {
+    let imports = ImportTable::new(&file.imports, packages);
+    let caller_package = packages.package_of(&file.file_info.path);
+
+    let mut unchecked = Vec::new();
+    for call in &file.function_calls {
+        let Some(discarded) = &call.discarded_results else { continue };
+        let Some((callee, arity)) = resolve(file, call, packages, &imports, caller_package, functions, methods) else { continue };
+        let drops_error = match discarded {
+            DiscardedResults::All => true,
+            DiscardedResults::Blank { positions, arity: assigned } => {
+                *assigned as usize == arity && positions.contains(&(arity as u32 - 1))
+            }
+        };
+        if drops_error {
+            unchecked.push(UncheckedError { line_number: call.line_number, callee });
+        }
+    }
+    unchecked
+}
+
+/// Helper: Display name and result count of an error-returning callee
+fn resolve(
+    file: &AnalysisResult,
+    call: &FunctionCall,
+    packages: &PackageIndex,
+    imports: &ImportTable,
+    caller_package: Option<&str>,
+    functions: &HashMap<(PathBuf, u32), usize>,
+    methods: &HashMap<(String, String), usize>,
+) -> Option<(String, usize)> {
+    if call.is_method_call {
+        // Inferred variable type, else the receiver of the enclosing method
+        let object = call.object_name.as_deref().unwrap_or("");
+        let receiver_type = call.receiver_type.clone().or_else(|| {
+            file.functions.iter()
+                .filter(|f| f.start_line <= call.line_number && call.line_number <= f.end_line)
+                .find(|f| f.metadata.get("receiver_name").map(String::as_str) == Some(object))
+                .and_then(|f| f.metadata.get("receiver_type").cloned())
+        });
+        if let Some(receiver_type) = receiver_type {
+            let arity = methods.get(&(receiver_type.clone(), call.function_name.clone()))?;
+            return Some((format!("{}.{}", receiver_type, call.function_name), *arity));
+        }
+    }
+
+    // Package-level function: same package, dot import or `pkg.Func`
+    let key = packages.resolve_call(imports, caller_package, call)?;
+    let definitions = packages.get(&key)?.functions.get(&call.function_name)?;
+    let arity = definitions.iter()
+        .find_map(|(path, line)| functions.get(&(path.clone(), *line)))?;
+    Some((call.full_name(), *arity))
+}
@@ -0,0 +1,343 @@
+//! Go interface satisfaction
+//!
+//! Go interfaces are satisfied implicitly: a type implements an interface when
+//! its method set covers every interface method with the same signature.
+//! Methods with a value receiver belong to both `T` and `*T`, methods with a
+//! pointer receiver only to `*T`.
+//!
+//! Embedded fields promote their methods: embedding `*E` promotes all of
+//! `E`'s methods, embedding `E` keeps `E`'s pointer receiver methods on `*T`
+//! only, and an embedded interface promotes the methods it requires. A type's
+//! own methods shadow promoted ones; a name promoted by two embedded types is
+//! ambiguous and not promoted.
+
+use std::collections::{BTreeSet, HashMap, HashSet};
+
+use crate::core::types::{AnalysisResult, Language};
+
+/// Method name -> (signature, has pointer receiver)
+type MethodSet = HashMap<String, Vec<(String, bool)>>;
+
+/// Fill `implements` / `implementors` on Go types across the given files
+pub fn link_implementations(files: &mut [AnalysisResult]) {
+    let go_files = || files.iter().filter(|f| f.language == Language::Go);
+    
+    // Interface method requirements (embedded interfaces are expanded below)
+    let mut interfaces: HashMap<String, (Vec<(String, String)>, Vec<String>)> = HashMap::new();
+    for file in go_files() {
+        for class in file.classes.iter().filter(|c| is_interface(c.metadata.get("type"))) {
+            let methods = class.methods.iter()
+                .map(|m| (m.name.clone(), m.metadata.get("signature").cloned().unwrap_or_default()))
+                .collect();
+            interfaces.insert(class.name.clone(), (methods, class.embeds.clone()));
+        }
+    }
+    
+    // Method sets of concrete types, keyed by receiver base type
+    let mut method_sets: HashMap<String, MethodSet> = HashMap::new();
+    for file in go_files() {
+        for func in &file.functions {
+            if let Some(receiver_type) = func.metadata.get("receiver_type") {
+                let signature = func.metadata.get("signature").cloned().unwrap_or_default();
+                let pointer = func.metadata.get("pointer_receiver").map_or(false, |p| p == "true");
+                method_sets.entry(receiver_type.clone())
+                    .or_default()
+                    .entry(func.name.clone())
+                    .or_default()
+                    .push((signature, pointer));
+            }
+        }
+    }
+    
+    let concrete_classes = || go_files()
+        .flat_map(|f| f.classes.iter())
+        .filter(|c| !is_interface(c.metadata.get("type")));
+    let embeds: HashMap<String, Vec<String>> = concrete_classes()
+        .filter(|c| !c.embeds.is_empty())
+        .map(|c| (c.name.clone(), c.embeds.clone()))
+        .collect();
+    let concrete_types: BTreeSet<String> = concrete_classes().map(|c| c.name.clone()).collect();
+    
+    // Own methods plus those promoted through embedded fields
+    let full_method_sets: HashMap<String, MethodSet> = concrete_types.iter()
+        .map(|name| (name.clone(), promoted_method_set(name, &method_sets, &embeds, &interfaces, &mut HashSet::new())))
+        .filter(|(_, methods)| !methods.is_empty())
+        .collect();
+    
+    // interface -> implementors, type -> (interfaces, pointer-only interfaces)
+    let mut implementors: HashMap<String, BTreeSet<String>> = HashMap::new();
+    let mut implemented: HashMap<String, (BTreeSet<String>, BTreeSet<String>)> = HashMap::new();
+    
+    for interface in interfaces.keys() {
+        let required = required_methods(interface, &interfaces, &mut HashSet::new());
+        // The empty interface is satisfied by everything and carries no information
+        if required.is_empty() {
+            continue;
+        }
+        
+        for type_name in &concrete_types {
+            let methods = match full_method_sets.get(type_name) {
+                Some(methods) => methods,
+                None => continue,
+            };
+            
+            let mut value_ok = true;
+            let mut pointer_ok = true;
+            for (name, signature) in &required {
+                let candidates = methods.get(name).map(|c| c.as_slice()).unwrap_or(&[]);
+                let matching: Vec<bool> = candidates.iter()
+                    .filter(|(candidate, _)| candidate == signature)
+                    .map(|(_, pointer)| *pointer)
+                    .collect();
+                if matching.is_empty() {
+                    pointer_ok = false;
+                }
+                if !matching.iter().any(|pointer| !pointer) {
+                    value_ok = false;
+                }
+            }
+            
+            if pointer_ok {
+                implementors.entry(interface.clone()).or_default().insert(type_name.clone());
+                let entry = implemented.entry(type_name.clone()).or_default();
+                entry.0.insert(interface.clone());
+                if !value_ok {
+                    entry.1.insert(interface.clone());
+                }
+            }
+        }
+    }
+    
+    for file in files.iter_mut().filter(|f| f.language == Language::Go) {
+        for class in &mut file.classes {
+            if is_interface(class.metadata.get("type")) {
+                class.implementors = implementors.get(&class.name)
+                    .map(|set| set.iter().cloned().collect())
+                    .unwrap_or_default();
+            } else {
+                let (interfaces, pointer_only) = implemented.get(&class.name).cloned().unwrap_or_default();
+                class.implements = interfaces.into_iter().collect();
+                if pointer_only.is_empty() {
+                    class.metadata.remove("pointer_receiver_implements");
+                } else {
+                    // Only *T satisfies these (pointer receiver methods)
+                    class.metadata.insert(
+                        "pointer_receiver_implements".to_string(),
+                        pointer_only.into_iter().collect::<Vec<_>>().join(","),
+                    );
+                }
+            }
+        }
+    }
+}
+
+/// Helper: Class metadata "type" marks an interface
+fn is_interface(kind: Option<&String>) -> bool {
+    kind.map_or(false, |k| k == "interface")
+}
+
+/// Helper: Method set of a concrete type including methods promoted from embedded fields
+fn promoted_method_set(
+    type_name: &str,
+    method_sets: &HashMap<String, MethodSet>,
+    embeds: &HashMap<String, Vec<String>>,
+    interfaces: &HashMap<String, (Vec<(String, String)>, Vec<String>)>,
+    visiting: &mut HashSet<String>,
+) -> MethodSet {
+    let mut methods = method_sets.get(type_name).cloned().unwrap_or_default();
+    // `type Node struct { *Node }` is legal; stop at the cycle
+    if !visiting.insert(type_name.to_string()) {
+        return methods;
+    }
+    
+    // name -> (candidates, number of embedded types promoting it)
+    let mut promoted: HashMap<String, (Vec<(String, bool)>, usize)> = HashMap::new();
+    for embed in embeds.get(type_name).map(|e| e.as_slice()).unwrap_or(&[]) {
+        let pointer_embed = embed.starts_with('*');
+        let embedded = base_type_name(embed);
+        
+        let embedded_methods: MethodSet = if interfaces.contains_key(embedded) {
+            let mut set = MethodSet::new();
+            for (name, signature) in required_methods(embedded, interfaces, &mut HashSet::new()) {
+                set.entry(name).or_default().push((signature, false));
+            }
+            set
+        } else {
+            promoted_method_set(embedded, method_sets, embeds, interfaces, visiting)
+        };
+        
+        for (name, candidates) in embedded_methods {
+            let entry = promoted.entry(name).or_default();
+            entry.0.extend(candidates.into_iter().map(|(signature, pointer)| (signature, pointer && !pointer_embed)));
+            entry.1 += 1;
+        }
+    }
+    
+    for (name, (candidates, sources)) in promoted {
+        if sources == 1 && !methods.contains_key(&name) {
+            methods.insert(name, candidates);
+        }
+    }
+    
+    visiting.remove(type_name);
+    methods
+}
+
+/// Helper: `*pkg.List[T]` -> `List`
+fn base_type_name(embed: &str) -> &str {
+    let name = embed.trim_start_matches('*');
+    let name = name.split('[').next().unwrap_or(name);
+    name.rsplit('.').next().unwrap_or(name)
+}
+
+/// Helper: All (name, signature) pairs of an interface including embedded ones
+fn required_methods(
+    interface: &str,
+    interfaces: &HashMap<String, (Vec<(String, String)>, Vec<String>)>,
+    visited: &mut HashSet<String>,
+) -> Vec<(String, String)> {
+    if !visited.insert(interface.to_string()) {
+        return Vec::new();
+    }
+    
+    let (methods, embedded) = match interfaces.get(interface) {
+        Some(entry) => entry,
+        None => return Vec::new(),
+    };
+    
+    let mut required = methods.clone();
+    for name in embedded {
+        // pkg.Reader -> Reader
+        let local = name.rsplit('.').next().unwrap_or(name);
+        required.extend(required_methods(local, interfaces, visited));
+    }
+    
+    required.sort();
+    required.dedup();
+    required
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ClassInfo, FileInfo, FunctionInfo};
+    use std::path::PathBuf;
+    
+    fn interface(name: &str, methods: &[(&str, &str)], embeds: Option<&str>) -> ClassInfo {
+        let mut class = ClassInfo::new(name.to_string());
+        class.metadata.insert("type".to_string(), "interface".to_string());
+        if let Some(embeds) = embeds {
+            class.embeds = embeds.split(',').map(|s| s.to_string()).collect();
+        }
+        for (method, signature) in methods {
+            let mut func = FunctionInfo::new(method.to_string());
+            func.metadata.insert("signature".to_string(), signature.to_string());
+            class.methods.push(func);
+        }
+        class
+    }
+    
+    fn structure(name: &str) -> ClassInfo {
+        let mut class = ClassInfo::new(name.to_string());
+        class.metadata.insert("type".to_string(), "struct".to_string());
+        class
+    }
+    
+    fn embedding(name: &str, embeds: &[&str]) -> ClassInfo {
+        let mut class = structure(name);
+        class.embeds = embeds.iter().map(|e| e.to_string()).collect();
+        class
+    }
+    
+    fn method(receiver: &str, name: &str, signature: &str, pointer: bool) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.metadata.insert("receiver_type".to_string(), receiver.to_string());
+        func.metadata.insert("signature".to_string(), signature.to_string());
+        func.metadata.insert("pointer_receiver".to_string(), pointer.to_string());
+        func
+    }
+    
+    #[test]
+    fn test_link_implementations() {
+        let mut a = AnalysisResult::new(FileInfo::new(PathBuf::from("a.go")), Language::Go);
+        a.classes = vec![
+            interface("Processor", &[("ProcessData", "([]string) []string")], None),
+            interface("Named", &[("Name", "() string")], None),
+            interface("NamedProcessor", &[], Some("Processor,Named")),
+            interface("Any", &[], None),
+        ];
+        
+        let mut b = AnalysisResult::new(FileInfo::new(PathBuf::from("b.go")), Language::Go);
+        b.classes = vec![structure("DataProcessor"), structure("Label"), structure("Wrong")];
+        b.functions = vec![
+            method("DataProcessor", "ProcessData", "([]string) []string", true),
+            method("DataProcessor", "Name", "() string", false),
+            method("Label", "Name", "() string", false),
+            // Same name, different signature
+            method("Wrong", "ProcessData", "(string) string", false),
+        ];
+        
+        let mut files = vec![a, b];
+        link_implementations(&mut files);
+        
+        let class = |file: usize, name: &str| files[file].classes.iter().find(|c| c.name == name).unwrap().clone();
+        
+        assert_eq!(class(0, "Processor").implementors, vec!["DataProcessor"]);
+        assert_eq!(class(0, "Named").implementors, vec!["DataProcessor", "Label"]);
+        assert_eq!(class(0, "NamedProcessor").implementors, vec!["DataProcessor"]);
+        assert!(class(0, "Any").implementors.is_empty());
+        
+        let data_processor = class(1, "DataProcessor");
+        assert_eq!(data_processor.implements, vec!["Named", "NamedProcessor", "Processor"]);
+        // ProcessData has a pointer receiver: only *DataProcessor satisfies Processor
+        assert_eq!(data_processor.metadata.get("pointer_receiver_implements").map(|s| s.as_str()), Some("NamedProcessor,Processor"));
+        
+        assert_eq!(class(1, "Label").implements, vec!["Named"]);
+        assert!(class(1, "Label").metadata.get("pointer_receiver_implements").is_none());
+        assert!(class(1, "Wrong").implements.is_empty());
+    }
+    
+    #[test]
+    fn test_promoted_methods() {
+        let mut file = AnalysisResult::new(FileInfo::new(PathBuf::from("a.go")), Language::Go);
+        file.classes = vec![
+            interface("Closer", &[("Close", "() error")], None),
+            interface("Named", &[("Name", "() string")], None),
+            interface("NamedCloser", &[], Some("Named,Closer")),
+            structure("Base"),
+            embedding("ByValue", &["Base"]),
+            embedding("ByPointer", &["*Base"]),
+            embedding("Nested", &["ByPointer"]),
+            embedding("WithCloser", &["Base", "io.Closer"]),
+            embedding("Ambiguous", &["Base", "Other"]),
+            structure("Other"),
+            embedding("Cycle", &["*Cycle"]),
+        ];
+        file.functions = vec![
+            method("Base", "Name", "() string", false),
+            method("Base", "Close", "() error", true),
+            method("Other", "Name", "() string", false),
+        ];
+        
+        let mut files = vec![file];
+        link_implementations(&mut files);
+        
+        let class = |name: &str| files[0].classes.iter().find(|c| c.name == name).unwrap().clone();
+        let pointer_only = |name: &str| class(name).metadata.get("pointer_receiver_implements").cloned();
+        
+        // Value embedding keeps Base's pointer receiver Close on *ByValue only
+        assert_eq!(class("ByValue").implements, vec!["Closer", "Named", "NamedCloser"]);
+        assert_eq!(pointer_only("ByValue").as_deref(), Some("Closer,NamedCloser"));
+        
+        // Pointer embedding promotes every method to the value type
+        assert_eq!(class("ByPointer").implements, vec!["Closer", "Named", "NamedCloser"]);
+        assert_eq!(pointer_only("ByPointer"), None);
+        assert_eq!(class("Nested").implements, vec!["Closer", "Named", "NamedCloser"]);
+        
+        // An embedded interface contributes its methods; Base's Close is then ambiguous
+        assert_eq!(class("WithCloser").implements, vec!["Named"]);
+        // Name comes from both Base and Other at the same depth; Close only from Base
+        assert_eq!(class("Ambiguous").implements, vec!["Closer"]);
+        assert!(class("Cycle").implements.is_empty());
+    }
+}
@@ -0,0 +1,355 @@
+//! Package initialization of Go files: `init` functions and package-level variables
+//!
+//! Importing a package runs code before `main`: every package-level `var`
+//! initializer, then every `init` function. Variables are initialized in
+//! dependency order, otherwise in declaration order across the package's
+//! files (presented to the compiler sorted by name); `init` functions run in
+//! the same file order, in source order within a file.
+//!
+//! `package_variables` lists each package-level `var` / `const` with its
+//! initializer, flagged non-trivial when it calls a function (other than a
+//! builtin or a conversion to a predeclared or file-local type) or receives
+//! from a channel. `order_initialization` then orders the variables of each
+//! package. Dependencies are the names an initializer refers to, plus the
+//! package-level names of the same file read by the functions it refers to
+//! (transitively); reads by functions in other files and by methods are not
+//! seen.
+
+use std::collections::{BTreeMap, HashMap, HashSet};
+use std::path::PathBuf;
+
+use tree_sitter::Node;
+
+use crate::analyzers::go::assertions::PREDECLARED;
+use crate::analyzers::go::packages::{ImportTable, PackageIndex};
+use crate::core::types::{AnalysisResult, InitFunction, InitStep, Language, PackageInit, VariableInfo};
+
+/// Builtins that neither run project code nor have side effects
+const PURE_BUILTINS: &[&str] = &["append", "cap", "complex", "imag", "len", "make", "max", "min", "new", "real"];
+
+/// Predeclared names that are not types
+const PREDECLARED_VALUES: &[&str] = &[
+    "true", "false", "nil", "iota", "append", "cap", "clear", "close", "complex", "copy", "delete",
+    "imag", "len", "make", "max", "min", "new", "panic", "print", "println", "real", "recover",
+];
+
+/// Initializers longer than this are cut, with `...` appended
+const MAX_INITIALIZER_CHARS: usize = 200;
+
+/// Package-level `var` / `const` declarations of a file, in source order
+pub fn package_variables(root: Node, source: &str, result: &AnalysisResult) -> Vec<VariableInfo> {
+    let imports = ImportTable::new(&result.imports, &PackageIndex::default());
+    let types: HashSet<&str> = result.classes.iter().map(|class| class.name.as_str()).collect();
+    let mut scope = Scope { source, imports: &imports, types: &types, functions: HashMap::new(), top_level: HashSet::new() };
+    let mut cursor = root.walk();
+    for declaration in root.named_children(&mut cursor) {
+        match declaration.kind() {
+            "function_declaration" => {
+                if let (Some(name), Some(body)) = (declaration.child_by_field_name("name"), declaration.child_by_field_name("body")) {
+                    let name = scope.text(name);
+                    scope.functions.insert(name, body);
+                    scope.top_level.insert(name);
+                }
+            }
+            "var_declaration" | "const_declaration" => {
+                for spec in specs(declaration) {
+                    let mut cursor = spec.walk();
+                    let names: Vec<&str> = spec.children_by_field_name("name", &mut cursor).map(|n| scope.text(n)).collect();
+                    scope.top_level.extend(names);
+                }
+            }
+            _ => {}
+        }
+    }
+
+    let mut variables = Vec::new();
+    let mut cursor = root.walk();
+    for declaration in root.named_children(&mut cursor) {
+        let is_const = match declaration.kind() {
+            "var_declaration" => false,
+            "const_declaration" => true,
+            _ => continue,
+        };
+        // Within a `const ( ... )` group a spec without values repeats the previous one
+        let mut previous: Vec<Node> = Vec::new();
+        for spec in specs(declaration) {
+            let mut cursor = spec.walk();
+            let names: Vec<Node> = spec.children_by_field_name("name", &mut cursor).collect();
+            let values: Vec<Node> = match spec.child_by_field_name("value") {
+                Some(list) => {
+                    let mut cursor = list.walk();
+                    list.named_children(&mut cursor).filter(|n| n.kind() != "comment").collect()
+                }
+                None if is_const => previous.clone(),
+                None => Vec::new(),
+            };
+            previous = values.clone();
+            let var_type = spec.child_by_field_name("type")
+                .map(|t| scope.text(t).to_string())
+                .unwrap_or_default();
+
+            for (i, name) in names.iter().enumerate() {
+                // `a, b = 1, 2` pairs up; `a, b = f()` shares the one call
+                let value: &[Node] = if values.len() == names.len() { &values[i..=i] } else { &values };
+                let mut depends_on = Vec::new();
+                let mut expanded = HashSet::new();
+                for node in value {
+                    scope.references(*node, false, &mut expanded, &mut depends_on);
+                }
+                variables.push(VariableInfo {
+                    name: scope.text(*name).to_string(),
+                    var_type: var_type.clone(),
+                    line_number: name.start_position().row as u32 + 1,
+                    is_const,
+                    package_level: true,
+                    initializer: initializer_text(value, source),
+                    non_trivial_initializer: !is_const && value.iter().any(|node| scope.runs_code(*node)),
+                    depends_on,
+                    init_order: None,
+                });
+            }
+        }
+    }
+    variables
+}
+
+/// `init` functions of a file result, with the calls in their bodies
+pub fn init_functions(result: &AnalysisResult) -> Vec<InitFunction> {
+    result.functions.iter()
+        .filter(|func| func.name == "init" && !func.metadata.contains_key("receiver_type"))
+        .enumerate()
+        .map(|(i, func)| {
+            let mut calls: Vec<String> = Vec::new();
+            for call in result.function_calls.iter().filter(|c| c.line_number >= func.start_line && c.line_number <= func.end_line) {
+                let name = call.full_name();
+                if !calls.contains(&name) {
+                    calls.push(name);
+                }
+            }
+            InitFunction {
+                line_number: func.start_line,
+                end_line: func.end_line,
+                order: i as u32 + 1,
+                calls,
+            }
+        })
+        .collect()
+}
+
+/// Set `init_order` on the package-level variables of the Go files, package by
+/// package, and list each package's initialization steps
+pub fn order_initialization(files: &mut [AnalysisResult]) -> Vec<PackageInit> {
+    // (directory, package clause) -> file indices; external test packages stay apart
+    let mut packages: BTreeMap<(PathBuf, String), Vec<usize>> = BTreeMap::new();
+    for (index, file) in files.iter().enumerate().filter(|(_, f)| f.language == Language::Go) {
+        if file.variables.is_empty() && file.init_functions.is_empty() {
+            continue;
+        }
+        let dir = file.file_info.path.parent().map(|p| p.to_path_buf()).unwrap_or_default();
+        let package = file.metadata.get("package").cloned().unwrap_or_default();
+        packages.entry((dir, package)).or_default().push(index);
+    }
+
+    let mut inits = Vec::new();
+    for ((directory, package), mut members) in packages {
+        members.sort_by(|a, b| files[*a].file_info.path.file_name().cmp(&files[*b].file_info.path.file_name()));
+        let order = variable_order(files, &members);
+
+        let mut variables = Vec::new();
+        for (position, (file, variable)) in order.into_iter().enumerate() {
+            let path = files[file].file_info.path.clone();
+            let info = &mut files[file].variables[variable];
+            info.init_order = Some(position as u32 + 1);
+            variables.push(InitStep {
+                name: info.name.clone(),
+                file: path,
+                line_number: info.line_number,
+                runs_code: info.non_trivial_initializer,
+            });
+        }
+        let init_functions = members.iter()
+            .flat_map(|&file| files[file].init_functions.iter().map(move |init| (file, init)))
+            .map(|(file, init)| InitStep {
+                name: "init".to_string(),
+                file: files[file].file_info.path.clone(),
+                line_number: init.line_number,
+                runs_code: !init.calls.is_empty(),
+            })
+            .collect::<Vec<_>>();
+
+        // Constants alone initialize nothing
+        if variables.is_empty() && init_functions.is_empty() {
+            continue;
+        }
+        inits.push(PackageInit { package, directory, variables, init_functions });
+    }
+    inits
+}
+
+/// Helper: (file, variable) indices of a package's `var`s in initialization order
+///
+/// Repeatedly takes the first variable in declaration order whose dependencies
+/// are all initialized; a cycle (a compile error in Go) falls back to
+/// declaration order.
+fn variable_order(files: &[AnalysisResult], members: &[usize]) -> Vec<(usize, usize)> {
+    let declared: Vec<(usize, usize)> = members.iter()
+        .flat_map(|&file| {
+            files[file].variables.iter()
+                .enumerate()
+                .filter(|(_, v)| !v.is_const)
+                .map(move |(variable, _)| (file, variable))
+        })
+        .collect();
+    let by_name: HashMap<&str, usize> = declared.iter()
+        .enumerate()
+        .map(|(i, &(file, variable))| (files[file].variables[variable].name.as_str(), i))
+        .filter(|(name, _)| *name != "_")
+        .collect();
+    let dependencies: Vec<HashSet<usize>> = declared.iter()
+        .map(|&(file, variable)| {
+            files[file].variables[variable].depends_on.iter()
+                .filter_map(|name| by_name.get(name.as_str()).copied())
+                .collect()
+        })
+        .collect();
+
+    let mut initialized = vec![false; declared.len()];
+    let mut order = Vec::with_capacity(declared.len());
+    while order.len() < declared.len() {
+        let ready = (0..declared.len())
+            .find(|&i| !initialized[i] && dependencies[i].iter().all(|&d| d == i || initialized[d]))
+            .or_else(|| initialized.iter().position(|done| !done));
+        let Some(next) = ready else { break };
+        initialized[next] = true;
+        order.push(declared[next]);
+    }
+    order
+}
+
+/// Helper: `var_spec` / `const_spec` children, also inside a parenthesized group
+fn specs(declaration: Node) -> Vec<Node> {
+    let mut specs = Vec::new();
+    let mut cursor = declaration.walk();
+    for child in declaration.named_children(&mut cursor) {
+        match child.kind() {
+            "var_spec" | "const_spec" => specs.push(child),
+            "var_spec_list" => {
+                let mut inner = child.walk();
+                specs.extend(child.named_children(&mut inner).filter(|n| n.kind() == "var_spec"));
+            }
+            _ => {}
+        }
+    }
+    specs
+}
+
+/// Helper: Source of the initializer expressions, cut at `MAX_INITIALIZER_CHARS`
+fn initializer_text(values: &[Node], source: &str) -> String {
+    let (Some(first), Some(last)) = (values.first(), values.last()) else { return String::new() };
+    let text = &source[first.start_byte()..last.end_byte()];
+    match text.char_indices().nth(MAX_INITIALIZER_CHARS) {
+        Some((cut, _)) => format!("{}...", &text[..cut]),
+        None => text.to_string(),
+    }
+}
+
+/// What an initializer's names resolve against
+struct Scope<'a> {
+    source: &'a str,
+    imports: &'a ImportTable,
+    /// Types declared in the file
+    types: &'a HashSet<&'a str>,
+    /// Bodies of the file's functions
+    functions: HashMap<&'a str, Node<'a>>,
+    /// Functions, variables and constants declared at the file's top level
+    top_level: HashSet<&'a str>,
+}
+
+impl<'a> Scope<'a> {
+    fn text(&self, node: Node) -> &'a str {
+        node.utf8_text(self.source.as_bytes()).unwrap_or("")
+    }
+
+    /// Helper: Whether evaluating `node` calls a function or receives from a channel
+    fn runs_code(&self, node: Node) -> bool {
+        match node.kind() {
+            // Defining a function runs nothing
+            "func_literal" => return false,
+            "call_expression" => {
+                let callee = node.child_by_field_name("function");
+                let pure = callee.filter(|c| c.kind() == "identifier" || c.kind() == "type_identifier")
+                    .map(|c| self.text(c))
+                    .map_or(false, |name| PURE_BUILTINS.contains(&name) || PREDECLARED.contains(&name) || self.types.contains(name));
+                // `[]byte(s)`, `(*T)(p)`: conversions to type literals
+                let conversion = callee.map_or(false, |c| c.kind().ends_with("_type") || c.kind() == "parenthesized_type");
+                if !pure && !conversion {
+                    return true;
+                }
+            }
+            "unary_expression" => {
+                if node.child_by_field_name("operator").map(|op| self.text(op)) == Some("<-") {
+                    return true;
+                }
+            }
+            _ => {}
+        }
+        let mut cursor = node.walk();
+        let runs = node.named_children(&mut cursor).any(|child| self.runs_code(child));
+        runs
+    }
+
+    /// Helper: Collect the names `node` refers to, skipping imported packages,
+    /// predeclared names and composite literal keys, and continuing into the
+    /// bodies of the file's functions it refers to
+    ///
+    /// Inside a function body (`in_body`) only the file's top-level names count;
+    /// the rest are locals or declared in other files.
+    fn references(&self, node: Node<'a>, in_body: bool, expanded: &mut HashSet<&'a str>, names: &mut Vec<String>) {
+        match node.kind() {
+            "identifier" => {
+                let name = self.text(node);
+                let predeclared = PREDECLARED_VALUES.contains(&name) || PREDECLARED.contains(&name);
+                if predeclared || name == "_" || (in_body && !self.top_level.contains(name)) {
+                    return;
+                }
+                if !names.iter().any(|n| n == name) {
+                    names.push(name.to_string());
+                }
+                if let Some(body) = self.functions.get(name) {
+                    if expanded.insert(name) {
+                        self.references(*body, true, expanded, names);
+                    }
+                }
+                return;
+            }
+            // `pkg.Name` belongs to another package
+            "selector_expression" => {
+                if let Some(operand) = node.child_by_field_name("operand") {
+                    if !(operand.kind() == "identifier" && self.imports.import_path(self.text(operand)).is_some()) {
+                        self.references(operand, in_body, expanded, names);
+                    }
+                }
+                return;
+            }
+            // `Config{Name: value}`: the key is a field
+            "keyed_element" => {
+                if let Some(key) = node.child_by_field_name("key").or_else(|| node.named_child(0)) {
+                    let field = self.text(key);
+                    let mut cursor = node.walk();
+                    for child in node.named_children(&mut cursor) {
+                        let is_field_key = child.id() == key.id() && field.chars().all(|c| c.is_alphanumeric() || c == '_');
+                        if !is_field_key {
+                            self.references(child, in_body, expanded, names);
+                        }
+                    }
+                }
+                return;
+            }
+            _ => {}
+        }
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            self.references(child, in_body, expanded, names);
+        }
+    }
+}
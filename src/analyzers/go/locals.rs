@@ -0,0 +1,220 @@
+//! Lightweight local type inference for Go
+//!
+//! Method calls like `processor.ProcessData(data)` only name the receiver
+//! variable. To attribute them to `DataProcessor.ProcessData` the variable's
+//! type is inferred from its declaration in the enclosing function:
+//!
+//! - parameters and method receivers (`p *DataProcessor`)
+//! - `var p DataProcessor` / `var p = ...`
+//! - `p := NewDataProcessor(...)` (constructor returning `T` / `*T`)
+//! - `p := DataProcessor{...}`, `p := &DataProcessor{...}`, `p := new(DataProcessor)`
+//!
+//! Constructors are resolved through the result types of functions declared
+//! in the same file, falling back to the `NewT` naming convention.
+
+use std::collections::HashMap;
+use tree_sitter::Node;
+
+/// Variables declared in one function with their inferred type names
+pub struct LocalTypes {
+    /// (name, declaration byte offset, type name) in source order
+    bindings: Vec<(String, usize, String)>,
+}
+
+impl LocalTypes {
+    /// Collect the typed declarations of a function / method (closures included)
+    pub fn infer(func: Node, source: &str, constructors: &HashMap<String, String>) -> Self {
+        let mut locals = Self { bindings: Vec::new() };
+        if let Some(receiver) = func.child_by_field_name("receiver") {
+            locals.collect(receiver, source, constructors);
+        }
+        if let Some(parameters) = func.child_by_field_name("parameters") {
+            locals.collect(parameters, source, constructors);
+        }
+        if let Some(body) = func.child_by_field_name("body") {
+            locals.collect(body, source, constructors);
+        }
+        locals
+    }
+    
+    /// Type of `name` as declared most recently before `offset`
+    pub fn type_of(&self, name: &str, offset: usize) -> Option<&str> {
+        self.bindings.iter()
+            .filter(|(var, declared_at, _)| var == name && *declared_at <= offset)
+            .last()
+            .map(|(_, _, ty)| ty.as_str())
+    }
+    
+    /// Helper: Walk declarations below `node`
+    fn collect(&mut self, node: Node, source: &str, constructors: &HashMap<String, String>) {
+        match node.kind() {
+            "parameter_declaration" => {
+                let ty = node.child_by_field_name("type").and_then(|t| declared_type_name(t, source));
+                self.bind_names(node, ty, source);
+            }
+            "var_spec" => {
+                let declared = node.child_by_field_name("type").and_then(|t| declared_type_name(t, source));
+                match declared {
+                    Some(ty) => self.bind_names(node, Some(ty), source),
+                    None => {
+                        let names = field_children(node, "name");
+                        let values = node.child_by_field_name("value").map(named_children).unwrap_or_default();
+                        self.bind_pairs(&names, &values, node.start_byte(), source, constructors);
+                    }
+                }
+            }
+            "short_var_declaration" => {
+                let names = node.child_by_field_name("left").map(named_children).unwrap_or_default();
+                let values = node.child_by_field_name("right").map(named_children).unwrap_or_default();
+                self.bind_pairs(&names, &values, node.start_byte(), source, constructors);
+            }
+            _ => {}
+        }
+        
+        let mut cursor = node.walk();
+        for child in node.children(&mut cursor) {
+            self.collect(child, source, constructors);
+        }
+    }
+    
+    /// Helper: Bind every `name` field of a declaration to one type
+    fn bind_names(&mut self, node: Node, ty: Option<String>, source: &str) {
+        let Some(ty) = ty else { return };
+        for name in field_children(node, "name") {
+            if let Ok(text) = name.utf8_text(source.as_bytes()) {
+                self.bindings.push((text.to_string(), node.start_byte(), ty.clone()));
+            }
+        }
+    }
+    
+    /// Helper: Bind `a, b := x, y` positionally; `v, err := NewT()` binds the first name
+    fn bind_pairs(&mut self, names: &[Node], values: &[Node], offset: usize, source: &str, constructors: &HashMap<String, String>) {
+        if values.len() != names.len() && values.len() != 1 {
+            return;
+        }
+        for (name, value) in names.iter().zip(values) {
+            if name.kind() != "identifier" {
+                continue;
+            }
+            if let (Ok(text), Some(ty)) = (name.utf8_text(source.as_bytes()), expression_type(*value, source, constructors)) {
+                self.bindings.push((text.to_string(), offset, ty));
+            }
+        }
+    }
+}
+
+/// Result type names of the file's top-level functions (`NewDataProcessor` -> `DataProcessor`)
+pub fn constructor_types(root: Node, source: &str) -> HashMap<String, String> {
+    let mut constructors = HashMap::new();
+    
+    let mut cursor = root.walk();
+    for child in root.children(&mut cursor).filter(|c| c.kind() == "function_declaration") {
+        let name = child.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok());
+        let result = child.child_by_field_name("result").and_then(|result| {
+            // (*T, error): the first result is the constructed value
+            if result.kind() == "parameter_list" {
+                result.named_children(&mut result.walk())
+                    .next()
+                    .and_then(|first| first.child_by_field_name("type"))
+            } else {
+                Some(result)
+            }
+        });
+        if let (Some(name), Some(result)) = (name, result) {
+            if let Some(ty) = declared_type_name(result, source) {
+                constructors.insert(name.to_string(), ty);
+            }
+        }
+    }
+    
+    constructors
+}
+
+/// Helper: Type produced by an initializer expression, if it is evident
+fn expression_type(expr: Node, source: &str, constructors: &HashMap<String, String>) -> Option<String> {
+    match expr.kind() {
+        "composite_literal" => expr.child_by_field_name("type").and_then(|t| declared_type_name(t, source)),
+        "unary_expression" => {
+            let operator = expr.child_by_field_name("operator")?.utf8_text(source.as_bytes()).ok()?;
+            if operator != "&" {
+                return None;
+            }
+            expression_type(expr.child_by_field_name("operand")?, source, constructors)
+        }
+        "parenthesized_expression" => expression_type(expr.named_child(0)?, source, constructors),
+        "call_expression" => {
+            let function = expr.child_by_field_name("function")?;
+            let name = match function.kind() {
+                "identifier" => function.utf8_text(source.as_bytes()).ok()?,
+                // pkg.NewT(): only the naming convention can help across packages
+                "selector_expression" => function.child_by_field_name("field")?.utf8_text(source.as_bytes()).ok()?,
+                _ => return None,
+            };
+            
+            if name == "new" && function.kind() == "identifier" {
+                let arguments = expr.child_by_field_name("arguments")?;
+                let first = arguments.named_child(0)?;
+                return declared_type_name(first, source);
+            }
+            if function.kind() == "identifier" {
+                if let Some(ty) = constructors.get(name) {
+                    return Some(ty.clone());
+                }
+            }
+            constructor_convention(name)
+        }
+        _ => None,
+    }
+}
+
+/// Helper: `NewDataProcessor` -> `DataProcessor`
+fn constructor_convention(name: &str) -> Option<String> {
+    let rest = name.strip_prefix("New")?;
+    if rest.chars().next().map_or(false, |c| c.is_ascii_uppercase()) {
+        Some(rest.to_string())
+    } else {
+        None
+    }
+}
+
+/// Helper: Base name of a named type (`*pkg.Server[T]` -> `Server`); `None` for maps, slices, ...
+fn declared_type_name(node: Node, source: &str) -> Option<String> {
+    let text = node.utf8_text(source.as_bytes()).ok()?.trim();
+    let text = text.trim_start_matches(['*', '&']).trim();
+    let text = text.split('[').next().unwrap_or(text);
+    let name = text.rsplit('.').next().unwrap_or(text);
+    
+    let is_identifier = !name.is_empty()
+        && name.chars().all(|c| c.is_alphanumeric() || c == '_')
+        && !name.starts_with(|c: char| c.is_ascii_digit());
+    if is_identifier && !matches!(name, "map" | "chan" | "func" | "interface" | "struct") {
+        Some(name.to_string())
+    } else {
+        None
+    }
+}
+
+/// Helper: All children stored under a field name (`a, b int` has two `name`s)
+fn field_children<'a>(node: Node<'a>, field: &str) -> Vec<Node<'a>> {
+    let mut cursor = node.walk();
+    node.children_by_field_name(field, &mut cursor).collect()
+}
+
+/// Helper: Named children of an expression list
+fn named_children(node: Node) -> Vec<Node> {
+    let mut cursor = node.walk();
+    node.named_children(&mut cursor).collect()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    #[test]
+    fn test_constructor_convention() {
+        assert_eq!(constructor_convention("NewDataProcessor").as_deref(), Some("DataProcessor"));
+        assert_eq!(constructor_convention("Newline"), None);
+        assert_eq!(constructor_convention("New"), None);
+        assert_eq!(constructor_convention("CreateProcessor"), None);
+    }
+}
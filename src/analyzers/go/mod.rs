@@ -1,6 +1,18 @@
 pub mod analyzer;
 pub mod tree_sitter_analyzer;
+pub mod implements;
+pub mod locals;
+pub mod unused;
+pub mod panics;
+pub mod assertions;
+pub mod errcheck;
+pub mod build_tags;
+pub mod packages;
+pub mod initialization;
 // Grammar is embedded in analyzer.rs via pest_derive
 
 pub use analyzer::GoAnalyzer;
-pub use tree_sitter_analyzer::TreeSitterGoAnalyzer;
\ No newline at end of file
+pub use tree_sitter_analyzer::TreeSitterGoAnalyzer;
+pub use implements::link_implementations;
+pub use errcheck::link_unchecked_errors;
+pub use initialization::order_initialization;
@@ -0,0 +1,287 @@
+//! Go package-qualified references
+//!
+//! `proc.NewDataProcessor()` names a package by the local name an import
+//! gave it: the package clause name for `import "example.com/app/proc"`,
+//! `foo` for `import foo "example.com/app/proc"`, and no qualifier at all
+//! for a dot import. `ImportTable` maps those local names of one file back to
+//! import paths; `PackageIndex` maps import paths of the analyzed project
+//! (module path from the nearest `go.mod` plus the directory) to the
+//! package-level functions defined there.
+//!
+//! Without a `go.mod`, packages are keyed by directory and an import path
+//! resolves to the one directory ending in its longest matching suffix.
+
+use std::collections::HashMap;
+use std::fs;
+use std::path::{Path, PathBuf};
+
+use crate::core::types::{AnalysisResult, FunctionCall, ImportInfo, Language};
+
+/// One Go package of the analyzed set
+#[derive(Debug, Clone, Default)]
+pub struct GoPackage {
+    /// Name from the package clause
+    pub name: String,
+    pub dir: PathBuf,
+    /// `module/dir` import path (`None` outside a Go module)
+    pub import_path: Option<String>,
+    /// Package-level function name -> (file, line) definitions
+    pub functions: HashMap<String, Vec<(PathBuf, u32)>>,
+}
+
+/// Packages of the analyzed set, keyed by import path (or directory outside a module)
+#[derive(Debug, Clone, Default)]
+pub struct PackageIndex {
+    packages: HashMap<String, GoPackage>,
+    /// Directory -> key into `packages`
+    dirs: HashMap<PathBuf, String>,
+}
+
+impl PackageIndex {
+    pub fn build(files: &[AnalysisResult]) -> Self {
+        let mut index = Self::default();
+        let mut modules: HashMap<PathBuf, Option<(PathBuf, String)>> = HashMap::new();
+        
+        for file in files.iter().filter(|f| f.language == Language::Go) {
+            let dir = file.file_info.path.parent().unwrap_or_else(|| Path::new("")).to_path_buf();
+            let key = match index.dirs.get(&dir) {
+                Some(key) => key.clone(),
+                None => {
+                    let import_path = import_path_of(&dir, &mut modules);
+                    let key = import_path.clone().unwrap_or_else(|| dir.to_string_lossy().to_string());
+                    index.packages.insert(key.clone(), GoPackage { dir: dir.clone(), import_path, ..Default::default() });
+                    index.dirs.insert(dir, key.clone());
+                    key
+                }
+            };
+            let package = index.packages.get_mut(&key).expect("package registered above");
+            
+            // External test packages (`proc_test`) share the directory; keep the real name
+            if let Some(name) = file.metadata.get("package") {
+                if package.name.is_empty() || package.name.ends_with("_test") {
+                    package.name = name.clone();
+                }
+            }
+            for func in file.functions.iter().filter(|f| !f.metadata.contains_key("receiver_type")) {
+                package.functions.entry(func.name.clone())
+                    .or_default()
+                    .push((file.file_info.path.clone(), func.start_line));
+            }
+        }
+        
+        index
+    }
+    
+    /// Index key of the package `file` belongs to
+    pub fn package_of(&self, file: &Path) -> Option<&str> {
+        self.dirs.get(file.parent()?).map(String::as_str)
+    }
+    
+    /// Package by index key
+    pub fn get(&self, key: &str) -> Option<&GoPackage> {
+        self.packages.get(key)
+    }
+    
+    /// Index key of an import path, if the package is part of the analyzed set
+    pub fn lookup(&self, import_path: &str) -> Option<&str> {
+        if let Some((key, _)) = self.packages.get_key_value(import_path) {
+            return Some(key);
+        }
+        
+        // Outside a module: the directory ending in the longest suffix, if unambiguous
+        let segments: Vec<&str> = import_path.split('/').filter(|s| !s.is_empty()).collect();
+        for length in (1..=segments.len()).rev() {
+            let suffix: PathBuf = segments[segments.len() - length..].iter().collect();
+            let mut matches = self.packages.iter()
+                .filter(|(_, p)| p.import_path.is_none() && p.dir.ends_with(&suffix));
+            match (matches.next(), matches.next()) {
+                (Some((key, _)), None) => return Some(key),
+                (Some(_), Some(_)) => return None,
+                _ => {}
+            }
+        }
+        None
+    }
+    
+    /// Index key (or, for packages outside the set, import path) of the package
+    /// defining a package-level function call
+    ///
+    /// `None` for method calls on values and for unqualified names no package in
+    /// scope defines (builtins, locals).
+    pub fn resolve_call(&self, imports: &ImportTable, caller_package: Option<&str>, call: &FunctionCall) -> Option<String> {
+        let defines = |key: &str| self.get(key).map_or(false, |p| p.functions.contains_key(&call.function_name));
+        
+        if call.is_method_call {
+            // A variable with an inferred type is not a package
+            if call.receiver_type.is_some() {
+                return None;
+            }
+            let import_path = imports.import_path(call.object_name.as_deref()?)?;
+            return Some(self.lookup(import_path).unwrap_or(import_path).to_string());
+        }
+        
+        if let Some(package) = caller_package.filter(|key| defines(key)) {
+            return Some(package.to_string());
+        }
+        imports.dot_imports.iter()
+            .filter_map(|import_path| self.lookup(import_path))
+            .find(|key| defines(key))
+            .map(|key| key.to_string())
+    }
+}
+
+/// Local package names of one Go file
+#[derive(Debug, Clone, Default)]
+pub struct ImportTable {
+    /// Local name -> import path
+    names: HashMap<String, String>,
+    /// Import paths of dot imports
+    dot_imports: Vec<String>,
+}
+
+impl ImportTable {
+    /// Names are taken from the alias, else the indexed package clause, else the last path segment
+    pub fn new(imports: &[ImportInfo], index: &PackageIndex) -> Self {
+        let mut table = Self::default();
+        for import in imports {
+            match import.alias.as_deref() {
+                Some(".") => table.dot_imports.push(import.module_path.clone()),
+                Some("_") => {}
+                Some(alias) => {
+                    table.names.insert(alias.to_string(), import.module_path.clone());
+                }
+                None => {
+                    let name = index.lookup(&import.module_path)
+                        .and_then(|key| index.get(key))
+                        .map(|p| p.name.clone())
+                        .filter(|name| !name.is_empty())
+                        .unwrap_or_else(|| default_name(&import.module_path));
+                    table.names.insert(name, import.module_path.clone());
+                }
+            }
+        }
+        table
+    }
+    
+    /// Import path a qualifier refers to
+    pub fn import_path(&self, name: &str) -> Option<&str> {
+        self.names.get(name).map(String::as_str)
+    }
+    
+    pub fn dot_imports(&self) -> &[String] {
+        &self.dot_imports
+    }
+}
+
+/// Helper: Conventional package name of an import path (`gopkg.in/yaml.v3` -> `yaml`, `x/y/v2` -> `y`)
+fn default_name(import_path: &str) -> String {
+    let mut segments = import_path.rsplit('/');
+    let mut last = segments.next().unwrap_or(import_path);
+    let is_major_version = |s: &str| s.len() > 1 && s.starts_with('v') && s[1..].chars().all(|c| c.is_ascii_digit());
+    if is_major_version(last) {
+        last = segments.next().unwrap_or(last);
+    }
+    let last = match last.rsplit_once('.') {
+        Some((name, version)) if is_major_version(version) => name,
+        _ => last,
+    };
+    last.replace('-', "_")
+}
+
+/// Helper: `module/relative/dir` for a directory inside a Go module
+fn import_path_of(dir: &Path, modules: &mut HashMap<PathBuf, Option<(PathBuf, String)>>) -> Option<String> {
+    let (root, module) = module_of(dir, modules)?;
+    let relative = dir.strip_prefix(&root).ok()?;
+    let mut import_path = module;
+    for component in relative.components() {
+        import_path.push('/');
+        import_path.push_str(&component.as_os_str().to_string_lossy());
+    }
+    Some(import_path)
+}
+
+/// Helper: Nearest `go.mod` at or above `dir` as (module root, module path)
+fn module_of(dir: &Path, modules: &mut HashMap<PathBuf, Option<(PathBuf, String)>>) -> Option<(PathBuf, String)> {
+    if let Some(module) = modules.get(dir) {
+        return module.clone();
+    }
+    let module = match fs::read_to_string(dir.join("go.mod")) {
+        Ok(content) => content.lines()
+            .find_map(|line| line.trim().strip_prefix("module "))
+            .map(|module| (dir.to_path_buf(), module.trim().trim_matches('"').to_string())),
+        Err(_) => dir.parent().and_then(|parent| module_of(parent, modules)),
+    };
+    modules.insert(dir.to_path_buf(), module.clone());
+    module
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, FunctionInfo, ImportType};
+    
+    fn go_file(path: &str, package: &str, functions: &[&str]) -> AnalysisResult {
+        let mut file = AnalysisResult::new(FileInfo::new(PathBuf::from(path)), Language::Go);
+        file.metadata.insert("package".to_string(), package.to_string());
+        for (i, name) in functions.iter().enumerate() {
+            let mut func = FunctionInfo::new(name.to_string());
+            func.start_line = i as u32 + 3;
+            file.functions.push(func);
+        }
+        file
+    }
+    
+    fn import(path: &str, alias: Option<&str>) -> ImportInfo {
+        let mut import = ImportInfo::new(ImportType::GoImport, path.to_string());
+        import.alias = alias.map(|a| a.to_string());
+        import
+    }
+    
+    fn qualified(object: &str, name: &str) -> FunctionCall {
+        let mut call = FunctionCall::new(name.to_string(), 1);
+        call.object_name = Some(object.to_string());
+        call.is_method_call = true;
+        call
+    }
+    
+    #[test]
+    fn test_default_name() {
+        assert_eq!(default_name("example.com/app/proc"), "proc");
+        assert_eq!(default_name("github.com/x/y/v2"), "y");
+        assert_eq!(default_name("gopkg.in/yaml.v3"), "yaml");
+        assert_eq!(default_name("fmt"), "fmt");
+    }
+    
+    #[test]
+    fn test_resolve_call() {
+        // No go.mod on these paths: packages are keyed by directory
+        let files = vec![
+            go_file("/nonexistent/app/proc/processor.go", "processing", &["NewDataProcessor"]),
+            go_file("/nonexistent/app/util/util.go", "util", &["Clamp"]),
+            go_file("/nonexistent/app/main.go", "main", &["main", "helper"]),
+        ];
+        let index = PackageIndex::build(&files);
+        let proc_key = index.lookup("example.com/app/proc").unwrap().to_string();
+        let util_key = index.lookup("example.com/app/util").unwrap().to_string();
+        let main_key = index.package_of(Path::new("/nonexistent/app/main.go")).map(|k| k.to_string());
+        assert_eq!(index.get(&proc_key).unwrap().name, "processing");
+        
+        // The package clause name, not the directory, is the qualifier
+        let imports = ImportTable::new(&[
+            import("example.com/app/proc", None),
+            import("example.com/app/util", Some(".")),
+            import("fmt", None),
+        ], &index);
+        let resolve = |call: &FunctionCall| index.resolve_call(&imports, main_key.as_deref(), call);
+        assert_eq!(resolve(&qualified("processing", "NewDataProcessor")), Some(proc_key.clone()));
+        assert_eq!(resolve(&qualified("fmt", "Println")).as_deref(), Some("fmt"));
+        assert_eq!(resolve(&qualified("p", "Run")), None);
+        assert_eq!(resolve(&FunctionCall::new("Clamp".to_string(), 1)), Some(util_key));
+        assert_eq!(resolve(&FunctionCall::new("helper".to_string(), 1)), main_key);
+        assert_eq!(resolve(&FunctionCall::new("len".to_string(), 1)), None);
+        
+        let aliased = ImportTable::new(&[import("example.com/app/proc", Some("foo"))], &index);
+        assert_eq!(index.resolve_call(&aliased, None, &qualified("foo", "NewDataProcessor")), Some(proc_key));
+        assert_eq!(aliased.import_path("processing"), None);
+    }
+}
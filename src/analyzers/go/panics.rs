@@ -0,0 +1,78 @@
+//! `defer`, `panic` and `recover` usage of Go functions
+//!
+//! A `recover` only stops a panic of its own goroutine, so the body is split
+//! into goroutine paths: the function itself (including closures it calls or
+//! defers) and each `go func() { ... }()` literal. A path that calls `panic`
+//! but never `recover` is flagged. This is a heuristic: it does not check that
+//! `recover` runs inside a deferred function, nor follow calls into helpers.
+
+use tree_sitter::Node;
+
+use crate::core::types::{DeferInfo, FunctionInfo};
+
+/// `panic` / `recover` seen on one goroutine path
+#[derive(Default, Clone, Copy)]
+struct GoroutinePath {
+    panics: bool,
+    recovers: bool,
+}
+
+/// Record the defers of a function / method and whether it panics or recovers
+pub fn annotate_panics(func: Node, source: &str, func_info: &mut FunctionInfo) {
+    let Some(body) = func.child_by_field_name("body") else { return };
+
+    let mut paths = vec![GoroutinePath::default()];
+    collect(body, source, 0, &mut paths, &mut func_info.defers);
+
+    func_info.has_panic = paths.iter().any(|p| p.panics);
+    func_info.has_recover = paths.iter().any(|p| p.recovers);
+    func_info.unrecovered_panic = paths.iter().any(|p| p.panics && !p.recovers);
+}
+
+/// Helper: Walk a subtree, attributing builtin calls to goroutine path `path`
+fn collect(node: Node, source: &str, path: usize, paths: &mut Vec<GoroutinePath>, defers: &mut Vec<DeferInfo>) {
+    let text = |n: Node| n.utf8_text(source.as_bytes()).unwrap_or("").to_string();
+
+    match node.kind() {
+        "defer_statement" => {
+            // defer_statement wraps a call_expression
+            if let Some(call) = node.named_child(0) {
+                let function = call.child_by_field_name("function").unwrap_or(call);
+                let is_anonymous = function.kind() == "func_literal";
+                defers.push(DeferInfo {
+                    line_number: node.start_position().row as u32 + 1,
+                    expression: if is_anonymous { "func literal".to_string() } else { text(function) },
+                    is_anonymous,
+                });
+            }
+        }
+        "go_statement" => {
+            // The launched literal runs on its own goroutine; its arguments are evaluated here
+            let call = node.named_child(0);
+            let function = call.and_then(|c| c.child_by_field_name("function"));
+            if let Some(literal) = function.filter(|f| f.kind() == "func_literal") {
+                paths.push(GoroutinePath::default());
+                let goroutine = paths.len() - 1;
+                collect(literal, source, goroutine, paths, defers);
+                if let Some(arguments) = call.and_then(|c| c.child_by_field_name("arguments")) {
+                    collect(arguments, source, path, paths, defers);
+                }
+                return;
+            }
+        }
+        "call_expression" => {
+            let function = node.child_by_field_name("function");
+            match function.filter(|f| f.kind() == "identifier").map(text).as_deref() {
+                Some("panic") => paths[path].panics = true,
+                Some("recover") => paths[path].recovers = true,
+                _ => {}
+            }
+        }
+        _ => {}
+    }
+
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        collect(child, source, path, paths, defers);
+    }
+}
@@ -2,15 +2,27 @@
 //! 100x faster than PEST implementation!
 
 use anyhow::Result;
+use std::collections::HashMap;
 use tree_sitter::{Parser, Query, QueryCursor, Node};
 use async_trait::async_trait;
 
 use crate::core::types::{
-    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, 
-    Language, ComplexityInfo, ImportType
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, GoroutineInfo, ChannelOperation, ChannelOperationType,
+    TypeParameter, ParameterInfo, MemberVariable, Annotation
 };
 use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
 use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::go::build_tags::build_constraint;
+use crate::analyzers::go::errcheck::{discarded_results, link_unchecked_errors};
+use crate::analyzers::go::implements::link_implementations;
+use crate::analyzers::go::initialization::{init_functions, order_initialization, package_variables};
+use crate::analyzers::go::locals::{constructor_types, LocalTypes};
+use crate::analyzers::go::panics::annotate_panics;
+use crate::analyzers::go::assertions::type_assertions;
+use crate::analyzers::go::unused::unused_locals;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{anonymous_name, body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, nested_function_nodes, node_span, raw_hash};
 
 pub struct TreeSitterGoAnalyzer {
     parser: Parser,
@@ -55,6 +67,7 @@ impl TreeSitterGoAnalyzer {
                     "function" => {
                         func_node = Some(capture.node);
                         func_info.start_line = capture.node.start_position().row as u32 + 1;
+                        func_info.span = node_span(capture.node, source);
                         func_info.end_line = capture.node.end_position().row as u32 + 1;
                         
                         // Check if it's a method
@@ -65,6 +78,15 @@ impl TreeSitterGoAnalyzer {
                             if let Some(receiver) = capture.node.child_by_field_name("receiver") {
                                 if let Ok(receiver_text) = receiver.utf8_text(source.as_bytes()) {
                                     func_info.metadata.insert("receiver".to_string(), receiver_text.to_string());
+                                    func_info.metadata.insert("pointer_receiver".to_string(), receiver_text.contains('*').to_string());
+                                }
+                                
+                                // Receiver variable and base type: (dp *DataProcessor) -> dp, DataProcessor
+                                if let Some((receiver_name, receiver_type)) = self.parse_receiver(receiver, source) {
+                                    if !receiver_name.is_empty() {
+                                        func_info.metadata.insert("receiver_name".to_string(), receiver_name);
+                                    }
+                                    func_info.metadata.insert("receiver_type".to_string(), receiver_type);
                                 }
                             }
                         }
@@ -73,15 +95,23 @@ impl TreeSitterGoAnalyzer {
                 }
             }
             
-            // Extract parameters if we have a function node
+            // Extract parameters and complexity if we have a function node
             if let Some(node) = func_node {
-                func_info.parameters = self.extract_parameters(node, source)?;
+                self.extract_signature(node, source, &mut func_info);
+                func_info.type_params = self.extract_type_params(node, source);
+                func_info.metadata.insert("signature".to_string(), self.method_signature(node, source));
                 func_info.is_async = false; // Go doesn't have async/await
+                func_info.complexity = self.calculate_complexity(node, source);
+                func_info.body_hash = body_hash(node, source);
+                func_info.raw_hash = raw_hash(node, source);
+                func_info.clone_tokens = clone_tokens(node);
+                self.extract_concurrency(node, source, &mut func_info);
+                func_info.unused_locals = unused_locals(node, source);
+                annotate_panics(node, source, &mut func_info);
+                func_info.type_assertions = type_assertions(node, source);
+                func_info.children = self.nested_functions(node, &func_info.name, source);
             }
             
-            // Set default complexity (will be calculated separately)
-            func_info.complexity = ComplexityInfo::default();
-            
             functions.push(func_info);
         }
         
@@ -102,6 +132,10 @@ impl TreeSitterGoAnalyzer {
                 (type_spec
                   name: (type_identifier) @name
                   type: (interface_type) @interface)) @type_decl
+              (type_declaration
+                (type_spec
+                  name: (type_identifier) @name
+                  type: (_) @other)) @type_decl
             ]
         "#;
         
@@ -112,38 +146,66 @@ impl TreeSitterGoAnalyzer {
         for mat in matches {
             let mut class_info = ClassInfo::new(String::new());
             let mut type_node = None;
-            let mut is_interface = false;
+            let mut struct_node = None;
+            let mut interface_node = None;
+            let mut skip = false;
             
             for capture in mat.captures {
                 match query.capture_names()[capture.index as usize].as_ref() {
                     "name" => {
                         class_info.name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                        
+                        // Type parameters live on the enclosing type_spec
+                        if let Some(spec) = capture.node.parent() {
+                            class_info.type_params = self.extract_type_params(spec, source);
+                        }
                     }
                     "struct" => {
                         class_info.metadata.insert("type".to_string(), "struct".to_string());
+                        struct_node = Some(capture.node);
                     }
                     "interface" => {
                         class_info.metadata.insert("type".to_string(), "interface".to_string());
-                        is_interface = true;
+                        interface_node = Some(capture.node);
+                    }
+                    "other" => {
+                        // Structs/interfaces are matched above; other types only when generic
+                        if matches!(capture.node.kind(), "struct_type" | "interface_type") {
+                            skip = true;
+                        } else {
+                            class_info.metadata.insert("type".to_string(), "type".to_string());
+                            class_info.metadata.insert("underlying_type".to_string(), capture.node.utf8_text(source.as_bytes())?.to_string());
+                        }
                     }
                     "type_decl" => {
                         type_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                     }
                     _ => {}
                 }
             }
             
+            // `type Set[T comparable] map[T]struct{}` is recorded, plain aliases are not
+            if skip || (class_info.metadata.get("type").map(|t| t.as_str()) == Some("type") && class_info.type_params.is_empty()) {
+                continue;
+            }
+            
             // Extract methods for this type (methods with matching receiver)
             if let Some(_node) = type_node {
                 class_info.methods = self.extract_methods_for_type(&class_info.name, tree, source)?;
                 
                 // For interfaces, extract method signatures
-                if is_interface {
-                    if let Some(interface_methods) = self.extract_interface_methods(&class_info.name, tree, source)? {
-                        class_info.methods.extend(interface_methods);
-                    }
+                if let Some(interface) = interface_node {
+                    let (interface_methods, embedded) = self.extract_interface_methods(interface, source)?;
+                    class_info.methods.extend(interface_methods);
+                    class_info.embeds = embedded;
+                }
+                
+                if let Some(structure) = struct_node {
+                    class_info.embeds = self.extract_struct_embeds(structure, source)?;
+                    class_info.member_variables = self.extract_struct_fields(structure, source)?;
                 }
             }
             
@@ -161,11 +223,11 @@ impl TreeSitterGoAnalyzer {
             [
               (import_declaration
                 (import_spec
-                  path: (interpreted_string_literal) @path)) @import
+                  path: (interpreted_string_literal) @path) @spec) @import
               (import_declaration
                 (import_spec_list
                   (import_spec
-                    path: (interpreted_string_literal) @path))) @import
+                    path: (interpreted_string_literal) @path) @spec)) @import
             ]
         "#;
         
@@ -183,6 +245,12 @@ impl TreeSitterGoAnalyzer {
                         // Remove quotes
                         import_info.module_path = path_text.trim_matches('"').to_string();
                     }
+                    "spec" => {
+                        // `import foo "real/pkg"`, dot (`.`) and blank (`_`) imports
+                        if let Some(name) = capture.node.child_by_field_name("name") {
+                            import_info.alias = Some(name.utf8_text(source.as_bytes())?.to_string());
+                        }
+                    }
                     "import" => {
                         import_info.line_number = capture.node.start_position().row as u32 + 1;
                     }
@@ -196,47 +264,272 @@ impl TreeSitterGoAnalyzer {
         Ok(imports)
     }
     
-    /// Helper: Extract parameters from a function node
-    fn extract_parameters(&self, node: Node, source: &str) -> Result<Vec<String>> {
-        let mut params = Vec::new();
+    /// Extract function and method calls using tree-sitter query
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        
+        let query_str = r#"
+            [
+              (call_expression
+                function: (identifier) @function) @call
+              (call_expression
+                function: (selector_expression
+                  operand: (_) @object
+                  field: (field_identifier) @function)) @call
+            ]
+        "#;
         
-        if let Some(param_list) = node.child_by_field_name("parameters") {
+        let query = Query::new(&tree_sitter_go::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        // Receiver variable types, inferred once per enclosing function
+        let constructors = constructor_types(tree.root_node(), source);
+        let mut locals: HashMap<usize, LocalTypes> = HashMap::new();
+        
+        for mat in matches {
+            let mut function_name = String::new();
+            let mut object_name = None;
+            let mut object_node = None;
+            let mut call_node = None;
+            let mut line_number = 0;
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "function" => {
+                        function_name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    "object" => {
+                        object_name = Some(capture.node.utf8_text(source.as_bytes())?.to_string());
+                        object_node = Some(capture.node);
+                    }
+                    "call" => {
+                        line_number = capture.node.start_position().row as u32 + 1;
+                        call_node = Some(capture.node);
+                    }
+                    _ => {}
+                }
+            }
+            
+            if !function_name.is_empty() {
+                let mut function_call = FunctionCall::new(function_name, line_number);
+                // obj.Method() / pkg.Func()
+                if object_name.is_some() {
+                    function_call.object_name = object_name;
+                    function_call.is_method_call = true;
+                }
+                // processor.ProcessData() with processor := NewDataProcessor()
+                if let Some(object) = object_node.filter(|n| n.kind() == "identifier") {
+                    if let Some(func) = Self::enclosing_function(object) {
+                        let scope = locals.entry(func.id())
+                            .or_insert_with(|| LocalTypes::infer(func, source, &constructors));
+                        let variable = object.utf8_text(source.as_bytes())?;
+                        function_call.receiver_type = scope.type_of(variable, object.start_byte()).map(|t| t.to_string());
+                    }
+                }
+                // store.Save(x) / v, _ := Load(); resolved against the callee's results later
+                function_call.discarded_results = call_node.and_then(|call| discarded_results(call, source));
+                function_calls.push(function_call);
+            }
+        }
+        
+        Ok(function_calls)
+    }
+    
+    /// Helper: Name declared by the file's package clause
+    fn package_name(root: Node, source: &str) -> Option<String> {
+        let mut cursor = root.walk();
+        let clause = root.children(&mut cursor).find(|n| n.kind() == "package_clause")?;
+        let mut cursor = clause.walk();
+        let name = clause.named_children(&mut cursor).find(|n| n.kind() == "package_identifier")?;
+        name.utf8_text(source.as_bytes()).ok().map(|s| s.to_string())
+    }
+    
+    /// Helper: Outermost function / method declaration containing `node`
+    fn enclosing_function(node: Node) -> Option<Node> {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if matches!(parent.kind(), "function_declaration" | "method_declaration") {
+                return Some(parent);
+            }
+            current = parent.parent();
+        }
+        None
+    }
+    
+    /// Helper: Split a method receiver into (variable name, base type name)
+    fn parse_receiver(&self, receiver: Node, source: &str) -> Option<(String, String)> {
+        let mut cursor = receiver.walk();
+        let param = receiver.children(&mut cursor)
+            .find(|child| child.kind() == "parameter_declaration")?;
+        
+        let name = param.child_by_field_name("name")
+            .and_then(|n| n.utf8_text(source.as_bytes()).ok())
+            .unwrap_or("")
+            .to_string();
+        
+        // *T, T, *T[K] -> T
+        let type_text = param.child_by_field_name("type")?.utf8_text(source.as_bytes()).ok()?;
+        let base_type = type_text.trim_start_matches('*')
+            .split('[')
+            .next()
+            .unwrap_or("")
+            .trim()
+            .to_string();
+        
+        Some((name, base_type))
+    }
+    
+    /// Helper: Extract type parameters from a function_declaration or type_spec
+    fn extract_type_params(&self, node: Node, source: &str) -> Vec<TypeParameter> {
+        let mut type_params = Vec::new();
+        
+        let mut cursor = node.walk();
+        let param_list = node.child_by_field_name("type_parameters")
+            .or_else(|| node.children(&mut cursor).find(|child| child.kind() == "type_parameter_list"));
+        
+        if let Some(param_list) = param_list {
             let mut cursor = param_list.walk();
-            for child in param_list.children(&mut cursor) {
-                if child.kind() == "parameter_declaration" {
-                    let mut param_names = Vec::new();
-                    let mut param_type = String::new();
-                    
-                    let mut param_cursor = child.walk();
-                    for param_child in child.children(&mut param_cursor) {
-                        match param_child.kind() {
-                            "identifier" => {
-                                if let Ok(name) = param_child.utf8_text(source.as_bytes()) {
-                                    param_names.push(name.to_string());
-                                }
-                            }
-                            "type_identifier" | "pointer_type" | "slice_type" => {
-                                if let Ok(type_text) = param_child.utf8_text(source.as_bytes()) {
-                                    param_type = type_text.to_string();
-                                }
-                            }
-                            _ => {}
-                        }
+            for decl in param_list.named_children(&mut cursor) {
+                if !matches!(decl.kind(), "type_parameter_declaration" | "parameter_declaration") {
+                    continue;
+                }
+                
+                let constraint = decl.child_by_field_name("type")
+                    .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+                    .unwrap_or("")
+                    .to_string();
+                
+                // [K, V any] declares several parameters sharing one constraint
+                let mut name_cursor = decl.walk();
+                for name in decl.children_by_field_name("name", &mut name_cursor) {
+                    if let Ok(name) = name.utf8_text(source.as_bytes()) {
+                        type_params.push(TypeParameter {
+                            name: name.to_string(),
+                            constraint: constraint.clone(),
+                        });
                     }
-                    
-                    // Go allows multiple parameters of the same type: func(a, b int)
-                    if param_names.is_empty() {
-                        params.push(param_type);
-                    } else {
-                        for name in param_names {
-                            params.push(format!("{} {}", name, param_type));
-                        }
+                }
+            }
+        }
+        
+        type_params
+    }
+    
+    /// Helper: Fill in parameters and results of a function, method or interface method
+    fn extract_signature(&self, node: Node, source: &str, func: &mut FunctionInfo) {
+        func.params = node.child_by_field_name("parameters")
+            .map(|params| Self::parameter_list(params, source))
+            .unwrap_or_default();
+        // Go allows multiple parameters of the same type: func(a, b int)
+        func.parameters = func.params.iter()
+            .map(|param| if param.name.is_empty() { param.param_type.clone() } else { format!("{} {}", param.name, param.param_type) })
+            .collect();
+        
+        let (returns, return_names) = Self::result_types(node, source);
+        func.returns = returns;
+        func.return_names = return_names;
+    }
+    
+    /// Calculate cyclomatic complexity for a function or method declaration
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+        
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = node.child_by_field_name("body") {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::GO);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::GO);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::GO);
+        
+        complexity.update_rating();
+        complexity
+    }
+    
+    /// Helper: Function literals of a function body as `Parent$funcN` children, recursively
+    fn nested_functions(&self, node: Node, parent: &str, source: &str) -> Vec<FunctionInfo> {
+        nested_function_nodes(node, &["func_literal"]).into_iter()
+            .enumerate()
+            .map(|(index, literal)| {
+                let mut func_info = FunctionInfo::new(anonymous_name(parent, index + 1));
+                func_info.nested = true;
+                func_info.start_line = literal.start_position().row as u32 + 1;
+                func_info.span = node_span(literal, source);
+                func_info.end_line = literal.end_position().row as u32 + 1;
+                self.extract_signature(literal, source, &mut func_info);
+                func_info.complexity = self.calculate_complexity(literal, source);
+                func_info.children = self.nested_functions(literal, &func_info.name, source);
+                func_info
+            })
+            .collect()
+    }
+    
+    /// Collect goroutine launches and channel operations in a function body
+    fn extract_concurrency(&self, node: Node, source: &str, func_info: &mut FunctionInfo) {
+        if let Some(body) = node.child_by_field_name("body") {
+            self.collect_concurrency(body, source, func_info, false);
+        }
+    }
+    
+    /// Helper: Recursive walker for extract_concurrency
+    fn collect_concurrency(&self, node: Node, source: &str, func_info: &mut FunctionInfo, in_select: bool) {
+        let text = |n: Node| n.utf8_text(source.as_bytes()).unwrap_or("").to_string();
+        let line = node.start_position().row as u32 + 1;
+        let mut channel_op = |operation, channel: String| {
+            func_info.channels.push(ChannelOperation { operation, channel, line_number: line, in_select });
+        };
+        
+        match node.kind() {
+            "go_statement" => {
+                // go_statement wraps a call_expression
+                if let Some(call) = node.named_child(0) {
+                    let function = call.child_by_field_name("function").unwrap_or(call);
+                    let is_anonymous = function.kind() == "func_literal";
+                    func_info.goroutines.push(GoroutineInfo {
+                        line_number: line,
+                        expression: if is_anonymous { "func literal".to_string() } else { text(function) },
+                        is_anonymous,
+                    });
+                }
+            }
+            "send_statement" => {
+                if let Some(channel) = node.child_by_field_name("channel") {
+                    channel_op(ChannelOperationType::Send, text(channel));
+                }
+            }
+            "unary_expression" => {
+                let is_receive = node.child_by_field_name("operator")
+                    .map_or(false, |op| text(op) == "<-");
+                if is_receive {
+                    if let Some(operand) = node.child_by_field_name("operand") {
+                        channel_op(ChannelOperationType::Receive, text(operand));
                     }
                 }
             }
+            "call_expression" => {
+                let function = node.child_by_field_name("function").map(text).unwrap_or_default();
+                let first_arg = node.child_by_field_name("arguments").and_then(|args| args.named_child(0));
+                match (function.as_str(), first_arg) {
+                    ("close", Some(arg)) => channel_op(ChannelOperationType::Close, text(arg)),
+                    ("make", Some(arg)) if arg.kind() == "channel_type" => {
+                        channel_op(ChannelOperationType::Make, text(arg))
+                    }
+                    _ => {}
+                }
+            }
+            _ => {}
         }
         
-        Ok(params)
+        // Only the communication clause of a select case is "in select", not its body
+        let mut cursor = node.walk();
+        for child in node.children(&mut cursor) {
+            let child_in_select = match node.kind() {
+                "communication_case" => node.child_by_field_name("communication") == Some(child),
+                _ => in_select,
+            };
+            self.collect_concurrency(child, source, func_info, child_in_select);
+        }
     }
     
     /// Extract methods for a specific type by scanning all method declarations
@@ -272,6 +565,7 @@ impl TreeSitterGoAnalyzer {
                     "method" => {
                         method_node = Some(capture.node);
                         method.start_line = capture.node.start_position().row as u32 + 1;
+                        method.span = node_span(capture.node, source);
                         method.end_line = capture.node.end_position().row as u32 + 1;
                     }
                     _ => {}
@@ -281,7 +575,14 @@ impl TreeSitterGoAnalyzer {
             // Only include methods that belong to this type
             if receiver_type == type_name {
                 if let Some(node) = method_node {
-                    method.parameters = self.extract_parameters(node, source)?;
+                    self.extract_signature(node, source, &mut method);
+                    method.complexity = self.calculate_complexity(node, source);
+                    method.body_hash = body_hash(node, source);
+                    method.raw_hash = raw_hash(node, source);
+                    method.clone_tokens = clone_tokens(node);
+                    self.extract_concurrency(node, source, &mut method);
+                    method.unused_locals = unused_locals(node, source);
+                    annotate_panics(node, source, &mut method);
                     method.metadata.insert("is_method".to_string(), "true".to_string());
                     method.metadata.insert("receiver_type".to_string(), receiver_type);
                 }
@@ -292,11 +593,192 @@ impl TreeSitterGoAnalyzer {
         Ok(methods)
     }
     
-    /// Extract interface method signatures
-    fn extract_interface_methods(&self, _interface_name: &str, _tree: &tree_sitter::Tree, _source: &str) -> Result<Option<Vec<FunctionInfo>>> {
-        // For now, return empty - interface method extraction is complex
-        // This would require parsing the interface body and extracting method signatures
-        Ok(Some(Vec::new()))
+    /// Extract interface method signatures and embedded interface names
+    fn extract_interface_methods(&self, interface: Node, source: &str) -> Result<(Vec<FunctionInfo>, Vec<String>)> {
+        let mut methods = Vec::new();
+        let mut embedded = Vec::new();
+        
+        let mut cursor = interface.walk();
+        for child in interface.named_children(&mut cursor) {
+            match child.kind() {
+                "method_elem" | "method_spec" => {
+                    let name = match child.child_by_field_name("name") {
+                        Some(name) => name.utf8_text(source.as_bytes())?.to_string(),
+                        None => continue,
+                    };
+                    
+                    let mut method = FunctionInfo::new(name);
+                    method.start_line = child.start_position().row as u32 + 1;
+                    method.span = node_span(child, source);
+                    method.end_line = child.end_position().row as u32 + 1;
+                    self.extract_signature(child, source, &mut method);
+                    method.metadata.insert("is_interface_method".to_string(), "true".to_string());
+                    method.metadata.insert("signature".to_string(), self.method_signature(child, source));
+                    methods.push(method);
+                }
+                // Anonymous embedded interface (`interface{ Close() error }`): its methods are required directly
+                "type_elem" if child.named_child_count() == 1 && child.named_child(0).map_or(false, |c| c.kind() == "interface_type") => {
+                    if let Some(inner) = child.named_child(0) {
+                        let (inner_methods, inner_embedded) = self.extract_interface_methods(inner, source)?;
+                        methods.extend(inner_methods);
+                        embedded.extend(inner_embedded);
+                    }
+                }
+                // Embedded interfaces (`io.Reader`, `Stringer`); constraint unions are skipped
+                "type_elem" | "constraint_elem" | "interface_type_name" | "type_identifier" | "qualified_type" => {
+                    let text = child.utf8_text(source.as_bytes())?.trim();
+                    if !text.contains('|') && !text.contains('~') {
+                        embedded.push(text.to_string());
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        Ok((methods, embedded))
+    }
+    
+    /// Extract embedded struct fields (`Base`, `*Base`, `io.Reader`, `List[T]`)
+    fn extract_struct_embeds(&self, structure: Node, source: &str) -> Result<Vec<String>> {
+        let mut embeds = Vec::new();
+        
+        let mut cursor = structure.walk();
+        let Some(fields) = structure.named_children(&mut cursor).find(|c| c.kind() == "field_declaration_list") else {
+            return Ok(embeds);
+        };
+        
+        let mut field_cursor = fields.walk();
+        for field in fields.named_children(&mut field_cursor).filter(|f| f.kind() == "field_declaration") {
+            // Embedded fields have a type but no name
+            if field.child_by_field_name("name").is_some() {
+                continue;
+            }
+            let Some(field_type) = field.child_by_field_name("type") else { continue };
+            
+            let mut child_cursor = field.walk();
+            let pointer = field.children(&mut child_cursor).any(|c| c.kind() == "*");
+            let name = field_type.utf8_text(source.as_bytes())?.trim();
+            embeds.push(if pointer { format!("*{}", name) } else { name.to_string() });
+        }
+        
+        Ok(embeds)
+    }
+    
+    /// Extract named struct fields with their tags (`Name string `json:"name"``), one per name
+    fn extract_struct_fields(&self, structure: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut members = Vec::new();
+        
+        let mut cursor = structure.walk();
+        let Some(fields) = structure.named_children(&mut cursor).find(|c| c.kind() == "field_declaration_list") else {
+            return Ok(members);
+        };
+        
+        let mut field_cursor = fields.walk();
+        for field in fields.named_children(&mut field_cursor).filter(|f| f.kind() == "field_declaration") {
+            let line_number = field.start_position().row as u32 + 1;
+            let var_type = field.child_by_field_name("type")
+                .map(|t| t.utf8_text(source.as_bytes()))
+                .transpose()?
+                .unwrap_or("")
+                .to_string();
+            let tags = match field.child_by_field_name("tag") {
+                Some(tag) => struct_tags(tag.utf8_text(source.as_bytes())?, line_number),
+                None => Vec::new(),
+            };
+            
+            // `X, Y int` declares two fields; embedded fields have no name
+            let mut name_cursor = field.walk();
+            for name in field.children_by_field_name("name", &mut name_cursor) {
+                let name = name.utf8_text(source.as_bytes())?.to_string();
+                let mut member = MemberVariable::new(name, var_type.clone(), line_number);
+                member.span = node_span(field, source);
+                member.access_modifier = if member.name.starts_with(char::is_uppercase) { "public" } else { "private" }.to_string();
+                member.annotations = tags.clone();
+                members.push(member);
+            }
+        }
+        
+        Ok(members)
+    }
+    
+    /// Helper: Normalized signature `(string, ...int) (bool, error)` without parameter names
+    fn method_signature(&self, node: Node, source: &str) -> String {
+        let params: Vec<String> = node.child_by_field_name("parameters")
+            .map(|params| Self::parameter_list(params, source))
+            .unwrap_or_default()
+            .into_iter()
+            .map(|param| param.param_type)
+            .collect();
+        let mut signature = format!("({})", params.join(", "));
+        
+        let (results, _) = Self::result_types(node, source);
+        match results.len() {
+            0 => {}
+            1 => signature.push_str(&format!(" {}", results[0])),
+            _ => signature.push_str(&format!(" ({})", results.join(", "))),
+        }
+        
+        signature
+    }
+    
+    /// Helper: Declared parameters of a parameter list, one entry per name
+    fn parameter_list(list: Node, source: &str) -> Vec<ParameterInfo> {
+        let mut params = Vec::new();
+        
+        let mut cursor = list.walk();
+        for param in list.named_children(&mut cursor) {
+            let variadic = match param.kind() {
+                "parameter_declaration" => false,
+                "variadic_parameter_declaration" => true,
+                _ => continue,
+            };
+            
+            let type_text = param.child_by_field_name("type")
+                .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+                .unwrap_or("");
+            let param_type = format!("{}{}", if variadic { "..." } else { "" }, Self::normalize_type(type_text));
+            
+            let mut name_cursor = param.walk();
+            let names: Vec<String> = param.children_by_field_name("name", &mut name_cursor)
+                .filter_map(|name| name.utf8_text(source.as_bytes()).ok())
+                .map(|name| name.to_string())
+                .collect();
+            
+            if names.is_empty() {
+                params.push(ParameterInfo { name: String::new(), param_type, variadic });
+            } else {
+                for name in names {
+                    params.push(ParameterInfo { name, param_type: param_type.clone(), variadic });
+                }
+            }
+        }
+        
+        params
+    }
+    
+    /// Helper: Result types of a signature and the result names when they are named
+    fn result_types(node: Node, source: &str) -> (Vec<String>, Vec<String>) {
+        let Some(result) = node.child_by_field_name("result") else {
+            return (Vec::new(), Vec::new());
+        };
+        
+        if result.kind() != "parameter_list" {
+            let single = Self::normalize_type(result.utf8_text(source.as_bytes()).unwrap_or(""));
+            return (vec![single], Vec::new());
+        }
+        
+        // Results are either all named or all unnamed
+        let results = Self::parameter_list(result, source);
+        let names = results.iter()
+            .filter(|r| !r.name.is_empty())
+            .map(|r| r.name.clone())
+            .collect();
+        (results.into_iter().map(|r| r.param_type).collect(), names)
+    }
+    
+    /// Helper: Collapse whitespace inside a type expression
+    fn normalize_type(type_text: &str) -> String {
+        type_text.split_whitespace().collect::<Vec<_>>().join(" ")
     }
     
     /// Build AST from tree-sitter CST
@@ -353,6 +835,44 @@ impl TreeSitterGoAnalyzer {
     }
 }
 
+/// Helper: `key:"value"` pairs of a struct tag literal, in order (the `reflect.StructTag` convention)
+///
+/// Parsing stops at the first malformed pair, as `reflect` lookups do.
+fn struct_tags(literal: &str, line_number: u32) -> Vec<Annotation> {
+    let unquote = |quote: char| literal.strip_prefix(quote).and_then(|t| t.strip_suffix(quote));
+    let tag = match unquote('`') {
+        Some(raw) => raw.to_string(),
+        // "json:\"name\"" as an interpreted string
+        None => unquote('"').unwrap_or(literal).replace("\\\"", "\""),
+    };
+    
+    let mut tags = Vec::new();
+    let mut rest = tag.trim_start();
+    while let Some((key, after)) = rest.split_once(':') {
+        let Some(quoted) = after.strip_prefix('"') else { break };
+        if key.is_empty() || key.contains(char::is_whitespace) {
+            break;
+        }
+        let mut end = None;
+        let mut escaped = false;
+        for (i, c) in quoted.char_indices() {
+            match c {
+                _ if escaped => escaped = false,
+                '\\' => escaped = true,
+                '"' => {
+                    end = Some(i);
+                    break;
+                }
+                _ => {}
+            }
+        }
+        let Some(end) = end else { break };
+        tags.push(Annotation::new(key, &quoted[..end], line_number));
+        rest = quoted[end + 1..].trim_start();
+    }
+    tags
+}
+
 #[async_trait]
 impl LanguageAnalyzer for TreeSitterGoAnalyzer {
     fn get_language(&self) -> Language {
@@ -409,6 +929,7 @@ impl LanguageAnalyzer for TreeSitterGoAnalyzer {
         result.functions = self.extract_functions(&tree, content)?;
         result.classes = self.extract_classes(&tree, content)?;
         result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content)?;
         let extract_duration = extract_start.elapsed();
         
         if std::env::var("NEKOCODE_DEBUG").is_ok() {
@@ -428,8 +949,28 @@ impl LanguageAnalyzer for TreeSitterGoAnalyzer {
             eprintln!("⚡ [TREE-SITTER GO] AST build took: {:.3}ms", ast_duration.as_secs_f64() * 1000.0);
         }
         
-        // Update statistics
-        result.update_statistics();
+        // Interfaces satisfied within this file (the directory pass links across files)
+        link_implementations(std::slice::from_mut(&mut result));
+        
+        // Package clause name (`package proc`), resolving package-qualified calls
+        if let Some(package) = Self::package_name(tree.root_node(), content) {
+            result.metadata.insert("package".to_string(), package);
+        }
+        
+        // Ignored errors of callees in this file (the directory pass resolves across files)
+        link_unchecked_errors(std::slice::from_mut(&mut result));
+        
+        // Package-level variables and `init` functions, ordered within this file (the directory pass orders the package)
+        result.variables = package_variables(tree.root_node(), content, &result);
+        result.init_functions = init_functions(&result);
+        order_initialization(std::slice::from_mut(&mut result));
+        
+        // `//go:build` / `// +build` constraint of the file
+        if let Some(constraint) = build_constraint(content) {
+            result.metadata.insert("build_tags".to_string(), constraint);
+        }
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::GO);
         
         Ok(result)
     }
@@ -0,0 +1,260 @@
+//! Unused locals and parameters in Go functions
+//!
+//! Declarations are resolved through Go's block scopes: the function body,
+//! every `{}` block, the implicit scopes of `if` / `for` / `switch` / `select`
+//! headers and each case clause. A name counts as used when an identifier
+//! resolves to its innermost visible declaration and is read; plain writes
+//! (`x = v`, `x += v`, `x++`) do not count, matching the compiler's
+//! "declared and not used" rule. Because resolution is scoped, a used inner
+//! `x` never hides an unused outer `x`.
+//!
+//! Parameters of the function and of its closures are reported as well.
+//! Receivers and named results are tracked for shadowing only, the blank
+//! identifier `_` is never declared.
+
+use tree_sitter::Node;
+
+use crate::core::types::UnusedLocal;
+
+/// Declared name and whether it has been read
+struct Binding {
+    name: String,
+    line: u32,
+    used: bool,
+    /// Receivers, named results and constants only take part in shadowing
+    reported: bool,
+}
+
+/// Bindings of one function, with the stack of open scopes
+#[derive(Default)]
+struct Scopes {
+    bindings: Vec<Binding>,
+    /// Indices into `bindings`, innermost scope last
+    stack: Vec<Vec<usize>>,
+}
+
+/// Locals and parameters of a function / method that are never read, in declaration order
+pub fn unused_locals(func: Node, source: &str) -> Vec<UnusedLocal> {
+    // Declarations without a body (assembly stubs) have nothing to check
+    let Some(body) = func.child_by_field_name("body") else { return Vec::new() };
+
+    let mut scopes = Scopes::default();
+    scopes.push();
+    if let Some(receiver) = func.child_by_field_name("receiver") {
+        scopes.declare_parameters(receiver, source, false);
+    }
+    scopes.visit_function(func, body, source);
+    scopes.pop();
+
+    scopes.bindings.into_iter()
+        .filter(|b| b.reported && !b.used)
+        .map(|b| UnusedLocal { name: b.name, line: b.line })
+        .collect()
+}
+
+impl Scopes {
+    fn push(&mut self) {
+        self.stack.push(Vec::new());
+    }
+
+    fn pop(&mut self) {
+        self.stack.pop();
+    }
+
+    /// Parameters, named results and the body share the function's outermost scope
+    fn visit_function(&mut self, func: Node, body: Node, source: &str) {
+        if let Some(parameters) = func.child_by_field_name("parameters") {
+            self.declare_parameters(parameters, source, true);
+        }
+        if let Some(result) = func.child_by_field_name("result") {
+            if result.kind() == "parameter_list" {
+                self.declare_parameters(result, source, false);
+            }
+        }
+        self.visit_children(body, source);
+    }
+
+    /// Helper: Walk a subtree in source order, opening scopes and declaring names
+    fn visit(&mut self, node: Node, source: &str) {
+        match node.kind() {
+            "identifier" => self.read(node, source),
+            "block" | "if_statement" | "for_statement" | "expression_switch_statement"
+            | "select_statement" | "expression_case" | "type_case" | "default_case" | "communication_case" => {
+                self.push();
+                self.visit_children(node, source);
+                self.pop();
+            }
+            "func_literal" => {
+                self.push();
+                if let Some(body) = node.child_by_field_name("body") {
+                    self.visit_function(node, body, source);
+                }
+                self.pop();
+            }
+            "type_switch_statement" => {
+                // switch init; v := x.(type) { ... }: `v` is visible in every clause
+                self.push();
+                self.visit_field(node, "initializer", source);
+                self.visit_field(node, "value", source);
+                if let Some(alias) = node.child_by_field_name("alias") {
+                    self.declare_list(alias, source, true);
+                }
+                let mut cursor = node.walk();
+                for clause in node.named_children(&mut cursor).filter(|c| matches!(c.kind(), "type_case" | "default_case")) {
+                    self.visit(clause, source);
+                }
+                self.pop();
+            }
+            "short_var_declaration" => {
+                // The right side is evaluated before the new names come into scope
+                self.visit_field(node, "right", source);
+                if let Some(left) = node.child_by_field_name("left") {
+                    self.declare_list(left, source, true);
+                }
+            }
+            "range_clause" | "receive_statement" => {
+                self.visit_field(node, "right", source);
+                if let Some(left) = node.child_by_field_name("left") {
+                    if has_token(node, ":=") {
+                        self.declare_list(left, source, true);
+                    } else {
+                        self.write_list(left, source);
+                    }
+                }
+            }
+            "var_spec" | "const_spec" => {
+                self.visit_field(node, "type", source);
+                self.visit_field(node, "value", source);
+                let reported = node.kind() == "var_spec";
+                let mut cursor = node.walk();
+                let names: Vec<Node> = node.children_by_field_name("name", &mut cursor).collect();
+                for name in names {
+                    self.declare(name, source, reported);
+                }
+            }
+            "assignment_statement" => {
+                self.visit_field(node, "right", source);
+                if let Some(left) = node.child_by_field_name("left") {
+                    self.write_list(left, source);
+                }
+            }
+            "inc_statement" | "dec_statement" => {
+                if let Some(operand) = node.named_child(0) {
+                    self.write(operand, source);
+                }
+            }
+            // Parameter names of function types and local type declarations are not variables
+            "function_type" | "type_declaration" => {}
+            _ => self.visit_children(node, source),
+        }
+    }
+
+    /// Helper: Visit every child of `node` in the current scope
+    fn visit_children(&mut self, node: Node, source: &str) {
+        let mut cursor = node.walk();
+        for child in node.children(&mut cursor) {
+            self.visit(child, source);
+        }
+    }
+
+    /// Helper: Visit a field's child when present
+    fn visit_field(&mut self, node: Node, field: &str, source: &str) {
+        if let Some(child) = node.child_by_field_name(field) {
+            self.visit(child, source);
+        }
+    }
+
+    /// Helper: Declare the names of a parameter / result / receiver list
+    fn declare_parameters(&mut self, list: Node, source: &str, reported: bool) {
+        let mut cursor = list.walk();
+        for parameter in list.named_children(&mut cursor) {
+            if !matches!(parameter.kind(), "parameter_declaration" | "variadic_parameter_declaration") {
+                continue;
+            }
+            let mut names = parameter.walk();
+            let names: Vec<Node> = parameter.children_by_field_name("name", &mut names).collect();
+            for name in names {
+                self.declare(name, source, reported);
+            }
+        }
+    }
+
+    /// Helper: Declare the identifiers of `a, b :=`; names already in this scope are only reassigned
+    fn declare_list(&mut self, list: Node, source: &str, reported: bool) {
+        let mut cursor = list.walk();
+        for name in list.named_children(&mut cursor) {
+            if name.kind() != "identifier" {
+                self.visit(name, source);
+                continue;
+            }
+            let declared_here = name.utf8_text(source.as_bytes()).map_or(false, |text| {
+                self.stack.last().map_or(false, |scope| scope.iter().any(|&i| self.bindings[i].name == text))
+            });
+            if !declared_here {
+                self.declare(name, source, reported);
+            }
+        }
+    }
+
+    /// Helper: Add a binding to the innermost scope
+    fn declare(&mut self, name: Node, source: &str, reported: bool) {
+        let Ok(text) = name.utf8_text(source.as_bytes()) else { return };
+        if text == "_" {
+            return;
+        }
+        self.bindings.push(Binding {
+            name: text.to_string(),
+            line: name.start_position().row as u32 + 1,
+            used: false,
+            reported,
+        });
+        let index = self.bindings.len() - 1;
+        if let Some(scope) = self.stack.last_mut() {
+            scope.push(index);
+        }
+    }
+
+    /// Helper: Mark the innermost visible binding of an identifier as read
+    fn read(&mut self, identifier: Node, source: &str) {
+        let Ok(text) = identifier.utf8_text(source.as_bytes()) else { return };
+        let found = self.stack.iter().rev()
+            .find_map(|scope| scope.iter().rev().copied().find(|&i| self.bindings[i].name == text));
+        if let Some(index) = found {
+            self.bindings[index].used = true;
+        }
+    }
+
+    /// Helper: Assignment targets: a bare identifier is written, anything else (`p.x`, `a[i]`) reads its operands
+    fn write(&mut self, target: Node, source: &str) {
+        let target = unparenthesize(target);
+        if target.kind() != "identifier" {
+            self.visit(target, source);
+        }
+    }
+
+    /// Helper: `write` for each element of an expression list
+    fn write_list(&mut self, list: Node, source: &str) {
+        let mut cursor = list.walk();
+        for target in list.named_children(&mut cursor) {
+            self.write(target, source);
+        }
+    }
+}
+
+/// Helper: Strip `(x)` around an assignment target
+fn unparenthesize(mut node: Node) -> Node {
+    while node.kind() == "parenthesized_expression" {
+        match node.named_child(0) {
+            Some(inner) => node = inner,
+            None => break,
+        }
+    }
+    node
+}
+
+/// Helper: Whether `node` has an anonymous `token` child (`:=` vs `=` in range / receive)
+fn has_token(node: Node, token: &str) -> bool {
+    let mut cursor = node.walk();
+    let found = node.children(&mut cursor).any(|c| !c.is_named() && c.kind() == token);
+    found
+}
@@ -0,0 +1,699 @@
+//! 🚀 Tree-sitter based Java analyzer
+//! Classes, interfaces, enums and records (nested ones as `Outer.Inner`),
+//! methods with modifiers and annotations, imports and call references
+
+use anyhow::Result;
+use tree_sitter::{Parser, Query, QueryCursor, Node};
+use async_trait::async_trait;
+use std::collections::HashMap;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable, Annotation
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Declarations that introduce a type (and a level of `Outer.Inner` nesting)
+const TYPE_DECLARATIONS: &[&str] = &[
+    "class_declaration", "interface_declaration", "enum_declaration",
+    "record_declaration", "annotation_type_declaration",
+];
+
+pub struct TreeSitterJavaAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterJavaAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_java::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Java language: {:?}", e))?;
+        
+        Ok(Self { parser })
+    }
+    
+    /// Extract methods and constructors using tree-sitter query
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+        
+        let query_str = r#"
+            [
+              (method_declaration
+                name: (identifier) @name) @method
+              (constructor_declaration
+                name: (identifier) @name) @constructor
+              (compact_constructor_declaration
+                name: (identifier) @name) @constructor
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_java::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut func_node = None;
+            let mut name = String::new();
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "name" => {
+                        name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    "method" | "constructor" => {
+                        func_node = Some(capture.node);
+                    }
+                    _ => {}
+                }
+            }
+            
+            if let Some(node) = func_node {
+                let mut func_info = self.build_method_info(node, name, source)?;
+                if let Some(owner) = self.enclosing_type_name(node, source) {
+                    func_info.metadata.insert("is_method".to_string(), "true".to_string());
+                    func_info.metadata.insert("class_name".to_string(), owner);
+                }
+                functions.push(func_info);
+            }
+        }
+        
+        Ok(functions)
+    }
+    
+    /// Extract classes, interfaces, enums and records
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+        
+        let query_str = r#"
+            [
+              (class_declaration
+                name: (identifier) @name) @class
+              (interface_declaration
+                name: (identifier) @name) @interface
+              (enum_declaration
+                name: (identifier) @name) @enum
+              (record_declaration
+                name: (identifier) @name) @record
+              (annotation_type_declaration
+                name: (identifier) @name) @annotation
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_java::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut class_info = ClassInfo::new(String::new());
+            let mut class_node = None;
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "name" => {
+                        class_info.name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    kind @ ("class" | "interface" | "enum" | "record" | "annotation") => {
+                        class_node = Some(capture.node);
+                        class_info.metadata.insert("type".to_string(), kind.to_string());
+                        class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
+                        class_info.end_line = capture.node.end_position().row as u32 + 1;
+                    }
+                    _ => {}
+                }
+            }
+            
+            if let Some(node) = class_node {
+                // Nested and inner types: Outer.Inner
+                if let Some(outer) = self.enclosing_type_name(node, source) {
+                    class_info.metadata.insert("outer_class".to_string(), outer.clone());
+                    class_info.metadata.insert("simple_name".to_string(), class_info.name.clone());
+                    class_info.name = format!("{}.{}", outer, class_info.name);
+                    // Non-static classes nested in a class hold a reference to the outer instance
+                    let modifiers = self.extract_modifiers(node, source);
+                    if node.kind() == "class_declaration" && !modifiers.has("static") {
+                        class_info.metadata.insert("is_inner".to_string(), "true".to_string());
+                    }
+                }
+                
+                // class A extends B implements C, D / interface A extends B, C
+                if let Some(superclass) = node.child_by_field_name("superclass") {
+                    if let Some(parent) = superclass.named_child(0) {
+                        class_info.parent_class = Some(parent.utf8_text(source.as_bytes())?.to_string());
+                    }
+                }
+                let mut interfaces = Vec::new();
+                let mut cursor = node.walk();
+                for child in node.children(&mut cursor) {
+                    if matches!(child.kind(), "super_interfaces" | "extends_interfaces") {
+                        interfaces.extend(self.type_list(child, source));
+                    }
+                }
+                if !interfaces.is_empty() {
+                    class_info.metadata.insert("interfaces".to_string(), interfaces.join(", "));
+                }
+                
+                // record Point(int x, int y)
+                if node.kind() == "record_declaration" {
+                    if let Some(components) = node.child_by_field_name("parameters") {
+                        for component in self.extract_formal_parameters(components, source) {
+                            let (var_type, name) = component.rsplit_once(' ').unwrap_or(("", component.as_str()));
+                            let mut member = MemberVariable::new(name.to_string(), var_type.to_string(), node.start_position().row as u32 + 1);
+                            member.span = node_span(node, source);
+                            member.access_modifier = "private".to_string();
+                            member.is_const = true;
+                            member.metadata.insert("record_component".to_string(), "true".to_string());
+                            class_info.member_variables.push(member);
+                        }
+                    }
+                }
+                
+                class_info.metadata.extend(self.declaration_metadata(node, source));
+                class_info.annotations = self.extract_modifiers(node, source).annotations;
+                
+                if let Some(body) = node.child_by_field_name("body") {
+                    self.extract_class_body(body, source, &mut class_info)?;
+                }
+            }
+            
+            classes.push(class_info);
+        }
+        
+        Ok(classes)
+    }
+    
+    /// Helper: Methods and fields declared directly in a type body
+    fn extract_class_body(&self, body: Node, source: &str, class_info: &mut ClassInfo) -> Result<()> {
+        let is_interface = class_info.metadata.get("type").map_or(false, |t| t == "interface");
+        
+        let mut cursor = body.walk();
+        for child in body.children(&mut cursor) {
+            match child.kind() {
+                "method_declaration" | "constructor_declaration" | "compact_constructor_declaration" => {
+                    let name = child.child_by_field_name("name")
+                        .map(|n| n.utf8_text(source.as_bytes()))
+                        .transpose()?
+                        .unwrap_or("")
+                        .to_string();
+                    let mut method = self.build_method_info(child, name, source)?;
+                    method.metadata.insert("is_method".to_string(), "true".to_string());
+                    if is_interface {
+                        method.metadata.insert("is_interface_method".to_string(), "true".to_string());
+                    }
+                    class_info.methods.push(method);
+                }
+                "field_declaration" | "constant_declaration" => {
+                    class_info.member_variables.extend(self.extract_fields(child, source, is_interface)?);
+                }
+                // enum Color { RED, GREEN; void m() {} }
+                "enum_body_declarations" => {
+                    self.extract_class_body(child, source, class_info)?;
+                }
+                "enum_constant" => {
+                    if let Some(name) = child.child_by_field_name("name") {
+                        let mut member = MemberVariable::new(
+                            name.utf8_text(source.as_bytes())?.to_string(),
+                            class_info.name.clone(),
+                            child.start_position().row as u32 + 1,
+                        );
+                        member.span = node_span(child, source);
+                        member.access_modifier = "public".to_string();
+                        member.is_static = true;
+                        member.is_const = true;
+                        member.metadata.insert("enum_constant".to_string(), "true".to_string());
+                        class_info.member_variables.push(member);
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Member variables of a field declaration (`private int a, b;`)
+    fn extract_fields(&self, node: Node, source: &str, in_interface: bool) -> Result<Vec<MemberVariable>> {
+        let mut fields = Vec::new();
+        let modifiers = self.extract_modifiers(node, source);
+        let var_type = node.child_by_field_name("type")
+            .map(|t| t.utf8_text(source.as_bytes()))
+            .transpose()?
+            .unwrap_or("")
+            .to_string();
+        
+        let mut cursor = node.walk();
+        for declarator in node.children_by_field_name("declarator", &mut cursor) {
+            if let Some(name) = declarator.child_by_field_name("name") {
+                let mut member = MemberVariable::new(
+                    name.utf8_text(source.as_bytes())?.to_string(),
+                    var_type.clone(),
+                    declarator.start_position().row as u32 + 1,
+                );
+                member.span = node_span(declarator, source);
+                // Interface fields are implicitly public static final
+                member.access_modifier = if in_interface { "public".to_string() } else { modifiers.visibility() };
+                member.is_static = in_interface || modifiers.has("static");
+                member.is_const = in_interface || modifiers.has("final");
+                if !modifiers.annotations.is_empty() {
+                    member.metadata.insert("annotations".to_string(), modifiers.annotation_names());
+                }
+                member.annotations = modifiers.annotations.clone();
+                fields.push(member);
+            }
+        }
+        
+        Ok(fields)
+    }
+    
+    /// Extract import declarations (`import a.b.C;`, `import static a.b.C.m;`, `import a.b.*;`)
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        
+        let root = tree.root_node();
+        let mut cursor = root.walk();
+        for node in root.children(&mut cursor) {
+            if node.kind() != "import_declaration" {
+                continue;
+            }
+            
+            let mut path = String::new();
+            let mut is_static = false;
+            let mut is_wildcard = false;
+            let mut child_cursor = node.walk();
+            for child in node.children(&mut child_cursor) {
+                match child.kind() {
+                    "scoped_identifier" | "identifier" => {
+                        path = child.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    "static" => is_static = true,
+                    "asterisk" => is_wildcard = true,
+                    _ => {}
+                }
+            }
+            
+            let mut import_info = ImportInfo::new(ImportType::JavaImport, path.clone());
+            import_info.line_number = node.start_position().row as u32 + 1;
+            if is_wildcard {
+                import_info.imported_names.push("*".to_string());
+            } else if let Some((_, name)) = path.rsplit_once('.') {
+                import_info.imported_names.push(name.to_string());
+            }
+            if is_static {
+                import_info.metadata.insert("is_static".to_string(), "true".to_string());
+            }
+            imports.push(import_info);
+        }
+        
+        Ok(imports)
+    }
+    
+    /// Extract method invocations (`m()`, `this.m()`, `super.m()`, `Type.m()`, `obj.m()`)
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        
+        let query_str = r#"
+            [
+              (method_invocation
+                name: (identifier) @method) @call
+              (object_creation_expression
+                type: (_) @type) @new
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_java::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut call_node = None;
+            let mut name = String::new();
+            let mut is_constructor = false;
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "method" => {
+                        name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    "type" => {
+                        // new Outer.Inner<T>() -> Inner
+                        let type_name = capture.node.utf8_text(source.as_bytes())?;
+                        let base = type_name.split('<').next().unwrap_or(type_name);
+                        name = base.rsplit('.').next().unwrap_or(base).trim().to_string();
+                        is_constructor = true;
+                    }
+                    "call" | "new" => {
+                        call_node = Some(capture.node);
+                    }
+                    _ => {}
+                }
+            }
+            
+            let node = match call_node {
+                Some(node) if !name.is_empty() => node,
+                _ => continue,
+            };
+            
+            let mut function_call = FunctionCall::new(name, node.start_position().row as u32 + 1);
+            if !is_constructor {
+                // this.m() / super.m() / Type.m() / obj.m()
+                if let Some(object) = node.child_by_field_name("object") {
+                    function_call.object_name = Some(object.utf8_text(source.as_bytes())?.to_string());
+                    function_call.is_method_call = true;
+                }
+            }
+            
+            function_calls.push(function_call);
+        }
+        
+        Ok(function_calls)
+    }
+    
+    /// Helper: Build FunctionInfo for a method / constructor node
+    fn build_method_info(&self, node: Node, name: String, source: &str) -> Result<FunctionInfo> {
+        let mut func_info = FunctionInfo::new(name);
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        if let Some(params) = node.child_by_field_name("parameters") {
+            func_info.parameters = self.extract_formal_parameters(params, source);
+        }
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+        
+        let kind = if node.kind() == "method_declaration" { "method" } else { "constructor" };
+        func_info.metadata.insert("type".to_string(), kind.to_string());
+        if let Some(return_type) = node.child_by_field_name("type") {
+            func_info.metadata.insert("return_type".to_string(), return_type.utf8_text(source.as_bytes())?.to_string());
+        }
+        func_info.metadata.extend(self.declaration_metadata(node, source));
+        func_info.annotations = self.extract_modifiers(node, source).annotations;
+        
+        // Interface methods without a body are implicitly abstract
+        let in_interface = node.parent()
+            .and_then(|body| body.parent())
+            .map_or(false, |owner| owner.kind() == "interface_declaration");
+        if in_interface && node.child_by_field_name("body").is_none() {
+            func_info.metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+        
+        Ok(func_info)
+    }
+    
+    /// Helper: `modifiers`, `visibility`, `is_static`, `is_abstract` and `annotations` metadata
+    fn declaration_metadata(&self, node: Node, source: &str) -> HashMap<String, String> {
+        let modifiers = self.extract_modifiers(node, source);
+        let mut metadata = HashMap::new();
+        
+        if !modifiers.keywords.is_empty() {
+            metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+        }
+        metadata.insert("visibility".to_string(), modifiers.visibility());
+        if modifiers.has("static") {
+            metadata.insert("is_static".to_string(), "true".to_string());
+        }
+        if modifiers.has("abstract") {
+            metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+        if !modifiers.annotations.is_empty() {
+            metadata.insert("annotations".to_string(), modifiers.annotation_names());
+        }
+        
+        metadata
+    }
+    
+    /// Helper: Modifier keywords and annotations of a declaration
+    fn extract_modifiers(&self, node: Node, source: &str) -> Modifiers {
+        let mut modifiers = Modifiers::default();
+        
+        let mut cursor = node.walk();
+        let modifiers_node = node.children(&mut cursor).find(|child| child.kind() == "modifiers");
+        if let Some(modifiers_node) = modifiers_node {
+            let mut cursor = modifiers_node.walk();
+            for child in modifiers_node.children(&mut cursor) {
+                match child.kind() {
+                    "marker_annotation" | "annotation" => {
+                        if let Some(name) = child.child_by_field_name("name") {
+                            if let Ok(name) = name.utf8_text(source.as_bytes()) {
+                                // @RequestMapping(value = "/users", method = GET)
+                                let arguments = child.child_by_field_name("arguments")
+                                    .and_then(|arguments| arguments.utf8_text(source.as_bytes()).ok())
+                                    .unwrap_or("");
+                                modifiers.annotations.push(Annotation::new(name, arguments, child.start_position().row as u32 + 1));
+                            }
+                        }
+                    }
+                    _ => {
+                        if let Ok(keyword) = child.utf8_text(source.as_bytes()) {
+                            modifiers.keywords.push(keyword.to_string());
+                        }
+                    }
+                }
+            }
+        }
+        
+        modifiers
+    }
+    
+    /// Helper: "Type name" strings of a formal_parameters node
+    fn extract_formal_parameters(&self, params: Node, source: &str) -> Vec<String> {
+        let mut result = Vec::new();
+        
+        let mut cursor = params.walk();
+        for param in params.named_children(&mut cursor) {
+            match param.kind() {
+                "formal_parameter" => {
+                    let param_type = param.child_by_field_name("type")
+                        .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+                        .unwrap_or("");
+                    let name = param.child_by_field_name("name")
+                        .and_then(|n| n.utf8_text(source.as_bytes()).ok())
+                        .unwrap_or("");
+                    result.push(format!("{} {}", param_type, name).trim().to_string());
+                }
+                // String... args
+                "spread_parameter" => {
+                    if let Ok(text) = param.utf8_text(source.as_bytes()) {
+                        result.push(text.split_whitespace().collect::<Vec<_>>().join(" "));
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        result
+    }
+    
+    /// Helper: Type names of a super_interfaces / extends_interfaces node
+    fn type_list(&self, node: Node, source: &str) -> Vec<String> {
+        let mut names = Vec::new();
+        
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            if child.kind() == "type_list" {
+                let mut list_cursor = child.walk();
+                for item in child.named_children(&mut list_cursor) {
+                    if let Ok(name) = item.utf8_text(source.as_bytes()) {
+                        names.push(name.to_string());
+                    }
+                }
+            }
+        }
+        
+        names
+    }
+    
+    /// Helper: Name declared by the file's `package a.b;`
+    fn package_name(&self, root: Node, source: &str) -> Option<String> {
+        let mut cursor = root.walk();
+        let declaration = root.named_children(&mut cursor).find(|n| n.kind() == "package_declaration")?;
+        let mut cursor = declaration.walk();
+        let name = declaration.named_children(&mut cursor)
+            .find(|n| matches!(n.kind(), "scoped_identifier" | "identifier"))?;
+        name.utf8_text(source.as_bytes()).ok().map(str::to_string)
+    }
+    
+    /// Helper: Qualified name of the nearest enclosing type (`Outer.Inner`)
+    fn enclosing_type_name(&self, node: Node, source: &str) -> Option<String> {
+        let mut names = Vec::new();
+        
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if TYPE_DECLARATIONS.contains(&parent.kind()) {
+                if let Some(name) = parent.child_by_field_name("name") {
+                    if let Ok(name) = name.utf8_text(source.as_bytes()) {
+                        names.push(name.to_string());
+                    }
+                }
+            }
+            current = parent.parent();
+        }
+        
+        if names.is_empty() {
+            return None;
+        }
+        names.reverse();
+        Some(names.join("."))
+    }
+    
+    /// Calculate cyclomatic complexity for a method
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+        
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = node.child_by_field_name("body") {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::JAVA);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::JAVA);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::JAVA);
+        
+        complexity.update_rating();
+        complexity
+    }
+    
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+    
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let ast_type = match node.kind() {
+            "method_declaration" | "constructor_declaration" | "compact_constructor_declaration" => ASTNodeType::Function,
+            "class_declaration" | "interface_declaration" | "enum_declaration"
+            | "record_declaration" | "annotation_type_declaration" => ASTNodeType::Class,
+            "if_statement" => ASTNodeType::IfStatement,
+            "for_statement" | "enhanced_for_statement" | "while_statement" | "do_statement" => ASTNodeType::ForLoop,
+            "import_declaration" => ASTNodeType::Import,
+            "package_declaration" => ASTNodeType::Namespace,
+            "local_variable_declaration" | "field_declaration" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+        
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+            
+            // Try to get node name
+            if let Some(name_field) = node.child_by_field_name("name") {
+                if let Ok(name) = name_field.utf8_text(source.as_bytes()) {
+                    ast_node.name = name.to_string();
+                }
+            }
+            
+            parent.add_child(ast_node);
+            
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+            
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+/// Modifier keywords (`public`, `static`, ...) and annotations of a declaration
+#[derive(Default)]
+struct Modifiers {
+    keywords: Vec<String>,
+    annotations: Vec<Annotation>,
+}
+
+impl Modifiers {
+    fn has(&self, keyword: &str) -> bool {
+        self.keywords.iter().any(|k| k == keyword)
+    }
+    
+    /// Comma-separated annotation names (the `annotations` metadata)
+    fn annotation_names(&self) -> String {
+        self.annotations.iter().map(|a| a.name.as_str()).collect::<Vec<_>>().join(",")
+    }
+    
+    /// public / protected / private, or package-private when none is given
+    fn visibility(&self) -> String {
+        ["public", "protected", "private"].iter()
+            .find(|v| self.has(v))
+            .map_or("package", |v| *v)
+            .to_string()
+    }
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterJavaAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Java
+    }
+    
+    fn get_language_name(&self) -> &'static str {
+        "Java (Tree-sitter)"
+    }
+    
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".java"]
+    }
+    
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+        
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Java);
+        
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Java file"))?;
+        let parse_duration = parse_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER JAVA] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Extract all constructs
+        let extract_start = std::time::Instant::now();
+        result.functions = self.extract_functions(&tree, content)?;
+        result.classes = self.extract_classes(&tree, content)?;
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content)?;
+        if let Some(package) = self.package_name(tree.root_node(), content) {
+            result.metadata.insert("package".to_string(), package);
+        }
+        let extract_duration = extract_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER JAVA] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::JAVA);
+        
+        Ok(result)
+    }
+}
@@ -11,6 +11,14 @@ use crate::core::types::{
 };
 use crate::core::ast::{ASTBuilder, ASTNode, ASTNodeType, ASTStatistics};
 use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{anonymous_name, body_hash, clone_tokens, cognitive, cognitive_complexity, is_nested, magic_numbers, max_nesting_depth, nested_function_nodes, node_span, raw_hash};
+
+/// Function-like nodes; one inside another's body is a nested function
+const FUNCTION_KINDS: &[&str] = &[
+    "function_declaration", "function_expression", "arrow_function", "method_definition",
+    "generator_function_declaration", "generator_function",
+];
 
 pub struct TreeSitterJavaScriptAnalyzer {
     parser: Parser,
@@ -80,12 +88,18 @@ impl TreeSitterJavaScriptAnalyzer {
                     "function" => {
                         func_node = Some(capture.node);
                         func_info.start_line = capture.node.start_position().row as u32 + 1;
+                        func_info.span = node_span(capture.node, source);
                         func_info.end_line = capture.node.end_position().row as u32 + 1;
                     }
                     _ => {}
                 }
             }
             
+            // Closures and local functions are listed under their enclosing function
+            if func_node.map_or(false, |node| is_nested(node, FUNCTION_KINDS)) {
+                continue;
+            }
+            
             // Extract parameters if we have a function node
             if let Some(node) = func_node {
                 func_info.parameters = self.extract_parameters(node, source)?;
@@ -95,6 +109,14 @@ impl TreeSitterJavaScriptAnalyzer {
             
             // Set default complexity (will be calculated separately)
             func_info.complexity = ComplexityInfo::default();
+            if let Some(node) = func_node {
+                func_info.complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::JAVASCRIPT);
+                func_info.complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::JAVASCRIPT);
+                func_info.body_hash = body_hash(node, source);
+                func_info.raw_hash = raw_hash(node, source);
+                func_info.clone_tokens = clone_tokens(node);
+                func_info.children = self.nested_functions(node, &func_info.name, source)?;
+            }
             
             functions.push(func_info);
         }
@@ -125,6 +147,7 @@ impl TreeSitterJavaScriptAnalyzer {
                     }
                     "class" => {
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         
                         // Extract methods
@@ -322,6 +345,39 @@ impl TreeSitterJavaScriptAnalyzer {
         Ok(params)
     }
     
+    /// Helper: Functions defined in a function body as children, recursively
+    ///
+    /// Anonymous ones (arrow functions, `function () {}`) are named `Parent$funcN`.
+    fn nested_functions(&self, node: Node, parent: &str, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut children = Vec::new();
+        let mut anonymous = 0;
+        
+        for child in nested_function_nodes(node, FUNCTION_KINDS) {
+            let name = match child.child_by_field_name("name") {
+                Some(name) => name.utf8_text(source.as_bytes())?.to_string(),
+                None => {
+                    anonymous += 1;
+                    anonymous_name(parent, anonymous)
+                }
+            };
+            
+            let mut func_info = FunctionInfo::new(name);
+            func_info.nested = true;
+            func_info.start_line = child.start_position().row as u32 + 1;
+            func_info.span = node_span(child, source);
+            func_info.end_line = child.end_position().row as u32 + 1;
+            func_info.parameters = self.extract_parameters(child, source)?;
+            func_info.is_async = self.is_async_function(child, source);
+            func_info.is_arrow_function = child.kind() == "arrow_function";
+            func_info.complexity.cognitive_complexity = cognitive_complexity(child, source, &cognitive::JAVASCRIPT);
+            func_info.complexity.max_nesting_depth = max_nesting_depth(child, &cognitive::JAVASCRIPT);
+            func_info.children = self.nested_functions(child, &func_info.name, source)?;
+            children.push(func_info);
+        }
+        
+        Ok(children)
+    }
+    
     /// Helper: Check if function is async
     fn is_async_function(&self, node: Node, source: &str) -> bool {
         // Check if there's an async keyword before the function
@@ -354,6 +410,7 @@ impl TreeSitterJavaScriptAnalyzer {
                     }
                     
                     method.start_line = child.start_position().row as u32 + 1;
+                    method.span = node_span(child, source);
                     method.end_line = child.end_position().row as u32 + 1;
                     method.parameters = self.extract_parameters(child, source)?;
                     method.is_async = self.is_async_function(child, source);
@@ -511,8 +568,7 @@ impl LanguageAnalyzer for TreeSitterJavaScriptAnalyzer {
             eprintln!("⚡ [TREE-SITTER] AST build took: {:.3}ms", ast_duration.as_secs_f64() * 1000.0);
         }
         
-        // Update statistics
-        result.update_statistics();
+        finish_analysis(&tree, content, &mut result, &magic_numbers::JAVASCRIPT);
         
         Ok(result)
     }
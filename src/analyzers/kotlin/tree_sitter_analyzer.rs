@@ -0,0 +1,802 @@
+//! 🚀 Tree-sitter based Kotlin analyzer
+//! Classes, interfaces, objects, data and enum classes (nested ones as `Outer.Inner`),
+//! top-level, member and extension functions, `val` / `var` properties, imports
+//! and calls (including trailing-lambda calls like `items.forEach { ... }`)
+
+use anyhow::Result;
+use tree_sitter::{Parser, Query, QueryCursor, Node};
+use async_trait::async_trait;
+use std::collections::HashMap;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::tree_sitter_util::has_keyword;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Declarations that introduce a type (and a level of `Outer.Inner` nesting)
+const TYPE_DECLARATIONS: &[&str] = &["class_declaration", "object_declaration"];
+
+/// Node kinds a receiver, parameter or return type can have
+const TYPE_KINDS: &[&str] = &[
+    "user_type", "nullable_type", "function_type", "parenthesized_type", "non_nullable_type",
+];
+
+pub struct TreeSitterKotlinAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterKotlinAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_kotlin::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Kotlin language: {:?}", e))?;
+        
+        Ok(Self { parser })
+    }
+    
+    /// Extract top-level, member, extension and local functions using tree-sitter query
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+        
+        let query_str = r#"
+            (function_declaration) @function
+        "#;
+        
+        let query = Query::new(&tree_sitter_kotlin::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            for capture in mat.captures {
+                let node = capture.node;
+                let name = match self.child_text(node, "simple_identifier", source) {
+                    Some(name) => name,
+                    None => continue,
+                };
+                
+                let mut func_info = self.build_function_info(node, name, source)?;
+                if let Some(owner) = self.enclosing_type_name(node, source) {
+                    func_info.metadata.insert("is_method".to_string(), "true".to_string());
+                    func_info.metadata.insert("class_name".to_string(), owner);
+                }
+                if Self::in_companion(node) {
+                    func_info.metadata.insert("is_static".to_string(), "true".to_string());
+                    func_info.metadata.insert("is_companion".to_string(), "true".to_string());
+                }
+                functions.push(func_info);
+            }
+        }
+        
+        Ok(functions)
+    }
+    
+    /// Extract classes, interfaces, enum / data classes and object declarations
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+        
+        let query_str = r#"
+            [
+              (class_declaration) @class
+              (object_declaration) @object
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_kotlin::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            for capture in mat.captures {
+                let node = capture.node;
+                let name = match self.child_text(node, "type_identifier", source) {
+                    Some(name) => name,
+                    None => continue,
+                };
+                
+                let mut class_info = ClassInfo::new(name);
+                class_info.start_line = node.start_position().row as u32 + 1;
+                class_info.span = node_span(node, source);
+                class_info.end_line = node.end_position().row as u32 + 1;
+                
+                let modifiers = self.extract_modifiers(node, source);
+                let kind = if node.kind() == "object_declaration" {
+                    "object"
+                } else if has_keyword(node, "interface") {
+                    "interface"
+                } else if modifiers.has("enum") {
+                    "enum"
+                } else {
+                    "class"
+                };
+                class_info.metadata.insert("type".to_string(), kind.to_string());
+                if modifiers.has("data") {
+                    class_info.metadata.insert("is_data".to_string(), "true".to_string());
+                }
+                if modifiers.has("sealed") {
+                    class_info.metadata.insert("is_sealed".to_string(), "true".to_string());
+                }
+                
+                // Nested and inner types: Outer.Inner
+                if let Some(outer) = self.enclosing_type_name(node, source) {
+                    class_info.metadata.insert("outer_class".to_string(), outer.clone());
+                    class_info.metadata.insert("simple_name".to_string(), class_info.name.clone());
+                    class_info.name = format!("{}.{}", outer, class_info.name);
+                    if modifiers.has("inner") {
+                        class_info.metadata.insert("is_inner".to_string(), "true".to_string());
+                    }
+                }
+                
+                self.extract_supertypes(node, kind == "interface", source, &mut class_info)?;
+                class_info.metadata.extend(self.declaration_metadata(node, source));
+                
+                // class Point(val x: Int, var y: Int)
+                let mut cursor = node.walk();
+                let constructor = node.children(&mut cursor).find(|c| c.kind() == "primary_constructor");
+                if let Some(constructor) = constructor {
+                    class_info.member_variables.extend(self.extract_constructor_properties(constructor, source)?);
+                }
+                
+                let mut cursor = node.walk();
+                let body = node.children(&mut cursor).find(|c| matches!(c.kind(), "class_body" | "enum_class_body"));
+                if let Some(body) = body {
+                    self.extract_class_body(body, source, &mut class_info, false)?;
+                }
+                
+                classes.push(class_info);
+            }
+        }
+        
+        Ok(classes)
+    }
+    
+    /// Helper: `class A : Base(), I1, I2` -> parent_class Base, interfaces "I1, I2"
+    fn extract_supertypes(&self, node: Node, is_interface: bool, source: &str, class_info: &mut ClassInfo) -> Result<()> {
+        let mut interfaces = Vec::new();
+        
+        for specifier in Self::delegation_specifiers(node) {
+            let mut cursor = specifier.walk();
+            let Some(inner) = specifier.named_children(&mut cursor).next() else { continue };
+            match inner.kind() {
+                // Only a superclass is invoked with constructor arguments
+                "constructor_invocation" if !is_interface => {
+                    if let Some(parent) = self.child_text(inner, "user_type", source) {
+                        class_info.parent_class = Some(parent);
+                    }
+                }
+                // `I by delegate`
+                "explicit_delegation" => {
+                    if let Some(name) = self.child_text(inner, "user_type", source) {
+                        interfaces.push(name);
+                    }
+                }
+                _ => {
+                    interfaces.push(inner.utf8_text(source.as_bytes())?.to_string());
+                }
+            }
+        }
+        
+        if !interfaces.is_empty() {
+            class_info.metadata.insert("interfaces".to_string(), interfaces.join(", "));
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Methods, properties, enum entries and companion members declared in a type body
+    fn extract_class_body(&self, body: Node, source: &str, class_info: &mut ClassInfo, in_companion: bool) -> Result<()> {
+        let is_interface = class_info.metadata.get("type").map_or(false, |t| t == "interface");
+        
+        let mut cursor = body.walk();
+        for child in body.named_children(&mut cursor) {
+            match child.kind() {
+                "function_declaration" => {
+                    let name = match self.child_text(child, "simple_identifier", source) {
+                        Some(name) => name,
+                        None => continue,
+                    };
+                    let mut method = self.build_function_info(child, name, source)?;
+                    method.metadata.insert("is_method".to_string(), "true".to_string());
+                    if in_companion {
+                        method.metadata.insert("is_static".to_string(), "true".to_string());
+                        method.metadata.insert("is_companion".to_string(), "true".to_string());
+                    }
+                    if is_interface {
+                        method.metadata.insert("is_interface_method".to_string(), "true".to_string());
+                    }
+                    class_info.methods.push(method);
+                }
+                "secondary_constructor" => {
+                    let mut constructor = self.build_function_info(child, "constructor".to_string(), source)?;
+                    constructor.metadata.insert("is_method".to_string(), "true".to_string());
+                    class_info.methods.push(constructor);
+                }
+                "property_declaration" => {
+                    let mut properties = self.extract_properties(child, source)?;
+                    for property in &mut properties {
+                        property.is_static |= in_companion;
+                        if is_interface {
+                            property.access_modifier = "public".to_string();
+                        }
+                    }
+                    class_info.member_variables.extend(properties);
+                }
+                // Companion members are the class's static members
+                "companion_object" => {
+                    let name = self.child_text(child, "type_identifier", source).unwrap_or_else(|| "Companion".to_string());
+                    class_info.metadata.insert("companion_object".to_string(), name);
+                    let mut companion_cursor = child.walk();
+                    let companion_body = child.children(&mut companion_cursor).find(|c| c.kind() == "class_body");
+                    if let Some(companion_body) = companion_body {
+                        self.extract_class_body(companion_body, source, class_info, true)?;
+                    }
+                }
+                "enum_entry" => {
+                    if let Some(name) = self.child_text(child, "simple_identifier", source) {
+                        let mut member = MemberVariable::new(name, class_info.name.clone(), child.start_position().row as u32 + 1);
+                        member.span = node_span(child, source);
+                        member.access_modifier = "public".to_string();
+                        member.is_static = true;
+                        member.is_const = true;
+                        member.metadata.insert("enum_constant".to_string(), "true".to_string());
+                        class_info.member_variables.push(member);
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Member variables of a `val` / `var` declaration (`val (a, b) = pair` yields two)
+    fn extract_properties(&self, node: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut properties = Vec::new();
+        let modifiers = self.extract_modifiers(node, source);
+        let binding = self.binding_kind(node, source);
+        
+        let mut declarations = Vec::new();
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            match child.kind() {
+                "variable_declaration" => declarations.push(child),
+                "multi_variable_declaration" => {
+                    let mut inner = child.walk();
+                    declarations.extend(child.named_children(&mut inner).filter(|c| c.kind() == "variable_declaration"));
+                }
+                _ => {}
+            }
+        }
+        
+        for declaration in declarations {
+            let name = match self.child_text(declaration, "simple_identifier", source) {
+                Some(name) => name,
+                None => continue,
+            };
+            let var_type = self.type_child(declaration, source).unwrap_or_default();
+            let mut member = MemberVariable::new(name, var_type, declaration.start_position().row as u32 + 1);
+            member.span = node_span(declaration, source);
+            member.access_modifier = modifiers.visibility();
+            member.is_const = binding.as_deref() == Some("val") || modifiers.has("const");
+            self.annotate_property(&mut member, &binding, &modifiers);
+            properties.push(member);
+        }
+        
+        Ok(properties)
+    }
+    
+    /// Helper: Properties declared with `val` / `var` in a primary constructor
+    fn extract_constructor_properties(&self, constructor: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut properties = Vec::new();
+        
+        let mut cursor = constructor.walk();
+        let Some(parameters) = constructor.children(&mut cursor).find(|c| c.kind() == "class_parameters") else {
+            return Ok(properties);
+        };
+        
+        let mut param_cursor = parameters.walk();
+        for parameter in parameters.named_children(&mut param_cursor).filter(|p| p.kind() == "class_parameter") {
+            // Plain constructor parameters are not properties
+            let binding = self.binding_kind(parameter, source);
+            if binding.is_none() {
+                continue;
+            }
+            let name = match self.child_text(parameter, "simple_identifier", source) {
+                Some(name) => name,
+                None => continue,
+            };
+            
+            let modifiers = self.extract_modifiers(parameter, source);
+            let var_type = self.type_child(parameter, source).unwrap_or_default();
+            let mut member = MemberVariable::new(name, var_type, parameter.start_position().row as u32 + 1);
+            member.span = node_span(parameter, source);
+            member.access_modifier = modifiers.visibility();
+            member.is_const = binding.as_deref() == Some("val");
+            member.metadata.insert("constructor_property".to_string(), "true".to_string());
+            self.annotate_property(&mut member, &binding, &modifiers);
+            properties.push(member);
+        }
+        
+        Ok(properties)
+    }
+    
+    /// Helper: `binding` (val / var) and `annotations` metadata of a property
+    fn annotate_property(&self, member: &mut MemberVariable, binding: &Option<String>, modifiers: &Modifiers) {
+        if let Some(binding) = binding {
+            member.metadata.insert("binding".to_string(), binding.clone());
+        }
+        if !modifiers.keywords.is_empty() {
+            member.metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+        }
+        if !modifiers.annotations.is_empty() {
+            member.metadata.insert("annotations".to_string(), modifiers.annotations.join(","));
+        }
+    }
+    
+    /// Helper: Names of top-level `val` / `var` declarations
+    fn extract_top_level_properties(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<String>> {
+        let mut names = Vec::new();
+        
+        let root = tree.root_node();
+        let mut cursor = root.walk();
+        for node in root.named_children(&mut cursor).filter(|n| n.kind() == "property_declaration") {
+            names.extend(self.extract_properties(node, source)?.into_iter().map(|p| p.name));
+        }
+        
+        Ok(names)
+    }
+    
+    /// Extract import headers (`import a.b.C`, `import a.b.C as D`, `import a.b.*`)
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        
+        let mut headers = Vec::new();
+        let root = tree.root_node();
+        let mut cursor = root.walk();
+        for node in root.named_children(&mut cursor) {
+            match node.kind() {
+                "import_header" => headers.push(node),
+                "import_list" => {
+                    let mut list_cursor = node.walk();
+                    headers.extend(node.named_children(&mut list_cursor).filter(|c| c.kind() == "import_header"));
+                }
+                _ => {}
+            }
+        }
+        
+        for header in headers {
+            let path = match self.child_text(header, "identifier", source) {
+                Some(path) => path,
+                None => continue,
+            };
+            
+            let mut import_info = ImportInfo::new(ImportType::KotlinImport, path.clone());
+            import_info.line_number = header.start_position().row as u32 + 1;
+            
+            let mut child_cursor = header.walk();
+            let children: Vec<Node> = header.named_children(&mut child_cursor).collect();
+            if children.iter().any(|c| c.kind() == "wildcard_import") {
+                import_info.imported_names.push("*".to_string());
+            } else {
+                let name = path.rsplit('.').next().unwrap_or(&path);
+                import_info.imported_names.push(name.to_string());
+            }
+            if let Some(alias) = children.iter().find(|c| c.kind() == "import_alias") {
+                if let Some(alias) = self.child_text(*alias, "type_identifier", source) {
+                    import_info.metadata.insert("alias".to_string(), alias);
+                }
+            }
+            imports.push(import_info);
+        }
+        
+        Ok(imports)
+    }
+    
+    /// Extract calls (`f()`, `obj.m()`, `obj?.m()`, `Type()`, and trailing-lambda `run { }` / `xs.map { }`)
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        
+        let query_str = r#"
+            (call_expression) @call
+        "#;
+        
+        let query = Query::new(&tree_sitter_kotlin::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            for capture in mat.captures {
+                let node = capture.node;
+                let Some(callee) = node.named_child(0) else { continue };
+                
+                let function_call = match callee.kind() {
+                    "simple_identifier" => {
+                        FunctionCall::new(callee.utf8_text(source.as_bytes())?.to_string(), node.start_position().row as u32 + 1)
+                    }
+                    // receiver.name / receiver?.name
+                    "navigation_expression" => {
+                        let mut callee_cursor = callee.walk();
+                        let parts: Vec<Node> = callee.named_children(&mut callee_cursor).collect();
+                        let (Some(receiver), Some(suffix)) = (parts.first(), parts.last()) else { continue };
+                        let Some(name) = self.child_text(*suffix, "simple_identifier", source) else { continue };
+                        
+                        let mut call = FunctionCall::new(name, node.start_position().row as u32 + 1);
+                        call.object_name = Some(receiver.utf8_text(source.as_bytes())?.to_string());
+                        call.is_method_call = true;
+                        call
+                    }
+                    _ => continue,
+                };
+                function_calls.push(function_call);
+            }
+        }
+        
+        Ok(function_calls)
+    }
+    
+    /// Helper: Build FunctionInfo for a function / secondary constructor node
+    fn build_function_info(&self, node: Node, name: String, source: &str) -> Result<FunctionInfo> {
+        let mut func_info = FunctionInfo::new(name);
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+        
+        let kind = if node.kind() == "secondary_constructor" { "constructor" } else { "function" };
+        func_info.metadata.insert("type".to_string(), kind.to_string());
+        
+        // fun <T> Receiver.name(params): Return
+        let mut seen_name = false;
+        let mut seen_parameters = false;
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            match child.kind() {
+                "simple_identifier" => seen_name = true,
+                "function_value_parameters" => {
+                    seen_parameters = true;
+                    func_info.parameters = self.extract_parameters(child, source);
+                }
+                kind if TYPE_KINDS.contains(&kind) => {
+                    let text = child.utf8_text(source.as_bytes())?.to_string();
+                    if !seen_name {
+                        // Extension function: the receiver type is the key fact
+                        func_info.metadata.insert("is_extension".to_string(), "true".to_string());
+                        func_info.metadata.insert("receiver_type".to_string(), text);
+                    } else if seen_parameters {
+                        func_info.metadata.insert("return_type".to_string(), text);
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        func_info.metadata.extend(self.declaration_metadata(node, source));
+        
+        // Interface functions without a body are implicitly abstract
+        let in_interface = node.parent()
+            .and_then(|body| body.parent())
+            .map_or(false, |owner| owner.kind() == "class_declaration" && has_keyword(owner, "interface"));
+        if in_interface && Self::function_body(node).is_none() {
+            func_info.metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+        
+        Ok(func_info)
+    }
+    
+    /// Helper: `modifiers`, `visibility`, `is_abstract` and `annotations` metadata
+    fn declaration_metadata(&self, node: Node, source: &str) -> HashMap<String, String> {
+        let modifiers = self.extract_modifiers(node, source);
+        let mut metadata = HashMap::new();
+        
+        if !modifiers.keywords.is_empty() {
+            metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+        }
+        metadata.insert("visibility".to_string(), modifiers.visibility());
+        if modifiers.has("abstract") {
+            metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+        if modifiers.has("suspend") {
+            metadata.insert("is_suspend".to_string(), "true".to_string());
+        }
+        if !modifiers.annotations.is_empty() {
+            metadata.insert("annotations".to_string(), modifiers.annotations.join(","));
+        }
+        
+        metadata
+    }
+    
+    /// Helper: Modifier keywords and annotation names of a declaration
+    fn extract_modifiers(&self, node: Node, source: &str) -> Modifiers {
+        let mut modifiers = Modifiers::default();
+        
+        let mut cursor = node.walk();
+        let modifiers_node = node.children(&mut cursor).find(|child| child.kind() == "modifiers");
+        if let Some(modifiers_node) = modifiers_node {
+            let mut cursor = modifiers_node.walk();
+            for child in modifiers_node.named_children(&mut cursor) {
+                let Ok(text) = child.utf8_text(source.as_bytes()) else { continue };
+                if child.kind() == "annotation" {
+                    // @Deprecated("...") / @field:Json -> Deprecated / Json
+                    let name = text.trim_start_matches('@');
+                    let name = name.rsplit(':').next().unwrap_or(name);
+                    let name = name.split('(').next().unwrap_or(name).trim();
+                    modifiers.annotations.push(name.to_string());
+                } else {
+                    modifiers.keywords.extend(text.split_whitespace().map(str::to_string));
+                }
+            }
+        }
+        
+        modifiers
+    }
+    
+    /// Helper: "name: Type" strings of a function_value_parameters node
+    fn extract_parameters(&self, params: Node, source: &str) -> Vec<String> {
+        let mut result = Vec::new();
+        
+        let mut cursor = params.walk();
+        for param in params.named_children(&mut cursor).filter(|p| p.kind() == "parameter") {
+            if let Ok(text) = param.utf8_text(source.as_bytes()) {
+                result.push(text.split_whitespace().collect::<Vec<_>>().join(" "));
+            }
+        }
+        
+        result
+    }
+    
+    /// Helper: `val` / `var` of a property or constructor parameter
+    fn binding_kind(&self, node: Node, source: &str) -> Option<String> {
+        let mut cursor = node.walk();
+        let binding = node.children(&mut cursor).find_map(|child| match child.kind() {
+            "binding_pattern_kind" => child.utf8_text(source.as_bytes()).ok().map(str::to_string),
+            "val" | "var" => Some(child.kind().to_string()),
+            _ => None,
+        });
+        binding
+    }
+    
+    /// Helper: Text of the first direct child of the given kind
+    fn child_text(&self, node: Node, kind: &str, source: &str) -> Option<String> {
+        let mut cursor = node.walk();
+        let child = node.named_children(&mut cursor).find(|c| c.kind() == kind);
+        child.and_then(|c| c.utf8_text(source.as_bytes()).ok()).map(str::to_string)
+    }
+    
+    /// Helper: Text of the first type child (`x: List<Int>` -> `List<Int>`)
+    fn type_child(&self, node: Node, source: &str) -> Option<String> {
+        let mut cursor = node.walk();
+        let child = node.named_children(&mut cursor).find(|c| TYPE_KINDS.contains(&c.kind()));
+        child.and_then(|c| c.utf8_text(source.as_bytes()).ok()).map(str::to_string)
+    }
+    
+    /// Helper: `delegation_specifier` nodes after `:` in a class / object header
+    fn delegation_specifiers(node: Node) -> Vec<Node> {
+        let mut specifiers = Vec::new();
+        
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            match child.kind() {
+                "delegation_specifier" => specifiers.push(child),
+                "delegation_specifiers" => {
+                    let mut inner = child.walk();
+                    specifiers.extend(child.named_children(&mut inner).filter(|c| c.kind() == "delegation_specifier"));
+                }
+                _ => {}
+            }
+        }
+        
+        specifiers
+    }
+    
+    /// Helper: Body of a function (`{ ... }` or `= expr`)
+    fn function_body(node: Node) -> Option<Node> {
+        let mut cursor = node.walk();
+        let body = node.named_children(&mut cursor).find(|c| c.kind() == "function_body");
+        body
+    }
+    
+    /// Helper: Whether a declaration sits directly in a companion object body
+    fn in_companion(node: Node) -> bool {
+        node.parent()
+            .filter(|body| body.kind() == "class_body")
+            .and_then(|body| body.parent())
+            .map_or(false, |owner| owner.kind() == "companion_object")
+    }
+    
+    /// Helper: Qualified name of the nearest enclosing class / object (`Outer.Inner`)
+    fn enclosing_type_name(&self, node: Node, source: &str) -> Option<String> {
+        let mut names = Vec::new();
+        
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if TYPE_DECLARATIONS.contains(&parent.kind()) {
+                if let Some(name) = self.child_text(parent, "type_identifier", source) {
+                    names.push(name);
+                }
+            }
+            current = parent.parent();
+        }
+        
+        if names.is_empty() {
+            return None;
+        }
+        names.reverse();
+        Some(names.join("."))
+    }
+    
+    /// Calculate cyclomatic complexity for a function
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+        
+        // Base complexity 1 + one per decision point in the body
+        let body = Self::function_body(node).or_else(|| {
+            // Secondary constructors keep their body in a `statements` block
+            let mut cursor = node.walk();
+            let block = node.named_children(&mut cursor).find(|c| c.kind() == "statements");
+            block
+        });
+        if let Some(body) = body {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::KOTLIN);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::KOTLIN);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::KOTLIN);
+        
+        complexity.update_rating();
+        complexity
+    }
+    
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+    
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let ast_type = match node.kind() {
+            "function_declaration" | "secondary_constructor" => ASTNodeType::Function,
+            "class_declaration" | "object_declaration" | "companion_object" => ASTNodeType::Class,
+            "if_expression" => ASTNodeType::IfStatement,
+            "for_statement" | "while_statement" | "do_while_statement" => ASTNodeType::ForLoop,
+            "import_header" => ASTNodeType::Import,
+            "package_header" => ASTNodeType::Namespace,
+            "property_declaration" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+        
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+            
+            // Kotlin declarations carry their name as a plain child, not a field
+            let name = match node.kind() {
+                "class_declaration" | "object_declaration" | "companion_object" => self.child_text(node, "type_identifier", source),
+                "function_declaration" => self.child_text(node, "simple_identifier", source),
+                "import_header" | "package_header" => self.child_text(node, "identifier", source),
+                _ => None,
+            };
+            if let Some(name) = name {
+                ast_node.name = name;
+            }
+            
+            parent.add_child(ast_node);
+            
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+            
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+/// Modifier keywords (`private`, `data`, `override`, ...) and annotation names of a declaration
+#[derive(Default)]
+struct Modifiers {
+    keywords: Vec<String>,
+    annotations: Vec<String>,
+}
+
+impl Modifiers {
+    fn has(&self, keyword: &str) -> bool {
+        self.keywords.iter().any(|k| k == keyword)
+    }
+    
+    /// private / protected / internal, or public when none is given
+    fn visibility(&self) -> String {
+        ["private", "protected", "internal"].iter()
+            .find(|v| self.has(v))
+            .map_or("public", |v| *v)
+            .to_string()
+    }
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterKotlinAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Kotlin
+    }
+    
+    fn get_language_name(&self) -> &'static str {
+        "Kotlin (Tree-sitter)"
+    }
+    
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".kt", ".kts"]
+    }
+    
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+        
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Kotlin);
+        
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Kotlin file"))?;
+        let parse_duration = parse_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER KOTLIN] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Extract all constructs
+        let extract_start = std::time::Instant::now();
+        result.functions = self.extract_functions(&tree, content)?;
+        result.classes = self.extract_classes(&tree, content)?;
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content)?;
+        
+        let properties = self.extract_top_level_properties(&tree, content)?;
+        if !properties.is_empty() {
+            result.metadata.insert("top_level_properties".to_string(), properties.join(","));
+        }
+        let root = tree.root_node();
+        let mut cursor = root.walk();
+        let package = root.named_children(&mut cursor).find(|c| c.kind() == "package_header");
+        if let Some(package) = package.and_then(|p| self.child_text(p, "identifier", content)) {
+            result.metadata.insert("package".to_string(), package);
+        }
+        let extract_duration = extract_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER KOTLIN] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::KOTLIN);
+        
+        Ok(result)
+    }
+}
@@ -0,0 +1,634 @@
+//! 🚀 Tree-sitter based Lua analyzer
+//! Functions (`function foo()`, `local function foo()`, `foo = function() end`),
+//! table functions (`function M.bar()`) and methods (`function M:baz()`, with the
+//! implicit `self`), tables used as modules, `require` imports, and
+//! `module.func()` / `obj:method()` calls.
+//!
+//! Lua has no modifiers; scope stands in for visibility: `local` functions and
+//! tables are "local", globals are "global", and functions of the table a chunk
+//! returns (`return M`) are "module".
+
+use anyhow::Result;
+use tree_sitter::{Parser, Node};
+use async_trait::async_trait;
+use std::collections::HashSet;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable, ParameterInfo
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::tree_sitter_util::has_keyword;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+pub struct TreeSitterLuaAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterLuaAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_lua::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Lua language: {:?}", e))?;
+        
+        Ok(Self { parser })
+    }
+    
+    /// Extract named functions: declarations, functions assigned to variables or
+    /// table fields, and functions in table constructors
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str, scope: &ChunkScope) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+        let mut stack = vec![tree.root_node()];
+        
+        while let Some(node) = stack.pop() {
+            match node.kind() {
+                // function foo() / local function foo() / function M.bar() / function M:baz()
+                "function_declaration" => {
+                    if let Some(name) = node.child_by_field_name("name") {
+                        let is_local = has_keyword(node, "local");
+                        functions.push(self.build_function_info(node, name, is_local, source, scope)?);
+                    }
+                }
+                // foo = function() end / local foo = function() end / M.bar = function() end
+                "assignment_statement" => {
+                    let is_local = node.parent().map_or(false, |p| p.kind() == "variable_declaration");
+                    for (target, value) in Self::assignments(node) {
+                        if value.kind() == "function_definition" {
+                            functions.push(self.build_function_info(value, target, is_local, source, scope)?);
+                        }
+                    }
+                }
+                // local M = { add = function(a, b) ... end }
+                "table_constructor" => {
+                    if let Some(owner) = self.table_name(node, source) {
+                        for (field, value) in Self::fields(node) {
+                            if value.kind() == "function_definition" {
+                                let mut func_info = self.build_function_info(value, field, false, source, scope)?;
+                                self.attach_owner(&mut func_info, &owner, false, scope);
+                                functions.push(func_info);
+                            }
+                        }
+                    }
+                }
+                _ => {}
+            }
+            
+            let mut cursor = node.walk();
+            let children: Vec<Node> = node.named_children(&mut cursor).collect();
+            stack.extend(children.into_iter().rev());
+        }
+        
+        functions.sort_by_key(|f| f.start_line);
+        Ok(functions)
+    }
+    
+    /// Extract tables used as modules: top-level tables with a constructor and
+    /// tables functions are declared on, with their functions and fields
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str, scope: &ChunkScope, functions: &[FunctionInfo]) -> Result<Vec<ClassInfo>> {
+        let mut classes: Vec<ClassInfo> = Vec::new();
+        let root = tree.root_node();
+        
+        let mut cursor = root.walk();
+        for statement in root.named_children(&mut cursor) {
+            let assignment = match statement.kind() {
+                "variable_declaration" => {
+                    let mut inner = statement.walk();
+                    let found = statement.named_children(&mut inner).find(|c| c.kind() == "assignment_statement");
+                    found
+                }
+                "assignment_statement" => Some(statement),
+                _ => None,
+            };
+            let Some(assignment) = assignment else { continue };
+            
+            for (target, value) in Self::assignments(assignment) {
+                let target_name = target.utf8_text(source.as_bytes())?.to_string();
+                match (target.kind(), value.kind()) {
+                    // local M = { version = "1.0" }
+                    (_, "table_constructor") => {
+                        let class = Self::table_entry(&mut classes, &target_name, statement, source, scope);
+                        for (field, field_value) in Self::fields(value) {
+                            if field_value.kind() == "function_definition" {
+                                continue;
+                            }
+                            let name = field.utf8_text(source.as_bytes())?.to_string();
+                            class.member_variables.push(self.build_member(field, name, field_value, source));
+                        }
+                    }
+                    // M.count = 0
+                    ("dot_index_expression", kind) if kind != "function_definition" => {
+                        let (Some(table), Some(field)) = (target.child_by_field_name("table"), target.child_by_field_name("field")) else { continue };
+                        let table = table.utf8_text(source.as_bytes())?.to_string();
+                        if !classes.iter().any(|c| c.name == table) {
+                            continue;
+                        }
+                        let class = Self::table_entry(&mut classes, &table, statement, source, scope);
+                        let name = field.utf8_text(source.as_bytes())?.to_string();
+                        if name == "__index" {
+                            class.metadata.insert("metatable".to_string(), "true".to_string());
+                        }
+                        class.member_variables.push(self.build_member(target, name, value, source));
+                    }
+                    _ => {}
+                }
+            }
+        }
+        
+        // Tables only seen through their functions (function M.bar() with M from elsewhere)
+        for func in functions {
+            let Some(owner) = func.metadata.get("class_name") else { continue };
+            if !classes.iter().any(|c| &c.name == owner) {
+                let mut class_info = ClassInfo::new(owner.clone());
+                class_info.start_line = func.start_line;
+                class_info.end_line = func.end_line;
+                class_info.span = func.span.clone();
+                Self::annotate_table(&mut class_info, scope);
+                classes.push(class_info);
+            }
+            let class = classes.iter_mut().find(|c| &c.name == owner).expect("table registered above");
+            class.end_line = class.end_line.max(func.end_line);
+            class.methods.push(func.clone());
+        }
+        
+        Ok(classes)
+    }
+    
+    /// Helper: Table entry by name, created from its declaring statement
+    fn table_entry<'c>(classes: &'c mut Vec<ClassInfo>, name: &str, statement: Node, source: &str, scope: &ChunkScope) -> &'c mut ClassInfo {
+        if let Some(index) = classes.iter().position(|c| c.name == name) {
+            let class = &mut classes[index];
+            class.end_line = class.end_line.max(statement.end_position().row as u32 + 1);
+            return class;
+        }
+        
+        let mut class_info = ClassInfo::new(name.to_string());
+        class_info.start_line = statement.start_position().row as u32 + 1;
+        class_info.end_line = statement.end_position().row as u32 + 1;
+        class_info.span = node_span(statement, source);
+        Self::annotate_table(&mut class_info, scope);
+        classes.push(class_info);
+        classes.last_mut().expect("just pushed")
+    }
+    
+    /// Helper: `type` ("module" for the returned table, else "table") and `visibility` of a table
+    fn annotate_table(class_info: &mut ClassInfo, scope: &ChunkScope) {
+        let visibility = scope.table_visibility(&class_info.name);
+        let kind = if visibility == "module" { "module" } else { "table" };
+        class_info.metadata.insert("type".to_string(), kind.to_string());
+        class_info.metadata.insert("visibility".to_string(), visibility.to_string());
+    }
+    
+    /// Helper: Table field with the type of its value (`version = "1.0"` is a string)
+    fn build_member(&self, node: Node, name: String, value: Node, source: &str) -> MemberVariable {
+        let var_type = match value.kind() {
+            "string" => "string",
+            "number" => "number",
+            "true" | "false" => "boolean",
+            "table_constructor" => "table",
+            "nil" => "nil",
+            _ => "",
+        };
+        
+        let mut member = MemberVariable::new(name, var_type.to_string(), node.start_position().row as u32 + 1);
+        member.span = node_span(node, source);
+        member.access_modifier = "public".to_string();
+        member
+    }
+    
+    /// Extract `require` calls; `local json = require("json")` names the import
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        let mut stack = vec![tree.root_node()];
+        
+        while let Some(node) = stack.pop() {
+            let mut cursor = node.walk();
+            let children: Vec<Node> = node.named_children(&mut cursor).collect();
+            stack.extend(children.into_iter().rev());
+            
+            if node.kind() != "function_call" {
+                continue;
+            }
+            let is_require = node.child_by_field_name("name")
+                .map_or(false, |name| name.kind() == "identifier" && name.utf8_text(source.as_bytes()).ok() == Some("require"));
+            if !is_require {
+                continue;
+            }
+            // require("a.b") / require "a.b"
+            let Some(module) = node.child_by_field_name("arguments").and_then(|args| Self::string_argument(args, source)) else { continue };
+            
+            let mut import_info = ImportInfo::new(ImportType::LuaRequire, module.clone());
+            import_info.line_number = node.start_position().row as u32 + 1;
+            match Self::assigned_name(node, source) {
+                Some(name) => {
+                    if name != module.rsplit('.').next().unwrap_or(&module) {
+                        import_info.alias = Some(name.clone());
+                    }
+                    import_info.imported_names.push(name);
+                }
+                None => import_info.imported_names.push(module.rsplit('.').next().unwrap_or(&module).to_string()),
+            }
+            imports.push(import_info);
+        }
+        
+        imports.sort_by_key(|import| import.line_number);
+        Ok(imports)
+    }
+    
+    /// Helper: The literal module name of a `require` argument
+    fn string_argument(arguments: Node, source: &str) -> Option<String> {
+        let string = if arguments.kind() == "string" {
+            arguments
+        } else {
+            let mut cursor = arguments.walk();
+            let first = arguments.named_children(&mut cursor).next()?;
+            if first.kind() != "string" {
+                return None;
+            }
+            first
+        };
+        
+        let text = string.utf8_text(source.as_bytes()).ok()?;
+        let text = text.trim_start_matches("[[").trim_end_matches("]]").trim_matches(|c| c == '"' || c == '\'');
+        Some(text.to_string())
+    }
+    
+    /// Helper: Variable a call's value is assigned to (`local json = require("json")`)
+    fn assigned_name(call: Node, source: &str) -> Option<String> {
+        let expressions = call.parent().filter(|p| p.kind() == "expression_list")?;
+        let assignment = expressions.parent().filter(|p| p.kind() == "assignment_statement")?;
+        Self::assignments(assignment).into_iter()
+            .find(|(_, value)| value.id() == call.id())
+            .and_then(|(target, _)| target.utf8_text(source.as_bytes()).ok())
+            .map(str::to_string)
+    }
+    
+    /// Extract calls: `f()`, `module.func()` and `obj:method()`
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str, classes: &[ClassInfo], functions: &[FunctionInfo]) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        let mut stack = vec![tree.root_node()];
+        
+        while let Some(node) = stack.pop() {
+            let mut cursor = node.walk();
+            let children: Vec<Node> = node.named_children(&mut cursor).collect();
+            stack.extend(children.into_iter().rev());
+            
+            if node.kind() != "function_call" {
+                continue;
+            }
+            let Some(name) = node.child_by_field_name("name") else { continue };
+            let line = node.start_position().row as u32 + 1;
+            
+            let function_call = match name.kind() {
+                "identifier" => FunctionCall::new(name.utf8_text(source.as_bytes())?.to_string(), line),
+                "dot_index_expression" | "method_index_expression" => {
+                    let field = if name.kind() == "dot_index_expression" { "field" } else { "method" };
+                    let (Some(table), Some(member)) = (name.child_by_field_name("table"), name.child_by_field_name(field)) else { continue };
+                    let table = table.utf8_text(source.as_bytes())?.to_string();
+                    
+                    let mut call = FunctionCall::new(member.utf8_text(source.as_bytes())?.to_string(), line);
+                    call.is_method_call = name.kind() == "method_index_expression";
+                    // self:helper() inside function M:run() targets M
+                    call.receiver_type = if table == "self" {
+                        Self::enclosing_owner(node, functions)
+                    } else if classes.iter().any(|c| c.name == table) {
+                        Some(table.clone())
+                    } else {
+                        None
+                    };
+                    call.object_name = Some(table);
+                    call
+                }
+                _ => continue,
+            };
+            function_calls.push(function_call);
+        }
+        
+        function_calls.sort_by_key(|call| call.line_number);
+        Ok(function_calls)
+    }
+    
+    /// Helper: Table of the innermost function containing a node
+    fn enclosing_owner(node: Node, functions: &[FunctionInfo]) -> Option<String> {
+        let line = node.start_position().row as u32 + 1;
+        functions.iter()
+            .filter(|f| f.start_line <= line && line <= f.end_line)
+            .min_by_key(|f| f.end_line - f.start_line)
+            .and_then(|f| f.metadata.get("class_name").cloned())
+    }
+    
+    /// Helper: Build FunctionInfo for a declaration or function definition; `name`
+    /// is the declared name node (`foo`, `M.bar`, `M:baz`)
+    fn build_function_info(&self, node: Node, name: Node, is_local: bool, source: &str, scope: &ChunkScope) -> Result<FunctionInfo> {
+        let (owner, simple_name, is_method) = match name.kind() {
+            "dot_index_expression" => (
+                name.child_by_field_name("table").map(|t| t.utf8_text(source.as_bytes())).transpose()?,
+                name.child_by_field_name("field").map(|f| f.utf8_text(source.as_bytes())).transpose()?,
+                false,
+            ),
+            "method_index_expression" => (
+                name.child_by_field_name("table").map(|t| t.utf8_text(source.as_bytes())).transpose()?,
+                name.child_by_field_name("method").map(|m| m.utf8_text(source.as_bytes())).transpose()?,
+                true,
+            ),
+            _ => (None, Some(name.utf8_text(source.as_bytes())?), false),
+        };
+        
+        let mut func_info = FunctionInfo::new(simple_name.unwrap_or_default().to_string());
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+        
+        // function M:baz(x) has an implicit first parameter `self`
+        if is_method {
+            func_info.parameters.push("self".to_string());
+            func_info.params.push(ParameterInfo { name: "self".to_string(), param_type: String::new(), variadic: false });
+            func_info.metadata.insert("implicit_self".to_string(), "true".to_string());
+        }
+        if let Some(parameters) = node.child_by_field_name("parameters") {
+            let mut cursor = parameters.walk();
+            for param in parameters.named_children(&mut cursor) {
+                let variadic = match param.kind() {
+                    "identifier" => false,
+                    "vararg_expression" => true,
+                    _ => continue,
+                };
+                let text = param.utf8_text(source.as_bytes())?.to_string();
+                func_info.parameters.push(text.clone());
+                func_info.params.push(ParameterInfo {
+                    name: if variadic { String::new() } else { text },
+                    param_type: if variadic { "...".to_string() } else { String::new() },
+                    variadic,
+                });
+            }
+        }
+        
+        match owner {
+            Some(owner) => self.attach_owner(&mut func_info, owner, is_method, scope),
+            None => {
+                let visibility = if is_local { "local" } else { "global" };
+                func_info.metadata.insert("visibility".to_string(), visibility.to_string());
+            }
+        }
+        if node.kind() == "function_definition" {
+            func_info.metadata.insert("is_assigned".to_string(), "true".to_string());
+        }
+        
+        Ok(func_info)
+    }
+    
+    /// Helper: Mark a function as belonging to a table (`M.bar`) or as its method (`M:baz`)
+    fn attach_owner(&self, func_info: &mut FunctionInfo, owner: &str, is_method: bool, scope: &ChunkScope) {
+        func_info.metadata.insert("class_name".to_string(), owner.to_string());
+        func_info.metadata.insert("visibility".to_string(), scope.table_visibility(owner).to_string());
+        if is_method {
+            func_info.metadata.insert("is_method".to_string(), "true".to_string());
+        } else {
+            // Called as M.bar(): no receiver
+            func_info.metadata.insert("is_static".to_string(), "true".to_string());
+        }
+    }
+    
+    /// Helper: Name of the variable / field a table constructor is assigned to
+    fn table_name(&self, table: Node, source: &str) -> Option<String> {
+        let expressions = table.parent().filter(|p| p.kind() == "expression_list")?;
+        let assignment = expressions.parent().filter(|p| p.kind() == "assignment_statement")?;
+        Self::assignments(assignment).into_iter()
+            .find(|(_, value)| value.id() == table.id())
+            .and_then(|(target, _)| target.utf8_text(source.as_bytes()).ok())
+            .map(str::to_string)
+    }
+    
+    /// Helper: (target, value) pairs of `a, b = x, y`
+    fn assignments(assignment: Node) -> Vec<(Node, Node)> {
+        let mut cursor = assignment.walk();
+        let lists: Vec<Node> = assignment.named_children(&mut cursor).collect();
+        let (Some(targets), Some(values)) = (
+            lists.iter().find(|n| n.kind() == "variable_list"),
+            lists.iter().find(|n| n.kind() == "expression_list"),
+        ) else {
+            return Vec::new();
+        };
+        
+        let mut target_cursor = targets.walk();
+        let targets: Vec<Node> = targets.named_children(&mut target_cursor)
+            .filter(|n| n.kind() != "attribute")
+            .collect();
+        let mut value_cursor = values.walk();
+        let values: Vec<Node> = values.named_children(&mut value_cursor).collect();
+        targets.into_iter().zip(values).collect()
+    }
+    
+    /// Helper: (name, value) pairs of a table constructor's named fields
+    fn fields(table: Node) -> Vec<(Node, Node)> {
+        let mut cursor = table.walk();
+        let fields = table.named_children(&mut cursor)
+            .filter(|n| n.kind() == "field")
+            .filter_map(|field| {
+                let name = field.child_by_field_name("name").filter(|n| n.kind() == "identifier")?;
+                Some((name, field.child_by_field_name("value")?))
+            })
+            .collect();
+        fields
+    }
+    
+    /// Calculate cyclomatic complexity for a function
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+        
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = node.child_by_field_name("body") {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::LUA);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::LUA);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::LUA);
+        
+        complexity.update_rating();
+        complexity
+    }
+    
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+    
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let ast_type = match node.kind() {
+            "function_declaration" | "function_definition" => ASTNodeType::Function,
+            "if_statement" => ASTNodeType::IfStatement,
+            "for_statement" | "while_statement" | "repeat_statement" => ASTNodeType::ForLoop,
+            "variable_declaration" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+        
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+            
+            if let Some(name) = node.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                ast_node.name = name.to_string();
+            }
+            
+            parent.add_child(ast_node);
+            
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+            
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+/// Chunk-level names that decide a table's visibility
+struct ChunkScope {
+    /// `local` variables and functions declared at the top of the chunk
+    locals: HashSet<String>,
+    /// Names the chunk returns (`return M`)
+    returned: HashSet<String>,
+}
+
+impl ChunkScope {
+    fn new(tree: &tree_sitter::Tree, source: &str) -> Self {
+        let mut locals = HashSet::new();
+        let mut returned = HashSet::new();
+        let root = tree.root_node();
+        
+        let mut cursor = root.walk();
+        for statement in root.named_children(&mut cursor) {
+            match statement.kind() {
+                "variable_declaration" => {
+                    let mut stack = vec![statement];
+                    while let Some(node) = stack.pop() {
+                        if node.kind() == "variable_list" {
+                            let mut names = node.walk();
+                            locals.extend(node.named_children(&mut names)
+                                .filter(|n| n.kind() == "identifier")
+                                .filter_map(|n| n.utf8_text(source.as_bytes()).ok())
+                                .map(str::to_string));
+                            continue;
+                        }
+                        let mut children = node.walk();
+                        stack.extend(node.named_children(&mut children).filter(|n| n.kind() != "expression_list"));
+                    }
+                }
+                "function_declaration" if has_keyword(statement, "local") => {
+                    if let Some(name) = statement.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                        locals.insert(name.to_string());
+                    }
+                }
+                "return_statement" => {
+                    let mut stack = vec![statement];
+                    while let Some(node) = stack.pop() {
+                        if node.kind() == "identifier" {
+                            if let Ok(name) = node.utf8_text(source.as_bytes()) {
+                                returned.insert(name.to_string());
+                            }
+                            continue;
+                        }
+                        if matches!(node.kind(), "return_statement" | "expression_list") {
+                            let mut children = node.walk();
+                            stack.extend(node.named_children(&mut children));
+                        }
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        Self { locals, returned }
+    }
+    
+    /// "module" for the returned table, "local" for other local tables, else "global"
+    fn table_visibility(&self, table: &str) -> &'static str {
+        // M.sub belongs to M
+        let root = table.split(|c| c == '.' || c == ':').next().unwrap_or(table);
+        if self.returned.contains(root) {
+            "module"
+        } else if self.locals.contains(root) {
+            "local"
+        } else {
+            "global"
+        }
+    }
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterLuaAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Lua
+    }
+    
+    fn get_language_name(&self) -> &'static str {
+        "Lua (Tree-sitter)"
+    }
+    
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".lua"]
+    }
+    
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+        
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Lua);
+        
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Lua file"))?;
+        let parse_duration = parse_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER LUA] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Extract all constructs; tables are collected from their functions
+        let extract_start = std::time::Instant::now();
+        let scope = ChunkScope::new(&tree, content);
+        result.functions = self.extract_functions(&tree, content, &scope)?;
+        result.classes = self.extract_classes(&tree, content, &scope, &result.functions)?;
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content, &result.classes, &result.functions)?;
+        let extract_duration = extract_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER LUA] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::LUA);
+        
+        Ok(result)
+    }
+}
@@ -1,7 +1,18 @@
 pub mod traits;
+pub mod tree_sitter_util;
+pub mod common;
 pub mod javascript;
 pub mod python;
 pub mod cpp;
 pub mod csharp;
 pub mod go;
-pub mod rust;
\ No newline at end of file
+pub mod rust;
+pub mod ruby;
+pub mod java;
+pub mod kotlin;
+pub mod swift;
+pub mod php;
+pub mod lua;
+pub mod dart;
+pub mod scala;
+pub mod zig;
\ No newline at end of file
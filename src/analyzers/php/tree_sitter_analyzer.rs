@@ -0,0 +1,948 @@
+//! 🚀 Tree-sitter based PHP analyzer
+//! Classes, interfaces, traits and enums (namespace-qualified as `App\Models\User`),
+//! functions, methods with their visibility modifiers, properties, constants, enum cases,
+//! trait `use` (reported as `embeds`), namespace `use` imports, and calls including
+//! `$this->m()`, `self::m()`, `static::m()` and `parent::m()`.
+
+use anyhow::Result;
+use tree_sitter::{Parser, Query, QueryCursor, Node};
+use async_trait::async_trait;
+use std::collections::HashMap;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable, ParameterInfo
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::tree_sitter_util::has_keyword;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Declarations that introduce a class-like type
+const TYPE_DECLARATIONS: &[&str] = &[
+    "class_declaration", "interface_declaration", "trait_declaration", "enum_declaration",
+];
+
+pub struct TreeSitterPhpAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterPhpAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_php::LANGUAGE_PHP.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set PHP language: {:?}", e))?;
+        
+        Ok(Self { parser })
+    }
+    
+    /// Extract functions and methods using tree-sitter query
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str, names: &NameResolver) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+        
+        let query_str = r#"
+            [
+              (function_definition
+                name: (name) @name) @function
+              (method_declaration
+                name: (name) @name) @method
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_php::LANGUAGE_PHP.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut name = None;
+            let mut func_node = None;
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "name" => name = Some(capture.node.utf8_text(source.as_bytes())?.to_string()),
+                    "function" | "method" => func_node = Some(capture.node),
+                    _ => {}
+                }
+            }
+            
+            let (Some(name), Some(node)) = (name, func_node) else { continue };
+            let func_info = match self.enclosing_type(node) {
+                Some(owner) => {
+                    let class_name = names.qualify(&self.text(owner, "name", source).unwrap_or_default(), names.namespace_of(owner));
+                    self.build_method_info(node, name, &class_name, source)?
+                }
+                None => {
+                    let mut func_info = self.build_function_info(node, name, source)?;
+                    if let Some(namespace) = names.namespace_of(node) {
+                        func_info.metadata.insert("qualified_name".to_string(), format!("{}\\{}", namespace, func_info.name));
+                        func_info.metadata.insert("namespace".to_string(), namespace.to_string());
+                    }
+                    func_info
+                }
+            };
+            functions.push(func_info);
+        }
+        
+        Ok(functions)
+    }
+    
+    /// Extract classes, interfaces, traits and enums
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str, names: &NameResolver) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+        
+        let query_str = r#"
+            [
+              (class_declaration) @class
+              (interface_declaration) @interface
+              (trait_declaration) @trait
+              (enum_declaration) @enum
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_php::LANGUAGE_PHP.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            for capture in mat.captures {
+                let node = capture.node;
+                let kind = query.capture_names()[capture.index as usize].to_string();
+                let Some(simple_name) = self.text(node, "name", source) else { continue };
+                let namespace = names.namespace_of(node);
+                
+                let mut class_info = ClassInfo::new(names.qualify(&simple_name, namespace));
+                class_info.start_line = node.start_position().row as u32 + 1;
+                class_info.span = node_span(node, source);
+                class_info.end_line = node.end_position().row as u32 + 1;
+                class_info.metadata.insert("type".to_string(), kind.clone());
+                if let Some(namespace) = namespace {
+                    class_info.metadata.insert("namespace".to_string(), namespace.to_string());
+                    class_info.metadata.insert("simple_name".to_string(), simple_name);
+                }
+                
+                let modifiers = self.extract_modifiers(node, source);
+                if !modifiers.keywords.is_empty() {
+                    class_info.metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+                }
+                if modifiers.has("abstract") {
+                    class_info.metadata.insert("is_abstract".to_string(), "true".to_string());
+                }
+                if modifiers.has("final") {
+                    class_info.metadata.insert("is_final".to_string(), "true".to_string());
+                }
+                if !modifiers.annotations.is_empty() {
+                    class_info.metadata.insert("annotations".to_string(), modifiers.annotations.join(","));
+                }
+                
+                self.extract_supertypes(node, &kind, source, names, &mut class_info)?;
+                
+                if let Some(body) = node.child_by_field_name("body") {
+                    self.extract_class_body(body, source, names, &mut class_info)?;
+                }
+                
+                classes.push(class_info);
+            }
+        }
+        
+        Ok(classes)
+    }
+    
+    /// Helper: `extends` / `implements` clauses and an enum's backing type, resolved against imports
+    fn extract_supertypes(&self, node: Node, kind: &str, source: &str, names: &NameResolver, class_info: &mut ClassInfo) -> Result<()> {
+        let namespace = names.namespace_of(node);
+        
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            let referenced: Vec<String> = match child.kind() {
+                "base_clause" | "class_interface_clause" => self.type_names(child, source)
+                    .iter()
+                    .map(|name| names.qualify(name, namespace))
+                    .collect(),
+                // enum Suit: string
+                "primitive_type" if kind == "enum" => {
+                    class_info.metadata.insert("backing_type".to_string(), child.utf8_text(source.as_bytes())?.to_string());
+                    continue;
+                }
+                _ => continue,
+            };
+            
+            match (child.kind(), kind) {
+                // Interfaces extend any number of interfaces
+                ("base_clause", "interface") => {
+                    class_info.metadata.insert("interfaces".to_string(), referenced.join(", "));
+                }
+                ("base_clause", _) => class_info.parent_class = referenced.into_iter().next(),
+                _ => class_info.implements.extend(referenced),
+            }
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Methods, properties, constants, enum cases and used traits of a type body
+    fn extract_class_body(&self, body: Node, source: &str, names: &NameResolver, class_info: &mut ClassInfo) -> Result<()> {
+        let namespace = names.namespace_of(body);
+        
+        let mut cursor = body.walk();
+        for child in body.named_children(&mut cursor) {
+            match child.kind() {
+                "method_declaration" => {
+                    let Some(name) = self.text(child, "name", source) else { continue };
+                    let method = self.build_method_info(child, name, &class_info.name, source)?;
+                    // `function __construct(private string $name)` declares properties
+                    if method.metadata.get("type").map_or(false, |t| t == "constructor") {
+                        class_info.member_variables.extend(self.promoted_properties(child, source)?);
+                    }
+                    class_info.methods.push(method);
+                }
+                "property_declaration" => {
+                    class_info.member_variables.extend(self.extract_properties(child, source)?);
+                }
+                "const_declaration" => {
+                    class_info.member_variables.extend(self.extract_constants(child, source)?);
+                }
+                // use LoggerTrait, Cache\Memoizes { ... }
+                "use_declaration" => {
+                    for name in self.type_names(child, source) {
+                        class_info.embeds.push(names.qualify(&name, namespace));
+                    }
+                }
+                "enum_case" => {
+                    let Some(name) = self.text(child, "name", source) else { continue };
+                    let mut case = MemberVariable::new(name, class_info.name.clone(), child.start_position().row as u32 + 1);
+                    case.span = node_span(child, source);
+                    case.access_modifier = "public".to_string();
+                    case.is_static = true;
+                    case.is_const = true;
+                    case.metadata.insert("enum_case".to_string(), "true".to_string());
+                    if let Some(value) = self.text(child, "value", source) {
+                        case.metadata.insert("raw_value".to_string(), value);
+                    }
+                    class_info.member_variables.push(case);
+                }
+                _ => {}
+            }
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Member variables of a property declaration (`public int $a = 1, $b;` yields two)
+    fn extract_properties(&self, node: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut properties = Vec::new();
+        let modifiers = self.extract_modifiers(node, source);
+        let var_type = self.text(node, "type", source).unwrap_or_default();
+        
+        let mut cursor = node.walk();
+        for element in node.named_children(&mut cursor).filter(|c| c.kind() == "property_element") {
+            let mut element_cursor = element.walk();
+            let Some(variable) = element.named_children(&mut element_cursor).find(|c| c.kind() == "variable_name") else { continue };
+            let name = variable.utf8_text(source.as_bytes())?.trim_start_matches('$').to_string();
+            
+            let mut member = MemberVariable::new(name, var_type.clone(), element.start_position().row as u32 + 1);
+            member.span = node_span(node, source);
+            member.access_modifier = modifiers.visibility();
+            member.is_static = modifiers.has("static");
+            self.annotate_member(&mut member, &modifiers);
+            properties.push(member);
+        }
+        
+        Ok(properties)
+    }
+    
+    /// Helper: Class constants (`const A = 1, B = 2;`), reported as static constant members
+    fn extract_constants(&self, node: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut constants = Vec::new();
+        let modifiers = self.extract_modifiers(node, source);
+        
+        let mut cursor = node.walk();
+        for element in node.named_children(&mut cursor).filter(|c| c.kind() == "const_element") {
+            let mut element_cursor = element.walk();
+            let Some(name) = element.named_children(&mut element_cursor).find(|c| c.kind() == "name") else { continue };
+            
+            let mut member = MemberVariable::new(name.utf8_text(source.as_bytes())?.to_string(), String::new(), element.start_position().row as u32 + 1);
+            member.span = node_span(node, source);
+            member.access_modifier = modifiers.visibility();
+            member.is_static = true;
+            member.is_const = true;
+            self.annotate_member(&mut member, &modifiers);
+            constants.push(member);
+        }
+        
+        Ok(constants)
+    }
+    
+    /// Helper: Properties declared by constructor parameters with a visibility modifier
+    fn promoted_properties(&self, node: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut properties = Vec::new();
+        let Some(parameters) = node.child_by_field_name("parameters") else { return Ok(properties) };
+        
+        let mut cursor = parameters.walk();
+        for param in parameters.named_children(&mut cursor).filter(|c| c.kind() == "property_promotion_parameter") {
+            let info = self.parameter_info(param, source)?;
+            let modifiers = self.extract_modifiers(param, source);
+            
+            let mut member = MemberVariable::new(info.name, info.param_type, param.start_position().row as u32 + 1);
+            member.span = node_span(param, source);
+            member.access_modifier = modifiers.visibility();
+            member.metadata.insert("promoted".to_string(), "true".to_string());
+            self.annotate_member(&mut member, &modifiers);
+            properties.push(member);
+        }
+        
+        Ok(properties)
+    }
+    
+    /// Helper: `modifiers`, `is_readonly` and `annotations` metadata of a member
+    fn annotate_member(&self, member: &mut MemberVariable, modifiers: &Modifiers) {
+        if !modifiers.keywords.is_empty() {
+            member.metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+        }
+        if modifiers.has("readonly") {
+            member.metadata.insert("is_readonly".to_string(), "true".to_string());
+        }
+        if !modifiers.annotations.is_empty() {
+            member.metadata.insert("annotations".to_string(), modifiers.annotations.join(","));
+        }
+    }
+    
+    /// Extract namespace `use` declarations, including grouped and `function` / `const` imports
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        let mut stack = vec![tree.root_node()];
+        
+        while let Some(node) = stack.pop() {
+            // Namespace imports live at file or namespace level only
+            if TYPE_DECLARATIONS.contains(&node.kind()) || node.kind() == "function_definition" {
+                continue;
+            }
+            if node.kind() != "namespace_use_declaration" {
+                let mut cursor = node.walk();
+                stack.extend(node.children(&mut cursor));
+                continue;
+            }
+            
+            // The clause shapes differ between grammar versions; the text does not
+            let text = node.utf8_text(source.as_bytes())?.trim().trim_end_matches(';');
+            let line_number = node.start_position().row as u32 + 1;
+            for (kind, path, alias) in parse_use_declaration(text) {
+                let mut import_info = ImportInfo::new(ImportType::PhpUse, path.clone());
+                import_info.line_number = line_number;
+                import_info.imported_names.push(alias.clone().unwrap_or_else(|| last_segment(&path).to_string()));
+                import_info.alias = alias;
+                if let Some(kind) = kind {
+                    import_info.metadata.insert("import_kind".to_string(), kind);
+                }
+                imports.push(import_info);
+            }
+        }
+        
+        imports.sort_by_key(|import| import.line_number);
+        Ok(imports)
+    }
+    
+    /// Extract calls: `f()`, `$obj->m()`, `$obj?->m()`, `Type::m()`, `self::` / `static::` /
+    /// `parent::m()` and `new Type()`
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str, names: &NameResolver, classes: &[ClassInfo]) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        
+        let query_str = r#"
+            [
+              (function_call_expression) @call
+              (member_call_expression) @member
+              (nullsafe_member_call_expression) @member
+              (scoped_call_expression) @scoped
+              (object_creation_expression) @new
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_php::LANGUAGE_PHP.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            for capture in mat.captures {
+                let node = capture.node;
+                let line = node.start_position().row as u32 + 1;
+                let namespace = names.namespace_of(node);
+                
+                let function_call = match query.capture_names()[capture.index as usize].as_ref() {
+                    "call" => {
+                        let Some(function) = node.child_by_field_name("function") else { continue };
+                        // `$callback()` has no static name
+                        if !matches!(function.kind(), "name" | "qualified_name") {
+                            continue;
+                        }
+                        let name = function.utf8_text(source.as_bytes())?;
+                        FunctionCall::new(last_segment(name).to_string(), line)
+                    }
+                    "member" => {
+                        let (Some(object), Some(name)) = (node.child_by_field_name("object"), self.text(node, "name", source)) else { continue };
+                        let object = object.utf8_text(source.as_bytes())?.to_string();
+                        
+                        let mut call = FunctionCall::new(name, line);
+                        // $this->helper() resolves to the enclosing class
+                        if object == "$this" {
+                            call.receiver_type = self.enclosing_class_name(node, source, names);
+                        }
+                        call.object_name = Some(object);
+                        call.is_method_call = true;
+                        call
+                    }
+                    "scoped" => {
+                        let (Some(scope), Some(name)) = (node.child_by_field_name("scope"), self.text(node, "name", source)) else { continue };
+                        let scope = scope.utf8_text(source.as_bytes())?.to_string();
+                        
+                        let mut call = FunctionCall::new(name, line);
+                        call.receiver_type = match scope.to_lowercase().as_str() {
+                            "self" | "static" => self.enclosing_class_name(node, source, names),
+                            "parent" => self.enclosing_class_name(node, source, names)
+                                .and_then(|class| classes.iter().find(|c| c.name == class))
+                                .and_then(|class| class.parent_class.clone()),
+                            _ if scope.starts_with('$') => None,
+                            _ => Some(names.qualify(&scope, namespace)),
+                        };
+                        call.object_name = Some(scope);
+                        call.is_method_call = true;
+                        call
+                    }
+                    "new" => {
+                        let mut class_cursor = node.walk();
+                        let Some(class) = node.named_children(&mut class_cursor).find(|c| matches!(c.kind(), "name" | "qualified_name")) else { continue };
+                        let class = class.utf8_text(source.as_bytes())?;
+                        FunctionCall::new(last_segment(class).to_string(), line)
+                    }
+                    _ => continue,
+                };
+                function_calls.push(function_call);
+            }
+        }
+        
+        function_calls.sort_by_key(|call| call.line_number);
+        Ok(function_calls)
+    }
+    
+    /// Helper: Method of a class, interface, trait or enum
+    fn build_method_info(&self, node: Node, name: String, class_name: &str, source: &str) -> Result<FunctionInfo> {
+        let mut method = self.build_function_info(node, name, source)?;
+        method.metadata.insert("is_method".to_string(), "true".to_string());
+        method.metadata.insert("class_name".to_string(), class_name.to_string());
+        
+        let kind = match method.name.to_lowercase().as_str() {
+            "__construct" => "constructor",
+            "__destruct" => "destructor",
+            _ => "method",
+        };
+        method.metadata.insert("type".to_string(), kind.to_string());
+        
+        let modifiers = self.extract_modifiers(node, source);
+        if !modifiers.keywords.is_empty() {
+            method.metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+        }
+        method.metadata.insert("visibility".to_string(), modifiers.visibility());
+        if modifiers.has("static") {
+            method.metadata.insert("is_static".to_string(), "true".to_string());
+        }
+        if modifiers.has("final") {
+            method.metadata.insert("is_final".to_string(), "true".to_string());
+        }
+        
+        let in_interface = self.enclosing_type(node).map_or(false, |owner| owner.kind() == "interface_declaration");
+        if in_interface {
+            method.metadata.insert("is_interface_method".to_string(), "true".to_string());
+        }
+        // Abstract and interface methods end in `;` instead of a body
+        if modifiers.has("abstract") || node.child_by_field_name("body").is_none() {
+            method.metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+        
+        Ok(method)
+    }
+    
+    /// Helper: Build FunctionInfo for a function or method node
+    fn build_function_info(&self, node: Node, name: String, source: &str) -> Result<FunctionInfo> {
+        let mut func_info = FunctionInfo::new(name);
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+        
+        // function name(int $id, ?string $label = null, string ...$rest): ?User
+        if let Some(parameters) = node.child_by_field_name("parameters") {
+            let mut cursor = parameters.walk();
+            for param in parameters.named_children(&mut cursor) {
+                if !matches!(param.kind(), "simple_parameter" | "variadic_parameter" | "property_promotion_parameter") {
+                    continue;
+                }
+                func_info.parameters.push(normalize(param.utf8_text(source.as_bytes())?));
+                func_info.params.push(self.parameter_info(param, source)?);
+            }
+        }
+        if let Some(return_type) = node.child_by_field_name("return_type") {
+            let return_type = normalize(return_type.utf8_text(source.as_bytes())?.trim_start_matches(':'));
+            func_info.metadata.insert("return_type".to_string(), return_type.clone());
+            func_info.returns.push(return_type);
+        }
+        if has_keyword(node, "&") || Self::has_child(node, "reference_modifier") {
+            func_info.metadata.insert("returns_reference".to_string(), "true".to_string());
+        }
+        
+        let modifiers = self.extract_modifiers(node, source);
+        if !modifiers.annotations.is_empty() {
+            func_info.metadata.insert("annotations".to_string(), modifiers.annotations.join(","));
+        }
+        
+        Ok(func_info)
+    }
+    
+    /// Helper: Name (without `$`), type and variadic flag of a parameter node
+    fn parameter_info(&self, param: Node, source: &str) -> Result<ParameterInfo> {
+        let name = match param.child_by_field_name("name") {
+            Some(name) => name.utf8_text(source.as_bytes())?.trim_start_matches('$').to_string(),
+            None => String::new(),
+        };
+        let param_type = self.text(param, "type", source).unwrap_or_default();
+        
+        let variadic = param.kind() == "variadic_parameter";
+        let param_type = if variadic { format!("...{}", param_type) } else { param_type };
+        
+        Ok(ParameterInfo { name, param_type, variadic })
+    }
+    
+    /// Helper: Modifier keywords and attribute names of a declaration
+    fn extract_modifiers(&self, node: Node, source: &str) -> Modifiers {
+        let mut modifiers = Modifiers::default();
+        
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            match child.kind() {
+                "visibility_modifier" | "static_modifier" | "abstract_modifier" | "final_modifier"
+                | "readonly_modifier" | "var_modifier" => {
+                    if let Ok(text) = child.utf8_text(source.as_bytes()) {
+                        modifiers.keywords.push(text.to_lowercase());
+                    }
+                }
+                // #[Route('/users'), Deprecated] -> Route, Deprecated
+                "attribute_list" => {
+                    let mut stack = vec![child];
+                    while let Some(node) = stack.pop() {
+                        if node.kind() == "attribute" {
+                            if let Some(name) = node.named_child(0).and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                                modifiers.annotations.push(name.to_string());
+                            }
+                            continue;
+                        }
+                        let mut attribute_cursor = node.walk();
+                        let children: Vec<Node> = node.named_children(&mut attribute_cursor).collect();
+                        stack.extend(children.into_iter().rev());
+                    }
+                }
+                _ => {}
+            }
+        }
+        
+        modifiers
+    }
+    
+    /// Helper: Names listed in an `extends` / `implements` / trait `use` clause
+    fn type_names(&self, node: Node, source: &str) -> Vec<String> {
+        let mut cursor = node.walk();
+        let names = node.named_children(&mut cursor)
+            .filter(|c| matches!(c.kind(), "name" | "qualified_name"))
+            .filter_map(|c| c.utf8_text(source.as_bytes()).ok())
+            .map(normalize)
+            .collect();
+        names
+    }
+    
+    /// Helper: Nearest enclosing class, interface, trait or enum declaration
+    fn enclosing_type<'t>(&self, node: Node<'t>) -> Option<Node<'t>> {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if TYPE_DECLARATIONS.contains(&parent.kind()) {
+                return Some(parent);
+            }
+            current = parent.parent();
+        }
+        None
+    }
+    
+    /// Helper: Qualified name of the enclosing type (`$this`, `self::` and `static::` targets)
+    fn enclosing_class_name(&self, node: Node, source: &str, names: &NameResolver) -> Option<String> {
+        let owner = self.enclosing_type(node)?;
+        let name = self.text(owner, "name", source)?;
+        Some(names.qualify(&name, names.namespace_of(owner)))
+    }
+    
+    /// Helper: Text of a field child
+    fn text(&self, node: Node, field: &str, source: &str) -> Option<String> {
+        node.child_by_field_name(field)
+            .and_then(|child| child.utf8_text(source.as_bytes()).ok())
+            .map(normalize)
+    }
+    
+    /// Helper: Whether a named child of the given kind is a direct child
+    fn has_child(node: Node, kind: &str) -> bool {
+        let mut cursor = node.walk();
+        let found = node.named_children(&mut cursor).any(|c| c.kind() == kind);
+        found
+    }
+    
+    /// Calculate cyclomatic complexity for a function
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+        
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = node.child_by_field_name("body") {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::PHP);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::PHP);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::PHP);
+        
+        complexity.update_rating();
+        complexity
+    }
+    
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+    
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let ast_type = match node.kind() {
+            "function_definition" | "method_declaration" => ASTNodeType::Function,
+            "class_declaration" | "interface_declaration" | "trait_declaration" | "enum_declaration" => ASTNodeType::Class,
+            "if_statement" => ASTNodeType::IfStatement,
+            "for_statement" | "foreach_statement" | "while_statement" | "do_statement" => ASTNodeType::ForLoop,
+            "namespace_use_declaration" => ASTNodeType::Import,
+            "property_declaration" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+        
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+            
+            if let Some(name) = self.text(node, "name", source) {
+                ast_node.name = name;
+            }
+            
+            parent.add_child(ast_node);
+            
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+            
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+/// Resolves class names the way PHP does: against the file's `use` imports, else
+/// relative to the current namespace
+struct NameResolver {
+    /// Lowercased alias (or last segment) -> fully qualified class name
+    aliases: HashMap<String, String>,
+    /// `namespace App;` statements: (start byte, name), in file order
+    statement_namespaces: Vec<(usize, String)>,
+    /// `namespace App { ... }` blocks: (byte range, name)
+    block_namespaces: Vec<(std::ops::Range<usize>, String)>,
+}
+
+impl NameResolver {
+    fn new(tree: &tree_sitter::Tree, source: &str, imports: &[ImportInfo]) -> Self {
+        let aliases = Self::aliases(imports);
+        
+        let mut statement_namespaces = Vec::new();
+        let mut block_namespaces = Vec::new();
+        let root = tree.root_node();
+        let mut cursor = root.walk();
+        for node in root.named_children(&mut cursor).filter(|n| n.kind() == "namespace_definition") {
+            let name = node.child_by_field_name("name")
+                .and_then(|n| n.utf8_text(source.as_bytes()).ok())
+                .map(normalize)
+                .unwrap_or_default();
+            if node.child_by_field_name("body").is_some() {
+                block_namespaces.push((node.byte_range(), name));
+            } else {
+                statement_namespaces.push((node.start_byte(), name));
+            }
+        }
+        
+        Self { aliases, statement_namespaces, block_namespaces }
+    }
+    
+    /// Class imports by lowercased alias; `use function` / `use const` do not name classes
+    fn aliases(imports: &[ImportInfo]) -> HashMap<String, String> {
+        imports.iter()
+            .filter(|import| !import.metadata.contains_key("import_kind"))
+            .map(|import| {
+                let alias = import.alias.clone().unwrap_or_else(|| last_segment(&import.module_path).to_string());
+                (alias.to_lowercase(), import.module_path.clone())
+            })
+            .collect()
+    }
+    
+    /// Namespace a node is declared in (`None` for the global namespace)
+    fn namespace_of(&self, node: Node) -> Option<&str> {
+        let position = node.start_byte();
+        let block = self.block_namespaces.iter()
+            .find(|(range, _)| range.contains(&position))
+            .map(|(_, name)| name.as_str());
+        let name = block.or_else(|| {
+            self.statement_namespaces.iter()
+                .rev()
+                .find(|(start, _)| *start < position)
+                .map(|(_, name)| name.as_str())
+        })?;
+        if name.is_empty() { None } else { Some(name) }
+    }
+    
+    /// Fully qualified form of a class name written in `namespace`
+    fn qualify(&self, name: &str, namespace: Option<&str>) -> String {
+        // \Fully\Qualified
+        if let Some(absolute) = name.strip_prefix('\\') {
+            return absolute.to_string();
+        }
+        
+        let (first, rest) = match name.split_once('\\') {
+            Some((first, rest)) => (first, Some(rest)),
+            None => (name, None),
+        };
+        // namespace\Relative
+        if first.eq_ignore_ascii_case("namespace") {
+            let rest = rest.unwrap_or("");
+            return namespace.map_or(rest.to_string(), |ns| format!("{}\\{}", ns, rest));
+        }
+        if let Some(imported) = self.aliases.get(&first.to_lowercase()) {
+            return match rest {
+                Some(rest) => format!("{}\\{}", imported, rest),
+                None => imported.clone(),
+            };
+        }
+        
+        match namespace {
+            Some(namespace) => format!("{}\\{}", namespace, name),
+            None => name.to_string(),
+        }
+    }
+}
+
+/// Modifier keywords (`public`, `static`, `abstract`, ...) and attribute names of a declaration
+#[derive(Default)]
+struct Modifiers {
+    keywords: Vec<String>,
+    annotations: Vec<String>,
+}
+
+impl Modifiers {
+    fn has(&self, keyword: &str) -> bool {
+        self.keywords.iter().any(|k| k == keyword)
+    }
+    
+    /// public / protected / private; members without one (and `var`) are public
+    fn visibility(&self) -> String {
+        self.keywords.iter()
+            .find(|k| matches!(k.as_str(), "public" | "protected" | "private"))
+            .map_or("public", |k| k.as_str())
+            .to_string()
+    }
+}
+
+/// Helper: Split `use` declaration text into (kind, path, alias) entries
+///
+/// Handles `use A\B, C as D`, `use function A\f`, `use const A\X` and groups
+/// `use A\{B, function c, D as E}`.
+fn parse_use_declaration(text: &str) -> Vec<(Option<String>, String, Option<String>)> {
+    let text = text.trim().strip_prefix("use").unwrap_or(text).trim();
+    let (kind, text) = split_use_kind(text);
+    
+    let (prefix, items) = match (text.find('{'), text.rfind('}')) {
+        (Some(open), Some(close)) if open < close => {
+            (Some(text[..open].trim().trim_end_matches('\\')), &text[open + 1..close])
+        }
+        _ => (None, text),
+    };
+    
+    items.split(',')
+        .map(str::trim)
+        .filter(|item| !item.is_empty())
+        .map(|item| {
+            let (item_kind, item) = split_use_kind(item);
+            let words: Vec<&str> = item.split_whitespace().collect();
+            let (path, alias) = match words.as_slice() {
+                [path, keyword, alias] if keyword.eq_ignore_ascii_case("as") => (*path, Some(alias.to_string())),
+                _ => (words.first().copied().unwrap_or(""), None),
+            };
+            let path = path.trim_start_matches('\\');
+            let path = match prefix {
+                Some(prefix) => format!("{}\\{}", prefix.trim_start_matches('\\'), path),
+                None => path.to_string(),
+            };
+            (item_kind.or_else(|| kind.clone()), path, alias)
+        })
+        .collect()
+}
+
+/// Helper: Strip a leading `function` / `const` keyword
+fn split_use_kind(text: &str) -> (Option<String>, &str) {
+    for kind in ["function", "const"] {
+        if let Some(rest) = text.strip_prefix(kind) {
+            if rest.starts_with(char::is_whitespace) {
+                return (Some(kind.to_string()), rest.trim_start());
+            }
+        }
+    }
+    (None, text)
+}
+
+/// Helper: Last segment of a backslash-separated name
+fn last_segment(name: &str) -> &str {
+    name.rsplit('\\').next().unwrap_or(name)
+}
+
+/// Helper: Collapse whitespace inside a name or type expression
+fn normalize(text: &str) -> String {
+    text.split_whitespace().collect::<Vec<_>>().join(" ")
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterPhpAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Php
+    }
+    
+    fn get_language_name(&self) -> &'static str {
+        "PHP (Tree-sitter)"
+    }
+    
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".php", ".phtml"]
+    }
+    
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+        
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Php);
+        
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse PHP file"))?;
+        let parse_duration = parse_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER PHP] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Extract all constructs; names resolve against the imports
+        let extract_start = std::time::Instant::now();
+        result.imports = self.extract_imports(&tree, content)?;
+        let names = NameResolver::new(&tree, content, &result.imports);
+        result.functions = self.extract_functions(&tree, content, &names)?;
+        result.classes = self.extract_classes(&tree, content, &names)?;
+        result.function_calls = self.extract_function_calls(&tree, content, &names, &result.classes)?;
+        let extract_duration = extract_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER PHP] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+        
+        let mut namespaces: Vec<String> = names.statement_namespaces.iter()
+            .map(|(_, name)| name.clone())
+            .chain(names.block_namespaces.iter().map(|(_, name)| name.clone()))
+            .filter(|name| !name.is_empty())
+            .collect();
+        namespaces.sort();
+        namespaces.dedup();
+        if !namespaces.is_empty() {
+            result.metadata.insert("namespaces".to_string(), namespaces.join(","));
+        }
+        
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::PHP);
+        
+        Ok(result)
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    fn entry(kind: Option<&str>, path: &str, alias: Option<&str>) -> (Option<String>, String, Option<String>) {
+        (kind.map(str::to_string), path.to_string(), alias.map(str::to_string))
+    }
+    
+    #[test]
+    fn test_parse_use_declaration() {
+        assert_eq!(parse_use_declaration("use App\\Models\\User"), vec![entry(None, "App\\Models\\User", None)]);
+        assert_eq!(
+            parse_use_declaration("use \\App\\Models\\User as U, Psr\\Log\\LoggerInterface"),
+            vec![entry(None, "App\\Models\\User", Some("U")), entry(None, "Psr\\Log\\LoggerInterface", None)]
+        );
+        assert_eq!(parse_use_declaration("use function App\\Support\\format"), vec![entry(Some("function"), "App\\Support\\format", None)]);
+        assert_eq!(
+            parse_use_declaration("use App\\Models\\{User, Post AS P, function slug, const VERSION}"),
+            vec![
+                entry(None, "App\\Models\\User", None),
+                entry(None, "App\\Models\\Post", Some("P")),
+                entry(Some("function"), "App\\Models\\slug", None),
+                entry(Some("const"), "App\\Models\\VERSION", None),
+            ]
+        );
+    }
+    
+    #[test]
+    fn test_qualify() {
+        let mut import = ImportInfo::new(ImportType::PhpUse, "App\\Contracts\\Repository".to_string());
+        import.alias = Some("Repo".to_string());
+        let mut function = ImportInfo::new(ImportType::PhpUse, "App\\Support\\Str".to_string());
+        function.metadata.insert("import_kind".to_string(), "function".to_string());
+        let names = NameResolver {
+            aliases: NameResolver::aliases(&[import, ImportInfo::new(ImportType::PhpUse, "Psr\\Log\\LoggerInterface".to_string()), function]),
+            statement_namespaces: Vec::new(),
+            block_namespaces: Vec::new(),
+        };
+        
+        assert_eq!(names.qualify("User", Some("App\\Models")), "App\\Models\\User");
+        assert_eq!(names.qualify("\\Exception", Some("App\\Models")), "Exception");
+        assert_eq!(names.qualify("repo", Some("App")), "App\\Contracts\\Repository");
+        assert_eq!(names.qualify("LoggerInterface", Some("App")), "Psr\\Log\\LoggerInterface");
+        assert_eq!(names.qualify("Repo\\Cached", None), "App\\Contracts\\Repository\\Cached");
+        assert_eq!(names.qualify("namespace\\Sub\\Thing", Some("App")), "App\\Sub\\Thing");
+        // Function imports do not alias class names
+        assert_eq!(names.qualify("Str", Some("App")), "App\\Str");
+        assert_eq!(names.qualify("Helper", None), "Helper");
+    }
+}
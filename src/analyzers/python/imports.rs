@@ -0,0 +1,91 @@
+//! Python import table and module-to-file matching
+//!
+//! `from app.utils import slugify as slug` binds the local name `slug` to
+//! `slugify` of module `app.utils`; `import app.utils as u` binds `u` to the
+//! module itself. `ImportTable` keeps those bindings for one file so calls
+//! can be attributed to the module they come from; `module_matches_path`
+//! decides whether a dotted module name denotes a given source file.
+
+use std::collections::HashMap;
+use std::path::Path;
+
+use tree_sitter::Node;
+
+/// What a local name refers to
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub enum ImportBinding {
+    /// `import pkg.mod [as m]`
+    Module(String),
+    /// `from pkg.mod import name [as local]`: module and name inside it
+    Member { module: String, name: String },
+}
+
+/// Local names bound by the imports of one file
+#[derive(Debug, Clone, Default)]
+pub struct ImportTable {
+    bindings: HashMap<String, ImportBinding>,
+}
+
+impl ImportTable {
+    /// Record the bindings of an `import_statement` / `import_from_statement`
+    pub fn add_statement(&mut self, node: Node, source: &str) {
+        let text = |n: Node| n.utf8_text(source.as_bytes()).unwrap_or("").to_string();
+        let module = node.child_by_field_name("module_name").map(text);
+
+        let mut cursor = node.walk();
+        let names: Vec<Node> = node.children_by_field_name("name", &mut cursor).collect();
+        for name in names {
+            let (imported, local) = match name.kind() {
+                "aliased_import" => {
+                    let (Some(imported), Some(alias)) = (name.child_by_field_name("name"), name.child_by_field_name("alias")) else { continue };
+                    (text(imported), text(alias))
+                }
+                _ => (text(name), text(name)),
+            };
+            let binding = match &module {
+                Some(module) => ImportBinding::Member { module: module.clone(), name: imported },
+                None => ImportBinding::Module(imported),
+            };
+            self.bindings.insert(local, binding);
+        }
+    }
+
+    /// Binding of a local name (`slug`, `u`, `app.utils`)
+    pub fn get(&self, local: &str) -> Option<&ImportBinding> {
+        self.bindings.get(local)
+    }
+}
+
+/// Whether `path` is the source of dotted module `module`
+///
+/// `app.utils` is `.../app/utils.py` or `.../app/utils/__init__.py`. Relative
+/// names (`.utils`, `..core.models`) match on their dotted tail; a bare `.`
+/// matches any package `__init__.py`.
+pub fn module_matches_path(module: &str, path: &Path) -> bool {
+    let stem = path.with_extension("");
+    let mut parts: Vec<&str> = stem.iter().filter_map(|part| part.to_str()).collect();
+    if parts.last() == Some(&"__init__") {
+        parts.pop();
+        if module.trim_start_matches('.').is_empty() {
+            return true;
+        }
+    }
+
+    let wanted: Vec<&str> = module.trim_start_matches('.').split('.').filter(|p| !p.is_empty()).collect();
+    !wanted.is_empty() && parts.ends_with(&wanted)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_module_matches_path() {
+        assert!(module_matches_path("app.utils", Path::new("/repo/app/utils.py")));
+        assert!(module_matches_path("app.utils", Path::new("/repo/app/utils/__init__.py")));
+        assert!(module_matches_path(".utils", Path::new("/repo/app/utils.py")));
+        assert!(module_matches_path(".", Path::new("/repo/app/__init__.py")));
+        assert!(!module_matches_path("app.utils", Path::new("/repo/other/utils.py")));
+        assert!(!module_matches_path("utils", Path::new("/repo/app/my_utils.py")));
+    }
+}
@@ -1,5 +1,6 @@
 pub mod analyzer;
 pub mod tree_sitter_analyzer;
+pub mod imports;
 // Grammar is embedded in analyzer.rs via pest_derive
 
 pub use analyzer::PythonAnalyzer;
@@ -6,11 +6,17 @@ use tree_sitter::{Parser, Query, QueryCursor, Node};
 use async_trait::async_trait;
 
 use crate::core::types::{
-    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, 
-    Language, ComplexityInfo, ImportType
+    AnalysisResult, ClassInfo, FileInfo, FunctionCall, FunctionInfo, ImportInfo, 
+    Language, ComplexityInfo, ImportType, Annotation
 };
+use crate::analyzers::python::imports::{ImportBinding, ImportTable};
 use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
 use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{anonymous_name, body_hash, clone_tokens, cognitive, cognitive_complexity, is_nested, magic_numbers, max_nesting_depth, nested_function_nodes, node_span, raw_hash};
+
+/// Function-like nodes; one inside another's body is a nested function
+const FUNCTION_KINDS: &[&str] = &["function_definition", "lambda"];
 
 pub struct TreeSitterPythonAnalyzer {
     parser: Parser,
@@ -43,10 +49,23 @@ impl TreeSitterPythonAnalyzer {
         
         for mat in matches {
             let mut func_info = FunctionInfo::new(String::new());
+            let mut cognitive_score = 0;
+            let mut nesting_depth = 0;
+            
+            // Closures, lambdas and local functions are listed under their enclosing function
+            if mat.captures.iter().any(|capture| is_nested(capture.node, FUNCTION_KINDS)) {
+                continue;
+            }
             
             for capture in mat.captures {
                 let func_node = capture.node;
+                cognitive_score = cognitive_complexity(func_node, source, &cognitive::PYTHON);
+                nesting_depth = max_nesting_depth(func_node, &cognitive::PYTHON);
+                func_info.body_hash = body_hash(func_node, source);
+                func_info.raw_hash = raw_hash(func_node, source);
+                func_info.clone_tokens = clone_tokens(func_node);
                 func_info.start_line = func_node.start_position().row as u32 + 1;
+                func_info.span = node_span(func_node, source);
                 func_info.end_line = func_node.end_position().row as u32 + 1;
                 
                 if func_node.kind() == "lambda" {
@@ -63,15 +82,27 @@ impl TreeSitterPythonAnalyzer {
                             }
                         }
                     }
+                    
+                    // Methods are owned by the class whose body defines them directly
+                    if let Some(class_name) = self.defining_class(func_node, source) {
+                        func_info.metadata.insert("class_name".to_string(), class_name);
+                    }
+                    if let Some(parent) = func_node.parent() {
+                        func_info.metadata.extend(self.extract_decorators(parent, source)?);
+                        func_info.annotations = self.decorator_annotations(parent, source);
+                    }
                 }
                 
                 // Extract parameters
                 func_info.parameters = self.extract_parameters(func_node, source)?;
                 func_info.is_async = self.is_async_function(func_node, source);
+                func_info.children = self.nested_functions(func_node, &func_info.name, source)?;
             }
             
             // Set default complexity
             func_info.complexity = ComplexityInfo::default();
+            func_info.complexity.cognitive_complexity = cognitive_score;
+            func_info.complexity.max_nesting_depth = nesting_depth;
             
             functions.push(func_info);
         }
@@ -97,6 +128,7 @@ impl TreeSitterPythonAnalyzer {
             for capture in mat.captures {
                 let class_node = capture.node;
                 class_info.start_line = class_node.start_position().row as u32 + 1;
+                class_info.span = node_span(class_node, source);
                 class_info.end_line = class_node.end_position().row as u32 + 1;
                 
                 // Try to extract class name and other info from the node structure
@@ -112,6 +144,10 @@ impl TreeSitterPythonAnalyzer {
                     }
                 }
                 
+                if let Some(parent) = class_node.parent() {
+                    class_info.annotations = self.decorator_annotations(parent, source);
+                }
+                
                 // Extract methods
                 class_info.methods = self.extract_class_methods(class_node, source)?;
             }
@@ -122,11 +158,11 @@ impl TreeSitterPythonAnalyzer {
         Ok(classes)
     }
     
-    /// Extract imports using tree-sitter query
-    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+    /// Extract imports, one per imported module, with the file's import table
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<(Vec<ImportInfo>, ImportTable)> {
         let mut imports = Vec::new();
+        let mut table = ImportTable::default();
         
-        // Simple query for import statements - be conservative with node names
         let query_str = r#"
             [
               (import_statement) @import
@@ -139,35 +175,149 @@ impl TreeSitterPythonAnalyzer {
         let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
         
         for mat in matches {
-            let mut import_info = ImportInfo::new(ImportType::PythonImport, String::new());
-            
             for capture in mat.captures {
+                let node = capture.node;
+                let line = node.start_position().row as u32 + 1;
+                table.add_statement(node, source);
+                
+                let mut names = node.walk();
+                let names: Vec<Node> = node.children_by_field_name("name", &mut names).collect();
                 match query.capture_names()[capture.index as usize].as_ref() {
                     "from_import" => {
-                        import_info.import_type = ImportType::PythonFromImport;
-                        import_info.line_number = capture.node.start_position().row as u32 + 1;
-                        
-                        // Extract the whole import statement text
-                        if let Ok(import_text) = capture.node.utf8_text(source.as_bytes()) {
-                            import_info.module_path = import_text.to_string();
+                        // from pkg.mod import a, b as c / from . import *
+                        let module = node.child_by_field_name("module_name")
+                            .map(|m| m.utf8_text(source.as_bytes()).unwrap_or("").to_string())
+                            .unwrap_or_default();
+                        let mut import_info = ImportInfo::new(ImportType::PythonFromImport, module);
+                        import_info.line_number = line;
+                        import_info.imported_names = self.extract_imported_names(&names, source)?;
+                        let mut wildcard = node.walk();
+                        if node.children(&mut wildcard).any(|c| c.kind() == "wildcard_import") {
+                            import_info.imported_names.push("*".to_string());
                         }
+                        if let [aliased] = names.as_slice() {
+                            import_info.alias = aliased.child_by_field_name("alias")
+                                .and_then(|a| a.utf8_text(source.as_bytes()).ok())
+                                .map(str::to_string);
+                        }
+                        imports.push(import_info);
                     }
                     "import" => {
-                        import_info.line_number = capture.node.start_position().row as u32 + 1;
-                        
-                        // Extract the whole import statement text
-                        if let Ok(import_text) = capture.node.utf8_text(source.as_bytes()) {
-                            import_info.module_path = import_text.to_string();
+                        // import os, numpy as np
+                        for name in names {
+                            let (module, alias) = match name.kind() {
+                                "aliased_import" => (name.child_by_field_name("name"), name.child_by_field_name("alias")),
+                                _ => (Some(name), None),
+                            };
+                            let Some(module) = module else { continue };
+                            let mut import_info = ImportInfo::new(ImportType::PythonImport, module.utf8_text(source.as_bytes())?.to_string());
+                            import_info.line_number = line;
+                            import_info.alias = alias.map(|a| a.utf8_text(source.as_bytes()).unwrap_or("").to_string());
+                            imports.push(import_info);
                         }
                     }
                     _ => {}
                 }
             }
+        }
+        
+        Ok((imports, table))
+    }
+    
+    /// Extract calls: `f()`, `obj.m()`, `self.m()` / `cls.m()`, `ClassName.m()` and
+    /// module-qualified `mod.f()`, resolved against the class scope and imports
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str, imports: &ImportTable, classes: &[ClassInfo]) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        
+        let query = Query::new(&tree_sitter_python::LANGUAGE.into(), "(call function: (_) @function) @call")?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let Some(function) = mat.captures.iter().find(|c| query.capture_names()[c.index as usize] == "function").map(|c| c.node) else { continue };
+            let line = function.start_position().row as u32 + 1;
             
-            imports.push(import_info);
+            let function_call = match function.kind() {
+                "identifier" => {
+                    let name = function.utf8_text(source.as_bytes())?;
+                    match imports.get(name) {
+                        // from app.utils import slugify as slug; slug(): a call of app.utils.slugify
+                        Some(ImportBinding::Member { module, name: imported }) => {
+                            let mut call = FunctionCall::new(imported.clone(), line);
+                            call.module = Some(module.clone());
+                            call
+                        }
+                        _ => FunctionCall::new(name.to_string(), line),
+                    }
+                }
+                "attribute" => {
+                    let (Some(object), Some(attribute)) = (function.child_by_field_name("object"), function.child_by_field_name("attribute")) else { continue };
+                    let object_text = object.utf8_text(source.as_bytes())?.to_string();
+                    let mut call = FunctionCall::new(attribute.utf8_text(source.as_bytes())?.to_string(), line);
+                    call.object_name = Some(object_text.clone());
+                    call.is_method_call = true;
+                    
+                    match (object_text.as_str(), imports.get(&object_text)) {
+                        // self.helper() / cls.create() resolve to the enclosing class
+                        ("self" | "cls", _) => call.receiver_type = self.enclosing_class(function, source),
+                        // import app.utils as u; u.slugify(): a module function, not a method
+                        (_, Some(ImportBinding::Module(module))) => {
+                            call.module = Some(module.clone());
+                            call.is_method_call = false;
+                        }
+                        // from app.models import User; User.create(): static call on the imported class
+                        (_, Some(ImportBinding::Member { module, name })) => {
+                            call.receiver_type = Some(name.clone());
+                            call.module = Some(module.clone());
+                        }
+                        // Static call on a class of this file
+                        (object, None) if classes.iter().any(|c| c.name == object) => {
+                            call.receiver_type = Some(object.to_string());
+                        }
+                        _ => {}
+                    }
+                    call
+                }
+                // Calls of call results, subscripts and lambdas have no static name
+                _ => continue,
+            };
+            function_calls.push(function_call);
         }
         
-        Ok(imports)
+        function_calls.sort_by_key(|call| call.line_number);
+        Ok(function_calls)
+    }
+    
+    /// Helper: Class whose body defines `func` directly (not through a nested function)
+    fn defining_class(&self, func: Node, source: &str) -> Option<String> {
+        let mut current = func.parent();
+        while let Some(parent) = current {
+            match parent.kind() {
+                "class_definition" => return self.class_name(parent, source),
+                "function_definition" | "lambda" => return None,
+                _ => current = parent.parent(),
+            }
+        }
+        None
+    }
+    
+    /// Helper: Innermost class around a node, through nested functions (`self` in a closure)
+    fn enclosing_class(&self, node: Node, source: &str) -> Option<String> {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if parent.kind() == "class_definition" {
+                return self.class_name(parent, source);
+            }
+            current = parent.parent();
+        }
+        None
+    }
+    
+    /// Helper: Name of a class_definition
+    fn class_name(&self, class: Node, source: &str) -> Option<String> {
+        class.child_by_field_name("name")
+            .and_then(|name| name.utf8_text(source.as_bytes()).ok())
+            .map(str::to_string)
     }
     
     /// Helper: Extract parameters from a function node
@@ -205,6 +355,41 @@ impl TreeSitterPythonAnalyzer {
         Ok(params)
     }
     
+    /// Helper: Functions defined in a function body as children, recursively
+    ///
+    /// Local `def`s keep their name; lambdas are named `Parent$funcN`.
+    fn nested_functions(&self, node: Node, parent: &str, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut children = Vec::new();
+        let mut anonymous = 0;
+        
+        for child in nested_function_nodes(node, FUNCTION_KINDS) {
+            let name = match child.child_by_field_name("name") {
+                Some(name) => name.utf8_text(source.as_bytes())?.to_string(),
+                None => {
+                    anonymous += 1;
+                    anonymous_name(parent, anonymous)
+                }
+            };
+            
+            let mut func_info = FunctionInfo::new(name);
+            func_info.nested = true;
+            if child.kind() == "lambda" {
+                func_info.metadata.insert("is_lambda".to_string(), "true".to_string());
+            }
+            func_info.start_line = child.start_position().row as u32 + 1;
+            func_info.span = node_span(child, source);
+            func_info.end_line = child.end_position().row as u32 + 1;
+            func_info.parameters = self.extract_parameters(child, source)?;
+            func_info.is_async = self.is_async_function(child, source);
+            func_info.complexity.cognitive_complexity = cognitive_complexity(child, source, &cognitive::PYTHON);
+            func_info.complexity.max_nesting_depth = max_nesting_depth(child, &cognitive::PYTHON);
+            func_info.children = self.nested_functions(child, &func_info.name, source)?;
+            children.push(func_info);
+        }
+        
+        Ok(children)
+    }
+    
     /// Helper: Check if function is async
     fn is_async_function(&self, node: Node, _source: &str) -> bool {
         // Check if there's an async keyword before the function
@@ -239,6 +424,14 @@ impl TreeSitterPythonAnalyzer {
         if let Some(body) = class_node.child_by_field_name("body") {
             let mut cursor = body.walk();
             for child in body.children(&mut cursor) {
+                // `@property def url(self)` is wrapped in a decorated_definition
+                let child = match child.kind() {
+                    "decorated_definition" => match child.child_by_field_name("definition") {
+                        Some(definition) => definition,
+                        None => continue,
+                    },
+                    _ => child,
+                };
                 if child.kind() == "function_definition" {
                     let mut method = FunctionInfo::new(String::new());
                     
@@ -247,6 +440,7 @@ impl TreeSitterPythonAnalyzer {
                     }
                     
                     method.start_line = child.start_position().row as u32 + 1;
+                    method.span = node_span(child, source);
                     method.end_line = child.end_position().row as u32 + 1;
                     method.parameters = self.extract_parameters(child, source)?;
                     method.is_async = self.is_async_function(child, source);
@@ -260,6 +454,7 @@ impl TreeSitterPythonAnalyzer {
                     // Check for decorators
                     if let Some(parent) = child.parent() {
                         method.metadata.extend(self.extract_decorators(parent, source)?);
+                        method.annotations = self.decorator_annotations(parent, source);
                     }
                     
                     methods.push(method);
@@ -290,15 +485,51 @@ impl TreeSitterPythonAnalyzer {
             metadata.insert("decorators".to_string(), decorators.join(", "));
         }
         
+        // Built-in decorators that change how the method is called
+        for decorator in &decorators {
+            let name = decorator.trim_start_matches('@').split('(').next().unwrap_or("").trim();
+            let flag = match name {
+                "staticmethod" => "is_static",
+                "classmethod" => "is_classmethod",
+                "property" | "functools.cached_property" | "cached_property" => "is_property",
+                _ if name.ends_with(".setter") || name.ends_with(".getter") || name.ends_with(".deleter") => "is_property",
+                _ => continue,
+            };
+            metadata.insert(flag.to_string(), "true".to_string());
+        }
+        
         Ok(metadata)
     }
     
-    /// Extract imported names from import list
-    fn extract_imported_names(&self, node: Node, source: &str) -> Result<Vec<String>> {
-        let mut names = Vec::new();
+    /// Helper: Decorators of a decorated definition as annotations (`@app.route("/")` is `app.route`)
+    fn decorator_annotations(&self, node: Node, source: &str) -> Vec<Annotation> {
+        let mut annotations = Vec::new();
+        if node.kind() != "decorated_definition" {
+            return annotations;
+        }
         
+        let text = |n: Node| n.utf8_text(source.as_bytes()).unwrap_or("");
         let mut cursor = node.walk();
-        for child in node.children(&mut cursor) {
+        for decorator in node.children(&mut cursor).filter(|child| child.kind() == "decorator") {
+            let Some(expression) = decorator.named_child(0) else { continue };
+            let line_number = decorator.start_position().row as u32 + 1;
+            let annotation = match (expression.kind(), expression.child_by_field_name("function")) {
+                ("call", Some(function)) => {
+                    let arguments = expression.child_by_field_name("arguments").map_or("", text);
+                    Annotation::new(text(function), arguments, line_number)
+                }
+                _ => Annotation::new(text(expression), "", line_number),
+            };
+            annotations.push(annotation);
+        }
+        annotations
+    }
+    
+    /// Extract imported names from the `name` children of an import (aliases resolved to the original)
+    fn extract_imported_names(&self, name_nodes: &[Node], source: &str) -> Result<Vec<String>> {
+        let mut names = Vec::new();
+        
+        for child in name_nodes {
             match child.kind() {
                 "identifier" | "dotted_name" => {
                     if let Ok(name) = child.utf8_text(source.as_bytes()) {
@@ -429,7 +660,9 @@ impl LanguageAnalyzer for TreeSitterPythonAnalyzer {
         let extract_start = std::time::Instant::now();
         result.functions = self.extract_functions(&tree, content)?;
         result.classes = self.extract_classes(&tree, content)?;
-        result.imports = self.extract_imports(&tree, content)?;
+        let (imports, import_table) = self.extract_imports(&tree, content)?;
+        result.imports = imports;
+        result.function_calls = self.extract_function_calls(&tree, content, &import_table, &result.classes)?;
         let extract_duration = extract_start.elapsed();
         
         if std::env::var("NEKOCODE_DEBUG").is_ok() {
@@ -449,8 +682,7 @@ impl LanguageAnalyzer for TreeSitterPythonAnalyzer {
             eprintln!("⚡ [TREE-SITTER PYTHON] AST build took: {:.3}ms", ast_duration.as_secs_f64() * 1000.0);
         }
         
-        // Update statistics
-        result.update_statistics();
+        finish_analysis(&tree, content, &mut result, &magic_numbers::PYTHON);
         
         Ok(result)
     }
@@ -0,0 +1,525 @@
+//! 🚀 Tree-sitter based Ruby analyzer
+//! Classes, modules, methods (instance + singleton), attr_* members and call references
+
+use anyhow::Result;
+use tree_sitter::{Parser, Query, QueryCursor, Node};
+use async_trait::async_trait;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Methods that declare members or load files rather than reference symbols
+const DECLARATION_CALLS: &[&str] = &[
+    "attr_accessor", "attr_reader", "attr_writer",
+    "require", "require_relative", "load",
+];
+
+pub struct TreeSitterRubyAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterRubyAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_ruby::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Ruby language: {:?}", e))?;
+        
+        Ok(Self { parser })
+    }
+    
+    /// Extract methods (instance and singleton) using tree-sitter query
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+        
+        let query_str = r#"
+            [
+              (method
+                name: (_) @name) @method
+              (singleton_method
+                name: (_) @name) @method
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_ruby::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut func_node = None;
+            let mut name = String::new();
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "name" => {
+                        name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    "method" => {
+                        func_node = Some(capture.node);
+                    }
+                    _ => {}
+                }
+            }
+            
+            if let Some(node) = func_node {
+                let mut func_info = self.build_method_info(node, name, source)?;
+                
+                if let Some(owner) = self.enclosing_class_name(node, source) {
+                    func_info.metadata.insert("is_method".to_string(), "true".to_string());
+                    func_info.metadata.insert("class_name".to_string(), owner);
+                }
+                
+                functions.push(func_info);
+            }
+        }
+        
+        Ok(functions)
+    }
+    
+    /// Extract classes and modules (nested ones are reported separately)
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+        
+        let query_str = r#"
+            [
+              (class
+                name: (_) @name) @class
+              (module
+                name: (_) @name) @module
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_ruby::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut class_info = ClassInfo::new(String::new());
+            let mut class_node = None;
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "name" => {
+                        class_info.name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    kind @ ("class" | "module") => {
+                        class_node = Some(capture.node);
+                        class_info.metadata.insert("type".to_string(), kind.to_string());
+                        class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
+                        class_info.end_line = capture.node.end_position().row as u32 + 1;
+                    }
+                    _ => {}
+                }
+            }
+            
+            if let Some(node) = class_node {
+                // class Foo < Bar
+                if let Some(superclass) = node.child_by_field_name("superclass") {
+                    if let Some(parent) = superclass.named_child(0) {
+                        class_info.parent_class = Some(parent.utf8_text(source.as_bytes())?.to_string());
+                    }
+                }
+                
+                if let Some(body) = Self::body_of(node) {
+                    self.extract_class_body(body, source, &mut class_info)?;
+                }
+            }
+            
+            classes.push(class_info);
+        }
+        
+        Ok(classes)
+    }
+    
+    /// Helper: Collect methods and attr_* members declared directly in a class body
+    fn extract_class_body(&self, body: Node, source: &str, class_info: &mut ClassInfo) -> Result<()> {
+        let mut cursor = body.walk();
+        for child in body.children(&mut cursor) {
+            match child.kind() {
+                "method" | "singleton_method" => {
+                    let name = child.child_by_field_name("name")
+                        .map(|n| n.utf8_text(source.as_bytes()))
+                        .transpose()?
+                        .unwrap_or("")
+                        .to_string();
+                    let mut method = self.build_method_info(child, name, source)?;
+                    method.metadata.insert("is_method".to_string(), "true".to_string());
+                    class_info.methods.push(method);
+                }
+                // class << self ... end
+                "singleton_class" => {
+                    if let Some(singleton_body) = Self::body_of(child) {
+                        self.extract_class_body(singleton_body, source, class_info)?;
+                    }
+                }
+                "call" => {
+                    class_info.member_variables.extend(self.extract_attr_members(child, source)?);
+                }
+                _ => {}
+            }
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Turn `attr_accessor :a, :b` into member variables
+    fn extract_attr_members(&self, call: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut members = Vec::new();
+        
+        if call.child_by_field_name("receiver").is_some() {
+            return Ok(members);
+        }
+        
+        let accessor = match call.child_by_field_name("method") {
+            Some(method) => method.utf8_text(source.as_bytes())?,
+            None => return Ok(members),
+        };
+        if !matches!(accessor, "attr_accessor" | "attr_reader" | "attr_writer") {
+            return Ok(members);
+        }
+        
+        if let Some(arguments) = call.child_by_field_name("arguments") {
+            let mut cursor = arguments.walk();
+            for arg in arguments.named_children(&mut cursor) {
+                if arg.kind() == "simple_symbol" {
+                    let name = arg.utf8_text(source.as_bytes())?.trim_start_matches(':').to_string();
+                    let mut member = MemberVariable::new(name, String::new(), arg.start_position().row as u32 + 1);
+                    member.span = node_span(arg, source);
+                    member.access_modifier = "public".to_string();
+                    member.metadata.insert("accessor".to_string(), accessor.to_string());
+                    members.push(member);
+                }
+            }
+        }
+        
+        Ok(members)
+    }
+    
+    /// Extract require / require_relative / load statements
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        
+        let query_str = r#"
+            (call
+              method: (identifier) @method
+              arguments: (argument_list
+                (string
+                  (string_content) @path))) @import
+        "#;
+        
+        let query = Query::new(&tree_sitter_ruby::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut import_info = ImportInfo::new(ImportType::RubyRequire, String::new());
+            let mut method = "";
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "method" => {
+                        method = capture.node.utf8_text(source.as_bytes())?;
+                    }
+                    "path" => {
+                        import_info.module_path = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    "import" => {
+                        import_info.line_number = capture.node.start_position().row as u32 + 1;
+                    }
+                    _ => {}
+                }
+            }
+            
+            if matches!(method, "require" | "require_relative" | "load") {
+                import_info.metadata.insert("kind".to_string(), method.to_string());
+                imports.push(import_info);
+            }
+        }
+        
+        Ok(imports)
+    }
+    
+    /// Extract method call references (used by analyze-impact)
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        
+        let query_str = r#"
+            (call
+              method: (identifier) @method) @call
+        "#;
+        
+        let query = Query::new(&tree_sitter_ruby::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut call_node = None;
+            let mut method_name = String::new();
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "method" => {
+                        method_name = capture.node.utf8_text(source.as_bytes())?.to_string();
+                    }
+                    "call" => {
+                        call_node = Some(capture.node);
+                    }
+                    _ => {}
+                }
+            }
+            
+            if method_name.is_empty() || DECLARATION_CALLS.contains(&method_name.as_str()) {
+                continue;
+            }
+            
+            if let Some(node) = call_node {
+                let mut function_call = FunctionCall::new(method_name, node.start_position().row as u32 + 1);
+                
+                // obj.method / Klass.method
+                if let Some(receiver) = node.child_by_field_name("receiver") {
+                    function_call.object_name = Some(receiver.utf8_text(source.as_bytes())?.to_string());
+                    function_call.is_method_call = true;
+                }
+                
+                function_calls.push(function_call);
+            }
+        }
+        
+        Ok(function_calls)
+    }
+    
+    /// Helper: Build FunctionInfo for a `method` / `singleton_method` node
+    fn build_method_info(&self, node: Node, name: String, source: &str) -> Result<FunctionInfo> {
+        let mut func_info = FunctionInfo::new(name);
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.parameters = self.extract_parameters(node, source)?;
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+        
+        // def self.foo
+        if node.kind() == "singleton_method" {
+            func_info.metadata.insert("is_static".to_string(), "true".to_string());
+        } else if self.in_singleton_class(node) {
+            func_info.metadata.insert("is_static".to_string(), "true".to_string());
+        }
+        
+        Ok(func_info)
+    }
+    
+    /// Helper: Extract parameters from a method node
+    fn extract_parameters(&self, node: Node, source: &str) -> Result<Vec<String>> {
+        let mut params = Vec::new();
+        
+        if let Some(param_list) = node.child_by_field_name("parameters") {
+            let mut cursor = param_list.walk();
+            for child in param_list.named_children(&mut cursor) {
+                if child.kind() == "comment" {
+                    continue;
+                }
+                params.push(child.utf8_text(source.as_bytes())?.to_string());
+            }
+        }
+        
+        Ok(params)
+    }
+    
+    /// Calculate cyclomatic complexity for a method
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+        
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = Self::body_of(node) {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::RUBY);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::RUBY);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::RUBY);
+        
+        complexity.update_rating();
+        complexity
+    }
+    
+    /// Helper: Body of a class/module/method (field `body`, or the body_statement child)
+    fn body_of(node: Node) -> Option<Node> {
+        if let Some(body) = node.child_by_field_name("body") {
+            return Some(body);
+        }
+        
+        let mut cursor = node.walk();
+        let body = node.children(&mut cursor).find(|child| child.kind() == "body_statement");
+        body
+    }
+    
+    /// Helper: Name of the nearest enclosing class or module
+    fn enclosing_class_name(&self, node: Node, source: &str) -> Option<String> {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if matches!(parent.kind(), "class" | "module") {
+                return parent.child_by_field_name("name")
+                    .and_then(|name| name.utf8_text(source.as_bytes()).ok())
+                    .map(|name| name.to_string());
+            }
+            current = parent.parent();
+        }
+        None
+    }
+    
+    /// Helper: Whether a method is defined inside `class << self`
+    fn in_singleton_class(&self, node: Node) -> bool {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            match parent.kind() {
+                "singleton_class" => return true,
+                "class" | "module" => return false,
+                _ => current = parent.parent(),
+            }
+        }
+        false
+    }
+    
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+    
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let ast_type = match node.kind() {
+            "class" => ASTNodeType::Class,
+            "module" => ASTNodeType::Namespace,
+            "method" | "singleton_method" => ASTNodeType::Function,
+            "if" | "unless" => ASTNodeType::IfStatement,
+            "for" => ASTNodeType::ForLoop,
+            _ => ASTNodeType::Unknown,
+        };
+        
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+            
+            // Try to get node name
+            if let Some(name_field) = node.child_by_field_name("name") {
+                if let Ok(name) = name_field.utf8_text(source.as_bytes()) {
+                    ast_node.name = name.to_string();
+                }
+            }
+            
+            parent.add_child(ast_node);
+            
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+            
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterRubyAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Ruby
+    }
+    
+    fn get_language_name(&self) -> &'static str {
+        "Ruby (Tree-sitter)"
+    }
+    
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".rb"]
+    }
+    
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+        
+        // Calculate basic line statistics (# comments and =begin/=end blocks)
+        let mut in_block_comment = false;
+        for line in content.lines() {
+            let trimmed = line.trim();
+            if in_block_comment {
+                file_info.comment_lines += 1;
+                if line.starts_with("=end") {
+                    in_block_comment = false;
+                }
+            } else if line.starts_with("=begin") {
+                file_info.comment_lines += 1;
+                in_block_comment = true;
+            } else if trimmed.is_empty() {
+                file_info.empty_lines += 1;
+            } else if trimmed.starts_with('#') {
+                file_info.comment_lines += 1;
+            } else {
+                file_info.code_lines += 1;
+            }
+        }
+        
+        file_info.code_ratio = if file_info.total_lines > 0 {
+            file_info.code_lines as f64 / file_info.total_lines as f64
+        } else {
+            0.0
+        };
+        
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Ruby);
+        
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Ruby file"))?;
+        let parse_duration = parse_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER RUBY] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Extract all constructs
+        let extract_start = std::time::Instant::now();
+        result.functions = self.extract_functions(&tree, content)?;
+        result.classes = self.extract_classes(&tree, content)?;
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content)?;
+        let extract_duration = extract_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER RUBY] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::RUBY);
+        
+        Ok(result)
+    }
+}
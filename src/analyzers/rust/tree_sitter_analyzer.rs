@@ -7,10 +7,12 @@ use async_trait::async_trait;
 
 use crate::core::types::{
     AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, 
-    Language, ComplexityInfo, ImportType
+    Language, ComplexityInfo, ImportType, Annotation
 };
 use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
 use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, magic_numbers, max_nesting_depth, node_span, raw_hash};
 
 pub struct TreeSitterRustAnalyzer {
     parser: Parser,
@@ -54,12 +56,14 @@ impl TreeSitterRustAnalyzer {
                     "function" => {
                         func_node = Some(capture.node);
                         func_info.start_line = capture.node.start_position().row as u32 + 1;
+                        func_info.span = node_span(capture.node, source);
                         func_info.end_line = capture.node.end_position().row as u32 + 1;
                         func_info.metadata.insert("type".to_string(), "function".to_string());
                     }
                     "closure" => {
                         func_node = Some(capture.node);
                         func_info.start_line = capture.node.start_position().row as u32 + 1;
+                        func_info.span = node_span(capture.node, source);
                         func_info.end_line = capture.node.end_position().row as u32 + 1;
                         func_info.name = "closure".to_string();
                         func_info.metadata.insert("type".to_string(), "closure".to_string());
@@ -75,10 +79,18 @@ impl TreeSitterRustAnalyzer {
                 
                 // Extract function modifiers (pub, unsafe, etc.)
                 func_info.metadata.extend(self.extract_function_modifiers(node, source)?);
+                func_info.annotations = self.outer_attributes(node, source);
             }
             
             // Set default complexity (will be calculated separately)
             func_info.complexity = ComplexityInfo::default();
+            if let Some(node) = func_node {
+                func_info.complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::RUST);
+                func_info.complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::RUST);
+                func_info.body_hash = body_hash(node, source);
+                func_info.raw_hash = raw_hash(node, source);
+                func_info.clone_tokens = clone_tokens(node);
+            }
             
             functions.push(func_info);
         }
@@ -122,24 +134,28 @@ impl TreeSitterRustAnalyzer {
                     "struct" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "struct".to_string());
                     }
                     "enum" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "enum".to_string());
                     }
                     "trait" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "trait".to_string());
                     }
                     "impl" => {
                         class_node = Some(capture.node);
                         class_info.start_line = capture.node.start_position().row as u32 + 1;
+                        class_info.span = node_span(capture.node, source);
                         class_info.end_line = capture.node.end_position().row as u32 + 1;
                         class_info.metadata.insert("type".to_string(), "impl".to_string());
                     }
@@ -162,6 +178,7 @@ impl TreeSitterRustAnalyzer {
                 
                 // Extract visibility modifiers
                 class_info.metadata.extend(self.extract_type_modifiers(node, source)?);
+                class_info.annotations = self.outer_attributes(node, source);
             }
             
             classes.push(class_info);
@@ -326,6 +343,36 @@ impl TreeSitterRustAnalyzer {
         Ok(metadata)
     }
     
+    /// Helper: `#[...]` attributes directly above an item, doc comments between them skipped
+    ///
+    /// `#[derive(Debug, Clone)]` is `derive` with `Debug, Clone`; `#[path = "x.rs"]`
+    /// keeps the value as its arguments.
+    fn outer_attributes(&self, node: Node, source: &str) -> Vec<Annotation> {
+        let text = |n: Node| n.utf8_text(source.as_bytes()).unwrap_or("");
+        let mut attributes = Vec::new();
+        
+        let mut sibling = node.prev_named_sibling();
+        while let Some(item) = sibling {
+            match item.kind() {
+                "attribute_item" => {
+                    if let Some(attribute) = item.named_child(0) {
+                        let name = attribute.named_child(0).map_or("", text);
+                        let arguments = attribute.child_by_field_name("arguments")
+                            .or_else(|| attribute.child_by_field_name("value"))
+                            .map_or("", text);
+                        attributes.push(Annotation::new(name, arguments, item.start_position().row as u32 + 1));
+                    }
+                }
+                "line_comment" | "block_comment" => {}
+                _ => break,
+            }
+            sibling = item.prev_named_sibling();
+        }
+        
+        attributes.reverse();
+        attributes
+    }
+    
     /// Extract associated functions (methods) from impl blocks
     fn extract_associated_functions(&self, type_node: Node, source: &str) -> Result<Vec<FunctionInfo>> {
         let mut methods = Vec::new();
@@ -342,6 +389,7 @@ impl TreeSitterRustAnalyzer {
                     }
                     
                     method.start_line = child.start_position().row as u32 + 1;
+                    method.span = node_span(child, source);
                     method.end_line = child.end_position().row as u32 + 1;
                     method.parameters = self.extract_parameters(child, source)?;
                     method.is_async = self.is_async_function(child, source);
@@ -356,6 +404,7 @@ impl TreeSitterRustAnalyzer {
                     
                     // Extract method modifiers
                     method.metadata.extend(self.extract_function_modifiers(child, source)?);
+                    method.annotations = self.outer_attributes(child, source);
                     
                     methods.push(method);
                 }
@@ -498,8 +547,7 @@ impl LanguageAnalyzer for TreeSitterRustAnalyzer {
             eprintln!("⚡ [TREE-SITTER RUST] AST build took: {:.3}ms", ast_duration.as_secs_f64() * 1000.0);
         }
         
-        // Update statistics
-        result.update_statistics();
+        finish_analysis(&tree, content, &mut result, &magic_numbers::RUST);
         
         Ok(result)
     }
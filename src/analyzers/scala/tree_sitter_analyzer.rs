@@ -0,0 +1,933 @@
+//! 🚀 Tree-sitter based Scala analyzer
+//! Classes, case classes, traits, objects (companions included), Scala 3 enums
+//! and package objects, nested ones as `Outer.Inner`; `def`s at any level,
+//! `val` / `var` members and constructor properties; imports with selectors
+//! and renames; calls, `new T(...)` and alphanumeric infix calls (`xs map f`).
+//!
+//! The first type after `extends` is the parent class, the `with` (or Scala 3
+//! `,`) types are recorded in `implements`, so both feed the type hierarchy.
+
+use anyhow::Result;
+use tree_sitter::{Parser, Node};
+use async_trait::async_trait;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable, ParameterInfo
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::tree_sitter_util::{child_of_kind, has_keyword};
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Definitions that introduce a type (and a level of `Outer.Inner` nesting)
+const TYPE_DEFINITIONS: &[&str] = &[
+    "class_definition", "trait_definition", "object_definition", "enum_definition", "package_object",
+];
+
+/// Function definitions (with body) and abstract declarations
+const FUNCTION_DEFINITIONS: &[&str] = &["function_definition", "function_declaration"];
+
+/// Member `val` / `var` definitions and abstract declarations
+const FIELD_DEFINITIONS: &[&str] = &["val_definition", "var_definition", "val_declaration", "var_declaration"];
+
+pub struct TreeSitterScalaAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterScalaAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_scala::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Scala language: {:?}", e))?;
+
+        Ok(Self { parser })
+    }
+
+    /// Extract top-level, member and local `def`s
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+
+        for node in Self::descendants(tree.root_node(), FUNCTION_DEFINITIONS) {
+            let owner = self.enclosing_type_name(node, source);
+            let mut func_info = self.build_function_info(node, owner.as_deref(), source)?;
+            if Self::is_local(node) {
+                func_info.metadata.insert("is_local".to_string(), "true".to_string());
+            }
+            functions.push(func_info);
+        }
+
+        Ok(functions)
+    }
+
+    /// Extract classes, traits, objects, enums and package objects with their members
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+        for node in Self::descendants(tree.root_node(), TYPE_DEFINITIONS) {
+            if let Some(class_info) = self.build_class_info(node, source)? {
+                classes.push(class_info);
+            }
+        }
+
+        // object Foo next to class / trait Foo is its companion
+        let companions: Vec<String> = classes.iter()
+            .filter(|c| c.metadata.get("type").map_or(false, |t| t == "object"))
+            .filter(|object| classes.iter().any(|c| c.name == object.name && Self::is_class_like(c)))
+            .map(|object| object.name.clone())
+            .collect();
+        for class in &mut classes {
+            if !companions.contains(&class.name) {
+                continue;
+            }
+            if Self::is_class_like(class) {
+                class.metadata.insert("companion_object".to_string(), class.name.clone());
+            } else {
+                class.metadata.insert("is_companion".to_string(), "true".to_string());
+            }
+        }
+
+        Ok(classes)
+    }
+
+    /// Helper: Build ClassInfo for a type definition
+    fn build_class_info(&self, node: Node, source: &str) -> Result<Option<ClassInfo>> {
+        let Some(name) = node.child_by_field_name("name") else { return Ok(None) };
+        let mut class_info = ClassInfo::new(name.utf8_text(source.as_bytes())?.to_string());
+        class_info.start_line = node.start_position().row as u32 + 1;
+        class_info.end_line = node.end_position().row as u32 + 1;
+        class_info.span = node_span(node, source);
+
+        let is_case = has_keyword(node, "case");
+        let kind = match node.kind() {
+            "class_definition" if is_case => "case_class",
+            "class_definition" => "class",
+            "trait_definition" => "trait",
+            "object_definition" if is_case => "case_object",
+            "object_definition" => "object",
+            "enum_definition" => "enum",
+            _ => "package_object",
+        };
+        class_info.metadata.insert("type".to_string(), kind.to_string());
+
+        // Nested types: Outer.Inner
+        if let Some(outer) = self.enclosing_type_name(node, source) {
+            class_info.metadata.insert("outer_class".to_string(), outer.clone());
+            class_info.metadata.insert("simple_name".to_string(), class_info.name.clone());
+            class_info.name = format!("{}.{}", outer, class_info.name);
+        }
+
+        let modifiers = self.extract_modifiers(node, source);
+        class_info.metadata.extend(modifiers.metadata());
+        if modifiers.has("sealed") {
+            class_info.metadata.insert("is_sealed".to_string(), "true".to_string());
+        }
+
+        // class A(x: Int) extends Base(x) with T1 with T2 derives Eq
+        let extends = node.child_by_field_name("extend").or_else(|| child_of_kind(node, "extends_clause"));
+        if let Some(extends) = extends {
+            let mut supertypes = split_supertypes(extends.utf8_text(source.as_bytes())?).into_iter();
+            class_info.parent_class = supertypes.next();
+            class_info.implements.extend(supertypes);
+        }
+        let derives = node.child_by_field_name("derive").or_else(|| child_of_kind(node, "derives_clause"));
+        if let Some(derives) = derives {
+            let text = derives.utf8_text(source.as_bytes())?;
+            let text = text.trim().strip_prefix("derives").unwrap_or(text);
+            class_info.metadata.insert("derives".to_string(), split_supertypes(text).join(", "));
+        }
+
+        // class Point(val x: Int, var y: Int); case class parameters are vals
+        let mut cursor = node.walk();
+        let parameter_lists: Vec<Node> = node.named_children(&mut cursor)
+            .filter(|c| c.kind() == "class_parameters")
+            .collect();
+        for list in parameter_lists {
+            class_info.member_variables.extend(self.extract_constructor_properties(list, is_case, source)?);
+        }
+
+        let body = node.child_by_field_name("body").or_else(|| {
+            let mut cursor = node.walk();
+            let found = node.named_children(&mut cursor).find(|c| matches!(c.kind(), "template_body" | "enum_body"));
+            found
+        });
+        if let Some(body) = body {
+            self.extract_members(body, source, &mut class_info)?;
+        }
+
+        Ok(Some(class_info))
+    }
+
+    /// Helper: Methods, fields and enum cases declared directly in a type body
+    fn extract_members(&self, body: Node, source: &str, class_info: &mut ClassInfo) -> Result<()> {
+        let is_trait = class_info.metadata.get("type").map_or(false, |t| t == "trait");
+
+        let mut cursor = body.walk();
+        let children: Vec<Node> = body.named_children(&mut cursor).collect();
+        for child in children {
+            match child.kind() {
+                kind if FUNCTION_DEFINITIONS.contains(&kind) => {
+                    let mut method = self.build_function_info(child, Some(&class_info.name), source)?;
+                    if is_trait {
+                        method.metadata.insert("is_interface_method".to_string(), "true".to_string());
+                    }
+                    class_info.methods.push(method);
+                }
+                kind if FIELD_DEFINITIONS.contains(&kind) => {
+                    class_info.member_variables.extend(self.extract_fields(child, source)?);
+                }
+                // enum Color { case Red, Green; case Mix(r: Int) }
+                "enum_case_definitions" => {
+                    let mut case_cursor = child.walk();
+                    let cases: Vec<Node> = child.named_children(&mut case_cursor)
+                        .filter(|c| matches!(c.kind(), "simple_enum_case" | "full_enum_case"))
+                        .collect();
+                    for case in cases {
+                        let Some(name) = case.child_by_field_name("name") else { continue };
+                        let mut member = MemberVariable::new(name.utf8_text(source.as_bytes())?.to_string(), class_info.name.clone(), case.start_position().row as u32 + 1);
+                        member.span = node_span(case, source);
+                        member.access_modifier = "public".to_string();
+                        member.is_static = true;
+                        member.is_const = true;
+                        member.metadata.insert("enum_constant".to_string(), "true".to_string());
+                        class_info.member_variables.push(member);
+                    }
+                }
+                _ => {}
+            }
+        }
+
+        Ok(())
+    }
+
+    /// Helper: Member variables of a `val` / `var` (`val (a, b) = pair` yields two)
+    fn extract_fields(&self, node: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let modifiers = self.extract_modifiers(node, source);
+        let binding = if node.kind().starts_with("val") { "val" } else { "var" };
+        let var_type = node.child_by_field_name("type")
+            .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+            .unwrap_or("")
+            .to_string();
+
+        // Declarations list their names, definitions bind a pattern
+        let names: Vec<Node> = match node.child_by_field_name("pattern") {
+            Some(pattern) if pattern.kind() == "identifier" => vec![pattern],
+            Some(pattern) => Self::descendants(pattern, &["identifier"]),
+            None => {
+                let mut cursor = node.walk();
+                let names = node.children_by_field_name("name", &mut cursor).collect();
+                names
+            }
+        };
+
+        let mut fields = Vec::new();
+        for name in names {
+            let name = name.utf8_text(source.as_bytes())?;
+            if name == "_" {
+                continue;
+            }
+            let mut member = MemberVariable::new(name.to_string(), var_type.clone(), node.start_position().row as u32 + 1);
+            member.span = node_span(node, source);
+            member.access_modifier = modifiers.visibility();
+            member.is_const = binding == "val";
+            member.metadata.insert("binding".to_string(), binding.to_string());
+            if node.kind().ends_with("_declaration") {
+                member.metadata.insert("is_abstract".to_string(), "true".to_string());
+            }
+            if modifiers.has("lazy") {
+                member.metadata.insert("lazy".to_string(), "true".to_string());
+            }
+            if !modifiers.keywords.is_empty() {
+                member.metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+            }
+            fields.push(member);
+        }
+
+        Ok(fields)
+    }
+
+    /// Helper: Constructor parameters that are also members (`val` / `var`, or any case class parameter)
+    fn extract_constructor_properties(&self, list: Node, is_case: bool, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut properties = Vec::new();
+
+        let mut cursor = list.walk();
+        let parameters: Vec<Node> = list.named_children(&mut cursor)
+            .filter(|p| p.kind() == "class_parameter")
+            .collect();
+        for parameter in parameters {
+            let binding = if has_keyword(parameter, "var") {
+                "var"
+            } else if has_keyword(parameter, "val") || is_case {
+                "val"
+            } else {
+                continue;
+            };
+            let Some(name) = parameter.child_by_field_name("name") else { continue };
+
+            let modifiers = self.extract_modifiers(parameter, source);
+            let var_type = parameter.child_by_field_name("type")
+                .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+                .unwrap_or("")
+                .to_string();
+            let mut member = MemberVariable::new(name.utf8_text(source.as_bytes())?.to_string(), var_type, parameter.start_position().row as u32 + 1);
+            member.span = node_span(parameter, source);
+            member.access_modifier = modifiers.visibility();
+            member.is_const = binding == "val";
+            member.metadata.insert("binding".to_string(), binding.to_string());
+            member.metadata.insert("constructor_property".to_string(), "true".to_string());
+            properties.push(member);
+        }
+
+        Ok(properties)
+    }
+
+    /// Helper: Build FunctionInfo for a `def`
+    fn build_function_info(&self, node: Node, owner: Option<&str>, source: &str) -> Result<FunctionInfo> {
+        let name = match node.child_by_field_name("name") {
+            Some(name) => name.utf8_text(source.as_bytes())?.to_string(),
+            None => String::new(),
+        };
+
+        let mut func_info = FunctionInfo::new(name);
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+
+        // def this(...) is an auxiliary constructor
+        let kind = if func_info.name == "this" { "constructor" } else { "function" };
+        func_info.metadata.insert("type".to_string(), kind.to_string());
+
+        // def f[T](a: Int)(implicit ctx: Context): T
+        let mut cursor = node.walk();
+        let parameter_lists: Vec<Node> = node.children_by_field_name("parameters", &mut cursor).collect();
+        let mut implicit = Vec::new();
+        for list in &parameter_lists {
+            let is_implicit = has_keyword(*list, "implicit") || has_keyword(*list, "using");
+            let mut param_cursor = list.walk();
+            for parameter in list.named_children(&mut param_cursor).filter(|p| p.kind() == "parameter") {
+                let name = parameter.child_by_field_name("name")
+                    .and_then(|n| n.utf8_text(source.as_bytes()).ok())
+                    .unwrap_or("")
+                    .to_string();
+                let param_type = parameter.child_by_field_name("type")
+                    .and_then(|t| t.utf8_text(source.as_bytes()).ok())
+                    .unwrap_or("")
+                    .to_string();
+                if let Ok(text) = parameter.utf8_text(source.as_bytes()) {
+                    func_info.parameters.push(text.split_whitespace().collect::<Vec<_>>().join(" "));
+                }
+                if is_implicit {
+                    implicit.push(name.clone());
+                }
+                // xs: Int* is a repeated parameter
+                let variadic = param_type.trim_end().ends_with('*');
+                func_info.params.push(ParameterInfo { name, param_type, variadic });
+            }
+        }
+        if parameter_lists.len() > 1 {
+            func_info.metadata.insert("parameter_lists".to_string(), parameter_lists.len().to_string());
+        }
+        if !implicit.is_empty() {
+            func_info.metadata.insert("implicit_parameters".to_string(), implicit.join(","));
+        }
+        if let Some(return_type) = node.child_by_field_name("return_type") {
+            let return_type = return_type.utf8_text(source.as_bytes())?.to_string();
+            func_info.returns.push(return_type.clone());
+            func_info.metadata.insert("return_type".to_string(), return_type);
+        }
+
+        let modifiers = self.extract_modifiers(node, source);
+        func_info.metadata.extend(modifiers.metadata());
+        // A `def` without a body is abstract
+        if node.kind() == "function_declaration" {
+            func_info.metadata.insert("is_abstract".to_string(), "true".to_string());
+        }
+
+        if let Some(owner) = owner {
+            func_info.metadata.insert("is_method".to_string(), "true".to_string());
+            func_info.metadata.insert("class_name".to_string(), owner.to_string());
+        }
+        // extension (s: String) def shout: String
+        if Self::ancestor_of_kind(node, "extension_definition").is_some() {
+            func_info.metadata.insert("is_extension".to_string(), "true".to_string());
+        }
+
+        Ok(func_info)
+    }
+
+    /// Helper: Modifier keywords and annotation names of a definition
+    fn extract_modifiers(&self, node: Node, source: &str) -> Modifiers {
+        let mut modifiers = Modifiers::default();
+
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            let Ok(text) = child.utf8_text(source.as_bytes()) else { continue };
+            match child.kind() {
+                // @deprecated("...") / @transient -> deprecated / transient
+                "annotation" => {
+                    let name = text.trim_start_matches('@');
+                    let name = name.split(|c: char| c == '(' || c.is_whitespace()).next().unwrap_or(name);
+                    modifiers.annotations.push(name.to_string());
+                }
+                "modifiers" => {
+                    let mut inner = child.walk();
+                    for modifier in child.children(&mut inner) {
+                        let Ok(text) = modifier.utf8_text(source.as_bytes()) else { continue };
+                        let text: String = text.split_whitespace().collect();
+                        if modifier.kind() == "access_modifier" {
+                            // private[spark] -> private, scoped to `spark`
+                            let (access, scope) = match text.split_once('[') {
+                                Some((access, scope)) => (access.to_string(), Some(scope.trim_end_matches(']').to_string())),
+                                None => (text.clone(), None),
+                            };
+                            modifiers.access = Some(access);
+                            modifiers.scope = scope;
+                        }
+                        modifiers.keywords.push(text);
+                    }
+                }
+                _ => {}
+            }
+        }
+
+        modifiers
+    }
+
+    /// Extract `import` clauses (`a.b.C`, `a.b._`, `a.b.{C, D => E}`, `a.b.C as D`)
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+
+        for node in Self::descendants(tree.root_node(), &["import_declaration"]) {
+            let text = node.utf8_text(source.as_bytes())?;
+            for clause in import_clauses(text) {
+                let mut import_info = ImportInfo::new(ImportType::ScalaImport, clause.path);
+                import_info.line_number = node.start_position().row as u32 + 1;
+                import_info.imported_names = clause.names;
+                if let [(_, alias)] = clause.renames.as_slice() {
+                    if import_info.imported_names.len() == 1 {
+                        import_info.alias = Some(alias.clone());
+                    }
+                }
+                if !clause.renames.is_empty() {
+                    let renames: Vec<String> = clause.renames.iter().map(|(name, alias)| format!("{}=>{}", name, alias)).collect();
+                    import_info.metadata.insert("aliases".to_string(), renames.join(","));
+                }
+                if !clause.hidden.is_empty() {
+                    import_info.metadata.insert("hide".to_string(), clause.hidden.join(","));
+                }
+                imports.push(import_info);
+            }
+        }
+
+        Ok(imports)
+    }
+
+    /// Extract calls: `f()`, `obj.m()`, `f[T]()`, `new T()` and infix `xs map f`
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str, classes: &[ClassInfo]) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        let mut stack = vec![tree.root_node()];
+
+        while let Some(node) = stack.pop() {
+            let mut cursor = node.walk();
+            let children: Vec<Node> = node.named_children(&mut cursor).collect();
+            stack.extend(children.iter().rev().copied());
+            let line = node.start_position().row as u32 + 1;
+
+            match node.kind() {
+                "call_expression" => {
+                    let Some(mut callee) = node.child_by_field_name("function") else { continue };
+                    // f[T](x)
+                    if callee.kind() == "generic_function" {
+                        match callee.child_by_field_name("function") {
+                            Some(inner) => callee = inner,
+                            None => continue,
+                        }
+                    }
+                    match callee.kind() {
+                        "identifier" => function_calls.push(FunctionCall::new(callee.utf8_text(source.as_bytes())?.to_string(), line)),
+                        // receiver.method(...)
+                        "field_expression" => {
+                            let (Some(receiver), Some(field)) = (callee.child_by_field_name("value"), callee.child_by_field_name("field")) else { continue };
+                            let call = self.method_call(field.utf8_text(source.as_bytes())?, receiver, line, source, classes)?;
+                            function_calls.push(call);
+                        }
+                        // Curried `f(a)(b)` is counted once, at its innermost call
+                        _ => {}
+                    }
+                }
+                // new Foo(x) / new Foo[Int]
+                "instance_expression" => {
+                    let type_node = children.iter()
+                        .find(|c| matches!(c.kind(), "type_identifier" | "generic_type" | "stable_type_identifier"));
+                    if let Some(type_node) = type_node {
+                        let text = type_node.utf8_text(source.as_bytes())?;
+                        let name = text.split('[').next().unwrap_or(text).rsplit('.').next().unwrap_or(text).trim();
+                        function_calls.push(FunctionCall::new(name.to_string(), line));
+                    }
+                }
+                // xs map f: alphanumeric operators are method calls; `+`, `::`, `&&` are not reported
+                "infix_expression" => {
+                    let (Some(left), Some(operator)) = (node.child_by_field_name("left"), node.child_by_field_name("operator")) else { continue };
+                    let operator = operator.utf8_text(source.as_bytes())?;
+                    if operator.starts_with(|c: char| c.is_alphabetic()) {
+                        function_calls.push(self.method_call(operator, left, line, source, classes)?);
+                    }
+                }
+                _ => {}
+            }
+        }
+
+        function_calls.sort_by_key(|call| call.line_number);
+        Ok(function_calls)
+    }
+
+    /// Helper: Method call on a receiver; `this.m()` and `Type.m()` resolve the receiver type
+    fn method_call(&self, name: &str, receiver: Node, line: u32, source: &str, classes: &[ClassInfo]) -> Result<FunctionCall> {
+        let mut call = FunctionCall::new(name.to_string(), line);
+        call.is_method_call = true;
+
+        let receiver_text = receiver.utf8_text(source.as_bytes())?.to_string();
+        if receiver_text == "this" {
+            call.receiver_type = Self::enclosing_class(receiver, classes);
+        } else if receiver.kind() == "identifier" {
+            // Objects are referred to by their simple name
+            call.receiver_type = classes.iter()
+                .find(|c| c.name == receiver_text || c.metadata.get("simple_name") == Some(&receiver_text))
+                .map(|c| c.name.clone());
+        }
+        call.object_name = Some(receiver_text);
+
+        Ok(call)
+    }
+
+    /// Helper: Name of the innermost type definition containing a node
+    fn enclosing_class(node: Node, classes: &[ClassInfo]) -> Option<String> {
+        let line = node.start_position().row as u32 + 1;
+        classes.iter()
+            .filter(|c| c.start_line <= line && line <= c.end_line)
+            .max_by_key(|c| c.start_line)
+            .map(|c| c.name.clone())
+    }
+
+    /// Helper: Qualified name of the nearest enclosing type definition (`Outer.Inner`)
+    fn enclosing_type_name(&self, node: Node, source: &str) -> Option<String> {
+        let mut names = Vec::new();
+
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if TYPE_DEFINITIONS.contains(&parent.kind()) {
+                if let Some(name) = parent.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                    names.push(name.to_string());
+                }
+            }
+            current = parent.parent();
+        }
+
+        if names.is_empty() {
+            return None;
+        }
+        names.reverse();
+        Some(names.join("."))
+    }
+
+    /// Helper: Whether a `def` is nested in another `def` before any type definition
+    fn is_local(node: Node) -> bool {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if FUNCTION_DEFINITIONS.contains(&parent.kind()) {
+                return true;
+            }
+            if TYPE_DEFINITIONS.contains(&parent.kind()) {
+                return false;
+            }
+            current = parent.parent();
+        }
+        false
+    }
+
+    /// Helper: Classes and traits (the types an object can be a companion of)
+    fn is_class_like(class: &ClassInfo) -> bool {
+        class.metadata.get("type").map_or(false, |t| matches!(t.as_str(), "class" | "case_class" | "trait" | "enum"))
+    }
+
+    /// Helper: Descendants of the given kinds in document order, nested matches included
+    fn descendants<'t>(node: Node<'t>, kinds: &[&str]) -> Vec<Node<'t>> {
+        let mut found = Vec::new();
+        let mut stack = vec![node];
+        while let Some(current) = stack.pop() {
+            if current.id() != node.id() && kinds.contains(&current.kind()) {
+                found.push(current);
+            }
+            let mut cursor = current.walk();
+            let children: Vec<Node> = current.named_children(&mut cursor).collect();
+            stack.extend(children.into_iter().rev());
+        }
+        found
+    }
+
+    /// Helper: Nearest ancestor of the given kind
+    fn ancestor_of_kind<'t>(node: Node<'t>, kind: &str) -> Option<Node<'t>> {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if parent.kind() == kind {
+                return Some(parent);
+            }
+            current = parent.parent();
+        }
+        None
+    }
+
+    /// Calculate cyclomatic complexity for a `def`
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = node.child_by_field_name("body") {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::SCALA);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::SCALA);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::SCALA);
+
+        complexity.update_rating();
+        complexity
+    }
+
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let in_type = node.parent().map_or(false, |p| p.kind() == "template_body");
+        let ast_type = match node.kind() {
+            "class_definition" | "trait_definition" | "object_definition" | "package_object" => ASTNodeType::Class,
+            "enum_definition" => ASTNodeType::Enum,
+            "function_definition" | "function_declaration" if in_type => ASTNodeType::Method,
+            "function_definition" | "function_declaration" => ASTNodeType::Function,
+            "if_expression" => ASTNodeType::IfStatement,
+            "for_expression" | "while_expression" | "do_while_expression" => ASTNodeType::ForLoop,
+            "import_declaration" => ASTNodeType::Import,
+            "package_clause" => ASTNodeType::Namespace,
+            "val_definition" | "var_definition" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+
+            if let Some(name) = node.child_by_field_name("name").and_then(|n| n.utf8_text(source.as_bytes()).ok()) {
+                ast_node.name = name.to_string();
+            }
+
+            parent.add_child(ast_node);
+
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+/// Modifier keywords (`private[spark]`, `override`, `lazy`, ...) and annotation names of a definition
+#[derive(Default)]
+struct Modifiers {
+    keywords: Vec<String>,
+    annotations: Vec<String>,
+    /// `private` / `protected`
+    access: Option<String>,
+    /// Qualifier of a scoped access modifier (`private[spark]` -> `spark`)
+    scope: Option<String>,
+}
+
+impl Modifiers {
+    fn has(&self, keyword: &str) -> bool {
+        self.keywords.iter().any(|k| k == keyword)
+    }
+
+    /// private / protected, or public when none is given
+    fn visibility(&self) -> String {
+        self.access.clone().unwrap_or_else(|| "public".to_string())
+    }
+
+    /// `modifiers`, `visibility`, `visibility_scope`, `is_abstract` and `annotations` metadata
+    fn metadata(&self) -> Vec<(String, String)> {
+        let mut metadata = vec![("visibility".to_string(), self.visibility())];
+        if !self.keywords.is_empty() {
+            metadata.push(("modifiers".to_string(), self.keywords.join(" ")));
+        }
+        if let Some(scope) = &self.scope {
+            metadata.push(("visibility_scope".to_string(), scope.clone()));
+        }
+        if self.has("abstract") {
+            metadata.push(("is_abstract".to_string(), "true".to_string()));
+        }
+        if !self.annotations.is_empty() {
+            metadata.push(("annotations".to_string(), self.annotations.join(",")));
+        }
+        metadata
+    }
+}
+
+/// One clause of an import declaration (`import a.b.{C, D => E}, x.y._` has two)
+#[derive(Debug, PartialEq)]
+struct ImportClause {
+    path: String,
+    names: Vec<String>,
+    /// (name, alias) pairs of `D => E` / `D as E` selectors
+    renames: Vec<(String, String)>,
+    /// Names excluded with `D => _`
+    hidden: Vec<String>,
+}
+
+/// Helper: Split an import declaration into its clauses
+///
+/// `a.b.C` imports `C` from `a.b.C` (the module path is the full path, as for
+/// Kotlin); wildcards and selector lists import from the prefix path.
+fn import_clauses(text: &str) -> Vec<ImportClause> {
+    let text = text.trim();
+    let text = text.strip_prefix("import").unwrap_or(text);
+
+    let mut clauses = Vec::new();
+    for expression in split_top_level(text, ',') {
+        let expression: String = expression.split_whitespace().collect::<Vec<_>>().join(" ");
+        let mut clause = ImportClause { path: String::new(), names: Vec::new(), renames: Vec::new(), hidden: Vec::new() };
+
+        if let Some((prefix, selectors)) = expression.split_once('{') {
+            clause.path = prefix.trim().trim_end_matches('.').trim().to_string();
+            for selector in split_top_level(selectors.trim_end_matches('}'), ',') {
+                let selector = selector.trim();
+                match rename(selector) {
+                    Some((name, alias)) if alias == "_" => clause.hidden.push(name),
+                    Some((name, alias)) => {
+                        clause.names.push(name.clone());
+                        clause.renames.push((name, alias));
+                    }
+                    None if selector == "_" || selector == "*" => clause.names.push("*".to_string()),
+                    None if !selector.is_empty() => clause.names.push(selector.to_string()),
+                    None => {}
+                }
+            }
+        } else if let Some(path) = expression.strip_suffix("._").or_else(|| expression.strip_suffix(".*")) {
+            clause.path = path.trim().to_string();
+            clause.names.push("*".to_string());
+        } else {
+            // a.b.C as D (Scala 3)
+            let (path, alias) = match rename(&expression) {
+                Some((path, alias)) => (path, Some(alias)),
+                None => (expression.trim().to_string(), None),
+            };
+            let name = path.rsplit('.').next().unwrap_or(&path).trim().to_string();
+            clause.path = path.clone();
+            if let Some(alias) = alias {
+                clause.renames.push((name.clone(), alias));
+            }
+            clause.names.push(name);
+        }
+
+        if !clause.path.is_empty() {
+            clauses.push(clause);
+        }
+    }
+    clauses
+}
+
+/// Helper: `D => E` / `D as E` -> (D, E)
+fn rename(selector: &str) -> Option<(String, String)> {
+    let (name, alias) = selector.split_once("=>").or_else(|| selector.split_once(" as "))?;
+    Some((name.trim().to_string(), alias.trim().to_string()))
+}
+
+/// Helper: Supertypes of an `extends` clause, without constructor arguments
+///
+/// `extends Job(id) with Transformer[DataFrame] with Serializable` ->
+/// `Job`, `Transformer[DataFrame]`, `Serializable`; Scala 3 also separates with `,`.
+fn split_supertypes(clause: &str) -> Vec<String> {
+    let text = clause.trim();
+    let text = text.strip_prefix("extends").unwrap_or(text);
+    let chars: Vec<char> = text.chars().collect();
+
+    let mut types = Vec::new();
+    let mut current = String::new();
+    let mut arguments = 0;
+    let mut generics = 0;
+    let mut i = 0;
+    while i < chars.len() {
+        let c = chars[i];
+        i += 1;
+        if arguments > 0 {
+            match c {
+                '(' | '{' => arguments += 1,
+                ')' | '}' => arguments -= 1,
+                _ => {}
+            }
+            continue;
+        }
+        match c {
+            '(' | '{' => arguments += 1,
+            '[' => { generics += 1; current.push(c) }
+            ']' => { generics -= 1; current.push(c) }
+            ',' if generics == 0 => types.push(std::mem::take(&mut current)),
+            // ` with ` between two types
+            'w' if generics == 0
+                && (i == 1 || chars[i - 2].is_whitespace() || chars[i - 2] == ')')
+                && chars[i..].starts_with(&['i', 't', 'h'])
+                && chars.get(i + 3).map_or(true, |next| next.is_whitespace()) => {
+                types.push(std::mem::take(&mut current));
+                i += 3;
+            }
+            _ => current.push(c),
+        }
+    }
+    types.push(current);
+
+    types.into_iter()
+        .map(|t| t.split_whitespace().collect::<Vec<_>>().join(" "))
+        .filter(|t| !t.is_empty())
+        .collect()
+}
+
+/// Helper: Split at a separator outside of (), [] and {}
+fn split_top_level(text: &str, separator: char) -> Vec<&str> {
+    let mut parts = Vec::new();
+    let mut depth = 0;
+    let mut start = 0;
+    for (i, c) in text.char_indices() {
+        match c {
+            '(' | '[' | '{' => depth += 1,
+            ')' | ']' | '}' => depth -= 1,
+            c if c == separator && depth == 0 => {
+                parts.push(&text[start..i]);
+                start = i + c.len_utf8();
+            }
+            _ => {}
+        }
+    }
+    parts.push(&text[start..]);
+    parts
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterScalaAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Scala
+    }
+
+    fn get_language_name(&self) -> &'static str {
+        "Scala (Tree-sitter)"
+    }
+
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".scala", ".sc"]
+    }
+
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Scala);
+
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Scala file"))?;
+        let parse_duration = parse_start.elapsed();
+
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER SCALA] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+
+        // Extract all constructs
+        let extract_start = std::time::Instant::now();
+        result.functions = self.extract_functions(&tree, content)?;
+        result.classes = self.extract_classes(&tree, content)?;
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content, &result.classes)?;
+
+        // package a.b; package c -> a.b.c
+        let packages: Vec<String> = Self::descendants(tree.root_node(), &["package_clause"]).into_iter()
+            .filter(|p| self.enclosing_type_name(*p, content).is_none())
+            .filter_map(|p| p.child_by_field_name("name"))
+            .filter_map(|name| name.utf8_text(content.as_bytes()).ok())
+            .map(str::to_string)
+            .collect();
+        if !packages.is_empty() {
+            result.metadata.insert("package".to_string(), packages.join("."));
+        }
+        let extract_duration = extract_start.elapsed();
+
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER SCALA] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+
+        finish_analysis(&tree, content, &mut result, &magic_numbers::SCALA);
+
+        Ok(result)
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_split_supertypes() {
+        assert_eq!(
+            split_supertypes("extends Job(id, { x => x }) with Transformer[Map[String, Int]] with Serializable"),
+            vec!["Job", "Transformer[Map[String, Int]]", "Serializable"],
+        );
+        assert_eq!(split_supertypes("extends A, B"), vec!["A", "B"]);
+        assert_eq!(split_supertypes("extends Widthable"), vec!["Widthable"]);
+    }
+
+    #[test]
+    fn test_import_clauses() {
+        let clauses = import_clauses("import org.apache.spark.sql.{DataFrame, SparkSession => Session, Row => _}, scala.util._");
+        assert_eq!(clauses, vec![
+            ImportClause {
+                path: "org.apache.spark.sql".to_string(),
+                names: vec!["DataFrame".to_string(), "SparkSession".to_string()],
+                renames: vec![("SparkSession".to_string(), "Session".to_string())],
+                hidden: vec!["Row".to_string()],
+            },
+            ImportClause { path: "scala.util".to_string(), names: vec!["*".to_string()], renames: Vec::new(), hidden: Vec::new() },
+        ]);
+
+        let renamed = import_clauses("import scala.collection.mutable as m");
+        assert_eq!(renamed[0].path, "scala.collection.mutable");
+        assert_eq!(renamed[0].names, vec!["mutable"]);
+        assert_eq!(renamed[0].renames, vec![("mutable".to_string(), "m".to_string())]);
+    }
+}
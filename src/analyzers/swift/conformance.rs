@@ -0,0 +1,138 @@
+//! Swift protocol conformance
+//!
+//! Swift conformances are declared, not inferred: `struct S: P`, `class C: Base, P`
+//! and `extension S: Q` list protocols after the colon. A class's first entry is
+//! its superclass unless it names a protocol, which is only known once every
+//! file's protocols are collected. Conformances added by extensions are credited
+//! to the extended type as well, and each protocol lists the types conforming to it.
+
+use std::collections::{BTreeSet, HashMap, HashSet};
+
+use crate::core::types::{AnalysisResult, ClassInfo, Language};
+
+/// Fill `implements` / `implementors` on Swift types across the given files
+pub fn link_conformances(files: &mut [AnalysisResult]) {
+    let protocols: HashSet<String> = files.iter()
+        .filter(|f| f.language == Language::Swift)
+        .flat_map(|f| f.classes.iter())
+        .filter(|c| is_protocol(c))
+        .map(|c| c.name.clone())
+        .collect();
+    
+    // Type name -> protocols from its declaration and all its extensions, in declaration order
+    let mut conformances: HashMap<String, Vec<String>> = HashMap::new();
+    for file in files.iter_mut().filter(|f| f.language == Language::Swift) {
+        for class in file.classes.iter_mut().filter(|c| !is_protocol(c)) {
+            // `class C: P` with no superclass
+            if let Some(parent) = class.parent_class.take() {
+                if protocols.contains(&parent) {
+                    if !class.implements.contains(&parent) {
+                        class.implements.insert(0, parent);
+                    }
+                } else {
+                    class.parent_class = Some(parent);
+                }
+            }
+            
+            // Already-linked results only ever hold conformances that apply, so
+            // linking again (per file, then per directory) is idempotent
+            let entry = conformances.entry(class.name.clone()).or_default();
+            for protocol in &class.implements {
+                if !entry.contains(protocol) {
+                    entry.push(protocol.clone());
+                }
+            }
+        }
+    }
+    
+    let mut implementors: HashMap<&str, BTreeSet<&str>> = HashMap::new();
+    for (type_name, protocols) in &conformances {
+        for protocol in protocols {
+            implementors.entry(protocol.as_str()).or_default().insert(type_name.as_str());
+        }
+    }
+    
+    for file in files.iter_mut().filter(|f| f.language == Language::Swift) {
+        for class in &mut file.classes {
+            if is_protocol(class) {
+                class.implementors = implementors.get(class.name.as_str())
+                    .map(|set| set.iter().map(|s| s.to_string()).collect())
+                    .unwrap_or_default();
+            } else if !is_extension(class) {
+                if let Some(protocols) = conformances.get(&class.name) {
+                    class.implements = protocols.clone();
+                }
+            }
+        }
+    }
+}
+
+/// Helper: Class metadata "type" marks a protocol
+fn is_protocol(class: &ClassInfo) -> bool {
+    class.metadata.get("type").map_or(false, |t| t == "protocol")
+}
+
+/// Helper: Class metadata "type" marks an extension
+fn is_extension(class: &ClassInfo) -> bool {
+    class.metadata.get("type").map_or(false, |t| t == "extension")
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::FileInfo;
+    use std::path::PathBuf;
+    
+    fn declaration(kind: &str, name: &str, supertypes: &[&str]) -> ClassInfo {
+        let mut class = ClassInfo::new(name.to_string());
+        class.metadata.insert("type".to_string(), kind.to_string());
+        let mut supertypes: Vec<String> = supertypes.iter().map(|s| s.to_string()).collect();
+        if kind == "class" && !supertypes.is_empty() {
+            class.parent_class = Some(supertypes.remove(0));
+        }
+        class.implements = supertypes;
+        class
+    }
+    
+    #[test]
+    fn test_link_conformances() {
+        let mut a = AnalysisResult::new(FileInfo::new(PathBuf::from("Protocols.swift")), Language::Swift);
+        a.classes = vec![
+            declaration("protocol", "Drawable", &[]),
+            declaration("protocol", "Named", &[]),
+        ];
+        
+        let mut b = AnalysisResult::new(FileInfo::new(PathBuf::from("Shapes.swift")), Language::Swift);
+        b.classes = vec![
+            declaration("class", "Shape", &["Drawable"]),
+            declaration("class", "Circle", &["Shape", "Named"]),
+            declaration("struct", "Tag", &["Hashable"]),
+        ];
+        
+        let mut c = AnalysisResult::new(FileInfo::new(PathBuf::from("Tag+Drawable.swift")), Language::Swift);
+        c.classes = vec![declaration("extension", "Tag", &["Drawable", "Named"])];
+        
+        let mut files = vec![a, b, c];
+        link_conformances(&mut files);
+        // Per-file then per-directory linking gives the same result
+        link_conformances(&mut files);
+        
+        let class = |file: usize, name: &str| files[file].classes.iter().find(|c| c.name == name).unwrap().clone();
+        
+        // Drawable is a protocol, not Shape's superclass
+        let shape = class(1, "Shape");
+        assert_eq!(shape.parent_class, None);
+        assert_eq!(shape.implements, vec!["Drawable"]);
+        
+        let circle = class(1, "Circle");
+        assert_eq!(circle.parent_class.as_deref(), Some("Shape"));
+        assert_eq!(circle.implements, vec!["Named"]);
+        
+        // Extension conformances are credited to the extended type
+        assert_eq!(class(1, "Tag").implements, vec!["Hashable", "Drawable", "Named"]);
+        assert_eq!(class(2, "Tag").implements, vec!["Drawable", "Named"]);
+        
+        assert_eq!(class(0, "Drawable").implementors, vec!["Shape", "Tag"]);
+        assert_eq!(class(0, "Named").implementors, vec!["Circle", "Tag"]);
+    }
+}
@@ -0,0 +1,5 @@
+pub mod tree_sitter_analyzer;
+pub mod conformance;
+
+pub use tree_sitter_analyzer::TreeSitterSwiftAnalyzer;
+pub use conformance::link_conformances;
@@ -0,0 +1,740 @@
+//! 🚀 Tree-sitter based Swift analyzer
+//! Classes, structs, enums, actors, protocols and extensions (nested ones as `Outer.Inner`),
+//! functions, methods, initializers, stored and computed properties, `static` / `class`
+//! members, imports and calls. Declared conformances are linked to protocols in `conformance.rs`.
+
+use anyhow::Result;
+use tree_sitter::{Parser, Query, QueryCursor, Node};
+use async_trait::async_trait;
+use std::collections::HashMap;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable, ParameterInfo
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::tree_sitter_util::has_keyword;
+use crate::analyzers::swift::conformance::link_conformances;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Declarations that introduce a type (and a level of `Outer.Inner` nesting)
+const TYPE_DECLARATIONS: &[&str] = &["class_declaration", "protocol_declaration"];
+
+/// Standard library types an enum's first supertype names as its raw value type
+const RAW_VALUE_TYPES: &[&str] = &[
+    "String", "Character", "Int", "Int8", "Int16", "Int32", "Int64",
+    "UInt", "UInt8", "UInt16", "UInt32", "UInt64", "Double", "Float", "CGFloat",
+];
+
+pub struct TreeSitterSwiftAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterSwiftAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_swift::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Swift language: {:?}", e))?;
+        
+        Ok(Self { parser })
+    }
+    
+    /// Extract top-level, member and local functions using tree-sitter query
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+        let mut functions = Vec::new();
+        
+        let query_str = r#"
+            (function_declaration
+              name: (simple_identifier) @name) @function
+        "#;
+        
+        let query = Query::new(&tree_sitter_swift::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            let mut name = None;
+            let mut func_node = None;
+            
+            for capture in mat.captures {
+                match query.capture_names()[capture.index as usize].as_ref() {
+                    "name" => name = Some(capture.node.utf8_text(source.as_bytes())?.to_string()),
+                    "function" => func_node = Some(capture.node),
+                    _ => {}
+                }
+            }
+            
+            let (Some(name), Some(node)) = (name, func_node) else { continue };
+            let mut func_info = self.build_function_info(node, name, source)?;
+            if let Some(owner) = self.enclosing_type_name(node, source) {
+                func_info.metadata.insert("is_method".to_string(), "true".to_string());
+                func_info.metadata.insert("class_name".to_string(), owner);
+            }
+            functions.push(func_info);
+        }
+        
+        Ok(functions)
+    }
+    
+    /// Extract classes, structs, enums, actors, extensions and protocols
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+        
+        let query_str = r#"
+            [
+              (class_declaration) @class
+              (protocol_declaration) @protocol
+            ]
+        "#;
+        
+        let query = Query::new(&tree_sitter_swift::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            for capture in mat.captures {
+                let node = capture.node;
+                let Some(name) = self.field_text(node, "name", source) else { continue };
+                // class / struct / enum / actor / extension / protocol
+                let kind = self.field_text(node, "declaration_kind", source).unwrap_or_else(|| "class".to_string());
+                
+                let mut class_info = ClassInfo::new(name);
+                class_info.start_line = node.start_position().row as u32 + 1;
+                class_info.span = node_span(node, source);
+                class_info.end_line = node.end_position().row as u32 + 1;
+                class_info.metadata.insert("type".to_string(), kind.clone());
+                
+                if kind == "extension" {
+                    // `extension Foo: Bar` reopens Foo; the extended type is the name
+                    class_info.metadata.insert("extended_type".to_string(), class_info.name.clone());
+                } else if let Some(outer) = self.enclosing_type_name(node, source) {
+                    // Nested types: Outer.Inner
+                    class_info.metadata.insert("outer_class".to_string(), outer.clone());
+                    class_info.metadata.insert("simple_name".to_string(), class_info.name.clone());
+                    class_info.name = format!("{}.{}", outer, class_info.name);
+                }
+                
+                self.extract_supertypes(node, &kind, source, &mut class_info)?;
+                class_info.metadata.extend(self.declaration_metadata(node, source));
+                
+                if let Some(body) = node.child_by_field_name("body") {
+                    self.extract_class_body(body, source, &mut class_info)?;
+                }
+                
+                classes.push(class_info);
+            }
+        }
+        
+        Ok(classes)
+    }
+    
+    /// Helper: Names after `:` in a type header, split into superclass, raw type and conformances
+    ///
+    /// Only a class has a superclass, and only as its first entry; whether that entry is
+    /// a protocol instead is settled by `link_conformances` once all protocols are known.
+    fn extract_supertypes(&self, node: Node, kind: &str, source: &str, class_info: &mut ClassInfo) -> Result<()> {
+        let mut supertypes = Vec::new();
+        
+        let mut cursor = node.walk();
+        for specifier in node.named_children(&mut cursor).filter(|c| c.kind() == "inheritance_specifier") {
+            let inherited = specifier.child_by_field_name("inherits_from").unwrap_or(specifier);
+            supertypes.push(Self::normalize_type(inherited.utf8_text(source.as_bytes())?));
+        }
+        
+        if supertypes.is_empty() {
+            return Ok(());
+        }
+        
+        match kind {
+            "class" => {
+                class_info.parent_class = Some(supertypes.remove(0));
+                class_info.implements = supertypes;
+            }
+            "enum" if RAW_VALUE_TYPES.contains(&supertypes[0].as_str()) => {
+                class_info.metadata.insert("raw_type".to_string(), supertypes.remove(0));
+                class_info.implements = supertypes;
+            }
+            // Protocols refine other protocols
+            "protocol" => {
+                class_info.metadata.insert("interfaces".to_string(), supertypes.join(", "));
+            }
+            // Structs, enums, actors and extensions only conform to protocols
+            _ => class_info.implements = supertypes,
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Methods, initializers, properties and enum cases declared in a type body
+    fn extract_class_body(&self, body: Node, source: &str, class_info: &mut ClassInfo) -> Result<()> {
+        let is_protocol = class_info.metadata.get("type").map_or(false, |t| t == "protocol");
+        
+        let mut cursor = body.walk();
+        for child in body.named_children(&mut cursor) {
+            match child.kind() {
+                "function_declaration" | "protocol_function_declaration" => {
+                    let Some(name) = self.field_text(child, "name", source) else { continue };
+                    let mut method = self.build_function_info(child, name, source)?;
+                    method.metadata.insert("is_method".to_string(), "true".to_string());
+                    if is_protocol {
+                        method.metadata.insert("is_interface_method".to_string(), "true".to_string());
+                        if child.child_by_field_name("body").is_none() {
+                            method.metadata.insert("is_abstract".to_string(), "true".to_string());
+                        }
+                    }
+                    class_info.methods.push(method);
+                }
+                "init_declaration" | "deinit_declaration" | "subscript_declaration" => {
+                    let name = match child.kind() {
+                        "init_declaration" => "init",
+                        "deinit_declaration" => "deinit",
+                        _ => "subscript",
+                    };
+                    let mut method = self.build_function_info(child, name.to_string(), source)?;
+                    method.metadata.insert("is_method".to_string(), "true".to_string());
+                    class_info.methods.push(method);
+                }
+                "property_declaration" | "protocol_property_declaration" => {
+                    let mut properties = self.extract_properties(child, source)?;
+                    for property in &mut properties {
+                        if is_protocol {
+                            // Protocol requirements are as visible as the protocol
+                            property.access_modifier = "public".to_string();
+                        }
+                    }
+                    class_info.member_variables.extend(properties);
+                }
+                "enum_entry" => {
+                    class_info.member_variables.extend(self.extract_enum_cases(child, &class_info.name, source)?);
+                }
+                _ => {}
+            }
+        }
+        
+        Ok(())
+    }
+    
+    /// Helper: Member variables of a `let` / `var` declaration (`var a = 1, b = 2` yields two)
+    fn extract_properties(&self, node: Node, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut properties = Vec::new();
+        let modifiers = self.extract_modifiers(node, source);
+        
+        let mut binding_cursor = node.walk();
+        let binding = node.named_children(&mut binding_cursor)
+            .find(|c| c.kind() == "value_binding_pattern")
+            .and_then(|b| b.utf8_text(source.as_bytes()).ok())
+            .map(|b| b.split_whitespace().last().unwrap_or(b).to_string());
+        
+        // Each `name` is followed by its own type annotation and value / accessors
+        let mut cursor = node.walk();
+        let mut children = Vec::new();
+        if cursor.goto_first_child() {
+            loop {
+                children.push((cursor.field_name(), cursor.node()));
+                if !cursor.goto_next_sibling() {
+                    break;
+                }
+            }
+        }
+        
+        let mut current: Option<MemberVariable> = None;
+        for (field, child) in children {
+            if field == Some("name") {
+                properties.extend(current.take());
+                let pattern = child.child_by_field_name("bound_identifier").unwrap_or(child);
+                let name = pattern.utf8_text(source.as_bytes())?.to_string();
+                
+                let mut member = MemberVariable::new(name, String::new(), child.start_position().row as u32 + 1);
+                member.span = node_span(node, source);
+                member.access_modifier = modifiers.visibility();
+                member.is_static = modifiers.has("static") || modifiers.has("class");
+                member.is_const = binding.as_deref() == Some("let");
+                if let Some(binding) = &binding {
+                    member.metadata.insert("binding".to_string(), binding.clone());
+                }
+                if !modifiers.keywords.is_empty() {
+                    member.metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+                }
+                if !modifiers.annotations.is_empty() {
+                    member.metadata.insert("annotations".to_string(), modifiers.annotations.join(","));
+                }
+                current = Some(member);
+                continue;
+            }
+            
+            let Some(member) = current.as_mut() else { continue };
+            match (field, child.kind()) {
+                (_, "type_annotation") => {
+                    if let Some(var_type) = child.child_by_field_name("type") {
+                        member.var_type = Self::normalize_type(var_type.utf8_text(source.as_bytes())?);
+                    }
+                }
+                // var area: Double { width * height }
+                (Some("computed_value"), _) | (_, "computed_property") => {
+                    member.metadata.insert("is_computed".to_string(), "true".to_string());
+                    let has_setter = Self::has_descendant(child, "computed_setter");
+                    member.metadata.insert("accessors".to_string(), if has_setter { "get set" } else { "get" }.to_string());
+                    member.is_const = !has_setter;
+                }
+                (_, "willset_didset_block") => {
+                    member.metadata.insert("has_observers".to_string(), "true".to_string());
+                }
+                // Protocol requirements: { get } / { get set }
+                (_, "protocol_property_requirements") => {
+                    let text = child.utf8_text(source.as_bytes())?;
+                    let accessors: Vec<&str> = text.trim_matches(|c| c == '{' || c == '}').split_whitespace().collect();
+                    member.metadata.insert("accessors".to_string(), accessors.join(" "));
+                    member.metadata.insert("is_requirement".to_string(), "true".to_string());
+                }
+                _ => {}
+            }
+        }
+        properties.extend(current);
+        
+        Ok(properties)
+    }
+    
+    /// Helper: Enum cases of an `enum_entry` (`case red, green` yields two)
+    fn extract_enum_cases(&self, entry: Node, enum_name: &str, source: &str) -> Result<Vec<MemberVariable>> {
+        let mut cases = Vec::new();
+        
+        let mut cursor = entry.walk();
+        let mut last: Option<MemberVariable> = None;
+        if cursor.goto_first_child() {
+            loop {
+                let child = cursor.node();
+                match cursor.field_name() {
+                    Some("name") => {
+                        cases.extend(last.take());
+                        let mut member = MemberVariable::new(child.utf8_text(source.as_bytes())?.to_string(), enum_name.to_string(), child.start_position().row as u32 + 1);
+                        member.span = node_span(child, source);
+                        member.access_modifier = "public".to_string();
+                        member.is_static = true;
+                        member.is_const = true;
+                        member.metadata.insert("enum_case".to_string(), "true".to_string());
+                        last = Some(member);
+                    }
+                    // case success(Data) / case low = 1
+                    Some("data_contents") | Some("raw_value") => {
+                        if let Some(member) = last.as_mut() {
+                            let key = if cursor.field_name() == Some("raw_value") { "raw_value" } else { "associated_values" };
+                            member.metadata.insert(key.to_string(), Self::normalize_type(child.utf8_text(source.as_bytes())?));
+                        }
+                    }
+                    _ => {}
+                }
+                if !cursor.goto_next_sibling() {
+                    break;
+                }
+            }
+        }
+        cases.extend(last);
+        
+        Ok(cases)
+    }
+    
+    /// Extract import declarations (`import Foundation`, `import struct Module.Type`)
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+        
+        let root = tree.root_node();
+        let mut cursor = root.walk();
+        for node in root.named_children(&mut cursor).filter(|n| n.kind() == "import_declaration") {
+            let mut child_cursor = node.walk();
+            let Some(path) = node.named_children(&mut child_cursor).find(|c| c.kind() == "identifier") else { continue };
+            let path = path.utf8_text(source.as_bytes())?.split_whitespace().collect::<String>();
+            
+            let mut import_info = ImportInfo::new(ImportType::SwiftImport, path.clone());
+            import_info.line_number = node.start_position().row as u32 + 1;
+            let name = path.rsplit('.').next().unwrap_or(&path);
+            import_info.imported_names.push(name.to_string());
+            
+            // Scoped imports name the kind of symbol: import struct / class / func ...
+            let mut keyword_cursor = node.walk();
+            let scope = node.children(&mut keyword_cursor)
+                .filter(|c| !c.is_named())
+                .map(|c| c.kind())
+                .find(|k| matches!(*k, "typealias" | "struct" | "class" | "enum" | "protocol" | "let" | "var" | "func"));
+            if let Some(scope) = scope {
+                import_info.metadata.insert("import_kind".to_string(), scope.to_string());
+            }
+            imports.push(import_info);
+        }
+        
+        Ok(imports)
+    }
+    
+    /// Extract calls (`f()`, `obj.m()`, `obj?.m()`, `Type()`, and trailing-closure `xs.map { }`)
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionCall>> {
+        let mut function_calls = Vec::new();
+        
+        let query_str = r#"
+            (call_expression) @call
+        "#;
+        
+        let query = Query::new(&tree_sitter_swift::LANGUAGE.into(), query_str)?;
+        let mut cursor = QueryCursor::new();
+        let matches = cursor.matches(&query, tree.root_node(), source.as_bytes());
+        
+        for mat in matches {
+            for capture in mat.captures {
+                let node = capture.node;
+                let Some(callee) = node.named_child(0) else { continue };
+                
+                let function_call = match callee.kind() {
+                    "simple_identifier" => {
+                        FunctionCall::new(callee.utf8_text(source.as_bytes())?.to_string(), node.start_position().row as u32 + 1)
+                    }
+                    // target.name / target?.name
+                    "navigation_expression" => {
+                        let (Some(target), Some(suffix)) = (callee.child_by_field_name("target"), callee.child_by_field_name("suffix")) else { continue };
+                        let name = suffix.child_by_field_name("suffix").unwrap_or(suffix);
+                        let name = name.utf8_text(source.as_bytes())?.trim_start_matches('.').to_string();
+                        
+                        let mut call = FunctionCall::new(name, node.start_position().row as u32 + 1);
+                        call.object_name = Some(target.utf8_text(source.as_bytes())?.trim_end_matches('?').to_string());
+                        call.is_method_call = true;
+                        call
+                    }
+                    _ => continue,
+                };
+                function_calls.push(function_call);
+            }
+        }
+        
+        Ok(function_calls)
+    }
+    
+    /// Helper: Build FunctionInfo for a function, initializer or protocol requirement node
+    fn build_function_info(&self, node: Node, name: String, source: &str) -> Result<FunctionInfo> {
+        let mut func_info = FunctionInfo::new(name);
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+        
+        let kind = match node.kind() {
+            "init_declaration" => "constructor",
+            "deinit_declaration" => "destructor",
+            "subscript_declaration" => "subscript",
+            _ => "function",
+        };
+        func_info.metadata.insert("type".to_string(), kind.to_string());
+        
+        // func name(label param: Type, _ rest: Int...) async throws -> Return
+        let mut cursor = node.walk();
+        for child in node.named_children(&mut cursor) {
+            match child.kind() {
+                "parameter" => {
+                    let text = child.utf8_text(source.as_bytes())?;
+                    func_info.parameters.push(Self::normalize_type(text));
+                    func_info.params.push(self.parameter_info(child, source)?);
+                }
+                "throws" => {
+                    func_info.metadata.insert("throws".to_string(), "true".to_string());
+                }
+                _ => {}
+            }
+        }
+        if has_keyword(node, "async") {
+            func_info.is_async = true;
+        }
+        if let Some(return_type) = node.child_by_field_name("return_type") {
+            let return_type = Self::normalize_type(return_type.utf8_text(source.as_bytes())?);
+            func_info.metadata.insert("return_type".to_string(), return_type.clone());
+            func_info.returns.push(return_type);
+        }
+        
+        let metadata = self.declaration_metadata(node, source);
+        // `static func` / `class func` (overridable type method)
+        let modifiers = metadata.get("modifiers").map(|m| m.split_whitespace().collect::<Vec<_>>()).unwrap_or_default();
+        let static_kind = if modifiers.contains(&"class") || has_keyword(node, "class") {
+            Some("class")
+        } else if modifiers.contains(&"static") {
+            Some("static")
+        } else {
+            None
+        };
+        if let Some(static_kind) = static_kind {
+            func_info.metadata.insert("is_static".to_string(), "true".to_string());
+            func_info.metadata.insert("static_kind".to_string(), static_kind.to_string());
+        }
+        if modifiers.contains(&"mutating") {
+            func_info.metadata.insert("is_mutating".to_string(), "true".to_string());
+        }
+        func_info.metadata.extend(metadata);
+        
+        Ok(func_info)
+    }
+    
+    /// Helper: Name, type and variadic flag of a `parameter` node
+    fn parameter_info(&self, param: Node, source: &str) -> Result<ParameterInfo> {
+        let name = match param.child_by_field_name("name") {
+            Some(name) => name.utf8_text(source.as_bytes())?.to_string(),
+            None => String::new(),
+        };
+        let param_type = match param.child_by_field_name("type") {
+            Some(param_type) => Self::normalize_type(param_type.utf8_text(source.as_bytes())?),
+            None => String::new(),
+        };
+        
+        // `values: Int...`
+        let variadic = param.utf8_text(source.as_bytes())?.trim_end().ends_with("...");
+        let param_type = if variadic { format!("{}...", param_type) } else { param_type };
+        
+        Ok(ParameterInfo { name, param_type, variadic })
+    }
+    
+    /// Helper: `modifiers`, `visibility`, `is_abstract` and `annotations` metadata
+    fn declaration_metadata(&self, node: Node, source: &str) -> HashMap<String, String> {
+        let modifiers = self.extract_modifiers(node, source);
+        let mut metadata = HashMap::new();
+        
+        if !modifiers.keywords.is_empty() {
+            metadata.insert("modifiers".to_string(), modifiers.keywords.join(" "));
+        }
+        metadata.insert("visibility".to_string(), modifiers.visibility());
+        if modifiers.has("final") {
+            metadata.insert("is_final".to_string(), "true".to_string());
+        }
+        if modifiers.has("override") {
+            metadata.insert("is_override".to_string(), "true".to_string());
+        }
+        if !modifiers.annotations.is_empty() {
+            metadata.insert("annotations".to_string(), modifiers.annotations.join(","));
+        }
+        
+        metadata
+    }
+    
+    /// Helper: Modifier keywords and attribute names of a declaration
+    fn extract_modifiers(&self, node: Node, source: &str) -> Modifiers {
+        let mut modifiers = Modifiers::default();
+        
+        let mut cursor = node.walk();
+        let modifiers_node = node.children(&mut cursor).find(|child| child.kind() == "modifiers");
+        if let Some(modifiers_node) = modifiers_node {
+            let mut cursor = modifiers_node.walk();
+            for child in modifiers_node.named_children(&mut cursor) {
+                let Ok(text) = child.utf8_text(source.as_bytes()) else { continue };
+                if child.kind() == "attribute" {
+                    // @available(iOS 15, *) / @MainActor -> available / MainActor
+                    let name = text.trim_start_matches('@');
+                    let name = name.split('(').next().unwrap_or(name).trim();
+                    modifiers.annotations.push(name.to_string());
+                } else {
+                    // private(set) restricts the setter only
+                    modifiers.keywords.extend(text.split_whitespace().map(str::to_string));
+                }
+            }
+        }
+        
+        modifiers
+    }
+    
+    /// Helper: Text of a field child
+    fn field_text(&self, node: Node, field: &str, source: &str) -> Option<String> {
+        node.child_by_field_name(field)
+            .and_then(|child| child.utf8_text(source.as_bytes()).ok())
+            .map(Self::normalize_type)
+    }
+    
+    /// Helper: Whether any node of the given kind occurs in the subtree
+    fn has_descendant(node: Node, kind: &str) -> bool {
+        if node.kind() == kind {
+            return true;
+        }
+        let mut cursor = node.walk();
+        let found = node.named_children(&mut cursor).any(|child| Self::has_descendant(child, kind));
+        found
+    }
+    
+    /// Helper: Collapse whitespace inside a type expression
+    fn normalize_type(type_text: &str) -> String {
+        type_text.split_whitespace().collect::<Vec<_>>().join(" ")
+    }
+    
+    /// Helper: Qualified name of the nearest enclosing type or extension (`Outer.Inner`)
+    fn enclosing_type_name(&self, node: Node, source: &str) -> Option<String> {
+        let mut names = Vec::new();
+        
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            // Functions declared inside function bodies belong to that function, not a type
+            if parent.kind() == "function_body" {
+                break;
+            }
+            if TYPE_DECLARATIONS.contains(&parent.kind()) {
+                if let Some(name) = self.field_text(parent, "name", source) {
+                    names.push(name);
+                }
+            }
+            current = parent.parent();
+        }
+        
+        if names.is_empty() {
+            return None;
+        }
+        names.reverse();
+        Some(names.join("."))
+    }
+    
+    /// Calculate cyclomatic complexity for a function
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+        
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = node.child_by_field_name("body") {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::SWIFT);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::SWIFT);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::SWIFT);
+        
+        complexity.update_rating();
+        complexity
+    }
+    
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+    
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let ast_type = match node.kind() {
+            "function_declaration" | "init_declaration" | "deinit_declaration" => ASTNodeType::Function,
+            "class_declaration" | "protocol_declaration" => ASTNodeType::Class,
+            "if_statement" | "guard_statement" => ASTNodeType::IfStatement,
+            "for_statement" | "while_statement" | "repeat_while_statement" => ASTNodeType::ForLoop,
+            "import_declaration" => ASTNodeType::Import,
+            "property_declaration" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+        
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+            
+            let name = match node.kind() {
+                "class_declaration" | "protocol_declaration" | "function_declaration" => self.field_text(node, "name", source),
+                "init_declaration" => Some("init".to_string()),
+                "deinit_declaration" => Some("deinit".to_string()),
+                _ => None,
+            };
+            if let Some(name) = name {
+                ast_node.name = name;
+            }
+            
+            parent.add_child(ast_node);
+            
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+            
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+/// Modifier keywords (`private`, `static`, `override`, ...) and attribute names of a declaration
+#[derive(Default)]
+struct Modifiers {
+    keywords: Vec<String>,
+    annotations: Vec<String>,
+}
+
+impl Modifiers {
+    fn has(&self, keyword: &str) -> bool {
+        self.keywords.iter().any(|k| k == keyword)
+    }
+    
+    /// open / public / package / fileprivate / private, or internal when none is given
+    fn visibility(&self) -> String {
+        // `private(set) var` keeps the getter's visibility
+        self.keywords.iter()
+            .find(|k| matches!(k.as_str(), "open" | "public" | "package" | "internal" | "fileprivate" | "private"))
+            .map_or("internal", |k| k.as_str())
+            .to_string()
+    }
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterSwiftAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Swift
+    }
+    
+    fn get_language_name(&self) -> &'static str {
+        "Swift (Tree-sitter)"
+    }
+    
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".swift"]
+    }
+    
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+        
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Swift);
+        
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Swift file"))?;
+        let parse_duration = parse_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER SWIFT] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Extract all constructs
+        let extract_start = std::time::Instant::now();
+        result.functions = self.extract_functions(&tree, content)?;
+        result.classes = self.extract_classes(&tree, content)?;
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content)?;
+        let extract_duration = extract_start.elapsed();
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER SWIFT] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+        
+        // Conformances declared within this file (the directory pass links across files)
+        link_conformances(std::slice::from_mut(&mut result));
+        
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+        
+        finish_analysis(&tree, content, &mut result, &magic_numbers::SWIFT);
+        
+        Ok(result)
+    }
+}
@@ -0,0 +1,17 @@
+//! Small node lookups shared by the tree-sitter analyzers
+
+use tree_sitter::Node;
+
+/// First direct named child of the given kind
+pub fn child_of_kind<'t>(node: Node<'t>, kind: &str) -> Option<Node<'t>> {
+    let mut cursor = node.walk();
+    let found = node.named_children(&mut cursor).find(|c| c.kind() == kind);
+    found
+}
+
+/// Whether an anonymous token (a keyword such as `async`, `local`, `val`) is a direct child
+pub fn has_keyword(node: Node, keyword: &str) -> bool {
+    let mut cursor = node.walk();
+    let found = node.children(&mut cursor).any(|c| !c.is_named() && c.kind() == keyword);
+    found
+}
@@ -0,0 +1,47 @@
+//! Zig `@import` paths and import-to-file matching
+//!
+//! `@import("geo/point.zig")` names a file relative to the importing file;
+//! `@import("std")` and other bare names are modules wired up by the build,
+//! whose root file is matched by its stem.
+
+use std::path::{Component, Path, PathBuf};
+
+/// Whether `import`, written in `importer`, denotes the source file `path`
+pub fn import_matches_path(import: &str, importer: &Path, path: &Path) -> bool {
+    if import.ends_with(".zig") {
+        let resolved = importer.parent().unwrap_or(Path::new("")).join(import);
+        return normalize(&resolved) == normalize(path);
+    }
+    path.file_stem().is_some_and(|stem| stem == import)
+}
+
+/// Resolve `.` and `..` lexically (the files need not exist)
+pub fn normalize(path: &Path) -> PathBuf {
+    let mut normalized = PathBuf::new();
+    for component in path.components() {
+        match component {
+            Component::CurDir => {}
+            Component::ParentDir => {
+                normalized.pop();
+            }
+            other => normalized.push(other),
+        }
+    }
+    normalized
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_import_matches_path() {
+        let importer = Path::new("/repo/src/main.zig");
+        assert!(import_matches_path("geo.zig", importer, Path::new("/repo/src/geo.zig")));
+        assert!(import_matches_path("./shapes/point.zig", importer, Path::new("/repo/src/shapes/point.zig")));
+        assert!(import_matches_path("../lib/util.zig", importer, Path::new("/repo/lib/util.zig")));
+        assert!(import_matches_path("geo", importer, Path::new("/repo/geo/geo.zig")));
+        assert!(!import_matches_path("geo.zig", importer, Path::new("/repo/lib/geo.zig")));
+        assert!(!import_matches_path("std", importer, Path::new("/repo/src/geo.zig")));
+    }
+}
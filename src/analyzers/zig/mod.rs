@@ -0,0 +1,4 @@
+pub mod tree_sitter_analyzer;
+pub mod imports;
+
+pub use tree_sitter_analyzer::TreeSitterZigAnalyzer;
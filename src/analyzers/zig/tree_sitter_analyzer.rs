@@ -0,0 +1,613 @@
+//! 🚀 Tree-sitter based Zig analyzer
+//! `fn` declarations at file level and inside `struct` / `union` / `enum` /
+//! `opaque` containers, the containers themselves (named by the `const` they
+//! are bound to, nested ones as `Outer.Inner`), `pub` visibility, `comptime`
+//! parameters and type-returning functions, `@import` bindings and calls.
+//!
+//! A container function taking the container (`self: *Self`, `p: Point`) as
+//! its first parameter is a method; any other is a namespaced (static)
+//! function. `name.f()` through an `@import` binding is a call into that file.
+
+use std::collections::HashMap;
+
+use anyhow::Result;
+use tree_sitter::{Parser, Node};
+use async_trait::async_trait;
+
+use crate::core::types::{
+    AnalysisResult, ClassInfo, FileInfo, FunctionInfo, ImportInfo, FunctionCall,
+    Language, ComplexityInfo, ImportType, MemberVariable, ParameterInfo
+};
+use crate::core::ast::{ASTNode, ASTNodeType, ASTStatistics};
+use crate::analyzers::traits::LanguageAnalyzer;
+use crate::analyzers::tree_sitter_util::child_of_kind;
+use crate::analyzers::common::finish_analysis;
+use crate::metrics::{body_hash, clone_tokens, cognitive, cognitive_complexity, cyclomatic, decision_points, ComplexityWeights, magic_numbers, max_nesting_depth, node_span, raw_hash};
+
+/// Container declarations: types with fields, methods and nested declarations
+const CONTAINERS: &[&str] = &["struct_declaration", "union_declaration", "enum_declaration", "opaque_declaration"];
+
+/// Modifier keywords of a `fn` declaration
+const FUNCTION_MODIFIERS: &[&str] = &["export", "extern", "inline", "noinline"];
+
+pub struct TreeSitterZigAnalyzer {
+    parser: Parser,
+}
+
+impl TreeSitterZigAnalyzer {
+    pub fn new() -> Result<Self> {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_zig::LANGUAGE.into())
+            .map_err(|e| anyhow::anyhow!("Failed to set Zig language: {:?}", e))?;
+
+        Ok(Self { parser })
+    }
+
+    /// Extract every `fn`, container functions with their owner
+    fn extract_functions(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<FunctionInfo>> {
+        // const Self = @This();
+        let self_aliases: Vec<String> = Self::descendants(tree.root_node(), &["variable_declaration"]).into_iter()
+            .filter(|declaration| Self::value_text(*declaration, source).as_deref() == Some("@This()"))
+            .filter_map(|declaration| Self::declared_identifier(declaration, source))
+            .collect();
+
+        let mut functions = Vec::new();
+        for node in Self::descendants(tree.root_node(), &["function_declaration"]) {
+            let owner = Self::ancestor_of_kinds(node, CONTAINERS)
+                .and_then(|container| self.container_name(container, source));
+            functions.push(self.build_function_info(node, owner.as_deref(), &self_aliases, source)?);
+        }
+
+        Ok(functions)
+    }
+
+    /// Extract named containers with their fields and functions
+    fn extract_classes(&self, tree: &tree_sitter::Tree, source: &str, functions: &[FunctionInfo]) -> Result<Vec<ClassInfo>> {
+        let mut classes = Vec::new();
+
+        for node in Self::descendants(tree.root_node(), CONTAINERS) {
+            let Some(name) = self.container_name(node, source) else { continue };
+            let mut class_info = ClassInfo::new(name);
+            class_info.start_line = node.start_position().row as u32 + 1;
+            class_info.end_line = node.end_position().row as u32 + 1;
+            class_info.span = node_span(node, source);
+
+            let kind = node.kind().trim_end_matches("_declaration");
+            class_info.metadata.insert("type".to_string(), kind.to_string());
+            // extern struct / packed struct
+            if let Some(layout) = Self::keyword(node, &["extern", "packed"]) {
+                class_info.metadata.insert("layout".to_string(), layout.to_string());
+            }
+
+            // Nested containers: Outer.Inner
+            if let Some((outer, simple)) = class_info.name.rsplit_once('.') {
+                let (outer, simple) = (outer.to_string(), simple.to_string());
+                class_info.metadata.insert("outer_class".to_string(), outer);
+                class_info.metadata.insert("simple_name".to_string(), simple);
+            }
+
+            // fn List(comptime T: type) type { return struct { ... }; }
+            let (binding, type_function) = match Self::binding(node, source) {
+                Some((binding, type_function)) => (Some(binding), type_function),
+                None => (None, false),
+            };
+            if type_function {
+                class_info.metadata.insert("type_function".to_string(), "true".to_string());
+            }
+            let visibility = match binding {
+                Some(binding) if Self::is_pub(binding) => "public",
+                _ => "private",
+            };
+            class_info.metadata.insert("visibility".to_string(), visibility.to_string());
+
+            let mut cursor = node.walk();
+            let fields: Vec<Node> = node.named_children(&mut cursor)
+                .filter(|c| c.kind() == "container_field")
+                .collect();
+            for field in fields {
+                class_info.member_variables.push(self.build_field(field, &class_info.name, kind == "enum", source)?);
+            }
+
+            class_info.methods = functions.iter()
+                .filter(|f| f.metadata.get("class_name") == Some(&class_info.name))
+                .cloned()
+                .collect();
+
+            classes.push(class_info);
+        }
+
+        Ok(classes)
+    }
+
+    /// Helper: Member variable of a container field (`x: f32 = 0`, or an enum tag `red`)
+    fn build_field(&self, field: Node, owner: &str, is_enum: bool, source: &str) -> Result<MemberVariable> {
+        let text: String = field.utf8_text(source.as_bytes())?.split_whitespace().collect::<Vec<_>>().join(" ");
+        let text = text.trim_end_matches(',');
+        let text = text.strip_prefix("comptime ").unwrap_or(text);
+        let declaration = text.split_once('=').map_or(text, |(declaration, _)| declaration);
+        let (name, field_type) = match declaration.split_once(':') {
+            Some((name, field_type)) => (name.trim(), field_type.trim()),
+            None => (declaration.trim(), ""),
+        };
+        let name = field.child_by_field_name("name")
+            .and_then(|n| n.utf8_text(source.as_bytes()).ok())
+            .unwrap_or(name);
+
+        let field_type = if is_enum { owner } else { field_type };
+        let mut member = MemberVariable::new(name.to_string(), field_type.to_string(), field.start_position().row as u32 + 1);
+        member.span = node_span(field, source);
+        // Fields are always accessible
+        member.access_modifier = "public".to_string();
+        if is_enum {
+            member.is_static = true;
+            member.is_const = true;
+            member.metadata.insert("enum_constant".to_string(), "true".to_string());
+        }
+        if text.contains('=') {
+            member.metadata.insert("has_default".to_string(), "true".to_string());
+        }
+
+        Ok(member)
+    }
+
+    /// Helper: Build FunctionInfo for a `fn` declaration
+    fn build_function_info(&self, node: Node, owner: Option<&str>, self_aliases: &[String], source: &str) -> Result<FunctionInfo> {
+        let name = match node.child_by_field_name("name") {
+            Some(name) => name.utf8_text(source.as_bytes())?.to_string(),
+            None => child_of_kind(node, "identifier")
+                .and_then(|n| n.utf8_text(source.as_bytes()).ok())
+                .unwrap_or("")
+                .to_string(),
+        };
+
+        let mut func_info = FunctionInfo::new(name);
+        func_info.start_line = node.start_position().row as u32 + 1;
+        func_info.span = node_span(node, source);
+        func_info.end_line = node.end_position().row as u32 + 1;
+        func_info.complexity = self.calculate_complexity(node, source);
+        func_info.body_hash = body_hash(node, source);
+        func_info.raw_hash = raw_hash(node, source);
+        func_info.clone_tokens = clone_tokens(node);
+
+        // fn max(comptime T: type, a: T, b: T) T
+        let parameters = Self::parameters(node);
+        let mut comptime_params = Vec::new();
+        if let Some(parameters) = parameters {
+            let mut cursor = parameters.walk();
+            for parameter in parameters.named_children(&mut cursor).filter(|p| p.kind() == "parameter") {
+                let text: String = parameter.utf8_text(source.as_bytes())?.split_whitespace().collect::<Vec<_>>().join(" ");
+                let (is_comptime, name, param_type) = split_parameter(&text);
+                if is_comptime || Self::keyword(parameter, &["comptime"]).is_some() {
+                    comptime_params.push(name.clone());
+                }
+                func_info.parameters.push(text);
+                let variadic = param_type == "...";
+                func_info.params.push(ParameterInfo { name, param_type, variadic });
+            }
+        }
+
+        // The return type sits between the parameter list and the body
+        let body = Self::body(node);
+        if let Some(parameters) = parameters {
+            let end = body.map_or(node.end_byte(), |b| b.start_byte());
+            let return_type = source.get(parameters.end_byte()..end).unwrap_or("").trim().trim_end_matches(';').trim();
+            if !return_type.is_empty() {
+                func_info.returns.push(return_type.to_string());
+                func_info.metadata.insert("return_type".to_string(), return_type.to_string());
+            }
+        }
+        if body.is_none() {
+            func_info.metadata.insert("is_extern".to_string(), "true".to_string());
+        }
+
+        // comptime parameters, or a function computing a type at compile time
+        let returns_type = func_info.returns.first().map_or(false, |t| t == "type");
+        if !comptime_params.is_empty() {
+            func_info.metadata.insert("comptime_params".to_string(), comptime_params.join(","));
+        }
+        if returns_type {
+            func_info.metadata.insert("returns_type".to_string(), "true".to_string());
+        }
+        if returns_type || !comptime_params.is_empty() {
+            func_info.metadata.insert("comptime".to_string(), "true".to_string());
+        }
+
+        let visibility = if Self::is_pub(node) { "public" } else { "private" };
+        func_info.metadata.insert("visibility".to_string(), visibility.to_string());
+        let modifiers = Self::keywords(node, FUNCTION_MODIFIERS);
+        if !modifiers.is_empty() {
+            func_info.metadata.insert("modifiers".to_string(), modifiers.join(" "));
+        }
+
+        if let Some(owner) = owner {
+            func_info.metadata.insert("class_name".to_string(), owner.to_string());
+            // First parameter of the container's type: p: Point, self: *const Self, self: *List(T)
+            let simple = owner.rsplit('.').next().unwrap_or(owner);
+            let receiver = func_info.params.first().filter(|p| {
+                let base = p.param_type.trim_start_matches(|c| c == '*' || c == '?').trim();
+                let base = base.strip_prefix("const ").unwrap_or(base).trim();
+                let base = base.split('(').next().unwrap_or(base);
+                base == simple || base == "@This" || self_aliases.iter().any(|alias| alias == base)
+            });
+            match receiver {
+                Some(receiver) => {
+                    func_info.metadata.insert("is_method".to_string(), "true".to_string());
+                    func_info.metadata.insert("receiver_name".to_string(), receiver.name.clone());
+                }
+                None => {
+                    func_info.metadata.insert("is_static".to_string(), "true".to_string());
+                }
+            }
+        }
+
+        Ok(func_info)
+    }
+
+    /// Extract `const name = @import("path")` declarations
+    fn extract_imports(&self, tree: &tree_sitter::Tree, source: &str) -> Result<Vec<ImportInfo>> {
+        let mut imports = Vec::new();
+
+        for node in Self::descendants(tree.root_node(), &["builtin_function"]) {
+            if !node.utf8_text(source.as_bytes())?.starts_with("@import") {
+                continue;
+            }
+            let Some(path) = Self::descendants(node, &["string"]).into_iter().next() else { continue };
+            let path = path.utf8_text(source.as_bytes())?.trim_matches('"').to_string();
+
+            let mut import_info = ImportInfo::new(ImportType::ZigImport, path);
+            import_info.line_number = node.start_position().row as u32 + 1;
+            // const print = @import("std").debug.print: binds a member of the module
+            let mut bound = node;
+            let mut member = Vec::new();
+            while let Some(parent) = bound.parent().filter(|p| p.kind() == "field_expression") {
+                if let Some(field) = parent.child_by_field_name("member") {
+                    member.push(field.utf8_text(source.as_bytes())?.to_string());
+                }
+                bound = parent;
+            }
+            if let Some(name) = bound.parent()
+                .filter(|p| p.kind() == "variable_declaration")
+                .and_then(|declaration| Self::declared_identifier(declaration, source))
+            {
+                import_info.imported_names.push(name);
+            }
+            if !member.is_empty() {
+                import_info.metadata.insert("member".to_string(), member.join("."));
+            }
+            imports.push(import_info);
+        }
+
+        Ok(imports)
+    }
+
+    /// Extract calls: `f()`, `obj.m()`, `Type.f()` and `module.f()` through an import
+    fn extract_function_calls(&self, tree: &tree_sitter::Tree, source: &str, classes: &[ClassInfo], functions: &[FunctionInfo], imports: &[ImportInfo]) -> Result<Vec<FunctionCall>> {
+        // Names bound to a whole module: const geo = @import("geo.zig")
+        let modules: HashMap<&str, &str> = imports.iter()
+            .filter(|import| !import.metadata.contains_key("member"))
+            .filter_map(|import| import.imported_names.first().map(|name| (name.as_str(), import.module_path.as_str())))
+            .collect();
+
+        let mut function_calls = Vec::new();
+        for node in Self::descendants(tree.root_node(), &["call_expression"]) {
+            let Some(callee) = node.child_by_field_name("function").or_else(|| node.named_child(0)) else { continue };
+            let line = node.start_position().row as u32 + 1;
+
+            match callee.kind() {
+                "identifier" => function_calls.push(FunctionCall::new(callee.utf8_text(source.as_bytes())?.to_string(), line)),
+                "field_expression" => {
+                    let (Some(object), Some(member)) = (callee.child_by_field_name("object"), callee.child_by_field_name("member")) else { continue };
+                    let mut call = FunctionCall::new(member.utf8_text(source.as_bytes())?.to_string(), line);
+                    call.is_method_call = true;
+                    let object_text: String = object.utf8_text(source.as_bytes())?.split_whitespace().collect();
+
+                    // Leftmost identifier of `a.b.c`
+                    let mut root = object;
+                    while root.kind() == "field_expression" {
+                        let Some(inner) = root.child_by_field_name("object") else { break };
+                        root = inner;
+                    }
+                    let root_text = root.utf8_text(source.as_bytes())?;
+
+                    if let Some(module) = modules.get(root_text).filter(|_| root.kind() == "identifier") {
+                        call.module = Some(module.to_string());
+                        // geo.Point.init(): a function of a container (types are TitleCase) in the imported file
+                        let container = object_text.split_once('.')
+                            .map(|(_, path)| path)
+                            .filter(|path| path.rsplit('.').next().map_or(false, |name| name.starts_with(|c: char| c.is_ascii_uppercase())));
+                        match container {
+                            Some(container) => call.receiver_type = Some(container.to_string()),
+                            // geo.area() / std.math.sqrt(): a file-level function, not a method
+                            None => call.is_method_call = false,
+                        }
+                    } else if object.kind() == "identifier" {
+                        // self.reset() inside a method, Point.init() on a container of this file
+                        let receiver_of_method = functions.iter()
+                            .filter(|f| f.start_line <= line && line <= f.end_line)
+                            .filter(|f| f.metadata.get("receiver_name").map(String::as_str) == Some(object_text.as_str()))
+                            .max_by_key(|f| f.start_line)
+                            .and_then(|f| f.metadata.get("class_name").cloned());
+                        call.receiver_type = receiver_of_method.or_else(|| {
+                            classes.iter()
+                                .find(|c| c.name == object_text || c.metadata.get("simple_name") == Some(&object_text))
+                                .map(|c| c.name.clone())
+                        });
+                    }
+                    call.object_name = Some(object_text);
+                    function_calls.push(call);
+                }
+                _ => {}
+            }
+        }
+
+        function_calls.sort_by_key(|call| call.line_number);
+        Ok(function_calls)
+    }
+
+    /// Helper: Qualified name of a container (`Outer.Inner`), `None` for anonymous ones
+    fn container_name(&self, container: Node, source: &str) -> Option<String> {
+        let (binding, type_function) = Self::binding(container, source)?;
+        let name = if type_function {
+            binding.child_by_field_name("name").or_else(|| child_of_kind(binding, "identifier"))?
+                .utf8_text(source.as_bytes()).ok()?
+                .to_string()
+        } else {
+            Self::declared_identifier(binding, source)?
+        };
+
+        match Self::ancestor_of_kinds(container, CONTAINERS) {
+            Some(outer) => Some(format!("{}.{}", self.container_name(outer, source)?, name)),
+            None => Some(name),
+        }
+    }
+
+    /// Helper: Declaration naming a container: its `const` (false) or the `fn` returning it (true)
+    fn binding<'t>(container: Node<'t>, source: &str) -> Option<(Node<'t>, bool)> {
+        let mut current = container.parent();
+        while let Some(parent) = current {
+            match parent.kind() {
+                "variable_declaration" => return Some((parent, false)),
+                "return_expression" | "return_statement" => {
+                    let function = Self::ancestor_of_kinds(parent, &["function_declaration"])?;
+                    let returns_type = Self::body(function).map_or(false, |body| {
+                        let start = Self::parameters(function).map_or(function.start_byte(), |p| p.end_byte());
+                        source.get(start..body.start_byte()).map_or(false, |t| t.trim() == "type")
+                    });
+                    return returns_type.then_some((function, true));
+                }
+                // Passed as an argument, or a container inside an expression
+                kind if CONTAINERS.contains(&kind) => return None,
+                "block" | "call_expression" | "arguments" | "function_declaration" | "source_file" => return None,
+                _ => current = parent.parent(),
+            }
+        }
+        None
+    }
+
+    /// Helper: Name bound by a `const` / `var` declaration
+    fn declared_identifier(declaration: Node, source: &str) -> Option<String> {
+        let name = declaration.child_by_field_name("name").or_else(|| child_of_kind(declaration, "identifier"))?;
+        Some(name.utf8_text(source.as_bytes()).ok()?.to_string())
+    }
+
+    /// Helper: Text after `=` in a `const` / `var` declaration
+    fn value_text(declaration: Node, source: &str) -> Option<String> {
+        let text = declaration.utf8_text(source.as_bytes()).ok()?;
+        let (_, value) = text.split_once('=')?;
+        Some(value.trim().trim_end_matches(';').split_whitespace().collect())
+    }
+
+    /// Helper: Whether a declaration is marked `pub` (inside the node or just before it)
+    fn is_pub(node: Node) -> bool {
+        Self::keyword(node, &["pub"]).is_some()
+            || node.prev_sibling().map_or(false, |previous| previous.kind() == "pub")
+    }
+
+    /// Helper: Parameter list of a `fn`
+    fn parameters(node: Node) -> Option<Node> {
+        node.child_by_field_name("parameters").or_else(|| child_of_kind(node, "parameters"))
+    }
+
+    /// Helper: Body block of a `fn` (`None` for `extern fn` prototypes)
+    fn body(node: Node) -> Option<Node> {
+        node.child_by_field_name("body").or_else(|| child_of_kind(node, "block"))
+    }
+
+    /// Helper: Descendants of the given kinds in document order, nested matches included
+    fn descendants<'t>(node: Node<'t>, kinds: &[&str]) -> Vec<Node<'t>> {
+        let mut found = Vec::new();
+        let mut stack = vec![node];
+        while let Some(current) = stack.pop() {
+            if current.id() != node.id() && kinds.contains(&current.kind()) {
+                found.push(current);
+            }
+            let mut cursor = current.walk();
+            let children: Vec<Node> = current.named_children(&mut cursor).collect();
+            stack.extend(children.into_iter().rev());
+        }
+        found
+    }
+
+    /// Helper: Nearest ancestor of one of the given kinds
+    fn ancestor_of_kinds<'t>(node: Node<'t>, kinds: &[&str]) -> Option<Node<'t>> {
+        let mut current = node.parent();
+        while let Some(parent) = current {
+            if kinds.contains(&parent.kind()) {
+                return Some(parent);
+            }
+            current = parent.parent();
+        }
+        None
+    }
+
+    /// Helper: First of the given anonymous keyword tokens among the direct children
+    fn keyword(node: Node, keywords: &[&'static str]) -> Option<&'static str> {
+        Self::keywords(node, keywords).into_iter().next()
+    }
+
+    /// Helper: The given anonymous keyword tokens among the direct children, in source order
+    fn keywords(node: Node, keywords: &[&'static str]) -> Vec<&'static str> {
+        let mut cursor = node.walk();
+        let found = node.children(&mut cursor)
+            .filter(|c| !c.is_named())
+            .filter_map(|c| keywords.iter().find(|k| **k == c.kind()).copied())
+            .collect();
+        found
+    }
+
+    /// Calculate cyclomatic complexity for a `fn`
+    fn calculate_complexity(&self, node: Node, source: &str) -> ComplexityInfo {
+        let mut complexity = ComplexityInfo::new();
+
+        // Base complexity 1 + one per decision point in the body
+        if let Some(body) = Self::body(node) {
+            complexity.decision_points = decision_points(body, source, &cyclomatic::ZIG);
+            complexity.cyclomatic_complexity = ComplexityWeights::STANDARD.cyclomatic(&complexity.decision_points);
+        }
+        complexity.cognitive_complexity = cognitive_complexity(node, source, &cognitive::ZIG);
+        complexity.max_nesting_depth = max_nesting_depth(node, &cognitive::ZIG);
+
+        complexity.update_rating();
+        complexity
+    }
+
+    /// Build AST from tree-sitter CST
+    fn build_ast(&self, tree: &tree_sitter::Tree, source: &str) -> ASTNode {
+        let mut root = ASTNode::new(ASTNodeType::FileRoot, String::new());
+        self.build_ast_recursive(tree.root_node(), source, &mut root, 0);
+        root
+    }
+
+    /// Recursive AST building
+    fn build_ast_recursive(&self, node: Node, source: &str, parent: &mut ASTNode, depth: usize) {
+        // Map tree-sitter node types to our AST types
+        let in_container = Self::ancestor_of_kinds(node, CONTAINERS).is_some();
+        let ast_type = match node.kind() {
+            "struct_declaration" | "union_declaration" | "opaque_declaration" => ASTNodeType::Struct,
+            "enum_declaration" => ASTNodeType::Enum,
+            "function_declaration" if in_container => ASTNodeType::Method,
+            "function_declaration" => ASTNodeType::Function,
+            "if_statement" | "if_expression" => ASTNodeType::IfStatement,
+            "for_statement" | "for_expression" | "while_statement" | "while_expression" => ASTNodeType::ForLoop,
+            "variable_declaration" => ASTNodeType::Variable,
+            _ => ASTNodeType::Unknown,
+        };
+
+        if ast_type != ASTNodeType::Unknown {
+            let mut ast_node = ASTNode::new(ast_type, String::new());
+            ast_node.start_line = node.start_position().row as u32 + 1;
+            ast_node.end_line = node.end_position().row as u32 + 1;
+            ast_node.depth = depth as u32;
+
+            let name = match node.kind() {
+                "function_declaration" | "variable_declaration" => Self::declared_identifier(node, source),
+                kind if CONTAINERS.contains(&kind) => self.container_name(node, source),
+                _ => None,
+            };
+            if let Some(name) = name {
+                ast_node.name = name;
+            }
+
+            parent.add_child(ast_node);
+
+            // Use the newly created node as parent for its children
+            let parent_index = parent.children.len() - 1;
+            let new_parent = &mut parent.children[parent_index];
+
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, new_parent, depth + 1);
+            }
+        } else {
+            // For unknown nodes, just recurse through children with the same parent
+            let mut cursor = node.walk();
+            for child in node.children(&mut cursor) {
+                self.build_ast_recursive(child, source, parent, depth + 1);
+            }
+        }
+    }
+}
+
+/// Helper: `comptime T: type` -> (true, "T", "type"); `anytype` / `...` have no name
+fn split_parameter(text: &str) -> (bool, String, String) {
+    let (is_comptime, rest) = match text.strip_prefix("comptime ") {
+        Some(rest) => (true, rest),
+        None => (false, text),
+    };
+    let rest = rest.strip_prefix("noalias ").unwrap_or(rest);
+    match rest.split_once(':') {
+        Some((name, param_type)) => (is_comptime, name.trim().to_string(), param_type.trim().to_string()),
+        None => (is_comptime, String::new(), rest.trim().to_string()),
+    }
+}
+
+#[async_trait]
+impl LanguageAnalyzer for TreeSitterZigAnalyzer {
+    fn get_language(&self) -> Language {
+        Language::Zig
+    }
+
+    fn get_language_name(&self) -> &'static str {
+        "Zig (Tree-sitter)"
+    }
+
+    fn get_supported_extensions(&self) -> Vec<&'static str> {
+        vec![".zig"]
+    }
+
+    async fn analyze(&mut self, content: &str, filename: &str) -> Result<AnalysisResult> {
+        // Create file info
+        let file_path = std::path::PathBuf::from(filename);
+        let mut file_info = FileInfo::new(file_path);
+        file_info.total_lines = content.lines().count() as u32;
+
+        // Create analysis result
+        let mut result = AnalysisResult::new(file_info, Language::Zig);
+
+        // 🚀 Parse with tree-sitter
+        let parse_start = std::time::Instant::now();
+        let tree = self.parser.parse(content, None)
+            .ok_or_else(|| anyhow::anyhow!("Failed to parse Zig file"))?;
+        let parse_duration = parse_start.elapsed();
+
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER ZIG] Parse took: {:.3}ms", parse_duration.as_secs_f64() * 1000.0);
+        }
+
+        // Extract all constructs
+        let extract_start = std::time::Instant::now();
+        result.functions = self.extract_functions(&tree, content)?;
+        result.classes = self.extract_classes(&tree, content, &result.functions)?;
+        result.imports = self.extract_imports(&tree, content)?;
+        result.function_calls = self.extract_function_calls(&tree, content, &result.classes, &result.functions, &result.imports)?;
+        let extract_duration = extract_start.elapsed();
+
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("⚡ [TREE-SITTER ZIG] Extraction took: {:.3}ms", extract_duration.as_secs_f64() * 1000.0);
+        }
+
+        // Build AST
+        let ast_root = self.build_ast(&tree, content);
+        let mut ast_stats = ASTStatistics::default();
+        ast_stats.update_from_root(&ast_root);
+        result.ast_root = Some(ast_root);
+        result.ast_statistics = Some(ast_stats);
+
+        finish_analysis(&tree, content, &mut result, &magic_numbers::ZIG);
+
+        Ok(result)
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_split_parameter() {
+        assert_eq!(split_parameter("comptime T: type"), (true, "T".to_string(), "type".to_string()));
+        assert_eq!(split_parameter("self: *const Self"), (false, "self".to_string(), "*const Self".to_string()));
+        assert_eq!(split_parameter("noalias dest: []u8"), (false, "dest".to_string(), "[]u8".to_string()));
+        assert_eq!(split_parameter("anytype"), (false, String::new(), "anytype".to_string()));
+    }
+}
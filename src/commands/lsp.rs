@@ -0,0 +1,955 @@
+//! Language Server Protocol subset over stdio
+//!
+//! Answers `textDocument/documentSymbol`, `textDocument/references` and
+//! `workspace/symbol` from the same project index as `nekocode mcp`.
+//! Messages use LSP's `Content-Length` framing instead of MCP's lines.
+//!
+//! Documents opened by the client are kept as buffers: each `didChange`
+//! applies the (incremental) edits and re-analyzes only that document, and
+//! the buffer shadows the file on disk until it is closed. Files that are
+//! not open are re-indexed from file watcher events, as in MCP mode.
+//!
+//! Positions are 0-based lines with UTF-16 character offsets, the encoding
+//! every client supports.
+
+use anyhow::{Context, Result};
+use serde_json::{json, Value};
+use std::borrow::Cow;
+use std::collections::{BTreeMap, HashMap, HashSet};
+use std::path::{Path, PathBuf};
+use std::time::Duration;
+use tokio::io::{AsyncBufRead, AsyncBufReadExt, AsyncReadExt, AsyncWrite, AsyncWriteExt, BufReader};
+
+use crate::commands::mcp::{ProjectIndex, WATCH_POLL_INTERVAL};
+use crate::core::types::{AnalysisResult, ClassInfo, FunctionInfo, Language, MemberVariable, Span};
+
+// JSON-RPC / LSP error codes
+const PARSE_ERROR: i64 = -32700;
+const INVALID_REQUEST: i64 = -32600;
+const METHOD_NOT_FOUND: i64 = -32601;
+const INVALID_PARAMS: i64 = -32602;
+const INTERNAL_ERROR: i64 = -32603;
+const SERVER_NOT_INITIALIZED: i64 = -32002;
+
+// SymbolKind values used for analyzed items
+const KIND_CLASS: u32 = 5;
+const KIND_METHOD: u32 = 6;
+const KIND_FIELD: u32 = 8;
+const KIND_CONSTRUCTOR: u32 = 9;
+const KIND_ENUM: u32 = 10;
+const KIND_INTERFACE: u32 = 11;
+const KIND_FUNCTION: u32 = 12;
+const KIND_CONSTANT: u32 = 14;
+const KIND_OBJECT: u32 = 19;
+const KIND_ENUM_MEMBER: u32 = 22;
+const KIND_STRUCT: u32 = 23;
+
+/// Upper bound on `workspace/symbol` results
+const MAX_WORKSPACE_SYMBOLS: usize = 500;
+
+/// Buffer of a document opened by the client
+struct Document {
+    uri: String,
+    text: String,
+    language: Language,
+    /// `None` when the language is not supported or analysis failed
+    result: Option<AnalysisResult>,
+}
+
+/// LSP request dispatcher: the project index plus the client's open documents
+pub struct LspServer {
+    default_root: PathBuf,
+    include_tests: bool,
+    /// Debounce of the index's file watcher; `None` refreshes from modification times per request
+    watch: Option<Duration>,
+    /// Created by `initialize` from the client's workspace root
+    index: Option<ProjectIndex>,
+    documents: HashMap<PathBuf, Document>,
+    shutdown: bool,
+    exited: bool,
+}
+
+impl LspServer {
+    pub fn new(default_root: &Path, include_tests: bool) -> Self {
+        Self {
+            default_root: default_root.to_path_buf(),
+            include_tests,
+            watch: None,
+            index: None,
+            documents: HashMap::new(),
+            shutdown: false,
+            exited: false,
+        }
+    }
+
+    /// Watch the workspace once it is indexed, re-indexing changed files after `debounce`
+    pub fn with_watch(mut self, debounce: Duration) -> Self {
+        self.watch = Some(debounce);
+        self
+    }
+
+    /// Process exit code once `exit` was received: 0 only after a `shutdown` request
+    pub fn exit_code(&self) -> Option<i32> {
+        self.exited.then(|| if self.shutdown { 0 } else { 1 })
+    }
+
+    /// Handle one message; notifications (no `id`) produce no response
+    pub async fn handle_message(&mut self, message: Value) -> Option<Value> {
+        let id = message.get("id").cloned();
+        let method = match message.get("method").and_then(|m| m.as_str()) {
+            Some(method) => method.to_string(),
+            // Responses to server-initiated requests; we send none
+            None => return id.filter(|_| message.get("result").is_none() && message.get("error").is_none())
+                .map(|id| error_response(id, INVALID_REQUEST, "Missing method")),
+        };
+        let params = message.get("params").cloned().unwrap_or(Value::Null);
+
+        let outcome = match method.as_str() {
+            "initialize" => self.initialize(&params).await,
+            "shutdown" => {
+                self.shutdown = true;
+                Ok(Value::Null)
+            }
+            "exit" => {
+                self.exited = true;
+                return None;
+            }
+            _ if id.is_none() => {
+                self.notification(&method, &params).await;
+                return None;
+            }
+            _ if self.index.is_none() => Err((SERVER_NOT_INITIALIZED, "Server not initialized".to_string())),
+            _ if self.shutdown => Err((INVALID_REQUEST, "Server is shutting down".to_string())),
+            "textDocument/documentSymbol" => self.document_symbol(&params),
+            "textDocument/references" => self.references(&params).await,
+            "workspace/symbol" => self.workspace_symbol(&params).await,
+            _ => Err((METHOD_NOT_FOUND, format!("Method not found: {}", method))),
+        };
+
+        let id = id?;
+        Some(match outcome {
+            Ok(result) => json!({ "jsonrpc": "2.0", "id": id, "result": result }),
+            Err((code, message)) => error_response(id, code, &message),
+        })
+    }
+
+    /// Helper: `initialize` handshake; indexes the client's workspace root
+    async fn initialize(&mut self, params: &Value) -> std::result::Result<Value, (i64, String)> {
+        let root = params.get("rootUri").and_then(|v| v.as_str()).and_then(uri_to_path)
+            .or_else(|| params.get("rootPath").and_then(|v| v.as_str()).map(PathBuf::from))
+            .or_else(|| {
+                params.pointer("/workspaceFolders/0/uri").and_then(|v| v.as_str()).and_then(uri_to_path)
+            })
+            .unwrap_or_else(|| self.default_root.clone());
+
+        let mut index = ProjectIndex::new(&root, self.include_tests).map_err(|e| (INVALID_PARAMS, format!("{:#}", e)))?;
+        let indexed = index.refresh().await.map_err(|e| (INTERNAL_ERROR, format!("{:#}", e)))?;
+        eprintln!("# nekocode lsp: indexed {} files under {}", indexed, index.root().display());
+        if let Some(debounce) = self.watch {
+            if let Err(e) = index.watch(debounce) {
+                eprintln!("# nekocode lsp: not watching ({:#}); checking modification times per request", e);
+            }
+        }
+        self.index = Some(index);
+
+        Ok(json!({
+            "capabilities": {
+                // Incremental sync: didChange carries ranged edits
+                "textDocumentSync": { "openClose": true, "change": 2 },
+                "documentSymbolProvider": true,
+                "referencesProvider": true,
+                "workspaceSymbolProvider": true
+            },
+            "serverInfo": {
+                "name": "nekocode-rust",
+                "version": env!("CARGO_PKG_VERSION")
+            }
+        }))
+    }
+
+    /// Helper: Document lifecycle notifications; anything else is ignored
+    async fn notification(&mut self, method: &str, params: &Value) {
+        let Some(path) = params.pointer("/textDocument/uri").and_then(|v| v.as_str()).and_then(uri_to_path) else { return };
+
+        match method {
+            "textDocument/didOpen" => {
+                let document = &params["textDocument"];
+                let text = document["text"].as_str().unwrap_or("").to_string();
+                let language = document_language(&path, document["languageId"].as_str(), &text);
+                self.documents.insert(path.clone(), Document {
+                    uri: document["uri"].as_str().unwrap_or("").to_string(),
+                    text,
+                    language,
+                    result: None,
+                });
+                self.reanalyze(&path).await;
+            }
+            "textDocument/didChange" => {
+                let Some(document) = self.documents.get_mut(&path) else { return };
+                for change in params["contentChanges"].as_array().into_iter().flatten() {
+                    let text = change["text"].as_str().unwrap_or("");
+                    match change.get("range") {
+                        Some(range) => {
+                            let (start, end) = {
+                                let lines = LineIndex::new(&document.text);
+                                let start = lines.offset_of(&range["start"]);
+                                (start, lines.offset_of(&range["end"]).max(start))
+                            };
+                            document.text.replace_range(start..end, text);
+                        }
+                        None => document.text = text.to_string(),
+                    }
+                }
+                self.reanalyze(&path).await;
+            }
+            "textDocument/didClose" => {
+                self.documents.remove(&path);
+            }
+            _ => {}
+        }
+    }
+
+    /// Helper: Re-analyze one open document from its buffer
+    async fn reanalyze(&mut self, path: &Path) {
+        let (Some(index), Some(document)) = (self.index.as_ref(), self.documents.get_mut(path)) else { return };
+        if document.language == Language::Unknown {
+            return;
+        }
+
+        document.result = match index.session().analyze_source(&document.text, path, document.language).await {
+            Ok(result) => Some(result),
+            Err(e) => {
+                if std::env::var("NEKOCODE_DEBUG").is_ok() {
+                    eprintln!("⚠️  [LSP] Failed to analyze {}: {:#}", path.display(), e);
+                }
+                None
+            }
+        };
+    }
+
+    /// Helper: `textDocument/documentSymbol` as hierarchical `DocumentSymbol`s
+    fn document_symbol(&self, params: &Value) -> std::result::Result<Value, (i64, String)> {
+        let path = document_path(params)?;
+        let (text, result) = match self.documents.get(&path) {
+            Some(document) => (Cow::Borrowed(document.text.as_str()), document.result.as_ref()),
+            None => {
+                let result = self.index.as_ref().and_then(|index| index.results().find(|(p, _)| **p == path).map(|(_, r)| r));
+                (Cow::Owned(std::fs::read_to_string(&path).unwrap_or_default()), result)
+            }
+        };
+
+        Ok(match result {
+            Some(result) => Value::Array(document_symbols(result, &LineIndex::new(&text))),
+            None => Value::Null,
+        })
+    }
+
+    /// Helper: `textDocument/references` for the identifier under the cursor
+    async fn references(&mut self, params: &Value) -> std::result::Result<Value, (i64, String)> {
+        let path = document_path(params)?;
+        let (line, character) = position_param(&params["position"])
+            .ok_or_else(|| (INVALID_PARAMS, "Missing position".to_string()))?;
+        let include_declaration = params.pointer("/context/includeDeclaration").and_then(|v| v.as_bool()).unwrap_or(false);
+
+        let word = self.text(&path).and_then(|text| {
+            let lines = LineIndex::new(&text);
+            lines.word_at(lines.offset(line, character)).map(str::to_string)
+        });
+        let Some(word) = word else { return Ok(json!([])) };
+        self.refresh().await?;
+
+        let mut locations = Vec::new();
+        let mut seen = HashSet::new();
+        for (path, result) in self.results() {
+            let mut declarations: Vec<(u32, u32, &Span)> = Vec::new();
+            if include_declaration {
+                for func in result.functions.iter().chain(result.classes.iter().flat_map(|c| c.methods.iter())) {
+                    if func.name == word {
+                        declarations.push((func.start_line, func.end_line, &func.span));
+                    }
+                }
+                for class in result.classes.iter().filter(|c| simple_name(c) == word) {
+                    declarations.push((class.start_line, class.end_line, &class.span));
+                }
+            }
+            let calls: Vec<u32> = result.function_calls.iter()
+                .filter(|call| call.function_name == word)
+                .map(|call| call.line_number)
+                .collect();
+            if declarations.is_empty() && calls.is_empty() {
+                continue;
+            }
+
+            let Some(text) = self.text(&path) else { continue };
+            let lines = LineIndex::new(&text);
+            let uri = self.uri(&path);
+            let mut ranges = Vec::new();
+            for (start_line, end_line, span) in declarations {
+                let (from, to) = lines.item_bytes(start_line, end_line, span);
+                ranges.push(lines.name_range(from, to, &word));
+            }
+            // Several calls on one line match successive occurrences of the name
+            let mut per_line: HashMap<u32, usize> = HashMap::new();
+            for line_number in calls {
+                let nth = per_line.entry(line_number).or_insert(0);
+                let (from, to) = lines.item_bytes(line_number, line_number, &Span::default());
+                let occurrence = find_words(&lines.text[from..to], &word).nth(*nth).unwrap_or(0);
+                ranges.push(lines.name_range(from + occurrence, to, &word));
+                *nth += 1;
+            }
+
+            for range in ranges {
+                if seen.insert((uri.clone(), range.to_string())) {
+                    locations.push(json!({ "uri": uri, "range": range }));
+                }
+            }
+        }
+
+        Ok(Value::Array(locations))
+    }
+
+    /// Helper: `workspace/symbol` as `SymbolInformation`s, matching the query as a subsequence
+    async fn workspace_symbol(&mut self, params: &Value) -> std::result::Result<Value, (i64, String)> {
+        let query = params.get("query").and_then(|v| v.as_str()).unwrap_or("").to_lowercase();
+        self.refresh().await?;
+
+        let mut symbols = Vec::new();
+        for (path, result) in self.results() {
+            let uri = self.uri(&path);
+            let mut push = |name: &str, kind: u32, start_line: u32, end_line: u32, span: &Span, container: Option<&str>| {
+                if symbols.len() < MAX_WORKSPACE_SYMBOLS && matches_query(name, &query) {
+                    let mut symbol = json!({
+                        "name": name,
+                        "kind": kind,
+                        "location": { "uri": uri, "range": span_range(start_line, end_line, span) }
+                    });
+                    if let Some(container) = container {
+                        symbol["containerName"] = json!(container);
+                    }
+                    symbols.push(symbol);
+                }
+            };
+
+            let methods = method_keys(result);
+            for class in &result.classes {
+                push(class.name.as_str(), class_kind(class), class.start_line, class.end_line, &class.span, None);
+                for method in &class.methods {
+                    let kind = method_kind(class, method);
+                    push(method.name.as_str(), kind, method.start_line, method.end_line, &method.span, Some(class.name.as_str()));
+                }
+            }
+            for func in result.functions.iter().filter(|f| !methods.contains(&(f.name.as_str(), f.start_line))) {
+                let container = func.metadata.get("receiver_type").or_else(|| func.metadata.get("class_name"));
+                let kind = if container.is_some() { KIND_METHOD } else { KIND_FUNCTION };
+                push(func.name.as_str(), kind, func.start_line, func.end_line, &func.span, container.map(String::as_str));
+            }
+        }
+
+        Ok(Value::Array(symbols))
+    }
+
+    /// Apply watched changes whose debounce window has passed; call periodically
+    pub async fn tick(&mut self) {
+        let Some(index) = self.index.as_mut() else { return };
+        match index.sync_watched(false).await {
+            Ok(reanalyzed) if reanalyzed > 0 && std::env::var("NEKOCODE_DEBUG").is_ok() => {
+                eprintln!("🔄 [LSP] Re-indexed {} changed file(s)", reanalyzed);
+            }
+            Ok(_) => {}
+            // The next sync falls back to a full refresh
+            Err(e) => eprintln!("⚠️  [LSP] Re-indexing failed: {:#}", e),
+        }
+    }
+
+    /// Helper: Bring files that are not open up to date before answering
+    async fn refresh(&mut self) -> std::result::Result<(), (i64, String)> {
+        let Some(index) = self.index.as_mut() else { return Ok(()) };
+        let reanalyzed = if index.is_watching() { index.sync_watched(true).await } else { index.refresh().await };
+        let reanalyzed = reanalyzed.map_err(|e| (INTERNAL_ERROR, format!("{:#}", e)))?;
+        if reanalyzed > 0 && std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("🔄 [LSP] Re-indexed {} file(s)", reanalyzed);
+        }
+        Ok(())
+    }
+
+    /// Helper: Indexed results with open documents shadowing their files, in path order
+    fn results(&self) -> Vec<(PathBuf, &AnalysisResult)> {
+        let mut results: BTreeMap<PathBuf, &AnalysisResult> = self.index.iter()
+            .flat_map(|index| index.results())
+            .filter(|(path, _)| !self.documents.contains_key(*path))
+            .map(|(path, result)| (path.clone(), result))
+            .collect();
+        for (path, document) in &self.documents {
+            if let Some(result) = &document.result {
+                results.insert(path.clone(), result);
+            }
+        }
+        results.into_iter().collect()
+    }
+
+    /// Helper: Current text of a file: the open buffer, else the file on disk
+    fn text(&self, path: &Path) -> Option<Cow<'_, str>> {
+        match self.documents.get(path) {
+            Some(document) => Some(Cow::Borrowed(document.text.as_str())),
+            None => std::fs::read_to_string(path).ok().map(Cow::Owned),
+        }
+    }
+
+    /// Helper: URI to report for a path; open documents keep the client's spelling
+    fn uri(&self, path: &Path) -> String {
+        match self.documents.get(path) {
+            Some(document) => document.uri.clone(),
+            None => path_to_uri(path),
+        }
+    }
+}
+
+/// Helper: Hierarchical symbols of one document; nested types go under their enclosing type
+fn document_symbols(result: &AnalysisResult, lines: &LineIndex) -> Vec<Value> {
+    let classes = &result.classes;
+    let parents: Vec<Option<usize>> = classes.iter().enumerate()
+        .map(|(i, class)| {
+            let outer = class.metadata.get("outer_class")?;
+            // Companions share a name; the enclosing one is the one containing the type
+            (0..classes.len()).find(|&j| {
+                j != i && &classes[j].name == outer
+                    && classes[j].start_line <= class.start_line && class.end_line <= classes[j].end_line
+            })
+        })
+        .collect();
+
+    let methods = method_keys(result);
+    let mut symbols: Vec<Value> = (0..classes.len())
+        .filter(|&i| parents[i].is_none())
+        .map(|i| class_symbol(i, classes, &parents, lines))
+        .collect();
+    symbols.extend(result.functions.iter()
+        .filter(|f| !methods.contains(&(f.name.as_str(), f.start_line)))
+        .map(|f| function_symbol(f, KIND_FUNCTION, lines)));
+    sort_symbols(&mut symbols);
+    symbols
+}
+
+/// Helper: `DocumentSymbol` of a class with its members, methods and nested types
+fn class_symbol(index: usize, classes: &[ClassInfo], parents: &[Option<usize>], lines: &LineIndex) -> Value {
+    let class = &classes[index];
+    let kind = class_kind(class);
+    let mut children: Vec<Value> = class.member_variables.iter()
+        .map(|member| member_symbol(member, kind, lines))
+        .chain(class.methods.iter().map(|method| function_symbol(method, method_kind(class, method), lines)))
+        .chain((0..classes.len()).filter(|&j| parents[j] == Some(index)).map(|j| class_symbol(j, classes, parents, lines)))
+        .collect();
+    sort_symbols(&mut children);
+
+    let name = simple_name(class);
+    let detail = class.metadata.get("type").cloned().unwrap_or_default();
+    let mut symbol = symbol(name, &detail, kind, lines, lines.item_bytes(class.start_line, class.end_line, &class.span));
+    symbol["children"] = Value::Array(children);
+    symbol
+}
+
+/// Helper: `DocumentSymbol` of a function or method
+fn function_symbol(func: &FunctionInfo, kind: u32, lines: &LineIndex) -> Value {
+    let detail = format!("({})", func.parameters.join(", "));
+    symbol(&func.name, &detail, kind, lines, lines.item_bytes(func.start_line, func.end_line, &func.span))
+}
+
+/// Helper: `DocumentSymbol` of a member variable; enum members and constants get their own kinds
+fn member_symbol(member: &MemberVariable, owner_kind: u32, lines: &LineIndex) -> Value {
+    let kind = if owner_kind == KIND_ENUM {
+        KIND_ENUM_MEMBER
+    } else if member.is_const && member.is_static {
+        KIND_CONSTANT
+    } else {
+        KIND_FIELD
+    };
+    symbol(&member.name, &member.var_type, kind, lines, lines.item_bytes(member.declaration_line, member.declaration_line, &member.span))
+}
+
+/// Helper: `DocumentSymbol` covering `bytes`, selecting the first occurrence of its name
+fn symbol(name: &str, detail: &str, kind: u32, lines: &LineIndex, (from, to): (usize, usize)) -> Value {
+    let mut symbol = json!({
+        "name": name,
+        "kind": kind,
+        "range": { "start": lines.position(from), "end": lines.position(to) },
+        "selectionRange": lines.name_range(from, to, name),
+    });
+    if !detail.is_empty() {
+        symbol["detail"] = json!(detail);
+    }
+    symbol
+}
+
+/// Helper: Order sibling symbols by their start position
+fn sort_symbols(symbols: &mut [Value]) {
+    let start = |symbol: &Value| {
+        let start = &symbol["range"]["start"];
+        (start["line"].as_u64().unwrap_or(0), start["character"].as_u64().unwrap_or(0))
+    };
+    symbols.sort_by_key(start);
+}
+
+/// Helper: (name, start line) of every class method, to keep them out of the top level
+fn method_keys(result: &AnalysisResult) -> HashSet<(&str, u32)> {
+    result.classes.iter()
+        .flat_map(|class| class.methods.iter())
+        .map(|method| (method.name.as_str(), method.start_line))
+        .collect()
+}
+
+/// Helper: Unqualified name of a (possibly nested, `Outer.Inner`) type
+fn simple_name(class: &ClassInfo) -> &str {
+    class.metadata.get("simple_name").map(String::as_str).unwrap_or(&class.name)
+}
+
+/// Helper: SymbolKind of a type from the analyzer's `type` metadata
+fn class_kind(class: &ClassInfo) -> u32 {
+    match class.metadata.get("type").map(String::as_str) {
+        Some("interface" | "protocol" | "trait") => KIND_INTERFACE,
+        Some("enum") => KIND_ENUM,
+        Some("struct" | "record") => KIND_STRUCT,
+        Some("object" | "case_object" | "package_object") => KIND_OBJECT,
+        _ => KIND_CLASS,
+    }
+}
+
+/// Helper: SymbolKind of a method; constructors are named after the language's convention
+fn method_kind(class: &ClassInfo, method: &FunctionInfo) -> u32 {
+    let constructor = method.metadata.get("type").map_or(false, |t| t == "constructor")
+        || matches!(method.name.as_str(), "constructor" | "__init__" | "__construct" | "initialize" | "init")
+        || method.name == simple_name(class);
+    if constructor { KIND_CONSTRUCTOR } else { KIND_METHOD }
+}
+
+/// Helper: Case-insensitive subsequence match, the way editors filter symbol pickers
+fn matches_query(name: &str, query: &str) -> bool {
+    let mut name = name.chars().flat_map(char::to_lowercase);
+    query.chars().all(|q| name.any(|c| c == q))
+}
+
+/// Helper: Range from analyzed lines and the span's UTF-16 columns, without the source text
+fn span_range(start_line: u32, end_line: u32, span: &Span) -> Value {
+    let start_line = start_line.saturating_sub(1);
+    if span.end_byte > span.start_byte {
+        json!({
+            "start": { "line": start_line, "character": span.start_col_utf16 },
+            "end": { "line": end_line.saturating_sub(1), "character": span.end_col_utf16 }
+        })
+    } else {
+        // No span: whole lines, ending at the start of the following line
+        json!({ "start": { "line": start_line, "character": 0 }, "end": { "line": end_line, "character": 0 } })
+    }
+}
+
+/// Helper: Byte offsets of line starts, for converting between bytes and LSP positions
+struct LineIndex<'a> {
+    text: &'a str,
+    starts: Vec<usize>,
+}
+
+impl<'a> LineIndex<'a> {
+    fn new(text: &'a str) -> Self {
+        let mut starts = vec![0];
+        starts.extend(text.match_indices('\n').map(|(i, _)| i + 1));
+        Self { text, starts }
+    }
+
+    /// LSP position of a byte offset
+    fn position(&self, offset: usize) -> Value {
+        let mut offset = offset.min(self.text.len());
+        while !self.text.is_char_boundary(offset) {
+            offset -= 1;
+        }
+        let line = self.starts.partition_point(|&start| start <= offset) - 1;
+        let character = self.text[self.starts[line]..offset].encode_utf16().count();
+        json!({ "line": line, "character": character })
+    }
+
+    /// Byte offset of a 0-based line and UTF-16 character, clamped to the line
+    fn offset(&self, line: usize, character: usize) -> usize {
+        let Some(&start) = self.starts.get(line) else { return self.text.len() };
+        let end = self.line_end(line);
+        let mut units = 0;
+        for (i, c) in self.text[start..end].char_indices() {
+            if units >= character {
+                return start + i;
+            }
+            units += c.len_utf16();
+        }
+        end
+    }
+
+    /// Byte offset of an LSP position object
+    fn offset_of(&self, position: &Value) -> usize {
+        position_param(position).map_or(self.text.len(), |(line, character)| self.offset(line, character))
+    }
+
+    /// Helper: End of a line's content, before `\r\n` / `\n`
+    fn line_end(&self, line: usize) -> usize {
+        let end = self.starts.get(line + 1).map_or(self.text.len(), |&next| next - 1);
+        if self.text[..end].ends_with('\r') && end > self.starts[line] { end - 1 } else { end }
+    }
+
+    /// Byte range of an analyzed item: its span, or its whole 1-based lines when it has none
+    fn item_bytes(&self, start_line: u32, end_line: u32, span: &Span) -> (usize, usize) {
+        if span.end_byte > span.start_byte && (span.end_byte as usize) <= self.text.len() {
+            return (span.start_byte as usize, span.end_byte as usize);
+        }
+        let start = self.starts.get(start_line.saturating_sub(1) as usize).copied().unwrap_or(self.text.len());
+        let end_line = (end_line.max(start_line).saturating_sub(1) as usize).min(self.starts.len() - 1);
+        (start, self.line_end(end_line).max(start))
+    }
+
+    /// Range of the first whole-word `name` in `[from, to)`; an empty range at `from` when absent
+    fn name_range(&self, from: usize, to: usize, name: &str) -> Value {
+        let to = to.min(self.text.len());
+        let start = self.text.get(from..to)
+            .and_then(|haystack| find_words(haystack, name).next())
+            .map(|i| from + i);
+        match start {
+            Some(start) => json!({ "start": self.position(start), "end": self.position(start + name.len()) }),
+            None => json!({ "start": self.position(from), "end": self.position(from) }),
+        }
+    }
+
+    /// Identifier touching a byte offset (the cursor may sit just after it)
+    fn word_at(&self, offset: usize) -> Option<&'a str> {
+        let text = self.text;
+        let start = text[..offset].char_indices().rev()
+            .take_while(|(_, c)| is_identifier_char(*c))
+            .last()
+            .map_or(offset, |(i, _)| i);
+        let end = text[offset..].char_indices()
+            .find(|(_, c)| !is_identifier_char(*c))
+            .map_or(text.len(), |(i, _)| offset + i);
+        (end > start).then(|| &text[start..end])
+    }
+}
+
+/// Helper: Byte offsets of `word` in `haystack` that are not part of a longer identifier
+fn find_words<'h>(haystack: &'h str, word: &'h str) -> impl Iterator<Item = usize> + 'h {
+    haystack.match_indices(word).map(|(i, _)| i).filter(move |&i| {
+        let before = haystack[..i].chars().next_back().map_or(true, |c| !is_identifier_char(c));
+        let after = haystack[i + word.len()..].chars().next().map_or(true, |c| !is_identifier_char(c));
+        !word.is_empty() && before && after
+    })
+}
+
+/// Helper: Characters of an identifier in the analyzed languages
+fn is_identifier_char(c: char) -> bool {
+    c.is_alphanumeric() || c == '_' || c == '$'
+}
+
+/// Helper: (line, character) of an LSP position object
+fn position_param(position: &Value) -> Option<(usize, usize)> {
+    Some((position.get("line")?.as_u64()? as usize, position.get("character")?.as_u64()? as usize))
+}
+
+/// Helper: Path of the request's `textDocument`
+fn document_path(params: &Value) -> std::result::Result<PathBuf, (i64, String)> {
+    params.pointer("/textDocument/uri").and_then(|v| v.as_str()).and_then(uri_to_path)
+        .ok_or_else(|| (INVALID_PARAMS, "Missing or unsupported textDocument.uri".to_string()))
+}
+
+/// Helper: Language of an opened document: extension, then the client's language id, then shebang
+fn document_language(path: &Path, language_id: Option<&str>, text: &str) -> Language {
+    let by_extension = path.extension().and_then(|e| e.to_str())
+        .map_or(Language::Unknown, |e| Language::from_extension(&format!(".{}", e)));
+    if by_extension != Language::Unknown {
+        return by_extension;
+    }
+    language_id.and_then(Language::from_name)
+        .unwrap_or_else(|| Language::from_shebang(text.lines().next().unwrap_or("")))
+}
+
+/// Path of a `file://` URI (percent-decoded), canonicalized when it exists
+fn uri_to_path(uri: &str) -> Option<PathBuf> {
+    let rest = uri.strip_prefix("file://")?;
+    let rest = rest.strip_prefix("localhost").unwrap_or(rest);
+
+    let bytes = rest.as_bytes();
+    let mut decoded = Vec::with_capacity(bytes.len());
+    let mut i = 0;
+    while i < bytes.len() {
+        let hex = (bytes[i] == b'%').then(|| rest.get(i + 1..i + 3)).flatten()
+            .and_then(|h| u8::from_str_radix(h, 16).ok());
+        match hex {
+            Some(byte) => {
+                decoded.push(byte);
+                i += 3;
+            }
+            None => {
+                decoded.push(bytes[i]);
+                i += 1;
+            }
+        }
+    }
+    let mut path = String::from_utf8(decoded).ok()?;
+    // file:///C:/dir -> C:/dir
+    if cfg!(windows) && path.len() > 2 && path.as_bytes()[2] == b':' {
+        path.remove(0);
+    }
+
+    let path = PathBuf::from(path);
+    Some(std::fs::canonicalize(&path).unwrap_or(path))
+}
+
+/// `file://` URI of a path, percent-encoding everything but unreserved characters and `/`
+fn path_to_uri(path: &Path) -> String {
+    let path = path.to_string_lossy().replace('\\', "/");
+    let mut uri = String::from(if path.starts_with('/') { "file://" } else { "file:///" });
+    for byte in path.bytes() {
+        match byte {
+            b'A'..=b'Z' | b'a'..=b'z' | b'0'..=b'9' | b'-' | b'.' | b'_' | b'~' | b'/' => uri.push(byte as char),
+            _ => uri.push_str(&format!("%{:02X}", byte)),
+        }
+    }
+    uri
+}
+
+/// Helper: JSON-RPC error object
+fn error_response(id: Value, code: i64, message: &str) -> Value {
+    json!({ "jsonrpc": "2.0", "id": id, "error": { "code": code, "message": message } })
+}
+
+/// Helper: Read one `Content-Length` framed message body; `None` at end of input
+async fn read_message<R: AsyncBufRead + Unpin>(reader: &mut R) -> Result<Option<Vec<u8>>> {
+    let mut length = None;
+    loop {
+        let mut header = String::new();
+        if reader.read_line(&mut header).await? == 0 {
+            return Ok(None);
+        }
+        let header = header.trim_end();
+        if header.is_empty() {
+            if length.is_some() {
+                break;
+            }
+            continue;
+        }
+        if let Some((name, value)) = header.split_once(':') {
+            if name.trim().eq_ignore_ascii_case("content-length") {
+                let value = value.trim();
+                length = Some(value.parse::<usize>().with_context(|| format!("Invalid Content-Length: {}", value))?);
+            }
+        }
+    }
+
+    let mut body = vec![0; length.unwrap_or(0)];
+    reader.read_exact(&mut body).await?;
+    Ok(Some(body))
+}
+
+/// Helper: Write one message with its `Content-Length` header
+async fn write_message<W: AsyncWrite + Unpin>(writer: &mut W, message: &Value) -> Result<()> {
+    let body = serde_json::to_string(message)?;
+    writer.write_all(format!("Content-Length: {}\r\n\r\n", body.len()).as_bytes()).await?;
+    writer.write_all(body.as_bytes()).await?;
+    writer.flush().await?;
+    Ok(())
+}
+
+/// Serve LSP on stdin/stdout until `exit` or end of input, re-indexing
+/// changed files after `debounce_ms` of quiet
+pub async fn handle_lsp(path: &Path, include_tests: bool, debounce_ms: u64) -> Result<()> {
+    let mut server = LspServer::new(path, include_tests).with_watch(Duration::from_millis(debounce_ms));
+    let mut stdout = tokio::io::stdout();
+    let mut poll = tokio::time::interval(WATCH_POLL_INTERVAL);
+
+    // `read_message` is not cancel safe, so messages are read on their own task
+    let (tx, mut messages) = tokio::sync::mpsc::channel(16);
+    tokio::spawn(async move {
+        let mut reader = BufReader::new(tokio::io::stdin());
+        while let Some(message) = read_message(&mut reader).await.transpose() {
+            let failed = message.is_err();
+            if tx.send(message).await.is_err() || failed {
+                break;
+            }
+        }
+    });
+
+    loop {
+        let body = tokio::select! {
+            message = messages.recv() => message,
+            _ = poll.tick() => {
+                server.tick().await;
+                continue;
+            }
+        };
+        let Some(body) = body.transpose()? else { break };
+        let response = match serde_json::from_slice::<Value>(&body) {
+            Ok(message) => server.handle_message(message).await,
+            Err(e) => Some(error_response(Value::Null, PARSE_ERROR, &format!("Parse error: {}", e))),
+        };
+
+        if let Some(response) = response {
+            write_message(&mut stdout, &response).await?;
+        }
+        if let Some(code) = server.exit_code() {
+            // The reader task's blocking stdin read would hold up runtime shutdown
+            std::process::exit(code);
+        }
+    }
+
+    Ok(())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    const GREETER: &str = "class Greeter:\n    def greet(self): return \"👋\" + hello()\n\n\ndef hello():\n    return \"hi\"\n";
+
+    /// Initialized server over an empty workspace, with `greet.py` opened from `GREETER`
+    async fn server(name: &str) -> (LspServer, String) {
+        let root = std::env::temp_dir().join(format!("nekocode-lsp-{}-{}", name, std::process::id()));
+        std::fs::create_dir_all(&root).unwrap();
+        let uri = path_to_uri(&std::fs::canonicalize(&root).unwrap().join("greet.py"));
+
+        let mut server = LspServer::new(&root, false);
+        let response = server.handle_message(json!({
+            "jsonrpc": "2.0", "id": 1, "method": "initialize",
+            "params": { "rootUri": path_to_uri(&root), "capabilities": {} }
+        })).await.unwrap();
+        assert_eq!(response["result"]["capabilities"]["textDocumentSync"]["change"], 2);
+        assert_eq!(response["result"]["capabilities"]["documentSymbolProvider"], true);
+
+        assert!(server.handle_message(json!({
+            "jsonrpc": "2.0", "method": "textDocument/didOpen",
+            "params": { "textDocument": { "uri": uri, "languageId": "python", "version": 1, "text": GREETER } }
+        })).await.is_none());
+        (server, uri)
+    }
+
+    async fn request(server: &mut LspServer, method: &str, params: Value) -> Value {
+        let response = server.handle_message(json!({ "jsonrpc": "2.0", "id": 7, "method": method, "params": params })).await.unwrap();
+        response["result"].clone()
+    }
+
+    #[test]
+    fn test_line_index_utf16() {
+        let text = "let s = \"日本\"; x\r\n𝄞y\n";
+        let lines = LineIndex::new(text);
+
+        let x = text.find('x').unwrap();
+        assert_eq!(lines.position(x), json!({ "line": 0, "character": 14 }));
+        assert_eq!(lines.offset(0, 14), x);
+        // 𝄞 is a surrogate pair
+        let y = text.find('y').unwrap();
+        assert_eq!(lines.position(y), json!({ "line": 1, "character": 2 }));
+        assert_eq!(lines.offset(1, 2), y);
+        // Past the end of a line clamps before `\r\n`
+        assert_eq!(lines.offset(0, 99), x + 1);
+        assert_eq!(lines.word_at(x + 1), Some("x"));
+    }
+
+    #[test]
+    fn test_uri_round_trip() {
+        let path = Path::new("/tmp/no such dir/ファイル.go");
+        let uri = path_to_uri(path);
+        assert_eq!(uri, "file:///tmp/no%20such%20dir/%E3%83%95%E3%82%A1%E3%82%A4%E3%83%AB.go");
+        assert_eq!(uri_to_path(&uri).unwrap(), path);
+        assert_eq!(uri_to_path("untitled:Untitled-1"), None);
+    }
+
+    #[tokio::test]
+    async fn test_framing() {
+        let input = b"Content-Length: 17\r\nContent-Type: application/vscode-jsonrpc\r\n\r\n{\"method\":\"exit\"}";
+        let mut reader = BufReader::new(&input[..]);
+        assert_eq!(read_message(&mut reader).await.unwrap().unwrap(), b"{\"method\":\"exit\"}");
+        assert!(read_message(&mut reader).await.unwrap().is_none());
+
+        let mut output = Vec::new();
+        write_message(&mut output, &json!({ "id": 1 })).await.unwrap();
+        assert_eq!(output, b"Content-Length: 8\r\n\r\n{\"id\":1}");
+    }
+
+    #[tokio::test]
+    async fn test_lifecycle() {
+        let mut server = LspServer::new(Path::new("."), false);
+        let response = server.handle_message(json!({ "jsonrpc": "2.0", "id": 1, "method": "workspace/symbol", "params": { "query": "" } })).await.unwrap();
+        assert_eq!(response["error"]["code"], SERVER_NOT_INITIALIZED);
+
+        let (mut server, _) = server("lifecycle").await;
+        let response = server.handle_message(json!({ "jsonrpc": "2.0", "id": 2, "method": "shutdown" })).await.unwrap();
+        assert_eq!(response["result"], Value::Null);
+        let response = server.handle_message(json!({ "jsonrpc": "2.0", "id": 3, "method": "workspace/symbol", "params": {} })).await.unwrap();
+        assert_eq!(response["error"]["code"], INVALID_REQUEST);
+
+        assert_eq!(server.exit_code(), None);
+        assert!(server.handle_message(json!({ "jsonrpc": "2.0", "method": "exit" })).await.is_none());
+        assert_eq!(server.exit_code(), Some(0));
+    }
+
+    /// Methods nest under their class; ranges use UTF-16 columns
+    #[tokio::test]
+    async fn test_document_symbols() {
+        let (mut server, uri) = server("symbols").await;
+        let symbols = request(&mut server, "textDocument/documentSymbol", json!({ "textDocument": { "uri": uri } })).await;
+
+        let outline: Vec<(&str, u64)> = symbols.as_array().unwrap().iter()
+            .map(|s| (s["name"].as_str().unwrap(), s["kind"].as_u64().unwrap()))
+            .collect();
+        assert_eq!(outline, vec![("Greeter", KIND_CLASS as u64), ("hello", KIND_FUNCTION as u64)]);
+
+        let greet = &symbols[0]["children"][0];
+        assert_eq!(greet["name"], "greet");
+        assert_eq!(greet["kind"], KIND_METHOD);
+        // "👋" is two UTF-16 units but four bytes
+        assert_eq!(greet["range"], json!({ "start": { "line": 1, "character": 4 }, "end": { "line": 1, "character": 42 } }));
+        assert_eq!(greet["selectionRange"], json!({ "start": { "line": 1, "character": 8 }, "end": { "line": 1, "character": 13 } }));
+        assert_eq!(symbols[1]["range"]["end"], json!({ "line": 5, "character": 15 }));
+    }
+
+    /// `didChange` applies ranged edits and re-analyzes the buffer
+    #[tokio::test]
+    async fn test_incremental_change_and_references() {
+        let (mut server, uri) = server("references").await;
+
+        let references = request(&mut server, "textDocument/references", json!({
+            "textDocument": { "uri": uri },
+            "position": { "line": 4, "character": 6 },
+            "context": { "includeDeclaration": true }
+        })).await;
+        let ranges: Vec<&Value> = references.as_array().unwrap().iter().map(|r| &r["range"]).collect();
+        assert_eq!(ranges, vec![
+            &json!({ "start": { "line": 4, "character": 4 }, "end": { "line": 4, "character": 9 } }),
+            &json!({ "start": { "line": 1, "character": 35 }, "end": { "line": 1, "character": 40 } }),
+        ]);
+        assert_eq!(references[0]["uri"], uri.as_str());
+
+        assert!(server.handle_message(json!({
+            "jsonrpc": "2.0", "method": "textDocument/didChange",
+            "params": {
+                "textDocument": { "uri": uri, "version": 2 },
+                "contentChanges": [{
+                    "range": { "start": { "line": 4, "character": 4 }, "end": { "line": 4, "character": 9 } },
+                    "text": "howdy"
+                }]
+            }
+        })).await.is_none());
+
+        let symbols = request(&mut server, "textDocument/documentSymbol", json!({ "textDocument": { "uri": uri } })).await;
+        assert_eq!(symbols[1]["name"], "howdy");
+        // The call still names `hello`, which no longer has a definition
+        let references = request(&mut server, "textDocument/references", json!({
+            "textDocument": { "uri": uri },
+            "position": { "line": 1, "character": 37 },
+            "context": { "includeDeclaration": true }
+        })).await;
+        assert_eq!(references.as_array().unwrap().len(), 1);
+    }
+
+    #[tokio::test]
+    async fn test_workspace_symbols() {
+        let (mut server, uri) = server("workspace").await;
+        let symbols = request(&mut server, "workspace/symbol", json!({ "query": "gre" })).await;
+
+        let found: Vec<(&str, u64, Option<&str>)> = symbols.as_array().unwrap().iter()
+            .map(|s| (s["name"].as_str().unwrap(), s["kind"].as_u64().unwrap(), s["containerName"].as_str()))
+            .collect();
+        assert_eq!(found, vec![("Greeter", KIND_CLASS as u64, None), ("greet", KIND_METHOD as u64, Some("Greeter"))]);
+        assert_eq!(symbols[1]["location"]["uri"], uri.as_str());
+        assert_eq!(symbols[1]["location"]["range"]["end"], json!({ "line": 1, "character": 42 }));
+    }
+}
@@ -0,0 +1,900 @@
+//! MCP (Model Context Protocol) server over stdio
+//!
+//! Speaks newline-delimited JSON-RPC 2.0 on stdin/stdout so editors and
+//! agents can call NekoCode as a tool provider. The project is analyzed once
+//! into an in-memory index, which a file watcher keeps current: changed files
+//! are re-analyzed once the debounce window has passed quietly, and any
+//! changes still pending when a tool call arrives are applied before it is
+//! answered. Without a watcher, every tool call re-checks modification times.
+//!
+//! Nothing but protocol messages may be written to stdout in this mode.
+
+use anyhow::{Context, Result};
+use notify::{Event, EventKind, RecommendedWatcher, RecursiveMode, Watcher};
+use serde_json::{json, Value};
+use std::collections::{BTreeMap, BTreeSet, HashMap, HashSet};
+use std::path::{Path, PathBuf};
+use std::sync::mpsc::{self, Receiver, TryRecvError};
+use std::time::{Duration, Instant, SystemTime};
+use tokio::io::{AsyncBufReadExt, AsyncWriteExt, BufReader};
+
+use crate::core::callgraph::CallGraph;
+use crate::core::impact::{ImpactAnalyzer, ImpactConfig, RiskLevel};
+use crate::core::session::AnalysisSession;
+use crate::core::project_config::load_analysis_config;
+use crate::core::types::{AnalysisResult, DirectoryAnalysis};
+
+/// Protocol revision implemented by this server
+pub const PROTOCOL_VERSION: &str = "2024-11-05";
+
+/// How often the serve loops check for debounced file changes
+pub const WATCH_POLL_INTERVAL: Duration = Duration::from_millis(50);
+
+// JSON-RPC error codes
+const PARSE_ERROR: i64 = -32700;
+const INVALID_REQUEST: i64 = -32600;
+const METHOD_NOT_FOUND: i64 = -32601;
+const INVALID_PARAMS: i64 = -32602;
+
+/// In-memory analysis of a project, refreshed from file modification times
+/// or, once [`ProjectIndex::watch`] is called, from filesystem events
+pub struct ProjectIndex {
+    root: PathBuf,
+    include_tests: bool,
+    session: AnalysisSession,
+    files: BTreeMap<PathBuf, (Option<SystemTime>, AnalysisResult)>,
+    symbols: SymbolTable,
+    watcher: Option<IndexWatcher>,
+}
+
+impl ProjectIndex {
+    pub fn new(root: &Path, include_tests: bool) -> Result<Self> {
+        let root = std::fs::canonicalize(root)
+            .with_context(|| format!("Failed to resolve path: {}", root.display()))?;
+        let mut config = load_analysis_config(&root)?;
+        config.include_test_files |= include_tests;
+        let include_tests = config.include_test_files;
+        
+        Ok(Self {
+            root,
+            include_tests,
+            session: AnalysisSession::with_config(config),
+            files: BTreeMap::new(),
+            symbols: SymbolTable::default(),
+            watcher: None,
+        })
+    }
+    
+    /// Re-analyze new and modified files, drop deleted ones; returns the number re-analyzed
+    pub async fn refresh(&mut self) -> Result<usize> {
+        let discovered = self.discover()?;
+        
+        let present: HashSet<&PathBuf> = discovered.iter().collect();
+        let deleted: Vec<PathBuf> = self.files.keys().filter(|path| !present.contains(path)).cloned().collect();
+        for path in deleted {
+            self.set_file(&path, None);
+        }
+        
+        let mut reanalyzed = 0;
+        for path in discovered {
+            if self.reindex(path, false).await {
+                reanalyzed += 1;
+            }
+        }
+        
+        Ok(reanalyzed)
+    }
+    
+    /// Update the index for paths reported as changed, touching nothing else;
+    /// returns the number of files re-analyzed
+    ///
+    /// Each path may be a file or a directory that was created, modified,
+    /// deleted or renamed. Deleted paths drop every file indexed at or under
+    /// them; indexed files are re-analyzed; paths not yet indexed are checked
+    /// against discovery (ignore files, excludes, test files) before they are
+    /// added.
+    pub async fn apply_changes(&mut self, changed: impl IntoIterator<Item = PathBuf>) -> Result<usize> {
+        let mut modified = BTreeSet::new();
+        let mut unknown = Vec::new();
+        for path in changed {
+            if !path.starts_with(&self.root) {
+                continue;
+            }
+            if !path.exists() {
+                for indexed in self.indexed_under(&path) {
+                    self.set_file(&indexed, None);
+                }
+            } else if self.files.contains_key(&path) {
+                modified.insert(path);
+            } else if path.is_dir() || self.may_be_source(&path) {
+                unknown.push(path);
+            }
+        }
+        
+        let mut reanalyzed = 0;
+        if !unknown.is_empty() {
+            // One walk decides which new paths discovery would include
+            let discovered: BTreeSet<PathBuf> = self.discover()?.into_iter().collect();
+            for path in unknown {
+                for indexed in self.indexed_under(&path) {
+                    if !discovered.contains(&indexed) {
+                        self.set_file(&indexed, None);
+                    }
+                }
+                let found: Vec<PathBuf> = discovered.range(path.clone()..).take_while(|p| p.starts_with(&path)).cloned().collect();
+                for file in found {
+                    // A directory event says nothing about the files in it: check their mtimes
+                    if !modified.contains(&file) && self.reindex(file, false).await {
+                        reanalyzed += 1;
+                    }
+                }
+            }
+        }
+        for path in modified {
+            if self.reindex(path, true).await {
+                reanalyzed += 1;
+            }
+        }
+        
+        Ok(reanalyzed)
+    }
+    
+    /// Watch the project root; changes then reach the index through
+    /// [`ProjectIndex::sync_watched`] instead of full refreshes
+    pub fn watch(&mut self, debounce: Duration) -> Result<()> {
+        let (tx, rx) = mpsc::channel();
+        let mut watcher = notify::recommended_watcher(move |res: notify::Result<Event>| {
+            let _ = tx.send(res);
+        })?;
+        let mode = if self.root.is_dir() { RecursiveMode::Recursive } else { RecursiveMode::NonRecursive };
+        watcher.watch(&self.root, mode)
+            .with_context(|| format!("Failed to watch {}", self.root.display()))?;
+        
+        self.watcher = Some(IndexWatcher {
+            _watcher: watcher,
+            events: rx,
+            pending: BTreeSet::new(),
+            rescan: false,
+            last_event: Instant::now(),
+            debounce,
+        });
+        Ok(())
+    }
+    
+    /// Whether filesystem events keep the index current
+    pub fn is_watching(&self) -> bool {
+        self.watcher.is_some()
+    }
+    
+    /// Apply the watched changes once they have been quiet for the debounce
+    /// window, or right away with `now` (before answering a request); returns
+    /// the number of files re-analyzed
+    pub async fn sync_watched(&mut self, now: bool) -> Result<usize> {
+        let Some(watcher) = self.watcher.as_mut() else { return Ok(0) };
+        watcher.collect();
+        if !watcher.is_due(now) {
+            return Ok(0);
+        }
+        
+        let changed = std::mem::take(&mut watcher.pending);
+        // Events were lost (queue overflow, failed update): only a full refresh is safe
+        let outcome = if std::mem::take(&mut watcher.rescan) {
+            self.refresh().await
+        } else {
+            self.apply_changes(changed).await
+        };
+        if let (Err(_), Some(watcher)) = (&outcome, self.watcher.as_mut()) {
+            watcher.rescan = true;
+            watcher.last_event = Instant::now();
+        }
+        outcome
+    }
+    
+    /// Canonical project root
+    pub fn root(&self) -> &Path {
+        &self.root
+    }
+    
+    /// Session the index analyzes with (project config applied)
+    pub fn session(&self) -> &AnalysisSession {
+        &self.session
+    }
+    
+    /// Indexed files and their results, in path order
+    pub fn results(&self) -> impl Iterator<Item = (&PathBuf, &AnalysisResult)> {
+        self.files.iter().map(|(path, (_, result))| (path, result))
+    }
+    
+    /// Helper: Files the project consists of now
+    fn discover(&self) -> Result<Vec<PathBuf>> {
+        if self.root.is_dir() {
+            self.session.discover_files(&self.root)
+        } else {
+            Ok(vec![self.root.clone()])
+        }
+    }
+    
+    /// Helper: Indexed files at or under `path`
+    fn indexed_under(&self, path: &Path) -> Vec<PathBuf> {
+        // Paths order by component, so a subtree is one contiguous range
+        self.files.range(path.to_path_buf()..)
+            .map(|(indexed, _)| indexed)
+            .take_while(|indexed| indexed.starts_with(path))
+            .cloned()
+            .collect()
+    }
+    
+    /// Helper: Whether a new file could be analyzed at all, to skip editor
+    /// swap files and build output without walking the tree
+    fn may_be_source(&self, path: &Path) -> bool {
+        match path.extension().and_then(|e| e.to_str()) {
+            Some(extension) => self.session.config().included_extensions.contains(&format!(".{}", extension)),
+            // Extension-less scripts are recognized by their shebang
+            None => true,
+        }
+    }
+    
+    /// Helper: Analyze one file unless (without `force`) its modification time
+    /// is unchanged; returns whether it was re-analyzed
+    async fn reindex(&mut self, path: PathBuf, force: bool) -> bool {
+        let modified = std::fs::metadata(&path).and_then(|m| m.modified()).ok();
+        let up_to_date = self.files.get(&path)
+            .map_or(false, |(indexed, _)| indexed.is_some() && *indexed == modified);
+        if up_to_date && !force {
+            return false;
+        }
+        
+        match self.session.analyze_file(&path).await {
+            Ok(result) => {
+                self.set_file(&path, Some((modified, result)));
+                true
+            }
+            Err(e) => {
+                if std::env::var("NEKOCODE_DEBUG").is_ok() {
+                    eprintln!("⚠️  [MCP] Failed to analyze {}: {:#}", path.display(), e);
+                }
+                self.set_file(&path, None);
+                false
+            }
+        }
+    }
+    
+    /// Helper: Replace (with `None`, remove) the result of one file, moving
+    /// its symbols and call sites in the symbol table along with it
+    fn set_file(&mut self, path: &Path, entry: Option<(Option<SystemTime>, AnalysisResult)>) {
+        if let Some((_, old)) = self.files.remove(path) {
+            self.symbols.remove_file(path, &old);
+        }
+        if let Some((modified, result)) = entry {
+            self.symbols.add_file(path, &result);
+            self.files.insert(path.to_path_buf(), (modified, result));
+        }
+    }
+    
+    /// Helper: Resolve a tool path argument against the project root
+    fn resolve(&self, path: &str) -> PathBuf {
+        let path = Path::new(path);
+        let joined = if path.is_absolute() { path.to_path_buf() } else { self.root.join(path) };
+        std::fs::canonicalize(&joined).unwrap_or(joined)
+    }
+    
+    /// Indexed result for one file
+    pub fn file(&self, path: &str) -> Option<&AnalysisResult> {
+        self.files.get(&self.resolve(path)).map(|(_, result)| result)
+    }
+    
+    /// The index as a directory analysis (cross-file links applied)
+    pub fn directory_analysis(&self) -> DirectoryAnalysis {
+        let mut analysis = DirectoryAnalysis::new(self.root.clone());
+        analysis.files = self.files.values().map(|(_, result)| result.clone()).collect();
+        crate::analyzers::go::link_implementations(&mut analysis.files);
+        crate::analyzers::go::link_unchecked_errors(&mut analysis.files);
+        crate::analyzers::cpp::link_declarations(&mut analysis.files);
+        analysis.update_summary();
+        analysis
+    }
+    
+    /// Definitions of `symbol` (`name` or `Type.name`) and the call sites referencing them
+    pub fn find_references(&self, symbol: &str) -> Value {
+        let (owner, name) = match symbol.rsplit_once('.') {
+            Some((owner, name)) => (Some(owner), name),
+            None => (None, symbol),
+        };
+        let display = |path: &Path| path.strip_prefix(&self.root).unwrap_or(path).display().to_string();
+        
+        let definitions: Vec<Value> = self.symbols.definitions(name, owner).into_iter()
+            .map(|definition| json!({
+                "file": display(&definition.file),
+                "line": definition.line,
+                "kind": definition.label(),
+            }))
+            .collect();
+        let references: Vec<Value> = self.symbols.references(name, owner).into_iter()
+            .map(|call| json!({
+                "file": display(&call.file),
+                "line": call.line,
+                "caller": call.caller,
+                "object": call.object,
+                "receiver_type": call.receiver_type,
+            }))
+            .collect();
+        
+        json!({ "symbol": symbol, "definitions": definitions, "references": references })
+    }
+}
+
+/// Filesystem events waiting to reach the index
+struct IndexWatcher {
+    /// Kept alive for as long as events are wanted
+    _watcher: RecommendedWatcher,
+    events: Receiver<notify::Result<Event>>,
+    pending: BTreeSet<PathBuf>,
+    /// Set when the watcher lost events and a full refresh is needed
+    rescan: bool,
+    last_event: Instant,
+    debounce: Duration,
+}
+
+impl IndexWatcher {
+    /// Helper: Move delivered events into `pending`
+    fn collect(&mut self) {
+        loop {
+            match self.events.try_recv() {
+                Ok(Ok(event)) => {
+                    if event.need_rescan() {
+                        self.rescan = true;
+                    } else if matches!(event.kind, EventKind::Create(_) | EventKind::Modify(_) | EventKind::Remove(_)) {
+                        self.pending.extend(event.paths);
+                    } else {
+                        continue;
+                    }
+                    self.last_event = Instant::now();
+                }
+                Ok(Err(e)) => {
+                    if std::env::var("NEKOCODE_DEBUG").is_ok() {
+                        eprintln!("⚠️  [MCP] File watch error: {}", e);
+                    }
+                    self.rescan = true;
+                    self.last_event = Instant::now();
+                }
+                Err(TryRecvError::Empty | TryRecvError::Disconnected) => break,
+            }
+        }
+    }
+    
+    /// Helper: Whether pending changes should be applied now
+    fn is_due(&self, now: bool) -> bool {
+        (self.rescan || !self.pending.is_empty()) && (now || self.last_event.elapsed() >= self.debounce)
+    }
+}
+
+/// Symbols and call sites of the indexed files, by name
+///
+/// A reference edge joins a call site to the definitions of the name it
+/// calls. Both ends are filed under the file they were found in and leave
+/// the table with it, so re-indexing a file drops exactly the edges starting
+/// or ending there: calls into a deleted function stop being references,
+/// and calls from untouched files attach to a new definition as soon as it
+/// is indexed.
+#[derive(Default)]
+struct SymbolTable {
+    definitions: HashMap<String, Vec<Definition>>,
+    calls: HashMap<String, Vec<CallSite>>,
+}
+
+/// One definition in the symbol table
+struct Definition {
+    file: PathBuf,
+    line: u32,
+    kind: DefinitionKind,
+    /// Receiver type of a function, class of a method
+    owner: Option<String>,
+}
+
+/// Where a definition was listed, which decides how `Type.name` matches it
+#[derive(Clone, Copy, PartialEq, Eq)]
+enum DefinitionKind {
+    /// File-level function, with its receiver type as owner if it has one
+    Function,
+    /// Method listed under its class
+    ClassMethod,
+    Class,
+}
+
+impl Definition {
+    /// Helper: `kind` as reported by `find_references`
+    fn label(&self) -> &'static str {
+        match self.kind {
+            DefinitionKind::Function if self.owner.is_none() => "function",
+            DefinitionKind::Function | DefinitionKind::ClassMethod => "method",
+            DefinitionKind::Class => "class",
+        }
+    }
+}
+
+/// One call site in the symbol table
+struct CallSite {
+    file: PathBuf,
+    line: u32,
+    /// Innermost function containing the call
+    caller: Option<String>,
+    object: Option<String>,
+    receiver_type: Option<String>,
+}
+
+impl SymbolTable {
+    /// Helper: File the definitions and calls of one result
+    fn add_file(&mut self, path: &Path, result: &AnalysisResult) {
+        for func in &result.functions {
+            self.definitions.entry(func.name.clone()).or_default().push(Definition {
+                file: path.to_path_buf(),
+                line: func.start_line,
+                kind: DefinitionKind::Function,
+                owner: func.metadata.get("receiver_type").cloned(),
+            });
+        }
+        for class in &result.classes {
+            self.definitions.entry(class.name.clone()).or_default()
+                .push(Definition { file: path.to_path_buf(), line: class.start_line, kind: DefinitionKind::Class, owner: None });
+            for method in &class.methods {
+                // Go methods are also listed in functions
+                let listed = result.functions.iter().any(|f| f.name == method.name && f.start_line == method.start_line);
+                if !listed {
+                    self.definitions.entry(method.name.clone()).or_default().push(Definition {
+                        file: path.to_path_buf(),
+                        line: method.start_line,
+                        kind: DefinitionKind::ClassMethod,
+                        owner: Some(class.name.clone()),
+                    });
+                }
+            }
+        }
+        
+        for call in &result.function_calls {
+            let caller = result.functions.iter()
+                .filter(|f| f.start_line <= call.line_number && call.line_number <= f.end_line)
+                .min_by_key(|f| f.end_line - f.start_line)
+                .map(|f| f.name.clone());
+            self.calls.entry(call.function_name.clone()).or_default().push(CallSite {
+                file: path.to_path_buf(),
+                line: call.line_number,
+                caller,
+                object: call.object_name.clone(),
+                receiver_type: call.receiver_type.clone(),
+            });
+        }
+    }
+    
+    /// Helper: Remove everything `add_file` filed for this result
+    fn remove_file(&mut self, path: &Path, result: &AnalysisResult) {
+        let defined = result.functions.iter().map(|f| &f.name)
+            .chain(result.classes.iter().map(|c| &c.name))
+            .chain(result.classes.iter().flat_map(|c| c.methods.iter().map(|m| &m.name)));
+        for name in defined {
+            remove_entries(&mut self.definitions, name, |definition| definition.file == path);
+        }
+        for call in &result.function_calls {
+            remove_entries(&mut self.calls, &call.function_name, |site| site.file == path);
+        }
+    }
+    
+    /// Helper: Definitions named `name`, of `owner` when given, in file and line order
+    fn definitions(&self, name: &str, owner: Option<&str>) -> Vec<&Definition> {
+        let mut found: Vec<&Definition> = self.definitions.get(name).into_iter().flatten()
+            .filter(|definition| match (definition.kind, owner) {
+                (DefinitionKind::ClassMethod, None) => false,
+                (_, None) => true,
+                (DefinitionKind::Class, Some(_)) => false,
+                (_, Some(owner)) => definition.owner.as_deref() == Some(owner),
+            })
+            .collect();
+        found.sort_by(|a, b| (&a.file, a.line).cmp(&(&b.file, b.line)));
+        found
+    }
+    
+    /// Helper: Call sites of `name`; none when nothing indexed defines it, as
+    /// a call whose target was deleted references nothing
+    fn references(&self, name: &str, owner: Option<&str>) -> Vec<&CallSite> {
+        if self.definitions(name, owner).is_empty() {
+            return Vec::new();
+        }
+        let mut found: Vec<&CallSite> = self.calls.get(name).into_iter().flatten().collect();
+        found.sort_by(|a, b| (&a.file, a.line).cmp(&(&b.file, b.line)));
+        found
+    }
+}
+
+/// Helper: Drop the entries of `name` that `is_stale`, and the name once it has none
+fn remove_entries<T>(table: &mut HashMap<String, Vec<T>>, name: &str, is_stale: impl Fn(&T) -> bool) {
+    if let Some(entries) = table.get_mut(name) {
+        entries.retain(|entry| !is_stale(entry));
+        if entries.is_empty() {
+            table.remove(name);
+        }
+    }
+}
+
+/// Tool descriptions advertised through `tools/list`
+pub fn tool_definitions() -> Value {
+    json!([
+        {
+            "name": "analyze_file",
+            "description": "Analysis result (functions, classes, imports, calls, complexity) of one file in the project",
+            "inputSchema": {
+                "type": "object",
+                "properties": {
+                    "path": { "type": "string", "description": "File path, absolute or relative to the project root" }
+                },
+                "required": ["path"]
+            }
+        },
+        {
+            "name": "find_references",
+            "description": "Definitions of a symbol and every call site referencing it",
+            "inputSchema": {
+                "type": "object",
+                "properties": {
+                    "symbol": { "type": "string", "description": "Function name, or Type.method for methods" }
+                },
+                "required": ["symbol"]
+            }
+        },
+        {
+            "name": "get_callgraph",
+            "description": "Call graph of the project, optionally limited to what a root symbol reaches",
+            "inputSchema": {
+                "type": "object",
+                "properties": {
+                    "root": { "type": "string", "description": "Root symbol (e.g. main or DataProcessor.ProcessData)" },
+                    "depth": { "type": "integer", "minimum": 0, "description": "Maximum call depth from the root" }
+                }
+            }
+        },
+        {
+            "name": "analyze_impact",
+            "description": "Impact analysis of uncommitted changes (or changes since a git reference)",
+            "inputSchema": {
+                "type": "object",
+                "properties": {
+                    "compare_ref": { "type": "string", "description": "Git reference to compare against" },
+                    "risk_threshold": { "type": "string", "enum": ["low", "medium", "high"] }
+                }
+            }
+        }
+    ])
+}
+
+/// JSON-RPC request dispatcher holding the project index
+pub struct McpServer {
+    index: ProjectIndex,
+}
+
+impl McpServer {
+    pub fn new(index: ProjectIndex) -> Self {
+        Self { index }
+    }
+    
+    /// Handle one message; notifications (no `id`) produce no response
+    pub async fn handle_message(&mut self, message: Value) -> Option<Value> {
+        let id = message.get("id").cloned();
+        let method = match message.get("method").and_then(|m| m.as_str()) {
+            Some(method) => method.to_string(),
+            None => return id.map(|id| error_response(id, INVALID_REQUEST, "Missing method")),
+        };
+        let params = message.get("params").cloned().unwrap_or(Value::Null);
+        
+        let outcome = match method.as_str() {
+            "initialize" => Ok(self.initialize(&params)),
+            "ping" => Ok(json!({})),
+            "tools/list" => Ok(json!({ "tools": tool_definitions() })),
+            "tools/call" => self.call_tool(&params).await,
+            _ if method.starts_with("notifications/") => return None,
+            _ => Err((METHOD_NOT_FOUND, format!("Method not found: {}", method))),
+        };
+        
+        let id = id?;
+        Some(match outcome {
+            Ok(result) => json!({ "jsonrpc": "2.0", "id": id, "result": result }),
+            Err((code, message)) => error_response(id, code, &message),
+        })
+    }
+    
+    /// Helper: `initialize` handshake
+    fn initialize(&self, params: &Value) -> Value {
+        let version = params.get("protocolVersion")
+            .and_then(|v| v.as_str())
+            .unwrap_or(PROTOCOL_VERSION);
+        
+        json!({
+            "protocolVersion": version,
+            "capabilities": {
+                "tools": { "listChanged": false }
+            },
+            "serverInfo": {
+                "name": "nekocode-rust",
+                "version": env!("CARGO_PKG_VERSION")
+            },
+            "instructions": "Code analysis of the project rooted at the server's PATH. Call tools/list for the tool input schemas."
+        })
+    }
+    
+    /// Helper: `tools/call`; tool failures are reported in the result with `isError`
+    async fn call_tool(&mut self, params: &Value) -> std::result::Result<Value, (i64, String)> {
+        let name = params.get("name")
+            .and_then(|n| n.as_str())
+            .ok_or_else(|| (INVALID_PARAMS, "Missing tool name".to_string()))?;
+        let arguments = params.get("arguments").cloned().unwrap_or_else(|| json!({}));
+        let string_arg = |key: &str| arguments.get(key).and_then(|v| v.as_str()).map(String::from);
+        
+        let output = match name {
+            "analyze_file" => {
+                let path = string_arg("path").ok_or_else(|| (INVALID_PARAMS, "Missing argument: path".to_string()))?;
+                self.refresh().await.and_then(|_| {
+                    self.index.file(&path)
+                        .ok_or_else(|| anyhow::anyhow!("File not in the analyzed project: {}", path))
+                        .and_then(|result| Ok(serde_json::to_value(result)?))
+                })
+            }
+            "find_references" => {
+                let symbol = string_arg("symbol").ok_or_else(|| (INVALID_PARAMS, "Missing argument: symbol".to_string()))?;
+                self.refresh().await.map(|_| self.index.find_references(&symbol))
+            }
+            "get_callgraph" => {
+                let depth = arguments.get("depth").and_then(|v| v.as_u64()).map(|d| d as usize);
+                let root = string_arg("root");
+                self.refresh().await.and_then(|_| {
+                    let mut graph = CallGraph::build(&self.index.directory_analysis());
+                    if let Some(root) = root {
+                        graph = graph.subgraph(&root, depth)?;
+                    }
+                    Ok(serde_json::to_value(&graph)?)
+                })
+            }
+            "analyze_impact" => {
+                let risk_threshold = string_arg("risk_threshold").unwrap_or_else(|| "low".to_string());
+                match RiskLevel::parse(&risk_threshold) {
+                    Some(risk_level) => {
+                        let config = ImpactConfig {
+                            include_tests: self.index.include_tests,
+                            compare_ref: string_arg("compare_ref"),
+                            skip_circular: false,
+                            risk_threshold: risk_level,
+                            verbose: false,
+                        };
+                        match ImpactAnalyzer::new(config).analyze_impact(&self.index.root).await {
+                            Ok(result) => serde_json::to_value(&result).map_err(Into::into),
+                            Err(e) => Err(e),
+                        }
+                    }
+                    None => Err(anyhow::anyhow!("Invalid risk threshold: {}", risk_threshold)),
+                }
+            }
+            _ => return Err((INVALID_PARAMS, format!("Unknown tool: {}", name))),
+        };
+        
+        Ok(match output {
+            Ok(value) => json!({
+                "content": [{ "type": "text", "text": serde_json::to_string_pretty(&value).unwrap_or_default() }],
+                "isError": false
+            }),
+            Err(e) => json!({
+                "content": [{ "type": "text", "text": format!("{:#}", e) }],
+                "isError": true
+            }),
+        })
+    }
+    
+    /// Apply watched changes whose debounce window has passed; call periodically
+    pub async fn tick(&mut self) {
+        match self.index.sync_watched(false).await {
+            Ok(reanalyzed) if reanalyzed > 0 && std::env::var("NEKOCODE_DEBUG").is_ok() => {
+                eprintln!("🔄 [MCP] Re-indexed {} changed file(s)", reanalyzed);
+            }
+            Ok(_) => {}
+            // The next sync falls back to a full refresh
+            Err(e) => eprintln!("⚠️  [MCP] Re-indexing failed: {:#}", e),
+        }
+    }
+    
+    /// Helper: Bring the index up to date before answering
+    async fn refresh(&mut self) -> Result<()> {
+        let reanalyzed = if self.index.is_watching() {
+            self.index.sync_watched(true).await?
+        } else {
+            self.index.refresh().await?
+        };
+        if reanalyzed > 0 && std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("🔄 [MCP] Re-indexed {} file(s)", reanalyzed);
+        }
+        Ok(())
+    }
+}
+
+/// Helper: JSON-RPC error object
+fn error_response(id: Value, code: i64, message: &str) -> Value {
+    json!({ "jsonrpc": "2.0", "id": id, "error": { "code": code, "message": message } })
+}
+
+/// Serve MCP on stdin/stdout until stdin closes, re-indexing changed files
+/// after `debounce_ms` of quiet
+pub async fn handle_mcp(path: &Path, include_tests: bool, debounce_ms: u64) -> Result<()> {
+    let mut index = ProjectIndex::new(path, include_tests)?;
+    index.refresh().await?;
+    eprintln!("# nekocode mcp: indexed {} files under {}", index.files.len(), index.root.display());
+    if let Err(e) = index.watch(Duration::from_millis(debounce_ms)) {
+        eprintln!("# nekocode mcp: not watching ({:#}); checking modification times per call", e);
+    }
+    
+    let mut server = McpServer::new(index);
+    let mut lines = BufReader::new(tokio::io::stdin()).lines();
+    let mut stdout = tokio::io::stdout();
+    let mut poll = tokio::time::interval(WATCH_POLL_INTERVAL);
+    
+    loop {
+        // `next_line` is cancel safe, so a tick never loses input
+        let line = tokio::select! {
+            line = lines.next_line() => line?,
+            _ = poll.tick() => {
+                server.tick().await;
+                continue;
+            }
+        };
+        let Some(line) = line else { break };
+        if line.trim().is_empty() {
+            continue;
+        }
+        
+        let response = match serde_json::from_str::<Value>(&line) {
+            Ok(message) => server.handle_message(message).await,
+            Err(e) => Some(error_response(Value::Null, PARSE_ERROR, &format!("Parse error: {}", e))),
+        };
+        
+        if let Some(response) = response {
+            stdout.write_all(serde_json::to_string(&response)?.as_bytes()).await?;
+            stdout.write_all(b"\n").await?;
+            stdout.flush().await?;
+        }
+    }
+    
+    Ok(())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, FunctionCall, FunctionInfo, Language};
+    
+    fn server() -> McpServer {
+        McpServer::new(ProjectIndex::new(Path::new("."), false).unwrap())
+    }
+    
+    #[tokio::test]
+    async fn test_initialize_and_tools_list() {
+        let mut server = server();
+        
+        let response = server.handle_message(json!({
+            "jsonrpc": "2.0", "id": 1, "method": "initialize",
+            "params": { "protocolVersion": "2025-03-26", "capabilities": {} }
+        })).await.unwrap();
+        assert_eq!(response["id"], 1);
+        assert_eq!(response["result"]["protocolVersion"], "2025-03-26");
+        assert!(response["result"]["capabilities"]["tools"].is_object());
+        
+        // Notifications are not answered
+        assert!(server.handle_message(json!({ "jsonrpc": "2.0", "method": "notifications/initialized" })).await.is_none());
+        
+        let response = server.handle_message(json!({ "jsonrpc": "2.0", "id": 2, "method": "tools/list" })).await.unwrap();
+        let names: Vec<&str> = response["result"]["tools"].as_array().unwrap()
+            .iter()
+            .map(|t| t["name"].as_str().unwrap())
+            .collect();
+        assert_eq!(names, vec!["analyze_file", "find_references", "get_callgraph", "analyze_impact"]);
+        assert!(response["result"]["tools"][0]["inputSchema"]["required"].is_array());
+    }
+    
+    #[tokio::test]
+    async fn test_errors() {
+        let mut server = server();
+        
+        let response = server.handle_message(json!({ "jsonrpc": "2.0", "id": 3, "method": "resources/list" })).await.unwrap();
+        assert_eq!(response["error"]["code"], METHOD_NOT_FOUND);
+        
+        let response = server.handle_message(json!({
+            "jsonrpc": "2.0", "id": 4, "method": "tools/call", "params": { "name": "analyze_file", "arguments": {} }
+        })).await.unwrap();
+        assert_eq!(response["error"]["code"], INVALID_PARAMS);
+    }
+    
+    #[test]
+    fn test_find_references() {
+        let mut index = ProjectIndex::new(Path::new("."), false).unwrap();
+        let path = index.root.join("main.go");
+        
+        let mut result = AnalysisResult::new(FileInfo::new(path.clone()), Language::Go);
+        let mut helper = FunctionInfo::new("helper".to_string());
+        helper.start_line = 1;
+        helper.end_line = 3;
+        let mut main = FunctionInfo::new("main".to_string());
+        main.start_line = 5;
+        main.end_line = 8;
+        result.functions = vec![helper, main];
+        result.function_calls = vec![FunctionCall::new("helper".to_string(), 6), FunctionCall::new("other".to_string(), 7)];
+        index.set_file(&path, Some((None, result)));
+        
+        let found = index.find_references("helper");
+        assert_eq!(found["definitions"].as_array().unwrap().len(), 1);
+        assert_eq!(found["definitions"][0]["kind"], "function");
+        let references = found["references"].as_array().unwrap();
+        assert_eq!(references.len(), 1);
+        assert_eq!(references[0]["line"], 6);
+        assert_eq!(references[0]["caller"], "main");
+        assert_eq!(references[0]["file"], "main.go");
+    }
+    
+    /// `util.go` defines `helper`, `main.go` calls it
+    async fn project() -> (tempfile::TempDir, ProjectIndex) {
+        let dir = tempfile::TempDir::new().unwrap();
+        std::fs::write(dir.path().join("util.go"), "package app\n\nfunc helper() int {\n\treturn 2\n}\n").unwrap();
+        std::fs::write(dir.path().join("main.go"), "package app\n\nfunc run() int {\n\treturn helper()\n}\n").unwrap();
+        let mut index = ProjectIndex::new(dir.path(), false).unwrap();
+        assert_eq!(index.refresh().await.unwrap(), 2);
+        (dir, index)
+    }
+    
+    fn reference_sites(index: &ProjectIndex, symbol: &str) -> Vec<(String, u64)> {
+        index.find_references(symbol)["references"].as_array().unwrap().iter()
+            .map(|r| (r["file"].as_str().unwrap().to_string(), r["line"].as_u64().unwrap()))
+            .collect()
+    }
+    
+    #[tokio::test]
+    async fn test_apply_changes() {
+        let (_dir, mut index) = project().await;
+        let root = index.root().to_path_buf();
+        assert_eq!(reference_sites(&index, "helper"), vec![("main.go".to_string(), 4)]);
+        
+        // Renaming the definition leaves the call dangling
+        std::fs::write(root.join("util.go"), "package app\n\nfunc assist() int {\n\treturn 2\n}\n").unwrap();
+        assert_eq!(index.apply_changes(vec![root.join("util.go")]).await.unwrap(), 1);
+        assert!(index.find_references("helper")["definitions"].as_array().unwrap().is_empty());
+        assert!(reference_sites(&index, "helper").is_empty());
+        
+        // A new file defining it again picks up the untouched caller
+        std::fs::create_dir(root.join("lib")).unwrap();
+        std::fs::write(root.join("lib/helper.go"), "package app\n\nfunc helper() int {\n\treturn 3\n}\n").unwrap();
+        std::fs::write(root.join("lib/notes.txt"), "helper()\n").unwrap();
+        assert_eq!(index.apply_changes(vec![root.join("lib")]).await.unwrap(), 1);
+        assert_eq!(index.results().count(), 3);
+        assert_eq!(index.find_references("helper")["definitions"][0]["file"], "lib/helper.go");
+        assert_eq!(reference_sites(&index, "helper"), vec![("main.go".to_string(), 4)]);
+        
+        // Deleting the caller drops its references; deleting the directory drops its symbols
+        std::fs::remove_file(root.join("main.go")).unwrap();
+        assert_eq!(index.apply_changes(vec![root.join("main.go")]).await.unwrap(), 0);
+        assert!(reference_sites(&index, "helper").is_empty());
+        std::fs::remove_dir_all(root.join("lib")).unwrap();
+        index.apply_changes(vec![root.join("lib")]).await.unwrap();
+        assert!(index.find_references("helper")["definitions"].as_array().unwrap().is_empty());
+        let indexed: Vec<&PathBuf> = index.results().map(|(path, _)| path).collect();
+        assert_eq!(indexed, vec![&root.join("util.go")]);
+        assert!(index.symbols.calls.is_empty());
+    }
+    
+    #[tokio::test]
+    async fn test_watched_changes() {
+        let (_dir, mut index) = project().await;
+        let root = index.root().to_path_buf();
+        index.watch(Duration::from_millis(20)).unwrap();
+        
+        std::fs::write(root.join("extra.go"), "package app\n\nfunc extra() int {\n\treturn helper()\n}\n").unwrap();
+        std::fs::remove_file(root.join("main.go")).unwrap();
+        let mut waited = Duration::ZERO;
+        while reference_sites(&index, "helper") != vec![("extra.go".to_string(), 4)] {
+            assert!(waited < Duration::from_secs(5), "watched changes were not applied");
+            tokio::time::sleep(WATCH_POLL_INTERVAL).await;
+            waited += WATCH_POLL_INTERVAL;
+            index.sync_watched(false).await.unwrap();
+        }
+        assert_eq!(index.results().count(), 2);
+    }
+}
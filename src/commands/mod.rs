@@ -1,2 +1,5 @@
 pub mod session_update;
-pub mod watch;
\ No newline at end of file
+pub mod watch;
+pub mod mcp;
+pub mod lsp;
+pub mod repl;
@@ -0,0 +1,243 @@
+//! Interactive session (`nekocode-rust session <PATH>`)
+//!
+//! The project is indexed once; each command then answers from the warm
+//! index, re-analyzing only files modified since the previous command. Every
+//! answer is the JSON the matching CLI command or MCP tool produces, so it
+//! can be copied out as is. Line editing and history come from rustyline;
+//! history persists in `.nekocode_sessions/repl_history`.
+
+use anyhow::Result;
+use rustyline::error::ReadlineError;
+use rustyline::DefaultEditor;
+use serde_json::{json, Value};
+use std::collections::BTreeSet;
+use std::path::{Path, PathBuf};
+
+use crate::commands::mcp::ProjectIndex;
+use crate::core::callgraph::CallGraph;
+
+/// History file, next to the saved sessions
+const HISTORY_FILE: &str = ".nekocode_sessions/repl_history";
+
+/// Shown by `help`
+pub const HELP: &str = "\
+Commands:
+  find <name>          Classes and functions whose name contains <name>
+  refs <symbol>        Definitions and call sites of <symbol> (name or Type.method)
+  callers <symbol> [depth]
+                       Call graph of the functions calling <symbol> (default depth 1)
+  complexity <file>    Complexity of a file and each of its functions
+  impact <symbol>      Transitive callers of <symbol> and the files they live in
+  help                 Show this help
+  quit / exit          Leave the session (Ctrl-D works too)";
+
+/// Result of one command line
+#[derive(Debug, PartialEq)]
+pub enum ReplOutput {
+    Json(Value),
+    Text(String),
+    Quit,
+}
+
+/// Command interpreter over a project index
+pub struct Repl {
+    index: ProjectIndex,
+}
+
+impl Repl {
+    pub fn new(index: ProjectIndex) -> Self {
+        Self { index }
+    }
+
+    /// Run one command line against the (refreshed) index
+    pub async fn execute(&mut self, line: &str) -> Result<ReplOutput> {
+        let words: Vec<&str> = line.split_whitespace().collect();
+        let Some((&command, args)) = words.split_first() else {
+            return Ok(ReplOutput::Text(String::new()));
+        };
+        let argument = |usage: &str| args.first().copied()
+            .ok_or_else(|| anyhow::anyhow!("Usage: {}", usage));
+
+        match command {
+            "help" | "?" => return Ok(ReplOutput::Text(HELP.to_string())),
+            "quit" | "exit" => return Ok(ReplOutput::Quit),
+            "find" | "refs" | "callers" | "complexity" | "impact" => {}
+            _ => anyhow::bail!("Unknown command: {} (type `help` for the list)", command),
+        }
+
+        self.index.refresh().await?;
+        let value = match command {
+            "find" => self.find(argument("find <name>")?),
+            "refs" => self.index.find_references(argument("refs <symbol>")?),
+            "callers" => {
+                let symbol = argument("callers <symbol> [depth]")?;
+                let depth = match args.get(1) {
+                    Some(depth) => depth.parse().map_err(|_| anyhow::anyhow!("Invalid depth: {}", depth))?,
+                    None => 1,
+                };
+                let graph = CallGraph::build(&self.index.directory_analysis()).callers(symbol, Some(depth))?;
+                serde_json::to_value(&graph)?
+            }
+            "complexity" => self.complexity(argument("complexity <file>")?)?,
+            "impact" => self.impact(argument("impact <symbol>")?)?,
+            _ => unreachable!("commands are checked above"),
+        };
+        Ok(ReplOutput::Json(value))
+    }
+
+    /// Helper: Classes and functions matching a name, case-insensitively (as `session-command find`)
+    fn find(&self, term: &str) -> Value {
+        let term_lower = term.to_lowercase();
+        let mut matches = Vec::new();
+        for (path, result) in self.index.results() {
+            for class in result.classes.iter().filter(|c| c.name.to_lowercase().contains(&term_lower)) {
+                matches.push(json!({
+                    "type": "class",
+                    "name": class.name,
+                    "file": path,
+                    "line_start": class.start_line,
+                    "line_end": class.end_line,
+                }));
+            }
+            for function in result.functions.iter().filter(|f| f.name.to_lowercase().contains(&term_lower)) {
+                matches.push(json!({
+                    "type": "function",
+                    "name": function.name,
+                    "file": path,
+                    "line_start": function.start_line,
+                    "line_end": function.end_line,
+                    "parameters": function.parameters,
+                }));
+            }
+        }
+        json!({ "search_term": term, "total_matches": matches.len(), "matches": matches })
+    }
+
+    /// Helper: File complexity plus the complexity of each function, as in `analyze` output
+    fn complexity(&self, file: &str) -> Result<Value> {
+        let result = self.index.file(file)
+            .ok_or_else(|| anyhow::anyhow!("File not in the analyzed project: {}", file))?;
+        let functions: Vec<Value> = result.functions.iter()
+            .map(|function| json!({
+                "name": function.name,
+                "start_line": function.start_line,
+                "end_line": function.end_line,
+                "complexity": function.complexity.cyclomatic_complexity,
+                "complexity_detail": function.complexity,
+            }))
+            .collect();
+        Ok(json!({
+            "file": result.file_info.path,
+            "complexity": result.complexity,
+            "functions": functions,
+        }))
+    }
+
+    /// Helper: Everything that (transitively) calls `symbol`
+    fn impact(&self, symbol: &str) -> Result<Value> {
+        let graph = CallGraph::build(&self.index.directory_analysis()).callers(symbol, None)?;
+        let affected_files: BTreeSet<&PathBuf> = graph.nodes.iter().map(|node| &node.file).collect();
+        Ok(json!({
+            "symbol": symbol,
+            "affected_files": affected_files,
+            "callers": graph,
+        }))
+    }
+}
+
+/// Index `path`, then read commands from the prompt until `quit` or end of input
+pub async fn handle_session(path: &Path, include_tests: bool) -> Result<()> {
+    let mut index = ProjectIndex::new(path, include_tests)?;
+    let indexed = index.refresh().await?;
+    eprintln!("# nekocode session: indexed {} files under {} (type `help` for commands)", indexed, index.root().display());
+
+    let mut repl = Repl::new(index);
+    let mut editor = DefaultEditor::new()?;
+    // A missing history file just means a first session
+    let _ = editor.load_history(HISTORY_FILE);
+
+    loop {
+        let line = match editor.readline("nekocode> ") {
+            Ok(line) => line,
+            Err(ReadlineError::Interrupted) => continue,
+            Err(ReadlineError::Eof) => break,
+            Err(e) => return Err(e.into()),
+        };
+        if line.trim().is_empty() {
+            continue;
+        }
+        editor.add_history_entry(line.as_str())?;
+
+        match repl.execute(&line).await {
+            Ok(ReplOutput::Json(value)) => println!("{}", serde_json::to_string_pretty(&value)?),
+            Ok(ReplOutput::Text(text)) => println!("{}", text),
+            Ok(ReplOutput::Quit) => break,
+            Err(e) => eprintln!("❌ {:#}", e),
+        }
+    }
+
+    if let Some(dir) = Path::new(HISTORY_FILE).parent() {
+        let _ = std::fs::create_dir_all(dir);
+    }
+    if let Err(e) = editor.save_history(HISTORY_FILE) {
+        eprintln!("⚠️  Failed to save history: {}", e);
+    }
+    Ok(())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use tempfile::TempDir;
+
+    async fn repl() -> (TempDir, Repl) {
+        let temp_dir = TempDir::new().unwrap();
+        std::fs::write(
+            temp_dir.path().join("main.go"),
+            "package main\n\nfunc helper() int {\n\treturn 1\n}\n\nfunc run() int {\n\treturn helper()\n}\n\nfunc main() {\n\trun()\n}\n",
+        ).unwrap();
+        let mut index = ProjectIndex::new(temp_dir.path(), false).unwrap();
+        index.refresh().await.unwrap();
+        (temp_dir, Repl::new(index))
+    }
+
+    fn as_json(output: ReplOutput) -> Value {
+        match output {
+            ReplOutput::Json(value) => value,
+            other => panic!("expected JSON, got {:?}", other),
+        }
+    }
+
+    #[tokio::test]
+    async fn test_commands() {
+        let (_temp_dir, mut repl) = repl().await;
+
+        let found = as_json(repl.execute("find HELP").await.unwrap());
+        assert_eq!(found["total_matches"], 1);
+        assert_eq!(found["matches"][0]["name"], "helper");
+
+        let refs = as_json(repl.execute("refs helper").await.unwrap());
+        assert_eq!(refs["references"][0]["caller"], "run");
+
+        let callers = as_json(repl.execute("callers helper").await.unwrap());
+        assert_eq!(callers["edges"].as_array().unwrap().len(), 1);
+
+        let impact = as_json(repl.execute("impact helper").await.unwrap());
+        assert_eq!(impact["callers"]["edges"].as_array().unwrap().len(), 2);
+        assert_eq!(impact["affected_files"].as_array().unwrap().len(), 1);
+
+        let complexity = as_json(repl.execute("complexity main.go").await.unwrap());
+        assert_eq!(complexity["functions"].as_array().unwrap().len(), 3);
+    }
+
+    #[tokio::test]
+    async fn test_help_quit_and_errors() {
+        let (_temp_dir, mut repl) = repl().await;
+
+        assert_eq!(repl.execute("help").await.unwrap(), ReplOutput::Text(HELP.to_string()));
+        assert_eq!(repl.execute("exit").await.unwrap(), ReplOutput::Quit);
+        assert!(repl.execute("frobnicate").await.is_err());
+        assert!(repl.execute("refs").await.is_err());
+        assert!(repl.execute("complexity missing.go").await.is_err());
+    }
+}
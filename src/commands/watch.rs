@@ -4,6 +4,7 @@
 //! for changes and automatically triggers session updates.
 
 use anyhow::{Context, Result};
+use std::collections::{HashMap, HashSet};
 use std::path::{Path, PathBuf};
 use std::fs;
 use std::process::{Command, Stdio};
@@ -14,7 +15,10 @@ use notify::{Watcher, RecursiveMode, Event, EventKind};
 use serde::{Deserialize, Serialize};
 use chrono::{DateTime, Utc};
 
-use crate::core::session::SessionManager;
+use crate::core::incremental::diff_symbols;
+use crate::core::session::{AnalysisSession, SessionManager};
+use crate::core::project_config::load_analysis_config;
+use crate::core::types::AnalysisResult;
 
 /// File watching status for a session
 #[derive(Debug, Clone, Serialize, Deserialize)]
@@ -78,9 +82,22 @@ impl Default for WatchConfig {
                 "py".to_string(),
                 "pyw".to_string(),
                 "pyi".to_string(),
+                "ipynb".to_string(),
                 "cs".to_string(),
                 "go".to_string(),
                 "rs".to_string(),
+                "rb".to_string(),
+                "java".to_string(),
+                "kt".to_string(),
+                "kts".to_string(),
+                "swift".to_string(),
+                "php".to_string(),
+                "phtml".to_string(),
+                "lua".to_string(),
+                "dart".to_string(),
+                "scala".to_string(),
+                "sc".to_string(),
+                "zig".to_string(),
             ],
             include_important_files: vec![
                 "Makefile".to_string(),
@@ -102,6 +119,19 @@ impl Default for WatchConfig {
 }
 
 impl WatchConfig {
+    /// Check if a path matches one of the exclude patterns
+    pub fn is_excluded(&self, path: &Path) -> bool {
+        let path_str = path.to_string_lossy();
+        self.exclude_patterns.iter().any(|pattern| path_str.contains(pattern.as_str()))
+    }
+
+    /// Check if a file has a supported source extension
+    pub fn is_source_file(&self, path: &Path) -> bool {
+        path.extension()
+            .map(|extension| self.include_extensions.contains(&extension.to_string_lossy().to_lowercase()))
+            .unwrap_or(false)
+    }
+
     /// Load configuration from nekocode_config.json
     fn load_from_config() -> Result<Self> {
         let config_path = std::path::Path::new("nekocode_config.json");
@@ -239,11 +269,8 @@ impl FileWatcher {
     /// Check if a file should be watched based on configuration
     fn should_watch_file(&self, path: &Path) -> bool {
         // Check if path contains any excluded patterns
-        let path_str = path.to_string_lossy();
-        for pattern in &self.config.exclude_patterns {
-            if path_str.contains(pattern) {
-                return false;
-            }
+        if self.config.is_excluded(path) {
+            return false;
         }
 
         // Check if it's an important file (without extension)
@@ -254,13 +281,7 @@ impl FileWatcher {
             }
         }
 
-        // Check if file has a supported extension
-        if let Some(extension) = path.extension() {
-            let ext_str = extension.to_string_lossy().to_lowercase();
-            return self.config.include_extensions.contains(&ext_str);
-        }
-
-        false
+        self.config.is_source_file(path)
     }
 
     /// Start watching files in the background
@@ -572,6 +593,103 @@ pub async fn handle_watch_daemon(session_id: &str) -> Result<()> {
     Ok(())
 }
 
+/// Watch a path in the foreground and print symbol changes as they happen
+///
+/// Output is one line per event: `+`/`-`/`~` for added/removed/modified
+/// symbols, `!` for analysis errors and `#` for status lines.
+pub async fn handle_watch_path(path: &Path, debounce_ms: u64, include_tests: bool) -> Result<()> {
+    let watch_config = WatchConfig::default();
+    let root = fs::canonicalize(path)
+        .with_context(|| format!("Failed to resolve path: {}", path.display()))?;
+    let display_root = if root.is_dir() { root.clone() } else { root.parent().unwrap_or(&root).to_path_buf() };
+
+    // The content-hash cache keeps re-analysis limited to files whose content changed
+    let mut config = load_analysis_config(&root)?;
+    config.cache_enabled = true;
+    let include_tests = include_tests || config.include_test_files;
+    let mut session = AnalysisSession::with_config(config);
+
+    let initial = session.analyze_path(&root, include_tests).await?;
+    let mut snapshot: HashMap<PathBuf, AnalysisResult> = HashMap::new();
+    for result in initial.files {
+        let key = fs::canonicalize(&result.file_info.path).unwrap_or_else(|_| result.file_info.path.clone());
+        snapshot.insert(key, result);
+    }
+
+    let symbol_count: usize = snapshot.values().map(|r| r.functions.len() + r.classes.len()).sum();
+    println!("# watching {} files={} symbols={}", root.display(), snapshot.len(), symbol_count);
+
+    let (tx, rx): (Sender<Event>, Receiver<Event>) = mpsc::channel();
+    let mut watcher = notify::recommended_watcher(move |res: Result<Event, notify::Error>| {
+        match res {
+            Ok(event) => {
+                let _ = tx.send(event);
+            }
+            Err(e) => eprintln!("File watch error: {:?}", e),
+        }
+    })?;
+    watcher.watch(&root, RecursiveMode::Recursive)?;
+
+    let debounce_duration = Duration::from_millis(debounce_ms);
+    let mut pending: HashSet<PathBuf> = HashSet::new();
+    let mut last_event = Instant::now();
+
+    loop {
+        match rx.recv_timeout(Duration::from_millis(50)) {
+            Ok(event) => {
+                if matches!(event.kind, EventKind::Create(_) | EventKind::Modify(_) | EventKind::Remove(_)) {
+                    for changed in event.paths {
+                        if watch_config.is_source_file(&changed) && !watch_config.is_excluded(&changed) {
+                            pending.insert(changed);
+                            last_event = Instant::now();
+                        }
+                    }
+                }
+                continue;
+            }
+            Err(mpsc::RecvTimeoutError::Timeout) => {}
+            Err(mpsc::RecvTimeoutError::Disconnected) => break,
+        }
+
+        // Quiet for the debounce window: re-analyze the touched files only
+        if pending.is_empty() || last_event.elapsed() < debounce_duration {
+            continue;
+        }
+
+        let start = Instant::now();
+        let mut changed_files: Vec<PathBuf> = pending.drain().collect();
+        changed_files.sort();
+
+        for file in &changed_files {
+            let display_path = file.strip_prefix(&display_root).unwrap_or(file);
+            let current = if file.exists() {
+                match session.analyze_file(file).await {
+                    Ok(result) => Some(result),
+                    Err(e) => {
+                        println!("! {} {}", display_path.display(), e);
+                        continue;
+                    }
+                }
+            } else {
+                None
+            };
+
+            for change in diff_symbols(display_path, snapshot.get(file), current.as_ref()) {
+                println!("{}", change.to_line());
+            }
+
+            match current {
+                Some(result) => snapshot.insert(file.clone(), result),
+                None => snapshot.remove(file),
+            };
+        }
+
+        println!("# reanalyzed files={} ms={}", changed_files.len(), start.elapsed().as_millis());
+    }
+
+    Ok(())
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -813,7 +931,7 @@ mod tests {
             "test.ts", "test.tsx",                           // TypeScript
             "test.cpp", "test.cxx", "test.cc", "test.hpp",  // C++
             "test.c", "test.h",                              // C
-            "test.py", "test.pyw", "test.pyi",              // Python
+            "test.py", "test.pyw", "test.pyi", "test.ipynb", // Python
             "test.cs",                                       // C#
             "test.go",                                       // Go
             "test.rs",                                       // Rust
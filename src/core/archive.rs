@@ -0,0 +1,164 @@
+//! Source archives (`.zip`, `.tar`, `.tar.gz` / `.tgz`) read in memory
+//!
+//! Entries are never extracted to disk. Each analyzed entry is reported under
+//! a virtual path `<archive>!<entry>` (e.g. `deps.tar.gz!pkg/src/lib.go`), so
+//! the archive reads as one project rooted at the archive itself.
+
+use anyhow::{Context, Result};
+use std::fs::File;
+use std::io::{BufReader, Read};
+use std::path::{Component, Path, PathBuf};
+
+/// Separator between the archive path and the entry name in virtual paths
+pub const ENTRY_SEPARATOR: char = '!';
+
+/// Supported archive formats
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum ArchiveKind {
+    Zip,
+    Tar,
+    TarGz,
+}
+
+impl ArchiveKind {
+    /// Archive format from the file name; `None` for anything else
+    pub fn detect(path: &Path) -> Option<Self> {
+        let name = path.file_name()?.to_str()?.to_lowercase();
+        if name.ends_with(".zip") {
+            Some(ArchiveKind::Zip)
+        } else if name.ends_with(".tar.gz") || name.ends_with(".tgz") {
+            Some(ArchiveKind::TarGz)
+        } else if name.ends_with(".tar") {
+            Some(ArchiveKind::Tar)
+        } else {
+            None
+        }
+    }
+}
+
+/// A regular file read from an archive
+#[derive(Debug, Clone)]
+pub struct ArchiveEntry {
+    /// `/`-separated path inside the archive
+    pub name: String,
+    pub content: Vec<u8>,
+}
+
+/// An entry that was accepted by name but not read
+#[derive(Debug, Clone)]
+pub struct SkippedEntry {
+    pub name: String,
+    pub size_bytes: u64,
+}
+
+/// Virtual path of an archive entry: `<archive>!<name>`
+pub fn entry_path(archive: &Path, name: &str) -> PathBuf {
+    PathBuf::from(format!("{}{}{}", archive.display(), ENTRY_SEPARATOR, name))
+}
+
+/// Read the regular files of an archive whose names `accept` admits
+///
+/// Entries over `max_size` bytes are returned as skipped without being read;
+/// directories, links and entries escaping the archive root (`../`) are
+/// ignored.
+pub fn read_entries(
+    path: &Path,
+    max_size: Option<u64>,
+    mut accept: impl FnMut(&str) -> bool,
+) -> Result<(Vec<ArchiveEntry>, Vec<SkippedEntry>)> {
+    let kind = ArchiveKind::detect(path)
+        .with_context(|| format!("Not a supported archive: {}", path.display()))?;
+    let file = File::open(path).with_context(|| format!("Failed to open archive: {}", path.display()))?;
+    let reader = BufReader::new(file);
+
+    let mut entries = Vec::new();
+    let mut skipped = Vec::new();
+    let mut visit = |name: String, size_bytes: u64, content: &mut dyn Read| -> Result<()> {
+        if !accept(&name) {
+            return Ok(());
+        }
+        if max_size.map_or(false, |limit| size_bytes > limit) {
+            skipped.push(SkippedEntry { name, size_bytes });
+            return Ok(());
+        }
+        let mut bytes = Vec::with_capacity(size_bytes as usize);
+        content.read_to_end(&mut bytes)
+            .with_context(|| format!("Failed to read {}{}{}", path.display(), ENTRY_SEPARATOR, name))?;
+        entries.push(ArchiveEntry { name, content: bytes });
+        Ok(())
+    };
+
+    match kind {
+        ArchiveKind::Zip => {
+            let mut archive = zip::ZipArchive::new(reader)
+                .with_context(|| format!("Invalid zip archive: {}", path.display()))?;
+            for index in 0..archive.len() {
+                let mut entry = archive.by_index(index)?;
+                if !entry.is_file() {
+                    continue;
+                }
+                let Some(name) = entry.enclosed_name().as_deref().and_then(entry_name) else { continue };
+                let size = entry.size();
+                visit(name, size, &mut entry)?;
+            }
+        }
+        ArchiveKind::Tar => read_tar(tar::Archive::new(reader), path, &mut visit)?,
+        ArchiveKind::TarGz => read_tar(tar::Archive::new(flate2::read::GzDecoder::new(reader)), path, &mut visit)?,
+    }
+
+    Ok((entries, skipped))
+}
+
+/// Helper: Visit the regular files of a tar stream in order
+fn read_tar<R: Read>(
+    mut archive: tar::Archive<R>,
+    path: &Path,
+    visit: &mut dyn FnMut(String, u64, &mut dyn Read) -> Result<()>,
+) -> Result<()> {
+    let entries = archive.entries().with_context(|| format!("Invalid tar archive: {}", path.display()))?;
+    for entry in entries {
+        let mut entry = entry.with_context(|| format!("Invalid tar archive: {}", path.display()))?;
+        if !entry.header().entry_type().is_file() {
+            continue;
+        }
+        let Some(name) = entry.path().ok().as_deref().and_then(entry_name) else { continue };
+        let size = entry.header().size().unwrap_or(0);
+        visit(name, size, &mut entry)?;
+    }
+    Ok(())
+}
+
+/// Helper: `/`-joined relative entry name; `None` for absolute or `..` paths
+fn entry_name(path: &Path) -> Option<String> {
+    let mut parts = Vec::new();
+    for component in path.components() {
+        match component {
+            Component::Normal(part) => parts.push(part.to_str()?),
+            Component::CurDir => {}
+            _ => return None,
+        }
+    }
+    (!parts.is_empty()).then(|| parts.join("/"))
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_detect() {
+        assert_eq!(ArchiveKind::detect(Path::new("deps/lib-1.0.tar.gz")), Some(ArchiveKind::TarGz));
+        assert_eq!(ArchiveKind::detect(Path::new("lib.TGZ")), Some(ArchiveKind::TarGz));
+        assert_eq!(ArchiveKind::detect(Path::new("lib.tar")), Some(ArchiveKind::Tar));
+        assert_eq!(ArchiveKind::detect(Path::new("src.zip")), Some(ArchiveKind::Zip));
+        assert_eq!(ArchiveKind::detect(Path::new("main.go")), None);
+    }
+
+    #[test]
+    fn test_entry_names() {
+        assert_eq!(entry_name(Path::new("./pkg/src/lib.go")), Some("pkg/src/lib.go".to_string()));
+        assert_eq!(entry_name(Path::new("../etc/passwd")), None);
+        assert_eq!(entry_name(Path::new("/abs/file.py")), None);
+        assert_eq!(entry_path(Path::new("deps.tar.gz"), "pkg/lib.go"), PathBuf::from("deps.tar.gz!pkg/lib.go"));
+    }
+}
@@ -3,11 +3,12 @@
 //! This module contains the core AST types and functionality, ported from the C++ implementation
 //! to provide language-agnostic AST building and manipulation capabilities.
 
+use schemars::JsonSchema;
 use serde::{Deserialize, Serialize};
 use std::collections::HashMap;
 
 /// AST node types corresponding to C++ ASTNodeType enum
-#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, Hash)]
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, JsonSchema, Hash)]
 pub enum ASTNodeType {
     // Basic structure
     #[serde(rename = "file_root")]
@@ -167,7 +168,7 @@ enum QueryPath {
 }
 
 /// AST Node representing a single element in the syntax tree
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct ASTNode {
     // Basic node information
     #[serde(rename = "type")]
@@ -522,7 +523,7 @@ impl ASTNode {
 }
 
 /// AST Statistics structure matching C++ ASTStatistics
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct ASTStatistics {
     pub total_nodes: u32,
     pub max_depth: u32,
@@ -0,0 +1,184 @@
+//! 📌 Baseline of known gate findings
+//!
+//! `analyze --write-baseline baseline.json` snapshots the current gate
+//! violations instead of failing; `analyze --baseline baseline.json` then fails
+//! only on violations not in the snapshot, so a gate can be adopted on a code
+//! base that does not pass it yet.
+//!
+//! A finding matches a baseline entry with the same file, `stable_id` and
+//! metric, wherever the function has moved to; a second same-named overload
+//! has its own id and is new. Entries written without a stable id match the
+//! same symbol name at most `LINE_TOLERANCE` lines away instead. Each entry
+//! suppresses one finding; the value may have changed.
+
+use anyhow::{Context, Result};
+use serde::{Deserialize, Serialize};
+use std::path::{Path, PathBuf};
+
+use crate::core::gate::GateViolation;
+
+/// Format version written to and expected in baseline files
+pub const BASELINE_VERSION: u32 = 1;
+
+/// How far, in lines, a finding may move and still match its baseline entry
+pub const LINE_TOLERANCE: u32 = 20;
+
+/// Snapshot of gate violations
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct Baseline {
+    pub version: u32,
+    pub findings: Vec<GateViolation>,
+}
+
+/// What the gate does with its violations
+#[derive(Debug, Clone)]
+pub enum BaselineMode {
+    /// Fail on every violation
+    Off,
+    /// Fail only on violations not in the baseline
+    Suppress(Baseline),
+    /// Write the violations to a baseline file instead of failing
+    Write(PathBuf),
+}
+
+impl Baseline {
+    /// Baseline of `findings`, ordered by file, line and metric for stable diffs
+    pub fn new(mut findings: Vec<GateViolation>) -> Self {
+        findings.sort_by(|a, b| (&a.file, a.line, &a.name, &a.metric).cmp(&(&b.file, b.line, &b.name, &b.metric)));
+        Self { version: BASELINE_VERSION, findings }
+    }
+
+    /// Read a baseline file
+    pub fn load(path: &Path) -> Result<Self> {
+        let content = std::fs::read_to_string(path)
+            .with_context(|| format!("Failed to read baseline {}", path.display()))?;
+        let baseline: Baseline = serde_json::from_str(&content)
+            .with_context(|| format!("Invalid baseline {}", path.display()))?;
+        if baseline.version != BASELINE_VERSION {
+            anyhow::bail!("Unsupported baseline version {} in {} (expected {})", baseline.version, path.display(), BASELINE_VERSION);
+        }
+        Ok(baseline)
+    }
+
+    /// Write the baseline as pretty JSON
+    pub fn save(&self, path: &Path) -> Result<()> {
+        std::fs::write(path, serde_json::to_string_pretty(self)? + "\n")
+            .with_context(|| format!("Failed to write baseline {}", path.display()))
+    }
+
+    /// Split `violations` into those not in the baseline and the number suppressed
+    pub fn filter(&self, violations: Vec<GateViolation>) -> (Vec<GateViolation>, usize) {
+        let mut used = vec![false; self.findings.len()];
+        let mut remaining = Vec::new();
+        let mut suppressed = 0;
+
+        for violation in violations {
+            // Closest unused entry for the same symbol and metric
+            let matched = self.findings.iter()
+                .enumerate()
+                .filter(|(index, entry)| {
+                    let same_symbol = if entry.stable_id.is_empty() || violation.stable_id.is_empty() {
+                        entry.name == violation.name && entry.line.abs_diff(violation.line) <= LINE_TOLERANCE
+                    } else {
+                        entry.stable_id == violation.stable_id
+                    };
+                    !used[*index]
+                        && entry.file == violation.file
+                        && entry.metric == violation.metric
+                        && same_symbol
+                })
+                .min_by_key(|(_, entry)| entry.line.abs_diff(violation.line))
+                .map(|(index, _)| index);
+            match matched {
+                Some(index) => {
+                    used[index] = true;
+                    suppressed += 1;
+                }
+                None => remaining.push(violation),
+            }
+        }
+
+        (remaining, suppressed)
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use tempfile::TempDir;
+
+    fn violation(name: &str, line: u32, metric: &str, value: u32) -> GateViolation {
+        GateViolation {
+            file: "src/app.py".to_string(),
+            line,
+            name: name.to_string(),
+            stable_id: String::new(),
+            metric: metric.to_string(),
+            value,
+            limit: 10,
+        }
+    }
+
+    #[test]
+    fn test_filter() {
+        let baseline = Baseline::new(vec![
+            violation("Parser.parse", 12, "cyclomatic", 14),
+            violation("route", 80, "cognitive", 16),
+        ]);
+
+        let (remaining, suppressed) = baseline.filter(vec![
+            // Shifted by a few lines and a little worse: still known
+            violation("Parser.parse", 17, "cyclomatic", 15),
+            // Known symbol, metric not in the baseline
+            violation("Parser.parse", 17, "cognitive", 18),
+            // Same name far from the known one
+            violation("route", 200, "cognitive", 16),
+            violation("load", 40, "cyclomatic", 11),
+        ]);
+
+        assert_eq!(suppressed, 1);
+        let names: Vec<(&str, &str)> = remaining.iter().map(|v| (v.name.as_str(), v.metric.as_str())).collect();
+        assert_eq!(names, vec![("Parser.parse", "cognitive"), ("route", "cognitive"), ("load", "cyclomatic")]);
+
+        // One entry suppresses one finding
+        let (remaining, suppressed) = baseline.filter(vec![
+            violation("route", 78, "cognitive", 16),
+            violation("route", 82, "cognitive", 16),
+        ]);
+        assert_eq!((remaining.len(), suppressed), (1, 1));
+    }
+
+    #[test]
+    fn test_filter_by_stable_id() {
+        let with_id = |name: &str, line: u32, id: &str| GateViolation { stable_id: id.to_string(), ..violation(name, line, "cyclomatic", 14) };
+        let baseline = Baseline::new(vec![with_id("Parser.parse", 12, "a1"), violation("route", 80, "cyclomatic", 14)]);
+
+        let (remaining, suppressed) = baseline.filter(vec![
+            // Moved far down the file: still known
+            with_id("Parser.parse", 300, "a1"),
+            // An overload with the same name is new
+            with_id("Parser.parse", 14, "b2"),
+            // Without an id in the baseline entry, the line decides
+            with_id("route", 85, "c3"),
+        ]);
+
+        assert_eq!(suppressed, 2);
+        assert_eq!(remaining.len(), 1);
+        assert_eq!(remaining[0].stable_id, "b2");
+    }
+
+    #[test]
+    fn test_save_and_load() {
+        let dir = TempDir::new().unwrap();
+        let path = dir.path().join("baseline.json");
+        let baseline = Baseline::new(vec![violation("route", 80, "cognitive", 16), violation("Parser.parse", 12, "cyclomatic", 14)]);
+        baseline.save(&path).unwrap();
+
+        let loaded = Baseline::load(&path).unwrap();
+        assert_eq!(loaded, baseline);
+        assert_eq!(loaded.findings[0].name, "Parser.parse");
+
+        std::fs::write(&path, r#"{"version": 9, "findings": []}"#).unwrap();
+        assert!(Baseline::load(&path).is_err());
+    }
+}
@@ -0,0 +1,213 @@
+//! On-disk analysis cache keyed by content hash
+//!
+//! Maps the blake3 hash of a file's content (plus its language) to the
+//! serialized `AnalysisResult`, so unchanged files are not re-parsed.
+//! Entries live under a fingerprint directory derived from the analyzer
+//! version and the tree-sitter grammar versions; any upgrade changes the
+//! fingerprint and stale entries are discarded.
+
+use anyhow::{Context, Result};
+use std::fs;
+use std::path::{Path, PathBuf};
+
+use crate::core::types::{AnalysisResult, FileInfo, Language};
+use crate::metrics::plugin::registered_plugins;
+
+/// Default cache directory name (created next to the analyzed path)
+pub const CACHE_DIR: &str = ".nekocode-cache";
+
+/// Grammar versions recorded by build.rs from Cargo.lock
+const GRAMMAR_VERSIONS: &str = match option_env!("NEKOCODE_GRAMMAR_VERSIONS") {
+    Some(versions) => versions,
+    None => "unknown",
+};
+
+/// Cache directory used for `path` when none is given: next to a file, inside a directory
+pub fn default_cache_dir(path: &Path) -> PathBuf {
+    let base = if path.is_dir() { path } else { path.parent().unwrap_or_else(|| Path::new(".")) };
+    base.join(CACHE_DIR)
+}
+
+/// Content-hash keyed analysis cache
+#[derive(Debug, Clone)]
+pub struct AnalysisCache {
+    root: PathBuf,
+    fingerprint: String,
+}
+
+impl AnalysisCache {
+    /// Open (or lazily create) a cache rooted at `root`
+    pub fn new(root: PathBuf) -> Self {
+        Self {
+            root,
+            fingerprint: Self::fingerprint(),
+        }
+    }
+    
+    /// Cache fingerprint: analyzer version + grammar versions + metric plugins
+    pub fn fingerprint() -> String {
+        let mut hasher = blake3::Hasher::new();
+        hasher.update(env!("CARGO_PKG_VERSION").as_bytes());
+        hasher.update(b"\n");
+        hasher.update(GRAMMAR_VERSIONS.as_bytes());
+        // Custom metrics are part of cached results
+        let plugins = registered_plugins();
+        if !plugins.is_empty() {
+            hasher.update(b"\n");
+            hasher.update(plugins.join(",").as_bytes());
+        }
+        hasher.finalize().to_hex()[..16].to_string()
+    }
+    
+    /// Look up a cached result for this content; file info is refreshed for `file_info`
+    pub fn get(&self, language: Language, content: &str, file_info: &FileInfo) -> Option<AnalysisResult> {
+        let data = fs::read(self.entry_path(language, content)).ok()?;
+        let mut result: AnalysisResult = serde_json::from_slice(&data).ok()?;
+        
+        // Same content may live under several paths
+        result.file_info.name = file_info.name.clone();
+        result.file_info.path = file_info.path.clone();
+        result.file_info.size_bytes = file_info.size_bytes;
+        
+        Some(result)
+    }
+    
+    /// Store a result for this content
+    pub fn put(&self, language: Language, content: &str, result: &AnalysisResult) -> Result<()> {
+        let entry_path = self.entry_path(language, content);
+        let entry_dir = entry_path.parent().unwrap_or(&self.root);
+        fs::create_dir_all(entry_dir)
+            .with_context(|| format!("Failed to create cache directory: {}", entry_dir.display()))?;
+        
+        // Write to a temp file and rename so parallel readers never see partial entries
+        let temp_path = entry_path.with_extension(format!("tmp{}", std::process::id()));
+        fs::write(&temp_path, serde_json::to_vec(result)?)
+            .with_context(|| format!("Failed to write cache entry: {}", temp_path.display()))?;
+        fs::rename(&temp_path, &entry_path)
+            .with_context(|| format!("Failed to write cache entry: {}", entry_path.display()))?;
+        
+        Ok(())
+    }
+    
+    /// Remove entries written by other analyzer/grammar versions
+    pub fn prune_stale(&self) -> Result<usize> {
+        let mut removed = 0;
+        
+        if let Ok(entries) = fs::read_dir(&self.root) {
+            for entry in entries.flatten() {
+                let path = entry.path();
+                if path.is_dir() && entry.file_name().to_string_lossy() != self.fingerprint {
+                    fs::remove_dir_all(&path)
+                        .with_context(|| format!("Failed to remove stale cache: {}", path.display()))?;
+                    removed += 1;
+                }
+            }
+        }
+        
+        Ok(removed)
+    }
+    
+    /// Delete the whole cache directory, returning the number of entries removed
+    pub fn clear(&self) -> Result<usize> {
+        if !self.root.exists() {
+            return Ok(0);
+        }
+        
+        let count = Self::count_entries(&self.root);
+        
+        fs::remove_dir_all(&self.root)
+            .with_context(|| format!("Failed to remove cache directory: {}", self.root.display()))?;
+        
+        Ok(count)
+    }
+    
+    /// Helper: Count cache entry files below `dir`
+    fn count_entries(dir: &Path) -> usize {
+        let entries = match fs::read_dir(dir) {
+            Ok(entries) => entries,
+            Err(_) => return 0,
+        };
+        
+        entries.flatten()
+            .map(|entry| {
+                let path = entry.path();
+                if path.is_dir() {
+                    Self::count_entries(&path)
+                } else {
+                    usize::from(path.extension().map_or(false, |ext| ext == "json"))
+                }
+            })
+            .sum()
+    }
+    
+    /// Helper: <root>/<fingerprint>/<2 hex>/<hash>.json
+    fn entry_path(&self, language: Language, content: &str) -> PathBuf {
+        let mut hasher = blake3::Hasher::new();
+        hasher.update(format!("{:?}\n", language).as_bytes());
+        hasher.update(content.as_bytes());
+        let key = hasher.finalize().to_hex().to_string();
+        
+        self.root
+            .join(&self.fingerprint)
+            .join(&key[..2])
+            .join(format!("{}.json", key))
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use tempfile::TempDir;
+    
+    fn sample_result(path: &str) -> AnalysisResult {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from(path)), Language::Go);
+        result.file_info.total_lines = 3;
+        result
+    }
+    
+    #[test]
+    fn test_cache_roundtrip() {
+        let temp_dir = TempDir::new().unwrap();
+        let cache = AnalysisCache::new(temp_dir.path().join(CACHE_DIR));
+        let content = "package main\n\nfunc main() {}\n";
+        
+        assert!(cache.get(Language::Go, content, &FileInfo::new(PathBuf::from("a.go"))).is_none());
+        cache.put(Language::Go, content, &sample_result("a.go")).unwrap();
+        
+        // Hit for identical content under another path
+        let hit = cache.get(Language::Go, content, &FileInfo::new(PathBuf::from("b.go"))).unwrap();
+        assert_eq!(hit.file_info.path, PathBuf::from("b.go"));
+        assert_eq!(hit.file_info.total_lines, 3);
+        
+        // Miss for different content or language
+        assert!(cache.get(Language::Go, "package other\n", &FileInfo::new(PathBuf::from("a.go"))).is_none());
+        assert!(cache.get(Language::Rust, content, &FileInfo::new(PathBuf::from("a.go"))).is_none());
+    }
+    
+    #[test]
+    fn test_default_cache_dir() {
+        let temp_dir = TempDir::new().unwrap();
+        let file = temp_dir.path().join("main.go");
+        fs::write(&file, "package main\n").unwrap();
+        
+        assert_eq!(default_cache_dir(temp_dir.path()), temp_dir.path().join(CACHE_DIR));
+        assert_eq!(default_cache_dir(&file), temp_dir.path().join(CACHE_DIR));
+    }
+    
+    #[test]
+    fn test_cache_prune_and_clear() {
+        let temp_dir = TempDir::new().unwrap();
+        let root = temp_dir.path().join(CACHE_DIR);
+        let cache = AnalysisCache::new(root.clone());
+        
+        cache.put(Language::Go, "package main\n", &sample_result("a.go")).unwrap();
+        fs::create_dir_all(root.join("0000000000000000")).unwrap();
+        
+        // Entries from another version are dropped, current ones survive
+        assert_eq!(cache.prune_stale().unwrap(), 1);
+        assert!(cache.get(Language::Go, "package main\n", &FileInfo::new(PathBuf::from("a.go"))).is_some());
+        
+        assert_eq!(cache.clear().unwrap(), 1);
+        assert!(!root.exists());
+    }
+}
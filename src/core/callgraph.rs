@@ -0,0 +1,822 @@
+//! Call graph construction for NekoCode Rust
+//!
+//! Builds a directed who-calls-whom graph from the function definitions and
+//! function calls collected by the language analyzers. Callers are attributed
+//! by line range (innermost enclosing function), callees are resolved by name
+//! using the receiver/class information the analyzers attach as metadata.
+//! Self-loops mark directly recursive functions; strongly connected components
+//! of two or more functions are mutual recursion. Fan-in / fan-out count the
+//! distinct callers and callees of each node, ignoring self-loops.
+
+use anyhow::Result;
+use serde::{Deserialize, Serialize};
+use std::collections::{BTreeMap, HashMap, HashSet, VecDeque};
+use std::path::PathBuf;
+
+use crate::core::types::{AnalysisResult, DirectoryAnalysis, FunctionCall, FunctionInfo};
+
+/// A function or method in the call graph
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CallGraphNode {
+    /// Unique node id (qualified name, suffixed with the file when ambiguous)
+    pub id: String,
+    pub name: String,
+    pub qualified_name: String,
+    pub file: PathBuf,
+    pub line: u32,
+    /// "function" or "method"
+    pub kind: String,
+    /// Distinct functions calling this one (recursion excluded)
+    #[serde(default)]
+    pub fan_in: u32,
+    /// Distinct functions this one calls (recursion excluded)
+    #[serde(default)]
+    pub fan_out: u32,
+}
+
+/// A caller -> callee edge
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CallGraphEdge {
+    pub from: String,
+    pub to: String,
+    /// Number of call sites from caller to callee
+    pub call_count: u32,
+    pub is_method_call: bool,
+}
+
+/// Directed call graph
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct CallGraph {
+    pub nodes: Vec<CallGraphNode>,
+    pub edges: Vec<CallGraphEdge>,
+}
+
+/// Functions that (transitively) call each other: a strongly connected component
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CallCycle {
+    /// Members in definition order
+    pub functions: Vec<CallGraphNode>,
+}
+
+/// Internal definition record used while resolving calls
+struct Definition {
+    name: String,
+    owner: Option<String>,
+    receiver_name: Option<String>,
+    file_index: usize,
+    start_line: u32,
+    end_line: u32,
+}
+
+/// Definitions grouped for lookup: by name for callees, by file for callers
+///
+/// Index lists are ascending, so the first candidate is still the first definition.
+struct DefinitionIndex<'a> {
+    definitions: &'a [Definition],
+    by_name: HashMap<&'a str, Vec<usize>>,
+    by_file: HashMap<usize, Vec<usize>>,
+}
+
+impl CallGraph {
+    /// Build the call graph for every file of a directory analysis
+    pub fn build(analysis: &DirectoryAnalysis) -> Self {
+        Self::from_files(&analysis.files)
+    }
+    
+    /// Build the call graph for a set of file results
+    pub fn from_files(files: &[AnalysisResult]) -> Self {
+        let definitions = Self::collect_definitions(files);
+        
+        // Qualified names that occur more than once get the file appended to their id
+        let mut qualified_counts: HashMap<String, usize> = HashMap::new();
+        for def in &definitions {
+            *qualified_counts.entry(def.qualified_name()).or_insert(0) += 1;
+        }
+        
+        let mut nodes: Vec<CallGraphNode> = definitions.iter().map(|def| {
+            let qualified_name = def.qualified_name();
+            let file = files[def.file_index].file_info.path.clone();
+            let id = if qualified_counts[&qualified_name] > 1 {
+                format!("{}@{}:{}", qualified_name, file.display(), def.start_line)
+            } else {
+                qualified_name.clone()
+            };
+            
+            CallGraphNode {
+                id,
+                name: def.name.clone(),
+                qualified_name,
+                file,
+                line: def.start_line,
+                kind: if def.owner.is_some() { "method" } else { "function" }.to_string(),
+                fan_in: 0,
+                fan_out: 0,
+            }
+        }).collect();
+        
+        let index = DefinitionIndex::new(&definitions);
+        let mut edges: BTreeMap<(usize, usize), CallGraphEdge> = BTreeMap::new();
+        
+        for (file_index, file) in files.iter().enumerate() {
+            for call in &file.function_calls {
+                let caller = match index.find_caller(file_index, call.line_number) {
+                    Some(caller) => caller,
+                    None => continue, // top-level call outside any function
+                };
+                
+                if let Some(callee) = index.resolve_callee(caller, call) {
+                    let edge = edges.entry((caller, callee)).or_insert_with(|| CallGraphEdge {
+                        from: nodes[caller].id.clone(),
+                        to: nodes[callee].id.clone(),
+                        call_count: 0,
+                        is_method_call: call.is_method_call,
+                    });
+                    edge.call_count += 1;
+                }
+            }
+        }
+        
+        // Each (caller, callee) pair is one edge, so counting edges counts distinct functions
+        for &(caller, callee) in edges.keys().filter(|(caller, callee)| caller != callee) {
+            nodes[caller].fan_out += 1;
+            nodes[callee].fan_in += 1;
+        }
+        
+        Self {
+            nodes,
+            edges: edges.into_values().collect(),
+        }
+    }
+    
+    /// Restrict the graph to symbols reachable from `root` within `depth` calls
+    pub fn subgraph(&self, root: &str, depth: Option<usize>) -> Result<Self> {
+        self.expand(root, depth, |edge| (edge.from.as_str(), edge.to.as_str()))
+    }
+    
+    /// Restrict the graph to symbols reaching `root` within `depth` calls (its callers)
+    pub fn callers(&self, root: &str, depth: Option<usize>) -> Result<Self> {
+        self.expand(root, depth, |edge| (edge.to.as_str(), edge.from.as_str()))
+    }
+    
+    /// Helper: Breadth-first expansion from `root` along edges oriented by `direction` (near, far)
+    fn expand<'a>(&'a self, root: &str, depth: Option<usize>, direction: impl Fn(&'a CallGraphEdge) -> (&'a str, &'a str)) -> Result<Self> {
+        let roots: Vec<&str> = self.nodes.iter()
+            .filter(|n| n.id == root || n.qualified_name == root)
+            .map(|n| n.id.as_str())
+            .collect();
+        
+        // Fall back to the bare name (e.g. "ProcessData")
+        let roots = if roots.is_empty() {
+            self.nodes.iter()
+                .filter(|n| n.name == root)
+                .map(|n| n.id.as_str())
+                .collect()
+        } else {
+            roots
+        };
+        
+        if roots.is_empty() {
+            anyhow::bail!("Root symbol not found in call graph: {}", root);
+        }
+        
+        let mut adjacent: HashMap<&str, Vec<&CallGraphEdge>> = HashMap::new();
+        for edge in &self.edges {
+            adjacent.entry(direction(edge).0).or_default().push(edge);
+        }
+        
+        let mut reached: HashSet<&str> = roots.iter().copied().collect();
+        let mut queue: VecDeque<(&str, usize)> = roots.iter().map(|r| (*r, 0)).collect();
+        let mut kept_edges = Vec::new();
+        
+        while let Some((id, level)) = queue.pop_front() {
+            if depth.map_or(false, |max| level >= max) {
+                continue;
+            }
+            
+            for edge in adjacent.get(id).map(|v| v.as_slice()).unwrap_or(&[]) {
+                kept_edges.push((*edge).clone());
+                let far = direction(edge).1;
+                if reached.insert(far) {
+                    queue.push_back((far, level + 1));
+                }
+            }
+        }
+        
+        Ok(Self {
+            nodes: self.nodes.iter().filter(|n| reached.contains(n.id.as_str())).cloned().collect(),
+            edges: kept_edges,
+        })
+    }
+    
+    /// Mutually recursive groups (Tarjan's SCC), ordered by their first member
+    ///
+    /// Single functions calling themselves are not cycles here; they are
+    /// flagged with `FunctionInfo::recursive` instead.
+    pub fn cycles(&self) -> Vec<CallCycle> {
+        let index_of: HashMap<&str, usize> = self.nodes.iter()
+            .enumerate()
+            .map(|(index, node)| (node.id.as_str(), index))
+            .collect();
+        
+        let mut successors: Vec<Vec<usize>> = vec![Vec::new(); self.nodes.len()];
+        for edge in &self.edges {
+            if let (Some(&from), Some(&to)) = (index_of.get(edge.from.as_str()), index_of.get(edge.to.as_str())) {
+                successors[from].push(to);
+            }
+        }
+        
+        let mut components: Vec<Vec<usize>> = strongly_connected_components(&successors)
+            .into_iter()
+            .filter(|component| component.len() > 1)
+            .map(|mut component| {
+                component.sort_unstable();
+                component
+            })
+            .collect();
+        components.sort();
+        
+        components.into_iter()
+            .map(|component| CallCycle {
+                functions: component.into_iter().map(|index| self.nodes[index].clone()).collect(),
+            })
+            .collect()
+    }
+    
+    /// Collect function/method definitions from all files
+    fn collect_definitions(files: &[AnalysisResult]) -> Vec<Definition> {
+        let mut definitions = Vec::new();
+        
+        for (file_index, file) in files.iter().enumerate() {
+            for func in &file.functions {
+                definitions.push(Definition::from_function(func, None, file_index));
+            }
+            
+            // Class methods the analyzer did not also report as functions (interfaces only declare)
+            for class in file.classes.iter().filter(|c| c.metadata.get("type").map(|t| t.as_str()) != Some("interface")) {
+                for method in &class.methods {
+                    let already_known = file.functions.iter()
+                        .any(|f| f.name == method.name && f.start_line == method.start_line);
+                    if !already_known {
+                        definitions.push(Definition::from_function(method, Some(&class.name), file_index));
+                    }
+                }
+            }
+        }
+        
+        definitions
+    }
+    
+    /// Look up a node by id
+    pub fn node(&self, id: &str) -> Option<&CallGraphNode> {
+        self.nodes.iter().find(|n| n.id == id)
+    }
+    
+    /// Render as a GraphViz DOT digraph, one cluster per source file
+    pub fn to_dot(&self) -> String {
+        let mut dot = String::new();
+        dot.push_str("digraph callgraph {\n");
+        dot.push_str("    rankdir=LR;\n");
+        dot.push_str("    node [shape=box, fontname=\"Helvetica\"];\n");
+        
+        let mut files: BTreeMap<&PathBuf, Vec<&CallGraphNode>> = BTreeMap::new();
+        for node in &self.nodes {
+            files.entry(&node.file).or_default().push(node);
+        }
+        
+        for (index, (file, nodes)) in files.iter().enumerate() {
+            let file = file.display().to_string();
+            dot.push_str(&format!("    subgraph {} {{\n", dot_quote(&format!("cluster_{}_{}", index, file))));
+            dot.push_str(&format!("        label={};\n", dot_quote(&file)));
+            for node in nodes {
+                let shape = if node.kind == "method" { "box" } else { "ellipse" };
+                dot.push_str(&format!("        {} [label={}, shape={}];\n",
+                                      dot_quote(&node.id), dot_quote(&node.qualified_name), shape));
+            }
+            dot.push_str("    }\n");
+        }
+        
+        // Recursive calls keep from == to and render as self-loops
+        for edge in &self.edges {
+            let style = if edge.is_method_call { "dashed" } else { "solid" };
+            let label = if edge.call_count > 1 { format!(", label=\"{}\"", edge.call_count) } else { String::new() };
+            dot.push_str(&format!("    {} -> {} [style={}{}];\n",
+                                  dot_quote(&edge.from), dot_quote(&edge.to), style, label));
+        }
+        
+        dot.push_str("}\n");
+        dot
+    }
+}
+
+/// Set `recursive` on functions and methods that call themselves directly
+pub fn mark_recursive(files: &mut [AnalysisResult]) {
+    let graph = CallGraph::from_files(files);
+    let recursive: HashSet<(PathBuf, String, u32)> = graph.edges.iter()
+        .filter(|edge| edge.from == edge.to)
+        .filter_map(|edge| graph.node(&edge.from))
+        .map(|node| (node.file.clone(), node.name.clone(), node.line))
+        .collect();
+    
+    if recursive.is_empty() {
+        return;
+    }
+    
+    for file in files.iter_mut() {
+        let path = file.file_info.path.clone();
+        // Methods can be reported both as functions and inside their class
+        let functions = file.functions.iter_mut()
+            .chain(file.classes.iter_mut().flat_map(|class| class.methods.iter_mut()));
+        for func in functions {
+            if recursive.contains(&(path.clone(), func.name.clone(), func.start_line)) {
+                func.recursive = true;
+            }
+        }
+    }
+}
+
+/// Set `fan_in` / `fan_out` on every function and method from the call graph of the whole set
+///
+/// Run after cross-file passes so calls into other files resolve.
+pub fn annotate_coupling(files: &mut [AnalysisResult]) {
+    let graph = CallGraph::from_files(files);
+    let coupling: HashMap<(PathBuf, String, u32), (u32, u32)> = graph.nodes.iter()
+        .map(|node| ((node.file.clone(), node.name.clone(), node.line), (node.fan_in, node.fan_out)))
+        .collect();
+    
+    for file in files.iter_mut() {
+        let path = file.file_info.path.clone();
+        let functions = file.functions.iter_mut()
+            .chain(file.classes.iter_mut().flat_map(|class| class.methods.iter_mut()));
+        for func in functions {
+            if let Some(&(fan_in, fan_out)) = coupling.get(&(path.clone(), func.name.clone(), func.start_line)) {
+                func.fan_in = fan_in;
+                func.fan_out = fan_out;
+            }
+        }
+    }
+}
+
+/// Render the `top` highest fan-in (shared utilities) and fan-out (god functions) nodes
+pub fn coupling_to_text(graph: &CallGraph, top: usize) -> String {
+    let mut text = format!("🔗 Coupling across {} functions\n", graph.nodes.len());
+    
+    let sections: [(&str, fn(&CallGraphNode) -> u32); 2] = [
+        ("Highest fan-in (called by many)", |node| node.fan_in),
+        ("Highest fan-out (calls many)", |node| node.fan_out),
+    ];
+    for (title, metric) in sections {
+        let mut ranked: Vec<&CallGraphNode> = graph.nodes.iter().filter(|node| metric(node) > 0).collect();
+        // Ties keep definition order
+        ranked.sort_by_key(|node| std::cmp::Reverse(metric(node)));
+        
+        text.push_str(&format!("\n{}:\n", title));
+        if ranked.is_empty() {
+            text.push_str("  (none)\n");
+        }
+        for node in ranked.into_iter().take(top) {
+            text.push_str(&format!("  {:>4}  {}  {}:{}\n", metric(node), node.qualified_name, node.file.display(), node.line));
+        }
+    }
+    text
+}
+
+/// Render cycles for the terminal, one member per line with its location
+pub fn cycles_to_text(cycles: &[CallCycle]) -> String {
+    if cycles.is_empty() {
+        return "✅ No call cycles found\n".to_string();
+    }
+    
+    let mut text = format!("🔁 {} call cycle(s)\n", cycles.len());
+    for (index, cycle) in cycles.iter().enumerate() {
+        text.push_str(&format!("\nCycle {} ({} functions):\n", index + 1, cycle.functions.len()));
+        for node in &cycle.functions {
+            text.push_str(&format!("  {}  {}:{}\n", node.qualified_name, node.file.display(), node.line));
+        }
+    }
+    text
+}
+
+/// Tarjan's strongly connected components over adjacency lists
+///
+/// Iterative so that long call chains cannot overflow the stack.
+pub(crate) fn strongly_connected_components(successors: &[Vec<usize>]) -> Vec<Vec<usize>> {
+    const UNVISITED: usize = usize::MAX;
+    
+    let mut index = vec![UNVISITED; successors.len()];
+    let mut lowlink = vec![0; successors.len()];
+    let mut on_stack = vec![false; successors.len()];
+    let mut stack = Vec::new();
+    let mut components = Vec::new();
+    let mut next_index = 0;
+    
+    for root in 0..successors.len() {
+        if index[root] != UNVISITED {
+            continue;
+        }
+        
+        // (node, position of the next successor to visit)
+        let mut work = vec![(root, 0)];
+        while let Some((node, position)) = work.pop() {
+            if position == 0 {
+                index[node] = next_index;
+                lowlink[node] = next_index;
+                next_index += 1;
+                stack.push(node);
+                on_stack[node] = true;
+            }
+            
+            if let Some(&next) = successors[node].get(position) {
+                work.push((node, position + 1));
+                if index[next] == UNVISITED {
+                    work.push((next, 0));
+                } else if on_stack[next] {
+                    lowlink[node] = lowlink[node].min(index[next]);
+                }
+                continue;
+            }
+            
+            // All successors visited: `node` roots a component or reports to its parent
+            if lowlink[node] == index[node] {
+                let mut component = Vec::new();
+                while let Some(member) = stack.pop() {
+                    on_stack[member] = false;
+                    component.push(member);
+                    if member == node {
+                        break;
+                    }
+                }
+                components.push(component);
+            }
+            if let Some(&(parent, _)) = work.last() {
+                lowlink[parent] = lowlink[parent].min(lowlink[node]);
+            }
+        }
+    }
+    
+    components
+}
+
+/// Quote a DOT identifier (ids contain dots, brackets, paths); also used by the hierarchy graph
+pub(crate) fn dot_quote(id: &str) -> String {
+    format!("\"{}\"", id.replace('\\', "\\\\").replace('"', "\\\""))
+}
+
+impl<'a> DefinitionIndex<'a> {
+    fn new(definitions: &'a [Definition]) -> Self {
+        let mut by_name: HashMap<&str, Vec<usize>> = HashMap::new();
+        let mut by_file: HashMap<usize, Vec<usize>> = HashMap::new();
+        for (index, def) in definitions.iter().enumerate() {
+            by_name.entry(def.name.as_str()).or_default().push(index);
+            by_file.entry(def.file_index).or_default().push(index);
+        }
+        Self { definitions, by_name, by_file }
+    }
+    
+    /// Innermost definition in `file_index` whose line range contains `line`
+    fn find_caller(&self, file_index: usize, line: u32) -> Option<usize> {
+        self.by_file.get(&file_index)?
+            .iter()
+            .copied()
+            .filter(|&index| self.definitions[index].start_line <= line && line <= self.definitions[index].end_line)
+            .min_by_key(|&index| self.definitions[index].end_line - self.definitions[index].start_line)
+    }
+    
+    /// Resolve a call site to a definition (best-effort for dynamic dispatch)
+    fn resolve_callee(&self, caller: usize, call: &FunctionCall) -> Option<usize> {
+        let definitions = self.definitions;
+        let caller_def = &definitions[caller];
+        let named = self.by_name.get(call.function_name.as_str()).map(|v| v.as_slice()).unwrap_or(&[]);
+        
+        let find = |predicate: &dyn Fn(&Definition) -> bool| -> Vec<usize> {
+            named.iter()
+                .copied()
+                .filter(|&index| predicate(&definitions[index]))
+                .collect()
+        };
+        
+        if call.is_method_call {
+            let object = call.object_name.as_deref().unwrap_or("");
+            
+            // Receiver type inferred by the analyzer: processor := NewDataProcessor()
+            if let Some(receiver_type) = call.receiver_type.as_deref() {
+                if let Some(index) = find(&|d| d.owner.as_deref() == Some(receiver_type)).first() {
+                    return Some(*index);
+                }
+            }
+            
+            // Call on the receiver variable: dp.processItem() inside (dp *DataProcessor)
+            if caller_def.receiver_name.as_deref() == Some(object) {
+                if let Some(index) = find(&|d| d.owner == caller_def.owner).first() {
+                    return Some(*index);
+                }
+            }
+            
+            // Call on a type / class name: DataProcessor.create, Neko::DataProcessor.create
+            let type_name = object.rsplit("::").next().unwrap_or(object);
+            if let Some(index) = find(&|d| d.owner.as_deref() == Some(type_name)).first() {
+                return Some(*index);
+            }
+            
+            // Unknown receiver type: accept a unique method name
+            let candidates = find(&|d| d.owner.is_some());
+            return if candidates.len() == 1 { Some(candidates[0]) } else { None };
+        }
+        
+        // Implicit self call inside a class (Ruby, Python-style helpers)
+        if caller_def.owner.is_some() {
+            if let Some(index) = find(&|d| d.owner == caller_def.owner && d.file_index == caller_def.file_index).first() {
+                return Some(*index);
+            }
+        }
+        
+        // Free function: prefer the caller's file, otherwise a unique match
+        let candidates = find(&|d| d.owner.is_none());
+        candidates.iter()
+            .copied()
+            .find(|index| definitions[*index].file_index == caller_def.file_index)
+            .or_else(|| if candidates.len() == 1 { Some(candidates[0]) } else { None })
+    }
+}
+
+impl Definition {
+    fn from_function(func: &FunctionInfo, class_name: Option<&str>, file_index: usize) -> Self {
+        // Go methods carry receiver_type, Ruby methods carry class_name
+        let owner = func.metadata.get("receiver_type")
+            .or_else(|| func.metadata.get("class_name"))
+            .cloned()
+            .or_else(|| class_name.map(|c| c.to_string()));
+        
+        Self {
+            name: func.name.clone(),
+            owner,
+            receiver_name: func.metadata.get("receiver_name").cloned(),
+            file_index,
+            start_line: func.start_line,
+            end_line: func.end_line.max(func.start_line),
+        }
+    }
+    
+    fn qualified_name(&self) -> String {
+        match &self.owner {
+            Some(owner) => format!("{}.{}", owner, self.name),
+            None => self.name.clone(),
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, Language};
+    
+    fn function(name: &str, start: u32, end: u32, receiver: Option<(&str, &str)>) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = start;
+        func.end_line = end;
+        if let Some((var, ty)) = receiver {
+            func.metadata.insert("receiver_name".to_string(), var.to_string());
+            func.metadata.insert("receiver_type".to_string(), ty.to_string());
+        }
+        func
+    }
+    
+    fn call(name: &str, object: Option<&str>, line: u32) -> FunctionCall {
+        let mut call = FunctionCall::new(name.to_string(), line);
+        if let Some(object) = object {
+            call.object_name = Some(object.to_string());
+            call.is_method_call = true;
+        }
+        call
+    }
+    
+    /// Mirrors test_samples/sample.go
+    fn sample_analysis() -> DirectoryAnalysis {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("sample.go")), Language::Go);
+        result.functions = vec![
+            function("NewDataProcessor", 23, 25, None),
+            function("ProcessData", 28, 37, Some(("dp", "DataProcessor"))),
+            function("processItem", 39, 42, Some(("dp", "DataProcessor"))),
+            function("main", 44, 48, None),
+        ];
+        result.function_calls = vec![
+            call("Lock", Some("dp.mu"), 29),
+            call("processItem", Some("dp"), 34),
+            call("TrimSpace", Some("strings"), 41),
+            call("NewDataProcessor", None, 45),
+            call("ProcessData", Some("processor"), 46),
+            call("Println", Some("fmt"), 47),
+        ];
+        
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("."));
+        analysis.files.push(result);
+        analysis
+    }
+    
+    fn edge_pairs(graph: &CallGraph) -> Vec<(String, String)> {
+        graph.edges.iter().map(|e| (e.from.clone(), e.to.clone())).collect()
+    }
+    
+    #[test]
+    fn test_build_resolves_direct_and_method_calls() {
+        let graph = CallGraph::build(&sample_analysis());
+        
+        assert_eq!(graph.nodes.len(), 4);
+        assert!(graph.node("DataProcessor.ProcessData").is_some());
+        
+        let edges = edge_pairs(&graph);
+        assert!(edges.contains(&("main".to_string(), "NewDataProcessor".to_string())));
+        assert!(edges.contains(&("main".to_string(), "DataProcessor.ProcessData".to_string())));
+        assert!(edges.contains(&("DataProcessor.ProcessData".to_string(), "DataProcessor.processItem".to_string())));
+        // Library calls (fmt.Println, dp.mu.Lock) are not part of the graph
+        assert_eq!(edges.len(), 3);
+    }
+    
+    #[test]
+    fn test_build_uses_inferred_receiver_type() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("workers.go")), Language::Go);
+        result.functions = vec![
+            function("Process", 1, 5, Some(("q", "Queue"))),
+            function("Process", 7, 11, Some(("p", "Pool"))),
+            function("main", 13, 17, None),
+        ];
+        let mut process = call("Process", Some("pool"), 15);
+        process.receiver_type = Some("Pool".to_string());
+        result.function_calls = vec![process];
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("."));
+        analysis.files.push(result);
+        
+        let graph = CallGraph::build(&analysis);
+        
+        assert_eq!(edge_pairs(&graph), vec![("main".to_string(), "Pool.Process".to_string())]);
+    }
+    
+    #[test]
+    fn test_subgraph_depth() {
+        let graph = CallGraph::build(&sample_analysis());
+        
+        let direct = graph.subgraph("main", Some(1)).unwrap();
+        assert_eq!(direct.edges.len(), 2);
+        assert!(direct.node("DataProcessor.processItem").is_none());
+        
+        let full = graph.subgraph("main", None).unwrap();
+        assert_eq!(full.edges.len(), 3);
+        assert!(full.node("DataProcessor.processItem").is_some());
+        
+        assert!(graph.subgraph("missing", None).is_err());
+    }
+    
+    #[test]
+    fn test_callers() {
+        let graph = CallGraph::build(&sample_analysis());
+        
+        let direct = graph.callers("processItem", Some(1)).unwrap();
+        assert_eq!(edge_pairs(&direct), vec![("DataProcessor.ProcessData".to_string(), "DataProcessor.processItem".to_string())]);
+        assert!(direct.node("main").is_none());
+        
+        let transitive = graph.callers("processItem", None).unwrap();
+        assert_eq!(transitive.edges.len(), 2);
+        assert!(transitive.node("main").is_some());
+        assert!(transitive.node("NewDataProcessor").is_none());
+    }
+    
+    #[test]
+    fn test_to_dot() {
+        let mut analysis = sample_analysis();
+        let mut generic = AnalysisResult::new(FileInfo::new(PathBuf::from("set.go")), Language::Go);
+        generic.functions = vec![function("Add", 1, 5, Some(("s", "Set[T]")))];
+        generic.function_calls = vec![call("Add", Some("s"), 3)];
+        analysis.files.push(generic);
+        
+        let dot = CallGraph::build(&analysis).to_dot();
+        
+        assert!(dot.starts_with("digraph callgraph {"));
+        assert!(dot.contains("subgraph \"cluster_0_sample.go\" {"));
+        assert!(dot.contains("subgraph \"cluster_1_set.go\" {"));
+        assert!(dot.contains("\"main\" -> \"NewDataProcessor\" [style=solid];"));
+        assert!(dot.contains("\"DataProcessor.ProcessData\" -> \"DataProcessor.processItem\" [style=dashed];"));
+        // Recursive method call as a self-loop with a quoted generic id
+        assert!(dot.contains("\"Set[T].Add\" -> \"Set[T].Add\" [style=dashed];"));
+        assert_eq!(dot_quote("a\"b"), "\"a\\\"b\"");
+    }
+    
+    #[test]
+    fn test_mark_recursive() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("tree.go")), Language::Go);
+        result.functions = vec![
+            function("factorial", 1, 6, None),
+            function("Walk", 8, 14, Some(("n", "Node"))),
+            function("main", 16, 20, None),
+        ];
+        result.function_calls = vec![
+            call("factorial", None, 5),
+            call("Walk", Some("n"), 12),
+            call("factorial", None, 18),
+        ];
+        
+        let mut files = vec![result];
+        mark_recursive(&mut files);
+        
+        let recursive: Vec<&str> = files[0].functions.iter()
+            .filter(|f| f.recursive)
+            .map(|f| f.name.as_str())
+            .collect();
+        assert_eq!(recursive, vec!["factorial", "Walk"]);
+    }
+    
+    #[test]
+    fn test_cycles() {
+        let mut a = AnalysisResult::new(FileInfo::new(PathBuf::from("parity.go")), Language::Go);
+        a.functions = vec![
+            function("isEven", 1, 6, None),
+            function("isOdd", 8, 13, None),
+            function("main", 15, 18, None),
+        ];
+        a.function_calls = vec![call("isOdd", None, 5), call("isEven", None, 12), call("isEven", None, 16)];
+        
+        // parse -> expr -> term -> parse across files, plus a self-recursive helper
+        let mut b = AnalysisResult::new(FileInfo::new(PathBuf::from("parser.go")), Language::Go);
+        b.functions = vec![
+            function("parse", 1, 4, None),
+            function("expr", 6, 9, None),
+            function("term", 11, 15, None),
+            function("skip", 17, 20, None),
+        ];
+        b.function_calls = vec![
+            call("expr", None, 3),
+            call("term", None, 8),
+            call("parse", None, 13),
+            call("skip", None, 14),
+            call("skip", None, 19),
+        ];
+        
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("."));
+        analysis.files = vec![a, b];
+        
+        let cycles = CallGraph::build(&analysis).cycles();
+        let names: Vec<Vec<&str>> = cycles.iter()
+            .map(|c| c.functions.iter().map(|f| f.name.as_str()).collect())
+            .collect();
+        assert_eq!(names, vec![vec!["isEven", "isOdd"], vec!["parse", "expr", "term"]]);
+        assert_eq!(cycles[1].functions[0].file, PathBuf::from("parser.go"));
+        
+        let text = cycles_to_text(&cycles);
+        assert!(text.starts_with("🔁 2 call cycle(s)"));
+        assert!(text.contains("Cycle 2 (3 functions):\n  parse  parser.go:1\n"));
+        
+        assert!(CallGraph::build(&sample_analysis()).cycles().is_empty());
+        assert_eq!(cycles_to_text(&[]), "✅ No call cycles found\n");
+    }
+    
+    #[test]
+    fn test_strongly_connected_components() {
+        // 0 -> 1 -> 2 -> 0, 2 -> 3, 3 -> 3, 4 alone
+        let successors = vec![vec![1], vec![2], vec![0, 3], vec![3], vec![]];
+        let mut components: Vec<Vec<usize>> = strongly_connected_components(&successors)
+            .into_iter()
+            .map(|mut c| {
+                c.sort_unstable();
+                c
+            })
+            .collect();
+        components.sort();
+        
+        assert_eq!(components, vec![vec![0, 1, 2], vec![3], vec![4]]);
+    }
+
+    /// Distinct callers / callees, counted over every file; self-calls do not count
+    #[test]
+    fn test_coupling() {
+        let mut util = AnalysisResult::new(FileInfo::new(PathBuf::from("util.go")), Language::Go);
+        util.functions = vec![function("clamp", 1, 5, None)];
+        util.function_calls = vec![call("clamp", None, 3)];
+
+        let mut app = AnalysisResult::new(FileInfo::new(PathBuf::from("app.go")), Language::Go);
+        app.functions = vec![
+            function("load", 1, 6, None),
+            function("save", 8, 12, None),
+            function("main", 14, 20, None),
+        ];
+        app.function_calls = vec![
+            call("clamp", None, 3),
+            call("clamp", None, 4),
+            call("clamp", None, 10),
+            call("load", None, 15),
+            call("save", None, 16),
+            call("clamp", None, 17),
+        ];
+        let mut files = vec![util, app];
+
+        annotate_coupling(&mut files);
+        let clamp = &files[0].functions[0];
+        assert_eq!((clamp.fan_in, clamp.fan_out), (3, 0));
+        let main = &files[1].functions[2];
+        assert_eq!((main.fan_in, main.fan_out), (0, 3));
+        // Two calls from load to clamp are one edge
+        assert_eq!(files[1].functions[0].fan_out, 1);
+
+        let text = coupling_to_text(&CallGraph::from_files(&files), 1);
+        assert!(text.starts_with("🔗 Coupling across 4 functions\n"));
+        assert!(text.contains("Highest fan-in (called by many):\n     3  clamp  util.go:1\n\n"));
+        assert!(text.ends_with("Highest fan-out (calls many):\n     3  main  app.go:14\n"));
+    }
+}
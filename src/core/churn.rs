@@ -0,0 +1,74 @@
+//! 🔥 Churn and churn risk of functions (`analyze --with-churn`)
+//!
+//! Churn is the number of commits that touched a function's lines within the
+//! window (`--churn-since 6.months`, default all history), counted with
+//! `git log -L` so the span is followed back as edits above it shift it.
+//! Risk is cyclomatic complexity times churn: complex code that keeps
+//! changing is where refactoring pays off first.
+//!
+//! Spans come from the analyzed working-tree file and are looked up at HEAD,
+//! so uncommitted edits above a function skew its range. Untracked files and
+//! virtual paths (`--rev`, archives) get no churn. One `git log` runs per
+//! function, which is why the mode is opt-in.
+
+use anyhow::Result;
+use std::collections::HashMap;
+use std::path::{Path, PathBuf};
+
+use crate::core::git::{line_range_commits, HistoryWindow};
+use crate::core::types::DirectoryAnalysis;
+
+/// Set `churn` and `risk` on every function and method of files tracked by git
+///
+/// Fails only when no function could be looked up at all (not a repository,
+/// git missing), with the first git error.
+pub fn annotate_churn(analysis: &mut DirectoryAnalysis, since: Option<&str>) -> Result<()> {
+    // Methods listed both at file level and under their class are looked up once
+    let mut counted: HashMap<(PathBuf, u32, u32), Option<u32>> = HashMap::new();
+    let mut windows: HashMap<PathBuf, Option<HistoryWindow>> = HashMap::new();
+    let mut first_error = None;
+    let mut annotated = 0;
+
+    for file in &mut analysis.files {
+        let path = file.file_info.path.clone();
+        if !path.is_file() {
+            continue;
+        }
+        let dir = path.parent().filter(|dir| !dir.as_os_str().is_empty()).unwrap_or_else(|| Path::new(".")).to_path_buf();
+        let window = windows.entry(dir.clone()).or_insert_with(|| match since {
+            None => Some(HistoryWindow::All),
+            Some(since) => match HistoryWindow::since(&dir, since) {
+                Ok(window) => Some(window),
+                Err(e) => {
+                    first_error.get_or_insert(e);
+                    None
+                }
+            },
+        });
+        let Some(window) = window.as_ref() else { continue };
+        let functions = file.functions.iter_mut()
+            .chain(file.classes.iter_mut().flat_map(|class| class.methods.iter_mut()));
+        for func in functions {
+            let key = (path.clone(), func.start_line, func.end_line);
+            let churn = *counted.entry(key).or_insert_with(|| {
+                match line_range_commits(&path, func.start_line, func.end_line, window) {
+                    Ok(churn) => Some(churn),
+                    Err(e) => {
+                        first_error.get_or_insert(e);
+                        None
+                    }
+                }
+            });
+            if let Some(churn) = churn {
+                func.churn = Some(churn);
+                func.risk = Some(func.complexity.cyclomatic_complexity * churn);
+                annotated += 1;
+            }
+        }
+    }
+
+    match first_error {
+        Some(e) if annotated == 0 => Err(e.context("--with-churn needs the analyzed files in a git repository")),
+        _ => Ok(()),
+    }
+}
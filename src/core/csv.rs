@@ -0,0 +1,127 @@
+//! 📑 CSV export (`analyze --format csv`): one row per function or method
+//!
+//! The columns are part of the output contract: new data gets new columns
+//! appended at the end, existing ones are never renamed or reordered, so
+//! saved spreadsheets and pivot tables keep working. Fields are quoted per
+//! RFC 4180 when they contain a comma, quote or line break.
+
+use std::collections::HashSet;
+use std::path::Path;
+
+use crate::core::types::AnalysisResult;
+
+/// Header row, in column order
+pub const CSV_COLUMNS: &[&str] = &[
+    "file", "language", "symbol", "kind", "start_line", "end_line",
+    "complexity", "cognitive_complexity", "loc", "params_count",
+];
+
+/// Header plus one row per function / method; `file` is relative to `root`
+pub fn functions_to_csv(files: &[AnalysisResult], root: &Path) -> String {
+    let mut out = String::new();
+    push_row(&mut out, CSV_COLUMNS.iter().map(|c| c.to_string()));
+
+    for file in files {
+        let path = file.file_info.path.strip_prefix(root)
+            .unwrap_or(&file.file_info.path)
+            .to_string_lossy()
+            .to_string();
+        let language = file.language.as_str();
+
+        // Some analyzers list methods both at file level and under their class
+        let mut seen = HashSet::new();
+        let mut rows = Vec::new();
+        let methods = file.classes.iter()
+            .flat_map(|class| class.methods.iter().map(move |m| (m, Some(class.name.as_str()))));
+        for (func, class_name) in methods.chain(file.functions.iter().map(|f| (f, None))) {
+            if !seen.insert((func.start_line, func.name.clone())) {
+                continue;
+            }
+            let owner = class_name.map(str::to_string)
+                .or_else(|| func.metadata.get("class_name").cloned())
+                .or_else(|| func.metadata.get("receiver_type").cloned());
+            let (symbol, kind) = match owner {
+                Some(owner) => (format!("{}.{}", owner, func.name), "method"),
+                None => (func.name.clone(), "function"),
+            };
+            rows.push((func.start_line, [
+                path.clone(),
+                language.to_string(),
+                symbol,
+                kind.to_string(),
+                func.start_line.to_string(),
+                func.end_line.to_string(),
+                func.complexity.cyclomatic_complexity.to_string(),
+                func.complexity.cognitive_complexity.to_string(),
+                func.loc.to_string(),
+                func.parameters.len().to_string(),
+            ]));
+        }
+
+        rows.sort_by(|a, b| (a.0, &a.1[2]).cmp(&(b.0, &b.1[2])));
+        for (_, row) in rows {
+            push_row(&mut out, row.into_iter());
+        }
+    }
+
+    out
+}
+
+/// Helper: Append one CRLF-terminated record
+fn push_row(out: &mut String, fields: impl Iterator<Item = String>) {
+    let fields: Vec<String> = fields.map(|field| escape(&field)).collect();
+    out.push_str(&fields.join(","));
+    out.push_str("\r\n");
+}
+
+/// Helper: Quote a field containing a separator, quote or line break; quotes are doubled
+fn escape(field: &str) -> String {
+    if field.contains([',', '"', '\n', '\r']) {
+        format!("\"{}\"", field.replace('"', "\"\""))
+    } else {
+        field.to_string()
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ClassInfo, FileInfo, FunctionInfo, Language};
+    use std::path::PathBuf;
+
+    fn function(name: &str, start: u32, end: u32, params: &[&str]) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = start;
+        func.end_line = end;
+        func.loc = end - start + 1;
+        func.parameters = params.iter().map(|p| p.to_string()).collect();
+        func.complexity.cyclomatic_complexity = 3;
+        func.complexity.cognitive_complexity = 2;
+        func
+    }
+
+    #[test]
+    fn test_rows_and_header() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/src/lib.rs")), Language::Rust);
+        result.functions = vec![function("parse", 20, 30, &["input"]), function("method", 5, 8, &[])];
+        let mut class = ClassInfo::new("Parser".to_string());
+        class.methods.push(function("method", 5, 8, &[]));
+        result.classes.push(class);
+
+        let csv = functions_to_csv(&[result], Path::new("/repo"));
+        let lines: Vec<&str> = csv.split("\r\n").collect();
+        assert_eq!(lines[0], "file,language,symbol,kind,start_line,end_line,complexity,cognitive_complexity,loc,params_count");
+        assert_eq!(lines[1], "src/lib.rs,rust,Parser.method,method,5,8,3,2,4,0");
+        assert_eq!(lines[2], "src/lib.rs,rust,parse,function,20,30,3,2,11,1");
+        assert_eq!(lines.len(), 4);
+        assert_eq!(lines[3], "");
+    }
+
+    #[test]
+    fn test_quoting() {
+        assert_eq!(escape("plain"), "plain");
+        assert_eq!(escape("operator,"), "\"operator,\"");
+        assert_eq!(escape("say \"hi\""), "\"say \"\"hi\"\"\"");
+        assert_eq!(escape("a\nb"), "\"a\nb\"");
+    }
+}
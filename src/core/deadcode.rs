@@ -0,0 +1,367 @@
+//! Dead code detection for NekoCode Rust
+//!
+//! Reports functions and methods (by default only exported ones) that are
+//! never called anywhere in the analyzed set. References are matched by name across all files (a call
+//! to any symbol with the same name counts), so the report errs on the side
+//! of missing dead code rather than flagging live code. Symbols of generated
+//! files are never reported, but their calls still keep other symbols alive.
+
+use serde::{Deserialize, Serialize};
+use std::collections::{HashMap, HashSet};
+use std::path::{Path, PathBuf};
+
+use crate::core::session::AnalysisSession;
+use crate::core::types::{AnalysisResult, DirectoryAnalysis, FunctionInfo, Language};
+use crate::core::visibility::{exported_names, is_public_function, Visibility};
+
+/// Go methods that commonly satisfy standard library interfaces
+const GO_STD_INTERFACE_METHODS: &[&str] = &[
+    "String", "GoString", "Format", "Error", "Unwrap", "Is", "As",
+    "Read", "Write", "Close", "Seek", "ReadFrom", "WriteTo",
+    "Len", "Less", "Swap", "ServeHTTP", "Scan", "Value",
+    "MarshalJSON", "UnmarshalJSON", "MarshalText", "UnmarshalText",
+    "MarshalYAML", "UnmarshalYAML",
+];
+
+/// A symbol without references
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct DeadSymbol {
+    pub name: String,
+    pub qualified_name: String,
+    pub file: PathBuf,
+    pub line: u32,
+    /// "function" or "method"
+    pub kind: String,
+    /// "high", or "low" when the symbol may be reached without a direct call
+    pub confidence: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub note: Option<String>,
+}
+
+/// Dead code report for an analyzed directory
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct DeadCodeReport {
+    /// Symbols with no references at all
+    pub unreferenced: Vec<DeadSymbol>,
+    /// Symbols referenced only from test files
+    pub test_only: Vec<DeadSymbol>,
+    /// Entry points and symbols suppressed by a `//nolint:unused` style directive
+    pub excluded: usize,
+}
+
+/// Internal candidate record
+struct Candidate<'a> {
+    func: &'a FunctionInfo,
+    owner: Option<String>,
+    file_index: usize,
+}
+
+impl DeadCodeReport {
+    /// Build the report for symbols of the given visibility, reading sources from disk for directive comments
+    pub fn build(analysis: &DirectoryAnalysis, visibility: Visibility) -> Self {
+        Self::build_with_sources(analysis, visibility, |path| std::fs::read_to_string(path).ok())
+    }
+    
+    /// Build the report with a custom source loader
+    fn build_with_sources<F>(analysis: &DirectoryAnalysis, visibility: Visibility, load_source: F) -> Self
+    where
+        F: Fn(&Path) -> Option<String>,
+    {
+        let files = &analysis.files;
+        let is_test: Vec<bool> = files.iter()
+            .map(|f| AnalysisSession::is_test_path(&f.file_info.path))
+            .collect();
+        
+        // Reference table: called name -> call sites
+        let mut references: HashMap<&str, Vec<(usize, u32)>> = HashMap::new();
+        for (file_index, file) in files.iter().enumerate() {
+            for call in &file.function_calls {
+                references.entry(call.function_name.as_str())
+                    .or_default()
+                    .push((file_index, call.line_number));
+            }
+        }
+        
+        // Go interface method names -> interface names
+        let mut interface_methods: HashMap<&str, Vec<&str>> = HashMap::new();
+        for file in files.iter().filter(|f| f.language == Language::Go) {
+            for class in file.classes.iter().filter(|c| Self::is_interface(c.metadata.get("type"))) {
+                for method in &class.methods {
+                    interface_methods.entry(method.name.as_str()).or_default().push(class.name.as_str());
+                }
+            }
+        }
+        
+        let exports: Vec<Option<Vec<String>>> = files.iter().map(exported_names).collect();
+        
+        let mut report = Self::default();
+        let mut sources: HashMap<usize, Option<Vec<String>>> = HashMap::new();
+        
+        for candidate in Self::collect_candidates(files, &is_test) {
+            let file = &files[candidate.file_index];
+            let func = candidate.func;
+            
+            if Self::is_entry_point(file.language, func, candidate.owner.is_some()) {
+                report.excluded += 1;
+                continue;
+            }
+            
+            if !visibility.admits(is_public_function(file.language, func, exports[candidate.file_index].as_deref())) {
+                continue;
+            }
+            
+            let lines = sources.entry(candidate.file_index)
+                .or_insert_with(|| load_source(&file.file_info.path).map(|s| s.lines().map(String::from).collect()));
+            if lines.as_deref().map_or(false, |lines| Self::has_unused_directive(lines, func.start_line)) {
+                report.excluded += 1;
+                continue;
+            }
+            
+            // Calls inside the symbol's own body (recursion) do not keep it alive
+            let end_line = func.end_line.max(func.start_line);
+            let sites: Vec<(usize, u32)> = references.get(func.name.as_str())
+                .map(|sites| sites.iter()
+                    .copied()
+                    .filter(|(index, line)| !(*index == candidate.file_index && func.start_line <= *line && *line <= end_line))
+                    .collect())
+                .unwrap_or_default();
+            
+            if sites.iter().any(|(index, _)| !is_test[*index]) {
+                continue;
+            }
+            
+            let note = Self::dispatch_note(file, &candidate, &interface_methods);
+            let symbol = DeadSymbol {
+                name: func.name.clone(),
+                qualified_name: match &candidate.owner {
+                    Some(owner) => format!("{}.{}", owner, func.name),
+                    None => func.name.clone(),
+                },
+                file: file.file_info.path.clone(),
+                line: func.start_line,
+                kind: if candidate.owner.is_some() { "method" } else { "function" }.to_string(),
+                confidence: if note.is_some() { "low" } else { "high" }.to_string(),
+                note,
+            };
+            
+            if sites.is_empty() {
+                report.unreferenced.push(symbol);
+            } else {
+                report.test_only.push(symbol);
+            }
+        }
+        
+        report
+    }
+    
+    /// Functions and methods defined in non-test, hand-written files
+    fn collect_candidates<'a>(files: &'a [AnalysisResult], is_test: &[bool]) -> Vec<Candidate<'a>> {
+        let mut candidates = Vec::new();
+        
+        for (file_index, file) in files.iter().enumerate() {
+            if is_test[file_index] || file.file_info.generated {
+                continue;
+            }
+            
+            for func in &file.functions {
+                candidates.push(Candidate { func, owner: Self::owner_of(func, None), file_index });
+            }
+            
+            // Class methods the analyzer did not also report as functions (interfaces only declare)
+            for class in file.classes.iter().filter(|c| !Self::is_interface(c.metadata.get("type"))) {
+                for method in &class.methods {
+                    let already_known = file.functions.iter()
+                        .any(|f| f.name == method.name && f.start_line == method.start_line);
+                    if !already_known {
+                        candidates.push(Candidate { func: method, owner: Self::owner_of(method, Some(&class.name)), file_index });
+                    }
+                }
+            }
+        }
+        
+        candidates
+    }
+    
+    /// Helper: Owning type (Go receiver type, class name)
+    fn owner_of(func: &FunctionInfo, class_name: Option<&str>) -> Option<String> {
+        func.metadata.get("receiver_type")
+            .or_else(|| func.metadata.get("class_name"))
+            .cloned()
+            .or_else(|| class_name.map(|c| c.to_string()))
+    }
+    
+    /// Helper: Class metadata "type" marks an interface / trait
+    fn is_interface(kind: Option<&String>) -> bool {
+        matches!(kind.map(|k| k.as_str()), Some("interface") | Some("trait"))
+    }
+    
+    /// Program entry points that are reached without a call
+    fn is_entry_point(language: Language, func: &FunctionInfo, is_method: bool) -> bool {
+        match language {
+            Language::Go => !is_method && (func.name == "main" || func.name == "init"),
+            // public static void main(String[] args)
+            Language::Java => func.name == "main" && func.metadata.get("is_static").map_or(false, |v| v == "true"),
+            _ => !is_method && func.name == "main",
+        }
+    }
+    
+    /// `//nolint`, `//nolint:unused` or `//lint:ignore U1000` on or above the declaration
+    fn has_unused_directive(lines: &[String], start_line: u32) -> bool {
+        let is_directive = |comment: &str| {
+            let comment = comment.trim_start_matches('/').trim();
+            if let Some(rest) = comment.strip_prefix("nolint") {
+                let linters = rest.split_whitespace().next().unwrap_or("");
+                return linters.is_empty()
+                    || linters.strip_prefix(':').map_or(false, |list| {
+                        list.split(',').any(|l| l == "unused" || l == "deadcode")
+                    });
+            }
+            comment.starts_with("lint:ignore U1000")
+        };
+        
+        let index = (start_line as usize).saturating_sub(1);
+        
+        // Trailing comment on the declaration line
+        if let Some(line) = lines.get(index) {
+            if let Some(pos) = line.find("//") {
+                if is_directive(&line[pos..]) {
+                    return true;
+                }
+            }
+        }
+        
+        // Contiguous comment block directly above
+        lines[..index.min(lines.len())].iter()
+            .rev()
+            .map(|line| line.trim())
+            .take_while(|line| line.starts_with("//"))
+            .any(is_directive)
+    }
+    
+    /// Confidence note for symbols that may be reached through dynamic dispatch
+    fn dispatch_note(file: &AnalysisResult, candidate: &Candidate, interface_methods: &HashMap<&str, Vec<&str>>) -> Option<String> {
+        candidate.owner.as_ref()?;
+        let name = candidate.func.name.as_str();
+        
+        match file.language {
+            Language::Go => {
+                if let Some(interfaces) = interface_methods.get(name) {
+                    let unique: HashSet<&str> = interfaces.iter().copied().collect();
+                    let mut interfaces: Vec<&str> = unique.into_iter().collect();
+                    interfaces.sort();
+                    return Some(format!("may be reachable via interface dispatch ({})", interfaces.join(", ")));
+                }
+                if GO_STD_INTERFACE_METHODS.contains(&name) {
+                    return Some("may satisfy a standard library interface".to_string());
+                }
+                None
+            }
+            Language::Rust => {
+                let line = candidate.func.start_line;
+                file.classes.iter()
+                    .filter(|c| c.start_line <= line && line <= c.end_line)
+                    .find_map(|c| c.metadata.get("implementing_trait"))
+                    .map(|t| format!("trait implementation ({}) may be called through the trait", t))
+            }
+            _ => Some("methods may be reached via dynamic dispatch".to_string()),
+        }
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ClassInfo, FileInfo, FunctionCall};
+    
+    fn function(name: &str, start: u32, end: u32, receiver_type: Option<&str>) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = start;
+        func.end_line = end;
+        if let Some(receiver_type) = receiver_type {
+            func.metadata.insert("receiver_type".to_string(), receiver_type.to_string());
+        }
+        func
+    }
+    
+    fn call(name: &str, line: u32) -> FunctionCall {
+        FunctionCall::new(name.to_string(), line)
+    }
+    
+    fn sample_analysis() -> DirectoryAnalysis {
+        let mut lib = AnalysisResult::new(FileInfo::new(PathBuf::from("lib.go")), Language::Go);
+        lib.functions = vec![
+            function("main", 1, 4, None),
+            function("Used", 6, 8, None),
+            function("Unused", 11, 13, None),
+            function("Suppressed", 16, 18, None),
+            function("OnlyTested", 20, 22, None),
+            function("Recursive", 24, 27, None),
+            function("helper", 29, 31, None),
+            function("Run", 33, 35, Some("Worker")),
+        ];
+        lib.function_calls = vec![call("Used", 2), call("Recursive", 26)];
+        
+        let mut runner = ClassInfo::new("Runner".to_string());
+        runner.metadata.insert("type".to_string(), "interface".to_string());
+        runner.methods.push(function("Run", 38, 38, None));
+        lib.classes.push(runner);
+        
+        let mut test = AnalysisResult::new(FileInfo::new(PathBuf::from("lib_test.go")), Language::Go);
+        test.functions = vec![function("TestOnly", 1, 3, None)];
+        test.function_calls = vec![call("OnlyTested", 2)];
+        
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("."));
+        analysis.files = vec![lib, test];
+        analysis
+    }
+    
+    fn sample_source() -> String {
+        let mut lines = vec![String::new(); 40];
+        lines[14] = "//nolint:unused // kept for plugins".to_string();
+        lines.join("\n")
+    }
+    
+    #[test]
+    fn test_deadcode_report() {
+        let report = DeadCodeReport::build_with_sources(&sample_analysis(), Visibility::Public, |_| Some(sample_source()));
+        
+        let unreferenced: Vec<&str> = report.unreferenced.iter().map(|s| s.qualified_name.as_str()).collect();
+        assert_eq!(unreferenced, vec!["Unused", "Recursive", "Worker.Run"]);
+        
+        let test_only: Vec<&str> = report.test_only.iter().map(|s| s.name.as_str()).collect();
+        assert_eq!(test_only, vec!["OnlyTested"]);
+        
+        // main + Suppressed
+        assert_eq!(report.excluded, 2);
+        
+        let run = &report.unreferenced[2];
+        assert_eq!(run.confidence, "low");
+        assert_eq!(run.note.as_deref(), Some("may be reachable via interface dispatch (Runner)"));
+        assert_eq!(report.unreferenced[0].confidence, "high");
+    }
+    
+    #[test]
+    fn test_unused_directive() {
+        let lines: Vec<String> = ["// Doc comment", "//nolint", "func A() {}", "func B() {} //nolint:errcheck,unused", "func C() {} //nolint:errcheck"]
+            .iter().map(|s| s.to_string()).collect();
+        
+        assert!(DeadCodeReport::has_unused_directive(&lines, 3));
+        assert!(DeadCodeReport::has_unused_directive(&lines, 4));
+        assert!(!DeadCodeReport::has_unused_directive(&lines, 5));
+    }
+    
+    /// Generated symbols are not reported; calls from generated code still count
+    #[test]
+    fn test_generated_files() {
+        let mut analysis = sample_analysis();
+        let mut generated = AnalysisResult::new(FileInfo::new(PathBuf::from("lib.pb.go")), Language::Go);
+        generated.file_info.generated = true;
+        generated.functions = vec![function("GetName", 1, 3, None)];
+        generated.function_calls = vec![call("Unused", 2)];
+        analysis.files.push(generated);
+        
+        let report = DeadCodeReport::build_with_sources(&analysis, Visibility::Public, |_| Some(sample_source()));
+        let unreferenced: Vec<&str> = report.unreferenced.iter().map(|s| s.qualified_name.as_str()).collect();
+        assert_eq!(unreferenced, vec!["Recursive", "Worker.Run"]);
+    }
+}
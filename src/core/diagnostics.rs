@@ -0,0 +1,158 @@
+//! 🩺 Parse diagnostics (`analyze --diagnostics`): how hard each file is on Tree-sitter
+//!
+//! The file is parsed once more with the grammar its analyzer uses, so the
+//! timing covers the Tree-sitter parse alone, not symbol extraction. Node
+//! count and depth show which files blow up the tree; `has_error` flags files
+//! the grammar could only partly understand, whose symbols may be incomplete.
+
+use std::path::Path;
+use std::time::Instant;
+
+use schemars::JsonSchema;
+use serde::{Deserialize, Serialize};
+use tree_sitter::Parser;
+
+use crate::core::types::{AnalysisResult, Language};
+
+/// Files listed in the slowest-parse report
+pub const SLOWEST_FILES: usize = 10;
+
+/// Parse cost and shape of one file's syntax tree
+#[derive(Debug, Clone, Default, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct ParseDiagnostics {
+    /// Wall time of the Tree-sitter parse in microseconds
+    pub parse_time_us: u64,
+    /// All nodes of the tree, named and anonymous
+    pub node_count: u64,
+    /// Depth of the deepest node; the root is depth 1
+    pub max_depth: u32,
+    /// Whether the tree contains ERROR or MISSING nodes
+    pub has_error: bool,
+}
+
+/// Parse `content` with the grammar for `language` and measure the tree
+///
+/// `None` for languages without an analyzer or if the parse is aborted.
+pub fn measure(content: &str, language: Language, path: &Path) -> Option<ParseDiagnostics> {
+    let grammar = grammar(language, path)?;
+    let mut parser = Parser::new();
+    parser.set_language(&grammar).ok()?;
+
+    let started = Instant::now();
+    let tree = parser.parse(content, None)?;
+    let parse_time_us = started.elapsed().as_micros() as u64;
+
+    // Iterative pre-order walk: deep trees must not overflow the stack
+    let mut node_count = 0;
+    let mut max_depth = 0;
+    let mut depth = 1;
+    let mut cursor = tree.walk();
+    'walk: loop {
+        node_count += 1;
+        max_depth = max_depth.max(depth);
+        if cursor.goto_first_child() {
+            depth += 1;
+            continue;
+        }
+        while !cursor.goto_next_sibling() {
+            if !cursor.goto_parent() {
+                break 'walk;
+            }
+            depth -= 1;
+        }
+    }
+
+    Some(ParseDiagnostics {
+        parse_time_us,
+        node_count,
+        max_depth,
+        has_error: tree.root_node().has_error(),
+    })
+}
+
+/// Text report of the files that took longest to parse
+pub fn slowest_to_text<'a>(files: impl IntoIterator<Item = &'a AnalysisResult>, root: &Path) -> String {
+    let mut measured: Vec<(&Path, &ParseDiagnostics)> = files.into_iter()
+        .filter_map(|file| file.diagnostics.as_ref().map(|d| (file.file_info.path.as_path(), d)))
+        .collect();
+    slowest_entries_to_text(&mut measured, root)
+}
+
+/// Same report from `(path, diagnostics)` pairs, for callers that do not keep results
+pub fn slowest_entries_to_text(measured: &mut [(&Path, &ParseDiagnostics)], root: &Path) -> String {
+    measured.sort_by(|a, b| b.1.parse_time_us.cmp(&a.1.parse_time_us).then_with(|| a.0.cmp(b.0)));
+
+    let mut out = format!("🩺 Slowest {} files to parse:\n", SLOWEST_FILES.min(measured.len()));
+    for (path, diag) in measured.iter().take(SLOWEST_FILES) {
+        let path = path.strip_prefix(root).unwrap_or(path);
+        out.push_str(&format!(
+            "  {:>10} µs  {:>8} nodes  depth {:>4}{}  {}\n",
+            diag.parse_time_us,
+            diag.node_count,
+            diag.max_depth,
+            if diag.has_error { "  ⚠️ errors" } else { "" },
+            path.display(),
+        ));
+    }
+    out
+}
+
+/// Grammar the analyzer of `language` parses with (`None` without an analyzer)
+pub fn grammar(language: Language, path: &Path) -> Option<tree_sitter::Language> {
+    let is_tsx = path.extension().is_some_and(|ext| ext == "tsx");
+    Some(match language {
+        Language::JavaScript => tree_sitter_javascript::LANGUAGE.into(),
+        Language::TypeScript if is_tsx => tree_sitter_typescript::LANGUAGE_TSX.into(),
+        Language::TypeScript => tree_sitter_typescript::LANGUAGE_TYPESCRIPT.into(),
+        Language::Python => tree_sitter_python::LANGUAGE.into(),
+        Language::Cpp => tree_sitter_cpp::LANGUAGE.into(),
+        Language::CSharp => tree_sitter_c_sharp::LANGUAGE.into(),
+        Language::Go => tree_sitter_go::LANGUAGE.into(),
+        Language::Rust => tree_sitter_rust::LANGUAGE.into(),
+        Language::Ruby => tree_sitter_ruby::LANGUAGE.into(),
+        Language::Java => tree_sitter_java::LANGUAGE.into(),
+        Language::Kotlin => tree_sitter_kotlin::LANGUAGE.into(),
+        Language::Swift => tree_sitter_swift::LANGUAGE.into(),
+        Language::Php => tree_sitter_php::LANGUAGE_PHP.into(),
+        Language::Lua => tree_sitter_lua::LANGUAGE.into(),
+        Language::Dart => tree_sitter_dart::LANGUAGE.into(),
+        Language::Scala => tree_sitter_scala::LANGUAGE.into(),
+        Language::Zig => tree_sitter_zig::LANGUAGE.into(),
+        _ => return None,
+    })
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::FileInfo;
+    use std::path::PathBuf;
+
+    #[test]
+    fn test_measure() {
+        let diag = measure("package main\n\nfunc main() {}\n", Language::Go, Path::new("main.go")).unwrap();
+        assert!(diag.node_count > 5);
+        assert!(diag.max_depth >= 3);
+        assert!(!diag.has_error);
+
+        let broken = measure("package main\n\nfunc main( {\n", Language::Go, Path::new("main.go")).unwrap();
+        assert!(broken.has_error);
+
+        assert!(measure("int main() {}", Language::C, Path::new("main.c")).is_none());
+    }
+
+    #[test]
+    fn test_slowest_report() {
+        let files: Vec<AnalysisResult> = (0..12u64).map(|i| {
+            let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from(format!("/repo/f{}.go", i))), Language::Go);
+            result.diagnostics = Some(ParseDiagnostics { parse_time_us: i * 100, node_count: 10, max_depth: 3, has_error: i == 11 });
+            result
+        }).collect();
+
+        let report = slowest_to_text(&files, Path::new("/repo"));
+        let lines: Vec<&str> = report.lines().collect();
+        assert_eq!(lines.len(), 1 + SLOWEST_FILES);
+        assert!(lines[1].contains("1100 µs") && lines[1].contains("errors") && lines[1].ends_with("f11.go"));
+        assert!(lines[10].ends_with("f2.go"));
+    }
+}
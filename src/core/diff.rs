@@ -0,0 +1,635 @@
+//! Symbol-level diff between two saved analysis snapshots
+//!
+//! Compares the function / method / class inventory of two `analyze` JSON
+//! outputs (a `DirectoryAnalysis` or a single `AnalysisResult`). Symbols are
+//! keyed by file (relative to the analyzed root) and `stable_id`, so overloads
+//! are told apart; snapshots written before stable ids fall back to the
+//! qualified name. A removed and an added symbol with the same name are a
+//! signature change; other removed and added symbols of the same kind are
+//! then paired up as renames. Symbols present in both are compared by body
+//! hash, which ignores whitespace and comments, to tell an edited
+//! implementation from one that only moved.
+
+use anyhow::{Context, Result};
+use serde::{Deserialize, Serialize};
+use std::collections::{BTreeMap, HashMap, HashSet};
+use std::path::{Path, PathBuf};
+
+use crate::core::git::REVISION_SEPARATOR;
+use crate::core::types::{AnalysisResult, ClassInfo, DirectoryAnalysis, FunctionInfo};
+
+/// Minimum signature similarity for a rename without a matching body hash
+const RENAME_SIMILARITY_THRESHOLD: f64 = 0.9;
+
+/// Bodies this short (in source lines) hash alike too easily to trust
+const TRIVIAL_BODY_SLOC: u32 = 2;
+
+/// A function, method or class in one snapshot
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct DiffSymbol {
+    /// "function", "method" or "class"
+    pub kind: String,
+    pub qualified_name: String,
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub stable_id: String,
+    pub file: String,
+    pub line: u32,
+    pub signature: String,
+    #[serde(skip)]
+    body_hash: String,
+    #[serde(skip)]
+    sloc: u32,
+}
+
+/// A removed symbol matched to an added one
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct RenamedSymbol {
+    pub kind: String,
+    pub old_name: String,
+    pub new_name: String,
+    pub old_file: String,
+    pub new_file: String,
+    pub old_line: u32,
+    pub new_line: u32,
+    /// 1.0 for an identical body hash, otherwise the signature similarity
+    pub similarity: f64,
+    /// "high" for a unique identical body, "low" for heuristic matches
+    pub confidence: String,
+}
+
+/// A symbol present in both snapshots whose signature changed
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct SignatureChange {
+    pub kind: String,
+    pub qualified_name: String,
+    pub file: String,
+    pub line: u32,
+    pub old_signature: String,
+    pub new_signature: String,
+}
+
+/// A symbol present in both snapshots with an unchanged signature
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MatchedSymbol {
+    pub kind: String,
+    pub qualified_name: String,
+    pub file: String,
+    pub old_line: u32,
+    pub new_line: u32,
+}
+
+/// Differences between two snapshots, sorted by file and name
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct SnapshotDiff {
+    pub added: Vec<DiffSymbol>,
+    pub removed: Vec<DiffSymbol>,
+    pub renamed: Vec<RenamedSymbol>,
+    pub signature_changed: Vec<SignatureChange>,
+    /// Same signature, different body hash: the implementation was edited
+    pub body_changed: Vec<MatchedSymbol>,
+    /// Same body hash at a different line: moved (or reformatted) only
+    pub moved: Vec<MatchedSymbol>,
+}
+
+impl SnapshotDiff {
+    /// Load an `analyze` JSON output (directory or single file)
+    pub fn load_snapshot(path: &Path) -> Result<Vec<AnalysisResult>> {
+        let content = std::fs::read_to_string(path)
+            .with_context(|| format!("Failed to read snapshot {}", path.display()))?;
+        let value: serde_json::Value = serde_json::from_str(&content)
+            .with_context(|| format!("Invalid JSON in {}", path.display()))?;
+        
+        if value.get("files").is_some() {
+            let mut analysis: DirectoryAnalysis = serde_json::from_value(value)
+                .with_context(|| format!("{} is not a directory analysis", path.display()))?;
+            // Key files relative to the analyzed root so snapshots of different checkouts line up
+            let root = analysis.directory_path.clone();
+            let suffix = analysis.revision.as_ref().map(|commit| format!("{}{}", REVISION_SEPARATOR, commit));
+            for file in &mut analysis.files {
+                if let Some(path) = suffix.as_deref().and_then(|suffix| file.file_info.path.to_str()?.strip_suffix(suffix)) {
+                    file.file_info.path = PathBuf::from(path);
+                }
+                if let Ok(relative) = file.file_info.path.strip_prefix(&root) {
+                    if !relative.as_os_str().is_empty() {
+                        file.file_info.path = relative.to_path_buf();
+                    }
+                }
+            }
+            Ok(analysis.files)
+        } else {
+            let mut result: AnalysisResult = serde_json::from_value(value)
+                .with_context(|| format!("{} is not an analysis result", path.display()))?;
+            result.file_info.path = result.file_info.name.clone().into();
+            Ok(vec![result])
+        }
+    }
+    
+    /// Compare two snapshots
+    pub fn compare(old: &[AnalysisResult], new: &[AnalysisResult]) -> Self {
+        let by_id = has_stable_ids(old) && has_stable_ids(new);
+        let old_symbols = collect_symbols(old, by_id);
+        let new_symbols = collect_symbols(new, by_id);
+        
+        let mut diff = Self::default();
+        let mut removed = Vec::new();
+        for (key, old_symbol) in &old_symbols {
+            match new_symbols.get(key) {
+                Some(new_symbol) if new_symbol.signature != old_symbol.signature => {
+                    diff.signature_changed.push(SignatureChange {
+                        kind: new_symbol.kind.clone(),
+                        qualified_name: new_symbol.qualified_name.clone(),
+                        file: new_symbol.file.clone(),
+                        line: new_symbol.line,
+                        old_signature: old_symbol.signature.clone(),
+                        new_signature: new_symbol.signature.clone(),
+                    });
+                }
+                // Symbols without a body hash (older snapshots) cannot be compared
+                Some(new_symbol) if old_symbol.body_hash.is_empty() || new_symbol.body_hash.is_empty() => {}
+                Some(new_symbol) if new_symbol.body_hash != old_symbol.body_hash => {
+                    diff.body_changed.push(matched(old_symbol, new_symbol));
+                }
+                Some(new_symbol) if new_symbol.line != old_symbol.line => {
+                    diff.moved.push(matched(old_symbol, new_symbol));
+                }
+                Some(_) => {}
+                None => removed.push(old_symbol.clone()),
+            }
+        }
+        let added: Vec<DiffSymbol> = new_symbols.iter()
+            .filter(|(key, _)| !old_symbols.contains_key(*key))
+            .map(|(_, symbol)| symbol.clone())
+            .collect();
+        
+        // A changed signature is a new stable id: pair the leftovers by name
+        let (changed, removed, added) = match_signature_changes(removed, added);
+        diff.signature_changed.extend(changed);
+        let (renamed, removed, added) = match_renames(removed, added);
+        diff.renamed = renamed;
+        diff.removed = removed;
+        diff.added = added;
+        
+        diff.added.sort_by(|a, b| (&a.file, &a.kind, &a.qualified_name).cmp(&(&b.file, &b.kind, &b.qualified_name)));
+        diff.removed.sort_by(|a, b| (&a.file, &a.kind, &a.qualified_name).cmp(&(&b.file, &b.kind, &b.qualified_name)));
+        diff.signature_changed.sort_by(|a, b| (&a.file, &a.kind, &a.qualified_name).cmp(&(&b.file, &b.kind, &b.qualified_name)));
+        diff.body_changed.sort_by(|a, b| (&a.file, &a.kind, &a.qualified_name).cmp(&(&b.file, &b.kind, &b.qualified_name)));
+        diff.moved.sort_by(|a, b| (&a.file, &a.kind, &a.qualified_name).cmp(&(&b.file, &b.kind, &b.qualified_name)));
+        diff
+    }
+    
+    /// Human readable summary
+    pub fn to_text(&self) -> String {
+        let mut lines = vec![format!(
+            "added: {}, removed: {}, renamed: {}, signature changed: {}, body changed: {}, moved: {}",
+            self.added.len(), self.removed.len(), self.renamed.len(), self.signature_changed.len(),
+            self.body_changed.len(), self.moved.len()
+        )];
+        
+        for symbol in &self.added {
+            lines.push(format!("+ {} {}:{} {}", symbol.kind, symbol.file, symbol.line, symbol.qualified_name));
+        }
+        for symbol in &self.removed {
+            lines.push(format!("- {} {}:{} {}", symbol.kind, symbol.file, symbol.line, symbol.qualified_name));
+        }
+        for rename in &self.renamed {
+            let confidence = if rename.confidence == "low" { " (low confidence)" } else { "" };
+            lines.push(format!(
+                "> {} {}:{} {} -> {}:{} {}{}",
+                rename.kind, rename.old_file, rename.old_line, rename.old_name,
+                rename.new_file, rename.new_line, rename.new_name, confidence
+            ));
+        }
+        for change in &self.signature_changed {
+            lines.push(format!(
+                "~ {} {}:{} {} {} -> {}",
+                change.kind, change.file, change.line, change.qualified_name,
+                change.old_signature, change.new_signature
+            ));
+        }
+        for change in &self.body_changed {
+            lines.push(format!("* {} {}:{} {}", change.kind, change.file, change.new_line, change.qualified_name));
+        }
+        for change in &self.moved {
+            lines.push(format!(
+                "^ {} {}:{} -> {} {}",
+                change.kind, change.file, change.old_line, change.new_line, change.qualified_name
+            ));
+        }
+        
+        lines.join("\n")
+    }
+}
+
+/// Helper: Whether every symbol of a snapshot has a stable id (older snapshots have none)
+fn has_stable_ids(files: &[AnalysisResult]) -> bool {
+    files.iter().all(|file| {
+        file.functions.iter().all(|func| !func.stable_id.is_empty())
+            && file.classes.iter().all(|class| {
+                !class.stable_id.is_empty() && class.methods.iter().all(|method| !method.stable_id.is_empty())
+            })
+    })
+}
+
+/// Helper: All symbols of a snapshot keyed by (file, kind, stable id or qualified name)
+fn collect_symbols(files: &[AnalysisResult], by_id: bool) -> BTreeMap<(String, String, String), DiffSymbol> {
+    let mut symbols = BTreeMap::new();
+    
+    for file in files {
+        let path = file.file_info.path.to_string_lossy().to_string();
+        let mut insert = |symbol: DiffSymbol| {
+            let id = if by_id { symbol.stable_id.clone() } else { symbol.qualified_name.clone() };
+            symbols.entry((symbol.file.clone(), symbol.kind.clone(), id))
+                .or_insert(symbol);
+        };
+        
+        // Some analyzers list methods both at file level and under their class;
+        // the file-level entry wins
+        let mut seen = HashSet::new();
+        for func in &file.functions {
+            if seen.insert((func.start_line, func.name.clone())) {
+                let owner = func.metadata.get("receiver_type").or_else(|| func.metadata.get("class_name"));
+                insert(function_symbol(func, owner, &path));
+            }
+        }
+        
+        for class in &file.classes {
+            insert(class_symbol(class, &path));
+            for method in &class.methods {
+                if seen.insert((method.start_line, method.name.clone())) {
+                    insert(function_symbol(method, Some(&class.name), &path));
+                }
+            }
+        }
+    }
+    
+    symbols
+}
+
+/// Helper: Snapshot entry for a function or method
+fn function_symbol(func: &FunctionInfo, owner: Option<&String>, path: &str) -> DiffSymbol {
+    let signature = func.metadata.get("signature")
+        .cloned()
+        .unwrap_or_else(|| format!("({})", func.parameters.join(", ")));
+    
+    DiffSymbol {
+        kind: if owner.is_some() { "method" } else { "function" }.to_string(),
+        qualified_name: match owner {
+            Some(owner) => format!("{}.{}", owner, func.name),
+            None => func.name.clone(),
+        },
+        stable_id: func.stable_id.clone(),
+        file: path.to_string(),
+        line: func.start_line,
+        signature,
+        body_hash: func.body_hash.clone(),
+        sloc: func.sloc,
+    }
+}
+
+/// Helper: Snapshot entry for a class; its "body" is the set of member bodies
+fn class_symbol(class: &ClassInfo, path: &str) -> DiffSymbol {
+    let mut signature = class.metadata.get("type").cloned().unwrap_or_else(|| "class".to_string());
+    if let Some(parent) = &class.parent_class {
+        signature.push_str(&format!(" : {}", parent));
+    }
+    if !class.type_params.is_empty() {
+        let params: Vec<&str> = class.type_params.iter().map(|p| p.name.as_str()).collect();
+        signature.push_str(&format!(" [{}]", params.join(", ")));
+    }
+    
+    let mut members: Vec<String> = class.methods.iter()
+        .map(|m| format!("{}:{}", m.parameters.len(), m.body_hash))
+        .chain(class.properties.iter().cloned())
+        .collect();
+    members.sort();
+    let body_hash = if members.is_empty() {
+        String::new()
+    } else {
+        blake3::hash(members.join("\n").as_bytes()).to_hex()[..16].to_string()
+    };
+    
+    DiffSymbol {
+        kind: "class".to_string(),
+        qualified_name: class.name.clone(),
+        stable_id: class.stable_id.clone(),
+        file: path.to_string(),
+        line: class.start_line,
+        signature,
+        body_hash,
+        sloc: class.methods.iter().map(|m| m.sloc).sum(),
+    }
+}
+
+/// Helper: Pair a removed and an added symbol sharing file, kind and name as a
+/// signature change; returns (changes, removed, added)
+///
+/// Names held by several removed or added symbols (overloads) stay unpaired.
+fn match_signature_changes(
+    removed: Vec<DiffSymbol>,
+    added: Vec<DiffSymbol>,
+) -> (Vec<SignatureChange>, Vec<DiffSymbol>, Vec<DiffSymbol>) {
+    let key = |symbol: &DiffSymbol| (symbol.file.clone(), symbol.kind.clone(), symbol.qualified_name.clone());
+    let mut counts: HashMap<(String, String, String), (usize, usize)> = HashMap::new();
+    for symbol in &removed {
+        counts.entry(key(symbol)).or_default().0 += 1;
+    }
+    for symbol in &added {
+        counts.entry(key(symbol)).or_default().1 += 1;
+    }
+    
+    let mut added: Vec<Option<DiffSymbol>> = added.into_iter().map(Some).collect();
+    let mut changes = Vec::new();
+    let mut unmatched = Vec::new();
+    for old in removed {
+        let unique = counts.get(&key(&old)) == Some(&(1, 1));
+        let index = added.iter()
+            .position(|new| unique && new.as_ref().map_or(false, |new| key(new) == key(&old)));
+        match index.and_then(|j| added[j].take()) {
+            Some(new) => changes.push(SignatureChange {
+                kind: new.kind,
+                qualified_name: new.qualified_name,
+                file: new.file,
+                line: new.line,
+                old_signature: old.signature,
+                new_signature: new.signature,
+            }),
+            None => unmatched.push(old),
+        }
+    }
+    
+    (changes, unmatched, added.into_iter().flatten().collect())
+}
+
+/// Helper: Pair removed and added symbols as renames; returns (renames, removed, added)
+fn match_renames(
+    removed: Vec<DiffSymbol>,
+    added: Vec<DiffSymbol>,
+) -> (Vec<RenamedSymbol>, Vec<DiffSymbol>, Vec<DiffSymbol>) {
+    let mut removed: Vec<Option<DiffSymbol>> = removed.into_iter().map(Some).collect();
+    let mut added: Vec<Option<DiffSymbol>> = added.into_iter().map(Some).collect();
+    let mut renamed = Vec::new();
+    
+    // 1. Identical body hash; ambiguous or trivial bodies are low confidence
+    let mut old_by_hash: HashMap<(String, String), Vec<usize>> = HashMap::new();
+    for (i, symbol) in removed.iter().enumerate() {
+        if let Some(symbol) = symbol.as_ref().filter(|s| !s.body_hash.is_empty()) {
+            old_by_hash.entry((symbol.kind.clone(), symbol.body_hash.clone())).or_default().push(i);
+        }
+    }
+    let mut new_by_hash: HashMap<(String, String), usize> = HashMap::new();
+    for symbol in added.iter().flatten() {
+        *new_by_hash.entry((symbol.kind.clone(), symbol.body_hash.clone())).or_default() += 1;
+    }
+    
+    for j in 0..added.len() {
+        let key = match added[j].as_ref() {
+            Some(symbol) if !symbol.body_hash.is_empty() => (symbol.kind.clone(), symbol.body_hash.clone()),
+            _ => continue,
+        };
+        let candidates = match old_by_hash.get(&key) {
+            Some(candidates) => candidates,
+            None => continue,
+        };
+        let ambiguous = candidates.len() > 1 || new_by_hash.get(&key).copied().unwrap_or(0) > 1;
+        
+        // Prefer a candidate from the same file
+        let new_file = added[j].as_ref().map(|s| s.file.clone()).unwrap_or_default();
+        let pick = candidates.iter()
+            .copied()
+            .filter(|i| removed[*i].is_some())
+            .min_by_key(|i| removed[*i].as_ref().map_or(true, |s| s.file != new_file));
+        
+        if let Some(i) = pick {
+            let old = removed[i].take().unwrap();
+            let new = added[j].take().unwrap();
+            let trivial = old.sloc <= TRIVIAL_BODY_SLOC && new.sloc <= TRIVIAL_BODY_SLOC;
+            let confidence = if ambiguous || trivial { "low" } else { "high" };
+            renamed.push(rename(old, new, 1.0, confidence));
+        }
+    }
+    
+    // 2. Same kind and file with a near-identical signature and size
+    let mut pairs = Vec::new();
+    for (i, old) in removed.iter().enumerate() {
+        for (j, new) in added.iter().enumerate() {
+            if let (Some(old), Some(new)) = (old, new) {
+                if old.kind == new.kind && old.file == new.file {
+                    let similarity = signature_similarity(old, new);
+                    if similarity >= RENAME_SIMILARITY_THRESHOLD {
+                        pairs.push((similarity, i, j));
+                    }
+                }
+            }
+        }
+    }
+    pairs.sort_by(|a, b| b.0.partial_cmp(&a.0).unwrap_or(std::cmp::Ordering::Equal).then((a.1, a.2).cmp(&(b.1, b.2))));
+    for (similarity, i, j) in pairs {
+        if removed[i].is_some() && added[j].is_some() {
+            let old = removed[i].take().unwrap();
+            let new = added[j].take().unwrap();
+            renamed.push(rename(old, new, similarity, "low"));
+        }
+    }
+    
+    renamed.sort_by(|a, b| (&a.new_file, &a.new_name).cmp(&(&b.new_file, &b.new_name)));
+    (
+        renamed,
+        removed.into_iter().flatten().collect(),
+        added.into_iter().flatten().collect(),
+    )
+}
+
+/// Helper: Build a record for a symbol found in both snapshots
+fn matched(old: &DiffSymbol, new: &DiffSymbol) -> MatchedSymbol {
+    MatchedSymbol {
+        kind: new.kind.clone(),
+        qualified_name: new.qualified_name.clone(),
+        file: new.file.clone(),
+        old_line: old.line,
+        new_line: new.line,
+    }
+}
+
+/// Helper: Build a rename record
+fn rename(old: DiffSymbol, new: DiffSymbol, similarity: f64, confidence: &str) -> RenamedSymbol {
+    RenamedSymbol {
+        kind: new.kind,
+        old_name: old.qualified_name,
+        new_name: new.qualified_name,
+        old_file: old.file,
+        new_file: new.file,
+        old_line: old.line,
+        new_line: new.line,
+        similarity: (similarity * 100.0).round() / 100.0,
+        confidence: confidence.to_string(),
+    }
+}
+
+/// Helper: Similarity of two symbols from their signature tokens and size (0.0 - 1.0)
+fn signature_similarity(old: &DiffSymbol, new: &DiffSymbol) -> f64 {
+    let tokens = |s: &str| -> Vec<String> {
+        s.split(|c: char| !c.is_alphanumeric() && c != '_' && c != '*' && c != '[' && c != ']')
+            .filter(|t| !t.is_empty())
+            .map(String::from)
+            .collect()
+    };
+    let old_tokens = tokens(&old.signature);
+    let new_tokens = tokens(&new.signature);
+    // Parameterless signatures say nothing about identity
+    if old_tokens.is_empty() || new_tokens.is_empty() {
+        return 0.0;
+    }
+    
+    let common = old_tokens.iter().filter(|t| new_tokens.contains(t)).count();
+    let signature = 2.0 * common as f64 / (old_tokens.len() + new_tokens.len()) as f64;
+    let size = if old.sloc.max(new.sloc) == 0 {
+        1.0
+    } else {
+        old.sloc.min(new.sloc) as f64 / old.sloc.max(new.sloc) as f64
+    };
+    
+    0.5 * signature + 0.5 * size
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, Language};
+    use std::path::PathBuf;
+    
+    fn function(name: &str, params: &[&str], body_hash: &str, sloc: u32) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = 1;
+        func.parameters = params.iter().map(|p| p.to_string()).collect();
+        func.body_hash = body_hash.to_string();
+        func.sloc = sloc;
+        func
+    }
+    
+    fn file(functions: Vec<FunctionInfo>) -> AnalysisResult {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("main.go")), Language::Go);
+        result.functions = functions;
+        result
+    }
+    
+    #[test]
+    fn test_added_removed_and_signature_changed() {
+        let old = file(vec![function("keep", &["a int"], "h1", 5), function("gone", &[], "h2", 5)]);
+        let new = file(vec![function("keep", &["a int", "b int"], "h1", 5), function("fresh", &[], "h3", 5)]);
+        
+        let diff = SnapshotDiff::compare(&[old], &[new]);
+        
+        assert_eq!(diff.added.iter().map(|s| s.qualified_name.as_str()).collect::<Vec<_>>(), vec!["fresh"]);
+        assert_eq!(diff.removed.iter().map(|s| s.qualified_name.as_str()).collect::<Vec<_>>(), vec!["gone"]);
+        assert!(diff.renamed.is_empty());
+        assert_eq!(diff.signature_changed.len(), 1);
+        assert_eq!(diff.signature_changed[0].old_signature, "(a int)");
+        assert_eq!(diff.signature_changed[0].new_signature, "(a int, b int)");
+    }
+    
+    #[test]
+    fn test_rename_by_body_hash() {
+        let old = file(vec![function("process", &["data []string"], "abc", 8), function("tiny", &[], "t", 1)]);
+        let new = file(vec![function("transform", &["data []string"], "abc", 8), function("small", &[], "t", 1)]);
+        
+        let diff = SnapshotDiff::compare(&[old], &[new]);
+        
+        assert!(diff.added.is_empty() && diff.removed.is_empty());
+        assert_eq!(diff.renamed.len(), 2);
+        let small = &diff.renamed[0];
+        assert_eq!((small.old_name.as_str(), small.new_name.as_str()), ("tiny", "small"));
+        // A one-line body matches too easily
+        assert_eq!(small.confidence, "low");
+        let transform = &diff.renamed[1];
+        assert_eq!((transform.old_name.as_str(), transform.new_name.as_str()), ("process", "transform"));
+        assert_eq!(transform.confidence, "high");
+    }
+    
+    #[test]
+    fn test_body_changed_and_moved() {
+        let mut moved = function("moved", &[], "m1", 5);
+        moved.start_line = 20;
+        let old = file(vec![function("edited", &["a int"], "e1", 5), function("moved", &[], "m1", 5), function("legacy", &[], "", 5)]);
+        let new = file(vec![function("edited", &["a int"], "e2", 5), moved, function("legacy", &[], "l1", 5)]);
+        
+        let diff = SnapshotDiff::compare(&[old], &[new]);
+        
+        assert_eq!(diff.body_changed.iter().map(|s| s.qualified_name.as_str()).collect::<Vec<_>>(), vec!["edited"]);
+        assert_eq!(diff.moved.len(), 1);
+        assert_eq!((diff.moved[0].old_line, diff.moved[0].new_line), (1, 20));
+        assert!(diff.signature_changed.is_empty());
+        assert!(diff.to_text().contains("^ function main.go:1 -> 20 moved"));
+    }
+    
+    #[test]
+    fn test_overloads_keyed_by_stable_id() {
+        let annotated = |functions: Vec<FunctionInfo>| {
+            let mut result = file(functions);
+            crate::core::stable_id::annotate_stable_ids(&mut result);
+            result
+        };
+        let mut second = function("run", &["s string"], "r2", 5);
+        second.start_line = 10;
+        let old = annotated(vec![function("run", &["n int"], "r1", 5), second.clone()]);
+        second.body_hash = "r3".to_string();
+        let mut changed = function("run", &["n int", "m int"], "r1", 5);
+        changed.start_line = 3;
+        let new = annotated(vec![changed, second]);
+        
+        let diff = SnapshotDiff::compare(&[old], &[new]);
+        
+        // Each overload is matched to itself, not both to the first `run`
+        assert_eq!(diff.body_changed.len(), 1);
+        assert_eq!(diff.body_changed[0].new_line, 10);
+        assert_eq!(diff.signature_changed.len(), 1);
+        assert_eq!(diff.signature_changed[0].new_signature, "(n int, m int)");
+        assert!(diff.added.is_empty() && diff.removed.is_empty() && diff.renamed.is_empty());
+    }
+    
+    #[test]
+    fn test_methods_listed_twice_are_one_symbol() {
+        // Python lists methods in `functions` (with `class_name`) and under their class
+        let python = |methods: Vec<FunctionInfo>| {
+            let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("parser.py")), Language::Python);
+            let mut parser = ClassInfo::new("Parser".to_string());
+            parser.methods = methods.clone();
+            result.classes.push(parser);
+            result.functions = methods.into_iter()
+                .map(|mut method| {
+                    method.metadata.insert("class_name".to_string(), "Parser".to_string());
+                    method
+                })
+                .collect();
+            result
+        };
+        let mut legacy = function("legacy", &["self"], "l1", 5);
+        legacy.start_line = 10;
+        let old = python(vec![function("parse", &["self"], "p1", 5), legacy]);
+        let new = python(vec![function("parse", &["self"], "p1", 5)]);
+        
+        let diff = SnapshotDiff::compare(&[old], &[new]);
+        
+        let removed: Vec<(&str, &str)> = diff.removed.iter().map(|s| (s.kind.as_str(), s.qualified_name.as_str())).collect();
+        assert_eq!(removed, vec![("method", "Parser.legacy")]);
+        assert!(diff.added.is_empty() && diff.renamed.is_empty() && diff.body_changed.is_empty());
+    }
+    
+    #[test]
+    fn test_rename_by_signature_similarity() {
+        let old = file(vec![function("load", &["path string", "strict bool"], "x1", 10)]);
+        let new = file(vec![function("read", &["path string", "strict bool"], "x2", 10)]);
+        
+        let diff = SnapshotDiff::compare(&[old], &[new]);
+        
+        assert_eq!(diff.renamed.len(), 1);
+        assert_eq!(diff.renamed[0].confidence, "low");
+        assert_eq!(diff.renamed[0].similarity, 1.0);
+        
+        // Unrelated signatures stay as added + removed
+        let old = file(vec![function("load", &["path string"], "x1", 10)]);
+        let new = file(vec![function("count", &["n int"], "x2", 3)]);
+        let diff = SnapshotDiff::compare(&[old], &[new]);
+        assert!(diff.renamed.is_empty());
+        assert_eq!((diff.added.len(), diff.removed.len()), (1, 1));
+    }
+}
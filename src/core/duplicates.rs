@@ -0,0 +1,378 @@
+//! Function-level clone detection
+//!
+//! Each function body is reduced to a normalized token stream (comments
+//! dropped, identifiers and literals replaced by placeholders), so renamed
+//! copies (type-2 clones) produce identical streams. Functions with identical
+//! streams are grouped directly; near-identical ones are grouped when the
+//! Jaccard similarity of their token shingles reaches `min_similarity`.
+//! Groups are listed largest first; `--sort-by` / `--top` re-rank them.
+
+use serde::{Deserialize, Serialize};
+use std::collections::{BTreeMap, HashMap, HashSet};
+use std::path::Path;
+
+use crate::core::ranking::{rank, Rankable, RankedSymbol, Ranking};
+use crate::core::types::{DirectoryAnalysis, FunctionInfo};
+
+/// Tokens per shingle for near-identical matching
+const SHINGLE_SIZE: usize = 4;
+
+/// One function taking part in a clone group
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CloneMember {
+    pub file: String,
+    pub name: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub class_name: Option<String>,
+    pub start_line: u32,
+    pub end_line: u32,
+    #[serde(default)]
+    pub cyclomatic_complexity: u32,
+    #[serde(default)]
+    pub fan_in: u32,
+}
+
+/// Functions whose normalized bodies are identical or near-identical
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct CloneGroup {
+    /// Lowest pairwise similarity that joined the group (1.0 = identical)
+    pub similarity: f64,
+    /// Normalized tokens of the largest member
+    pub tokens: usize,
+    /// Physical lines of the largest member
+    pub lines: u32,
+    pub members: Vec<CloneMember>,
+}
+
+/// A group ranks by its first member's location and name, its most complex
+/// member, its line count and the callers of all copies together
+impl Rankable for CloneGroup {
+    fn ranked(&self) -> RankedSymbol<'_> {
+        let first = &self.members[0];
+        RankedSymbol {
+            file: Path::new(&first.file),
+            line: first.start_line,
+            name: &first.name,
+            cyclomatic_complexity: self.members.iter().map(|m| m.cyclomatic_complexity).max().unwrap_or(0),
+            cognitive_complexity: 0,
+            loc: self.lines,
+            fan_in: self.members.iter().map(|m| m.fan_in).sum(),
+        }
+    }
+}
+
+/// Result of the `duplicates` command
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct DuplicateReport {
+    pub min_similarity: f64,
+    pub min_tokens: usize,
+    pub functions_scanned: usize,
+    pub duplicated_functions: usize,
+    pub groups: Vec<CloneGroup>,
+}
+
+/// Helper: Function candidate with its precomputed shingles
+struct Candidate {
+    member: CloneMember,
+    tokens: Vec<u32>,
+    lines: u32,
+    shingles: HashSet<u64>,
+}
+
+impl DuplicateReport {
+    /// Group clones among all functions and methods of an analysis
+    pub fn build(analysis: &DirectoryAnalysis, min_similarity: f64, min_tokens: usize) -> Self {
+        let candidates = collect_candidates(analysis, min_tokens);
+        let mut parent: Vec<usize> = (0..candidates.len()).collect();
+        let mut link_similarity: HashMap<usize, f64> = HashMap::new();
+        
+        // Identical streams: join everything sharing a token sequence
+        let mut by_tokens: HashMap<&[u32], usize> = HashMap::new();
+        for (i, candidate) in candidates.iter().enumerate() {
+            if let Some(&first) = by_tokens.get(candidate.tokens.as_slice()) {
+                union(&mut parent, &mut link_similarity, first, i, 1.0);
+            } else {
+                by_tokens.insert(&candidate.tokens, i);
+            }
+        }
+        
+        // Near-identical streams: compare pairs whose sizes could still reach the threshold
+        if min_similarity < 1.0 {
+            let mut order: Vec<usize> = (0..candidates.len()).collect();
+            order.sort_by_key(|&i| candidates[i].shingles.len());
+            for (pos, &i) in order.iter().enumerate() {
+                let size = candidates[i].shingles.len() as f64;
+                for &j in &order[pos + 1..] {
+                    if size < candidates[j].shingles.len() as f64 * min_similarity {
+                        break;
+                    }
+                    if find(&mut parent, i) == find(&mut parent, j) {
+                        continue;
+                    }
+                    let similarity = jaccard(&candidates[i].shingles, &candidates[j].shingles);
+                    if similarity >= min_similarity {
+                        union(&mut parent, &mut link_similarity, i, j, similarity);
+                    }
+                }
+            }
+        }
+        
+        let mut grouped: BTreeMap<usize, Vec<usize>> = BTreeMap::new();
+        for i in 0..candidates.len() {
+            let root = find(&mut parent, i);
+            grouped.entry(root).or_default().push(i);
+        }
+        
+        let mut groups: Vec<CloneGroup> = grouped.into_iter()
+            .filter(|(_, members)| members.len() > 1)
+            .map(|(root, members)| {
+                let largest = members.iter()
+                    .copied()
+                    .max_by_key(|&i| (candidates[i].tokens.len(), candidates[i].lines))
+                    .unwrap_or(root);
+                let mut clone_members: Vec<CloneMember> = members.iter()
+                    .map(|&i| candidates[i].member.clone())
+                    .collect();
+                clone_members.sort_by(|a, b| (&a.file, a.start_line).cmp(&(&b.file, b.start_line)));
+                
+                CloneGroup {
+                    similarity: link_similarity.get(&root).copied().unwrap_or(1.0),
+                    tokens: candidates[largest].tokens.len(),
+                    lines: candidates[largest].lines,
+                    members: clone_members,
+                }
+            })
+            .collect();
+        groups.sort_by(|a, b| {
+            b.tokens.cmp(&a.tokens).then_with(|| a.members[0].file.cmp(&b.members[0].file))
+        });
+        
+        Self {
+            min_similarity,
+            min_tokens,
+            functions_scanned: candidates.len(),
+            duplicated_functions: groups.iter().map(|g| g.members.len()).sum(),
+            groups,
+        }
+    }
+    
+    /// Order the groups by `--sort-by` (default: largest first) and keep the first `--top`
+    pub fn rank(&mut self, ranking: &Ranking) {
+        rank(&mut self.groups, ranking.sort_by, ranking.top);
+    }
+    
+    /// Human-readable listing, one block per clone group
+    pub fn to_text(&self) -> String {
+        let mut lines = vec![format!(
+            "clone groups: {}, duplicated functions: {} of {}",
+            self.groups.len(), self.duplicated_functions, self.functions_scanned
+        )];
+        
+        for group in &self.groups {
+            lines.push(format!(
+                "# {} functions, {} tokens, {} lines, similarity {:.2}",
+                group.members.len(), group.tokens, group.lines, group.similarity
+            ));
+            for member in &group.members {
+                let name = match &member.class_name {
+                    Some(class) => format!("{}.{}", class, member.name),
+                    None => member.name.clone(),
+                };
+                lines.push(format!("  {}:{}-{} {}", member.file, member.start_line, member.end_line, name));
+            }
+        }
+        
+        lines.join("\n")
+    }
+}
+
+/// Helper: Every function / method with at least `min_tokens` normalized tokens, once
+fn collect_candidates(analysis: &DirectoryAnalysis, min_tokens: usize) -> Vec<Candidate> {
+    let mut candidates = Vec::new();
+    let mut seen = HashSet::new();
+    
+    for file in &analysis.files {
+        let path = file.file_info.path.strip_prefix(&analysis.directory_path)
+            .unwrap_or(&file.file_info.path)
+            .to_string_lossy()
+            .to_string();
+        
+        let methods = file.classes.iter()
+            .flat_map(|class| class.methods.iter().map(move |m| (m, Some(class.name.as_str()))));
+        let functions = file.functions.iter().map(|f| (f, None));
+        
+        // Some analyzers list methods both at file level and under their class
+        for (func, class_name) in methods.chain(functions) {
+            if func.clone_tokens.len() < min_tokens || !seen.insert((path.clone(), func.start_line, func.name.clone())) {
+                continue;
+            }
+            candidates.push(Candidate {
+                member: member(&path, func, class_name),
+                tokens: func.clone_tokens.clone(),
+                lines: func.loc.max(func.end_line.saturating_sub(func.start_line) + 1),
+                shingles: shingles(&func.clone_tokens),
+            });
+        }
+    }
+    
+    candidates
+}
+
+/// Helper: Location entry of a function
+fn member(file: &str, func: &FunctionInfo, class_name: Option<&str>) -> CloneMember {
+    let class_name = class_name
+        .map(str::to_string)
+        .or_else(|| func.metadata.get("class_name").cloned())
+        .or_else(|| func.metadata.get("receiver_type").cloned());
+    
+    CloneMember {
+        file: file.to_string(),
+        name: func.name.clone(),
+        class_name,
+        start_line: func.start_line,
+        end_line: func.end_line,
+        cyclomatic_complexity: func.complexity.cyclomatic_complexity,
+        fan_in: func.fan_in,
+    }
+}
+
+/// Helper: Hashes of all `SHINGLE_SIZE`-token windows
+fn shingles(tokens: &[u32]) -> HashSet<u64> {
+    if tokens.len() < SHINGLE_SIZE {
+        return tokens.iter().map(|&t| t as u64).collect();
+    }
+    
+    tokens.windows(SHINGLE_SIZE)
+        .map(|window| {
+            window.iter().fold(0xcbf29ce484222325u64, |hash, &token| {
+                (hash ^ token as u64).wrapping_mul(0x100000001b3)
+            })
+        })
+        .collect()
+}
+
+/// Helper: |A ∩ B| / |A ∪ B|
+fn jaccard(a: &HashSet<u64>, b: &HashSet<u64>) -> f64 {
+    let union = a.union(b).count();
+    if union == 0 {
+        return 1.0;
+    }
+    a.intersection(b).count() as f64 / union as f64
+}
+
+/// Helper: Union-find root with path halving
+fn find(parent: &mut [usize], mut i: usize) -> usize {
+    while parent[i] != i {
+        parent[i] = parent[parent[i]];
+        i = parent[i];
+    }
+    i
+}
+
+/// Helper: Join two sets, keeping the lowest joining similarity on the new root
+fn union(parent: &mut [usize], link_similarity: &mut HashMap<usize, f64>, a: usize, b: usize, similarity: f64) {
+    let (root_a, root_b) = (find(parent, a), find(parent, b));
+    if root_a == root_b {
+        return;
+    }
+    
+    let lowest = [link_similarity.remove(&root_a), link_similarity.remove(&root_b), Some(similarity)]
+        .into_iter()
+        .flatten()
+        .fold(1.0, f64::min);
+    parent[root_b] = root_a;
+    link_similarity.insert(root_a, lowest);
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::ranking::SortKey;
+    use crate::core::types::{AnalysisResult, FileInfo, Language};
+    use std::path::PathBuf;
+    
+    fn function(name: &str, start: u32, tokens: &[u32]) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = start;
+        func.end_line = start + 5;
+        func.clone_tokens = tokens.to_vec();
+        func
+    }
+    
+    fn analysis(files: Vec<(&str, Vec<FunctionInfo>)>) -> DirectoryAnalysis {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        for (path, functions) in files {
+            let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo").join(path)), Language::Go);
+            result.functions = functions;
+            analysis.files.push(result);
+        }
+        analysis
+    }
+    
+    #[test]
+    fn test_identical_streams_across_files() {
+        let body: Vec<u32> = (0..20).collect();
+        let other: Vec<u32> = (100..120).collect();
+        let analysis = analysis(vec![
+            ("a/util.go", vec![function("parse", 1, &body), function("other", 10, &other)]),
+            ("b/util.go", vec![function("parseCopy", 3, &body)]),
+        ]);
+        
+        let report = DuplicateReport::build(&analysis, 1.0, 10);
+        assert_eq!(report.groups.len(), 1);
+        let group = &report.groups[0];
+        assert_eq!(group.similarity, 1.0);
+        assert_eq!(group.tokens, 20);
+        let names: Vec<_> = group.members.iter().map(|m| (m.file.as_str(), m.name.as_str())).collect();
+        assert_eq!(names, vec![("a/util.go", "parse"), ("b/util.go", "parseCopy")]);
+    }
+    
+    #[test]
+    fn test_near_identical_needs_threshold() {
+        let body: Vec<u32> = (0..40).collect();
+        let mut edited = body.clone();
+        edited[39] = 999;
+        let analysis = analysis(vec![
+            ("a.go", vec![function("f", 1, &body)]),
+            ("b.go", vec![function("g", 1, &edited)]),
+        ]);
+        
+        assert!(DuplicateReport::build(&analysis, 1.0, 10).groups.is_empty());
+        
+        let report = DuplicateReport::build(&analysis, 0.9, 10);
+        assert_eq!(report.groups.len(), 1);
+        assert!(report.groups[0].similarity >= 0.9 && report.groups[0].similarity < 1.0);
+    }
+    
+    #[test]
+    fn test_small_functions_ignored() {
+        let body: Vec<u32> = (0..5).collect();
+        let analysis = analysis(vec![
+            ("a.go", vec![function("f", 1, &body)]),
+            ("b.go", vec![function("g", 1, &body)]),
+        ]);
+        
+        let report = DuplicateReport::build(&analysis, 1.0, 10);
+        assert!(report.groups.is_empty());
+        assert_eq!(report.functions_scanned, 0);
+    }
+    
+    #[test]
+    fn test_rank_groups() {
+        let small: Vec<u32> = (0..20).collect();
+        let large: Vec<u32> = (100..140).collect();
+        let mut complex = function("complexCopy", 1, &small);
+        complex.complexity.cyclomatic_complexity = 8;
+        let analysis = analysis(vec![
+            ("a.go", vec![function("bigA", 1, &large), function("smallA", 20, &small)]),
+            ("b.go", vec![function("bigB", 1, &large), complex]),
+        ]);
+        
+        let mut report = DuplicateReport::build(&analysis, 1.0, 10);
+        assert_eq!(report.groups[0].members[0].name, "bigA");
+        
+        report.rank(&Ranking { sort_by: Some(SortKey::Complexity), top: Some(1) });
+        assert_eq!(report.groups.len(), 1);
+        assert_eq!(report.groups[0].members[0].name, "smallA");
+    }
+}
@@ -0,0 +1,196 @@
+//! 🚦 Complexity gate for CI
+//!
+//! `analyze --fail-on-complexity N` / `--fail-on-cognitive N` /
+//! `--max-function-loc N --fail-on-long-functions`: every function, method
+//! or nested function above a limit is a violation. The report is written first, so artifacts
+//! survive; the violations then go to stderr and the command fails.
+
+use serde::{Deserialize, Serialize};
+use std::collections::HashSet;
+use std::path::Path;
+
+use crate::core::smells::function_lines;
+use crate::core::types::{AnalysisResult, FunctionInfo};
+
+/// Complexity limits; a `None` limit is not enforced
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub struct ComplexityGate {
+    pub max_cyclomatic: Option<u32>,
+    pub max_cognitive: Option<u32>,
+    /// Physical lines of the function span (see [`function_lines`])
+    pub max_function_loc: Option<u32>,
+}
+
+/// One function above a limit
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct GateViolation {
+    pub file: String,
+    pub line: u32,
+    /// `Class.method` or `function`
+    pub name: String,
+    /// The function's `stable_id`; baselines match on it when set
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub stable_id: String,
+    /// "cyclomatic", "cognitive" or "loc"
+    pub metric: String,
+    pub value: u32,
+    pub limit: u32,
+}
+
+impl ComplexityGate {
+    /// Whether any limit is set
+    pub fn is_enabled(&self) -> bool {
+        self.max_cyclomatic.is_some() || self.max_cognitive.is_some() || self.max_function_loc.is_some()
+    }
+    
+    /// Violations in one file; paths are reported relative to `root`
+    pub fn check(&self, file: &AnalysisResult, root: &Path) -> Vec<GateViolation> {
+        let mut violations = Vec::new();
+        if !self.is_enabled() {
+            return violations;
+        }
+        
+        let path = file.file_info.path.strip_prefix(root)
+            .unwrap_or(&file.file_info.path)
+            .to_string_lossy()
+            .to_string();
+        
+        // Some analyzers list methods both at file level and under their class
+        let mut seen = HashSet::new();
+        let methods = file.classes.iter()
+            .flat_map(|class| class.methods.iter().map(move |m| (m, Some(class.name.as_str()))));
+        for (func, class_name) in methods.chain(file.functions.iter().map(|f| (f, None))) {
+            if !seen.insert((func.start_line, func.name.clone())) {
+                continue;
+            }
+            
+            let name = match class_name.or(func.metadata.get("class_name").map(String::as_str)) {
+                Some(class) => format!("{}.{}", class, func.name),
+                None => func.name.clone(),
+            };
+            self.check_function(func, &name, &path, &mut violations);
+        }
+        
+        violations.sort_by_key(|v| v.line);
+        violations
+    }
+    
+    /// Helper: Violations of one function and of the functions nested in it
+    fn check_function(&self, func: &FunctionInfo, name: &str, path: &str, violations: &mut Vec<GateViolation>) {
+        let checks = [
+            ("cyclomatic", func.complexity.cyclomatic_complexity, self.max_cyclomatic),
+            ("cognitive", func.complexity.cognitive_complexity, self.max_cognitive),
+            ("loc", function_lines(func), self.max_function_loc),
+        ];
+        for (metric, value, limit) in checks {
+            let Some(limit) = limit else { continue };
+            if value > limit {
+                violations.push(GateViolation {
+                    file: path.to_string(),
+                    line: func.start_line,
+                    name: name.to_string(),
+                    stable_id: func.stable_id.clone(),
+                    metric: metric.to_string(),
+                    value,
+                    limit,
+                });
+            }
+        }
+        
+        for child in &func.children {
+            // Anonymous functions are already named after their parent (`Run$func1`)
+            let child_name = match child.name.strip_prefix(func.name.as_str()).filter(|rest| rest.starts_with('$')) {
+                Some(rest) => format!("{}{}", name, rest),
+                None => format!("{}.{}", name, child.name),
+            };
+            self.check_function(child, &child_name, path, violations);
+        }
+    }
+}
+
+/// Helper: One line per violation (`src/app.py:12 Parser.parse cyclomatic 14 > 10`)
+pub fn violations_to_text(violations: &[GateViolation]) -> String {
+    let mut output = String::new();
+    for violation in violations {
+        output.push_str(&format!(
+            "{}:{} {} {} {} > {}\n",
+            violation.file, violation.line, violation.name, violation.metric, violation.value, violation.limit
+        ));
+    }
+    output
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ClassInfo, FileInfo, Language};
+    use std::path::PathBuf;
+    
+    fn function(name: &str, line: u32, cyclomatic: u32, cognitive: u32) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = line;
+        func.complexity.cyclomatic_complexity = cyclomatic;
+        func.complexity.cognitive_complexity = cognitive;
+        func
+    }
+    
+    #[test]
+    fn test_check() {
+        let mut file = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/src/app.py")), Language::Python);
+        let parse = function("parse", 3, 12, 20);
+        file.functions = vec![parse.clone(), function("main", 30, 2, 1), function("route", 40, 6, 16)];
+        let mut parser = ClassInfo::new("Parser".to_string());
+        parser.methods.push(parse);
+        file.classes.push(parser);
+        
+        let gate = ComplexityGate { max_cyclomatic: Some(10), max_cognitive: Some(15), max_function_loc: None };
+        let violations = gate.check(&file, Path::new("/repo"));
+        
+        // The method listed twice is reported once, under its class
+        let summary: Vec<(&str, &str, u32)> = violations.iter()
+            .map(|v| (v.name.as_str(), v.metric.as_str(), v.value))
+            .collect();
+        assert_eq!(summary, vec![
+            ("Parser.parse", "cyclomatic", 12),
+            ("Parser.parse", "cognitive", 20),
+            ("route", "cognitive", 16),
+        ]);
+        assert_eq!(violations_to_text(&violations[..1]), "src/app.py:3 Parser.parse cyclomatic 12 > 10\n");
+        
+        let cyclomatic_only = ComplexityGate { max_cyclomatic: Some(10), ..ComplexityGate::default() };
+        assert_eq!(cyclomatic_only.check(&file, Path::new("/repo")).len(), 1);
+        
+        // `main` spans lines 30-45
+        file.functions[1].end_line = 45;
+        let loc_only = ComplexityGate { max_function_loc: Some(15), ..ComplexityGate::default() };
+        let violations = loc_only.check(&file, Path::new("/repo"));
+        assert_eq!(violations.len(), 1);
+        assert_eq!(violations_to_text(&violations), "src/app.py:30 main loc 16 > 15\n");
+        assert!(ComplexityGate::default().check(&file, Path::new("/repo")).is_empty());
+    }
+    
+    #[test]
+    fn test_check_methods_and_nested_functions() {
+        let mut file = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/server.go")), Language::Go);
+        let mut run = function("Run", 10, 4, 2);
+        run.children.push(function("Run$func1", 12, 14, 3));
+        let mut helper = function("helper", 30, 1, 1);
+        helper.children.push(function("inner", 31, 11, 1));
+        file.functions = vec![helper];
+        
+        // Only listed under its class
+        let mut server = ClassInfo::new("Server".to_string());
+        server.methods = vec![function("Serve", 3, 18, 4), run];
+        file.classes.push(server);
+        
+        let gate = ComplexityGate { max_cyclomatic: Some(10), ..ComplexityGate::default() };
+        let names: Vec<(String, u32)> = gate.check(&file, Path::new("/repo")).into_iter()
+            .map(|v| (v.name, v.value))
+            .collect();
+        assert_eq!(names, vec![
+            ("Server.Serve".to_string(), 18),
+            ("Server.Run$func1".to_string(), 14),
+            ("helper.inner".to_string(), 11),
+        ]);
+    }
+}
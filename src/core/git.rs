@@ -0,0 +1,357 @@
+//! 🔀 Files changed relative to a git reference (`--since <ref>`)
+//!
+//! `git diff --name-status -M <ref>` compares the working tree with `<ref>`
+//! and detects renames; untracked files are added on top since they are new
+//! relative to any ref. Paths are kept relative to the repository root, the
+//! form `git show <ref>:<path>` expects, and resolved to absolute paths for
+//! matching against analysis results.
+//!
+//! [`Revision`] reads a file or tree as it was at a commit straight from the
+//! object database (`git ls-tree`, `git cat-file`), without a checkout
+//! (`analyze --rev <commit>`).
+//!
+//! [`line_range_commits`] counts the commits of a [`HistoryWindow`] that
+//! touched a line range, for churn (`analyze --with-churn`).
+
+use anyhow::{Context, Result};
+use serde::{Deserialize, Serialize};
+use std::collections::BTreeMap;
+use std::path::{Path, PathBuf};
+use std::process::Command;
+
+/// Kind of change reported by `git diff --name-status`
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(rename_all = "lowercase")]
+pub enum ChangeStatus {
+    Added,
+    Modified,
+    Deleted,
+    Renamed,
+    Copied,
+    /// File type changed (e.g. regular file to symlink)
+    TypeChanged,
+}
+
+/// One changed path, relative to the repository root
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct FileChange {
+    pub status: ChangeStatus,
+    /// Path in the working tree (the old path for deletions)
+    pub path: PathBuf,
+    /// Path at the reference for renames and copies
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub old_path: Option<PathBuf>,
+}
+
+impl FileChange {
+    /// Path of this file's content at the reference (`None` for new files)
+    pub fn path_at_ref(&self) -> Option<&Path> {
+        match self.status {
+            ChangeStatus::Added => None,
+            ChangeStatus::Renamed | ChangeStatus::Copied => self.old_path.as_deref(),
+            _ => Some(&self.path),
+        }
+    }
+}
+
+/// Working-tree changes relative to a reference
+#[derive(Debug, Clone)]
+pub struct ChangedFiles {
+    /// Repository root (canonical)
+    pub root: PathBuf,
+    pub git_ref: String,
+    pub changes: Vec<FileChange>,
+}
+
+impl ChangedFiles {
+    /// Changes of the repository containing `path` relative to `git_ref`
+    pub fn since(path: &Path, git_ref: &str) -> Result<Self> {
+        let start = if path.is_dir() { path } else { path.parent().unwrap_or_else(|| Path::new(".")) };
+        let start = if start.as_os_str().is_empty() { Path::new(".") } else { start };
+        
+        let toplevel = run_git(start, &["rev-parse", "--show-toplevel"])
+            .with_context(|| format!("{} is not inside a git repository", path.display()))?;
+        let root = std::fs::canonicalize(toplevel.trim())
+            .with_context(|| format!("Failed to resolve git root {}", toplevel.trim()))?;
+        
+        let diff = run_git(&root, &["diff", "--name-status", "-M", "-z", git_ref, "--"])
+            .with_context(|| format!("Failed to list files changed since {}", git_ref))?;
+        let mut changes = parse_name_status(&diff);
+        
+        let untracked = run_git(&root, &["ls-files", "--others", "--exclude-standard", "-z"])
+            .context("Failed to list untracked files")?;
+        changes.extend(untracked.split('\0').filter(|p| !p.is_empty()).map(|p| FileChange {
+            status: ChangeStatus::Added,
+            path: PathBuf::from(p),
+            old_path: None,
+        }));
+        
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("🔀 [RUST] {} files changed since {} in {}", changes.len(), git_ref, root.display());
+        }
+        
+        Ok(Self { root, git_ref: git_ref.to_string(), changes })
+    }
+    
+    /// Absolute paths of changed files that still exist in the working tree
+    pub fn existing_files(&self) -> Vec<PathBuf> {
+        self.changes.iter()
+            .filter(|change| change.status != ChangeStatus::Deleted)
+            .map(|change| self.root.join(&change.path))
+            .collect()
+    }
+    
+    /// Renamed files, old path -> new path
+    pub fn renames(&self) -> BTreeMap<&Path, &Path> {
+        self.changes.iter()
+            .filter(|change| change.status == ChangeStatus::Renamed)
+            .filter_map(|change| Some((change.old_path.as_deref()?, change.path.as_path())))
+            .collect()
+    }
+    
+    /// Change record of a working-tree file, matched by canonical path
+    pub fn get(&self, file: &Path) -> Option<&FileChange> {
+        let file = std::fs::canonicalize(file).ok()?;
+        let relative = file.strip_prefix(&self.root).ok()?;
+        self.changes.iter()
+            .find(|change| change.status != ChangeStatus::Deleted && change.path == relative)
+    }
+}
+
+/// Separator between a path and its commit in virtual paths (`src/lib.rs@1a2b3c4`)
+pub const REVISION_SEPARATOR: char = '@';
+
+/// A blob listed under the requested path at a revision
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct RevisionFile {
+    /// Path as the user would name it today: the requested path joined with the rest
+    pub path: PathBuf,
+    /// Path relative to the repository root, as `git cat-file` expects it
+    pub repo_path: String,
+    pub size_bytes: u64,
+}
+
+/// A file or directory as it was at one commit
+#[derive(Debug, Clone)]
+pub struct Revision {
+    /// Existing directory git runs in (the path itself, or its nearest existing ancestor)
+    dir: PathBuf,
+    /// Abbreviated commit id the revision resolved to
+    pub commit: String,
+    /// Blobs under the requested path, in path order
+    pub files: Vec<RevisionFile>,
+    /// Whether the requested path names a single file rather than a tree
+    pub is_file: bool,
+}
+
+impl Revision {
+    /// List what `path` held at `rev`; the path need not exist in the working tree
+    pub fn open(path: &Path, rev: &str) -> Result<Self> {
+        // Deleted since: run git in the nearest directory that still exists
+        let mut dir = if path.as_os_str().is_empty() { Path::new(".") } else { path };
+        while !dir.is_dir() {
+            dir = match dir.parent() {
+                Some(parent) if !parent.as_os_str().is_empty() => parent,
+                _ => Path::new("."),
+            };
+        }
+        let rest = path.strip_prefix(dir).unwrap_or(Path::new(""));
+        let rest = if rest.as_os_str().is_empty() { "." } else { rest.to_str().context("Path is not valid UTF-8")? };
+        
+        let commit = run_git(dir, &["rev-parse", "--short", "--verify", &format!("{}^{{commit}}", rev)])
+            .with_context(|| format!("Unknown revision `{}`", rev))?
+            .trim()
+            .to_string();
+        let prefix = run_git(dir, &["rev-parse", "--show-prefix"])?.trim().to_string();
+        let listing = run_git(dir, &["ls-tree", "-r", "-l", "-z", "--full-name", &commit, "--", rest])
+            .with_context(|| format!("Failed to list {} at {}", path.display(), rev))?;
+        
+        let files: Vec<RevisionFile> = parse_ls_tree(&listing).into_iter()
+            .map(|(repo_path, size_bytes)| RevisionFile {
+                path: dir.join(repo_path.strip_prefix(&prefix).unwrap_or(&repo_path)),
+                repo_path,
+                size_bytes,
+            })
+            .collect();
+        if files.is_empty() {
+            anyhow::bail!("{} does not exist at revision {} ({})", path.display(), rev, commit);
+        }
+        
+        let requested = format!("{}{}", prefix, rest.trim_end_matches('/'));
+        let is_file = files.len() == 1 && files[0].repo_path == requested;
+        Ok(Self { dir: dir.to_path_buf(), commit, files, is_file })
+    }
+    
+    /// Content of a listed blob
+    pub fn read(&self, file: &RevisionFile) -> Result<Vec<u8>> {
+        let spec = format!("{}:{}", self.commit, file.repo_path);
+        git_output(&self.dir, &["cat-file", "blob", &spec])
+            .with_context(|| format!("Failed to read {} at {}", file.repo_path, self.commit))
+    }
+    
+    /// Virtual path of a listed blob: `<path>@<commit>`
+    pub fn virtual_path(&self, file: &RevisionFile) -> PathBuf {
+        PathBuf::from(format!("{}{}{}", file.path.display(), REVISION_SEPARATOR, self.commit))
+    }
+}
+
+/// Helper: Blobs of `git ls-tree -r -l -z` (`<mode> blob <sha> <size>\t<path>\0...`);
+/// symlinks and submodules are left out
+fn parse_ls_tree(output: &str) -> Vec<(String, u64)> {
+    output.split('\0')
+        .filter_map(|record| {
+            let (meta, path) = record.split_once('\t')?;
+            let mut fields = meta.split_whitespace();
+            let mode = fields.next()?;
+            if fields.next()? != "blob" || mode == "120000" {
+                return None;
+            }
+            let size = fields.nth(1)?.parse().ok()?;
+            Some((path.to_string(), size))
+        })
+        .collect()
+}
+
+/// Commits of HEAD's history a churn count looks at
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub enum HistoryWindow {
+    /// All of HEAD's history
+    All,
+    /// Commits after `boundary`, the newest commit outside the window
+    After(String),
+    /// No commit in the window
+    Empty,
+}
+
+impl HistoryWindow {
+    /// Commits since `since` (a `git log --since` date, e.g. `6.months`) in the
+    /// repository containing `dir`
+    ///
+    /// `git log -L` ignores `--since`, so the window becomes a commit range.
+    pub fn since(dir: &Path, since: &str) -> Result<Self> {
+        let since_arg = format!("--since={}", since);
+        if run_git(dir, &["rev-list", "-1", &since_arg, "HEAD"])?.trim().is_empty() {
+            return Ok(Self::Empty);
+        }
+        let before_arg = format!("--before={}", since);
+        let boundary = run_git(dir, &["rev-list", "-1", &before_arg, "HEAD"])?.trim().to_string();
+        Ok(if boundary.is_empty() { Self::All } else { Self::After(boundary) })
+    }
+}
+
+/// Commits of `window` that touched lines `start..=end` of `file` as they are at HEAD
+///
+/// `git log -L` re-maps the range through every earlier commit, so lines
+/// shifted by edits above the function still count as the same function.
+pub fn line_range_commits(file: &Path, start: u32, end: u32, window: &HistoryWindow) -> Result<u32> {
+    let range = match window {
+        HistoryWindow::Empty => return Ok(0),
+        HistoryWindow::All => "HEAD".to_string(),
+        HistoryWindow::After(boundary) => format!("{}..HEAD", boundary),
+    };
+    let dir = file.parent().filter(|dir| !dir.as_os_str().is_empty()).unwrap_or_else(|| Path::new("."));
+    let name = file.file_name()
+        .and_then(|name| name.to_str())
+        .with_context(|| format!("Path is not valid UTF-8: {}", file.display()))?;
+    
+    let lines = format!("-L{},{}:{}", start, end.max(start), name);
+    // One marker line per commit; patch lines never start with \x1f
+    let output = run_git(dir, &["log", "--format=%x1f%H", &lines, &range])?;
+    Ok(output.lines().filter(|line| line.starts_with('\u{1f}')).count() as u32)
+}
+
+/// Helper: Run git in `dir`, returning stdout; stderr becomes the error
+fn run_git(dir: &Path, args: &[&str]) -> Result<String> {
+    let output = git_output(dir, args)?;
+    Ok(String::from_utf8_lossy(&output).into_owned())
+}
+
+/// Helper: Run git in `dir`, returning raw stdout (blob contents)
+fn git_output(dir: &Path, args: &[&str]) -> Result<Vec<u8>> {
+    let output = Command::new("git")
+        .args(args)
+        .current_dir(dir)
+        .output()
+        .context("Failed to run git. Make sure git is installed")?;
+    
+    if !output.status.success() {
+        anyhow::bail!("git {} failed: {}", args.join(" "), String::from_utf8_lossy(&output.stderr).trim());
+    }
+    Ok(output.stdout)
+}
+
+/// Helper: Parse `git diff --name-status -z` (`M\0path\0R100\0old\0new\0...`)
+fn parse_name_status(output: &str) -> Vec<FileChange> {
+    let mut fields = output.split('\0').filter(|f| !f.is_empty());
+    let mut changes = Vec::new();
+    
+    while let Some(status) = fields.next() {
+        let (status, paired) = match status.chars().next() {
+            Some('A') => (ChangeStatus::Added, false),
+            Some('M') | Some('U') => (ChangeStatus::Modified, false),
+            Some('D') => (ChangeStatus::Deleted, false),
+            Some('T') => (ChangeStatus::TypeChanged, false),
+            Some('R') => (ChangeStatus::Renamed, true),
+            Some('C') => (ChangeStatus::Copied, true),
+            // Unknown status letter: skip its path
+            _ => {
+                fields.next();
+                continue;
+            }
+        };
+        
+        let Some(first) = fields.next() else { break };
+        let change = if paired {
+            let Some(second) = fields.next() else { break };
+            FileChange { status, path: PathBuf::from(second), old_path: Some(PathBuf::from(first)) }
+        } else {
+            FileChange { status, path: PathBuf::from(first), old_path: None }
+        };
+        changes.push(change);
+    }
+    
+    changes
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    #[test]
+    fn test_parse_name_status() {
+        let output = "M\0src/main.rs\0R087\0src/old_name.go\0src/new_name.go\0D\0gone.py\0A\0new.ts\0C100\0a.rs\0b.rs\0";
+        let changes = parse_name_status(output);
+        
+        assert_eq!(changes.len(), 5);
+        assert_eq!(changes[0], FileChange { status: ChangeStatus::Modified, path: "src/main.rs".into(), old_path: None });
+        assert_eq!(changes[1].status, ChangeStatus::Renamed);
+        assert_eq!(changes[1].path, PathBuf::from("src/new_name.go"));
+        assert_eq!(changes[1].path_at_ref(), Some(Path::new("src/old_name.go")));
+        assert_eq!(changes[2].status, ChangeStatus::Deleted);
+        assert_eq!(changes[3].path_at_ref(), None);
+        assert_eq!(changes[4].old_path.as_deref(), Some(Path::new("a.rs")));
+    }
+    
+    #[test]
+    fn test_parse_ls_tree() {
+        let output = "100644 blob 1f2e3d      120\tsrc/main.go\0120000 blob 4a5b6c       10\tlink.go\0160000 commit 7d8e9f       -\tvendor/lib\0100755 blob 0a1b2c     4096\tbin/run.sh\0";
+        assert_eq!(parse_ls_tree(output), vec![
+            ("src/main.go".to_string(), 120),
+            ("bin/run.sh".to_string(), 4096),
+        ]);
+    }
+    
+    #[test]
+    fn test_existing_files_and_renames() {
+        let changed = ChangedFiles {
+            root: PathBuf::from("/repo"),
+            git_ref: "main".to_string(),
+            changes: parse_name_status("M\0a.go\0D\0b.go\0R100\0c.go\0pkg/c.go\0"),
+        };
+        
+        assert_eq!(changed.existing_files(), vec![PathBuf::from("/repo/a.go"), PathBuf::from("/repo/pkg/c.go")]);
+        let renames = changed.renames();
+        assert_eq!(renames.get(Path::new("c.go")), Some(&Path::new("pkg/c.go")));
+        assert_eq!(renames.len(), 1);
+    }
+}
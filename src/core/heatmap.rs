@@ -0,0 +1,184 @@
+//! 🌡️ Complexity distribution for the `complexity-heatmap` command
+//!
+//! Functions are bucketed by cyclomatic complexity into fixed bands, over
+//! the whole project and per file. Comparing the counts between runs shows
+//! whether refactoring shifts code out of the high bands, which an average
+//! hides. Generated files are left out, as in `stats`.
+
+use serde::{Deserialize, Serialize};
+use std::collections::HashSet;
+
+use crate::core::types::{AnalysisResult, DirectoryAnalysis};
+
+/// Lower bound of each band; the last one is open-ended
+pub const BAND_STARTS: [u32; 4] = [1, 6, 11, 21];
+
+/// Width of the longest bar in the text chart
+const BAR_WIDTH: usize = 40;
+
+/// Function counts of one file, parallel to `bands`
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct FileHeatmap {
+    pub file: String,
+    pub functions: u32,
+    pub counts: Vec<u32>,
+}
+
+/// Result of the `complexity-heatmap` command
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ComplexityHeatmap {
+    /// Band labels (`1-5`, `6-10`, `11-20`, `21+`)
+    pub bands: Vec<String>,
+    pub total_functions: u32,
+    /// Functions per band over every file
+    pub overall: Vec<u32>,
+    /// Files with at least one function, by path
+    pub files: Vec<FileHeatmap>,
+}
+
+impl ComplexityHeatmap {
+    /// Bucket the functions and methods of every file of an analysis
+    pub fn build(analysis: &DirectoryAnalysis) -> Self {
+        let mut overall = vec![0; BAND_STARTS.len()];
+        let mut files = Vec::new();
+        
+        for file in analysis.files.iter().filter(|f| !f.file_info.generated) {
+            let mut counts = vec![0; BAND_STARTS.len()];
+            for complexity in function_complexities(file) {
+                counts[band_of(complexity)] += 1;
+            }
+            let functions: u32 = counts.iter().sum();
+            if functions == 0 {
+                continue;
+            }
+            for (total, count) in overall.iter_mut().zip(&counts) {
+                *total += count;
+            }
+            
+            let path = file.file_info.path.strip_prefix(&analysis.directory_path)
+                .unwrap_or(&file.file_info.path)
+                .to_string_lossy()
+                .to_string();
+            files.push(FileHeatmap { file: path, functions, counts });
+        }
+        files.sort_by(|a, b| a.file.cmp(&b.file));
+        
+        Self {
+            bands: band_labels(),
+            total_functions: overall.iter().sum(),
+            overall,
+            files,
+        }
+    }
+    
+    /// Bar chart of the overall distribution, then a count table per file
+    pub fn to_text(&self) -> String {
+        let mut lines = vec![format!("functions: {}", self.total_functions)];
+        
+        let largest = self.overall.iter().copied().max().unwrap_or(0);
+        for (band, &count) in self.bands.iter().zip(&self.overall) {
+            let share = if self.total_functions > 0 { count as f64 * 100.0 / self.total_functions as f64 } else { 0.0 };
+            lines.push(format!("  {:<6} {} {} ({:.1}%)", band, bar(count, largest), count, share));
+        }
+        
+        if !self.files.is_empty() {
+            let width = self.files.iter().map(|f| f.file.len()).max().unwrap_or(0).max("file".len());
+            lines.push(String::new());
+            let header: Vec<String> = self.bands.iter().map(|band| format!("{:>6}", band)).collect();
+            lines.push(format!("{:<width$} {}", "file", header.join(""), width = width));
+            for file in &self.files {
+                let counts: Vec<String> = file.counts.iter().map(|count| format!("{:>6}", count)).collect();
+                lines.push(format!("{:<width$} {}", file.file, counts.join(""), width = width));
+            }
+        }
+        
+        lines.join("\n")
+    }
+}
+
+/// Helper: Index of the band containing a cyclomatic complexity (0 counts as the lowest)
+fn band_of(complexity: u32) -> usize {
+    BAND_STARTS.iter().rposition(|&start| complexity >= start).unwrap_or(0)
+}
+
+/// Helper: `1-5`, `6-10`, ..., `21+`
+fn band_labels() -> Vec<String> {
+    BAND_STARTS.iter().enumerate()
+        .map(|(i, start)| match BAND_STARTS.get(i + 1) {
+            Some(next) => format!("{}-{}", start, next - 1),
+            None => format!("{}+", start),
+        })
+        .collect()
+}
+
+/// Helper: Bar scaled to the largest band; any non-empty band gets at least one block
+fn bar(count: u32, largest: u32) -> String {
+    if count == 0 || largest == 0 {
+        return String::new();
+    }
+    let width = ((count as usize * BAR_WIDTH) / largest as usize).max(1);
+    "█".repeat(width)
+}
+
+/// Helper: Cyclomatic complexity of each function and method of a file, counted once
+fn function_complexities(file: &AnalysisResult) -> Vec<u32> {
+    // Some analyzers list methods both at file level and under their class
+    let mut seen = HashSet::new();
+    file.classes.iter()
+        .flat_map(|class| class.methods.iter())
+        .chain(file.functions.iter())
+        .filter(|func| seen.insert((func.start_line, func.name.clone())))
+        .map(|func| func.complexity.cyclomatic_complexity)
+        .collect()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ClassInfo, FileInfo, FunctionInfo, Language};
+    use std::path::PathBuf;
+    
+    fn function(name: &str, line: u32, complexity: u32) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = line;
+        func.complexity.cyclomatic_complexity = complexity;
+        func
+    }
+    
+    #[test]
+    fn test_bands() {
+        assert_eq!(band_labels(), vec!["1-5", "6-10", "11-20", "21+"]);
+        let bands: Vec<usize> = [0, 1, 5, 6, 10, 11, 20, 21, 99].iter().map(|&c| band_of(c)).collect();
+        assert_eq!(bands, vec![0, 0, 0, 1, 1, 2, 2, 3, 3]);
+    }
+    
+    #[test]
+    fn test_build_counts_overall_and_per_file() {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        
+        let mut b = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/b.go")), Language::Go);
+        b.functions = vec![function("small", 1, 2), function("huge", 10, 30)];
+        analysis.files.push(b);
+        
+        // The method is listed twice but counted once
+        let mut a = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/a.py")), Language::Python);
+        let mut class = ClassInfo::new("Parser".to_string());
+        class.methods.push(function("parse", 4, 12));
+        a.classes.push(class);
+        a.functions = vec![function("parse", 4, 12), function("helper", 20, 7)];
+        analysis.files.push(a);
+        
+        let empty = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/empty.go")), Language::Go);
+        analysis.files.push(empty);
+        
+        let heatmap = ComplexityHeatmap::build(&analysis);
+        assert_eq!(heatmap.total_functions, 4);
+        assert_eq!(heatmap.overall, vec![1, 1, 1, 1]);
+        let files: Vec<(&str, &[u32])> = heatmap.files.iter().map(|f| (f.file.as_str(), f.counts.as_slice())).collect();
+        assert_eq!(files, vec![("a.py", &[0, 1, 1, 0][..]), ("b.go", &[1, 0, 0, 1][..])]);
+        
+        let text = heatmap.to_text();
+        assert!(text.starts_with("functions: 4\n  1-5    ████████████████████████████████████████ 1 (25.0%)"));
+        assert!(text.contains("file    1-5  6-10 11-20   21+\na.py      0     1     1     0"));
+    }
+}
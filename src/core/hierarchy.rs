@@ -0,0 +1,432 @@
+//! Type hierarchy construction for NekoCode Rust
+//!
+//! Builds the `extends` / `implements` graph of the classes, interfaces and
+//! other types the language analyzers report. Base types are resolved by name
+//! (generic arguments and qualifiers stripped), preferring a declaration in
+//! the same file; bases declared outside the analyzed code become leaf nodes
+//! marked `external`. Edges point from the subtype to its supertype.
+
+use serde::{Deserialize, Serialize};
+use std::collections::{BTreeSet, HashMap, HashSet};
+use std::path::PathBuf;
+
+use crate::core::callgraph::dot_quote;
+use crate::core::types::{AnalysisResult, ClassInfo, DirectoryAnalysis};
+
+/// A type in the hierarchy
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct HierarchyNode {
+    /// Unique node id (type name, suffixed with the file when ambiguous)
+    pub id: String,
+    pub name: String,
+    /// `None` for external types
+    pub file: Option<PathBuf>,
+    pub line: u32,
+    /// Analyzer type ("class", "interface", "trait", ...) or "external"
+    pub kind: String,
+    /// Declared outside the analyzed files
+    pub external: bool,
+    /// Distinct supertypes reachable through extends / implements edges
+    pub ancestors: usize,
+    /// Distinct subtypes reaching this type through extends / implements edges
+    pub descendants: usize,
+}
+
+/// A subtype -> supertype edge
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct HierarchyEdge {
+    pub from: String,
+    pub to: String,
+    /// "extends" or "implements"
+    pub relation: String,
+}
+
+/// Inheritance / implementation graph
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct TypeHierarchy {
+    pub nodes: Vec<HierarchyNode>,
+    pub edges: Vec<HierarchyEdge>,
+}
+
+impl TypeHierarchy {
+    /// Build the hierarchy for every file of a directory analysis
+    pub fn build(analysis: &DirectoryAnalysis) -> Self {
+        Self::from_files(&analysis.files)
+    }
+
+    /// Build the hierarchy for a set of file results
+    pub fn from_files(files: &[AnalysisResult]) -> Self {
+        let classes: Vec<(usize, &ClassInfo)> = files.iter()
+            .enumerate()
+            .flat_map(|(file_index, file)| file.classes.iter().map(move |class| (file_index, class)))
+            .collect();
+
+        // Names declared more than once get the file appended to their id
+        let mut name_counts: HashMap<&str, usize> = HashMap::new();
+        for (_, class) in &classes {
+            *name_counts.entry(class.name.as_str()).or_insert(0) += 1;
+        }
+
+        let mut nodes: Vec<HierarchyNode> = classes.iter().map(|(file_index, class)| {
+            let file = files[*file_index].file_info.path.clone();
+            let id = if name_counts[class.name.as_str()] > 1 {
+                format!("{}@{}:{}", class.name, file.display(), class.start_line)
+            } else {
+                class.name.clone()
+            };
+
+            HierarchyNode {
+                id,
+                name: class.name.clone(),
+                file: Some(file),
+                line: class.start_line,
+                kind: class.metadata.get("type").cloned().unwrap_or_else(|| "class".to_string()),
+                external: false,
+                ancestors: 0,
+                descendants: 0,
+            }
+        }).collect();
+
+        let mut edges = Vec::new();
+        let mut external: BTreeSet<String> = BTreeSet::new();
+
+        for (index, (file_index, class)) in classes.iter().enumerate() {
+            for (base, relation) in Self::supertypes(class) {
+                let target = match Self::resolve(&classes, *file_index, &base) {
+                    Some(target) if target != index => nodes[target].id.clone(),
+                    Some(_) => continue, // `class Node extends Node<T>`-style self reference
+                    None => {
+                        let name = base_name(&base).to_string();
+                        external.insert(name.clone());
+                        name
+                    }
+                };
+
+                let edge = HierarchyEdge { from: nodes[index].id.clone(), to: target, relation: relation.to_string() };
+                if !edges.contains(&edge) {
+                    edges.push(edge);
+                }
+            }
+        }
+
+        // External types are leaves, listed after the analyzed ones
+        for name in external {
+            nodes.push(HierarchyNode {
+                id: name.clone(),
+                name,
+                file: None,
+                line: 0,
+                kind: "external".to_string(),
+                external: true,
+                ancestors: 0,
+                descendants: 0,
+            });
+        }
+
+        let mut hierarchy = Self { nodes, edges };
+        hierarchy.count_relatives();
+        hierarchy
+    }
+
+    /// Look up a node by id
+    pub fn node(&self, id: &str) -> Option<&HierarchyNode> {
+        self.nodes.iter().find(|n| n.id == id)
+    }
+
+    /// Render as a GraphViz DOT digraph; supertypes above their subtypes
+    pub fn to_dot(&self) -> String {
+        let mut dot = String::new();
+        dot.push_str("digraph hierarchy {\n");
+        dot.push_str("    rankdir=BT;\n");
+        dot.push_str("    node [shape=box, fontname=\"Helvetica\"];\n");
+
+        for node in &self.nodes {
+            let style = if node.external { ", style=dashed" } else { "" };
+            let shape = if matches!(node.kind.as_str(), "interface" | "trait" | "protocol") { "ellipse" } else { "box" };
+            dot.push_str(&format!("    {} [label={}, shape={}{}];\n",
+                                  dot_quote(&node.id), dot_quote(&node.name), shape, style));
+        }
+
+        // Open arrowheads for implemented interfaces (UML realization)
+        for edge in &self.edges {
+            let style = if edge.relation == "implements" { "style=dashed, arrowhead=empty" } else { "arrowhead=empty" };
+            dot.push_str(&format!("    {} -> {} [{}];\n", dot_quote(&edge.from), dot_quote(&edge.to), style));
+        }
+
+        dot.push_str("}\n");
+        dot
+    }
+
+    /// Helper: Declared supertypes of a class with their relation
+    ///
+    /// Analyzers put the superclass in `parent_class` (C++ and Python list every
+    /// base in `multiple_inheritance`) and interfaces in `implements` or the
+    /// comma-separated `interfaces` metadata (Java, C#, Kotlin).
+    fn supertypes(class: &ClassInfo) -> Vec<(String, &'static str)> {
+        let mut supertypes = Vec::new();
+
+        match class.metadata.get("multiple_inheritance") {
+            Some(bases) => supertypes.extend(split_types(bases).into_iter().map(|base| (base, "extends"))),
+            None => supertypes.extend(class.parent_class.iter().map(|base| (base.clone(), "extends"))),
+        }
+
+        // Interface inheritance (`interface A extends B, C`) is still an extends edge
+        let relation = match class.metadata.get("type").map(|t| t.as_str()) {
+            Some("interface") | Some("trait") | Some("protocol") => "extends",
+            _ => "implements",
+        };
+        let mut interfaces: Vec<String> = class.implements.clone();
+        if let Some(listed) = class.metadata.get("interfaces") {
+            interfaces.extend(split_types(listed));
+        }
+        for interface in interfaces {
+            if !supertypes.iter().any(|(base, _)| base_name(base) == base_name(&interface)) {
+                supertypes.push((interface, relation));
+            }
+        }
+
+        supertypes.retain(|(base, _)| !base_name(base).is_empty());
+        supertypes
+    }
+
+    /// Helper: Class declaring a base type, preferring the referencing file
+    ///
+    /// Singleton objects (Kotlin / Scala `object`) cannot be extended, so a
+    /// Scala companion never shadows the class of the same name.
+    fn resolve(classes: &[(usize, &ClassInfo)], file_index: usize, base: &str) -> Option<usize> {
+        let name = base_name(base);
+        let candidates: Vec<usize> = classes.iter()
+            .enumerate()
+            .filter(|(_, (_, class))| class.name == name || base_name(&class.name) == name)
+            .filter(|(_, (_, class))| !matches!(class.metadata.get("type").map(String::as_str), Some("object" | "case_object" | "package_object")))
+            .map(|(index, _)| index)
+            .collect();
+
+        candidates.iter()
+            .copied()
+            .find(|&index| classes[index].0 == file_index)
+            .or_else(|| if candidates.len() == 1 { Some(candidates[0]) } else { None })
+    }
+
+    /// Helper: Fill in ancestor and descendant counts (cycle-safe)
+    fn count_relatives(&mut self) {
+        let index_of: HashMap<&str, usize> = self.nodes.iter()
+            .enumerate()
+            .map(|(index, node)| (node.id.as_str(), index))
+            .collect();
+
+        let mut parents: Vec<Vec<usize>> = vec![Vec::new(); self.nodes.len()];
+        let mut children: Vec<Vec<usize>> = vec![Vec::new(); self.nodes.len()];
+        for edge in &self.edges {
+            if let (Some(&from), Some(&to)) = (index_of.get(edge.from.as_str()), index_of.get(edge.to.as_str())) {
+                parents[from].push(to);
+                children[to].push(from);
+            }
+        }
+
+        for index in 0..self.nodes.len() {
+            self.nodes[index].ancestors = reachable(&parents, index);
+            self.nodes[index].descendants = reachable(&children, index);
+        }
+    }
+}
+
+/// Helper: Number of nodes reachable from `start`, excluding itself
+fn reachable(adjacency: &[Vec<usize>], start: usize) -> usize {
+    let mut seen: HashSet<usize> = HashSet::new();
+    let mut stack = vec![start];
+    while let Some(node) = stack.pop() {
+        for &next in &adjacency[node] {
+            if next != start && seen.insert(next) {
+                stack.push(next);
+            }
+        }
+    }
+    seen.len()
+}
+
+/// Helper: Type name without generic arguments, qualifiers or pointer marks
+///
+/// `State<Counter>` -> `State`, `java.util.List<T>` -> `List`, `public Base` -> `Base`
+fn base_name(base: &str) -> &str {
+    let base = base.split(|c| c == '<' || c == '[' || c == '(').next().unwrap_or(base).trim();
+    let base = base.rsplit(|c: char| c.is_whitespace()).next().unwrap_or(base);
+    let base = base.rsplit(|c| c == '.' || c == ':' || c == '\\').next().unwrap_or(base);
+    base.trim_matches(|c| c == '*' || c == '&' || c == '?')
+}
+
+/// Helper: Split a comma-separated type list at top-level commas (`Map<K, V>, I`)
+fn split_types(list: &str) -> Vec<String> {
+    let mut types = Vec::new();
+    let mut depth = 0;
+    let mut current = String::new();
+    for c in list.chars() {
+        match c {
+            '<' | '(' | '[' => depth += 1,
+            '>' | ')' | ']' => depth -= 1,
+            ',' if depth == 0 => {
+                types.push(current.trim().to_string());
+                current.clear();
+                continue;
+            }
+            _ => {}
+        }
+        current.push(c);
+    }
+    types.push(current.trim().to_string());
+    types.retain(|t| !t.is_empty());
+    types
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, Language};
+
+    fn class(name: &str, line: u32, parent: Option<&str>, implements: &[&str]) -> ClassInfo {
+        let mut class = ClassInfo::new(name.to_string());
+        class.start_line = line;
+        class.end_line = line + 5;
+        class.parent_class = parent.map(|p| p.to_string());
+        class.implements = implements.iter().map(|i| i.to_string()).collect();
+        class
+    }
+
+    fn analysis(files: Vec<(&str, Vec<ClassInfo>)>) -> DirectoryAnalysis {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("."));
+        for (path, classes) in files {
+            let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from(path)), Language::Dart);
+            result.classes = classes;
+            analysis.files.push(result);
+        }
+        analysis
+    }
+
+    fn edge(from: &str, to: &str, relation: &str) -> HierarchyEdge {
+        HierarchyEdge { from: from.to_string(), to: to.to_string(), relation: relation.to_string() }
+    }
+
+    /// Mirrors the widget hierarchy of test_samples/sample.dart
+    fn widgets() -> DirectoryAnalysis {
+        analysis(vec![("sample.dart", vec![
+            class("Widget", 10, None, &[]),
+            class("StatelessWidget", 18, Some("Widget"), &[]),
+            class("StatefulWidget", 24, Some("Widget"), &["Comparable<StatefulWidget>"]),
+            class("Counter", 33, Some("StatefulWidget"), &[]),
+            class("_CounterState", 50, Some("State<Counter>"), &[]),
+        ])])
+    }
+
+    #[test]
+    fn test_build_edges_and_external_leaves() {
+        let hierarchy = TypeHierarchy::build(&widgets());
+
+        assert_eq!(hierarchy.edges, vec![
+            edge("StatelessWidget", "Widget", "extends"),
+            edge("StatefulWidget", "Widget", "extends"),
+            edge("StatefulWidget", "Comparable", "implements"),
+            edge("Counter", "StatefulWidget", "extends"),
+            edge("_CounterState", "State", "extends"),
+        ]);
+
+        let comparable = hierarchy.node("Comparable").unwrap();
+        assert!(comparable.external);
+        assert_eq!(comparable.file, None);
+        assert_eq!(comparable.descendants, 2);
+        assert!(!hierarchy.node("Widget").unwrap().external);
+    }
+
+    #[test]
+    fn test_ancestor_and_descendant_counts() {
+        let hierarchy = TypeHierarchy::build(&widgets());
+
+        let counts = |id: &str| {
+            let node = hierarchy.node(id).unwrap();
+            (node.ancestors, node.descendants)
+        };
+        assert_eq!(counts("Widget"), (0, 3));
+        assert_eq!(counts("StatefulWidget"), (2, 1));
+        // Widget, StatefulWidget, Comparable
+        assert_eq!(counts("Counter"), (3, 0));
+        assert_eq!(counts("State"), (0, 1));
+    }
+
+    #[test]
+    fn test_multiple_interfaces_and_metadata_lists() {
+        let mut service = class("UserService", 1, Some("BaseService"), &[]);
+        service.metadata.insert("interfaces".to_string(), "Repository<User, Long>, AutoCloseable".to_string());
+        let mut repository = class("Repository", 20, None, &[]);
+        repository.metadata.insert("type".to_string(), "interface".to_string());
+        repository.metadata.insert("interfaces".to_string(), "Iterable<T>".to_string());
+        let mut shape = class("Square", 40, Some("Shape"), &[]);
+        shape.metadata.insert("multiple_inheritance".to_string(), "Shape, Printable".to_string());
+
+        let hierarchy = TypeHierarchy::build(&analysis(vec![("Service.java", vec![service, repository, shape])]));
+
+        assert_eq!(hierarchy.edges, vec![
+            edge("UserService", "BaseService", "extends"),
+            edge("UserService", "Repository", "implements"),
+            edge("UserService", "AutoCloseable", "implements"),
+            edge("Repository", "Iterable", "extends"),
+            edge("Square", "Shape", "extends"),
+            edge("Square", "Printable", "extends"),
+        ]);
+        assert_eq!(hierarchy.node("UserService").unwrap().ancestors, 4);
+        let external: Vec<&str> = hierarchy.nodes.iter().filter(|n| n.external).map(|n| n.id.as_str()).collect();
+        assert_eq!(external, vec!["AutoCloseable", "BaseService", "Iterable", "Printable", "Shape"]);
+    }
+
+    #[test]
+    fn test_ambiguous_names_prefer_same_file() {
+        let hierarchy = TypeHierarchy::build(&analysis(vec![
+            ("a.dart", vec![class("Base", 1, None, &[]), class("Child", 5, Some("Base"), &[])]),
+            ("b.dart", vec![class("Base", 1, None, &[])]),
+        ]));
+
+        assert_eq!(hierarchy.edges, vec![edge("Child", "Base@a.dart:1", "extends")]);
+        assert_eq!(hierarchy.node("Base@b.dart:1").unwrap().descendants, 0);
+    }
+
+    #[test]
+    fn test_companion_objects_are_not_supertypes() {
+        let mut companion = class("Job", 1, None, &[]);
+        companion.metadata.insert("type".to_string(), "object".to_string());
+        let hierarchy = TypeHierarchy::build(&analysis(vec![("jobs.scala", vec![
+            companion,
+            class("Job", 5, None, &[]),
+            class("WordCount", 10, Some("Job"), &[]),
+        ])]));
+
+        assert_eq!(hierarchy.edges, vec![edge("WordCount", "Job@jobs.scala:5", "extends")]);
+    }
+
+    #[test]
+    fn test_cycles_terminate() {
+        let hierarchy = TypeHierarchy::build(&analysis(vec![("loop.py", vec![
+            class("A", 1, Some("B"), &[]),
+            class("B", 5, Some("A"), &[]),
+        ])]));
+
+        assert_eq!(hierarchy.node("A").unwrap().ancestors, 1);
+        assert_eq!(hierarchy.node("A").unwrap().descendants, 1);
+    }
+
+    #[test]
+    fn test_to_dot() {
+        let dot = TypeHierarchy::build(&widgets()).to_dot();
+
+        assert!(dot.starts_with("digraph hierarchy {"));
+        assert!(dot.contains("\"Counter\" -> \"StatefulWidget\" [arrowhead=empty];"));
+        assert!(dot.contains("\"StatefulWidget\" -> \"Comparable\" [style=dashed, arrowhead=empty];"));
+        assert!(dot.contains("\"State\" [label=\"State\", shape=box, style=dashed];"));
+    }
+
+    #[test]
+    fn test_base_name() {
+        assert_eq!(base_name("State<Counter>"), "State");
+        assert_eq!(base_name("java.util.List<T>"), "List");
+        assert_eq!(base_name("public Base"), "Base");
+        assert_eq!(base_name("std::vector<int>"), "vector");
+        assert_eq!(base_name("\\App\\Models\\Model"), "Model");
+    }
+}
@@ -10,11 +10,15 @@ use std::collections::{HashMap, HashSet};
 use std::path::{Path, PathBuf};
 use chrono::{DateTime, Utc};
 
-use crate::core::types::{AnalysisResult, DirectoryAnalysis, FunctionInfo, ClassInfo, Language};
+use crate::core::types::{AnalysisResult, DirectoryAnalysis, FunctionInfo, ClassInfo, Language, TypeAssertionKind};
 use crate::core::session::AnalysisSession;
+use crate::core::git::ChangedFiles;
+use crate::analyzers::go::packages::{ImportTable, PackageIndex};
+use crate::analyzers::python::imports::module_matches_path;
+use crate::analyzers::zig::imports::import_matches_path;
 
-/// Risk levels for impact assessment
-#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+/// Risk levels for impact assessment (ordered Low < Medium < High)
+#[derive(Debug, Clone, Copy, PartialEq, Eq, PartialOrd, Ord, Serialize, Deserialize)]
 pub enum RiskLevel {
     #[serde(rename = "low")]
     Low,
@@ -40,8 +44,21 @@ impl RiskLevel {
             RiskLevel::High => "High",
         }
     }
+    
+    /// Parse a CLI value ("low", "medium", "high")
+    pub fn parse(value: &str) -> Option<Self> {
+        match value.to_lowercase().as_str() {
+            "low" => Some(RiskLevel::Low),
+            "medium" => Some(RiskLevel::Medium),
+            "high" => Some(RiskLevel::High),
+            _ => None,
+        }
+    }
 }
 
+/// SARIF rule id for impact findings
+const SARIF_RULE_ID: &str = "nekocode/impact";
+
 /// Type of change detected
 #[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
 pub enum ChangeType {
@@ -93,6 +110,27 @@ pub struct ChangedSymbol {
     pub breaking_change: bool,
 }
 
+impl ChangedSymbol {
+    /// The definition references are looked up for
+    pub fn definition(&self) -> SymbolDefinition {
+        SymbolDefinition {
+            name: self.name.clone(),
+            symbol_type: self.symbol_type.clone(),
+            file_path: self.file_path.clone(),
+            line_number: self.line_number,
+        }
+    }
+}
+
+/// A symbol's declaration: what reference lookups match against
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct SymbolDefinition {
+    pub name: String,
+    pub symbol_type: String, // "function", "method" or "class"
+    pub file_path: PathBuf,
+    pub line_number: u32,
+}
+
 /// Reference to a symbol in the codebase
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct SymbolReference {
@@ -175,44 +213,51 @@ impl ImpactAnalyzer {
         }
         
         // Perform analysis based on git comparison mode
-        let (current_analysis, changed_files_for_detection) = if let Some(ref compare_ref) = self.config.compare_ref {
+        let (current_analysis, changed_files_for_detection, git_changes) = if let Some(ref compare_ref) = self.config.compare_ref {
             if self.config.verbose {
                 println!("📊 Comparing against git reference: {}", compare_ref);
             }
-            let git_changed_files = self.get_changed_files_from_git(path, compare_ref)?;
+            let git_changes = self.get_changed_files_from_git(path, compare_ref)?;
+            let git_changed_files: Vec<PathBuf> = git_changes.existing_files()
+                .into_iter()
+                .filter(|file| file.extension()
+                    .and_then(|ext| ext.to_str())
+                    .map_or(false, |ext| Language::from_extension(&format!(".{}", ext)) != Language::Unknown))
+                .collect();
             
             if git_changed_files.is_empty() {
                 if self.config.verbose {
                     println!("📄 No changed files found, analyzing all files");
                 }
-                (self.analyze_current_state(path).await?, Vec::new())
+                (self.analyze_current_state(path).await?, Vec::new(), Some(git_changes))
             } else {
                 if self.config.verbose {
                     println!("🔍 Git mode: Analyzing all files for references, detecting changes in {} files", git_changed_files.len());
                 }
                 // Analyze all files for complete reference graph, but track changed files
                 let analysis = self.analyze_current_state(path).await?;
-                (analysis, git_changed_files)
+                (analysis, git_changed_files, Some(git_changes))
             }
         } else {
             if self.config.verbose {
                 println!("🔍 Analyzing all files (no git comparison)");
             }
-            (self.analyze_current_state(path).await?, Vec::new())
+            (self.analyze_current_state(path).await?, Vec::new(), None)
         };
         
         // Detect changes (either in specific files from git, or simulated for all files)
-        let changed_symbols = if !changed_files_for_detection.is_empty() {
+        let changed_symbols = if let (false, Some(git_changes)) = (changed_files_for_detection.is_empty(), &git_changes) {
             // Git mode: detect actual deletions and changes using my improved implementation
-            self.detect_changed_symbols_in_files(&current_analysis, &changed_files_for_detection).await?
+            self.detect_changed_symbols_in_files(&current_analysis, git_changes).await?
         } else {
             self.detect_changed_symbols(&current_analysis)?
         };
         
         // Find references for changed symbols
+        let packages = PackageIndex::build(&current_analysis.files);
         let mut symbols_with_refs = Vec::new();
         for mut symbol in changed_symbols {
-            symbol.references = self.find_symbol_references(&symbol, &current_analysis)?;
+            symbol.references = self.find_symbol_references(&symbol, &current_analysis, &packages)?;
             symbol.risk_level = self.assess_risk_level(&symbol);
             symbols_with_refs.push(symbol);
         }
@@ -363,7 +408,10 @@ impl ImpactAnalyzer {
     }
     
     /// Detect changed symbols specifically in the provided files (git mode)
-    async fn detect_changed_symbols_in_files(&self, analysis: &DirectoryAnalysis, changed_files: &[PathBuf]) -> Result<Vec<ChangedSymbol>> {
+    ///
+    /// Files are matched by canonical path, and renamed files are compared with
+    /// the content of their old path at the reference.
+    async fn detect_changed_symbols_in_files(&self, analysis: &DirectoryAnalysis, changes: &ChangedFiles) -> Result<Vec<ChangedSymbol>> {
         let mut changed_symbols = Vec::new();
         
         // First pass: count references for each function to identify widely-used functions
@@ -374,13 +422,10 @@ impl ImpactAnalyzer {
             }
         }
         
-        // Create a set of changed file paths for quick lookup
-        let changed_file_set: HashSet<PathBuf> = changed_files.iter().cloned().collect();
-        
         if self.config.verbose {
             println!("🔍 Changed files from git:");
-            for path in &changed_file_set {
-                println!("  📄 Git: {}", path.display());
+            for change in &changes.changes {
+                println!("  📄 Git: {} ({:?})", change.path.display(), change.status);
             }
             println!("🔍 Analysis files found:");
             for file in &analysis.files {
@@ -391,20 +436,25 @@ impl ImpactAnalyzer {
         // Only look for changed symbols in the files that were actually modified
         for file in &analysis.files {
             // Skip files that weren't changed according to git
-            if !changed_file_set.contains(&file.file_info.path) {
+            let Some(change) = changes.get(&file.file_info.path) else {
                 if self.config.verbose {
                     println!("🔍 Skipping file (not in changed set): {}", file.file_info.path.display());
                 }
                 continue;
-            }
+            };
             
             if self.config.verbose {
                 println!("🔍 Comparing file: {}", file.file_info.path.display());
             }
             
             // Get the old version of this file from git for comparison
+            // New files have no old version: every symbol is an addition
             if let Some(ref compare_ref) = self.config.compare_ref {
-                match self.analyze_file_at_git_ref(&file.file_info.path, compare_ref).await {
+                let old_version = match change.path_at_ref() {
+                    Some(ref_path) => self.analyze_file_at_git_ref(&changes.root, ref_path, compare_ref).await,
+                    None => Ok(Vec::new()),
+                };
+                match old_version {
                     Ok(old_functions) => {
                         // Compare old vs new functions to detect changes
                         let current_functions: HashSet<String> = file.functions.iter()
@@ -481,6 +531,20 @@ impl ImpactAnalyzer {
                                             risk_level: RiskLevel::Low,
                                             breaking_change,
                                         });
+                                    } else if Self::body_changed(old_func, function) {
+                                        // Same signature, edited implementation: callers keep compiling
+                                        changed_symbols.push(ChangedSymbol {
+                                            name: function.name.clone(),
+                                            symbol_type: "function".to_string(),
+                                            file_path: file.file_info.path.clone(),
+                                            line_number: function.start_line,
+                                            change_type: ChangeType::FunctionModified,
+                                            signature_before: Some(old_sig),
+                                            signature_after: Some(new_sig),
+                                            references: Vec::new(),
+                                            risk_level: RiskLevel::Low,
+                                            breaking_change: false,
+                                        });
                                     }
                                 }
                             }
@@ -546,7 +610,7 @@ impl ImpactAnalyzer {
         }
         
         if self.config.verbose {
-            println!("🔍 Detected {} potentially changed symbols in {} files", changed_symbols.len(), changed_files.len());
+            println!("🔍 Detected {} potentially changed symbols in {} files", changed_symbols.len(), changes.changes.len());
             for symbol in &changed_symbols {
                 let usage = function_usage_count.get(&symbol.name).unwrap_or(&0);
                 println!("  📍 {} '{}' (usage count: {})", symbol.symbol_type, symbol.name, usage);
@@ -556,25 +620,132 @@ impl ImpactAnalyzer {
         Ok(changed_symbols)
     }
     
+    /// Whether a function's implementation changed between two versions
+    ///
+    /// Compares body hashes, so a function that only moved or was reformatted
+    /// is unchanged. Without hashes on both sides it counts as changed.
+    fn body_changed(old: &FunctionInfo, new: &FunctionInfo) -> bool {
+        old.body_hash.is_empty() || new.body_hash.is_empty() || old.body_hash != new.body_hash
+    }
+    
     /// Find references to a changed symbol
-    fn find_symbol_references(&self, symbol: &ChangedSymbol, analysis: &DirectoryAnalysis) 
+    fn find_symbol_references(&self, symbol: &ChangedSymbol, analysis: &DirectoryAnalysis, packages: &PackageIndex) 
         -> Result<Vec<SymbolReference>> {
+        Ok(self.references_to(&symbol.definition(), analysis, packages))
+    }
+    
+    /// Find references to a defined symbol across the analyzed set (`find-references`)
+    pub fn references_to(&self, symbol: &SymbolDefinition, analysis: &DirectoryAnalysis, packages: &PackageIndex) -> Vec<SymbolReference> {
         let mut references = Vec::new();
         
+        // Owner type of a changed method (Go receiver, Python class), to reject calls on other types
+        let symbol_owner = analysis.files.iter()
+            .filter(|f| f.file_info.path == symbol.file_path)
+            .flat_map(|f| f.functions.iter())
+            .find(|f| f.name == symbol.name && f.start_line == symbol.line_number)
+            .and_then(|f| f.metadata.get("receiver_type").or_else(|| f.metadata.get("class_name")));
+        // Package of a changed Go function: cross-package calls name it via an import
+        let symbol_is_go = analysis.files.iter()
+            .any(|f| f.file_info.path == symbol.file_path && f.language == Language::Go);
+        let symbol_is_python = analysis.files.iter()
+            .any(|f| f.file_info.path == symbol.file_path && f.language == Language::Python);
+        let symbol_is_zig = analysis.files.iter()
+            .any(|f| f.file_info.path == symbol.file_path && f.language == Language::Zig);
+        let symbol_package = match symbol_owner {
+            None if symbol_is_go => packages.package_of(&symbol.file_path),
+            _ => None,
+        };
+        // Go type whose method set the change touches: the changed type itself or the changed method's receiver
+        let asserted_type = match symbol_owner {
+            _ if !symbol_is_go => None,
+            Some(owner) => Some(owner.as_str()),
+            None if symbol.symbol_type == "class" => Some(symbol.name.as_str()),
+            None => None,
+        };
+        let asserted_package = asserted_type.and_then(|_| packages.package_of(&symbol.file_path));
+        
         for file in &analysis.files {
+            let go_scope = match (symbol_package, file.language == Language::Go) {
+                (Some(_), true) => Some((ImportTable::new(&file.imports, packages), packages.package_of(&file.file_info.path))),
+                _ => None,
+            };
+            
             // Look for function calls that match our symbol
             for call in &file.function_calls {
+                // Package-qualified (`proc.NewDataProcessor()`), dot-imported or same-package calls
+                if let Some((imports, caller_package)) = &go_scope {
+                    match packages.resolve_call(imports, *caller_package, call) {
+                        Some(package) => {
+                            if Some(package.as_str()) == symbol_package && call.function_name == symbol.name {
+                                references.push(SymbolReference {
+                                    file_path: file.file_info.path.clone(),
+                                    line_number: call.line_number,
+                                    context: format!("{}()", call.full_name()),
+                                    usage_type: "call".to_string(),
+                                });
+                            }
+                            continue;
+                        }
+                        // Unqualified, yet not defined by any package in scope
+                        None if !call.is_method_call => continue,
+                        None => {}
+                    }
+                }
+                
+                // Python / Zig calls through an import name their module; another module's namesake is not a reference
+                if let Some(module) = &call.module {
+                    if symbol_is_python && !module_matches_path(module, &symbol.file_path) {
+                        continue;
+                    }
+                    if symbol_is_zig && !import_matches_path(module, &file.file_info.path, &symbol.file_path) {
+                        continue;
+                    }
+                }
+                
+                let other_receiver = match (symbol_owner, &call.receiver_type) {
+                    (Some(owner), Some(receiver_type)) => owner != receiver_type,
+                    _ => false,
+                };
+                if other_receiver {
+                    continue;
+                }
+                
                 if call.function_name == symbol.name || 
                    call.full_name().contains(&symbol.name) {
                     references.push(SymbolReference {
                         file_path: file.file_info.path.clone(),
                         line_number: call.line_number,
-                        context: format!("{}()", call.full_name()),
+                        context: format!("{}()", call.qualified_name()),
                         usage_type: "call".to_string(),
                     });
                 }
             }
             
+            // Type assertions and type switch arms fail at run time once the type's method set changes
+            if let (Some(type_name), Some(type_package), Language::Go) = (asserted_type, asserted_package, file.language) {
+                let imports = ImportTable::new(&file.imports, packages);
+                let file_package = packages.package_of(&file.file_info.path);
+                let names_package = |qualifier: Option<&str>| match qualifier {
+                    Some(qualifier) => imports.import_path(qualifier).and_then(|path| packages.lookup(path)) == Some(type_package),
+                    None => file_package == Some(type_package) || imports.dot_imports().iter()
+                        .any(|path| packages.lookup(path) == Some(type_package)),
+                };
+                
+                let assertions = file.functions.iter().flat_map(|f| f.type_assertions.iter());
+                for assertion in assertions.filter(|a| a.type_name == type_name && names_package(a.package.as_deref())) {
+                    let context = match assertion.kind {
+                        TypeAssertionKind::Assertion => format!(".({})", assertion.type_text()),
+                        TypeAssertionKind::TypeSwitch => format!("case {}:", assertion.type_text()),
+                    };
+                    references.push(SymbolReference {
+                        file_path: file.file_info.path.clone(),
+                        line_number: assertion.line_number,
+                        context,
+                        usage_type: "type_assertion".to_string(),
+                    });
+                }
+            }
+            
             // Look for imports that reference our symbol
             for import in &file.imports {
                 if import.imported_names.contains(&symbol.name) {
@@ -642,7 +813,7 @@ impl ImpactAnalyzer {
             }
         }
         
-        Ok(references)
+        references
     }
     
     /// Assess risk level for a changed symbol
@@ -721,10 +892,14 @@ impl ImpactAnalyzer {
         }
     }
     
-    /// Format function signature for display
+    /// Format function signature for display (with result types when known)
     fn format_function_signature(&self, function: &FunctionInfo) -> String {
         let params = function.parameters.join(", ");
-        format!("{}({})", function.name, params)
+        match function.returns.len() {
+            0 => format!("{}({})", function.name, params),
+            1 => format!("{}({}) {}", function.name, params, function.returns[0]),
+            _ => format!("{}({}) ({})", function.name, params, function.returns.join(", ")),
+        }
     }
     
     /// Analyze functions at a specific git reference
@@ -891,120 +1066,46 @@ impl ImpactAnalyzer {
         Ok(deleted_symbols)
     }
 
-    /// Get changed files from git comparison
-    fn get_changed_files_from_git(&self, repo_path: &Path, compare_ref: &str) -> Result<Vec<PathBuf>> {
-        use std::process::Command;
-        
+    /// Get changed files from git comparison (renames detected by git)
+    fn get_changed_files_from_git(&self, repo_path: &Path, compare_ref: &str) -> Result<ChangedFiles> {
         if self.config.verbose {
             println!("🔍 Running git diff to find changed files...");
         }
         
-        // Get the git root directory to run the command from
-        let git_root = {
-            // Start from current working directory and walk up to find .git
-            let start_path = if repo_path.is_absolute() {
-                repo_path.to_path_buf()
-            } else {
-                std::env::current_dir()?.join(repo_path)
-            };
-            
-            let mut current = start_path.as_path();
-            loop {
-                if current.join(".git").exists() {
-                    break current.to_path_buf();
-                }
-                if let Some(parent) = current.parent() {
-                    current = parent;
-                } else {
-                    // Fallback to current working directory
-                    break std::env::current_dir().unwrap_or_else(|_| PathBuf::from("."));
-                }
-            }
-        };
-        
-        if self.config.verbose {
-            println!("🔍 Git root: {}, Target path: {}", git_root.display(), repo_path.display());
-        }
-        
-        let output = Command::new("git")
-            .arg("diff")
-            .arg("--name-only")
-            .arg(compare_ref)
-            .current_dir(&git_root)
-            .output()
-            .map_err(|e| anyhow::anyhow!("Failed to run git command: {}. Make sure you're in a git repository.", e))?;
-            
-        if !output.status.success() {
-            let error = String::from_utf8_lossy(&output.stderr);
-            anyhow::bail!("Git command failed: {}", error);
-        }
+        let changes = ChangedFiles::since(repo_path, compare_ref)?;
         
-        let files_output = String::from_utf8_lossy(&output.stdout);
-        let changed_files: Vec<PathBuf> = files_output
-            .lines()
-            .filter(|line| !line.trim().is_empty())
-            .map(|line| {
-                let git_relative_path = PathBuf::from(line.trim());
-                // Convert git relative path to our analysis path format
-                // If the git root is different from repo_path, we need to adjust
-                if git_root == *repo_path {
-                    git_relative_path
-                } else {
-                    // Git path is relative to git_root, we need it relative to repo_path
-                    if let Ok(relative_to_git_root) = repo_path.strip_prefix(&git_root) {
-                        if let Ok(stripped) = git_relative_path.strip_prefix(relative_to_git_root) {
-                            stripped.to_path_buf()
-                        } else {
-                            git_relative_path
-                        }
-                    } else {
-                        git_relative_path
-                    }
-                }
-            })
-            .filter(|path| {
-                // Only include supported file types for analysis
-                if let Some(ext) = path.extension() {
-                    matches!(ext.to_str(), Some("js") | Some("ts") | Some("jsx") | Some("tsx") | 
-                                          Some("py") | Some("cpp") | Some("hpp") | Some("c") | 
-                                          Some("h") | Some("cs") | Some("go") | Some("rs"))
-                } else {
-                    false
-                }
-            })
-            .collect();
-            
         if self.config.verbose {
-            println!("📝 Found {} changed files:", changed_files.len());
-            for file in &changed_files {
-                println!("  • {}", file.display());
+            println!("🔍 Git root: {}, Target path: {}", changes.root.display(), repo_path.display());
+            println!("📝 Found {} changed files since {}:", changes.changes.len(), changes.git_ref);
+            for change in &changes.changes {
+                println!("  • {} ({:?})", change.path.display(), change.status);
+            }
+            for (old_path, new_path) in changes.renames() {
+                println!("  🔀 {} -> {}", old_path.display(), new_path.display());
             }
         }
         
-        Ok(changed_files)
+        Ok(changes)
     }
     
     /// Analyze a file at a specific git reference (commit, branch, tag)
-    async fn analyze_file_at_git_ref(&self, file_path: &Path, git_ref: &str) -> Result<Vec<FunctionInfo>> {
+    ///
+    /// `relative_path` is relative to the repository root `git_root`; for renamed
+    /// files it is the old path.
+    async fn analyze_file_at_git_ref(&self, git_root: &Path, relative_path: &Path, git_ref: &str) -> Result<Vec<FunctionInfo>> {
         use std::process::Command;
         use crate::core::session::AnalysisSession;
         
-        // Get the relative path from the file_path
-        let relative_path = if let Some(parent) = file_path.parent() {
-            file_path.strip_prefix(parent).unwrap_or(file_path)
-        } else {
-            file_path
-        };
-        
         if self.config.verbose {
             println!("📄 Getting file content: {}:{}", git_ref, relative_path.display());
         }
         
-        // Get file content from git
+        // Get file content from git (git wants `/` separators in `<ref>:<path>`)
+        let object = format!("{}:{}", git_ref, relative_path.to_string_lossy().replace('\\', "/"));
         let output = Command::new("git")
             .arg("show")
-            .arg(format!("{}:{}", git_ref, relative_path.display()))
-            .current_dir(file_path.parent().unwrap_or_else(|| Path::new(".")))
+            .arg(&object)
+            .current_dir(git_root)
             .output()
             .map_err(|e| anyhow::anyhow!("Failed to run git show command: {}", e))?;
             
@@ -1020,7 +1121,9 @@ impl ImpactAnalyzer {
         }
         
         // Create a temporary file to analyze the old content
-        let temp_file = std::env::temp_dir().join(format!("nekocode_git_{}.js", uuid::Uuid::new_v4()));
+        // Keep the extension so the old content is parsed as the same language
+        let extension = relative_path.extension().and_then(|ext| ext.to_str()).unwrap_or("js");
+        let temp_file = std::env::temp_dir().join(format!("nekocode_git_{}.{}", uuid::Uuid::new_v4(), extension));
         std::fs::write(&temp_file, file_content.as_bytes())
             .context("Failed to write temporary file")?;
             
@@ -1156,6 +1259,108 @@ impl OutputFormatter {
             .context("Failed to serialize impact analysis result to JSON")
     }
     
+    /// Format as SARIF 2.1.0 (GitHub code scanning)
+    ///
+    /// Each changed symbol with at least one reference becomes a `nekocode/impact`
+    /// result. Symbols at or above `error_threshold` are errors, other medium risk
+    /// symbols are warnings and low risk symbols are notes.
+    pub fn format_sarif(result: &ImpactAnalysisResult, error_threshold: RiskLevel) -> Result<String> {
+        let results: Vec<serde_json::Value> = result.changed_symbols.iter()
+            .filter(|symbol| !symbol.references.is_empty())
+            .map(|symbol| {
+                let level = if symbol.risk_level >= error_threshold {
+                    "error"
+                } else if symbol.risk_level == RiskLevel::Medium {
+                    "warning"
+                } else {
+                    "note"
+                };
+                
+                let related_locations: Vec<serde_json::Value> = symbol.references.iter()
+                    .enumerate()
+                    .map(|(id, reference)| {
+                        let mut location = Self::sarif_location(&result.analysis_path, &reference.file_path, reference.line_number);
+                        location["id"] = serde_json::json!(id);
+                        // `--redact` leaves no context
+                        let text = if reference.context.is_empty() {
+                            reference.usage_type.clone()
+                        } else {
+                            format!("{}: {}", reference.usage_type, reference.context)
+                        };
+                        location["message"] = serde_json::json!({ "text": text });
+                        location
+                    })
+                    .collect();
+                
+                serde_json::json!({
+                    "ruleId": SARIF_RULE_ID,
+                    "ruleIndex": 0,
+                    "level": level,
+                    "message": {
+                        "text": format!(
+                            "{}: `{}` is referenced in {} location(s) (risk: {})",
+                            symbol.change_type.as_str(),
+                            symbol.name,
+                            symbol.references.len(),
+                            symbol.risk_level.as_str()
+                        )
+                    },
+                    "locations": [Self::sarif_location(&result.analysis_path, &symbol.file_path, symbol.line_number)],
+                    "relatedLocations": related_locations,
+                    "properties": {
+                        "symbolType": symbol.symbol_type,
+                        "riskLevel": symbol.risk_level,
+                        "breakingChange": symbol.breaking_change,
+                        "changeType": symbol.change_type
+                    }
+                })
+            })
+            .collect();
+        
+        let sarif = serde_json::json!({
+            "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+            "version": "2.1.0",
+            "runs": [{
+                "tool": {
+                    "driver": {
+                        "name": "nekocode-rust",
+                        "version": env!("CARGO_PKG_VERSION"),
+                        "rules": [{
+                            "id": SARIF_RULE_ID,
+                            "name": "ChangeImpact",
+                            "shortDescription": {
+                                "text": "Changed symbol is referenced elsewhere in the codebase"
+                            },
+                            "defaultConfiguration": {
+                                "level": "warning"
+                            }
+                        }]
+                    }
+                },
+                "results": results
+            }]
+        });
+        
+        serde_json::to_string_pretty(&sarif)
+            .context("Failed to serialize impact analysis result to SARIF")
+    }
+    
+    /// Helper: SARIF physicalLocation with a repository-relative URI
+    fn sarif_location(base: &Path, file: &Path, line: u32) -> serde_json::Value {
+        let relative = file.strip_prefix(base).unwrap_or(file);
+        let uri = relative.to_string_lossy().replace('\\', "/");
+        
+        let mut physical_location = serde_json::json!({
+            "artifactLocation": { "uri": uri }
+        });
+        // SARIF lines are 1-based; omit the region when the line is unknown
+        if line > 0 {
+            physical_location["region"] = serde_json::json!({ "startLine": line });
+        }
+        
+        serde_json::json!({ "physicalLocation": physical_location })
+    }
+    
     /// Format as GitHub comment
     pub fn format_github_comment(result: &ImpactAnalysisResult) -> String {
         let mut output = Vec::new();
@@ -1207,11 +1412,12 @@ impl OutputFormatter {
                         output.push("  **Broken References:**".to_string());
                         for (i, reference) in func.references.iter().enumerate() {
                             if i < 5 { // Limit to first 5 references to avoid spam
-                                output.push(format!("  - `{}:{}` - {}",
-                                    reference.file_path.display(),
-                                    reference.line_number,
-                                    reference.context
-                                ));
+                                let location = format!("  - `{}:{}`", reference.file_path.display(), reference.line_number);
+                                if reference.context.is_empty() {
+                                    output.push(location);
+                                } else {
+                                    output.push(format!("{} - {}", location, reference.context));
+                                }
                             }
                         }
                         if func.references.len() > 5 {
@@ -1314,7 +1520,7 @@ impl OutputFormatter {
 #[cfg(test)]
 mod tests {
     use super::*;
-    use crate::core::types::{FileInfo, Language, AnalysisResult, FunctionInfo, ClassInfo, ImportInfo, ImportType, ExportInfo, ExportType, FunctionCall, Statistics};
+    use crate::core::types::{FileInfo, Language, AnalysisResult, FunctionInfo, ClassInfo, ImportInfo, ImportType, ExportInfo, ExportType, FunctionCall, Statistics, TypeAssertion};
     use std::collections::HashMap;
     use chrono::Utc;
     
@@ -1425,6 +1631,22 @@ mod tests {
         assert_eq!(symbol.breaking_change, true);
     }
     
+    #[test]
+    fn test_body_changed_uses_body_hash() {
+        let mut old = FunctionInfo::new("process".to_string());
+        old.start_line = 10;
+        old.body_hash = "a1".to_string();
+        let mut moved = old.clone();
+        moved.start_line = 42;
+        let mut edited = old.clone();
+        edited.body_hash = "b2".to_string();
+        
+        assert!(!ImpactAnalyzer::body_changed(&old, &moved));
+        assert!(ImpactAnalyzer::body_changed(&old, &edited));
+        // Unknown hashes are treated as changed
+        assert!(ImpactAnalyzer::body_changed(&FunctionInfo::new("process".to_string()), &old));
+    }
+    
     #[test]
     fn test_detect_changed_symbols() {
         let analysis = create_test_analysis();
@@ -1445,6 +1667,223 @@ mod tests {
         assert!(has_updated_manager, "Should detect UpdatedUserManager as changed");
     }
     
+    #[test]
+    fn test_go_cross_package_references() {
+        let go_file = |path: &str, package: &str| {
+            let mut file = AnalysisResult::new(FileInfo::new(PathBuf::from(path)), Language::Go);
+            file.metadata.insert("package".to_string(), package.to_string());
+            file
+        };
+        let qualified = |object: &str, name: &str, line: u32| {
+            let mut call = FunctionCall::new(name.to_string(), line);
+            call.object_name = Some(object.to_string());
+            call.is_method_call = true;
+            call
+        };
+        let import = |path: &str, alias: Option<&str>| {
+            let mut import = ImportInfo::new(ImportType::GoImport, path.to_string());
+            import.alias = alias.map(|a| a.to_string());
+            import
+        };
+        
+        let mut proc = go_file("/nonexistent/app/proc/processor.go", "proc");
+        let mut constructor = FunctionInfo::new("NewDataProcessor".to_string());
+        constructor.start_line = 5;
+        proc.functions.push(constructor.clone());
+        let mut other = go_file("/nonexistent/app/other/other.go", "other");
+        other.functions.push(constructor);
+        
+        let mut main = go_file("/nonexistent/app/cmd/main.go", "main");
+        main.imports = vec![import("example.com/app/proc", None), import("example.com/app/other", None)];
+        main.function_calls = vec![qualified("proc", "NewDataProcessor", 10), qualified("other", "NewDataProcessor", 11)];
+        let mut aliased = go_file("/nonexistent/app/cmd/aliased.go", "main");
+        aliased.imports = vec![import("example.com/app/proc", Some("p"))];
+        aliased.function_calls = vec![qualified("p", "NewDataProcessor", 7)];
+        let mut dotted = go_file("/nonexistent/app/tools/tools.go", "tools");
+        dotted.imports = vec![import("example.com/app/proc", Some("."))];
+        dotted.function_calls = vec![FunctionCall::new("NewDataProcessor".to_string(), 9)];
+        
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/nonexistent/app"));
+        analysis.files = vec![proc, other, main, aliased, dotted];
+        let symbol = ChangedSymbol {
+            name: "NewDataProcessor".to_string(),
+            symbol_type: "function".to_string(),
+            file_path: PathBuf::from("/nonexistent/app/proc/processor.go"),
+            line_number: 5,
+            change_type: ChangeType::FunctionModified,
+            signature_before: None,
+            signature_after: None,
+            references: Vec::new(),
+            risk_level: RiskLevel::Low,
+            breaking_change: false,
+        };
+        
+        let analyzer = ImpactAnalyzer::new(ImpactConfig::default());
+        let packages = PackageIndex::build(&analysis.files);
+        let references = analyzer.find_symbol_references(&symbol, &analysis, &packages).unwrap();
+        
+        // other.NewDataProcessor() is a different function
+        let calls: Vec<(&str, u32)> = references.iter()
+            .filter(|r| r.usage_type == "call")
+            .map(|r| (r.file_path.file_name().unwrap().to_str().unwrap(), r.line_number))
+            .collect();
+        assert_eq!(calls, vec![("main.go", 10), ("aliased.go", 7), ("tools.go", 9)]);
+    }
+    
+    #[test]
+    fn test_go_type_assertion_references() {
+        let go_file = |path: &str, package: &str| {
+            let mut file = AnalysisResult::new(FileInfo::new(PathBuf::from(path)), Language::Go);
+            file.metadata.insert("package".to_string(), package.to_string());
+            file
+        };
+        let assertion = |package: Option<&str>, name: &str, kind: TypeAssertionKind, line: u32| TypeAssertion {
+            type_name: name.to_string(),
+            package: package.map(|p| p.to_string()),
+            pointer: true,
+            kind,
+            line_number: line,
+        };
+        
+        let mut server = go_file("/nonexistent/app/srv/server.go", "srv");
+        let mut close = FunctionInfo::new("Close".to_string());
+        close.start_line = 12;
+        close.metadata.insert("receiver_type".to_string(), "Server".to_string());
+        let mut local = FunctionInfo::new("shutdown".to_string());
+        local.type_assertions = vec![assertion(None, "Server", TypeAssertionKind::Assertion, 20)];
+        server.functions = vec![close, local];
+        
+        let mut main = go_file("/nonexistent/app/cmd/main.go", "main");
+        main.imports = vec![ImportInfo::new(ImportType::GoImport, "example.com/app/srv".to_string())];
+        let mut handle = FunctionInfo::new("handle".to_string());
+        handle.type_assertions = vec![
+            assertion(Some("srv"), "Server", TypeAssertionKind::TypeSwitch, 8),
+            // main's own Server, not srv.Server
+            assertion(None, "Server", TypeAssertionKind::Assertion, 9),
+            assertion(Some("srv"), "Client", TypeAssertionKind::Assertion, 10),
+        ];
+        main.functions = vec![handle];
+        
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/nonexistent/app"));
+        analysis.files = vec![server, main];
+        let symbol = SymbolDefinition {
+            name: "Close".to_string(),
+            symbol_type: "method".to_string(),
+            file_path: PathBuf::from("/nonexistent/app/srv/server.go"),
+            line_number: 12,
+        };
+        
+        let analyzer = ImpactAnalyzer::new(ImpactConfig::default());
+        let packages = PackageIndex::build(&analysis.files);
+        let references = analyzer.references_to(&symbol, &analysis, &packages);
+        
+        let sites: Vec<(&str, u32, &str)> = references.iter()
+            .filter(|r| r.usage_type == "type_assertion")
+            .map(|r| (r.file_path.file_name().unwrap().to_str().unwrap(), r.line_number, r.context.as_str()))
+            .collect();
+        assert_eq!(sites, vec![("server.go", 20, ".(*Server)"), ("main.go", 8, "case *srv.Server:")]);
+    }
+    
+    #[test]
+    fn test_python_module_references() {
+        let python_file = |path: &str| AnalysisResult::new(FileInfo::new(PathBuf::from(path)), Language::Python);
+        let imported = |name: &str, module: &str, line: u32| {
+            let mut call = FunctionCall::new(name.to_string(), line);
+            call.module = Some(module.to_string());
+            call
+        };
+        
+        let mut utils = python_file("/nonexistent/app/utils.py");
+        let mut slugify = FunctionInfo::new("slugify".to_string());
+        slugify.start_line = 3;
+        utils.functions.push(slugify);
+        
+        let mut views = python_file("/nonexistent/app/views.py");
+        views.function_calls = vec![imported("slugify", "app.utils", 8), imported("slugify", "vendor.text", 9)];
+        
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/nonexistent/app"));
+        analysis.files = vec![utils, views];
+        let symbol = ChangedSymbol {
+            name: "slugify".to_string(),
+            symbol_type: "function".to_string(),
+            file_path: PathBuf::from("/nonexistent/app/utils.py"),
+            line_number: 3,
+            change_type: ChangeType::FunctionModified,
+            signature_before: None,
+            signature_after: None,
+            references: Vec::new(),
+            risk_level: RiskLevel::Low,
+            breaking_change: false,
+        };
+        
+        let analyzer = ImpactAnalyzer::new(ImpactConfig::default());
+        let packages = PackageIndex::build(&analysis.files);
+        let references = analyzer.find_symbol_references(&symbol, &analysis, &packages).unwrap();
+        
+        // vendor.text.slugify is a namesake from another module
+        let calls: Vec<u32> = references.iter().filter(|r| r.usage_type == "call").map(|r| r.line_number).collect();
+        assert_eq!(calls, vec![8]);
+    }
+
+    #[test]
+    fn test_zig_import_references() {
+        let zig_file = |path: &str| AnalysisResult::new(FileInfo::new(PathBuf::from(path)), Language::Zig);
+        let imported = |name: &str, module: &str, line: u32| {
+            let mut call = FunctionCall::new(name.to_string(), line);
+            call.module = Some(module.to_string());
+            call
+        };
+
+        let mut geo = zig_file("/nonexistent/src/geo.zig");
+        let mut area = FunctionInfo::new("area".to_string());
+        area.start_line = 4;
+        geo.functions.push(area);
+
+        let mut main = zig_file("/nonexistent/src/main.zig");
+        main.function_calls = vec![imported("area", "geo.zig", 6), imported("area", "legacy/geo.zig", 7)];
+
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/nonexistent/src"));
+        analysis.files = vec![geo, main];
+        let symbol = ChangedSymbol {
+            name: "area".to_string(),
+            symbol_type: "function".to_string(),
+            file_path: PathBuf::from("/nonexistent/src/geo.zig"),
+            line_number: 4,
+            change_type: ChangeType::FunctionModified,
+            signature_before: None,
+            signature_after: None,
+            references: Vec::new(),
+            risk_level: RiskLevel::Low,
+            breaking_change: false,
+        };
+
+        let analyzer = ImpactAnalyzer::new(ImpactConfig::default());
+        let packages = PackageIndex::build(&analysis.files);
+        let references = analyzer.find_symbol_references(&symbol, &analysis, &packages).unwrap();
+
+        // legacy/geo.zig is another file with the same name
+        let calls: Vec<u32> = references.iter().filter(|r| r.usage_type == "call").map(|r| r.line_number).collect();
+        assert_eq!(calls, vec![6]);
+    }
+
+    #[test]
+    fn test_function_signature_includes_types() {
+        let analyzer = ImpactAnalyzer::new(ImpactConfig::default());
+        
+        let mut before = FunctionInfo::new("getUserById".to_string());
+        before.parameters = vec!["id int".to_string()];
+        before.returns = vec!["*User".to_string(), "error".to_string()];
+        assert_eq!(analyzer.format_function_signature(&before), "getUserById(id int) (*User, error)");
+        
+        // An added parameter is a different signature
+        let mut after = before.clone();
+        after.parameters.push("ctx context.Context".to_string());
+        assert_ne!(analyzer.format_function_signature(&before), analyzer.format_function_signature(&after));
+        
+        after.returns = vec!["*User".to_string()];
+        assert_eq!(analyzer.format_function_signature(&after), "getUserById(id int, ctx context.Context) *User");
+    }
+    
     #[test]
     fn test_risk_assessment() {
         let config = ImpactConfig::default();
@@ -1614,4 +2053,76 @@ mod tests {
         assert!(output.contains("## 🔄 Circular Dependencies"));
         assert!(output.contains("## 📈 Complexity Changes"));
     }
-}
\ No newline at end of file
+    
+    #[test]
+    fn test_output_formatter_sarif() {
+        let symbol = |name: &str, risk_level: RiskLevel, references: Vec<SymbolReference>| ChangedSymbol {
+            name: name.to_string(),
+            symbol_type: "function".to_string(),
+            file_path: PathBuf::from("/tmp/test/file.js"),
+            line_number: 15,
+            change_type: ChangeType::FunctionModified,
+            signature_before: None,
+            signature_after: None,
+            references,
+            risk_level,
+            breaking_change: false,
+        };
+        let reference = SymbolReference {
+            file_path: PathBuf::from("/tmp/test/other.js"),
+            line_number: 20,
+            context: "addUser()".to_string(),
+            usage_type: "call".to_string(),
+        };
+        
+        let result = ImpactAnalysisResult {
+            analysis_path: PathBuf::from("/tmp/test"),
+            modified_files: vec![PathBuf::from("/tmp/test/file.js")],
+            changed_symbols: vec![
+                symbol("addUser", RiskLevel::High, vec![reference.clone()]),
+                symbol("updateUser", RiskLevel::Medium, vec![reference.clone()]),
+                symbol("unusedHelper", RiskLevel::Low, vec![]),
+            ],
+            affected_files: vec![],
+            circular_dependencies: vec![],
+            overall_risk: RiskLevel::High,
+            breaking_changes_count: 0,
+            references_count: 2,
+            complexity_change: ComplexityChange {
+                before_avg: 1.0,
+                after_avg: 1.0,
+                change_delta: 0.0,
+                complexity_increased: false,
+            },
+            analysis_time_ms: 10,
+            generated_at: Utc::now(),
+        };
+        
+        let sarif: serde_json::Value = serde_json::from_str(
+            &OutputFormatter::format_sarif(&result, RiskLevel::High).unwrap()
+        ).unwrap();
+        assert_eq!(sarif["version"], "2.1.0");
+        
+        // Symbols without references are omitted
+        let results = sarif["runs"][0]["results"].as_array().unwrap();
+        assert_eq!(results.len(), 2);
+        
+        assert_eq!(results[0]["ruleId"], "nekocode/impact");
+        assert_eq!(results[0]["level"], "error");
+        assert_eq!(results[1]["level"], "warning");
+        
+        let location = &results[0]["locations"][0]["physicalLocation"];
+        assert_eq!(location["artifactLocation"]["uri"], "file.js");
+        assert_eq!(location["region"]["startLine"], 15);
+        
+        let related = &results[0]["relatedLocations"][0]["physicalLocation"];
+        assert_eq!(related["artifactLocation"]["uri"], "other.js");
+        assert_eq!(related["region"]["startLine"], 20);
+        
+        // A lower threshold promotes medium risk to errors
+        let sarif: serde_json::Value = serde_json::from_str(
+            &OutputFormatter::format_sarif(&result, RiskLevel::Medium).unwrap()
+        ).unwrap();
+        assert_eq!(sarif["runs"][0]["results"][1]["level"], "error");
+    }
+}
@@ -0,0 +1,459 @@
+//! Module import graph and import cycles (`import-cycles`)
+//!
+//! Nodes are what each language imports as a unit: Go packages (resolved
+//! through `go.mod` like package-qualified calls), Python modules
+//! (`app/utils.py` is `app.utils`, a package is its `__init__.py`), and
+//! single files for JavaScript / TypeScript (relative specifiers, with the
+//! usual extension and `index` fallbacks) and Zig (`@import("x.zig")`).
+//! Only imports that resolve to another analyzed module are edges; the
+//! standard library and third-party packages are outside the graph. Rust
+//! `use` declarations are left out, since modules of one crate may use each
+//! other freely.
+//!
+//! A strongly connected component of two or more modules is an import cycle:
+//! Go rejects it at build time, Python sees partially initialized modules and
+//! ES modules uninitialized bindings.
+
+use serde::{Deserialize, Serialize};
+use std::collections::{BTreeMap, HashMap, HashSet};
+use std::path::{Path, PathBuf};
+
+use crate::analyzers::go::packages::PackageIndex;
+use crate::analyzers::zig::imports::normalize;
+use crate::core::callgraph::strongly_connected_components;
+use crate::core::types::{AnalysisResult, DirectoryAnalysis, ImportInfo, ImportType, Language};
+
+/// Extensions tried for an extension-less JavaScript / TypeScript specifier
+const SCRIPT_EXTENSIONS: &[&str] = &["ts", "tsx", "js", "jsx", "mjs", "cjs"];
+
+/// A package, module or file others import
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ImportModule {
+    /// Import path (Go), dotted name (Python) or root-relative file
+    pub name: String,
+    /// "go", "python", "javascript" (TypeScript included) or "zig"
+    pub language: String,
+    /// Files making up the module, relative to the analyzed root
+    pub files: Vec<PathBuf>,
+}
+
+/// One import statement behind an edge
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ImportSite {
+    pub file: PathBuf,
+    pub line: u32,
+    /// The imported path as written
+    pub import: String,
+}
+
+/// Module `from` imports module `to`
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ImportEdge {
+    pub language: String,
+    pub from: String,
+    pub to: String,
+    pub sites: Vec<ImportSite>,
+}
+
+/// Directed import graph over the analyzed modules
+#[derive(Debug, Clone, Default, Serialize, Deserialize)]
+pub struct ImportGraph {
+    pub modules: Vec<ImportModule>,
+    pub edges: Vec<ImportEdge>,
+}
+
+/// Modules that (transitively) import each other, and the imports doing so
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ImportCycle {
+    pub language: String,
+    /// Members in discovery order
+    pub modules: Vec<String>,
+    /// Imports between members
+    pub edges: Vec<ImportEdge>,
+}
+
+/// Graph under construction
+#[derive(Default)]
+struct GraphBuilder {
+    modules: Vec<ImportModule>,
+    index: HashMap<(&'static str, String), usize>,
+    edges: BTreeMap<(usize, usize), Vec<ImportSite>>,
+}
+
+impl ImportGraph {
+    pub fn build(analysis: &DirectoryAnalysis) -> Self {
+        let root = analysis.directory_path.as_path();
+        let mut builder = GraphBuilder::default();
+        builder.add_go(&analysis.files, root);
+        builder.add_python(&analysis.files, root);
+        builder.add_files(&analysis.files, root, "javascript", |l| matches!(l, Language::JavaScript | Language::TypeScript), resolve_script);
+        builder.add_files(&analysis.files, root, "zig", |l| l == Language::Zig, resolve_zig);
+        builder.finish()
+    }
+
+    /// Import cycles (Tarjan's SCC), ordered by their first member
+    ///
+    /// A module importing itself (a Go external test package) is not a cycle.
+    pub fn cycles(&self) -> Vec<ImportCycle> {
+        let mut index_of: HashMap<&str, HashMap<&str, usize>> = HashMap::new();
+        for (index, module) in self.modules.iter().enumerate() {
+            index_of.entry(module.language.as_str()).or_default().insert(module.name.as_str(), index);
+        }
+        let endpoints = |edge: &ImportEdge| {
+            let modules = index_of.get(edge.language.as_str())?;
+            Some((*modules.get(edge.from.as_str())?, *modules.get(edge.to.as_str())?))
+        };
+
+        let mut successors: Vec<Vec<usize>> = vec![Vec::new(); self.modules.len()];
+        for (from, to) in self.edges.iter().filter_map(endpoints) {
+            successors[from].push(to);
+        }
+
+        let mut components: Vec<Vec<usize>> = strongly_connected_components(&successors)
+            .into_iter()
+            .filter(|component| component.len() > 1)
+            .map(|mut component| {
+                component.sort_unstable();
+                component
+            })
+            .collect();
+        components.sort();
+
+        components.into_iter()
+            .map(|component| {
+                let members: HashSet<usize> = component.iter().copied().collect();
+                ImportCycle {
+                    language: self.modules[component[0]].language.clone(),
+                    modules: component.iter().map(|&index| self.modules[index].name.clone()).collect(),
+                    edges: self.edges.iter()
+                        .filter(|edge| endpoints(edge).is_some_and(|(from, to)| members.contains(&from) && members.contains(&to)))
+                        .cloned()
+                        .collect(),
+                }
+            })
+            .collect()
+    }
+}
+
+impl GraphBuilder {
+    /// Go: one node per package directory, edges through resolved import paths
+    fn add_go(&mut self, files: &[AnalysisResult], root: &Path) {
+        let packages = PackageIndex::build(files);
+        let name_of = |key: &str| match packages.get(key) {
+            Some(package) if package.import_path.is_none() => display_dir(&package.dir, root),
+            _ => key.to_string(),
+        };
+
+        for file in files.iter().filter(|f| f.language == Language::Go) {
+            let Some(key) = packages.package_of(&file.file_info.path) else { continue };
+            let from = self.module("go", name_of(key), file, root);
+            for import in &file.imports {
+                if let Some(target) = packages.lookup(&import.module_path) {
+                    let to = self.node("go", name_of(target));
+                    self.link(from, to, file, import, root);
+                }
+            }
+        }
+    }
+
+    /// Python: one node per module, `from pkg import sub` prefers the submodule `pkg.sub`
+    fn add_python(&mut self, files: &[AnalysisResult], root: &Path) {
+        let files: Vec<&AnalysisResult> = files.iter().filter(|f| f.language == Language::Python).collect();
+        let names: Vec<(String, bool)> = files.iter().map(|file| python_module(&file.file_info.path, root)).collect();
+        let known: HashSet<&str> = names.iter().map(|(name, _)| name.as_str()).collect();
+        let lookup = |wanted: &str| -> Option<String> {
+            if known.contains(wanted) {
+                return Some(wanted.to_string());
+            }
+            // Sources below the analyzed root (`src/app/utils.py` for `app.utils`)
+            let suffix = format!(".{}", wanted);
+            let mut matches = known.iter().filter(|name| name.ends_with(&suffix));
+            match (matches.next(), matches.next()) {
+                (Some(name), None) => Some(name.to_string()),
+                _ => None,
+            }
+        };
+
+        for (file, (name, is_package)) in files.iter().zip(&names) {
+            let from = self.module("python", name.clone(), file, root);
+            for import in &file.imports {
+                let Some(module) = absolute_module(name, *is_package, &import.module_path) else { continue };
+                let mut targets = Vec::new();
+                if import.import_type == ImportType::PythonFromImport {
+                    for imported in import.imported_names.iter().filter(|n| *n != "*") {
+                        let submodule = if module.is_empty() { imported.clone() } else { format!("{}.{}", module, imported) };
+                        targets.push(lookup(&submodule).or_else(|| lookup(&module)));
+                    }
+                }
+                if targets.is_empty() {
+                    targets.push(lookup(&module));
+                }
+
+                let mut seen = HashSet::new();
+                for target in targets.into_iter().flatten() {
+                    if seen.insert(target.clone()) {
+                        let to = self.node("python", target);
+                        self.link(from, to, file, import, root);
+                    }
+                }
+            }
+        }
+    }
+
+    /// File-level languages: `resolve` maps an import to the candidate paths it may denote
+    fn add_files(
+        &mut self,
+        files: &[AnalysisResult],
+        root: &Path,
+        language: &'static str,
+        includes: impl Fn(Language) -> bool,
+        resolve: fn(&str, &Path) -> Vec<PathBuf>,
+    ) {
+        let files: Vec<&AnalysisResult> = files.iter().filter(|f| includes(f.language)).collect();
+        let by_path: HashMap<PathBuf, &AnalysisResult> = files.iter()
+            .map(|file| (normalize(&file.file_info.path), *file))
+            .collect();
+
+        for file in &files {
+            let from = self.module(language, display_path(&file.file_info.path, root), file, root);
+            for import in &file.imports {
+                let target = resolve(&import.module_path, &file.file_info.path).into_iter()
+                    .find_map(|candidate| by_path.get(&normalize(&candidate)));
+                if let Some(target) = target {
+                    let to = self.node(language, display_path(&target.file_info.path, root));
+                    self.link(from, to, file, import, root);
+                }
+            }
+        }
+    }
+
+    /// Helper: Node of `name`, recording `file` as one of its files
+    fn module(&mut self, language: &'static str, name: String, file: &AnalysisResult, root: &Path) -> usize {
+        let index = self.node(language, name);
+        self.modules[index].files.push(relative(&file.file_info.path, root));
+        index
+    }
+
+    /// Helper: Node of `name`, created on first use
+    fn node(&mut self, language: &'static str, name: String) -> usize {
+        if let Some(&index) = self.index.get(&(language, name.clone())) {
+            return index;
+        }
+        self.modules.push(ImportModule { name: name.clone(), language: language.to_string(), files: Vec::new() });
+        self.index.insert((language, name), self.modules.len() - 1);
+        self.modules.len() - 1
+    }
+
+    /// Helper: Add one import site to the `from -> to` edge
+    fn link(&mut self, from: usize, to: usize, file: &AnalysisResult, import: &ImportInfo, root: &Path) {
+        if from == to {
+            return;
+        }
+        self.edges.entry((from, to)).or_default().push(ImportSite {
+            file: relative(&file.file_info.path, root),
+            line: import.line_number,
+            import: import.module_path.clone(),
+        });
+    }
+
+    fn finish(self) -> ImportGraph {
+        let modules = self.modules;
+        let edges = self.edges.into_iter()
+            .map(|((from, to), sites)| ImportEdge {
+                language: modules[from].language.clone(),
+                from: modules[from].name.clone(),
+                to: modules[to].name.clone(),
+                sites,
+            })
+            .collect();
+        ImportGraph { modules, edges }
+    }
+}
+
+/// Render cycles for the terminal: members, then the imports between them
+pub fn import_cycles_to_text(cycles: &[ImportCycle]) -> String {
+    if cycles.is_empty() {
+        return "✅ No import cycles found\n".to_string();
+    }
+
+    let mut text = format!("🔁 {} import cycle(s)\n", cycles.len());
+    for (index, cycle) in cycles.iter().enumerate() {
+        text.push_str(&format!("\nCycle {} ({}, {} modules):\n", index + 1, cycle.language, cycle.modules.len()));
+        for module in &cycle.modules {
+            text.push_str(&format!("  {}\n", module));
+        }
+        for edge in &cycle.edges {
+            let sites: Vec<String> = edge.sites.iter().map(|site| format!("{}:{}", site.file.display(), site.line)).collect();
+            text.push_str(&format!("  {} -> {}  {}\n", edge.from, edge.to, sites.join(", ")));
+        }
+    }
+    text
+}
+
+/// Helper: Dotted module name of a Python file, and whether it is a package `__init__.py`
+fn python_module(path: &Path, root: &Path) -> (String, bool) {
+    let stem = relative(path, root).with_extension("");
+    let mut parts: Vec<String> = stem.iter().map(|part| part.to_string_lossy().to_string()).collect();
+    let is_package = parts.last().map(String::as_str) == Some("__init__");
+    if is_package {
+        parts.pop();
+    }
+    if parts.is_empty() {
+        // The analyzed directory is the package itself
+        let name = root.file_name().map_or("__init__".to_string(), |name| name.to_string_lossy().to_string());
+        return (name, is_package);
+    }
+    (parts.join("."), is_package)
+}
+
+/// Helper: Absolute dotted name of `module` imported from `importer`
+///
+/// `.utils` in `app/views.py` is `app.utils`, `..core` one package further
+/// up; `None` when the dots climb above the analyzed root.
+fn absolute_module(importer: &str, is_package: bool, module: &str) -> Option<String> {
+    let rest = module.trim_start_matches('.');
+    let dots = module.len() - rest.len();
+    if dots == 0 {
+        return Some(module.to_string());
+    }
+
+    let mut parts: Vec<&str> = importer.split('.').collect();
+    if !is_package {
+        parts.pop();
+    }
+    for _ in 1..dots {
+        parts.pop()?;
+    }
+    if !rest.is_empty() {
+        parts.push(rest);
+    }
+    Some(parts.join("."))
+}
+
+/// Helper: Files a relative JavaScript / TypeScript specifier may denote
+///
+/// `./util` is `util.ts`, `util.js`, ... or `util/index.*`; `./util.js` may
+/// be compiled from `util.ts`. Package specifiers (`react`) are skipped.
+fn resolve_script(specifier: &str, importer: &Path) -> Vec<PathBuf> {
+    if !(specifier.starts_with("./") || specifier.starts_with("../") || specifier == "." || specifier == "..") {
+        return Vec::new();
+    }
+    let base = importer.parent().unwrap_or(Path::new("")).join(specifier);
+
+    let mut candidates = vec![base.clone()];
+    match base.extension().and_then(|e| e.to_str()) {
+        Some("js") | Some("jsx") | Some("mjs") | Some("cjs") => {
+            candidates.push(base.with_extension("ts"));
+            candidates.push(base.with_extension("tsx"));
+        }
+        _ => {}
+    }
+    for extension in SCRIPT_EXTENSIONS {
+        candidates.push(PathBuf::from(format!("{}.{}", base.display(), extension)));
+    }
+    for extension in SCRIPT_EXTENSIONS {
+        candidates.push(base.join(format!("index.{}", extension)));
+    }
+    candidates
+}
+
+/// Helper: The file a Zig `@import("x.zig")` denotes; build modules (`std`) are skipped
+fn resolve_zig(import: &str, importer: &Path) -> Vec<PathBuf> {
+    if !import.ends_with(".zig") {
+        return Vec::new();
+    }
+    vec![importer.parent().unwrap_or(Path::new("")).join(import)]
+}
+
+/// Helper: Path relative to the analyzed root
+fn relative(path: &Path, root: &Path) -> PathBuf {
+    path.strip_prefix(root).unwrap_or(path).to_path_buf()
+}
+
+/// Helper: Root-relative path as a module name
+fn display_path(path: &Path, root: &Path) -> String {
+    relative(path, root).display().to_string()
+}
+
+/// Helper: Root-relative directory of a Go package outside a module (`.` for the root)
+fn display_dir(dir: &Path, root: &Path) -> String {
+    let name = display_path(dir, root);
+    if name.is_empty() { ".".to_string() } else { name }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::FileInfo;
+
+    fn file(path: &str, language: Language, imports: &[(ImportType, &str, &[&str], u32)]) -> AnalysisResult {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from(path)), language);
+        result.imports = imports.iter()
+            .map(|(import_type, module, names, line)| {
+                let mut import = ImportInfo::new(*import_type, module.to_string());
+                import.imported_names = names.iter().map(|n| n.to_string()).collect();
+                import.line_number = *line;
+                import
+            })
+            .collect();
+        result
+    }
+
+    fn analysis(files: Vec<AnalysisResult>) -> DirectoryAnalysis {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/nonexistent"));
+        analysis.files = files;
+        analysis
+    }
+
+    #[test]
+    fn test_absolute_module() {
+        assert_eq!(absolute_module("app.views", false, ".utils").as_deref(), Some("app.utils"));
+        assert_eq!(absolute_module("app.core", true, "..models").as_deref(), Some("app.models"));
+        assert_eq!(absolute_module("app.views", false, ".").as_deref(), Some("app"));
+        assert_eq!(absolute_module("views", false, "..x"), None);
+        assert_eq!(absolute_module("app.views", false, "os.path").as_deref(), Some("os.path"));
+    }
+
+    #[test]
+    fn test_python_cycle() {
+        use ImportType::{PythonFromImport, PythonImport};
+        let graph = ImportGraph::build(&analysis(vec![
+            file("/nonexistent/app/__init__.py", Language::Python, &[]),
+            file("/nonexistent/app/models.py", Language::Python, &[(PythonFromImport, ".", &["views"], 1), (PythonImport, "os", &[], 2)]),
+            file("/nonexistent/app/views.py", Language::Python, &[(PythonFromImport, "app.models", &["User"], 3)]),
+            file("/nonexistent/app/cli.py", Language::Python, &[(PythonFromImport, "app", &["main"], 1)]),
+        ]));
+
+        let edges: Vec<(&str, &str)> = graph.edges.iter().map(|e| (e.from.as_str(), e.to.as_str())).collect();
+        assert_eq!(edges, vec![("app.models", "app.views"), ("app.views", "app.models"), ("app.cli", "app")]);
+
+        let cycles = graph.cycles();
+        assert_eq!(cycles.len(), 1);
+        assert_eq!(cycles[0].modules, vec!["app.models", "app.views"]);
+        assert_eq!(cycles[0].edges.len(), 2);
+        assert_eq!(cycles[0].edges[0].sites[0].file, PathBuf::from("app/models.py"));
+
+        let text = import_cycles_to_text(&cycles);
+        assert!(text.starts_with("🔁 1 import cycle(s)"), "{}", text);
+        assert!(text.contains("Cycle 1 (python, 2 modules):\n  app.models\n  app.views\n  app.models -> app.views  app/models.py:1\n"), "{}", text);
+    }
+
+    #[test]
+    fn test_script_and_zig_cycles() {
+        use ImportType::{ES6Import, ZigImport};
+        let graph = ImportGraph::build(&analysis(vec![
+            file("/nonexistent/src/a.ts", Language::TypeScript, &[(ES6Import, "./b.js", &[], 1), (ES6Import, "react", &[], 2)]),
+            file("/nonexistent/src/b.ts", Language::TypeScript, &[(ES6Import, "./lib", &[], 1)]),
+            file("/nonexistent/src/lib/index.js", Language::JavaScript, &[(ES6Import, "../a", &[], 4)]),
+            file("/nonexistent/main.zig", Language::Zig, &[(ZigImport, "geo.zig", &[], 1), (ZigImport, "std", &[], 2)]),
+            file("/nonexistent/geo.zig", Language::Zig, &[]),
+        ]));
+
+        let cycles = graph.cycles();
+        assert_eq!(cycles.len(), 1);
+        assert_eq!(cycles[0].language, "javascript");
+        assert_eq!(cycles[0].modules, vec!["src/a.ts", "src/b.ts", "src/lib/index.js"]);
+        assert!(graph.edges.iter().any(|e| e.language == "zig" && e.from == "main.zig" && e.to == "geo.zig"));
+        assert_eq!(import_cycles_to_text(&[]), "✅ No import cycles found\n");
+    }
+}
@@ -12,6 +12,8 @@ use serde::{Deserialize, Serialize};
 use std::fs;
 use walkdir::WalkDir;
 
+use crate::core::types::{AnalysisResult, FunctionInfo};
+
 /// File metadata for change detection
 #[derive(Debug, Clone, Serialize, Deserialize, PartialEq)]
 pub struct FileMetadata {
@@ -226,7 +228,19 @@ impl ChangeDetector {
                 "py" | "pyw" | "pyi" |
                 "cs" |
                 "go" |
-                "rs"
+                "rs" |
+                "rb" |
+                "java" |
+                "kt" |
+                "kts" |
+                "swift" |
+                "php" |
+                "phtml" |
+                "lua" |
+                "dart" |
+                "scala" |
+                "sc" |
+                "zig"
             )
         } else {
             false
@@ -317,6 +331,151 @@ impl IncrementalSummary {
     }
 }
 
+/// Symbol-level change between two analyses of the same file
+#[derive(Debug, Clone, Serialize, Deserialize, PartialEq)]
+pub struct SymbolChange {
+    pub change_type: ChangeType,
+    /// "function", "method", "class", ...
+    pub kind: String,
+    /// Qualified name (Type.method for methods)
+    pub name: String,
+    pub file: PathBuf,
+    pub line: u32,
+    /// What changed for modified symbols
+    pub detail: Option<String>,
+}
+
+impl SymbolChange {
+    /// Compact, greppable line: `+ function src/a.go:12 Foo`
+    pub fn to_line(&self) -> String {
+        let marker = match self.change_type {
+            ChangeType::Added => "+",
+            ChangeType::Deleted => "-",
+            ChangeType::Modified => "~",
+        };
+        
+        let mut line = format!("{} {} {}:{} {}", marker, self.kind, self.file.display(), self.line, self.name);
+        if let Some(detail) = &self.detail {
+            line.push_str(&format!(" ({})", detail));
+        }
+        line
+    }
+}
+
+/// Comparable view of a symbol; line shifts alone are not a modification
+#[derive(Debug, Clone, PartialEq)]
+struct SymbolEntry {
+    kind: String,
+    line: u32,
+    length: u32,
+    parameters: Vec<String>,
+    complexity: u32,
+}
+
+/// Diff the symbols of two analyses of `file` (None = file absent)
+pub fn diff_symbols(file: &Path, previous: Option<&AnalysisResult>, current: Option<&AnalysisResult>) -> Vec<SymbolChange> {
+    let previous = previous.map(collect_symbols).unwrap_or_default();
+    let current = current.map(collect_symbols).unwrap_or_default();
+    let mut changes = Vec::new();
+    
+    let change = |change_type: ChangeType, name: &str, entry: &SymbolEntry, detail: Option<String>| SymbolChange {
+        change_type,
+        kind: entry.kind.clone(),
+        name: name.split('#').next().unwrap_or(name).to_string(),
+        file: file.to_path_buf(),
+        line: entry.line,
+        detail,
+    };
+    
+    for (name, old) in &previous {
+        match current.get(name) {
+            None => changes.push(change(ChangeType::Deleted, name, old, None)),
+            Some(new) => {
+                let mut details = Vec::new();
+                if old.length != new.length {
+                    details.push(format!("lines {} -> {}", old.length, new.length));
+                }
+                if old.parameters != new.parameters {
+                    details.push(format!("params ({}) -> ({})", old.parameters.join(", "), new.parameters.join(", ")));
+                }
+                if old.complexity != new.complexity {
+                    details.push(format!("complexity {} -> {}", old.complexity, new.complexity));
+                }
+                if !details.is_empty() {
+                    changes.push(change(ChangeType::Modified, name, new, Some(details.join(", "))));
+                }
+            }
+        }
+    }
+    
+    for (name, new) in &current {
+        if !previous.contains_key(name) {
+            changes.push(change(ChangeType::Added, name, new, None));
+        }
+    }
+    
+    changes.sort_by_key(|c| c.line);
+    changes
+}
+
+/// Helper: Functions, methods and classes keyed by qualified name (`#n` for overloads)
+fn collect_symbols(result: &AnalysisResult) -> std::collections::BTreeMap<String, SymbolEntry> {
+    let mut symbols = std::collections::BTreeMap::new();
+    
+    let mut insert = |name: String, entry: SymbolEntry| {
+        let mut key = name.clone();
+        let mut index = 1;
+        while symbols.contains_key(&key) {
+            index += 1;
+            key = format!("{}#{}", name, index);
+        }
+        symbols.insert(key, entry);
+    };
+    
+    let function_entry = |func: &FunctionInfo, owner: Option<&String>| {
+        let entry = SymbolEntry {
+            kind: if owner.is_some() { "method" } else { "function" }.to_string(),
+            line: func.start_line,
+            length: func.end_line.saturating_sub(func.start_line) + 1,
+            parameters: func.parameters.clone(),
+            complexity: func.complexity.cyclomatic_complexity,
+        };
+        let name = match owner {
+            Some(owner) => format!("{}.{}", owner, func.name),
+            None => func.name.clone(),
+        };
+        (name, entry)
+    };
+    
+    for func in &result.functions {
+        let owner = func.metadata.get("receiver_type").or_else(|| func.metadata.get("class_name"));
+        let (name, entry) = function_entry(func, owner);
+        insert(name, entry);
+    }
+    
+    for class in &result.classes {
+        insert(class.name.clone(), SymbolEntry {
+            kind: class.metadata.get("type").cloned().unwrap_or_else(|| "class".to_string()),
+            line: class.start_line,
+            length: class.end_line.saturating_sub(class.start_line) + 1,
+            parameters: Vec::new(),
+            complexity: 0,
+        });
+        
+        // Methods the analyzer did not also report as functions
+        for method in &class.methods {
+            let already_known = result.functions.iter()
+                .any(|f| f.name == method.name && f.start_line == method.start_line);
+            if !already_known {
+                let (name, entry) = function_entry(method, Some(&class.name));
+                insert(name, entry);
+            }
+        }
+    }
+    
+    symbols
+}
+
 #[cfg(test)]
 mod tests {
     use super::*;
@@ -394,4 +553,36 @@ mod tests {
         assert_eq!(summary.analysis_time_ms, 1000);
         assert_eq!(summary.estimated_speedup, 30.0);
     }
-}
\ No newline at end of file
+    
+    #[test]
+    fn test_diff_symbols() {
+        use crate::core::types::{FileInfo, Language};
+        
+        let function = |name: &str, start: u32, end: u32| {
+            let mut func = FunctionInfo::new(name.to_string());
+            func.start_line = start;
+            func.end_line = end;
+            func
+        };
+        
+        let mut before = AnalysisResult::new(FileInfo::new(PathBuf::from("a.go")), Language::Go);
+        before.functions = vec![function("Keep", 1, 3), function("Grow", 5, 7), function("Gone", 9, 10)];
+        
+        let mut after = before.clone();
+        after.functions = vec![function("Keep", 2, 4), function("Grow", 6, 12), function("Fresh", 14, 15)];
+        
+        let lines: Vec<String> = diff_symbols(Path::new("a.go"), Some(&before), Some(&after))
+            .iter()
+            .map(|c| c.to_line())
+            .collect();
+        
+        assert_eq!(lines, vec![
+            "~ function a.go:6 Grow (lines 3 -> 7)",
+            "- function a.go:9 Gone",
+            "+ function a.go:14 Fresh",
+        ]);
+        
+        // Deleted file removes every symbol
+        assert_eq!(diff_symbols(Path::new("a.go"), Some(&before), None).len(), 3);
+    }
+}
@@ -0,0 +1,144 @@
+//! 🧭 Normalized symbol kinds
+//!
+//! Analyzers name symbols in their language's terms (`func`, `fn`, `def`,
+//! `protocol`, `object`). `annotate_kinds` sets one `kind` from a fixed
+//! taxonomy on every function, method, type and field, and keeps the
+//! language's term as `native_kind`, so rules written against `kind` hold for
+//! every language:
+//!
+//! - `function` / `method`: methods are functions under a class or with a
+//!   receiver / owning class (Go `func (s *Server) Start()`)
+//! - `struct` (Go, Rust, C#, C++, Swift `struct`), `interface` (also Swift
+//!   `protocol`, Java `annotation`), `enum`, `trait` (also Dart `mixin`),
+//!   `module` (Ruby / Lua modules, Scala package objects)
+//! - `class` for every other type declaration: classes, records, objects,
+//!   Go defined types (`type Celsius float64`), Rust `impl` blocks, extensions
+//! - `constant` / `field` for member variables, by `is_const`
+//!
+//! The native term is the analyzer's `type` metadata where it records one,
+//! else the language's keyword for a function (`func`, `def`, `fn`, ...).
+
+use crate::core::types::{AnalysisResult, ClassInfo, FunctionInfo, Language, SymbolKind};
+
+/// Set `kind` and `native_kind` on every symbol of a file result
+pub fn annotate_kinds(result: &mut AnalysisResult) {
+    let language = result.language;
+    for func in &mut result.functions {
+        let is_method = func.metadata.contains_key("class_name") || func.metadata.contains_key("receiver_type");
+        annotate_function(func, language, is_method);
+    }
+    for class in &mut result.classes {
+        annotate_class(class, language);
+    }
+}
+
+/// Normalized kind of a type declaration's native term
+pub fn type_kind(native: &str) -> SymbolKind {
+    match native {
+        "struct" => SymbolKind::Struct,
+        "interface" | "protocol" | "annotation" => SymbolKind::Interface,
+        "enum" => SymbolKind::Enum,
+        "trait" | "mixin" => SymbolKind::Trait,
+        "module" | "package_object" => SymbolKind::Module,
+        _ => SymbolKind::Class,
+    }
+}
+
+/// Helper: Kind of a function and, recursively, of the functions nested in it
+fn annotate_function(func: &mut FunctionInfo, language: Language, is_method: bool) {
+    func.kind = if is_method { SymbolKind::Method } else { SymbolKind::Function };
+    func.native_kind = func.metadata.get("type").cloned()
+        .unwrap_or_else(|| function_term(language, is_method).to_string());
+    for child in &mut func.children {
+        annotate_function(child, language, false);
+    }
+}
+
+/// Helper: Kind of a type, its methods and its member variables
+fn annotate_class(class: &mut ClassInfo, language: Language) {
+    class.native_kind = class.metadata.get("type").cloned().unwrap_or_else(|| "class".to_string());
+    class.kind = type_kind(&class.native_kind);
+
+    for method in &mut class.methods {
+        annotate_function(method, language, true);
+    }
+    for member in &mut class.member_variables {
+        member.kind = if member.is_const { SymbolKind::Constant } else { SymbolKind::Field };
+        member.native_kind = if member.is_const { "const" } else { "field" }.to_string();
+    }
+}
+
+/// Helper: The language's word for a function or method without a recorded `type`
+fn function_term(language: Language, is_method: bool) -> &'static str {
+    match language {
+        Language::Go if is_method => "method",
+        Language::Go | Language::Swift => "func",
+        Language::Python | Language::Ruby | Language::Scala => "def",
+        Language::Rust | Language::Zig => "fn",
+        Language::Kotlin => "fun",
+        Language::Java | Language::CSharp => "method",
+        _ if is_method => "method",
+        _ => "function",
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, MemberVariable};
+    use std::path::PathBuf;
+
+    #[test]
+    fn test_annotate_go_kinds() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("server.go")), Language::Go);
+        let mut start = FunctionInfo::new("Start".to_string());
+        start.metadata.insert("receiver_type".to_string(), "Server".to_string());
+        let mut main = FunctionInfo::new("main".to_string());
+        main.children.push(FunctionInfo::new("main$func1".to_string()));
+        result.functions = vec![start, main];
+
+        let mut server = ClassInfo::new("Server".to_string());
+        server.metadata.insert("type".to_string(), "struct".to_string());
+        let mut limit = MemberVariable::new("limit".to_string(), "int".to_string(), 4);
+        limit.is_const = true;
+        server.member_variables = vec![MemberVariable::new("addr".to_string(), "string".to_string(), 3), limit];
+        let mut handler = ClassInfo::new("Handler".to_string());
+        handler.metadata.insert("type".to_string(), "interface".to_string());
+        let mut celsius = ClassInfo::new("Celsius".to_string());
+        celsius.metadata.insert("type".to_string(), "type".to_string());
+        result.classes = vec![server, handler, celsius];
+
+        annotate_kinds(&mut result);
+
+        let functions: Vec<(SymbolKind, &str)> = result.functions.iter().map(|f| (f.kind, f.native_kind.as_str())).collect();
+        assert_eq!(functions, vec![(SymbolKind::Method, "method"), (SymbolKind::Function, "func")]);
+        assert_eq!(result.functions[1].children[0].kind, SymbolKind::Function);
+
+        let classes: Vec<(SymbolKind, &str)> = result.classes.iter().map(|c| (c.kind, c.native_kind.as_str())).collect();
+        assert_eq!(classes, vec![(SymbolKind::Struct, "struct"), (SymbolKind::Interface, "interface"), (SymbolKind::Class, "type")]);
+        let members: Vec<SymbolKind> = result.classes[0].member_variables.iter().map(|m| m.kind).collect();
+        assert_eq!(members, vec![SymbolKind::Field, SymbolKind::Constant]);
+
+        assert_eq!(serde_json::to_value(SymbolKind::Interface).unwrap(), "interface");
+    }
+
+    #[test]
+    fn test_native_terms() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("shapes.swift")), Language::Swift);
+        let mut shape = ClassInfo::new("Shape".to_string());
+        shape.metadata.insert("type".to_string(), "protocol".to_string());
+        let mut area = FunctionInfo::new("area".to_string());
+        area.metadata.insert("type".to_string(), "function".to_string());
+        shape.methods.push(area);
+        result.classes.push(shape);
+        result.functions.push(FunctionInfo::new("main".to_string()));
+
+        annotate_kinds(&mut result);
+
+        assert_eq!((result.classes[0].kind, result.classes[0].native_kind.as_str()), (SymbolKind::Interface, "protocol"));
+        assert_eq!((result.classes[0].methods[0].kind, result.classes[0].methods[0].native_kind.as_str()), (SymbolKind::Method, "function"));
+        assert_eq!(result.functions[0].native_kind, "func");
+        assert_eq!(type_kind("mixin"), SymbolKind::Trait);
+        assert_eq!(type_kind("case_class"), SymbolKind::Class);
+    }
+}
@@ -0,0 +1,120 @@
+//! Tech-debt marker inventory for the `markers` command
+//!
+//! Flattens the per-file `markers` of an analysis into one list with file
+//! paths, ordered by file and line, with a text view grouped by tag.
+
+use serde::{Deserialize, Serialize};
+use std::collections::BTreeMap;
+
+use crate::core::types::DirectoryAnalysis;
+
+/// One marker with its location
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MarkerEntry {
+    #[serde(rename = "type")]
+    pub kind: String,
+    pub file: String,
+    pub line: u32,
+    pub text: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub function: Option<String>,
+}
+
+/// Result of the `markers` command
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct MarkerReport {
+    /// Number of markers per tag
+    pub counts: BTreeMap<String, usize>,
+    pub markers: Vec<MarkerEntry>,
+}
+
+impl MarkerReport {
+    /// Collect the markers of every file of an analysis
+    pub fn build(analysis: &DirectoryAnalysis) -> Self {
+        let mut markers = Vec::new();
+        
+        for file in &analysis.files {
+            let path = file.file_info.path.strip_prefix(&analysis.directory_path)
+                .unwrap_or(&file.file_info.path)
+                .to_string_lossy()
+                .to_string();
+            
+            for marker in &file.markers {
+                markers.push(MarkerEntry {
+                    kind: marker.kind.clone(),
+                    file: path.clone(),
+                    line: marker.line,
+                    text: marker.text.clone(),
+                    function: marker.function.clone(),
+                });
+            }
+        }
+        markers.sort_by(|a, b| (&a.file, a.line).cmp(&(&b.file, b.line)));
+        
+        let mut counts = BTreeMap::new();
+        for marker in &markers {
+            *counts.entry(marker.kind.clone()).or_default() += 1;
+        }
+        
+        Self { counts, markers }
+    }
+    
+    /// Human-readable listing, one section per tag
+    pub fn to_text(&self) -> String {
+        let mut lines = vec![format!("markers: {}", self.markers.len())];
+        
+        for (kind, count) in &self.counts {
+            lines.push(String::new());
+            lines.push(format!("{} ({})", kind, count));
+            for marker in self.markers.iter().filter(|m| &m.kind == kind) {
+                let location = match &marker.function {
+                    Some(function) => format!("{}:{} [{}]", marker.file, marker.line, function),
+                    None => format!("{}:{}", marker.file, marker.line),
+                };
+                lines.push(format!("  {} {}", location, marker.text));
+            }
+        }
+        
+        lines.join("\n")
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{AnalysisResult, FileInfo, Language, MarkerInfo};
+    use std::path::PathBuf;
+    
+    fn marker(kind: &str, line: u32, text: &str, function: Option<&str>) -> MarkerInfo {
+        MarkerInfo {
+            kind: kind.to_string(),
+            line,
+            text: text.to_string(),
+            function: function.map(str::to_string),
+        }
+    }
+    
+    #[test]
+    fn test_report_groups_by_tag() {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        
+        let mut b = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/b.go")), Language::Go);
+        b.markers.push(marker("FIXME", 9, "leaks on error", Some("Server.Start")));
+        analysis.files.push(b);
+        
+        let mut a = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/a.go")), Language::Go);
+        a.markers.push(marker("TODO", 12, "retry", None));
+        a.markers.push(marker("TODO", 3, "validate input", Some("parse")));
+        analysis.files.push(a);
+        
+        let report = MarkerReport::build(&analysis);
+        let order: Vec<_> = report.markers.iter().map(|m| (m.file.as_str(), m.line)).collect();
+        assert_eq!(order, vec![("a.go", 3), ("a.go", 12), ("b.go", 9)]);
+        assert_eq!(report.counts.get("TODO"), Some(&2));
+        assert_eq!(report.counts.get("FIXME"), Some(&1));
+        
+        let text = report.to_text();
+        assert!(text.contains("FIXME (1)\n  b.go:9 [Server.Start] leaks on error"));
+        assert!(text.contains("TODO (2)\n  a.go:3 [parse] validate input\n  a.go:12 retry"));
+    }
+}
@@ -1,5 +1,14 @@
 pub mod types;
 pub mod session;
+pub mod source;
+pub mod notebook;
+pub mod archive;
+pub mod generated;
+pub mod namespace;
+pub mod kinds;
+pub mod stable_id;
+pub mod diagnostics;
+pub mod validate;
 pub mod commands;
 pub mod config;
 pub mod memory;
@@ -7,4 +16,29 @@ pub mod preview;
 pub mod ast;
 pub mod moveclass;
 pub mod impact;
-pub mod incremental;
\ No newline at end of file
+pub mod incremental;
+pub mod callgraph;
+pub mod importgraph;
+pub mod hierarchy;
+pub mod cache;
+pub mod deadcode;
+pub mod references;
+pub mod diff;
+pub mod duplicates;
+pub mod project_config;
+pub mod schema;
+pub mod stats;
+pub mod ranking;
+pub mod visibility;
+pub mod query;
+pub mod markers;
+pub mod heatmap;
+pub mod smells;
+pub mod git;
+pub mod churn;
+pub mod report;
+pub mod redact;
+pub mod csv;
+pub mod gate;
+pub mod baseline;
+pub mod progress;
\ No newline at end of file
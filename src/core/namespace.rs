@@ -0,0 +1,170 @@
+//! 🏷️ Enclosing namespace of every symbol
+//!
+//! Fills `namespace` on classes, functions and methods so that names from
+//! different packages stay apart when results are merged:
+//!
+//! - Go, Java, Kotlin, Scala: the file's `package` declaration
+//! - C#, PHP: the namespace block the declaration sits in
+//! - Python: the dotted module path, through the enclosing `__init__.py` packages
+//! - Rust: the module path implied by the file's place under `src/` (`crate::core::types`)
+//!
+//! Methods without a namespace of their own take their class's. Languages
+//! without a declared or path-derived namespace are left as they are.
+
+use std::collections::HashMap;
+use std::path::Path;
+
+use crate::core::types::{AnalysisResult, FunctionInfo, Language};
+
+/// Set `namespace` on every symbol of a file result
+pub fn annotate_namespaces(result: &mut AnalysisResult) {
+    let file_namespace = file_namespace(result);
+
+    for class in &mut result.classes {
+        class.namespace = class.metadata.get("namespace").cloned().or_else(|| file_namespace.clone());
+        let namespace = class.namespace.clone();
+        for method in &mut class.methods {
+            set_namespace(method, namespace.clone());
+        }
+    }
+
+    let class_namespaces: HashMap<String, Option<String>> = result.classes.iter()
+        .map(|class| (class.name.clone(), class.namespace.clone()))
+        .collect();
+    for func in &mut result.functions {
+        let owner = func.metadata.get("class_name").and_then(|owner| class_namespaces.get(owner)).cloned().flatten();
+        set_namespace(func, owner.or_else(|| file_namespace.clone()));
+    }
+}
+
+/// Helper: A function's own namespace, else `fallback`; nested functions follow
+fn set_namespace(func: &mut FunctionInfo, fallback: Option<String>) {
+    func.namespace = func.metadata.get("namespace").cloned().or(fallback);
+    let namespace = func.namespace.clone();
+    for child in &mut func.children {
+        set_namespace(child, namespace.clone());
+    }
+}
+
+/// Helper: Namespace of the whole file, if the language has one
+fn file_namespace(result: &AnalysisResult) -> Option<String> {
+    match result.language {
+        Language::Go | Language::Java | Language::Kotlin | Language::Scala => result.metadata.get("package").cloned(),
+        Language::Python => python_module(&result.file_info.path),
+        Language::Rust => rust_module(&result.file_info.path),
+        _ => None,
+    }
+}
+
+/// Dotted module path of a Python file: `app/models/user.py` is `app.models.user`
+/// when `app` and `app/models` are packages (contain `__init__.py`)
+pub fn python_module(path: &Path) -> Option<String> {
+    let stem = path.file_stem()?.to_str()?;
+    let mut parts = Vec::new();
+    if stem != "__init__" {
+        parts.push(stem.to_string());
+    }
+
+    let mut dir = path.parent();
+    while let Some(current) = dir {
+        let Some(name) = current.file_name().and_then(|n| n.to_str()) else { break };
+        if !current.join("__init__.py").is_file() {
+            break;
+        }
+        parts.push(name.to_string());
+        dir = current.parent();
+    }
+
+    if parts.is_empty() {
+        return None;
+    }
+    parts.reverse();
+    Some(parts.join("."))
+}
+
+/// Module path of a Rust file below the nearest `src/`: `src/core/types.rs` and
+/// `src/core/types/mod.rs` are `crate::core::types`, `src/lib.rs` is `crate`
+pub fn rust_module(path: &Path) -> Option<String> {
+    let components: Vec<&str> = path.iter().filter_map(|c| c.to_str()).collect();
+    let src = components.iter().rposition(|c| *c == "src")?;
+
+    let mut parts = vec!["crate"];
+    let inner = &components[src + 1..];
+    for (index, component) in inner.iter().enumerate() {
+        if index + 1 < inner.len() {
+            parts.push(component);
+            continue;
+        }
+        let stem = component.strip_suffix(".rs")?;
+        let is_root = index == 0 && matches!(stem, "lib" | "main");
+        if stem != "mod" && !is_root {
+            parts.push(stem);
+        }
+    }
+    Some(parts.join("::"))
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ClassInfo, FileInfo};
+    use std::path::PathBuf;
+
+    #[test]
+    fn test_annotate_namespaces() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("main.go")), Language::Go);
+        result.metadata.insert("package".to_string(), "main".to_string());
+        let mut method = FunctionInfo::new("Run".to_string());
+        method.children.push(FunctionInfo::new("Run$func1".to_string()));
+        result.functions.push(method.clone());
+        let mut class = ClassInfo::new("Server".to_string());
+        class.methods.push(method);
+        result.classes.push(class);
+        annotate_namespaces(&mut result);
+
+        assert_eq!(result.classes[0].namespace.as_deref(), Some("main"));
+        assert_eq!(result.classes[0].methods[0].namespace.as_deref(), Some("main"));
+        assert_eq!(result.functions[0].children[0].namespace.as_deref(), Some("main"));
+
+        // A declaration's own namespace wins; methods follow their class
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("Api.cs")), Language::CSharp);
+        let mut class = ClassInfo::new("Api".to_string());
+        class.metadata.insert("namespace".to_string(), "App.Web".to_string());
+        result.classes.push(class);
+        let mut method = FunctionInfo::new("Get".to_string());
+        method.metadata.insert("class_name".to_string(), "Api".to_string());
+        result.functions.push(method);
+        result.functions.push(FunctionInfo::new("Main".to_string()));
+        annotate_namespaces(&mut result);
+
+        assert_eq!(result.functions[0].namespace.as_deref(), Some("App.Web"));
+        assert_eq!(result.functions[1].namespace, None);
+    }
+
+    #[test]
+    fn test_rust_module() {
+        assert_eq!(rust_module(Path::new("/repo/src/core/types.rs")).as_deref(), Some("crate::core::types"));
+        assert_eq!(rust_module(Path::new("/repo/src/core/mod.rs")).as_deref(), Some("crate::core"));
+        assert_eq!(rust_module(Path::new("/repo/src/lib.rs")).as_deref(), Some("crate"));
+        assert_eq!(rust_module(Path::new("/repo/src/analyzers/main.rs")).as_deref(), Some("crate::analyzers::main"));
+        assert_eq!(rust_module(Path::new("/repo/build.rs")), None);
+    }
+
+    #[test]
+    fn test_python_module() {
+        let root = std::env::temp_dir().join(format!("nekocode-namespace-{}", std::process::id()));
+        let package = root.join("app").join("models");
+        std::fs::create_dir_all(&package).unwrap();
+        std::fs::write(root.join("app").join("__init__.py"), "").unwrap();
+        std::fs::write(package.join("__init__.py"), "").unwrap();
+
+        let user = python_module(&package.join("user.py"));
+        let init = python_module(&package.join("__init__.py"));
+        let script = python_module(&root.join("manage.py"));
+        std::fs::remove_dir_all(&root).unwrap();
+
+        assert_eq!(user.as_deref(), Some("app.models.user"));
+        assert_eq!(init.as_deref(), Some("app.models"));
+        assert_eq!(script.as_deref(), Some("manage"));
+    }
+}
@@ -0,0 +1,179 @@
+//! 📓 Jupyter notebooks (`.ipynb`) analyzed as Python
+//!
+//! The `code` cells are concatenated into one Python source, in notebook
+//! order; markdown and raw cells and all outputs are dropped. Symbol lines
+//! refer to that source, and each function and class also gets a
+//! `notebook_location` (`analysis.ipynb#cell3:line5`, cells counted from 1
+//! over every cell of the notebook) pointing back into the notebook.
+//!
+//! IPython magics and shell escapes (`%matplotlib inline`, `!pip install`)
+//! are not Python; they are turned into comments so line numbers still map.
+
+use anyhow::Result;
+use serde_json::Value;
+use std::path::Path;
+
+use crate::core::types::{AnalysisResult, FunctionInfo};
+
+/// Extension of Jupyter notebooks
+pub const NOTEBOOK_EXTENSION: &str = "ipynb";
+
+/// The lines one code cell contributes to the concatenated source
+#[derive(Debug, Clone, PartialEq, Eq)]
+struct CodeCell {
+    /// 1-based position among all cells of the notebook
+    cell: usize,
+    /// 1-based line of the cell's first line in the concatenated source
+    first_line: u32,
+    lines: u32,
+}
+
+/// Python source of a notebook's code cells, with the cell of every line
+#[derive(Debug, Clone)]
+pub struct NotebookSource {
+    pub source: String,
+    cells: Vec<CodeCell>,
+}
+
+/// Whether `path` names a Jupyter notebook
+pub fn is_notebook(path: &Path) -> bool {
+    path.extension().map_or(false, |e| e.eq_ignore_ascii_case(NOTEBOOK_EXTENSION))
+}
+
+impl NotebookSource {
+    /// Concatenate the code cells of notebook JSON (nbformat 4)
+    ///
+    /// Fails on invalid JSON, older formats without a `cells` list, and
+    /// notebooks whose kernel is not Python.
+    pub fn parse(json: &str) -> Result<Self> {
+        let notebook: Value = serde_json::from_str(json)
+            .map_err(|e| anyhow::anyhow!("Invalid notebook JSON: {}", e))?;
+        
+        let metadata = &notebook["metadata"];
+        let kernel_language = metadata["kernelspec"]["language"].as_str()
+            .or_else(|| metadata["language_info"]["name"].as_str());
+        if let Some(language) = kernel_language {
+            if !language.eq_ignore_ascii_case("python") {
+                anyhow::bail!("Notebook kernel language is {}, not Python", language);
+            }
+        }
+        
+        let Some(cells) = notebook["cells"].as_array() else {
+            anyhow::bail!("Unsupported notebook format: no `cells` list (nbformat 4 is required)");
+        };
+        
+        let mut source = String::new();
+        let mut code_cells = Vec::new();
+        let mut next_line = 1;
+        for (index, cell) in cells.iter().enumerate() {
+            if cell["cell_type"].as_str() != Some("code") {
+                continue;
+            }
+            let text = cell_source(&cell["source"]);
+            let lines = text.lines().count() as u32;
+            for line in text.lines() {
+                let code = line.trim_start();
+                if code.starts_with('%') || code.starts_with('!') {
+                    source.push_str("# ");
+                }
+                source.push_str(line);
+                source.push('\n');
+            }
+            code_cells.push(CodeCell { cell: index + 1, first_line: next_line, lines });
+            next_line += lines;
+        }
+        
+        Ok(Self { source, cells: code_cells })
+    }
+    
+    /// Cell (1-based, over every cell) and line within it of a source line
+    pub fn location(&self, line: u32) -> Option<(usize, u32)> {
+        self.cells.iter()
+            .find(|cell| line >= cell.first_line && line < cell.first_line + cell.lines)
+            .map(|cell| (cell.cell, line - cell.first_line + 1))
+    }
+    
+    /// Set `notebook_location` on the functions, classes and methods of a result
+    /// for the notebook file `name`, and the number of code cells on the file
+    pub fn annotate(&self, result: &mut AnalysisResult, name: &str) {
+        result.metadata.insert("notebook_code_cells".to_string(), self.cells.len().to_string());
+        for func in &mut result.functions {
+            self.annotate_function(func, name);
+        }
+        for class in &mut result.classes {
+            if let Some(location) = self.location_text(class.start_line, name) {
+                class.metadata.insert("notebook_location".to_string(), location);
+            }
+            for method in &mut class.methods {
+                self.annotate_function(method, name);
+            }
+        }
+    }
+    
+    /// Helper: A function and its nested functions
+    fn annotate_function(&self, func: &mut FunctionInfo, name: &str) {
+        if let Some(location) = self.location_text(func.start_line, name) {
+            func.metadata.insert("notebook_location".to_string(), location);
+        }
+        for child in &mut func.children {
+            self.annotate_function(child, name);
+        }
+    }
+    
+    /// Helper: `name#cellN:lineM` of a source line
+    fn location_text(&self, line: u32, name: &str) -> Option<String> {
+        self.location(line).map(|(cell, line)| format!("{}#cell{}:line{}", name, cell, line))
+    }
+}
+
+/// Helper: A cell's `source`, a string or a list of lines
+fn cell_source(source: &Value) -> String {
+    match source {
+        Value::String(text) => text.clone(),
+        Value::Array(lines) => lines.iter().filter_map(Value::as_str).collect(),
+        _ => String::new(),
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    const NOTEBOOK: &str = r##"{
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Title\n", "def not_code(): pass\n"]},
+  {"cell_type": "code", "metadata": {}, "outputs": [{"output_type": "stream", "text": ["def out(): pass\n"]}],
+   "source": ["%matplotlib inline\n", "import math"]},
+  {"cell_type": "code", "metadata": {}, "outputs": [], "source": "def area(r):\n    return math.pi * r * r\n"}
+ ],
+ "metadata": {"kernelspec": {"language": "python", "name": "python3"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}"##;
+    
+    #[test]
+    fn test_code_cells_are_concatenated() {
+        let notebook = NotebookSource::parse(NOTEBOOK).unwrap();
+        assert_eq!(notebook.source, "# %matplotlib inline\nimport math\ndef area(r):\n    return math.pi * r * r\n");
+        
+        assert_eq!(notebook.location(1), Some((2, 1)));
+        assert_eq!(notebook.location(2), Some((2, 2)));
+        assert_eq!(notebook.location(4), Some((3, 2)));
+        assert_eq!(notebook.location(5), None);
+    }
+    
+    #[test]
+    fn test_rejected_notebooks() {
+        assert!(NotebookSource::parse("{ not json").is_err());
+        assert!(NotebookSource::parse(r#"{"worksheets": [], "nbformat": 3}"#).is_err());
+        let r = r#"{"cells": [], "metadata": {"kernelspec": {"language": "R"}}}"#;
+        assert!(NotebookSource::parse(r).unwrap_err().to_string().contains("R, not Python"));
+    }
+    
+    #[test]
+    fn test_is_notebook() {
+        assert!(is_notebook(Path::new("work/analysis.ipynb")));
+        assert!(is_notebook(Path::new("Analysis.IPYNB")));
+        assert!(!is_notebook(Path::new("analysis.py")));
+    }
+}
@@ -0,0 +1,153 @@
+//! ⏳ Progress reporting for long directory runs
+//!
+//! `--progress auto` (the default) draws `files processed / total` on stderr,
+//! updated in place, when stderr is a terminal; `always` draws it even when
+//! stderr is redirected, `never` stays silent. Stdout is never touched, so
+//! `--format json` output stays clean.
+
+use schemars::JsonSchema;
+use serde::{Deserialize, Serialize};
+use std::io::{IsTerminal, Write};
+use std::time::{Duration, Instant};
+
+/// Minimum time between two redraws
+const REDRAW_INTERVAL: Duration = Duration::from_millis(100);
+
+/// When to report progress
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+#[serde(rename_all = "lowercase")]
+pub enum ProgressMode {
+    /// Always, even when stderr is not a terminal
+    Always,
+    /// Never (library default)
+    #[default]
+    Never,
+    /// Only when stderr is a terminal
+    Auto,
+}
+
+impl ProgressMode {
+    /// Parse a `--progress` value
+    pub fn parse(value: &str) -> Option<Self> {
+        match value.to_lowercase().as_str() {
+            "always" => Some(Self::Always),
+            "never" => Some(Self::Never),
+            "auto" => Some(Self::Auto),
+            _ => None,
+        }
+    }
+
+    /// Whether progress is drawn on this process's stderr
+    pub fn is_enabled(self) -> bool {
+        match self {
+            Self::Always => true,
+            Self::Never => false,
+            Self::Auto => std::io::stderr().is_terminal(),
+        }
+    }
+}
+
+/// Files-processed counter drawn on one line
+pub struct Progress<W: Write> {
+    out: Option<W>,
+    /// Redraw with `\r` on a terminal; one line per update elsewhere
+    in_place: bool,
+    total: usize,
+    done: usize,
+    last_draw: Option<Instant>,
+}
+
+impl Progress<std::io::Stderr> {
+    /// Progress on stderr for `total` files, or a silent counter if `mode` disables it
+    pub fn stderr(mode: ProgressMode, total: usize) -> Self {
+        let out = if mode.is_enabled() && total > 0 { Some(std::io::stderr()) } else { None };
+        Self::new(out, std::io::stderr().is_terminal(), total)
+    }
+}
+
+impl<W: Write> Progress<W> {
+    pub fn new(out: Option<W>, in_place: bool, total: usize) -> Self {
+        Self { out, in_place, total, done: 0, last_draw: None }
+    }
+
+    /// One more file processed
+    pub fn tick(&mut self) {
+        self.done += 1;
+        let due = self.last_draw.map_or(true, |last| last.elapsed() >= REDRAW_INTERVAL);
+        if due || self.done == self.total {
+            self.draw();
+        }
+    }
+
+    /// End the progress line so later stderr output starts on a fresh one
+    pub fn finish(&mut self) {
+        if self.last_draw.is_some() && self.in_place {
+            if let Some(out) = self.out.as_mut() {
+                // Progress output is best-effort; a closed stderr must not fail the run
+                let _ = out.write_all(b"\n");
+                let _ = out.flush();
+            }
+        }
+        self.out = None;
+    }
+
+    /// Helper: Write the current count
+    fn draw(&mut self) {
+        let Some(out) = self.out.as_mut() else { return };
+        let line = format!("⏳ Analyzing files: {}/{}", self.done, self.total);
+        let _ = if self.in_place {
+            write!(out, "\r{}", line)
+        } else {
+            writeln!(out, "{}", line)
+        };
+        let _ = out.flush();
+        self.last_draw = Some(Instant::now());
+    }
+}
+
+impl<W: Write> Drop for Progress<W> {
+    fn drop(&mut self) {
+        self.finish();
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_parse() {
+        assert_eq!(ProgressMode::parse("Auto"), Some(ProgressMode::Auto));
+        assert_eq!(ProgressMode::parse("never"), Some(ProgressMode::Never));
+        assert_eq!(ProgressMode::parse("sometimes"), None);
+        assert!(!ProgressMode::Never.is_enabled());
+        assert!(ProgressMode::Always.is_enabled());
+    }
+
+    #[test]
+    fn test_in_place_progress() {
+        let mut out = Vec::new();
+        {
+            let mut progress = Progress::new(Some(&mut out), true, 3);
+            for _ in 0..3 {
+                progress.tick();
+            }
+            progress.finish();
+        }
+
+        // First and last counts are always drawn; the line ends once
+        let text = String::from_utf8(out).unwrap();
+        assert!(text.starts_with("\r⏳ Analyzing files: 1/3"));
+        assert!(text.ends_with("\r⏳ Analyzing files: 3/3\n"));
+        assert_eq!(text.matches('\n').count(), 1);
+    }
+
+    #[test]
+    fn test_silent_progress() {
+        let mut progress: Progress<Vec<u8>> = Progress::new(None, true, 2);
+        progress.tick();
+        progress.tick();
+        progress.finish();
+        assert!(progress.out.is_none());
+    }
+}
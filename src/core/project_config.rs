@@ -0,0 +1,517 @@
+//! 🗂️ Project configuration file (`nekocode.toml`)
+//!
+//! The file is discovered by walking up from the analyzed path, so running
+//! nekocode anywhere inside a project picks up the same settings. Values are
+//! layered: built-in defaults, then the file, then command-line flags.
+//!
+//! ```toml
+//! [files]
+//! include = ["src/**"]
+//! exclude = ["**/generated/**"]
+//!
+//! [languages]
+//! enabled = ["go", "rust"]
+//!
+//! [metrics]
+//! max_cyclomatic = 15
+//! max_cognitive = 20
+//!
+//! [metrics.weights]  # per decision point in cyclomatic complexity (default 1 each)
+//! case = 0
+//! and = 0
+//! or = 0
+//!
+//! [smells]
+//! max_params = 6
+//! max_function_loc = 80
+//! allowed_numbers = [2, 100]
+//!
+//! [performance]
+//! jobs = 8
+//!
+//! [cache]
+//! enabled = true
+//! dir = ".nekocode-cache"
+//! ```
+//!
+//! Unknown sections or keys are rejected so typos don't silently no-op.
+
+use anyhow::{Context, Result};
+use globset::{Glob, GlobMatcher, GlobSet, GlobSetBuilder};
+use serde::Deserialize;
+use std::fs;
+use std::path::{Path, PathBuf};
+
+use crate::core::types::{AnalysisConfig, Language};
+use crate::metrics::cyclomatic::{ComplexityWeights, Construct};
+
+/// File name looked up in the target directory and its ancestors
+pub const PROJECT_CONFIG_FILE: &str = "nekocode.toml";
+
+/// Parsed `nekocode.toml`
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct ProjectConfig {
+    #[serde(default)]
+    pub files: FilesSection,
+    #[serde(default)]
+    pub languages: LanguagesSection,
+    #[serde(default)]
+    pub metrics: MetricsSection,
+    #[serde(default)]
+    pub smells: SmellsSection,
+    #[serde(default)]
+    pub performance: PerformanceSection,
+    #[serde(default)]
+    pub cache: CacheSection,
+}
+
+/// `[files]`: globs relative to the directory containing the config file
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct FilesSection {
+    /// Only files matching one of these globs are analyzed (empty = all)
+    #[serde(default)]
+    pub include: Vec<String>,
+    /// Files matching one of these globs are skipped
+    #[serde(default)]
+    pub exclude: Vec<String>,
+    pub include_tests: Option<bool>,
+    pub respect_ignore_files: Option<bool>,
+    pub follow_symlinks: Option<bool>,
+    /// Skip files whose header marks them as generated
+    pub exclude_generated: Option<bool>,
+}
+
+/// `[languages]`
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct LanguagesSection {
+    /// Languages to analyze (empty = every supported language)
+    #[serde(default)]
+    pub enabled: Vec<Language>,
+}
+
+/// `[metrics]`
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct MetricsSection {
+    /// Functions above this cyclomatic complexity are counted as over threshold
+    pub max_cyclomatic: Option<u32>,
+    /// Functions above this cognitive complexity are counted as over threshold
+    pub max_cognitive: Option<u32>,
+    /// Weight of each decision-point construct
+    pub weights: Option<WeightsSection>,
+}
+
+/// `[metrics.weights]`: construct -> weight; see `metrics::cyclomatic` for what each covers
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct WeightsSection {
+    #[serde(rename = "if")]
+    pub if_: Option<u32>,
+    /// Every loop
+    #[serde(rename = "for")]
+    pub for_: Option<u32>,
+    pub case: Option<u32>,
+    pub and: Option<u32>,
+    pub or: Option<u32>,
+    pub select: Option<u32>,
+    pub ternary: Option<u32>,
+    pub catch: Option<u32>,
+}
+
+impl WeightsSection {
+    /// Helper: Set weights over `weights`
+    fn apply(&self, weights: &mut ComplexityWeights) {
+        let set = [
+            (Construct::If, self.if_), (Construct::For, self.for_), (Construct::Case, self.case),
+            (Construct::And, self.and), (Construct::Or, self.or), (Construct::Select, self.select),
+            (Construct::Ternary, self.ternary), (Construct::Catch, self.catch),
+        ];
+        for (construct, weight) in set {
+            if let Some(weight) = weight {
+                *weights.weight_mut(construct) = weight;
+            }
+        }
+    }
+}
+
+/// `[smells]`
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct SmellsSection {
+    /// Functions with more parameters than this are reported (default 5)
+    pub max_params: Option<usize>,
+    /// Report numeric literals used inline (default true)
+    pub magic_numbers: Option<bool>,
+    /// Values tolerated inline besides 0 and 1
+    pub allowed_numbers: Option<Vec<f64>>,
+    /// Functions spanning more lines than this are reported (default off)
+    pub max_function_loc: Option<u32>,
+}
+
+/// `[performance]`
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct PerformanceSection {
+    /// Files analyzed concurrently (0 = number of logical CPUs)
+    pub jobs: Option<usize>,
+    /// Files larger than this many bytes are skipped with a warning (0 = no limit)
+    pub max_file_size: Option<u64>,
+    /// Only this many leading bytes of a file are parsed (0 = no limit)
+    pub max_parse_bytes: Option<u64>,
+}
+
+/// `[cache]`
+#[derive(Debug, Clone, Default, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct CacheSection {
+    pub enabled: Option<bool>,
+    /// Cache directory, relative to the directory containing the config file
+    pub dir: Option<PathBuf>,
+}
+
+impl ProjectConfig {
+    /// Parse a config file, naming the file (and toml names the key) on errors
+    pub fn load(path: &Path) -> Result<Self> {
+        let content = fs::read_to_string(path)
+            .with_context(|| format!("Failed to read {}", path.display()))?;
+        let config: Self = toml::from_str(&content)
+            .with_context(|| format!("Invalid project config {}", path.display()))?;
+        config.validate()
+            .with_context(|| format!("Invalid project config {}", path.display()))?;
+        Ok(config)
+    }
+    
+    /// Find the nearest `nekocode.toml` at or above `start`
+    pub fn find(start: &Path) -> Option<PathBuf> {
+        let start = fs::canonicalize(start).ok()?;
+        let mut dir = if start.is_dir() { Some(start.as_path()) } else { start.parent() };
+        while let Some(current) = dir {
+            let candidate = current.join(PROJECT_CONFIG_FILE);
+            if candidate.is_file() {
+                return Some(candidate);
+            }
+            dir = current.parent();
+        }
+        None
+    }
+    
+    /// Discover and parse the config for `start`; returns the config root with it
+    pub fn discover(start: &Path) -> Result<Option<(PathBuf, Self)>> {
+        match Self::find(start) {
+            Some(file) => {
+                let config = Self::load(&file)?;
+                let root = file.parent().unwrap_or_else(|| Path::new(".")).to_path_buf();
+                Ok(Some((root, config)))
+            }
+            None => Ok(None),
+        }
+    }
+    
+    /// Helper: Reject globs that would only fail later during the walk
+    fn validate(&self) -> Result<()> {
+        for pattern in self.files.include.iter().chain(&self.files.exclude) {
+            Glob::new(pattern).with_context(|| format!("files: invalid glob `{}`", pattern))?;
+        }
+        Ok(())
+    }
+    
+    /// Layer the file settings over `config`; `root` is the config file's directory
+    pub fn apply(&self, root: &Path, config: &mut AnalysisConfig) {
+        if !self.files.include.is_empty() || !self.files.exclude.is_empty() {
+            config.include_globs = self.files.include.clone();
+            config.exclude_globs = self.files.exclude.clone();
+            config.glob_root = Some(root.to_path_buf());
+        }
+        if let Some(include_tests) = self.files.include_tests {
+            config.include_test_files = include_tests;
+        }
+        if let Some(respect) = self.files.respect_ignore_files {
+            config.respect_ignore_files = respect;
+        }
+        if let Some(follow) = self.files.follow_symlinks {
+            config.follow_symlinks = follow;
+        }
+        if let Some(exclude) = self.files.exclude_generated {
+            config.exclude_generated = exclude;
+        }
+        if !self.languages.enabled.is_empty() {
+            config.enabled_languages = self.languages.enabled.clone();
+        }
+        if self.metrics.max_cyclomatic.is_some() {
+            config.max_cyclomatic = self.metrics.max_cyclomatic;
+        }
+        if self.metrics.max_cognitive.is_some() {
+            config.max_cognitive = self.metrics.max_cognitive;
+        }
+        if let Some(weights) = &self.metrics.weights {
+            weights.apply(&mut config.complexity_weights);
+        }
+        if let Some(max_params) = self.smells.max_params {
+            config.smells.max_params = max_params;
+        }
+        if let Some(enabled) = self.smells.magic_numbers {
+            config.smells.magic_numbers = enabled;
+        }
+        if self.smells.max_function_loc.is_some() {
+            config.smells.max_function_loc = self.smells.max_function_loc;
+        }
+        if let Some(allowed) = &self.smells.allowed_numbers {
+            config.smells.allowed_numbers = allowed.clone();
+        }
+        if let Some(jobs) = self.performance.jobs {
+            config.max_threads = jobs;
+        }
+        if let Some(limit) = self.performance.max_file_size {
+            config.max_file_size = (limit > 0).then_some(limit);
+        }
+        if let Some(limit) = self.performance.max_parse_bytes {
+            config.max_parse_bytes = (limit > 0).then_some(limit);
+        }
+        if let Some(enabled) = self.cache.enabled {
+            config.cache_enabled = enabled;
+        }
+        if let Some(dir) = &self.cache.dir {
+            config.cache_dir = Some(root.join(dir));
+        }
+    }
+}
+
+/// Built-in defaults with the project file for `path` (if any) applied
+pub fn load_analysis_config(path: &Path) -> Result<AnalysisConfig> {
+    let mut config = AnalysisConfig::default();
+    if let Some((root, project)) = ProjectConfig::discover(path)? {
+        if std::env::var("NEKOCODE_DEBUG").is_ok() {
+            eprintln!("🗂️ [RUST] Using project config: {}", root.join(PROJECT_CONFIG_FILE).display());
+        }
+        project.apply(&root, &mut config);
+    }
+    Ok(config)
+}
+
+/// Compiled include / exclude globs of an [`AnalysisConfig`]
+pub struct PathFilter {
+    root: Option<PathBuf>,
+    include: Option<GlobSet>,
+    exclude: Option<GlobSet>,
+}
+
+impl PathFilter {
+    pub fn new(config: &AnalysisConfig) -> Result<Self> {
+        Ok(Self {
+            root: config.glob_root.as_ref().map(|root| fs::canonicalize(root).unwrap_or_else(|_| root.clone())),
+            include: build_set(&config.include_globs)?,
+            exclude: build_set(&config.exclude_globs)?,
+        })
+    }
+    
+    /// Whether a discovered file passes the globs
+    pub fn matches(&self, path: &Path) -> bool {
+        if self.include.is_none() && self.exclude.is_none() {
+            return true;
+        }
+        
+        let absolute = fs::canonicalize(path).unwrap_or_else(|_| path.to_path_buf());
+        let relative = match &self.root {
+            Some(root) => absolute.strip_prefix(root).unwrap_or(&absolute),
+            None => absolute.as_path(),
+        };
+        
+        if let Some(include) = &self.include {
+            if !include.is_match(relative) {
+                return false;
+            }
+        }
+        if let Some(exclude) = &self.exclude {
+            if exclude.is_match(relative) {
+                return false;
+            }
+        }
+        true
+    }
+}
+
+/// Compiled `language_overrides` of an [`AnalysisConfig`]
+pub struct LanguageMap {
+    root: Option<PathBuf>,
+    /// (glob, matches the file name only, language), in flag order
+    overrides: Vec<(GlobMatcher, bool, Language)>,
+}
+
+impl LanguageMap {
+    pub fn new(config: &AnalysisConfig) -> Result<Self> {
+        let mut overrides = Vec::new();
+        for entry in &config.language_overrides {
+            let glob = Glob::new(&entry.glob).with_context(|| format!("Invalid glob `{}`", entry.glob))?;
+            overrides.push((glob.compile_matcher(), !entry.glob.contains('/'), entry.language));
+        }
+        Ok(Self {
+            root: config.glob_root.as_ref().map(|root| fs::canonicalize(root).unwrap_or_else(|_| root.clone())),
+            overrides,
+        })
+    }
+    
+    /// Language of the first override matching `path`, if any
+    pub fn language_of(&self, path: &Path) -> Option<Language> {
+        if self.overrides.is_empty() {
+            return None;
+        }
+        
+        let file_name = Path::new(path.file_name()?);
+        let relative = match &self.root {
+            Some(root) => {
+                let absolute = fs::canonicalize(path).unwrap_or_else(|_| path.to_path_buf());
+                absolute.strip_prefix(root).map(Path::to_path_buf).unwrap_or(absolute)
+            }
+            None => path.to_path_buf(),
+        };
+        self.overrides.iter()
+            .find(|(glob, file_name_only, _)| glob.is_match(if *file_name_only { file_name } else { relative.as_path() }))
+            .map(|(_, _, language)| *language)
+    }
+}
+
+/// Helper: Compile globs, `None` when there are none
+fn build_set(patterns: &[String]) -> Result<Option<GlobSet>> {
+    if patterns.is_empty() {
+        return Ok(None);
+    }
+    
+    let mut builder = GlobSetBuilder::new();
+    for pattern in patterns {
+        builder.add(Glob::new(pattern).with_context(|| format!("Invalid glob `{}`", pattern))?);
+    }
+    Ok(Some(builder.build()?))
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::LanguageOverride;
+    
+    #[test]
+    fn test_parse_all_sections() {
+        let config: ProjectConfig = toml::from_str(r#"
+            [files]
+            include = ["src/**"]
+            exclude = ["**/gen/**"]
+            exclude_generated = true
+            
+            [languages]
+            enabled = ["go", "rust"]
+            
+            [metrics]
+            max_cyclomatic = 15
+            
+            [smells]
+            max_params = 7
+            allowed_numbers = [2, 100]
+            max_function_loc = 80
+            
+            [performance]
+            jobs = 4
+            max_file_size = 0
+            max_parse_bytes = 65536
+            
+            [cache]
+            enabled = true
+            dir = "cache"
+        "#).unwrap();
+        
+        assert_eq!(config.files.include, vec!["src/**"]);
+        assert_eq!(config.languages.enabled, vec![Language::Go, Language::Rust]);
+        assert_eq!(config.metrics.max_cyclomatic, Some(15));
+        assert_eq!(config.performance.jobs, Some(4));
+        
+        let mut analysis = AnalysisConfig::default();
+        config.apply(Path::new("/project"), &mut analysis);
+        assert_eq!(analysis.max_threads, 4);
+        assert_eq!(analysis.max_file_size, None);
+        assert_eq!(analysis.max_parse_bytes, Some(65536));
+        assert!(analysis.exclude_generated);
+        assert!(analysis.cache_enabled);
+        assert_eq!(analysis.cache_dir, Some(PathBuf::from("/project/cache")));
+        assert_eq!(analysis.glob_root, Some(PathBuf::from("/project")));
+        assert_eq!(analysis.max_cognitive, None);
+        assert_eq!(analysis.smells.max_params, 7);
+        assert_eq!(analysis.smells.max_function_loc, Some(80));
+        assert_eq!(analysis.smells.allowed_numbers, vec![2.0, 100.0]);
+        assert!(analysis.smells.magic_numbers);
+    }
+    
+    #[test]
+    fn test_unknown_key_is_named() {
+        let err = toml::from_str::<ProjectConfig>("[performance]\njbos = 4\n").unwrap_err();
+        assert!(err.to_string().contains("jbos"), "{}", err);
+        
+        let err = toml::from_str::<ProjectConfig>("[perf]\njobs = 4\n").unwrap_err();
+        assert!(err.to_string().contains("perf"), "{}", err);
+    }
+    
+    #[test]
+    fn test_unset_keys_keep_defaults() {
+        let config: ProjectConfig = toml::from_str("[metrics]\nmax_cognitive = 10\n").unwrap();
+        let mut analysis = AnalysisConfig::default();
+        analysis.max_threads = 2;
+        config.apply(Path::new("."), &mut analysis);
+        
+        assert_eq!(analysis.max_threads, 2);
+        assert_eq!(analysis.max_cognitive, Some(10));
+        assert!(analysis.enabled_languages.is_empty());
+        assert!(analysis.glob_root.is_none());
+        assert_eq!(analysis.complexity_weights, ComplexityWeights::STANDARD);
+    }
+    
+    #[test]
+    fn test_complexity_weights() {
+        let config: ProjectConfig = toml::from_str("[metrics.weights]
+if = 2
+case = 0
+").unwrap();
+        let mut analysis = AnalysisConfig::default();
+        config.apply(Path::new("."), &mut analysis);
+        
+        let weights = analysis.complexity_weights;
+        assert_eq!((weights.if_, weights.case, weights.for_, weights.catch), (2, 0, 1, 1));
+        assert!(toml::from_str::<ProjectConfig>("[metrics.weights]
+while = 2
+").is_err());
+    }
+    
+    #[test]
+    fn test_path_filter() {
+        let mut analysis = AnalysisConfig::default();
+        analysis.include_globs = vec!["src/**".to_string()];
+        analysis.exclude_globs = vec!["**/gen/**".to_string()];
+        analysis.glob_root = Some(PathBuf::from("/nonexistent-root"));
+        let filter = PathFilter::new(&analysis).unwrap();
+        
+        assert!(filter.matches(Path::new("/nonexistent-root/src/main.go")));
+        assert!(!filter.matches(Path::new("/nonexistent-root/src/gen/api.go")));
+        assert!(!filter.matches(Path::new("/nonexistent-root/tools/build.go")));
+    }
+    
+    #[test]
+    fn test_language_map() {
+        let mut analysis = AnalysisConfig::default();
+        analysis.language_overrides = vec![
+            LanguageOverride::parse("templates/**/*.tmpl=python").unwrap(),
+            LanguageOverride::parse("*.tmpl=go").unwrap(),
+            LanguageOverride::parse("BUILD = py").unwrap(),
+        ];
+        analysis.glob_root = Some(PathBuf::from("/nonexistent-root"));
+        let map = LanguageMap::new(&analysis).unwrap();
+        
+        // First matching glob wins; globs without `/` match the file name
+        assert_eq!(map.language_of(Path::new("/nonexistent-root/templates/mail/body.tmpl")), Some(Language::Python));
+        assert_eq!(map.language_of(Path::new("/nonexistent-root/web/page.tmpl")), Some(Language::Go));
+        assert_eq!(map.language_of(Path::new("/nonexistent-root/tools/BUILD")), Some(Language::Python));
+        assert_eq!(map.language_of(Path::new("/nonexistent-root/main.go")), None);
+        
+        assert!(LanguageOverride::parse("*.tmpl").is_none());
+        assert!(LanguageOverride::parse("=go").is_none());
+        assert!(LanguageOverride::parse("*.tmpl=cobol").is_none());
+    }
+}
@@ -0,0 +1,572 @@
+//! 🔎 Symbol query language (`--query`)
+//!
+//! A small boolean expression language over the functions, methods and classes
+//! of a file result:
+//!
+//! ```text
+//! complexity > 10 and visibility == public and lang == go
+//! name ~= "^Get" or (kind == method and not async)
+//! ```
+//!
+//! Comparisons are `==`, `!=`, `<`, `<=`, `>`, `>=` and `~=` (regex match);
+//! they combine with `and` / `&&`, `or` / `||`, `not` / `!` and parentheses.
+//! Values are numbers, quoted strings or bare words. A bare field tests for
+//! truth (`async`, `recursive`). Fields the built-in set does not know are
+//! looked up in the symbol's metadata (`return_type == int`); a comparison
+//! against a field a symbol does not have is false.
+//!
+//! `kind` is the normalized kind (`function`, `method`, `class`, `struct`,
+//! `interface`, `enum`, `trait`, `module`), the same in every language;
+//! `native_kind` is the language's own term (`func`, `protocol`, `object`).
+
+use anyhow::Result;
+use regex::Regex;
+use serde::{Deserialize, Deserializer, Serialize, Serializer};
+
+use crate::core::types::{AnalysisResult, ClassInfo, FunctionInfo, Language};
+use crate::core::visibility::{exported_names, is_public_class, is_public_function, is_public_method};
+
+/// A parsed `--query` expression
+#[derive(Debug, Clone)]
+pub struct SymbolQuery {
+    source: String,
+    expr: Expr,
+}
+
+/// Comparison operators
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+enum CompareOp {
+    Eq,
+    Ne,
+    Lt,
+    Le,
+    Gt,
+    Ge,
+    Matches,
+}
+
+/// Syntax tree of a query
+#[derive(Debug, Clone)]
+enum Expr {
+    And(Box<Expr>, Box<Expr>),
+    Or(Box<Expr>, Box<Expr>),
+    Not(Box<Expr>),
+    /// A bare field: true when present and truthy
+    Truthy(String),
+    Compare(String, CompareOp, Literal),
+}
+
+/// Right-hand side of a comparison
+#[derive(Debug, Clone)]
+enum Literal {
+    Number(f64),
+    Text(String),
+    Bool(bool),
+    /// `~=` patterns are compiled once at parse time
+    Pattern(Regex),
+}
+
+/// Value of a field on one symbol
+#[derive(Debug, Clone, PartialEq)]
+enum Value {
+    Number(f64),
+    Text(String),
+    Bool(bool),
+}
+
+/// Tokens of the query language
+#[derive(Debug, Clone, PartialEq)]
+enum Token {
+    Word(String),
+    Number(f64),
+    Text(String),
+    Op(CompareOp),
+    And,
+    Or,
+    Not,
+    LParen,
+    RParen,
+}
+
+/// One function, method or class as seen by a query
+pub struct SymbolRecord<'a> {
+    pub kind: &'static str,
+    pub language: Language,
+    pub file: String,
+    pub is_public: bool,
+    /// Owning class of a method
+    pub class: Option<&'a str>,
+    pub function: Option<&'a FunctionInfo>,
+    pub class_info: Option<&'a ClassInfo>,
+}
+
+impl SymbolQuery {
+    /// Parse a query expression
+    pub fn parse(source: &str) -> Result<Self> {
+        let tokens = tokenize(source)?;
+        if tokens.is_empty() {
+            anyhow::bail!("Empty query");
+        }
+
+        let mut parser = QueryParser { tokens, position: 0 };
+        let expr = parser.parse_or()?;
+        if let Some(token) = parser.peek() {
+            anyhow::bail!("Invalid query: unexpected {:?} at token {}", token, parser.position + 1);
+        }
+        Ok(Self { source: source.to_string(), expr })
+    }
+
+    /// The expression as written
+    pub fn as_str(&self) -> &str {
+        &self.source
+    }
+
+    /// Whether a symbol satisfies the query
+    pub fn matches(&self, symbol: &SymbolRecord) -> bool {
+        evaluate(&self.expr, symbol)
+    }
+
+    /// Keep only the functions and classes of a file result the query selects
+    ///
+    /// A matching class is kept whole. Otherwise only its matching methods are
+    /// kept, together with the class as their owner; a class without any goes away.
+    pub fn filter(&self, result: &mut AnalysisResult) {
+        let exported_names = exported_names(result);
+        let language = result.language;
+        let file = result.file_info.path.to_string_lossy().to_string();
+
+        result.functions.retain(|func| {
+            let class = func.metadata.get("class_name").or_else(|| func.metadata.get("receiver_type"));
+            let record = SymbolRecord {
+                kind: if class.is_some() { "method" } else { "function" },
+                language,
+                file: file.clone(),
+                is_public: is_public_function(language, func, exported_names.as_deref()),
+                class: class.map(|c| c.as_str()),
+                function: Some(func),
+                class_info: None,
+            };
+            self.matches(&record)
+        });
+
+        result.classes.retain_mut(|class| {
+            let record = SymbolRecord {
+                kind: class.kind.as_str(),
+                language,
+                file: file.clone(),
+                is_public: is_public_class(language, class, exported_names.as_deref()),
+                class: None,
+                function: None,
+                class_info: Some(class),
+            };
+            if self.matches(&record) {
+                return true;
+            }
+
+            let owner = class.name.clone();
+            class.methods.retain(|method| {
+                let record = SymbolRecord {
+                    kind: "method",
+                    language,
+                    file: file.clone(),
+                    is_public: is_public_method(language, method),
+                    class: Some(&owner),
+                    function: Some(method),
+                    class_info: None,
+                };
+                self.matches(&record)
+            });
+            !class.methods.is_empty()
+        });
+
+        result.update_statistics();
+    }
+}
+
+impl Serialize for SymbolQuery {
+    fn serialize<S: Serializer>(&self, serializer: S) -> std::result::Result<S::Ok, S::Error> {
+        serializer.serialize_str(&self.source)
+    }
+}
+
+impl<'de> Deserialize<'de> for SymbolQuery {
+    fn deserialize<D: Deserializer<'de>>(deserializer: D) -> std::result::Result<Self, D::Error> {
+        let source = String::deserialize(deserializer)?;
+        SymbolQuery::parse(&source).map_err(serde::de::Error::custom)
+    }
+}
+
+impl<'a> SymbolRecord<'a> {
+    /// Value of a field, `None` when the symbol does not have it
+    fn field(&self, name: &str) -> Option<Value> {
+        let number = |n: u32| Some(Value::Number(n as f64));
+        let func = self.function;
+        let class = self.class_info;
+
+        match name {
+            "name" => func.map(|f| f.name.clone()).or_else(|| class.map(|c| c.name.clone())).map(Value::Text),
+            "kind" => Some(Value::Text(self.kind.to_string())),
+            "native_kind" => func.map(|f| &f.native_kind).or_else(|| class.map(|c| &c.native_kind))
+                .filter(|kind| !kind.is_empty())
+                .map(|kind| Value::Text(kind.clone())),
+            "lang" | "language" => Some(Value::Text(self.language.as_str().to_string())),
+            "file" => Some(Value::Text(self.file.clone())),
+            "line" => func.map(|f| f.start_line).or_else(|| class.map(|c| c.start_line)).and_then(number),
+            "end_line" => func.map(|f| f.end_line).or_else(|| class.map(|c| c.end_line)).and_then(number),
+            "visibility" => Some(Value::Text(if self.is_public { "public" } else { "private" }.to_string())),
+            "class" => self.class.map(|c| Value::Text(c.to_string())),
+            "complexity" | "cyclomatic" => func.and_then(|f| number(f.complexity.cyclomatic_complexity)),
+            "cognitive" => func.and_then(|f| number(f.complexity.cognitive_complexity)),
+            "nesting" => func.and_then(|f| number(f.complexity.max_nesting_depth)),
+            "params" => func.and_then(|f| number(f.parameters.len() as u32)),
+            "loc" => func.and_then(|f| number(f.loc)),
+            "sloc" => func.and_then(|f| number(f.sloc)),
+            "async" => func.map(|f| Value::Bool(f.is_async)),
+            "recursive" => func.map(|f| Value::Bool(f.recursive)),
+            "methods" => class.and_then(|c| number(c.methods.len() as u32)),
+            "fields" => class.and_then(|c| number(c.member_variables.len() as u32)),
+            // Analyzer-specific metadata (`return_type`, `is_static`, ...)
+            _ => {
+                let metadata = func.map(|f| &f.metadata).or_else(|| class.map(|c| &c.metadata))?;
+                metadata.get(name).map(|value| match value.as_str() {
+                    "true" => Value::Bool(true),
+                    "false" => Value::Bool(false),
+                    _ => value.parse::<f64>().map(Value::Number).unwrap_or_else(|_| Value::Text(value.clone())),
+                })
+            }
+        }
+    }
+}
+
+/// Helper: Evaluate an expression against one symbol
+fn evaluate(expr: &Expr, symbol: &SymbolRecord) -> bool {
+    match expr {
+        Expr::And(left, right) => evaluate(left, symbol) && evaluate(right, symbol),
+        Expr::Or(left, right) => evaluate(left, symbol) || evaluate(right, symbol),
+        Expr::Not(inner) => !evaluate(inner, symbol),
+        Expr::Truthy(field) => match symbol.field(field) {
+            Some(Value::Bool(value)) => value,
+            Some(Value::Number(value)) => value != 0.0,
+            Some(Value::Text(value)) => !value.is_empty(),
+            None => false,
+        },
+        Expr::Compare(field, op, literal) => match symbol.field(field) {
+            Some(value) => compare(field, &value, *op, literal),
+            None => false,
+        },
+    }
+}
+
+/// Helper: Apply a comparison operator
+fn compare(field: &str, value: &Value, op: CompareOp, literal: &Literal) -> bool {
+    if let Literal::Pattern(pattern) = literal {
+        let text = match value {
+            Value::Text(text) => text.clone(),
+            Value::Number(number) => number.to_string(),
+            Value::Bool(flag) => flag.to_string(),
+        };
+        return pattern.is_match(&text);
+    }
+
+    let ordering = match (value, literal) {
+        (Value::Number(a), Literal::Number(b)) => a.partial_cmp(b),
+        (Value::Bool(a), Literal::Bool(b)) => Some(a.cmp(b)),
+        // `lang == golang` / `lang == ts`: accept the same aliases as --lang
+        (Value::Text(a), Literal::Text(b)) if field == "lang" || field == "language" => {
+            let b = Language::from_name(b).map(|l| l.as_str().to_string()).unwrap_or_else(|| b.to_lowercase());
+            Some(a.as_str().cmp(b.as_str()))
+        }
+        (Value::Text(a), Literal::Text(b)) => Some(a.as_str().cmp(b.as_str())),
+        // Numeric metadata compared with a quoted number, and the reverse
+        (Value::Text(a), Literal::Number(b)) => a.parse::<f64>().ok().and_then(|a| a.partial_cmp(b)),
+        (Value::Number(a), Literal::Text(b)) => b.parse::<f64>().ok().and_then(|b| a.partial_cmp(&b)),
+        _ => None,
+    };
+
+    let Some(ordering) = ordering else {
+        // Values of different types are never equal
+        return op == CompareOp::Ne;
+    };
+    match op {
+        CompareOp::Eq => ordering.is_eq(),
+        CompareOp::Ne => ordering.is_ne(),
+        CompareOp::Lt => ordering.is_lt(),
+        CompareOp::Le => ordering.is_le(),
+        CompareOp::Gt => ordering.is_gt(),
+        CompareOp::Ge => ordering.is_ge(),
+        CompareOp::Matches => false,
+    }
+}
+
+/// Helper: Recursive-descent parser over the token list
+struct QueryParser {
+    tokens: Vec<Token>,
+    position: usize,
+}
+
+impl QueryParser {
+    fn peek(&self) -> Option<&Token> {
+        self.tokens.get(self.position)
+    }
+
+    fn next(&mut self) -> Option<Token> {
+        let token = self.tokens.get(self.position).cloned();
+        self.position += 1;
+        token
+    }
+
+    /// or_expr := and_expr (`or` and_expr)*
+    fn parse_or(&mut self) -> Result<Expr> {
+        let mut expr = self.parse_and()?;
+        while self.peek() == Some(&Token::Or) {
+            self.position += 1;
+            expr = Expr::Or(Box::new(expr), Box::new(self.parse_and()?));
+        }
+        Ok(expr)
+    }
+
+    /// and_expr := unary (`and` unary)*
+    fn parse_and(&mut self) -> Result<Expr> {
+        let mut expr = self.parse_unary()?;
+        while self.peek() == Some(&Token::And) {
+            self.position += 1;
+            expr = Expr::And(Box::new(expr), Box::new(self.parse_unary()?));
+        }
+        Ok(expr)
+    }
+
+    /// unary := `not` unary | `(` or_expr `)` | comparison
+    fn parse_unary(&mut self) -> Result<Expr> {
+        match self.next() {
+            Some(Token::Not) => Ok(Expr::Not(Box::new(self.parse_unary()?))),
+            Some(Token::LParen) => {
+                let expr = self.parse_or()?;
+                match self.next() {
+                    Some(Token::RParen) => Ok(expr),
+                    _ => anyhow::bail!("Invalid query: missing ')'"),
+                }
+            }
+            Some(Token::Word(field)) => self.parse_comparison(field),
+            Some(token) => anyhow::bail!("Invalid query: expected a field name, found {:?}", token),
+            None => anyhow::bail!("Invalid query: expression ends early"),
+        }
+    }
+
+    /// comparison := field (op value)?
+    fn parse_comparison(&mut self, field: String) -> Result<Expr> {
+        let op = match self.peek() {
+            Some(Token::Op(op)) => *op,
+            _ => return Ok(Expr::Truthy(field)),
+        };
+        self.position += 1;
+
+        let literal = match self.next() {
+            Some(Token::Number(number)) if op != CompareOp::Matches => Literal::Number(number),
+            Some(Token::Word(word)) if op != CompareOp::Matches && (word == "true" || word == "false") => Literal::Bool(word == "true"),
+            Some(Token::Text(text)) | Some(Token::Word(text)) if op != CompareOp::Matches => Literal::Text(text),
+            Some(Token::Text(text)) | Some(Token::Word(text)) => {
+                let pattern = Regex::new(&text)
+                    .map_err(|e| anyhow::anyhow!("Invalid query: bad regex {:?}: {}", text, e))?;
+                Literal::Pattern(pattern)
+            }
+            Some(Token::Number(number)) => {
+                let pattern = Regex::new(&regex::escape(&number.to_string()))?;
+                Literal::Pattern(pattern)
+            }
+            _ => anyhow::bail!("Invalid query: missing value after comparison on '{}'", field),
+        };
+        Ok(Expr::Compare(field, op, literal))
+    }
+}
+
+/// Helper: Split a query into tokens
+fn tokenize(source: &str) -> Result<Vec<Token>> {
+    let chars: Vec<char> = source.chars().collect();
+    let mut tokens = Vec::new();
+    let mut i = 0;
+
+    while i < chars.len() {
+        let c = chars[i];
+        let next = chars.get(i + 1).copied();
+        match c {
+            _ if c.is_whitespace() => i += 1,
+            '(' => { tokens.push(Token::LParen); i += 1; }
+            ')' => { tokens.push(Token::RParen); i += 1; }
+            '&' if next == Some('&') => { tokens.push(Token::And); i += 2; }
+            '|' if next == Some('|') => { tokens.push(Token::Or); i += 2; }
+            '=' if next == Some('=') => { tokens.push(Token::Op(CompareOp::Eq)); i += 2; }
+            '!' if next == Some('=') => { tokens.push(Token::Op(CompareOp::Ne)); i += 2; }
+            '~' if next == Some('=') => { tokens.push(Token::Op(CompareOp::Matches)); i += 2; }
+            '<' if next == Some('=') => { tokens.push(Token::Op(CompareOp::Le)); i += 2; }
+            '>' if next == Some('=') => { tokens.push(Token::Op(CompareOp::Ge)); i += 2; }
+            '<' => { tokens.push(Token::Op(CompareOp::Lt)); i += 1; }
+            '>' => { tokens.push(Token::Op(CompareOp::Gt)); i += 1; }
+            '!' => { tokens.push(Token::Not); i += 1; }
+            '"' | '\'' => {
+                // Backslash escapes the quote and itself; regex escapes pass through
+                let quote = c;
+                let start = i;
+                let mut text = String::new();
+                i += 1;
+                loop {
+                    match chars.get(i) {
+                        None => anyhow::bail!("Invalid query: unterminated string starting at column {}", start + 1),
+                        Some('\\') if matches!(chars.get(i + 1), Some(&q) if q == quote || q == '\\') => {
+                            text.push(chars[i + 1]);
+                            i += 2;
+                        }
+                        Some(&ch) if ch == quote => {
+                            i += 1;
+                            break;
+                        }
+                        Some(&ch) => {
+                            text.push(ch);
+                            i += 1;
+                        }
+                    }
+                }
+                tokens.push(Token::Text(text));
+            }
+            _ if c.is_ascii_digit() || (c == '-' && next.map_or(false, |n| n.is_ascii_digit())) => {
+                let start = i;
+                i += 1;
+                while i < chars.len() && (chars[i].is_ascii_digit() || chars[i] == '.') {
+                    i += 1;
+                }
+                let text: String = chars[start..i].iter().collect();
+                let number = text.parse::<f64>()
+                    .map_err(|_| anyhow::anyhow!("Invalid query: bad number {}", text))?;
+                tokens.push(Token::Number(number));
+            }
+            _ if c.is_alphanumeric() || c == '_' => {
+                let start = i;
+                while i < chars.len() && (chars[i].is_alphanumeric() || matches!(chars[i], '_' | '.' | '-' | '/' | '#' | '+')) {
+                    i += 1;
+                }
+                let word: String = chars[start..i].iter().collect();
+                tokens.push(match word.to_lowercase().as_str() {
+                    "and" => Token::And,
+                    "or" => Token::Or,
+                    "not" => Token::Not,
+                    _ => Token::Word(word),
+                });
+            }
+            _ => anyhow::bail!("Invalid query: unexpected character '{}' at column {}", c, i + 1),
+        }
+    }
+
+    Ok(tokens)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::FileInfo;
+    use std::path::PathBuf;
+
+    fn function(name: &str, complexity: u32) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.complexity.cyclomatic_complexity = complexity;
+        func
+    }
+
+    fn go_result() -> AnalysisResult {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("server.go")), Language::Go);
+        result.functions.push(function("GetUser", 12));
+        result.functions.push(function("getCache", 15));
+        result.functions.push(function("Serve", 3));
+        let mut run = function("Run", 11);
+        run.is_async = true;
+        run.metadata.insert("return_type".to_string(), "error".to_string());
+        result.functions.push(run);
+
+        let mut server = ClassInfo::new("Server".to_string());
+        server.methods.push(function("Start", 2));
+        server.methods.push(function("GetConfig", 20));
+        result.classes.push(server);
+        result.classes.push(ClassInfo::new("Getter".to_string()));
+        result
+    }
+
+    fn filtered(query: &str) -> (Vec<String>, Vec<(String, Vec<String>)>) {
+        let mut result = go_result();
+        SymbolQuery::parse(query).unwrap().filter(&mut result);
+        (
+            result.functions.iter().map(|f| f.name.clone()).collect(),
+            result.classes.iter().map(|c| (c.name.clone(), c.methods.iter().map(|m| m.name.clone()).collect())).collect(),
+        )
+    }
+
+    #[test]
+    fn test_comparison_and_boolean_operators() {
+        let (functions, _) = filtered("complexity > 10 and visibility == public and lang == go");
+        assert_eq!(functions, vec!["GetUser", "Run"]);
+
+        let (functions, _) = filtered("complexity <= 3 || name == getCache");
+        assert_eq!(functions, vec!["getCache", "Serve"]);
+
+        let (functions, _) = filtered("not (complexity >= 11) && kind != method");
+        assert_eq!(functions, vec!["Serve"]);
+    }
+
+    #[test]
+    fn test_regex_and_truthy_fields() {
+        let (functions, classes) = filtered(r#"name ~= "^Get""#);
+        assert_eq!(functions, vec!["GetUser"]);
+        // Getter matches whole; Server stays as the owner of GetConfig
+        assert_eq!(classes, vec![
+            ("Server".to_string(), vec!["GetConfig".to_string()]),
+            ("Getter".to_string(), vec![]),
+        ]);
+
+        let (functions, classes) = filtered("async and return_type == error");
+        assert_eq!(functions, vec!["Run"]);
+        assert!(classes.is_empty());
+    }
+
+    #[test]
+    fn test_class_fields_and_aliases() {
+        let (functions, classes) = filtered("kind == class and methods > 0");
+        assert!(functions.is_empty());
+        assert_eq!(classes, vec![("Server".to_string(), vec!["Start".to_string(), "GetConfig".to_string()])]);
+
+        let (functions, _) = filtered("lang == golang and complexity == 3");
+        assert_eq!(functions, vec!["Serve"]);
+        let (functions, _) = filtered("lang == rust");
+        assert!(functions.is_empty());
+    }
+
+    #[test]
+    fn test_normalized_kinds() {
+        let mut result = go_result();
+        result.classes[1].metadata.insert("type".to_string(), "interface".to_string());
+        crate::core::kinds::annotate_kinds(&mut result);
+        SymbolQuery::parse("kind == interface or native_kind == func").unwrap().filter(&mut result);
+
+        let functions: Vec<&str> = result.functions.iter().map(|f| f.name.as_str()).collect();
+        assert_eq!(functions, vec!["GetUser", "getCache", "Serve", "Run"]);
+        let classes: Vec<&str> = result.classes.iter().map(|c| c.name.as_str()).collect();
+        assert_eq!(classes, vec!["Getter"]);
+    }
+
+    #[test]
+    fn test_parse_errors() {
+        assert!(SymbolQuery::parse("").is_err());
+        assert!(SymbolQuery::parse("complexity >").is_err());
+        assert!(SymbolQuery::parse("(complexity > 1").is_err());
+        assert!(SymbolQuery::parse("name ~= \"[\"").is_err());
+        assert!(SymbolQuery::parse("name == \"open").is_err());
+        assert!(SymbolQuery::parse("complexity > 1 complexity").is_err());
+        assert!(SymbolQuery::parse("complexity $ 1").is_err());
+    }
+
+    #[test]
+    fn test_serde_round_trip() {
+        let query = SymbolQuery::parse("complexity > 10").unwrap();
+        let json = serde_json::to_string(&query).unwrap();
+        assert_eq!(json, "\"complexity > 10\"");
+        let parsed: SymbolQuery = serde_json::from_str(&json).unwrap();
+        assert_eq!(parsed.as_str(), "complexity > 10");
+        assert!(serde_json::from_str::<SymbolQuery>("\"complexity >\"").is_err());
+    }
+}
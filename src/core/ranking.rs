@@ -0,0 +1,256 @@
+//! 🏆 Ranked output (`--sort-by`, `--top`)
+//!
+//! Orders functions and methods by one metric, worst first, and optionally
+//! keeps only the first N, so `--sort-by complexity --top 20` reads as a
+//! review list. Equal values fall back to file, then line, so the order is the
+//! same on every run. `analyze`, `stats` and `duplicates` share the keys.
+
+use std::cmp::Ordering;
+use std::collections::{HashMap, HashSet};
+use std::path::Path;
+
+use crate::core::types::{AnalysisResult, DirectoryAnalysis, FunctionInfo};
+
+/// Metric a list is ordered by
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum SortKey {
+    /// Cyclomatic, then cognitive complexity, highest first
+    Complexity,
+    /// Physical lines, longest first
+    Loc,
+    /// Callers within the analyzed set, most first
+    FanIn,
+    /// Name, alphabetically
+    Name,
+}
+
+impl SortKey {
+    /// Parse a `--sort-by` value
+    pub fn parse(value: &str) -> Option<Self> {
+        match value.to_lowercase().replace('-', "_").as_str() {
+            "complexity" => Some(Self::Complexity),
+            "loc" => Some(Self::Loc),
+            "fan_in" | "fanin" => Some(Self::FanIn),
+            "name" => Some(Self::Name),
+            _ => None,
+        }
+    }
+
+    /// Order of two symbols: `a` first when it ranks higher
+    pub fn compare(self, a: &RankedSymbol, b: &RankedSymbol) -> Ordering {
+        let primary = match self {
+            Self::Complexity => b.cyclomatic_complexity.cmp(&a.cyclomatic_complexity)
+                .then_with(|| b.cognitive_complexity.cmp(&a.cognitive_complexity)),
+            Self::Loc => b.loc.cmp(&a.loc),
+            Self::FanIn => b.fan_in.cmp(&a.fan_in),
+            Self::Name => a.name.cmp(b.name),
+        };
+        primary.then_with(|| (a.file, a.line).cmp(&(b.file, b.line)))
+    }
+}
+
+/// The values a symbol is ranked on
+#[derive(Debug, Clone, Copy)]
+pub struct RankedSymbol<'a> {
+    pub file: &'a Path,
+    pub line: u32,
+    pub name: &'a str,
+    pub cyclomatic_complexity: u32,
+    pub cognitive_complexity: u32,
+    pub loc: u32,
+    pub fan_in: u32,
+}
+
+impl<'a> RankedSymbol<'a> {
+    /// Ranking values of a function defined in `file`
+    pub fn of_function(file: &'a Path, func: &'a FunctionInfo) -> Self {
+        Self {
+            file,
+            line: func.start_line,
+            name: &func.name,
+            cyclomatic_complexity: func.complexity.cyclomatic_complexity,
+            cognitive_complexity: func.complexity.cognitive_complexity,
+            loc: func.loc.max(func.end_line.saturating_sub(func.start_line) + 1),
+            fan_in: func.fan_in,
+        }
+    }
+}
+
+/// A report entry that can be ranked
+pub trait Rankable {
+    fn ranked(&self) -> RankedSymbol<'_>;
+}
+
+/// Sort `items` by `key` (`None` keeps their order), then keep the first `top`
+pub fn rank<T: Rankable>(items: &mut Vec<T>, key: Option<SortKey>, top: Option<usize>) {
+    if let Some(key) = key {
+        items.sort_by(|a, b| key.compare(&a.ranked(), &b.ranked()));
+    }
+    if let Some(top) = top {
+        items.truncate(top);
+    }
+}
+
+/// `--sort-by` / `--top` as given on the command line
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub struct Ranking {
+    /// `None` keeps each command's own order (complexity for `analyze`)
+    pub sort_by: Option<SortKey>,
+    pub top: Option<usize>,
+}
+
+impl Ranking {
+    /// Whether either option was given
+    pub fn is_active(&self) -> bool {
+        self.sort_by.is_some() || self.top.is_some()
+    }
+
+    /// Rank the functions and methods of an analysis
+    ///
+    /// Each file lists its functions (and each class its methods) in rank
+    /// order, and files come in the order of their best-ranked function. With
+    /// `top`, functions below the cut are removed, classes stay only as owners
+    /// of kept methods, and files left without functions are dropped.
+    pub fn apply(&self, analysis: &mut DirectoryAnalysis) {
+        let best = self.rank_functions(&mut analysis.files);
+
+        let mut files: Vec<(Option<usize>, AnalysisResult)> = best.into_iter()
+            .zip(std::mem::take(&mut analysis.files))
+            .filter(|(best, _)| self.top.is_none() || best.is_some())
+            .collect();
+        // Stable: files without functions keep their path order, last
+        files.sort_by_key(|(best, _)| best.unwrap_or(usize::MAX));
+        analysis.files = files.into_iter().map(|(_, file)| file).collect();
+
+        if self.top.is_some() {
+            analysis.update_summary();
+        }
+    }
+
+    /// Rank the functions and methods of a single file result
+    pub fn apply_to_file(&self, result: &mut AnalysisResult) {
+        self.rank_functions(std::slice::from_mut(result));
+    }
+
+    /// Helper: Sort (and cut) the functions of every file; best rank per file
+    fn rank_functions(&self, files: &mut [AnalysisResult]) -> Vec<Option<usize>> {
+        let key = self.sort_by.unwrap_or(SortKey::Complexity);
+
+        // Some analyzers list methods both at file level and under their class
+        let mut ranks: HashMap<(usize, u32, String), usize> = HashMap::new();
+        {
+            let mut seen = HashSet::new();
+            let mut symbols: Vec<(usize, RankedSymbol)> = Vec::new();
+            for (index, file) in files.iter().enumerate() {
+                let methods = file.classes.iter().flat_map(|class| class.methods.iter());
+                for func in file.functions.iter().chain(methods) {
+                    if seen.insert((index, func.start_line, func.name.as_str())) {
+                        symbols.push((index, RankedSymbol::of_function(&file.file_info.path, func)));
+                    }
+                }
+            }
+            symbols.sort_by(|a, b| key.compare(&a.1, &b.1));
+            if let Some(top) = self.top {
+                symbols.truncate(top);
+            }
+            for (rank, (index, symbol)) in symbols.into_iter().enumerate() {
+                ranks.insert((index, symbol.line, symbol.name.to_string()), rank);
+            }
+        }
+
+        let mut best = Vec::with_capacity(files.len());
+        for (index, file) in files.iter_mut().enumerate() {
+            let rank_of = |func: &FunctionInfo| ranks.get(&(index, func.start_line, func.name.clone())).copied();
+
+            file.functions.retain(|func| rank_of(func).is_some());
+            file.functions.sort_by_key(|func| rank_of(func));
+            for class in &mut file.classes {
+                class.methods.retain(|method| rank_of(method).is_some());
+                class.methods.sort_by_key(|method| rank_of(method));
+            }
+            if self.top.is_some() {
+                file.classes.retain(|class| !class.methods.is_empty());
+                file.update_statistics();
+            }
+
+            let methods = file.classes.iter().flat_map(|class| class.methods.iter());
+            best.push(file.functions.iter().chain(methods).filter_map(|func| rank_of(func)).min());
+        }
+        best
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ClassInfo, FileInfo, Language};
+    use std::path::PathBuf;
+
+    fn function(name: &str, line: u32, complexity: u32, fan_in: u32) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = line;
+        func.end_line = line + complexity;
+        func.complexity.cyclomatic_complexity = complexity;
+        func.fan_in = fan_in;
+        func
+    }
+
+    fn analysis() -> DirectoryAnalysis {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        let mut a = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/a.go")), Language::Go);
+        a.functions = vec![function("parse", 3, 7, 0), function("small", 20, 1, 5)];
+        let mut b = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/b.go")), Language::Go);
+        b.functions = vec![function("run", 1, 7, 1), function("method", 10, 12, 0)];
+        let mut class = ClassInfo::new("Server".to_string());
+        class.methods.push(function("method", 10, 12, 0));
+        b.classes.push(class);
+        let c = AnalysisResult::new(FileInfo::new(PathBuf::from("/repo/c.go")), Language::Go);
+        analysis.files = vec![a, b, c];
+        analysis
+    }
+
+    fn names(analysis: &DirectoryAnalysis) -> Vec<(&str, Vec<&str>)> {
+        analysis.files.iter()
+            .map(|f| (f.file_info.name.as_str(), f.functions.iter().map(|func| func.name.as_str()).collect()))
+            .collect()
+    }
+
+    #[test]
+    fn test_sort_key_parse() {
+        assert_eq!(SortKey::parse("fan-in"), Some(SortKey::FanIn));
+        assert_eq!(SortKey::parse("LOC"), Some(SortKey::Loc));
+        assert_eq!(SortKey::parse("size"), None);
+    }
+
+    #[test]
+    fn test_sort_without_top_keeps_everything() {
+        let mut analysis = analysis();
+        Ranking { sort_by: Some(SortKey::Complexity), top: None }.apply(&mut analysis);
+
+        // parse and run tie at 7: a.go comes first; c.go has no functions
+        assert_eq!(names(&analysis), vec![
+            ("b.go", vec!["method", "run"]),
+            ("a.go", vec!["parse", "small"]),
+            ("c.go", vec![]),
+        ]);
+        assert_eq!(analysis.files[0].classes[0].methods.len(), 1);
+    }
+
+    #[test]
+    fn test_top_cuts_across_files() {
+        let mut analysis = analysis();
+        Ranking { sort_by: None, top: Some(2) }.apply(&mut analysis);
+        assert_eq!(names(&analysis), vec![("b.go", vec!["method"]), ("a.go", vec!["parse"])]);
+        // Server stays as the owner of its kept method
+        assert_eq!(analysis.files[0].classes.len(), 1);
+        assert_eq!(analysis.summary.total_functions, 2);
+
+        let mut analysis = self::analysis();
+        Ranking { sort_by: Some(SortKey::FanIn), top: Some(1) }.apply(&mut analysis);
+        assert_eq!(names(&analysis), vec![("a.go", vec!["small"])]);
+
+        let mut analysis = self::analysis();
+        Ranking { sort_by: Some(SortKey::Name), top: Some(3) }.apply(&mut analysis);
+        assert_eq!(names(&analysis), vec![("b.go", vec!["method", "run"]), ("a.go", vec!["parse"])]);
+    }
+}
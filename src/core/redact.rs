@@ -0,0 +1,408 @@
+//! 🕶️ Redacted output (`--redact`, `--redact-names`)
+//!
+//! For sharing analysis artifacts without sharing the code. `--redact` removes
+//! every piece of source text the analyzers copy into a result: comment
+//! contents, marker text, Go variable initializers, literals (annotation
+//! arguments and struct tags, parameter defaults, magic number values, enum raw
+//! values, Python decorator text), the text of syntax errors, AST node source
+//! and the code context of impact references. Names, kinds, lines, metrics and relations are kept.
+//!
+//! `--redact-names` also replaces each identifier in a symbol name with a
+//! short hash (`Server.Start` -> `n1c2f0b9a.n77d03e41`). The hash is the same
+//! everywhere, so calls, fan-in, hierarchies, gate findings and baselines
+//! still line up. File and module paths, the types in `params` and the
+//! structural metadata (`type`, `visibility`, flags, ...) are kept; the AST
+//! tree, which is mostly names, is dropped.
+
+use crate::core::ast::ASTNode;
+use crate::core::impact::ImpactAnalysisResult;
+use crate::core::types::{
+    AnalysisResult, Annotation, ClassInfo, DirectoryAnalysis, FunctionInfo, SmellInfo,
+};
+
+/// Metadata removed by `--redact`: raw source text
+const SOURCE_METADATA: &[&str] = &["decorators", "raw_value"];
+
+/// Metadata kept by `--redact-names`: values that hold no project names
+const STRUCTURAL_METADATA: &[&str] = &[
+    "type", "visibility", "modifiers", "kind", "import_kind", "static_kind", "binding",
+    "layout", "annotations", "build_tags", "declared_at", "notebook_location", "notebook_code_cells",
+];
+
+/// `--redact` / `--redact-names` as given on the command line
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub struct Redaction {
+    /// Remove source text
+    pub source: bool,
+    /// Also hash symbol names (implies `source`)
+    pub names: bool,
+}
+
+impl Redaction {
+    /// Whether either option was given
+    pub fn is_active(&self) -> bool {
+        self.source || self.names
+    }
+
+    /// Redact every file of an analysis
+    pub fn apply(&self, analysis: &mut DirectoryAnalysis) {
+        for file in &mut analysis.files {
+            self.apply_to_file(file);
+        }
+        if self.names {
+            for package in &mut analysis.package_inits {
+                package.package = redact_name(&package.package);
+                for step in package.variables.iter_mut().chain(package.init_functions.iter_mut()) {
+                    step.name = redact_name(&step.name);
+                }
+            }
+        }
+    }
+
+    /// Redact a single file result
+    pub fn apply_to_file(&self, result: &mut AnalysisResult) {
+        if !self.is_active() {
+            return;
+        }
+
+        for comment in &mut result.commented_lines {
+            comment.content.clear();
+        }
+        for marker in &mut result.markers {
+            marker.text.clear();
+            if self.names {
+                marker.function = marker.function.as_deref().map(redact_name);
+            }
+        }
+        for error in &mut result.parse_errors {
+            error.text.clear();
+        }
+        for variable in &mut result.variables {
+            variable.initializer.clear();
+        }
+        if let Some(root) = &mut result.ast_root {
+            clear_source_text(root);
+        }
+        for func in &mut result.functions {
+            self.redact_function(func);
+        }
+        for class in &mut result.classes {
+            self.redact_class(class);
+        }
+
+        if self.names {
+            redact_file_names(result);
+        }
+    }
+
+    /// Redact an impact analysis: reference context and signatures, and names with `names`
+    pub fn apply_to_impact(&self, result: &mut ImpactAnalysisResult) {
+        if !self.is_active() {
+            return;
+        }
+
+        for symbol in &mut result.changed_symbols {
+            symbol.signature_before = None;
+            symbol.signature_after = None;
+            for reference in &mut symbol.references {
+                reference.context.clear();
+            }
+            if self.names {
+                symbol.name = redact_name(&symbol.name);
+            }
+        }
+    }
+
+    /// Helper: Redact a function, method or nested function
+    fn redact_function(&self, func: &mut FunctionInfo) {
+        for parameter in &mut func.parameters {
+            *parameter = without_default(parameter);
+        }
+        redact_annotations(&mut func.annotations);
+        for smell in &mut func.smells {
+            if smell.kind == SmellInfo::MAGIC_NUMBER {
+                smell.value.clear();
+                smell.message = "magic number".to_string();
+            }
+        }
+        for key in SOURCE_METADATA {
+            func.metadata.remove(*key);
+        }
+
+        if self.names {
+            func.name = redact_name(&func.name);
+            for parameter in &mut func.parameters {
+                *parameter = redact_name(parameter);
+            }
+            for param in &mut func.params {
+                param.name = redact_name(&param.name);
+            }
+            for name in &mut func.return_names {
+                *name = redact_name(name);
+            }
+            for local in &mut func.unused_locals {
+                local.name = redact_name(&local.name);
+            }
+            for goroutine in &mut func.goroutines {
+                goroutine.expression = redact_name(&goroutine.expression);
+            }
+            for defer in &mut func.defers {
+                defer.expression = redact_name(&defer.expression);
+            }
+            for channel in &mut func.channels {
+                channel.channel = redact_name(&channel.channel);
+            }
+            for assertion in &mut func.type_assertions {
+                assertion.type_name = redact_name(&assertion.type_name);
+            }
+            func.namespace = func.namespace.as_deref().map(redact_name);
+            redact_metadata_names(&mut func.metadata);
+        }
+
+        for child in &mut func.children {
+            self.redact_function(child);
+        }
+    }
+
+    /// Helper: Redact a type, its methods and its member variables
+    fn redact_class(&self, class: &mut ClassInfo) {
+        redact_annotations(&mut class.annotations);
+        for key in SOURCE_METADATA {
+            class.metadata.remove(*key);
+        }
+        for member in &mut class.member_variables {
+            redact_annotations(&mut member.annotations);
+            for key in SOURCE_METADATA {
+                member.metadata.remove(*key);
+            }
+        }
+        for method in &mut class.methods {
+            self.redact_function(method);
+        }
+
+        if self.names {
+            class.name = redact_name(&class.name);
+            class.parent_class = class.parent_class.as_deref().map(redact_name);
+            for name in class.properties.iter_mut()
+                .chain(class.implements.iter_mut())
+                .chain(class.implementors.iter_mut())
+                .chain(class.embeds.iter_mut())
+            {
+                *name = redact_name(name);
+            }
+            for member in &mut class.member_variables {
+                member.name = redact_name(&member.name);
+                for method in member.used_by_methods.iter_mut().chain(member.modified_by_methods.iter_mut()) {
+                    *method = redact_name(method);
+                }
+                redact_metadata_names(&mut member.metadata);
+            }
+            class.namespace = class.namespace.as_deref().map(redact_name);
+            redact_metadata_names(&mut class.metadata);
+        }
+    }
+}
+
+/// Replace each identifier in `name` with a short hash, keeping the punctuation
+/// (`Server.Start`, `geo::Shape`, `*Server`, `main$func1`)
+pub fn redact_name(name: &str) -> String {
+    let mut redacted = String::with_capacity(name.len());
+    let mut word = String::new();
+    for c in name.chars().chain(std::iter::once('\0')) {
+        if c.is_alphanumeric() || c == '_' {
+            word.push(c);
+            continue;
+        }
+        if !word.is_empty() {
+            redacted.push('n');
+            redacted.push_str(&blake3::hash(word.as_bytes()).to_hex()[..8]);
+            word.clear();
+        }
+        if c != '\0' {
+            redacted.push(c);
+        }
+    }
+    redacted
+}
+
+/// Helper: Hash the names a file refers to outside its own symbols
+fn redact_file_names(result: &mut AnalysisResult) {
+    for call in &mut result.function_calls {
+        call.function_name = redact_name(&call.function_name);
+        call.object_name = call.object_name.as_deref().map(redact_name);
+        call.receiver_type = call.receiver_type.as_deref().map(redact_name);
+    }
+    result.call_frequency = std::mem::take(&mut result.call_frequency).into_iter()
+        .map(|(name, count)| (redact_name(&name), count))
+        .collect();
+    for import in &mut result.imports {
+        for name in &mut import.imported_names {
+            *name = redact_name(name);
+        }
+        import.alias = import.alias.as_deref().map(redact_name);
+    }
+    for export in &mut result.exports {
+        for name in &mut export.exported_names {
+            *name = redact_name(name);
+        }
+    }
+    for declaration in &mut result.declarations {
+        declaration.name = redact_name(&declaration.name);
+        declaration.signature = redact_name(&declaration.signature);
+    }
+    for unchecked in &mut result.unchecked_errors {
+        unchecked.callee = redact_name(&unchecked.callee);
+    }
+    for variable in &mut result.variables {
+        variable.name = redact_name(&variable.name);
+        for name in &mut variable.depends_on {
+            *name = redact_name(name);
+        }
+    }
+    for init in &mut result.init_functions {
+        for call in &mut init.calls {
+            *call = redact_name(call);
+        }
+    }
+    result.ast_root = None;
+}
+
+/// Helper: A parameter as written without its default value (`timeout=30` -> `timeout`)
+///
+/// `=>` in a function type (`cb: () => void`) is not a default.
+fn without_default(parameter: &str) -> String {
+    let bytes = parameter.as_bytes();
+    let default = bytes.iter().enumerate().position(|(i, &b)| {
+        b == b'='
+            && bytes.get(i + 1).map_or(true, |next| *next != b'>' && *next != b'=')
+            && !matches!(i.checked_sub(1).map(|prev| bytes[prev]), Some(b'=' | b'!' | b'<' | b'>'))
+    });
+    match default {
+        Some(index) => parameter[..index].trim_end().to_string(),
+        None => parameter.to_string(),
+    }
+}
+
+/// Helper: Drop annotation arguments, which are mostly literals
+fn redact_annotations(annotations: &mut [Annotation]) {
+    for annotation in annotations {
+        annotation.arguments.clear();
+    }
+}
+
+/// Helper: Hash the metadata values that may hold names
+fn redact_metadata_names(metadata: &mut std::collections::HashMap<String, String>) {
+    for (key, value) in metadata.iter_mut() {
+        let structural = STRUCTURAL_METADATA.contains(&key.as_str())
+            || value == "true"
+            || value == "false"
+            || value.parse::<f64>().is_ok();
+        if !structural {
+            *value = redact_name(value);
+        }
+    }
+}
+
+/// Helper: Remove the source text of an AST node and its descendants
+fn clear_source_text(node: &mut ASTNode) {
+    node.source_text = None;
+    for child in &mut node.children {
+        clear_source_text(child);
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{CommentInfo, FileInfo, Language, MarkerInfo, MemberVariable};
+    use std::path::PathBuf;
+
+    fn sample() -> AnalysisResult {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("app.py")), Language::Python);
+        result.commented_lines.push(CommentInfo::new(1, 1, "line".to_string(), "# secret sauce".to_string()));
+        result.markers.push(MarkerInfo {
+            kind: "TODO".to_string(),
+            line: 2,
+            text: "rotate the API key".to_string(),
+            function: Some("Service.load".to_string()),
+        });
+
+        let mut load = FunctionInfo::new("load".to_string());
+        load.parameters = vec!["self".to_string(), "token='hunter2'".to_string()];
+        load.annotations.push(Annotation::new("route", "(\"/internal\")", 3));
+        load.smells.push(SmellInfo {
+            kind: SmellInfo::MAGIC_NUMBER.to_string(),
+            line: 5,
+            value: "8675309".to_string(),
+            message: "magic number 8675309".to_string(),
+        });
+        load.metadata.insert("decorators".to_string(), "@route(\"/internal\")".to_string());
+        load.metadata.insert("class_name".to_string(), "Service".to_string());
+        load.metadata.insert("is_method".to_string(), "true".to_string());
+        load.complexity.cyclomatic_complexity = 4;
+
+        let mut service = ClassInfo::new("Service".to_string());
+        service.parent_class = Some("Base".to_string());
+        let mut field = MemberVariable::new("name".to_string(), "string".to_string(), 8);
+        field.annotations.push(Annotation::new("json", "name,omitempty", 8));
+        field.used_by_methods.push("load".to_string());
+        service.member_variables.push(field);
+        service.methods.push(load.clone());
+        result.classes.push(service);
+        result.functions.push(load);
+        result
+    }
+
+    #[test]
+    fn test_redact_source() {
+        let mut result = sample();
+        Redaction { source: true, names: false }.apply_to_file(&mut result);
+
+        assert_eq!(result.commented_lines[0].content, "");
+        assert_eq!(result.commented_lines[0].line_start, 1);
+        assert_eq!(result.markers[0].text, "");
+        assert_eq!(result.markers[0].function.as_deref(), Some("Service.load"));
+
+        let load = &result.functions[0];
+        assert_eq!(load.name, "load");
+        assert_eq!(load.parameters, vec!["self", "token"]);
+        assert_eq!(without_default("cb: () => void = noop"), "cb: () => void");
+        assert_eq!(load.annotations[0].name, "route");
+        assert_eq!(load.annotations[0].arguments, "");
+        assert_eq!((load.smells[0].value.as_str(), load.smells[0].message.as_str()), ("", "magic number"));
+        assert!(!load.metadata.contains_key("decorators"));
+        assert_eq!(load.complexity.cyclomatic_complexity, 4);
+        assert_eq!(result.classes[0].member_variables[0].annotations[0].arguments, "");
+    }
+
+    #[test]
+    fn test_redact_names_consistently() {
+        let mut result = sample();
+        Redaction { source: true, names: true }.apply_to_file(&mut result);
+
+        let load = redact_name("load");
+        let service = redact_name("Service");
+        assert!(load.starts_with('n') && load.len() == 9);
+        assert_eq!(redact_name("Service.load"), format!("{}.{}", service, load));
+        assert_eq!(redact_name("*Service"), format!("*{}", service));
+
+        assert_eq!(result.functions[0].name, load);
+        assert_eq!(result.functions[0].parameters, vec![redact_name("self"), redact_name("token")]);
+        assert_eq!(result.functions[0].metadata["class_name"], service);
+        assert_eq!(result.functions[0].metadata["is_method"], "true");
+        let class = &result.classes[0];
+        assert_eq!(class.name, service);
+        assert_eq!(class.parent_class, Some(redact_name("Base")));
+        assert_eq!(class.methods[0].name, load);
+        assert_eq!(class.member_variables[0].used_by_methods, vec![load.clone()]);
+        assert_eq!(result.markers[0].function, Some(format!("{}.{}", service, load)));
+    }
+
+    #[test]
+    fn test_inactive_redaction_keeps_everything() {
+        let mut result = sample();
+        Redaction::default().apply_to_file(&mut result);
+        assert_eq!(result.commented_lines[0].content, "# secret sauce");
+        assert_eq!(result.functions[0].parameters[1], "token='hunter2'");
+    }
+}
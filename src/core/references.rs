@@ -0,0 +1,287 @@
+//! 🔎 Every reference to one symbol (`find-references`)
+//!
+//! The symbol is named `name`, `Owner.name` (class or receiver type) or
+//! `namespace.name` (`proc.NewProcessor`, `app.models.User.save`); `::` works
+//! as a separator too. When several definitions match — overloads, namesakes
+//! in other packages — `FILE:LINE` of the wanted definition picks one.
+//!
+//! Reference sites come from the index impact analysis uses, so calls are
+//! resolved through Go imports and Python / Zig modules the same way. Each
+//! site is reported with its column, enclosing function and source line.
+
+use anyhow::Result;
+use serde::{Deserialize, Serialize};
+use std::collections::{BTreeMap, HashSet};
+use std::path::{Path, PathBuf};
+
+use crate::analyzers::go::packages::PackageIndex;
+use crate::core::impact::{ImpactAnalyzer, ImpactConfig, SymbolDefinition, SymbolReference};
+use crate::core::types::{AnalysisResult, DirectoryAnalysis, FunctionInfo};
+
+/// Definition of the requested symbol
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct DefinitionSite {
+    pub file: String,
+    pub line: u32,
+    pub end_line: u32,
+    /// "function", "method" or "class"
+    pub kind: String,
+    pub name: String,
+    /// Class or receiver type of a method
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub owner: Option<String>,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub namespace: Option<String>,
+}
+
+impl DefinitionSite {
+    /// `file:line` as shown in ambiguity errors and text output
+    pub fn location(&self) -> String {
+        format!("{}:{}", self.file, self.line)
+    }
+}
+
+/// One place referencing the symbol
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct ReferenceSite {
+    pub file: String,
+    pub line: u32,
+    /// 1-based column (UTF-8 bytes) of the name on the line; 0 when the source is unavailable
+    pub column: u32,
+    /// "call", "import", "export", "inheritance", "constructor"
+    pub kind: String,
+    /// Innermost function containing the reference
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub caller: Option<String>,
+    /// The referencing line, trimmed
+    pub snippet: String,
+    /// The referencing line with one line of context on each side
+    pub context: Vec<String>,
+}
+
+/// Result of the `find-references` command
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ReferenceReport {
+    pub symbol: String,
+    pub definition: DefinitionSite,
+    pub references: Vec<ReferenceSite>,
+}
+
+impl ReferenceReport {
+    /// References to `symbol`, defined at `at` (`FILE:LINE`) when it is ambiguous
+    pub fn build(analysis: &DirectoryAnalysis, symbol: &str, at: Option<&str>) -> Result<Self> {
+        let root = &analysis.directory_path;
+        let mut candidates = definitions(analysis, symbol);
+        if candidates.is_empty() {
+            anyhow::bail!("Symbol not found: {}", symbol);
+        }
+
+        if let Some(at) = at {
+            let (file, line) = parse_location(at)?;
+            candidates.retain(|(path, site)| (path.ends_with(file) || Path::new(&site.file).ends_with(file)) && site.line <= line && line <= site.end_line);
+            // Innermost first, for a line inside a nested definition
+            candidates.sort_by_key(|(_, site)| site.end_line - site.line);
+            candidates.truncate(1);
+            if candidates.is_empty() {
+                anyhow::bail!("No definition of {} at {}", symbol, at);
+            }
+        }
+        if candidates.len() > 1 {
+            let locations: Vec<String> = candidates.iter().map(|(_, site)| format!("  {} ({})", site.location(), site.kind)).collect();
+            anyhow::bail!(
+                "{} has {} definitions; pass --definition FILE:LINE to pick one:\n{}",
+                symbol, candidates.len(), locations.join("\n"),
+            );
+        }
+        let (file_path, definition) = candidates.remove(0);
+
+        let target = SymbolDefinition {
+            name: definition.name.clone(),
+            symbol_type: definition.kind.clone(),
+            file_path,
+            line_number: definition.line,
+        };
+        let packages = PackageIndex::build(&analysis.files);
+        let found = ImpactAnalyzer::new(ImpactConfig::default()).references_to(&target, analysis, &packages);
+
+        let mut seen = HashSet::new();
+        let mut by_file: BTreeMap<PathBuf, Vec<SymbolReference>> = BTreeMap::new();
+        for reference in found {
+            if !is_exact(&reference, &definition) || !seen.insert((reference.file_path.clone(), reference.line_number, reference.usage_type.clone())) {
+                continue;
+            }
+            by_file.entry(reference.file_path.clone()).or_default().push(reference);
+        }
+
+        let mut references = Vec::new();
+        for (path, sites) in by_file {
+            let source = std::fs::read_to_string(&path).unwrap_or_default();
+            let lines: Vec<&str> = source.lines().collect();
+            let file = analysis.files.iter().find(|f| f.file_info.path == path);
+            for reference in sites {
+                references.push(site(&path, root, &lines, file, &definition.name, reference));
+            }
+        }
+        references.sort_by(|a, b| (&a.file, a.line, a.column).cmp(&(&b.file, b.line, b.column)));
+
+        Ok(Self { symbol: symbol.to_string(), definition, references })
+    }
+
+    /// `file:line:column: snippet` per reference, after the definition
+    pub fn to_text(&self) -> String {
+        let mut lines = vec![format!("🔎 {} ({}) defined at {}", self.symbol, self.definition.kind, self.definition.location())];
+        for reference in &self.references {
+            lines.push(format!("{}:{}:{}: {}", reference.file, reference.line, reference.column, reference.snippet));
+        }
+        lines.push(format!("{} references", self.references.len()));
+        lines.join("\n")
+    }
+}
+
+/// Helper: Definitions matching the requested name, with the path they were analyzed under
+fn definitions(analysis: &DirectoryAnalysis, symbol: &str) -> Vec<(PathBuf, DefinitionSite)> {
+    let symbol = symbol.replace("::", ".");
+    let (qualifier, name) = match symbol.rsplit_once('.') {
+        Some((qualifier, name)) => (Some(qualifier), name),
+        None => (None, symbol.as_str()),
+    };
+    let qualifies = |owner: Option<&str>, namespace: Option<&str>| {
+        let Some(qualifier) = qualifier else { return true };
+        let namespace = namespace.map(|ns| ns.replace("::", "."));
+        owner == Some(qualifier)
+            || namespace.as_deref() == Some(qualifier)
+            || matches!((&namespace, owner), (Some(ns), Some(owner)) if format!("{}.{}", ns, owner) == qualifier)
+    };
+
+    let mut found = Vec::new();
+    let mut seen = HashSet::new();
+    for file in &analysis.files {
+        let path = &file.file_info.path;
+        let display = display_path(path, &analysis.directory_path);
+
+        let methods = file.classes.iter()
+            .flat_map(|class| class.methods.iter().map(move |method| (method, Some(class.name.as_str()))));
+        let functions = file.functions.iter().map(|func| (func, owner_of(func)));
+        // Some analyzers list methods both at file level and under their class
+        for (func, owner) in functions.chain(methods) {
+            if func.name != name || !qualifies(owner, func.namespace.as_deref()) || !seen.insert((path.clone(), func.start_line)) {
+                continue;
+            }
+            found.push((path.clone(), DefinitionSite {
+                file: display.clone(),
+                line: func.start_line,
+                end_line: func.end_line,
+                kind: if owner.is_some() { "method" } else { "function" }.to_string(),
+                name: func.name.clone(),
+                owner: owner.map(str::to_string),
+                namespace: func.namespace.clone(),
+            }));
+        }
+
+        for class in file.classes.iter().filter(|class| class.name == name && qualifies(None, class.namespace.as_deref())) {
+            found.push((path.clone(), DefinitionSite {
+                file: display.clone(),
+                line: class.start_line,
+                end_line: class.end_line,
+                kind: "class".to_string(),
+                name: class.name.clone(),
+                owner: None,
+                namespace: class.namespace.clone(),
+            }));
+        }
+    }
+    found
+}
+
+/// Helper: Class or receiver type a function is a method of
+fn owner_of(func: &FunctionInfo) -> Option<&str> {
+    func.metadata.get("receiver_type").or_else(|| func.metadata.get("class_name")).map(|s| s.as_str())
+}
+
+/// Helper: Whether an index entry names exactly this symbol
+///
+/// The impact index also lists namesakes defined elsewhere and, for functions,
+/// calls whose name merely contains the symbol's; neither is a reference here.
+fn is_exact(reference: &SymbolReference, definition: &DefinitionSite) -> bool {
+    match reference.usage_type.as_str() {
+        "definition" => false,
+        "call" if definition.kind != "class" => {
+            let callee = reference.context.trim_end_matches("()").replace("::", ".");
+            callee.rsplit('.').next() == Some(definition.name.as_str())
+        }
+        _ => true,
+    }
+}
+
+/// Helper: Column, caller and source lines of one reference
+fn site(path: &Path, root: &Path, lines: &[&str], file: Option<&AnalysisResult>, name: &str, reference: SymbolReference) -> ReferenceSite {
+    let line = reference.line_number;
+    let index = line.saturating_sub(1) as usize;
+    let text = lines.get(index).copied().unwrap_or("");
+    let column = match word_offset(text, name) {
+        Some(offset) => offset as u32 + 1,
+        None if !text.is_empty() => (text.len() - text.trim_start().len()) as u32 + 1,
+        None => 0,
+    };
+    let context = lines.iter()
+        .skip(index.saturating_sub(1))
+        .take(if index == 0 { 2 } else { 3 })
+        .map(|l| l.to_string())
+        .collect();
+
+    let caller = file.and_then(|file| {
+        file.functions.iter()
+            .filter(|f| f.start_line <= line && line <= f.end_line)
+            .min_by_key(|f| f.end_line - f.start_line)
+            .map(|f| f.name.clone())
+    });
+
+    ReferenceSite {
+        file: display_path(path, root),
+        line,
+        column,
+        kind: reference.usage_type,
+        caller,
+        snippet: text.trim().to_string(),
+        context,
+    }
+}
+
+/// Helper: Byte offset of `name` as a whole word in `line`
+fn word_offset(line: &str, name: &str) -> Option<usize> {
+    let is_word = |c: char| c.is_alphanumeric() || c == '_';
+    line.match_indices(name).map(|(offset, _)| offset).find(|&offset| {
+        let before = line[..offset].chars().next_back();
+        let after = line[offset + name.len()..].chars().next();
+        !before.is_some_and(is_word) && !after.is_some_and(is_word)
+    })
+}
+
+/// Helper: `FILE:LINE` of a definition
+fn parse_location(at: &str) -> Result<(&Path, u32)> {
+    let parsed = at.rsplit_once(':').and_then(|(file, line)| Some((Path::new(file), line.parse().ok()?)));
+    parsed.ok_or_else(|| anyhow::anyhow!("Expected FILE:LINE for --definition, got `{}`", at))
+}
+
+/// Helper: Path relative to the analyzed root
+fn display_path(path: &Path, root: &Path) -> String {
+    path.strip_prefix(root).unwrap_or(path).display().to_string()
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_word_offset() {
+        assert_eq!(word_offset("\tx := helper(helpers)", "helper"), Some(6));
+        assert_eq!(word_offset("helpers()", "helper"), None);
+        assert_eq!(word_offset("s.helper()", "helper"), Some(2));
+    }
+
+    #[test]
+    fn test_parse_location() {
+        assert_eq!(parse_location("pkg/util.go:12").unwrap(), (Path::new("pkg/util.go"), 12));
+        assert!(parse_location("pkg/util.go").is_err());
+    }
+}
@@ -0,0 +1,312 @@
+//! 📄 Self-contained HTML report (`report --format html`)
+//!
+//! One file with inline CSS and JS and no external assets, so it can be
+//! attached to a PR or opened from disk. The full [`DirectoryAnalysis`] is
+//! embedded as JSON; the tables (files, functions) and the per-file sections
+//! are rendered from it in the browser, which is what makes sorting and
+//! filtering work without a server.
+
+use anyhow::Result;
+
+use crate::core::types::DirectoryAnalysis;
+
+/// Cyclomatic complexity highlighted when neither `--threshold` nor
+/// `max_cyclomatic` is configured
+pub const DEFAULT_REPORT_THRESHOLD: u32 = 10;
+
+/// Render the HTML report; functions with cyclomatic or cognitive complexity
+/// above `threshold` are highlighted
+pub fn render_html(analysis: &DirectoryAnalysis, threshold: u32) -> Result<String> {
+    let title = format!("NekoCode report: {}", analysis.directory_path.display());
+    let summary = &analysis.summary;
+    
+    let mut html = String::new();
+    html.push_str("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n");
+    html.push_str(&format!("<title>{}</title>\n", escape_html(&title)));
+    html.push_str(&format!("<style>{}</style>\n", STYLE));
+    html.push_str("</head>\n<body>\n");
+    html.push_str(&format!("<h1>{}</h1>\n", escape_html(&title)));
+    html.push_str(&format!("<p class=\"generated\">Generated {}</p>\n", analysis.generated_at.format("%Y-%m-%d %H:%M:%S UTC")));
+    
+    html.push_str("<div class=\"summary\">\n");
+    for (label, value) in [
+        ("Files", summary.total_files.to_string()),
+        ("Lines", summary.total_lines.to_string()),
+        ("Functions", summary.total_functions.to_string()),
+        ("Classes", summary.total_classes.to_string()),
+        ("Avg complexity", format!("{:.1}", summary.average_complexity)),
+        ("Max complexity", summary.max_complexity.to_string()),
+    ] {
+        html.push_str(&format!("<div class=\"card\"><span>{}</span><b>{}</b></div>\n", label, value));
+    }
+    html.push_str("</div>\n");
+    
+    html.push_str(&format!(concat!(
+        "<div class=\"controls\">\n",
+        "<input id=\"filter\" type=\"search\" placeholder=\"Filter by file or function\">\n",
+        "<label>Threshold <input id=\"threshold\" type=\"number\" min=\"0\" value=\"{}\"></label>\n",
+        "<label><input id=\"over-only\" type=\"checkbox\"> Over threshold only</label>\n",
+        "</div>\n"), threshold));
+    
+    html.push_str("<h2>Files</h2>\n<table id=\"files\" class=\"sortable\"><thead><tr>");
+    html.push_str("<th data-key=\"path\">File</th><th data-key=\"language\">Language</th><th data-key=\"lines\" data-numeric>Lines</th>");
+    html.push_str("<th data-key=\"functions\" data-numeric>Functions</th><th data-key=\"classes\" data-numeric>Classes</th>");
+    html.push_str("<th data-key=\"complexity\" data-numeric>Complexity</th><th data-key=\"over\" data-numeric>Over threshold</th>");
+    html.push_str("</tr></thead><tbody></tbody></table>\n");
+    
+    html.push_str("<h2>Functions</h2>\n<table id=\"functions\" class=\"sortable\"><thead><tr>");
+    html.push_str("<th data-key=\"path\">File</th><th data-key=\"name\">Function</th><th data-key=\"line\" data-numeric>Line</th>");
+    html.push_str("<th data-key=\"cyclomatic\" data-numeric>Cyclomatic</th><th data-key=\"cognitive\" data-numeric>Cognitive</th>");
+    html.push_str("<th data-key=\"nesting\" data-numeric>Nesting</th><th data-key=\"lines\" data-numeric>Lines</th>");
+    html.push_str("</tr></thead><tbody></tbody></table>\n");
+    
+    html.push_str("<h2>Per file</h2>\n<div id=\"details\"></div>\n");
+    
+    html.push_str(&format!("<script type=\"application/json\" id=\"nekocode-data\">{}</script>\n", embed_json(analysis)?));
+    html.push_str(&format!("<script>{}</script>\n", SCRIPT));
+    html.push_str("</body>\n</html>\n");
+    
+    Ok(html)
+}
+
+/// Helper: Serialize for a `<script>` element; `<`, `>` and `&` only occur
+/// inside JSON strings, where the `\uXXXX` escapes keep the JSON identical
+fn embed_json(analysis: &DirectoryAnalysis) -> Result<String> {
+    Ok(serde_json::to_string(analysis)?
+        .replace('<', "\\u003c")
+        .replace('>', "\\u003e")
+        .replace('&', "\\u0026"))
+}
+
+/// Helper: Escape text for HTML content and attribute values
+fn escape_html(text: &str) -> String {
+    text.replace('&', "&amp;")
+        .replace('<', "&lt;")
+        .replace('>', "&gt;")
+        .replace('"', "&quot;")
+}
+
+const STYLE: &str = r#"
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+h1 { font-size: 1.5em; margin-bottom: 0; }
+.generated { color: #57606a; margin-top: 0.2em; }
+.summary { display: flex; flex-wrap: wrap; gap: 0.8em; margin: 1em 0; }
+.card { border: 1px solid #d0d7de; border-radius: 6px; padding: 0.5em 1em; min-width: 7em; }
+.card span { display: block; color: #57606a; font-size: 0.85em; }
+.card b { font-size: 1.4em; }
+.controls { display: flex; gap: 1.5em; align-items: center; margin: 1em 0; }
+.controls input[type=search] { width: 22em; padding: 0.3em; }
+.controls input[type=number] { width: 4em; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1.5em; font-size: 0.9em; }
+th, td { border-bottom: 1px solid #d0d7de; padding: 0.3em 0.6em; text-align: left; }
+td.num, th[data-numeric] { text-align: right; }
+th { cursor: pointer; user-select: none; background: #f6f8fa; }
+th.asc::after { content: " \25B2"; }
+th.desc::after { content: " \25BC"; }
+tr.over td, li.over { color: #cf222e; font-weight: 600; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: 0.4em 0; padding: 0.3em 0.8em; }
+summary { cursor: pointer; }
+details ul { margin: 0.4em 0; }
+code { font-family: SFMono-Regular, Consolas, monospace; }
+"#;
+
+const SCRIPT: &str = r#"
+(function () {
+  "use strict";
+  var data = JSON.parse(document.getElementById("nekocode-data").textContent);
+  var filterInput = document.getElementById("filter");
+  var thresholdInput = document.getElementById("threshold");
+  var overOnly = document.getElementById("over-only");
+  var sortState = {};
+  
+  // Methods can be reported both as functions and inside their class
+  function functionsOf(file) {
+    var seen = {};
+    var out = [];
+    function add(fn, owner) {
+      var key = fn.name + ":" + fn.start_line;
+      if (seen[key]) { return; }
+      seen[key] = true;
+      var meta = fn.metadata || {};
+      owner = owner || meta.class_name || meta.receiver_type;
+      out.push({
+        path: file.file_info.path,
+        name: owner ? owner + "." + fn.name : fn.name,
+        line: fn.start_line,
+        cyclomatic: fn.complexity.cyclomatic_complexity,
+        cognitive: fn.complexity.cognitive_complexity,
+        nesting: fn.complexity.max_nesting_depth,
+        lines: Math.max(fn.end_line - fn.start_line + 1, 1)
+      });
+    }
+    file.functions.forEach(function (fn) { add(fn, null); });
+    file.classes.forEach(function (cls) {
+      cls.methods.forEach(function (m) { add(m, cls.name); });
+    });
+    return out;
+  }
+  
+  var files = data.files.map(function (file) {
+    return { path: file.file_info.path, language: file.language, lines: file.file_info.total_lines,
+             classes: file.classes.length, complexity: file.complexity.cyclomatic_complexity,
+             functionList: functionsOf(file) };
+  });
+  
+  function threshold() { return parseInt(thresholdInput.value, 10) || 0; }
+  function isOver(fn) { return fn.cyclomatic > threshold() || fn.cognitive > threshold(); }
+  function matches(text) {
+    var needle = filterInput.value.trim().toLowerCase();
+    return !needle || text.toLowerCase().indexOf(needle) !== -1;
+  }
+  
+  function cell(row, value, numeric) {
+    var td = document.createElement("td");
+    td.textContent = value;
+    if (numeric) { td.className = "num"; }
+    row.appendChild(td);
+  }
+  
+  function sorted(table, rows) {
+    var state = sortState[table.id];
+    if (!state) { return rows; }
+    return rows.slice().sort(function (a, b) {
+      var x = a[state.key], y = b[state.key];
+      var order = typeof x === "number" ? x - y : String(x).localeCompare(String(y));
+      return state.desc ? -order : order;
+    });
+  }
+  
+  function renderFiles() {
+    var table = document.getElementById("files");
+    var rows = files.map(function (file) {
+      var visible = file.functionList.filter(function (fn) { return matches(file.path + " " + fn.name); });
+      var over = file.functionList.filter(isOver).length;
+      return { path: file.path, language: file.language, lines: file.lines, functions: file.functionList.length,
+               classes: file.classes, complexity: file.complexity, over: over,
+               visible: matches(file.path) || visible.length > 0 };
+    }).filter(function (row) { return row.visible && (!overOnly.checked || row.over > 0); });
+    
+    var body = table.tBodies[0];
+    body.innerHTML = "";
+    sorted(table, rows).forEach(function (file) {
+      var tr = document.createElement("tr");
+      if (file.over > 0) { tr.className = "over"; }
+      cell(tr, file.path); cell(tr, file.language); cell(tr, file.lines, true);
+      cell(tr, file.functions, true); cell(tr, file.classes, true); cell(tr, file.complexity, true);
+      cell(tr, file.over, true);
+      body.appendChild(tr);
+    });
+  }
+  
+  function renderFunctions() {
+    var table = document.getElementById("functions");
+    var rows = [];
+    files.forEach(function (file) {
+      file.functionList.forEach(function (fn) {
+        if (matches(fn.path + " " + fn.name) && (!overOnly.checked || isOver(fn))) { rows.push(fn); }
+      });
+    });
+    
+    var body = table.tBodies[0];
+    body.innerHTML = "";
+    sorted(table, rows).forEach(function (fn) {
+      var tr = document.createElement("tr");
+      if (isOver(fn)) { tr.className = "over"; }
+      cell(tr, fn.path); cell(tr, fn.name); cell(tr, fn.line, true);
+      cell(tr, fn.cyclomatic, true); cell(tr, fn.cognitive, true); cell(tr, fn.nesting, true);
+      cell(tr, fn.lines, true);
+      body.appendChild(tr);
+    });
+  }
+  
+  function renderDetails() {
+    var container = document.getElementById("details");
+    var open = {};
+    Array.prototype.forEach.call(container.querySelectorAll("details[open]"), function (el) { open[el.dataset.path] = true; });
+    container.innerHTML = "";
+    files.forEach(function (file) {
+      var fns = file.functionList.filter(function (fn) {
+        return matches(file.path + " " + fn.name) && (!overOnly.checked || isOver(fn));
+      });
+      if (fns.length === 0 && !(matches(file.path) && !overOnly.checked)) { return; }
+      
+      var details = document.createElement("details");
+      details.dataset.path = file.path;
+      details.open = !!open[file.path];
+      var summary = document.createElement("summary");
+      summary.textContent = file.path + " (" + fns.length + " functions, " + file.lines + " lines)";
+      details.appendChild(summary);
+      
+      var list = document.createElement("ul");
+      fns.forEach(function (fn) {
+        var li = document.createElement("li");
+        if (isOver(fn)) { li.className = "over"; }
+        var code = document.createElement("code");
+        code.textContent = fn.name;
+        li.appendChild(code);
+        li.appendChild(document.createTextNode(" line " + fn.line + ", cyclomatic " + fn.cyclomatic + ", cognitive " + fn.cognitive +
+                                                ", nesting " + fn.nesting));
+        list.appendChild(li);
+      });
+      details.appendChild(list);
+      container.appendChild(details);
+    });
+  }
+  
+  function render() { renderFiles(); renderFunctions(); renderDetails(); }
+  
+  Array.prototype.forEach.call(document.querySelectorAll("table.sortable th"), function (th) {
+    th.addEventListener("click", function () {
+      var table = th.closest("table");
+      var state = sortState[table.id];
+      var desc = state && state.key === th.dataset.key ? !state.desc : th.hasAttribute("data-numeric");
+      sortState[table.id] = { key: th.dataset.key, desc: desc };
+      Array.prototype.forEach.call(table.querySelectorAll("th"), function (other) { other.className = ""; });
+      th.className = desc ? "desc" : "asc";
+      render();
+    });
+  });
+  [filterInput, thresholdInput, overOnly].forEach(function (input) { input.addEventListener("input", render); });
+  render();
+})();
+"#;
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{AnalysisResult, FileInfo, FunctionInfo, Language};
+    use std::path::PathBuf;
+    
+    #[test]
+    fn test_render_html() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("src/<main>.go")), Language::Go);
+        let mut func = FunctionInfo::new("run".to_string());
+        func.complexity.cyclomatic_complexity = 12;
+        result.functions.push(func);
+        
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("src & lib"));
+        analysis.files.push(result);
+        
+        let html = render_html(&analysis, 10).unwrap();
+        
+        assert!(html.starts_with("<!DOCTYPE html>"));
+        assert!(html.contains("<title>NekoCode report: src &amp; lib</title>"));
+        assert!(html.contains("id=\"threshold\" type=\"number\" min=\"0\" value=\"10\""));
+        // No external assets
+        assert!(!html.contains("<link") && !html.contains("src=\"http"));
+        
+        // Embedded JSON cannot close its script element and round-trips
+        let start = html.find("id=\"nekocode-data\">").unwrap() + "id=\"nekocode-data\">".len();
+        let end = start + html[start..].find("</script>").unwrap();
+        let json = &html[start..end];
+        assert!(!json.contains('<'));
+        let embedded: DirectoryAnalysis = serde_json::from_str(json).unwrap();
+        assert_eq!(embedded.files[0].file_info.path, PathBuf::from("src/<main>.go"));
+        assert_eq!(embedded.files[0].functions[0].complexity.cyclomatic_complexity, 12);
+    }
+    
+    #[test]
+    fn test_escape_html() {
+        assert_eq!(escape_html("a < b && \"c\""), "a &lt; b &amp;&amp; &quot;c&quot;");
+    }
+}
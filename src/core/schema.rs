@@ -0,0 +1,76 @@
+//! JSON Schema for the analysis output
+//!
+//! Generated from the output structs with schemars (draft 2020-12), so the
+//! published schema always matches what `analyze` serializes.
+
+use schemars::{schema_for, Schema};
+
+use crate::core::types::{AnalysisResult, DirectoryAnalysis};
+
+/// Top-level output type to describe
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum SchemaRoot {
+    /// Single file analysis (`AnalysisResult`)
+    File,
+    /// Directory analysis (`DirectoryAnalysis`)
+    Directory,
+}
+
+impl SchemaRoot {
+    pub fn parse(name: &str) -> Option<Self> {
+        match name {
+            "file" | "result" | "AnalysisResult" => Some(Self::File),
+            "directory" | "DirectoryAnalysis" => Some(Self::Directory),
+            _ => None,
+        }
+    }
+    
+    fn type_name(&self) -> &'static str {
+        match self {
+            Self::File => "AnalysisResult",
+            Self::Directory => "DirectoryAnalysis",
+        }
+    }
+}
+
+/// JSON Schema for the output, `$id` tagged with the tool version
+pub fn output_schema(root: SchemaRoot) -> Schema {
+    let mut schema = match root {
+        SchemaRoot::File => schema_for!(AnalysisResult),
+        SchemaRoot::Directory => schema_for!(DirectoryAnalysis),
+    };
+    
+    schema.insert(
+        "$id".to_string(),
+        serde_json::Value::String(format!(
+            "urn:nekocode-rust:{}:{}",
+            env!("CARGO_PKG_VERSION"),
+            root.type_name()
+        )),
+    );
+    
+    schema
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    #[test]
+    fn test_output_schema() {
+        let schema = serde_json::to_value(output_schema(SchemaRoot::File)).unwrap();
+        
+        assert_eq!(schema["$schema"], "https://json-schema.org/draft/2020-12/schema");
+        assert_eq!(schema["$id"], format!("urn:nekocode-rust:{}:AnalysisResult", env!("CARGO_PKG_VERSION")));
+        assert!(schema["properties"]["functions"].is_object());
+        assert!(schema["$defs"]["FunctionInfo"].is_object());
+        assert!(schema["$defs"]["ClassInfo"].is_object());
+    }
+    
+    #[test]
+    fn test_schema_root_parse() {
+        assert_eq!(SchemaRoot::parse("directory"), Some(SchemaRoot::Directory));
+        assert_eq!(SchemaRoot::parse("file"), Some(SchemaRoot::File));
+        assert_eq!(SchemaRoot::parse("other"), None);
+    }
+}
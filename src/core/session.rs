@@ -5,20 +5,77 @@
 
 use anyhow::{Context, Result};
 use std::path::{Path, PathBuf};
-use walkdir::WalkDir;
-use std::collections::HashMap;
+use ignore::WalkBuilder;
+use std::collections::{HashMap, HashSet};
 use chrono::{DateTime, Utc};
 use serde::{Deserialize, Serialize};
 use std::fs;
 
 use crate::core::types::{
-    AnalysisConfig, AnalysisResult, DirectoryAnalysis, FileInfo, Language,
+    AnalysisConfig, AnalysisError, AnalysisResult, AnalysisWarning, DirectoryAnalysis, DirectorySummary, FileInfo,
+    Language, SummaryBuilder,
 };
+use futures::stream::BoxStream;
+use futures::StreamExt;
 use crate::core::ast::{ASTNode, ASTStatistics};
+use crate::core::archive::{self, ArchiveKind};
+use crate::core::diagnostics::ParseDiagnostics;
+use crate::core::git::Revision;
+use crate::core::cache::{default_cache_dir, AnalysisCache, CACHE_DIR};
 use crate::core::incremental::{ChangeDetector, FileChange, IncrementalSummary};
+use crate::core::project_config::{LanguageMap, PathFilter};
+use crate::core::source::SourceText;
+use crate::core::notebook::{is_notebook, NotebookSource};
+use crate::analyzers::go::build_tags::BuildTags;
+use crate::core::progress::Progress;
 use crate::analyzers::javascript::{JavaScriptAnalyzer, TreeSitterJavaScriptAnalyzer};
 use crate::analyzers::traits::LanguageAnalyzer;
 
+/// Totals of a streamed analysis (the file results went to the sink)
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct StreamedAnalysis {
+    pub directory_path: PathBuf,
+    pub summary: DirectorySummary,
+    pub errors: Vec<AnalysisError>,
+    pub warnings: Vec<AnalysisWarning>,
+}
+
+/// A file over `max_file_size`; batches report it as a warning, not an error
+#[derive(Debug, Clone, Copy)]
+pub struct FileTooLarge {
+    pub size_bytes: u64,
+    pub limit: u64,
+}
+
+impl std::fmt::Display for FileTooLarge {
+    fn fmt(&self, f: &mut std::fmt::Formatter<'_>) -> std::fmt::Result {
+        write!(f, "Skipped: {} bytes exceeds the max file size of {} bytes", self.size_bytes, self.limit)
+    }
+}
+
+impl std::error::Error for FileTooLarge {}
+
+/// A generated file under `exclude_generated`; batches drop it silently
+#[derive(Debug, Clone, Copy)]
+pub struct GeneratedFile;
+
+impl std::fmt::Display for GeneratedFile {
+    fn fmt(&self, f: &mut std::fmt::Formatter<'_>) -> std::fmt::Result {
+        write!(f, "Skipped: generated file (exclude generated)")
+    }
+}
+
+impl std::error::Error for GeneratedFile {}
+
+/// An archive entry or historical blob decoded for analysis under its virtual path
+struct SourceEntry {
+    path: PathBuf,
+    content: String,
+    language: Language,
+    /// Set when the bytes were not valid UTF-8 and were decoded lossily
+    encoding_warning: Option<String>,
+}
+
 /// Session storage for managing multiple analysis sessions
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct SessionInfo {
@@ -39,6 +96,9 @@ pub struct SessionInfo {
 /// Session directory management
 const SESSION_DIR: &str = ".nekocode_sessions";
 
+/// Analyzer-specific ignore file (same syntax as .gitignore)
+pub const IGNORE_FILE_NAME: &str = ".nekocodeignore";
+
 /// Global session manager with file-based persistence
 pub struct SessionManager {
     sessions: HashMap<String, AnalysisSession>,
@@ -909,6 +969,17 @@ impl SessionManager {
                 None
             }
             
+            // Ruby - handle require_relative paths
+            Language::Ruby => {
+                if let Some(parent) = current_file.parent() {
+                    let rb_path = parent.join(clean_import_path).with_extension("rb");
+                    if rb_path.exists() {
+                        return Some(rb_path.to_string_lossy().to_string());
+                    }
+                }
+                None
+            }
+            
             _ => None,
         }
     }
@@ -961,11 +1032,19 @@ impl AnalysisSession {
         Self { config }
     }
     
+    /// Configuration the session analyzes with
+    pub fn config(&self) -> &AnalysisConfig {
+        &self.config
+    }
+    
     /// Analyze a single file or directory
     pub async fn analyze_path(&mut self, path: &Path, include_tests: bool) -> Result<DirectoryAnalysis> {
         self.config.include_test_files = include_tests;
+        self.prepare_cache(path)?;
         
-        if path.is_file() {
+        if path.is_file() && ArchiveKind::detect(path).is_some() {
+            self.analyze_archive(path).await
+        } else if path.is_file() {
             self.analyze_single_file(path).await
         } else if path.is_dir() {
             self.analyze_directory(path).await
@@ -974,112 +1053,604 @@ impl AnalysisSession {
         }
     }
     
+    /// Analyze a single file or directory, handing each file result to `sink` as soon
+    /// as it is ready instead of collecting them
+    ///
+    /// Results arrive in completion order. `sink` runs on the calling task, so it is
+    /// the single writer even with many workers. Cross-file passes (Go interface
+    /// linking across files) need the whole set and are skipped.
+    pub async fn analyze_path_streaming<F>(&mut self, path: &Path, include_tests: bool, mut sink: F) -> Result<StreamedAnalysis>
+    where
+        F: FnMut(AnalysisResult) -> Result<()>,
+    {
+        self.config.include_test_files = include_tests;
+        self.prepare_cache(path)?;
+        
+        let mut builder = SummaryBuilder::default();
+        let mut over_threshold = 0;
+        let mut errors = Vec::new();
+        let mut warnings = Vec::new();
+        
+        let jobs = if self.config.enable_parallel_processing { self.worker_count() } else { 1 };
+        let (root, total, mut results): (PathBuf, usize, BoxStream<'_, (PathBuf, Result<AnalysisResult>)>) =
+            if path.is_file() && ArchiveKind::detect(path).is_some() {
+                let (sources, skipped) = self.archive_sources(path).await?;
+                warnings = skipped;
+                (path.to_path_buf(), sources.len(), self.analyze_sources(sources).buffer_unordered(jobs).boxed())
+            } else {
+                let (root, files) = if path.is_file() {
+                    (path.parent().unwrap_or_else(|| Path::new(".")).to_path_buf(), vec![path.to_path_buf()])
+                } else if path.is_dir() {
+                    (path.to_path_buf(), self.discover_files(path)?)
+                } else {
+                    anyhow::bail!("Path does not exist or is not accessible: {}", path.display());
+                };
+                (root, files.len(), self.analyze_files(files).buffer_unordered(jobs).boxed())
+            };
+        
+        let mut progress = Progress::stderr(self.config.progress, total);
+        while let Some((file_path, result)) = results.next().await {
+            progress.tick();
+            match result {
+                Ok(result) => {
+                    builder.add(&result);
+                    over_threshold += self.functions_over_threshold(&result);
+                    sink(result)?;
+                }
+                Err(e) => self.record_failure(file_path, e, &mut errors, &mut warnings),
+            }
+        }
+        progress.finish();
+        
+        let mut summary = builder.finish();
+        summary.functions_over_threshold = over_threshold;
+        Ok(StreamedAnalysis { directory_path: root, summary, errors, warnings })
+    }
+    
+    /// Helper: Resolve the cache location once and drop entries from other versions
+    fn prepare_cache(&mut self, path: &Path) -> Result<()> {
+        if self.config.cache_enabled {
+            let cache_dir = self.config.cache_dir.clone().unwrap_or_else(|| default_cache_dir(path));
+            AnalysisCache::new(cache_dir.clone()).prune_stale()?;
+            self.config.cache_dir = Some(cache_dir);
+        }
+        Ok(())
+    }
+    
+    /// Helper: Analyze files on blocking workers; poll with `buffered` / `buffer_unordered`
+    fn analyze_files(&self, files: Vec<PathBuf>) -> impl futures::Stream<Item = impl std::future::Future<Output = (PathBuf, Result<AnalysisResult>)>> {
+        let config = self.config.clone();
+        futures::stream::iter(files).map(move |file_path| {
+            let config = config.clone();
+            async move {
+                let task_path = file_path.clone();
+                let result = tokio::task::spawn_blocking(move || {
+                    // Create a temporary session for this task
+                    let temp_session = AnalysisSession::with_config(config);
+                    tokio::runtime::Handle::current().block_on(async {
+                        temp_session.analyze_file(&task_path).await
+                    })
+                }).await;
+                
+                let result = match result {
+                    Ok(result) => result,
+                    Err(e) => Err(anyhow::anyhow!("Task join error: {}", e)),
+                };
+                (file_path, result)
+            }
+        })
+    }
+    
     /// Analyze a single file
     async fn analyze_single_file(&self, file_path: &Path) -> Result<DirectoryAnalysis> {
         let mut directory_analysis = DirectoryAnalysis::new(
             file_path.parent().unwrap_or_else(|| Path::new(".")).to_path_buf()
         );
         
-        let result = self.analyze_file(file_path).await
-            .with_context(|| format!("Failed to analyze file: {}", file_path.display()))?;
+        let result = match self.analyze_file(file_path).await {
+            Ok(result) => result,
+            Err(e) if e.downcast_ref::<FileTooLarge>().is_some() => {
+                directory_analysis.warnings.push(AnalysisWarning {
+                    file_path: file_path.to_path_buf(),
+                    message: e.to_string(),
+                });
+                return Ok(directory_analysis);
+            }
+            Err(e) if e.downcast_ref::<GeneratedFile>().is_some() => return Ok(directory_analysis),
+            Err(e) => return Err(e.context(format!("Failed to analyze file: {}", file_path.display()))),
+        };
         
         directory_analysis.files.push(result);
+        run_cross_file_passes(&mut directory_analysis);
         directory_analysis.update_summary();
+        self.count_over_threshold(&mut directory_analysis);
         
         Ok(directory_analysis)
     }
     
     /// Analyze a directory
     async fn analyze_directory(&self, dir_path: &Path) -> Result<DirectoryAnalysis> {
-        println!("🔍 [RUST] Starting directory analysis: {}", dir_path.display());
+        let debug = std::env::var("NEKOCODE_DEBUG").is_ok();
+        if debug {
+            eprintln!("🔍 [RUST] Starting directory analysis: {}", dir_path.display());
+        }
         let start_total = std::time::Instant::now();
         
         let mut directory_analysis = DirectoryAnalysis::new(dir_path.to_path_buf());
         
-        // Discover files
+        // Discover files (sorted so output order does not depend on the filesystem)
         let start_scan = std::time::Instant::now();
-        let files = self.discover_files(dir_path)?;
+        let mut files = self.discover_files(dir_path)?;
+        files.sort();
         let scan_duration = start_scan.elapsed();
-        println!("📁 [RUST] File discovery took: {:.3}s, found {} files", scan_duration.as_secs_f64(), files.len());
+        if debug {
+            eprintln!("📁 [RUST] File discovery took: {:.3}s, found {} files", scan_duration.as_secs_f64(), files.len());
+        }
         
         if self.config.verbose_output {
-            println!("📁 Found {} files to analyze", files.len());
+            eprintln!("📁 Found {} files to analyze", files.len());
             for file in &files {
-                println!("  - {}", file.display());
+                eprintln!("  - {}", file.display());
+            }
+        }
+        
+        self.analyze_file_set(&mut directory_analysis, files).await;
+        
+        let total_duration = start_total.elapsed();
+        if debug {
+            eprintln!("🏁 [RUST] Total directory analysis took: {:.3}s", total_duration.as_secs_f64());
+            if let Some(peak) = peak_rss_bytes() {
+                eprintln!("🧠 [RUST] Peak resident memory: {:.1} MiB", peak as f64 / (1024.0 * 1024.0));
+            }
+        }
+        
+        if self.config.verbose_output {
+            eprintln!("✅ Analyzed {} files successfully", directory_analysis.files.len());
+        }
+        
+        Ok(directory_analysis)
+    }
+    
+    /// Analyze several files / directories as one project
+    ///
+    /// Cross-file passes run over the union, so references between roots
+    /// resolve. A file reachable from more than one root (overlapping
+    /// directories, a file inside a listed directory) is analyzed once, under
+    /// the first root reaching it, compared by canonical path. Files named
+    /// directly skip the walk filters, as with a single path. `directory_path`
+    /// is the roots' common ancestor and `roots` lists them as given.
+    pub async fn analyze_paths(&mut self, paths: &[PathBuf], include_tests: bool) -> Result<DirectoryAnalysis> {
+        if let [path] = paths {
+            return self.analyze_path(path, include_tests).await;
+        }
+        self.config.include_test_files = include_tests;
+        
+        let root = common_ancestor(paths);
+        self.prepare_cache(&root)?;
+        
+        let mut files = Vec::new();
+        let mut seen = HashSet::new();
+        for path in paths {
+            let found = if path.is_file() && ArchiveKind::detect(path).is_some() {
+                anyhow::bail!("Archives cannot be combined with other paths: {}", path.display());
+            } else if path.is_file() {
+                vec![path.clone()]
+            } else if path.is_dir() {
+                let mut found = self.discover_files(path)?;
+                found.sort();
+                found
+            } else {
+                anyhow::bail!("Path does not exist or is not accessible: {}", path.display());
+            };
+            for file in found {
+                let canonical = fs::canonicalize(&file).unwrap_or_else(|_| file.clone());
+                if seen.insert(canonical) {
+                    files.push(file);
+                }
+            }
+        }
+        
+        if self.config.verbose_output {
+            eprintln!("📁 Found {} files to analyze in {} paths", files.len(), paths.len());
+        }
+        
+        let mut directory_analysis = DirectoryAnalysis::new(root);
+        directory_analysis.roots = paths.to_vec();
+        self.analyze_file_set(&mut directory_analysis, files).await;
+        Ok(directory_analysis)
+    }
+    
+    /// Analyze exactly `files` as one project, without walking directories
+    ///
+    /// For build systems that already computed the file set (`--files-from`):
+    /// ignore files, test-file filtering and `--build-tags` do not apply, only
+    /// the language selection does. A listed path that is missing or not a
+    /// regular file is reported in `errors`; the rest are still analyzed.
+    pub async fn analyze_file_list(&mut self, files: &[PathBuf]) -> Result<DirectoryAnalysis> {
+        // Missing files still place the root by their directory
+        let dirs: Vec<PathBuf> = files.iter()
+            .map(|file| if file.is_dir() { file.clone() } else { file.parent().unwrap_or_else(|| Path::new(".")).to_path_buf() })
+            .collect();
+        let root = common_ancestor(&dirs);
+        self.prepare_cache(&root)?;
+        let language_map = LanguageMap::new(&self.config)?;
+        
+        let mut directory_analysis = DirectoryAnalysis::new(root);
+        let mut selected = Vec::new();
+        let mut seen = HashSet::new();
+        for file in files {
+            if !file.exists() {
+                directory_analysis.errors.push(AnalysisError { file_path: file.clone(), message: "File not found".to_string() });
+                continue;
+            }
+            if !file.is_file() {
+                directory_analysis.errors.push(AnalysisError { file_path: file.clone(), message: "Not a regular file".to_string() });
+                continue;
+            }
+            let language = match Self::detect_language(&language_map, file, "") {
+                Language::Unknown => self.detect_shebang_language(file),
+                language => language,
+            };
+            if language == Language::Unknown || !self.is_language_enabled(language) {
+                if self.config.verbose_output {
+                    eprintln!("⏭️  {}: language not analyzed", file.display());
+                }
+                continue;
+            }
+            let canonical = fs::canonicalize(file).unwrap_or_else(|_| file.clone());
+            if seen.insert(canonical) {
+                selected.push(file.clone());
             }
         }
         
-        // Analyze files in parallel
+        if self.config.verbose_output {
+            eprintln!("📁 Analyzing {} of {} listed files", selected.len(), files.len());
+        }
+        
+        self.analyze_file_set(&mut directory_analysis, selected).await;
+        Ok(directory_analysis)
+    }
+    
+    /// Helper: Analyze `files` into `directory_analysis`, then run the cross-file passes
+    async fn analyze_file_set(&self, directory_analysis: &mut DirectoryAnalysis, files: Vec<PathBuf>) {
+        let debug = std::env::var("NEKOCODE_DEBUG").is_ok();
+        
+        // Analyze files on a bounded worker pool
         let start_analysis = std::time::Instant::now();
-        println!("⚡ [RUST] Starting {} analysis (parallel={})", 
-                 if self.config.enable_parallel_processing { "PARALLEL" } else { "SEQUENTIAL" },
-                 self.config.enable_parallel_processing);
-        
-        let results: Result<Vec<_>> = if self.config.enable_parallel_processing {
-            // 🚀 Use spawn_blocking with chunk processing for better parallelization
-            let total_files = files.len();
-            println!("🔧 [RUST] Creating {} spawn_blocking tasks for parallel processing", total_files);
-            
-            let futures: Vec<_> = files.into_iter().enumerate().map(|(i, file_path)| {
-                let config = self.config.clone();
-                tokio::task::spawn_blocking(move || {
-                    if i % 100 == 0 || i == total_files - 1 {
-                        println!("🔄 [RUST] Processing file {}/{} on thread {:?}: {}", 
-                                i + 1, total_files, 
-                                std::thread::current().id(), 
-                                file_path.display());
-                    }
-                    // Create a temporary session for this task
-                    let temp_session = AnalysisSession::with_config(config);
-                    // Use the sync version of the runtime
-                    tokio::runtime::Handle::current().block_on(async {
-                        temp_session.analyze_file(&file_path).await
-                    })
-                })
-            }).collect();
-            
-            println!("🚀 [RUST] Spawned {} blocking tasks, waiting for completion...", futures.len());
-            
-            // Process futures concurrently
-            let results = futures::future::join_all(futures).await;
-            results.into_iter().collect::<Result<Vec<_>, _>>().map_err(|e| anyhow::anyhow!("Task join error: {}", e))?
-                .into_iter().collect()
-        } else {
-            let mut results = Vec::new();
-            for file_path in &files {
-                results.push(self.analyze_file(file_path).await);
+        let jobs = if self.config.enable_parallel_processing { self.worker_count() } else { 1 };
+        if debug {
+            eprintln!("⚡ [RUST] Starting analysis with {} worker(s)", jobs);
+        }
+        
+        // `buffered` keeps results in input order regardless of completion order
+        let mut progress = Progress::stderr(self.config.progress, files.len());
+        let mut stream = self.analyze_files(files).buffered(jobs);
+        let mut results: Vec<(PathBuf, Result<AnalysisResult>)> = Vec::new();
+        while let Some(item) = stream.next().await {
+            progress.tick();
+            results.push(item);
+        }
+        progress.finish();
+        
+        // A failing file is reported, not fatal for the batch
+        for (file_path, result) in results {
+            match result {
+                Ok(result) => directory_analysis.files.push(result),
+                Err(e) => self.record_failure(file_path, e, &mut directory_analysis.errors, &mut directory_analysis.warnings),
             }
-            results.into_iter().collect()
-        };
+        }
         
-        directory_analysis.files = results?;
         let analysis_duration = start_analysis.elapsed();
-        println!("🔄 [RUST] File analysis took: {:.3}s ({} files)", analysis_duration.as_secs_f64(), directory_analysis.files.len());
+        if debug {
+            eprintln!("🔄 [RUST] File analysis took: {:.3}s ({} files, {} errors)",
+                     analysis_duration.as_secs_f64(), directory_analysis.files.len(), directory_analysis.errors.len());
+        }
         
-        let start_summary = std::time::Instant::now();
-        directory_analysis.update_summary();
-        let summary_duration = start_summary.elapsed();
-        println!("📊 [RUST] Summary generation took: {:.3}s", summary_duration.as_secs_f64());
+        run_cross_file_passes(directory_analysis);
         
-        let total_duration = start_total.elapsed();
-        println!("🏁 [RUST] Total directory analysis took: {:.3}s", total_duration.as_secs_f64());
+        directory_analysis.update_summary();
+        self.count_over_threshold(directory_analysis);
+    }
+    
+    /// Analyze the source entries of an archive as one project rooted at the archive
+    async fn analyze_archive(&self, archive_path: &Path) -> Result<DirectoryAnalysis> {
+        let mut directory_analysis = DirectoryAnalysis::new(archive_path.to_path_buf());
+        let (sources, warnings) = self.archive_sources(archive_path).await?;
+        directory_analysis.warnings = warnings;
         
         if self.config.verbose_output {
-            println!("✅ Analyzed {} files successfully", directory_analysis.files.len());
+            eprintln!("📦 Found {} entries to analyze in {}", sources.len(), archive_path.display());
         }
         
+        let jobs = if self.config.enable_parallel_processing { self.worker_count() } else { 1 };
+        let mut progress = Progress::stderr(self.config.progress, sources.len());
+        let mut stream = self.analyze_sources(sources).buffered(jobs);
+        while let Some((file_path, result)) = stream.next().await {
+            progress.tick();
+            match result {
+                Ok(result) => directory_analysis.files.push(result),
+                Err(e) => self.record_failure(file_path, e, &mut directory_analysis.errors, &mut directory_analysis.warnings),
+            }
+        }
+        progress.finish();
+        
+        // Entries reference each other exactly like files of a directory
+        run_cross_file_passes(&mut directory_analysis);
+        
+        directory_analysis.update_summary();
+        self.count_over_threshold(&mut directory_analysis);
+        
+        Ok(directory_analysis)
+    }
+    
+    /// Analyze a file or directory as it was at a git revision, without a checkout
+    ///
+    /// Blobs are read from the object database and reported as `<path>@<commit>`;
+    /// a path that did not exist at the revision is an error. A directory's
+    /// files pass the same filters as a walk; a file named directly is always
+    /// analyzed. Historical content is not cached.
+    pub async fn analyze_revision(&mut self, path: &Path, rev: &str, include_tests: bool) -> Result<DirectoryAnalysis> {
+        self.config.include_test_files = include_tests;
+        self.config.cache_enabled = false;
+        
+        let session = AnalysisSession::with_config(self.config.clone());
+        let (requested, rev) = (path.to_path_buf(), rev.to_string());
+        let (revision, sources, warnings) = tokio::task::spawn_blocking(move || session.read_revision_sources(&requested, &rev)).await
+            .map_err(|e| anyhow::anyhow!("Task join error: {}", e))??;
+        
+        // Rooted like a working-tree run, so snapshots of two revisions line up in `diff`
+        let root = if revision.is_file { path.parent().unwrap_or_else(|| Path::new(".")) } else { path };
+        let mut directory_analysis = DirectoryAnalysis::new(root.to_path_buf());
+        directory_analysis.revision = Some(revision.commit.clone());
+        directory_analysis.warnings = warnings;
+        
+        let jobs = if self.config.enable_parallel_processing { self.worker_count() } else { 1 };
+        let mut progress = Progress::stderr(self.config.progress, sources.len());
+        let mut stream = self.analyze_sources(sources).buffered(jobs);
+        while let Some((file_path, result)) = stream.next().await {
+            progress.tick();
+            match result {
+                Ok(result) => directory_analysis.files.push(result),
+                Err(e) => self.record_failure(file_path, e, &mut directory_analysis.errors, &mut directory_analysis.warnings),
+            }
+        }
+        progress.finish();
+        
+        run_cross_file_passes(&mut directory_analysis);
+        
+        directory_analysis.update_summary();
+        self.count_over_threshold(&mut directory_analysis);
+        
         Ok(directory_analysis)
     }
     
+    /// Helper: List and read the analyzable blobs of a revision (blocking: runs git)
+    fn read_revision_sources(&self, path: &Path, rev: &str) -> Result<(Revision, Vec<SourceEntry>, Vec<AnalysisWarning>)> {
+        let revision = Revision::open(path, rev)?;
+        let mut warnings = Vec::new();
+        let mut sources = Vec::new();
+        
+        for file in &revision.files {
+            let name = file.repo_path.as_str();
+            if !revision.is_file && !self.accepts_entry(name) {
+                continue;
+            }
+            let file_path = revision.virtual_path(file);
+            if let Some(limit) = self.config.max_file_size {
+                if file.size_bytes > limit {
+                    warnings.push(AnalysisWarning {
+                        file_path,
+                        message: FileTooLarge { size_bytes: file.size_bytes, limit }.to_string(),
+                    });
+                    continue;
+                }
+            }
+            let content = revision.read(file)?;
+            sources.extend(self.decode_source(file_path, name, content, &mut warnings));
+        }
+        
+        Ok((revision, sources, warnings))
+    }
+    
+    /// Helper: Read the analyzable entries of an archive off the async runtime
+    async fn archive_sources(&self, archive_path: &Path) -> Result<(Vec<SourceEntry>, Vec<AnalysisWarning>)> {
+        let session = AnalysisSession::with_config(self.config.clone());
+        let archive_path = archive_path.to_path_buf();
+        tokio::task::spawn_blocking(move || session.read_archive_sources(&archive_path)).await
+            .map_err(|e| anyhow::anyhow!("Task join error: {}", e))?
+    }
+    
+    /// Helper: Decode the entries that pass the walk filters into sources
+    ///
+    /// Oversized, binary and non-UTF-8 entries of a supported language become
+    /// warnings; anything without a supported language is skipped silently.
+    fn read_archive_sources(&self, archive_path: &Path) -> Result<(Vec<SourceEntry>, Vec<AnalysisWarning>)> {
+        let (entries, skipped) = archive::read_entries(archive_path, self.config.max_file_size, |name| self.accepts_entry(name))?;
+        let mut warnings: Vec<AnalysisWarning> = skipped.into_iter()
+            .map(|entry| AnalysisWarning {
+                file_path: archive::entry_path(archive_path, &entry.name),
+                message: FileTooLarge { size_bytes: entry.size_bytes, limit: self.config.max_file_size.unwrap_or(0) }.to_string(),
+            })
+            .collect();
+        
+        let sources = entries.into_iter()
+            .filter_map(|entry| {
+                let file_path = archive::entry_path(archive_path, &entry.name);
+                self.decode_source(file_path, &entry.name, entry.content, &mut warnings)
+            })
+            .collect();
+        
+        Ok((sources, warnings))
+    }
+    
+    /// Helper: An in-memory file as a source of a supported, enabled language
+    ///
+    /// `name` picks the language (extension, else shebang); binary content,
+    /// and non-UTF-8 content under `strict_utf8`, becomes a warning.
+    fn decode_source(&self, file_path: PathBuf, name: &str, content: Vec<u8>, warnings: &mut Vec<AnalysisWarning>) -> Option<SourceEntry> {
+        let first_line = content.split(|&b| b == b'\n').next().unwrap_or_default();
+        // Globs are validated when the flags are parsed
+        let language_map = LanguageMap::new(&self.config).ok()?;
+        let language = Self::detect_language(&language_map, Path::new(name), &String::from_utf8_lossy(first_line));
+        if language == Language::Unknown || !self.is_language_enabled(language) {
+            return None;
+        }
+        if content.contains(&0) {
+            warnings.push(AnalysisWarning { file_path, message: "Skipped: binary content".to_string() });
+            return None;
+        }
+        match crate::core::source::decode(content, self.config.strict_utf8) {
+            Ok((content, encoding_warning)) => Some(SourceEntry { path: file_path, content, language, encoding_warning }),
+            Err(_) => {
+                warnings.push(AnalysisWarning { file_path, message: "Skipped: not valid UTF-8".to_string() });
+                None
+            }
+        }
+    }
+    
+    /// Helper: Whether an entry name passes the language, exclusion and test filters of `discover_files`
+    fn accepts_entry(&self, name: &str) -> bool {
+        // Rooted so directory heuristics like `/tests/` also match top-level entries
+        let path = Path::new("/").join(name);
+        if self.should_exclude_path(&path) || (!self.config.include_test_files && self.is_test_file(&path)) {
+            return false;
+        }
+        if let Some(language) = LanguageMap::new(&self.config).ok().and_then(|map| map.language_of(Path::new(name))) {
+            return self.is_language_enabled(language);
+        }
+        match path.extension().and_then(|e| e.to_str()) {
+            Some(extension) => {
+                let ext_with_dot = format!(".{}", extension);
+                self.config.included_extensions.contains(&ext_with_dot)
+                    && self.is_language_enabled(Language::from_extension(&ext_with_dot))
+            }
+            // Extension-less scripts are decided by their shebang once read
+            None => true,
+        }
+    }
+    
+    /// Helper: Analyze in-memory sources on blocking workers, like `analyze_files`
+    fn analyze_sources(&self, sources: Vec<SourceEntry>) -> impl futures::Stream<Item = impl std::future::Future<Output = (PathBuf, Result<AnalysisResult>)>> {
+        let config = self.config.clone();
+        futures::stream::iter(sources).map(move |source| {
+            let config = config.clone();
+            async move {
+                let file_path = source.path.clone();
+                let result = tokio::task::spawn_blocking(move || {
+                    let temp_session = AnalysisSession::with_config(config);
+                    tokio::runtime::Handle::current().block_on(async {
+                        let result = temp_session.analyze_source(&source.content, &source.path, source.language).await;
+                        result.map(|mut result| {
+                            result.file_info.encoding_warning = source.encoding_warning;
+                            result
+                        })
+                    })
+                }).await;
+                
+                let result = match result {
+                    Ok(result) => result,
+                    Err(e) => Err(anyhow::anyhow!("Task join error: {}", e)),
+                };
+                (file_path, result)
+            }
+        })
+    }
+    
+    /// Helper: File a per-file failure; files skipped on purpose are warnings, not errors
+    fn record_failure(&self, file_path: PathBuf, e: anyhow::Error, errors: &mut Vec<AnalysisError>, warnings: &mut Vec<AnalysisWarning>) {
+        let message = format!("{:#}", e);
+        if e.downcast_ref::<GeneratedFile>().is_some() {
+            if self.config.verbose_output {
+                eprintln!("⏭️  {}: {}", file_path.display(), message);
+            }
+        } else if e.downcast_ref::<FileTooLarge>().is_some() {
+            if self.config.verbose_output {
+                eprintln!("⚠️  {}: {}", file_path.display(), message);
+            }
+            warnings.push(AnalysisWarning { file_path, message });
+        } else {
+            if self.config.verbose_output {
+                eprintln!("⚠️  Failed to analyze {}: {}", file_path.display(), message);
+            }
+            errors.push(AnalysisError { file_path, message });
+        }
+    }
+    
+    /// Count functions above the configured complexity thresholds into the summary
+    fn count_over_threshold(&self, analysis: &mut DirectoryAnalysis) {
+        analysis.summary.functions_over_threshold = analysis.files.iter()
+            .map(|file| self.functions_over_threshold(file))
+            .sum();
+    }
+    
+    /// Functions / methods of one file above `max_cyclomatic` / `max_cognitive`
+    pub fn functions_over_threshold(&self, file: &AnalysisResult) -> u32 {
+        let (max_cyclomatic, max_cognitive) = (self.config.max_cyclomatic, self.config.max_cognitive);
+        if max_cyclomatic.is_none() && max_cognitive.is_none() {
+            return 0;
+        }
+        
+        let over = |func: &&crate::core::types::FunctionInfo| {
+            max_cyclomatic.map_or(false, |max| func.complexity.cyclomatic_complexity > max)
+                || max_cognitive.map_or(false, |max| func.complexity.cognitive_complexity > max)
+        };
+        // Some analyzers list methods both at file level and under their class
+        let methods = file.classes.iter()
+            .flat_map(|c| c.methods.iter())
+            .filter(|m| !file.functions.iter().any(|f| f.name == m.name && f.start_line == m.start_line));
+        file.functions.iter().chain(methods).filter(over).count() as u32
+    }
+    
+    /// Number of files analyzed concurrently (`max_threads`, 0 = logical CPUs)
+    pub fn worker_count(&self) -> usize {
+        if self.config.max_threads > 0 {
+            self.config.max_threads
+        } else {
+            std::thread::available_parallelism().map(|n| n.get()).unwrap_or(4)
+        }
+    }
+    
     /// Discover files in a directory based on configuration
-    fn discover_files(&self, dir_path: &Path) -> Result<Vec<PathBuf>> {
+    pub fn discover_files(&self, dir_path: &Path) -> Result<Vec<PathBuf>> {
         let mut files = Vec::new();
+        let path_filter = PathFilter::new(&self.config)?;
+        let language_map = LanguageMap::new(&self.config)?;
+        let only_files: Option<HashSet<&PathBuf>> = self.config.only_files.as_ref().map(|files| files.iter().collect());
+        let build_tags = self.config.build_tags.as_ref().map(|tags| BuildTags::new(tags));
+        
+        // .gitignore / .nekocodeignore rules apply to their own subtree, like git
+        let mut walker = WalkBuilder::new(dir_path);
+        walker
+            .follow_links(self.config.follow_symlinks)
+            .hidden(false)
+            .ignore(false)
+            .git_global(false)
+            .require_git(false);
+        if self.config.respect_ignore_files {
+            walker.add_custom_ignore_filename(IGNORE_FILE_NAME);
+        } else {
+            walker.parents(false).git_ignore(false).git_exclude(false);
+        }
+        // A symlink back up the tree would otherwise be walked forever: enter
+        // each real directory (device + inode) once
+        if self.config.follow_symlinks {
+            let visited = std::sync::Mutex::new(HashSet::new());
+            walker.filter_entry(move |entry| {
+                if !entry.file_type().map_or(false, |t| t.is_dir()) {
+                    return true;
+                }
+                match file_identity(entry.path()) {
+                    Some(identity) => visited.lock().unwrap().insert(identity),
+                    None => true,
+                }
+            });
+        }
+        
+        // Symlinked files are analyzed once, under their real path if that is walked too
+        let mut seen_files = HashSet::new();
+        let mut symlinked_files = Vec::new();
         
-        for entry in WalkDir::new(dir_path)
-            .follow_links(false)
-            .into_iter()
-            .filter_map(|e| e.ok())
-        {
+        for entry in walker.build().filter_map(|e| e.ok()) {
             let path = entry.path();
             
             // Skip directories
@@ -1088,28 +1659,113 @@ impl AnalysisSession {
             }
             
             // Check if path should be excluded
-            if self.should_exclude_path(path) {
+            if self.should_exclude_path(path) || !path_filter.matches(path) {
                 continue;
             }
             
-            // Check if file extension is supported
-            if let Some(extension) = path.extension().and_then(|e| e.to_str()) {
+            // `--since`: only files changed relative to the reference
+            if let Some(only_files) = &only_files {
+                let is_listed = std::fs::canonicalize(path).map_or(false, |canonical| only_files.contains(&canonical));
+                if !is_listed {
+                    continue;
+                }
+            }
+            
+            // `--lang-map` decides before the extension; unmatched languages drop out silently
+            if let Some(language) = language_map.language_of(path) {
+                if !self.is_language_enabled(language) {
+                    continue;
+                }
+                if !self.config.include_test_files && self.is_test_file(path) {
+                    continue;
+                }
+            } else if let Some(extension) = path.extension().and_then(|e| e.to_str()) {
+                // Check if file extension is supported
                 let ext_with_dot = format!(".{}", extension);
                 
-                if self.config.included_extensions.contains(&ext_with_dot) {
-                    // Skip test files if not requested
-                    if !self.config.include_test_files && self.is_test_file(path) {
+                if !self.config.included_extensions.contains(&ext_with_dot)
+                    || !self.is_language_enabled(Language::from_extension(&ext_with_dot))
+                {
+                    continue;
+                }
+                
+                // Skip test files if not requested
+                if !self.config.include_test_files && self.is_test_file(path) {
+                    continue;
+                }
+                
+                // `--build-tags`: Go files excluded from the requested build
+                if let Some(build_tags) = &build_tags {
+                    let included = ext_with_dot != ".go"
+                        || fs::read_to_string(path).map_or(true, |source| build_tags.includes(path, &source));
+                    if !included {
                         continue;
                     }
-                    
-                    files.push(path.to_path_buf());
                 }
+            } else {
+                // Extension-less scripts (e.g. bin/rails with #!/usr/bin/env ruby)
+                let language = self.detect_shebang_language(path);
+                if language == Language::Unknown || !self.is_language_enabled(language) {
+                    continue;
+                }
+                if !self.config.include_test_files && self.is_test_file(path) {
+                    continue;
+                }
+            }
+            
+            if entry.path_is_symlink() {
+                symlinked_files.push(path.to_path_buf());
+            } else if file_identity(path).map_or(true, |identity| seen_files.insert(identity)) {
+                files.push(path.to_path_buf());
+            }
+        }
+        
+        for path in symlinked_files {
+            if file_identity(&path).map_or(false, |identity| seen_files.insert(identity)) {
+                files.push(path);
             }
         }
         
         Ok(files)
     }
     
+    /// Language of a file: a `--lang-map` override, else the extension, else the shebang line
+    fn detect_language(language_map: &LanguageMap, path: &Path, first_line: &str) -> Language {
+        if let Some(language) = language_map.language_of(path) {
+            return language;
+        }
+        let language = path.extension()
+            .and_then(|e| e.to_str())
+            .map_or(Language::Unknown, |extension| Language::from_extension(&format!(".{}", extension)));
+        if language == Language::Unknown {
+            Language::from_shebang(first_line)
+        } else {
+            language
+        }
+    }
+    
+    /// Whether `[languages] enabled` / `--lang` (if set) allows a language
+    fn is_language_enabled(&self, language: Language) -> bool {
+        self.config.enabled_languages.is_empty() || self.config.enabled_languages.contains(&language)
+    }
+    
+    /// Detect language from the shebang line of a file without extension
+    fn detect_shebang_language(&self, path: &Path) -> Language {
+        use std::io::{BufRead, BufReader};
+        
+        let file = match fs::File::open(path) {
+            Ok(file) => file,
+            Err(_) => return Language::Unknown,
+        };
+        
+        let mut first_line = String::new();
+        if BufReader::new(file).read_line(&mut first_line).is_err() {
+            return Language::Unknown;
+        }
+        
+        Language::from_shebang(&first_line)
+    }
+    
     /// Check if a path should be excluded based on patterns
     fn should_exclude_path(&self, path: &Path) -> bool {
         let path_str = path.to_string_lossy();
@@ -1125,6 +1781,11 @@ impl AnalysisSession {
     
     /// Check if a file is a test file
     fn is_test_file(&self, path: &Path) -> bool {
+        Self::is_test_path(path)
+    }
+    
+    /// Test file heuristic shared with whole-project reports
+    pub fn is_test_path(path: &Path) -> bool {
         let file_name = path.file_name()
             .and_then(|n| n.to_str())
             .unwrap_or("")
@@ -1147,17 +1808,73 @@ impl AnalysisSession {
     }
     
     /// Analyze a specific file
+    ///
+    /// Files over `max_file_size` fail with `FileTooLarge` before they are read; past
+    /// `max_parse_bytes` only a prefix is read, so the remainder is never buffered.
+    /// Other large files are memory-mapped (see [`SourceText`]). The source and its
+    /// parse tree are dropped once the result is extracted.
     pub async fn analyze_file(&self, file_path: &Path) -> Result<AnalysisResult> {
-        // Read file content
-        let content = tokio::fs::read_to_string(file_path).await
-            .with_context(|| format!("Failed to read file: {}", file_path.display()))?;
-        
-        // Create file info
         let metadata = tokio::fs::metadata(file_path).await
             .with_context(|| format!("Failed to get metadata for: {}", file_path.display()))?;
+        if let Some(limit) = self.config.max_file_size {
+            if metadata.len() > limit {
+                return Err(FileTooLarge { size_bytes: metadata.len(), limit }.into());
+            }
+        }
+        
+        // Read file content (large files are mapped, not copied)
+        let strict_utf8 = self.config.strict_utf8;
+        let ((content, encoding_warning), truncated) = match self.config.max_parse_bytes {
+            Some(limit) if metadata.len() > limit => {
+                let (prefix, warning) = read_prefix(file_path, limit, strict_utf8).await?;
+                ((SourceText::Read(prefix), warning), true)
+            }
+            _ => (SourceText::load(file_path, metadata.len(), strict_utf8).await?, false),
+        };
         
+        let language_map = LanguageMap::new(&self.config)?;
+        let language = Self::detect_language(&language_map, file_path, content.lines().next().unwrap_or(""));
+        
+        let mut result = self.analyze_content(&content, file_path, language, metadata.len()).await?;
+        result.file_info.encoding_warning = encoding_warning;
+        if truncated {
+            result.errors.push(format!(
+                "Parsed only the first {} of {} bytes (max parse bytes); later symbols are missing",
+                content.len(), metadata.len()
+            ));
+        }
+        Ok(result)
+    }
+    
+    /// Analyze in-memory source (stdin, unsaved editor buffers) under a synthetic path
+    pub async fn analyze_source(&self, content: &str, file_path: &Path, language: Language) -> Result<AnalysisResult> {
+        self.analyze_content(content, file_path, language, content.len() as u64).await
+    }
+    
+    /// Helper: Analyze `content` as `language`, reporting it as `file_path`
+    ///
+    /// A Jupyter notebook is analyzed as the Python of its code cells.
+    async fn analyze_content(&self, content: &str, file_path: &Path, language: Language, size_bytes: u64) -> Result<AnalysisResult> {
+        if language == Language::Python && is_notebook(file_path) {
+            let notebook = NotebookSource::parse(content)?;
+            let mut result = self.analyze_code(&notebook.source, file_path, language, size_bytes).await?;
+            let name = file_path.file_name().map(|n| n.to_string_lossy().into_owned()).unwrap_or_default();
+            notebook.annotate(&mut result, &name);
+            return Ok(result);
+        }
+        self.analyze_code(content, file_path, language, size_bytes).await
+    }
+    
+    /// Helper: Analyze source code `content` as `language`, reporting it as `file_path`
+    async fn analyze_code(&self, content: &str, file_path: &Path, language: Language, size_bytes: u64) -> Result<AnalysisResult> {
+        let generated = crate::core::generated::is_generated(content);
+        if generated && self.config.exclude_generated {
+            return Err(GeneratedFile.into());
+        }
+        
+        // Create file info
         let mut file_info = FileInfo::new(file_path.to_path_buf());
-        file_info.size_bytes = metadata.len();
+        file_info.size_bytes = size_bytes;
         file_info.total_lines = content.lines().count() as u32;
         
         // Calculate basic line statistics
@@ -1178,12 +1895,20 @@ impl AnalysisSession {
             0.0
         };
         
-        // Determine language
-        let language = if let Some(extension) = file_path.extension().and_then(|e| e.to_str()) {
-            Language::from_extension(&format!(".{}", extension))
-        } else {
-            Language::Unknown
-        };
+        // Unchanged content: reuse the cached result
+        let cache = self.cache();
+        if let Some(ref cache) = cache {
+            if let Some(mut cached) = cache.get(language, content, &file_info) {
+                // Entries are keyed on content alone; Python / Rust namespaces come from this path
+                crate::core::namespace::annotate_namespaces(&mut cached);
+                // Stable ids hash the namespace
+                crate::core::stable_id::annotate_stable_ids(&mut cached);
+                // Cached results are unfiltered so any --visibility / --markers can reuse them
+                self.apply_output_filters(&mut cached);
+                cached.diagnostics = self.parse_diagnostics(content, language, file_path);
+                return Ok(cached);
+            }
+        }
         
         // Create base analysis result
         let mut result = AnalysisResult::new(file_info, language);
@@ -1194,7 +1919,7 @@ impl AnalysisSession {
                 // 🚀 Always use Tree-sitter (fastest parser)
                 let mut analyzer = TreeSitterJavaScriptAnalyzer::new()
                     .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter analyzer: {}", e))?;
-                result = analyzer.analyze(&content, file_path.to_string_lossy().as_ref()).await?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
                 result.language = language; // Ensure correct language is set
             }
             Language::Python => {
@@ -1202,7 +1927,7 @@ impl AnalysisSession {
                 use crate::analyzers::python::TreeSitterPythonAnalyzer;
                 let mut analyzer = TreeSitterPythonAnalyzer::new()
                     .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Python analyzer: {}", e))?;
-                result = analyzer.analyze(&content, file_path.to_string_lossy().as_ref()).await?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
                 result.language = language; // Ensure correct language is set
             }
             Language::Cpp => {
@@ -1210,7 +1935,7 @@ impl AnalysisSession {
                 use crate::analyzers::cpp::TreeSitterCppAnalyzer;
                 let mut analyzer = TreeSitterCppAnalyzer::new()
                     .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter C++ analyzer: {}", e))?;
-                result = analyzer.analyze(&content, file_path.to_string_lossy().as_ref()).await?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
                 result.language = language; // Ensure correct language is set
             }
             Language::CSharp => {
@@ -1218,7 +1943,7 @@ impl AnalysisSession {
                 use crate::analyzers::csharp::TreeSitterCSharpAnalyzer;
                 let mut analyzer = TreeSitterCSharpAnalyzer::new()
                     .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter C# analyzer: {}", e))?;
-                result = analyzer.analyze(&content, file_path.to_string_lossy().as_ref()).await?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
                 result.language = language; // Ensure correct language is set
             }
             Language::Go => {
@@ -1226,7 +1951,7 @@ impl AnalysisSession {
                 use crate::analyzers::go::TreeSitterGoAnalyzer;
                 let mut analyzer = TreeSitterGoAnalyzer::new()
                     .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Go analyzer: {}", e))?;
-                result = analyzer.analyze(&content, file_path.to_string_lossy().as_ref()).await?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
                 result.language = language; // Ensure correct language is set
             }
             Language::Rust => {
@@ -1234,7 +1959,70 @@ impl AnalysisSession {
                 use crate::analyzers::rust::TreeSitterRustAnalyzer;
                 let mut analyzer = TreeSitterRustAnalyzer::new()
                     .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Rust analyzer: {}", e))?;
-                result = analyzer.analyze(&content, file_path.to_string_lossy().as_ref()).await?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Ruby => {
+                use crate::analyzers::ruby::TreeSitterRubyAnalyzer;
+                let mut analyzer = TreeSitterRubyAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Ruby analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Java => {
+                use crate::analyzers::java::TreeSitterJavaAnalyzer;
+                let mut analyzer = TreeSitterJavaAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Java analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Kotlin => {
+                use crate::analyzers::kotlin::TreeSitterKotlinAnalyzer;
+                let mut analyzer = TreeSitterKotlinAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Kotlin analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Swift => {
+                use crate::analyzers::swift::TreeSitterSwiftAnalyzer;
+                let mut analyzer = TreeSitterSwiftAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Swift analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Php => {
+                use crate::analyzers::php::TreeSitterPhpAnalyzer;
+                let mut analyzer = TreeSitterPhpAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter PHP analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Lua => {
+                use crate::analyzers::lua::TreeSitterLuaAnalyzer;
+                let mut analyzer = TreeSitterLuaAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Lua analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Dart => {
+                use crate::analyzers::dart::TreeSitterDartAnalyzer;
+                let mut analyzer = TreeSitterDartAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Dart analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Scala => {
+                use crate::analyzers::scala::TreeSitterScalaAnalyzer;
+                let mut analyzer = TreeSitterScalaAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Scala analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
+                result.language = language; // Ensure correct language is set
+            }
+            Language::Zig => {
+                use crate::analyzers::zig::TreeSitterZigAnalyzer;
+                let mut analyzer = TreeSitterZigAnalyzer::new()
+                    .map_err(|e| anyhow::anyhow!("Failed to create tree-sitter Zig analyzer: {}", e))?;
+                result = analyzer.analyze(content, file_path.to_string_lossy().as_ref()).await?;
                 result.language = language; // Ensure correct language is set
             }
             Language::Unknown => {
@@ -1249,12 +2037,76 @@ impl AnalysisSession {
             }
         }
         
+        // Direct recursion only needs the file's own call graph
+        crate::core::callgraph::mark_recursive(std::slice::from_mut(&mut result));
+        
+        // Package / namespace / module of every symbol
+        crate::core::namespace::annotate_namespaces(&mut result);
+        
+        // Normalized kind of every symbol, next to the language's own term
+        crate::core::kinds::annotate_kinds(&mut result);
+        
+        // Line-independent identity of every symbol (diff and baseline key)
+        crate::core::stable_id::annotate_stable_ids(&mut result);
+        
+        // A recovered tree still yields symbols; flag the file and where parsing failed
+        if let Some(parse_errors) = crate::core::validate::syntax_errors(content, language, file_path) {
+            result.partial = !parse_errors.is_empty();
+            result.parse_errors = parse_errors;
+        }
+        
+        // Content hash for change tracking
+        result.content_hash = crate::metrics::content_hash(content);
+        result.file_info.generated = generated;
+        
         // Update statistics
         result.update_statistics();
         
+        // A cache write failure only costs a re-parse next time
+        if let (Some(cache), true) = (cache, language != Language::Unknown) {
+            if let Err(e) = cache.put(language, content, &result) {
+                if self.config.verbose_output {
+                    eprintln!("⚠️  Failed to cache {}: {}", file_path.display(), e);
+                }
+            }
+        }
+        
+        self.apply_output_filters(&mut result);
+        // Set after the cache write: timings describe this run, not the cached one
+        result.diagnostics = self.parse_diagnostics(content, language, file_path);
         Ok(result)
     }
     
+    /// Helper: Parse diagnostics when `--diagnostics` is on
+    fn parse_diagnostics(&self, content: &str, language: Language, file_path: &Path) -> Option<ParseDiagnostics> {
+        if !self.config.diagnostics {
+            return None;
+        }
+        crate::core::diagnostics::measure(content, language, file_path)
+    }
+    
+    /// Helper: Weigh a file result's complexity, then narrow it to the configured visibility, query and marker tags
+    fn apply_output_filters(&self, result: &mut AnalysisResult) {
+        // Queries compare the weighted complexity that is output
+        self.config.complexity_weights.apply(result);
+        self.config.smells.apply(result);
+        self.config.visibility.filter(result);
+        if let Some(query) = &self.config.query {
+            query.filter(result);
+        }
+        result.markers.retain(|marker| self.config.markers.iter().any(|tag| tag == &marker.kind));
+    }
+    
+    /// Analysis cache, when enabled
+    fn cache(&self) -> Option<AnalysisCache> {
+        if !self.config.cache_enabled {
+            return None;
+        }
+        
+        let cache_dir = self.config.cache_dir.clone().unwrap_or_else(|| PathBuf::from(CACHE_DIR));
+        Some(AnalysisCache::new(cache_dir))
+    }
+    
     // Session-specific methods for new functionality
     pub fn get_stats(&self) -> String {
         "Session statistics placeholder".to_string()
@@ -1277,6 +2129,109 @@ impl AnalysisSession {
     }
 }
 
+/// Helper: Passes that resolve references between the files of one analysis
+fn run_cross_file_passes(analysis: &mut DirectoryAnalysis) {
+    // Go interfaces are satisfied across files of the analyzed set
+    crate::analyzers::go::link_implementations(&mut analysis.files);
+    // Ignored errors of callees defined in other files / packages
+    crate::analyzers::go::link_unchecked_errors(&mut analysis.files);
+    // Package-level variables initialize in dependency order across a package's files
+    analysis.package_inits = crate::analyzers::go::order_initialization(&mut analysis.files);
+    // Swift extensions add conformances to types declared in other files
+    crate::analyzers::swift::link_conformances(&mut analysis.files);
+    // C++ prototypes in headers, definitions in sources
+    crate::analyzers::cpp::link_declarations(&mut analysis.files);
+    // Fan-in / fan-out over the whole set, once calls into other files resolve
+    crate::core::callgraph::annotate_coupling(&mut analysis.files);
+}
+
+/// Identity of the file or directory a path resolves to: device and inode
+#[cfg(unix)]
+type FileIdentity = (u64, u64);
+
+/// Identity of the file or directory a path resolves to: its canonical path
+#[cfg(not(unix))]
+type FileIdentity = PathBuf;
+
+/// Helper: Identity of a path, following symlinks (`None` if it cannot be read)
+#[cfg(unix)]
+fn file_identity(path: &Path) -> Option<FileIdentity> {
+    use std::os::unix::fs::MetadataExt;
+    
+    let metadata = fs::metadata(path).ok()?;
+    Some((metadata.dev(), metadata.ino()))
+}
+
+/// Helper: Identity of a path, following symlinks (`None` if it cannot be read)
+#[cfg(not(unix))]
+fn file_identity(path: &Path) -> Option<FileIdentity> {
+    fs::canonicalize(path).ok()
+}
+
+/// Helper: Deepest directory containing every path, compared by components
+/// (`src/api` and `src/db/pool.go` share `src`; unrelated paths share `.`)
+fn common_ancestor(paths: &[PathBuf]) -> PathBuf {
+    let dirs = paths.iter().map(|path| {
+        if path.is_file() { path.parent().unwrap_or_else(|| Path::new(".")) } else { path.as_path() }
+    });
+    let mut common: Option<Vec<std::path::Component>> = None;
+    for dir in dirs {
+        let components: Vec<_> = dir.components().collect();
+        common = Some(match common {
+            None => components,
+            Some(prefix) => prefix.into_iter()
+                .zip(components)
+                .take_while(|(a, b)| a == b)
+                .map(|(a, _)| a)
+                .collect(),
+        });
+    }
+    let ancestor: PathBuf = common.unwrap_or_default().into_iter().collect();
+    if ancestor.as_os_str().is_empty() { PathBuf::from(".") } else { ancestor }
+}
+
+/// Helper: The first `limit` bytes of a file, cut back to the end of the last complete
+/// line, decoded like [`SourceText::load`]
+async fn read_prefix(path: &Path, limit: u64, strict_utf8: bool) -> Result<(String, Option<String>)> {
+    use tokio::io::AsyncReadExt;
+
+    let file = tokio::fs::File::open(path).await
+        .with_context(|| format!("Failed to read file: {}", path.display()))?;
+    let mut bytes = Vec::with_capacity(limit as usize);
+    file.take(limit).read_to_end(&mut bytes).await
+        .with_context(|| format!("Failed to read file: {}", path.display()))?;
+    if let Some(end) = bytes.iter().rposition(|&b| b == b'\n') {
+        bytes.truncate(end + 1);
+    }
+
+    // A single long line cut inside a multi-byte character
+    let incomplete_tail = match std::str::from_utf8(&bytes) {
+        Err(e) if e.error_len().is_none() => Some(e.valid_up_to()),
+        _ => None,
+    };
+    if let Some(valid) = incomplete_tail {
+        bytes.truncate(valid);
+    }
+    
+    crate::core::source::decode(bytes, strict_utf8)
+        .with_context(|| format!("Failed to read file: {}", path.display()))
+}
+
+/// Helper: Peak resident set size of this process (`VmHWM`), where the OS reports it
+#[cfg(target_os = "linux")]
+fn peak_rss_bytes() -> Option<u64> {
+    let status = fs::read_to_string("/proc/self/status").ok()?;
+    let line = status.lines().find(|line| line.starts_with("VmHWM:"))?;
+    let kib: u64 = line.trim_start_matches("VmHWM:").trim().trim_end_matches("kB").trim().parse().ok()?;
+    Some(kib * 1024)
+}
+
+/// Helper: Peak resident set size of this process (`VmHWM`), where the OS reports it
+#[cfg(not(target_os = "linux"))]
+fn peak_rss_bytes() -> Option<u64> {
+    None
+}
+
 impl Default for AnalysisSession {
     fn default() -> Self {
         Self::new()
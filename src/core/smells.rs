@@ -0,0 +1,187 @@
+//! 👃 Code smells (`smells` on each function)
+//!
+//! - `magic_number`: a numeric literal other than 0 / 1 used inline, found by
+//!   the analyzers (see [`crate::metrics::magic_numbers`])
+//! - `long_parameter_list`: more than `max_params` parameters (default 5);
+//!   `self`, `&self`, `this` and `cls` receivers are not counted
+//! - `long_function`: a span of more than `max_function_loc` physical lines
+//!   (off by default), from the declaration line to the closing line; doc
+//!   comments above are not counted
+//!
+//! All are settled per run from [`SmellConfig`] after the cache, so a
+//! different `--max-params` or `[smells]` section never needs a re-parse.
+
+use serde::{Deserialize, Serialize};
+
+use crate::core::types::{AnalysisResult, FunctionInfo, SmellInfo};
+use crate::metrics::magic_numbers::literal_value;
+
+/// Default `max_params`
+pub const DEFAULT_MAX_PARAMS: usize = 5;
+
+/// Parameters that name the receiver rather than an argument
+const RECEIVERS: &[&str] = &["self", "&self", "&mut self", "mut self", "cls", "this"];
+
+/// Which smells are reported
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize)]
+pub struct SmellConfig {
+    /// Functions with more parameters than this get a `long_parameter_list` smell
+    #[serde(default = "default_max_params")]
+    pub max_params: usize,
+    /// Report `magic_number` smells at all
+    #[serde(default = "default_magic_numbers")]
+    pub magic_numbers: bool,
+    /// Values tolerated inline besides 0 and 1 (`[2, 100]`)
+    #[serde(default)]
+    pub allowed_numbers: Vec<f64>,
+    /// Functions spanning more lines than this get a `long_function` smell (None = off)
+    #[serde(default)]
+    pub max_function_loc: Option<u32>,
+}
+
+impl Default for SmellConfig {
+    fn default() -> Self {
+        Self {
+            max_params: DEFAULT_MAX_PARAMS,
+            magic_numbers: true,
+            allowed_numbers: Vec::new(),
+            max_function_loc: None,
+        }
+    }
+}
+
+/// Helper: serde default of `SmellConfig::max_params`
+fn default_max_params() -> usize {
+    DEFAULT_MAX_PARAMS
+}
+
+/// Helper: serde default of `SmellConfig::magic_numbers`
+fn default_magic_numbers() -> bool {
+    true
+}
+
+impl SmellConfig {
+    /// Narrow the analyzers' magic numbers and add parameter-list / long-function smells
+    pub fn apply(&self, result: &mut AnalysisResult) {
+        let methods = result.classes.iter_mut().flat_map(|class| class.methods.iter_mut());
+        for func in result.functions.iter_mut().chain(methods) {
+            self.apply_to_function(func);
+        }
+    }
+
+    /// Helper: Smells of one function; safe to repeat on the same result
+    fn apply_to_function(&self, func: &mut FunctionInfo) {
+        func.smells.retain(|smell| match smell.kind.as_str() {
+            SmellInfo::MAGIC_NUMBER => self.magic_numbers && !self.is_allowed(&smell.value),
+            SmellInfo::LONG_PARAMETER_LIST | SmellInfo::LONG_FUNCTION => false,
+            _ => true,
+        });
+
+        let count = parameter_count(func);
+        if count > self.max_params {
+            func.smells.push(SmellInfo {
+                kind: SmellInfo::LONG_PARAMETER_LIST.to_string(),
+                line: func.start_line,
+                value: count.to_string(),
+                message: format!("{} parameters (max {})", count, self.max_params),
+            });
+        }
+
+        let lines = function_lines(func);
+        if let Some(max_lines) = self.max_function_loc.filter(|&max_lines| lines > max_lines) {
+            func.smells.push(SmellInfo {
+                kind: SmellInfo::LONG_FUNCTION.to_string(),
+                line: func.start_line,
+                value: lines.to_string(),
+                message: format!("{} lines (max {})", lines, max_lines),
+            });
+        }
+    }
+
+    /// Helper: Whether a literal's value is in `allowed_numbers`
+    fn is_allowed(&self, literal: &str) -> bool {
+        literal_value(literal).is_some_and(|value| self.allowed_numbers.contains(&value))
+    }
+}
+
+/// Parameters of a function, receivers excluded
+pub fn parameter_count(func: &FunctionInfo) -> usize {
+    func.parameters.iter()
+        .filter(|param| !RECEIVERS.contains(&param.trim()))
+        .count()
+}
+
+/// Physical lines from the declaration to the closing line (the analyzer's
+/// `loc` when the span is unknown)
+pub fn function_lines(func: &FunctionInfo) -> u32 {
+    if func.end_line >= func.start_line && func.start_line > 0 {
+        func.end_line - func.start_line + 1
+    } else {
+        func.loc
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, Language};
+    use std::path::PathBuf;
+
+    fn magic(value: &str) -> SmellInfo {
+        SmellInfo {
+            kind: SmellInfo::MAGIC_NUMBER.to_string(),
+            line: 3,
+            value: value.to_string(),
+            message: format!("magic number {}", value),
+        }
+    }
+
+    #[test]
+    fn test_apply() {
+        let mut func = FunctionInfo::new("connect".to_string());
+        func.start_line = 2;
+        func.parameters = ["self", "host", "port", "user", "password", "timeout", "retries"]
+            .iter().map(|p| p.to_string()).collect();
+        func.smells = vec![magic("42"), magic("0x64")];
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("db.py")), Language::Python);
+        result.functions.push(func);
+
+        let config = SmellConfig { allowed_numbers: vec![100.0], ..SmellConfig::default() };
+        config.apply(&mut result);
+        config.apply(&mut result);
+
+        let smells = &result.functions[0].smells;
+        assert_eq!(smells.len(), 2);
+        assert_eq!(smells[0].value, "42");
+        assert_eq!(smells[1].kind, SmellInfo::LONG_PARAMETER_LIST);
+        assert_eq!((smells[1].line, smells[1].value.as_str()), (2, "6"));
+
+        let config = SmellConfig { max_params: 6, magic_numbers: false, ..SmellConfig::default() };
+        config.apply(&mut result);
+        assert!(result.functions[0].smells.is_empty());
+    }
+
+    #[test]
+    fn test_long_function() {
+        let mut func = FunctionInfo::new("handle".to_string());
+        func.start_line = 10;
+        func.end_line = 70;
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("server.go")), Language::Go);
+        result.functions.push(func);
+
+        let config = SmellConfig { max_function_loc: Some(60), ..SmellConfig::default() };
+        config.apply(&mut result);
+        config.apply(&mut result);
+        let smells = &result.functions[0].smells;
+        assert_eq!(smells.len(), 1);
+        assert_eq!((smells[0].kind.as_str(), smells[0].line, smells[0].value.as_str()), (SmellInfo::LONG_FUNCTION, 10, "61"));
+        assert_eq!(smells[0].message, "61 lines (max 60)");
+
+        // Exactly at the limit, or no limit
+        let config = SmellConfig { max_function_loc: Some(61), ..SmellConfig::default() };
+        config.apply(&mut result);
+        assert!(result.functions[0].smells.is_empty());
+        SmellConfig::default().apply(&mut result);
+        assert!(result.functions[0].smells.is_empty());
+    }
+}
@@ -0,0 +1,158 @@
+//! 🗺️ Source text of an analyzed file, mapped or read
+//!
+//! Files of at least [`MMAP_THRESHOLD`] bytes are memory-mapped read-only and
+//! handed to the parser as a `&str` view of the mapping: with many workers, no
+//! worker holds a heap copy of a large file, and the kernel can drop its pages
+//! under memory pressure. Smaller files, and mappings that are not valid UTF-8
+//! (or cannot be created), fall back to an ordinary read.
+//!
+//! A read drops a UTF-8 byte order mark and decodes other invalid UTF-8
+//! lossily (U+FFFD per invalid sequence) with a warning, unless `strict_utf8`
+//! makes it an error. Analyzers only see the decoded text, so byte offsets
+//! and lines are those of the text actually parsed.
+//!
+//! No line-offset table is built up front for either kind; line numbers come
+//! from Tree-sitter positions when a symbol is extracted.
+
+use anyhow::{Context, Result};
+use memmap2::Mmap;
+use std::ops::Deref;
+use std::path::Path;
+
+/// Size from which a file is mapped instead of read (1 MiB)
+pub const MMAP_THRESHOLD: u64 = 1024 * 1024;
+
+/// UTF-8 byte order mark, dropped before parsing
+const UTF8_BOM: &[u8] = b"\xEF\xBB\xBF";
+
+/// File content, valid UTF-8 either way
+pub enum SourceText {
+    Read(String),
+    /// Checked to be UTF-8 when mapped
+    Mapped(Mmap),
+}
+
+impl SourceText {
+    /// Map `path` when it is `size` bytes or larger, else read it; also
+    /// returns the encoding warning of a lossy decode
+    pub async fn load(path: &Path, size: u64, strict_utf8: bool) -> Result<(Self, Option<String>)> {
+        if size >= MMAP_THRESHOLD {
+            if let Some(mapped) = Self::map(path) {
+                return Ok((mapped, None));
+            }
+        }
+        let bytes = tokio::fs::read(path).await
+            .with_context(|| format!("Failed to read file: {}", path.display()))?;
+        let (content, warning) = decode(bytes, strict_utf8)
+            .with_context(|| format!("Failed to read file: {}", path.display()))?;
+        Ok((Self::Read(content), warning))
+    }
+
+    /// Helper: Read-only mapping of a UTF-8 file without a byte order mark;
+    /// `None` falls back to reading
+    fn map(path: &Path) -> Option<Self> {
+        let file = std::fs::File::open(path).ok()?;
+        // SAFETY: the mapping is read-only and dropped with the analysis of the
+        // file. A file truncated by another process while mapped can fault the
+        // read, the same trade-off every mmap-based tool accepts.
+        let mmap = unsafe { Mmap::map(&file) }.ok()?;
+        if mmap.starts_with(UTF8_BOM) {
+            return None;
+        }
+        std::str::from_utf8(&mmap).ok()?;
+        Some(Self::Mapped(mmap))
+    }
+
+    /// Whether the content is a mapping rather than a heap copy
+    pub fn is_mapped(&self) -> bool {
+        matches!(self, Self::Mapped(_))
+    }
+
+    pub fn as_str(&self) -> &str {
+        match self {
+            Self::Read(content) => content,
+            // SAFETY: validated as UTF-8 in `map`; the mapping is read-only
+            Self::Mapped(mmap) => unsafe { std::str::from_utf8_unchecked(mmap) },
+        }
+    }
+}
+
+/// Text of file bytes: a leading byte order mark dropped, invalid UTF-8
+/// replaced (with a warning) or, under `strict_utf8`, an error
+pub fn decode(mut bytes: Vec<u8>, strict_utf8: bool) -> Result<(String, Option<String>)> {
+    if bytes.starts_with(UTF8_BOM) {
+        bytes.drain(..UTF8_BOM.len());
+    }
+    match String::from_utf8(bytes) {
+        Ok(content) => Ok((content, None)),
+        Err(e) => {
+            let offset = e.utf8_error().valid_up_to();
+            if strict_utf8 {
+                anyhow::bail!("Not valid UTF-8: invalid byte at offset {} (--strict-utf8)", offset);
+            }
+            let content = String::from_utf8_lossy(e.as_bytes()).into_owned();
+            let warning = format!(
+                "Not valid UTF-8 (first invalid byte at offset {}); invalid sequences decoded as U+FFFD",
+                offset
+            );
+            Ok((content, Some(warning)))
+        }
+    }
+}
+
+impl Deref for SourceText {
+    type Target = str;
+
+    fn deref(&self) -> &str {
+        self.as_str()
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[tokio::test]
+    async fn test_load() {
+        let dir = tempfile::tempdir().unwrap();
+        let small = dir.path().join("small.go");
+        std::fs::write(&small, "package main\n").unwrap();
+        let large = dir.path().join("large.go");
+        let body = "package main\n".to_string() + &"// padding\n".repeat((MMAP_THRESHOLD / 10) as usize);
+        std::fs::write(&large, &body).unwrap();
+        let binary = dir.path().join("binary.go");
+        let mut bytes = body.clone().into_bytes();
+        bytes.push(0xff);
+        std::fs::write(&binary, &bytes).unwrap();
+
+        let (text, warning) = SourceText::load(&small, 13, false).await.unwrap();
+        assert!(!text.is_mapped());
+        assert_eq!(&*text, "package main\n");
+        assert!(warning.is_none());
+
+        let (text, _) = SourceText::load(&large, body.len() as u64, false).await.unwrap();
+        assert!(text.is_mapped());
+        assert_eq!(text.as_str(), body);
+
+        // Not UTF-8: read and decoded lossily, or rejected under strict_utf8
+        let (text, warning) = SourceText::load(&binary, bytes.len() as u64, false).await.unwrap();
+        assert!(!text.is_mapped());
+        assert!(text.ends_with('\u{FFFD}'));
+        assert!(warning.unwrap().contains(&format!("offset {}", body.len())));
+        assert!(SourceText::load(&binary, bytes.len() as u64, true).await.is_err());
+    }
+
+    #[test]
+    fn test_decode() {
+        // Byte order mark dropped: offsets start at the first source byte
+        let (text, warning) = decode(b"\xEF\xBB\xBFpackage main\n".to_vec(), true).unwrap();
+        assert_eq!(text, "package main\n");
+        assert!(warning.is_none());
+
+        // Latin-1 comment
+        let (text, warning) = decode(b"// caf\xE9\nint x;\n".to_vec(), false).unwrap();
+        assert_eq!(text, "// caf\u{FFFD}\nint x;\n");
+        assert!(warning.unwrap().contains("offset 7"));
+        assert!(decode(b"// caf\xE9\n".to_vec(), true).is_err());
+    }
+}
@@ -0,0 +1,192 @@
+//! 🪪 Stable symbol identifiers
+//!
+//! `annotate_stable_ids` gives every function, method and type a `stable_id`
+//! that survives edits elsewhere in the file: a hash of the symbol's
+//! namespace, qualified name (`Server.Start`) and normalized signature, with
+//! no line number in it. Symbols that would still share an id (same-signature
+//! overloads, redefinitions) are told apart by source order: the first keeps
+//! the bare hash, later ones get `#2`, `#3`, ...
+//!
+//! The signature is the parameter types where the analyzer records them (so
+//! renaming a parameter keeps the id), else the parameter list as written,
+//! with whitespace removed. Ids are unique within a file; `diff` and
+//! `--baseline` key symbols by file and `stable_id`.
+
+use std::collections::{BTreeMap, BTreeSet};
+
+use crate::core::types::{AnalysisResult, ClassInfo, FunctionInfo};
+
+/// Set `stable_id` on every function, method, nested function and type of a file result
+pub fn annotate_stable_ids(result: &mut AnalysisResult) {
+    // Methods listed both at file level and under their class share a start
+    // line, so each distinct line is one symbol
+    let mut lines: BTreeMap<String, BTreeSet<u32>> = BTreeMap::new();
+    for func in &result.functions {
+        collect_function(func, function_owner(func).as_deref(), &mut lines);
+    }
+    for class in &result.classes {
+        lines.entry(class_key(class)).or_default().insert(class.start_line);
+        for method in &class.methods {
+            collect_function(method, Some(&class.name), &mut lines);
+        }
+    }
+
+    for func in &mut result.functions {
+        let owner = function_owner(func);
+        assign_function(func, owner.as_deref(), &lines);
+    }
+    for class in &mut result.classes {
+        class.stable_id = stable_id(class_key(class), class.start_line, &lines);
+        let owner = class.name.clone();
+        for method in &mut class.methods {
+            assign_function(method, Some(&owner), &lines);
+        }
+    }
+}
+
+/// Normalized signature of a function: parameter types if known, else the parameter list
+pub fn normalized_signature(func: &FunctionInfo) -> String {
+    let signature = match func.metadata.get("signature") {
+        Some(signature) => signature.clone(),
+        None if !func.params.is_empty() && func.params.iter().all(|p| !p.param_type.is_empty()) => {
+            let types: Vec<&str> = func.params.iter().map(|p| p.param_type.as_str()).collect();
+            format!("({})", types.join(","))
+        }
+        None => format!("({})", func.parameters.join(",")),
+    };
+    signature.split_whitespace().collect()
+}
+
+/// Helper: Receiver type (Go) or owning class recorded on a file-level function
+fn function_owner(func: &FunctionInfo) -> Option<String> {
+    func.metadata.get("receiver_type")
+        .or_else(|| func.metadata.get("class_name"))
+        .cloned()
+}
+
+/// Helper: Record the key and line of a function and of the functions nested in it
+fn collect_function(func: &FunctionInfo, owner: Option<&str>, lines: &mut BTreeMap<String, BTreeSet<u32>>) {
+    let qualified = qualified_name(owner, &func.name);
+    lines.entry(function_key(func, &qualified)).or_default().insert(func.start_line);
+    for child in &func.children {
+        collect_function(child, Some(&qualified), lines);
+    }
+}
+
+/// Helper: Set the id of a function and of the functions nested in it
+fn assign_function(func: &mut FunctionInfo, owner: Option<&str>, lines: &BTreeMap<String, BTreeSet<u32>>) {
+    let qualified = qualified_name(owner, &func.name);
+    func.stable_id = stable_id(function_key(func, &qualified), func.start_line, lines);
+    for child in &mut func.children {
+        assign_function(child, Some(&qualified), lines);
+    }
+}
+
+/// Helper: `Owner.name` or `name`
+fn qualified_name(owner: Option<&str>, name: &str) -> String {
+    match owner {
+        Some(owner) => format!("{}.{}", owner, name),
+        None => name.to_string(),
+    }
+}
+
+/// Helper: Identity of a function before overloads are told apart
+fn function_key(func: &FunctionInfo, qualified: &str) -> String {
+    format!("function\0{}\0{}\0{}", func.namespace.as_deref().unwrap_or(""), qualified, normalized_signature(func))
+}
+
+/// Helper: Identity of a type before redefinitions are told apart
+fn class_key(class: &ClassInfo) -> String {
+    format!("type\0{}\0{}", class.namespace.as_deref().unwrap_or(""), class.name)
+}
+
+/// Helper: Hash of `key`, suffixed with the symbol's position among those sharing it
+fn stable_id(key: String, line: u32, lines: &BTreeMap<String, BTreeSet<u32>>) -> String {
+    let id = blake3::hash(key.as_bytes()).to_hex()[..16].to_string();
+    let index = lines.get(&key).map_or(0, |lines| lines.range(..line).count());
+    if index == 0 {
+        id
+    } else {
+        format!("{}#{}", id, index + 1)
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, Language, ParameterInfo};
+    use std::path::PathBuf;
+
+    fn function(name: &str, line: u32, parameters: &[&str]) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = line;
+        func.parameters = parameters.iter().map(|p| p.to_string()).collect();
+        func
+    }
+
+    fn result(functions: Vec<FunctionInfo>, classes: Vec<ClassInfo>) -> AnalysisResult {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("Service.java")), Language::Java);
+        result.functions = functions;
+        result.classes = classes;
+        annotate_stable_ids(&mut result);
+        result
+    }
+
+    #[test]
+    fn test_ids_ignore_line_numbers() {
+        let before = result(vec![function("load", 3, &["String path"])], Vec::new());
+        let after = result(vec![function("load", 40, &["String  path"])], Vec::new());
+        assert_eq!(before.functions[0].stable_id.len(), 16);
+        assert_eq!(before.functions[0].stable_id, after.functions[0].stable_id);
+
+        let changed = result(vec![function("load", 3, &["String path", "boolean strict"])], Vec::new());
+        assert_ne!(before.functions[0].stable_id, changed.functions[0].stable_id);
+    }
+
+    #[test]
+    fn test_overloads_and_duplicates() {
+        let mut service = ClassInfo::new("Service".to_string());
+        service.start_line = 1;
+        service.methods = vec![
+            function("run", 2, &["int n"]),
+            function("run", 5, &["String s"]),
+            function("run", 9, &["int n"]),
+        ];
+        let classes = result(Vec::new(), vec![service]).classes;
+        let ids: Vec<&str> = classes[0].methods.iter().map(|m| m.stable_id.as_str()).collect();
+
+        // Different signatures are different ids; the same one repeated gets an index
+        assert_ne!(ids[0], ids[1]);
+        assert_eq!(ids[2], format!("{}#2", ids[0]));
+        assert!(!classes[0].stable_id.is_empty());
+    }
+
+    #[test]
+    fn test_go_methods_listed_twice_share_an_id() {
+        let mut start = function("Start", 7, &["addr string"]);
+        start.metadata.insert("receiver_type".to_string(), "Server".to_string());
+        start.params = vec![ParameterInfo { name: "addr".to_string(), param_type: "string".to_string(), variadic: false }];
+        let mut server = ClassInfo::new("Server".to_string());
+        server.methods = vec![start.clone()];
+        let result = result(vec![start], vec![server]);
+
+        assert_eq!(result.functions[0].stable_id, result.classes[0].methods[0].stable_id);
+        assert!(!result.functions[0].stable_id.contains('#'));
+
+        // Renaming the parameter keeps the id when types are known
+        let mut renamed = result.functions[0].clone();
+        renamed.params[0].name = "address".to_string();
+        assert_eq!(normalized_signature(&renamed), "(string)");
+    }
+
+    #[test]
+    fn test_namespace_is_part_of_the_id() {
+        let mut first = function("util", 1, &[]);
+        first.namespace = Some("app.a".to_string());
+        let mut second = function("util", 1, &[]);
+        second.namespace = Some("app.b".to_string());
+        let first = result(vec![first], Vec::new());
+        let second = result(vec![second], Vec::new());
+        assert_ne!(first.functions[0].stable_id, second.functions[0].stable_id);
+    }
+}
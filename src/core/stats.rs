@@ -0,0 +1,321 @@
+//! Project-wide aggregates for the `stats` command
+//!
+//! One pass over a [`DirectoryAnalysis`] producing the headline numbers:
+//! files per language, function / class totals, complexity average and
+//! maximum, SLOC, and the most complex functions with their locations.
+//! Generated files are counted but kept out of every other number.
+//! `--sort-by` / `--top` re-rank that function list by another metric.
+
+use serde::{Deserialize, Serialize};
+use std::collections::{BTreeMap, HashSet};
+use std::path::Path;
+
+use crate::core::ranking::{rank, Rankable, RankedSymbol, Ranking, SortKey};
+use crate::core::types::DirectoryAnalysis;
+
+/// Number of entries in `most_complex_functions`
+pub const TOP_COMPLEX_FUNCTIONS: usize = 10;
+
+/// One entry of the most-complex list
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ComplexFunction {
+    pub file: String,
+    pub line: u32,
+    pub name: String,
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub class_name: Option<String>,
+    pub cyclomatic_complexity: u32,
+    pub cognitive_complexity: u32,
+    #[serde(default)]
+    pub max_nesting_depth: u32,
+    #[serde(default)]
+    pub loc: u32,
+    #[serde(default)]
+    pub fan_in: u32,
+}
+
+impl ComplexFunction {
+    /// `Class.method` or `function`
+    pub fn qualified_name(&self) -> String {
+        match &self.class_name {
+            Some(class) => format!("{}.{}", class, self.name),
+            None => self.name.clone(),
+        }
+    }
+}
+
+impl Rankable for ComplexFunction {
+    fn ranked(&self) -> RankedSymbol<'_> {
+        RankedSymbol {
+            file: Path::new(&self.file),
+            line: self.line,
+            name: &self.name,
+            cyclomatic_complexity: self.cyclomatic_complexity,
+            cognitive_complexity: self.cognitive_complexity,
+            loc: self.loc,
+            fan_in: self.fan_in,
+        }
+    }
+}
+
+/// Result of the `stats` command
+#[derive(Debug, Clone, Serialize, Deserialize)]
+pub struct ProjectStats {
+    /// Hand-written files aggregated below
+    pub total_files: u32,
+    /// Files marked generated, left out of the aggregates
+    #[serde(default)]
+    pub generated_files: u32,
+    pub files_by_language: BTreeMap<String, u32>,
+    pub total_functions: u32,
+    pub total_classes: u32,
+    /// Source lines of code (lines with code, comments and blanks excluded)
+    pub total_sloc: u32,
+    /// Mean cyclomatic complexity per function
+    pub average_complexity: f64,
+    pub max_complexity: u32,
+    pub most_complex_functions: Vec<ComplexFunction>,
+}
+
+impl ProjectStats {
+    /// Aggregate every hand-written file of an analysis
+    pub fn build(analysis: &DirectoryAnalysis) -> Self {
+        Self::build_ranked(analysis, &Ranking::default())
+    }
+    
+    /// Same, with the function list ordered by `--sort-by` and cut at `--top`
+    ///
+    /// Defaults are complexity and [`TOP_COMPLEX_FUNCTIONS`]; totals always cover every function.
+    pub fn build_ranked(analysis: &DirectoryAnalysis, ranking: &Ranking) -> Self {
+        let mut files_by_language: BTreeMap<String, u32> = BTreeMap::new();
+        let mut functions = Vec::new();
+        let mut total_classes = 0;
+        let mut total_sloc = 0;
+        
+        let (generated, files): (Vec<_>, Vec<_>) = analysis.files.iter().partition(|f| f.file_info.generated);
+        for file in &files {
+            *files_by_language.entry(file.language.as_str().to_string()).or_default() += 1;
+            total_classes += file.classes.len() as u32;
+            total_sloc += file.file_info.code_lines;
+            
+            let path = file.file_info.path.strip_prefix(&analysis.directory_path)
+                .unwrap_or(&file.file_info.path)
+                .to_string_lossy()
+                .to_string();
+            
+            // Some analyzers list methods both at file level and under their class
+            let mut seen = HashSet::new();
+            let methods = file.classes.iter()
+                .flat_map(|class| class.methods.iter().map(move |m| (m, Some(class.name.as_str()))));
+            for (func, class_name) in methods.chain(file.functions.iter().map(|f| (f, None))) {
+                if !seen.insert((func.start_line, func.name.clone())) {
+                    continue;
+                }
+                functions.push(ComplexFunction {
+                    file: path.clone(),
+                    line: func.start_line,
+                    name: func.name.clone(),
+                    class_name: class_name.map(str::to_string)
+                        .or_else(|| func.metadata.get("class_name").cloned())
+                        .or_else(|| func.metadata.get("receiver_type").cloned()),
+                    cyclomatic_complexity: func.complexity.cyclomatic_complexity,
+                    cognitive_complexity: func.complexity.cognitive_complexity,
+                    max_nesting_depth: func.complexity.max_nesting_depth,
+                    loc: func.loc.max(func.end_line.saturating_sub(func.start_line) + 1),
+                    fan_in: func.fan_in,
+                });
+            }
+        }
+        
+        let total_complexity: u64 = functions.iter().map(|f| f.cyclomatic_complexity as u64).sum();
+        let average_complexity = if functions.is_empty() {
+            0.0
+        } else {
+            total_complexity as f64 / functions.len() as f64
+        };
+        let max_complexity = functions.iter().map(|f| f.cyclomatic_complexity).max().unwrap_or(0);
+        let total_functions = functions.len() as u32;
+        
+        rank(
+            &mut functions,
+            Some(ranking.sort_by.unwrap_or(SortKey::Complexity)),
+            Some(ranking.top.unwrap_or(TOP_COMPLEX_FUNCTIONS)),
+        );
+        
+        Self {
+            total_files: files.len() as u32,
+            generated_files: generated.len() as u32,
+            files_by_language,
+            total_functions,
+            total_classes,
+            total_sloc,
+            average_complexity,
+            max_complexity,
+            most_complex_functions: functions,
+        }
+    }
+    
+    /// Human-readable tables
+    pub fn to_text(&self) -> String {
+        let mut lines = vec![
+            format!(
+                "files: {}, functions: {}, classes: {}, sloc: {}",
+                self.total_files, self.total_functions, self.total_classes, self.total_sloc
+            ),
+            format!("complexity: average {:.2}, max {}", self.average_complexity, self.max_complexity),
+        ];
+        if self.generated_files > 0 {
+            lines.push(format!("generated files excluded: {}", self.generated_files));
+        }
+        lines.push(String::new());
+        lines.push(format!("{:<12} {:>8}", "language", "files"));
+        for (language, count) in &self.files_by_language {
+            lines.push(format!("{:<12} {:>8}", language, count));
+        }
+        
+        if !self.most_complex_functions.is_empty() {
+            lines.push(String::new());
+            lines.push(format!("{:>10} {:>10} {:>8}  {}", "cyclomatic", "cognitive", "nesting", "location"));
+            for func in &self.most_complex_functions {
+                lines.push(format!(
+                    "{:>10} {:>10} {:>8}  {}:{} {}",
+                    func.cyclomatic_complexity, func.cognitive_complexity, func.max_nesting_depth,
+                    func.file, func.line, func.qualified_name()
+                ));
+            }
+        }
+        
+        lines.join("\n")
+    }
+    
+    /// One line for prompts and hooks:
+    /// `42 files, 318 funcs, avg cx 3.1, max cx 27 (ProcessData@proc.go:120), 4 over threshold`
+    ///
+    /// Expects the default complexity order, so the first listed function is the maximum.
+    pub fn to_summary_line(&self, over_threshold: u32) -> String {
+        let max = match self.most_complex_functions.first() {
+            Some(func) if self.max_complexity > 0 => format!(
+                "max cx {} ({}@{}:{})",
+                self.max_complexity, func.qualified_name(), func.file, func.line
+            ),
+            _ => format!("max cx {}", self.max_complexity),
+        };
+        format!(
+            "{} files, {} funcs, avg cx {:.1}, {}, {} over threshold",
+            self.total_files, self.total_functions, self.average_complexity, max, over_threshold
+        )
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{AnalysisResult, ClassInfo, FileInfo, FunctionInfo, Language};
+    use std::path::PathBuf;
+    
+    fn function(name: &str, line: u32, complexity: u32) -> FunctionInfo {
+        let mut func = FunctionInfo::new(name.to_string());
+        func.start_line = line;
+        func.complexity.cyclomatic_complexity = complexity;
+        func
+    }
+    
+    fn file(path: &str, language: Language, code_lines: u32, functions: Vec<FunctionInfo>) -> AnalysisResult {
+        let mut info = FileInfo::new(PathBuf::from("/repo").join(path));
+        info.code_lines = code_lines;
+        let mut result = AnalysisResult::new(info, language);
+        result.functions = functions;
+        result
+    }
+    
+    #[test]
+    fn test_build_aggregates() {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        analysis.files.push(file("a.go", Language::Go, 100, vec![function("parse", 3, 7), function("small", 20, 1)]));
+        analysis.files.push(file("b.go", Language::Go, 50, vec![function("run", 1, 4)]));
+        
+        let mut rust = file("src/lib.rs", Language::Rust, 30, vec![function("method", 10, 12)]);
+        let mut class = ClassInfo::new("Parser".to_string());
+        class.methods.push(function("method", 10, 12));
+        rust.classes.push(class);
+        analysis.files.push(rust);
+        
+        let stats = ProjectStats::build(&analysis);
+        assert_eq!(stats.total_files, 3);
+        assert_eq!(stats.files_by_language.get("go"), Some(&2));
+        assert_eq!(stats.files_by_language.get("rust"), Some(&1));
+        // The method listed under its class and at file level counts once
+        assert_eq!(stats.total_functions, 4);
+        assert_eq!(stats.total_classes, 1);
+        assert_eq!(stats.total_sloc, 180);
+        assert_eq!(stats.max_complexity, 12);
+        assert!((stats.average_complexity - 6.0).abs() < 1e-9);
+        
+        let top = &stats.most_complex_functions[0];
+        assert_eq!((top.file.as_str(), top.line, top.qualified_name().as_str()), ("src/lib.rs", 10, "Parser.method"));
+        assert_eq!(stats.most_complex_functions[1].name, "parse");
+    }
+    
+    #[test]
+    fn test_top_list_is_capped() {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        let functions = (0..15).map(|i| function(&format!("f{}", i), i + 1, i)).collect();
+        analysis.files.push(file("a.py", Language::Python, 10, functions));
+        
+        let stats = ProjectStats::build(&analysis);
+        assert_eq!(stats.most_complex_functions.len(), TOP_COMPLEX_FUNCTIONS);
+        assert_eq!(stats.most_complex_functions[0].cyclomatic_complexity, 14);
+        assert!(stats.to_text().contains("a.py:15 f14"));
+        assert!(stats.to_text().contains("cyclomatic  cognitive  nesting  location"));
+    }
+    
+    #[test]
+    fn test_summary_line() {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        analysis.files.push(file("proc.go", Language::Go, 100, vec![function("ProcessData", 120, 27), function("small", 20, 2)]));
+        
+        let stats = ProjectStats::build(&analysis);
+        assert_eq!(
+            stats.to_summary_line(4),
+            "1 files, 2 funcs, avg cx 14.5, max cx 27 (ProcessData@proc.go:120), 4 over threshold"
+        );
+        
+        let empty = ProjectStats::build(&DirectoryAnalysis::new(PathBuf::from("/repo")));
+        assert_eq!(empty.to_summary_line(0), "0 files, 0 funcs, avg cx 0.0, max cx 0, 0 over threshold");
+    }
+    
+    #[test]
+    fn test_ranked_list() {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        let mut called = function("called", 30, 2);
+        called.fan_in = 4;
+        analysis.files.push(file("b.go", Language::Go, 10, vec![function("zeta", 1, 9), called]));
+        analysis.files.push(file("a.go", Language::Go, 10, vec![function("beta", 5, 9)]));
+        
+        // Equal complexity: a.go before b.go
+        let stats = ProjectStats::build_ranked(&analysis, &Ranking { sort_by: None, top: Some(2) });
+        let names: Vec<&str> = stats.most_complex_functions.iter().map(|f| f.name.as_str()).collect();
+        assert_eq!(names, vec!["beta", "zeta"]);
+        assert_eq!(stats.total_functions, 3);
+        
+        let stats = ProjectStats::build_ranked(&analysis, &Ranking { sort_by: Some(SortKey::FanIn), top: Some(1) });
+        assert_eq!(stats.most_complex_functions[0].name, "called");
+        assert_eq!(stats.most_complex_functions[0].fan_in, 4);
+    }
+    
+    /// Generated files are counted apart and do not move the averages
+    #[test]
+    fn test_generated_files_are_excluded() {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::from("/repo"));
+        analysis.files.push(file("a.go", Language::Go, 100, vec![function("parse", 3, 4)]));
+        let mut generated = file("a.pb.go", Language::Go, 5000, vec![function("Unmarshal", 10, 90)]);
+        generated.file_info.generated = true;
+        analysis.files.push(generated);
+        
+        let stats = ProjectStats::build(&analysis);
+        assert_eq!((stats.total_files, stats.generated_files), (1, 1));
+        assert_eq!(stats.files_by_language.get("go"), Some(&1));
+        assert_eq!((stats.total_functions, stats.total_sloc, stats.max_complexity), (1, 100, 4));
+        assert!(stats.to_text().contains("generated files excluded: 1"));
+    }
+}
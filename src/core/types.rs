@@ -3,15 +3,25 @@
 //! This module contains all the fundamental data structures used throughout
 //! the analysis system, ported from the C++ types.hpp file.
 
+use schemars::JsonSchema;
 use serde::{Deserialize, Serialize};
-use std::collections::HashMap;
+use std::collections::{BTreeMap, HashMap};
 use std::path::PathBuf;
 use chrono::{DateTime, Utc};
 
 use crate::core::ast::{ASTNode, ASTStatistics};
+use crate::core::diagnostics::ParseDiagnostics;
+use crate::core::progress::ProgressMode;
+use crate::core::query::SymbolQuery;
+use crate::core::smells::SmellConfig;
+use crate::core::validate::SyntaxError;
+use crate::core::visibility::Visibility;
+use crate::metrics::cyclomatic::ComplexityWeights;
+use crate::metrics::markers::DEFAULT_MARKERS;
+use crate::metrics::plugin::MetricValue;
 
 /// Supported programming languages
-#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
 pub enum Language {
     #[serde(rename = "javascript")]
     JavaScript,
@@ -29,6 +39,24 @@ pub enum Language {
     Go,
     #[serde(rename = "rust")]
     Rust,
+    #[serde(rename = "ruby")]
+    Ruby,
+    #[serde(rename = "java")]
+    Java,
+    #[serde(rename = "kotlin")]
+    Kotlin,
+    #[serde(rename = "swift")]
+    Swift,
+    #[serde(rename = "php")]
+    Php,
+    #[serde(rename = "lua")]
+    Lua,
+    #[serde(rename = "dart")]
+    Dart,
+    #[serde(rename = "scala")]
+    Scala,
+    #[serde(rename = "zig")]
+    Zig,
     #[serde(rename = "unknown")]
     Unknown,
 }
@@ -40,17 +68,105 @@ impl Language {
             ".ts" | ".tsx" => Language::TypeScript,
             ".cpp" | ".cxx" | ".cc" | ".hpp" | ".hxx" | ".hh" => Language::Cpp,
             ".c" | ".h" => Language::C,
-            ".py" | ".pyw" | ".pyi" => Language::Python,
+            // Notebooks: the Python of their code cells (core::notebook)
+            ".py" | ".pyw" | ".pyi" | ".ipynb" => Language::Python,
             ".cs" => Language::CSharp,
             ".go" => Language::Go,
             ".rs" => Language::Rust,
+            ".rb" => Language::Ruby,
+            ".java" => Language::Java,
+            ".kt" | ".kts" => Language::Kotlin,
+            ".swift" => Language::Swift,
+            ".php" | ".phtml" => Language::Php,
+            ".lua" => Language::Lua,
+            ".dart" => Language::Dart,
+            ".scala" | ".sc" => Language::Scala,
+            ".zig" => Language::Zig,
             _ => Language::Unknown,
         }
     }
+    
+    /// Detect language from a shebang line (e.g. `#!/usr/bin/env ruby`)
+    pub fn from_shebang(first_line: &str) -> Self {
+        let line = first_line.trim();
+        if !line.starts_with("#!") {
+            return Language::Unknown;
+        }
+        
+        let mut parts = line[2..].split_whitespace();
+        let mut interpreter = parts.next().unwrap_or("");
+        if interpreter.ends_with("/env") {
+            // #!/usr/bin/env [-S] ruby
+            interpreter = parts.find(|p| !p.starts_with('-')).unwrap_or("");
+        }
+        
+        let name = interpreter.rsplit('/').next().unwrap_or("");
+        if name.starts_with("ruby") {
+            Language::Ruby
+        } else if name.starts_with("php") {
+            Language::Php
+        } else if name.starts_with("lua") || name == "luajit" {
+            Language::Lua
+        } else if name == "dart" {
+            Language::Dart
+        } else if name == "scala" {
+            Language::Scala
+        } else {
+            Language::Unknown
+        }
+    }
+    
+    /// Parse a user-supplied language name (`--lang go`); common aliases are accepted
+    pub fn from_name(name: &str) -> Option<Self> {
+        match name.trim().to_lowercase().as_str() {
+            "javascript" | "js" => Some(Language::JavaScript),
+            "typescript" | "ts" => Some(Language::TypeScript),
+            "cpp" | "c++" | "cxx" => Some(Language::Cpp),
+            "c" => Some(Language::C),
+            "python" | "py" => Some(Language::Python),
+            "csharp" | "c#" | "cs" => Some(Language::CSharp),
+            "go" | "golang" => Some(Language::Go),
+            "rust" | "rs" => Some(Language::Rust),
+            "ruby" | "rb" => Some(Language::Ruby),
+            "java" => Some(Language::Java),
+            "kotlin" | "kt" => Some(Language::Kotlin),
+            "swift" => Some(Language::Swift),
+            "php" => Some(Language::Php),
+            "lua" => Some(Language::Lua),
+            "dart" => Some(Language::Dart),
+            "scala" | "sc" => Some(Language::Scala),
+            "zig" => Some(Language::Zig),
+            _ => None,
+        }
+    }
+    
+    /// Serialized name (`csharp`, `go`, ...), as in JSON output
+    pub fn as_str(&self) -> &'static str {
+        match self {
+            Language::JavaScript => "javascript",
+            Language::TypeScript => "typescript",
+            Language::Cpp => "cpp",
+            Language::C => "c",
+            Language::Python => "python",
+            Language::CSharp => "csharp",
+            Language::Go => "go",
+            Language::Rust => "rust",
+            Language::Ruby => "ruby",
+            Language::Java => "java",
+            Language::Kotlin => "kotlin",
+            Language::Swift => "swift",
+            Language::Php => "php",
+            Language::Lua => "lua",
+            Language::Dart => "dart",
+            Language::Scala => "scala",
+            Language::Zig => "zig",
+            Language::Unknown => "unknown",
+        }
+    }
 }
 
 /// File information structure
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct FileInfo {
     pub name: String,
     pub path: PathBuf,
@@ -60,6 +176,15 @@ pub struct FileInfo {
     pub comment_lines: u32,
     pub empty_lines: u32,
     pub code_ratio: f64,
+    /// Comment lines / (comment + code lines)
+    #[serde(default)]
+    pub comment_density: f64,
+    /// Header comments mark the file as generated (`// Code generated ... DO NOT EDIT.`)
+    #[serde(default)]
+    pub generated: bool,
+    /// Set when the file was not valid UTF-8 and was decoded lossily
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub encoding_warning: Option<String>,
     pub analyzed_at: DateTime<Utc>,
     pub metadata: HashMap<String, String>,
 }
@@ -78,6 +203,9 @@ impl FileInfo {
             comment_lines: 0,
             empty_lines: 0,
             code_ratio: 0.0,
+            comment_density: 0.0,
+            generated: false,
+            encoding_warning: None,
             analyzed_at: Utc::now(),
             metadata: HashMap::new(),
         }
@@ -85,7 +213,7 @@ impl FileInfo {
 }
 
 /// Complexity rating levels
-#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
 pub enum ComplexityRating {
     #[serde(rename = "simple")]
     Simple,      // <= 10
@@ -98,13 +226,16 @@ pub enum ComplexityRating {
 }
 
 /// Complexity analysis information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct ComplexityInfo {
     pub cyclomatic_complexity: u32,
     pub max_nesting_depth: u32,
     pub cognitive_complexity: u32,
     pub rating: ComplexityRating,
     pub rating_emoji: String,
+    /// Decision points behind `cyclomatic_complexity`, by construct ("if", "case", "and", ...)
+    #[serde(default, skip_serializing_if = "BTreeMap::is_empty")]
+    pub decision_points: BTreeMap<String, u32>,
 }
 
 impl ComplexityInfo {
@@ -115,6 +246,7 @@ impl ComplexityInfo {
             cognitive_complexity: 0,
             rating: ComplexityRating::Simple,
             rating_emoji: "🟢".to_string(),
+            decision_points: BTreeMap::new(),
         };
         info.update_rating();
         info
@@ -153,17 +285,208 @@ impl Default for ComplexityInfo {
     }
 }
 
+/// JSON shape of a function's complexity: the cyclomatic number flat, the rest nested
+mod function_complexity {
+    use super::ComplexityInfo;
+    use schemars::JsonSchema;
+    use serde::{Deserialize, Deserializer, Serialize, Serializer};
+
+    #[derive(Serialize)]
+    struct FunctionComplexityRef<'a> {
+        complexity: u32,
+        complexity_detail: &'a ComplexityInfo,
+    }
+
+    #[derive(Deserialize, JsonSchema)]
+    pub struct FunctionComplexity {
+        /// Cyclomatic complexity (same as `complexity_detail.cyclomatic_complexity`)
+        #[allow(dead_code)]
+        pub complexity: u32,
+        pub complexity_detail: ComplexityInfo,
+    }
+
+    pub fn serialize<S: Serializer>(info: &ComplexityInfo, serializer: S) -> Result<S::Ok, S::Error> {
+        FunctionComplexityRef {
+            complexity: info.cyclomatic_complexity,
+            complexity_detail: info,
+        }
+        .serialize(serializer)
+    }
+
+    pub fn deserialize<'de, D: Deserializer<'de>>(deserializer: D) -> Result<ComplexityInfo, D::Error> {
+        FunctionComplexity::deserialize(deserializer).map(|flat| flat.complexity_detail)
+    }
+}
+
+/// Source span of a symbol, taken from its syntax node
+///
+/// Columns are 0-based. `start_col` / `end_col` count UTF-8 bytes from the
+/// start of the line (Tree-sitter columns); the `_utf16` variants count
+/// UTF-16 code units, the default position encoding of LSP clients.
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct Span {
+    #[serde(default)]
+    pub start_byte: u32,
+    #[serde(default)]
+    pub end_byte: u32,
+    #[serde(default)]
+    pub start_col: u32,
+    #[serde(default)]
+    pub end_col: u32,
+    #[serde(default)]
+    pub start_col_utf16: u32,
+    #[serde(default)]
+    pub end_col_utf16: u32,
+}
+
+/// Language-independent kind of a symbol; `native_kind` keeps the language's own term
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash, Default, Serialize, Deserialize, JsonSchema)]
+#[serde(rename_all = "snake_case")]
+pub enum SymbolKind {
+    #[default]
+    Function,
+    Method,
+    Class,
+    Struct,
+    Interface,
+    Enum,
+    Trait,
+    Module,
+    Constant,
+    Field,
+}
+
+impl SymbolKind {
+    /// The serialized name (`function`, `struct`, ...)
+    pub fn as_str(self) -> &'static str {
+        match self {
+            Self::Function => "function",
+            Self::Method => "method",
+            Self::Class => "class",
+            Self::Struct => "struct",
+            Self::Interface => "interface",
+            Self::Enum => "enum",
+            Self::Trait => "trait",
+            Self::Module => "module",
+            Self::Constant => "constant",
+            Self::Field => "field",
+        }
+    }
+}
+
 /// Function information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct FunctionInfo {
     pub name: String,
     pub start_line: u32,
     pub end_line: u32,
+    /// Byte range and columns of the declaration
+    #[serde(flatten)]
+    pub span: Span,
     pub parameters: Vec<String>,
     pub is_async: bool,
     pub is_arrow_function: bool,
+    /// Serialized as `"complexity": N` (cyclomatic) plus the full breakdown under `complexity_detail`
+    #[serde(flatten, with = "function_complexity")]
+    #[schemars(with = "function_complexity::FunctionComplexity")]
     pub complexity: ComplexityInfo,
     pub metadata: HashMap<String, String>,
+    /// Goroutines launched in this function (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub goroutines: Vec<GoroutineInfo>,
+    /// Channel operations performed in this function (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub channels: Vec<ChannelOperation>,
+    /// Declared but never read locals and parameters (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub unused_locals: Vec<UnusedLocal>,
+    /// Deferred calls, in source order (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub defers: Vec<DeferInfo>,
+    /// Types pulled out of interface values: `v.(T)` and type switch `case T:` arms (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub type_assertions: Vec<TypeAssertion>,
+    /// Calls the `panic` builtin (Go)
+    #[serde(default)]
+    pub has_panic: bool,
+    /// Calls the `recover` builtin (Go)
+    #[serde(default)]
+    pub has_recover: bool,
+    /// Heuristic: panics on a goroutine path (the function itself or a `go func` literal) with no `recover` (Go)
+    #[serde(default)]
+    pub unrecovered_panic: bool,
+    /// Generic type parameters (Go `func Map[T any, U comparable]`, C++ `template <typename T>`)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub type_params: Vec<TypeParameter>,
+    /// Declared parameters with their types (Go, Swift)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub params: Vec<ParameterInfo>,
+    /// Result types, in order (Go, Swift)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub returns: Vec<String>,
+    /// Names of named results, parallel to `returns` (Go `(n int, err error)`)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub return_names: Vec<String>,
+    /// Physical lines of the function span
+    #[serde(default)]
+    pub loc: u32,
+    /// Lines containing code (non-blank, non-comment)
+    #[serde(default)]
+    pub sloc: u32,
+    /// Comment-only lines in the span, plus the doc comment directly above
+    #[serde(default)]
+    pub comment_lines: u32,
+    /// Calls itself directly (mutual recursion is reported by `cycles`)
+    #[serde(default)]
+    pub recursive: bool,
+    /// Distinct functions of the analyzed set that call this one (cross-file calls resolved)
+    #[serde(default)]
+    pub fan_in: u32,
+    /// Distinct functions of the analyzed set this one calls
+    #[serde(default)]
+    pub fan_out: u32,
+    /// Commits that touched the function's lines (`analyze --with-churn`)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub churn: Option<u32>,
+    /// Cyclomatic complexity times `churn`: where refactoring pays off most
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub risk: Option<u32>,
+    /// Hash of the body's token stream, ignoring whitespace and comments (change and rename detection)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub body_hash: String,
+    /// Hash of the body's exact bytes (any edit, formatting included)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub raw_hash: String,
+    /// Normalized body tokens for clone detection (in-memory only)
+    #[serde(skip)]
+    pub clone_tokens: Vec<u32>,
+    /// Values of registered metric plugins, by name
+    #[serde(default, skip_serializing_if = "BTreeMap::is_empty")]
+    pub custom: BTreeMap<String, MetricValue>,
+    /// Enclosing package / namespace / module (Go `main`, Python `app.models`, C# `App.Services`)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub namespace: Option<String>,
+    /// Defined inside another function's body (closure, lambda, local function)
+    #[serde(default)]
+    pub nested: bool,
+    /// Functions defined directly in this one's body, in source order (Go, JavaScript, Python)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub children: Vec<FunctionInfo>,
+    /// Code smells: magic numbers in the body, a long parameter list
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub smells: Vec<SmellInfo>,
+    /// Decorators / annotations / attributes, in source order (Python, Java, C#, Rust)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub annotations: Vec<Annotation>,
+    /// `function` or `method`
+    #[serde(default)]
+    pub kind: SymbolKind,
+    /// The language's term (`func`, `def`, `fn`, `constructor`)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub native_kind: String,
+    /// Hash of namespace, qualified name and normalized signature, `#2`.. for repeats (diff / baseline key)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub stable_id: String,
 }
 
 impl FunctionInfo {
@@ -172,27 +495,202 @@ impl FunctionInfo {
             name,
             start_line: 0,
             end_line: 0,
+            span: Span::default(),
             parameters: Vec::new(),
             is_async: false,
             is_arrow_function: false,
             complexity: ComplexityInfo::new(),
             metadata: HashMap::new(),
+            goroutines: Vec::new(),
+            channels: Vec::new(),
+            unused_locals: Vec::new(),
+            defers: Vec::new(),
+            type_assertions: Vec::new(),
+            has_panic: false,
+            has_recover: false,
+            unrecovered_panic: false,
+            type_params: Vec::new(),
+            params: Vec::new(),
+            returns: Vec::new(),
+            return_names: Vec::new(),
+            loc: 0,
+            sloc: 0,
+            comment_lines: 0,
+            recursive: false,
+            fan_in: 0,
+            fan_out: 0,
+            churn: None,
+            risk: None,
+            body_hash: String::new(),
+            raw_hash: String::new(),
+            clone_tokens: Vec::new(),
+            custom: BTreeMap::new(),
+            namespace: None,
+            nested: false,
+            children: Vec::new(),
+            smells: Vec::new(),
+            annotations: Vec::new(),
+            kind: SymbolKind::Function,
+            native_kind: String::new(),
+            stable_id: String::new(),
         }
     }
 }
 
+/// Declared function parameter (`id int`, `opts ...Option`)
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct ParameterInfo {
+    /// Empty for unnamed parameters (`func(int, string)`)
+    pub name: String,
+    /// Type as written, prefixed with `...` for variadic parameters
+    #[serde(rename = "type")]
+    pub param_type: String,
+    #[serde(default)]
+    pub variadic: bool,
+}
+
+/// Decorator, annotation, attribute or struct tag on a symbol
+///
+/// Python `@route("/users")`, Java `@Override`, C# `[HttpGet("{id}")]`,
+/// Rust `#[derive(Debug, Clone)]`, Go field tag `json:"name,omitempty"`
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct Annotation {
+    /// Name as written, without `@` / `[` / `#[` (`app.route`, `derive`, `json`)
+    pub name: String,
+    /// Raw argument text inside the parentheses; the tag value for Go (`name,omitempty`)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub arguments: String,
+    pub line_number: u32,
+}
+
+impl Annotation {
+    /// Annotation with `arguments` stripped of one pair of enclosing parentheses
+    pub fn new(name: &str, arguments: &str, line_number: u32) -> Self {
+        let arguments = arguments.trim();
+        let arguments = arguments.strip_prefix('(')
+            .and_then(|inner| inner.strip_suffix(')'))
+            .unwrap_or(arguments);
+        Self { name: name.trim().to_string(), arguments: arguments.trim().to_string(), line_number }
+    }
+}
+
+/// Generic type parameter and its constraint (`T comparable`)
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct TypeParameter {
+    pub name: String,
+    pub constraint: String,
+}
+
+/// Goroutine launch site (`go f()` / `go func() { ... }()`)
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
+pub struct GoroutineInfo {
+    pub line_number: u32,
+    /// Called expression, e.g. `worker` or `dp.run`; `func literal` for anonymous goroutines
+    pub expression: String,
+    pub is_anonymous: bool,
+}
+
+/// Deferred call (`defer mu.Unlock()` / `defer func() { ... }()`)
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
+pub struct DeferInfo {
+    pub line_number: u32,
+    /// Called expression, e.g. `dp.mu.Unlock`; `func literal` for deferred closures
+    pub expression: String,
+    pub is_anonymous: bool,
+}
+
+/// Where a type assertion names its type
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+#[serde(rename_all = "snake_case")]
+pub enum TypeAssertionKind {
+    /// `v.(T)` / `v, ok := x.(T)`
+    Assertion,
+    /// `case T:` arm of a type switch
+    TypeSwitch,
+}
+
+/// Named type an interface value is asserted to (Go `w.(*bufio.Writer)`)
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct TypeAssertion {
+    /// Type name without package, pointer or type arguments (`Writer`)
+    pub type_name: String,
+    /// Package qualifier as written (`bufio`)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub package: Option<String>,
+    /// Asserted to a pointer (`*T`)
+    #[serde(default)]
+    pub pointer: bool,
+    pub kind: TypeAssertionKind,
+    pub line_number: u32,
+}
+
+impl TypeAssertion {
+    /// The type as written, without type arguments (`*bufio.Writer`)
+    pub fn type_text(&self) -> String {
+        let pointer = if self.pointer { "*" } else { "" };
+        match &self.package {
+            Some(package) => format!("{}{}.{}", pointer, package, self.type_name),
+            None => format!("{}{}", pointer, self.type_name),
+        }
+    }
+}
+
+/// Local variable or parameter that is declared but never read
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct UnusedLocal {
+    pub name: String,
+    /// Line of the declaration
+    pub line: u32,
+}
+
+/// Channel operation kinds
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub enum ChannelOperationType {
+    #[serde(rename = "send")]
+    Send,       // ch <- v
+    #[serde(rename = "receive")]
+    Receive,    // <-ch
+    #[serde(rename = "close")]
+    Close,      // close(ch)
+    #[serde(rename = "make")]
+    Make,       // make(chan T)
+}
+
+/// Channel operation information
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
+pub struct ChannelOperation {
+    #[serde(rename = "type")]
+    pub operation: ChannelOperationType,
+    /// Channel expression (channel type for `make`)
+    pub channel: String,
+    pub line_number: u32,
+    /// Operation is a `case` of a `select` statement
+    pub in_select: bool,
+}
+
 /// Member variable information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct MemberVariable {
     pub name: String,
     pub var_type: String,
     pub declaration_line: u32,
+    /// Byte range and columns of the declaration
+    #[serde(flatten)]
+    pub span: Span,
     pub is_static: bool,
     pub is_const: bool,
     pub access_modifier: String,
     pub used_by_methods: Vec<String>,
     pub modified_by_methods: Vec<String>,
     pub metadata: HashMap<String, String>,
+    /// Annotations / attributes on the field (Java, C#); struct tags (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub annotations: Vec<Annotation>,
+    /// `field` or `constant`
+    #[serde(default = "default_field_kind")]
+    pub kind: SymbolKind,
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub native_kind: String,
 }
 
 impl MemberVariable {
@@ -201,27 +699,61 @@ impl MemberVariable {
             name,
             var_type,
             declaration_line,
+            span: Span::default(),
             is_static: false,
             is_const: false,
             access_modifier: "private".to_string(),
             used_by_methods: Vec::new(),
             modified_by_methods: Vec::new(),
             metadata: HashMap::new(),
+            annotations: Vec::new(),
+            kind: SymbolKind::Field,
+            native_kind: String::new(),
         }
     }
 }
 
 /// Class information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct ClassInfo {
     pub name: String,
     pub parent_class: Option<String>,
     pub start_line: u32,
     pub end_line: u32,
+    /// Byte range and columns of the declaration
+    #[serde(flatten)]
+    pub span: Span,
     pub methods: Vec<FunctionInfo>,
     pub properties: Vec<String>,
     pub member_variables: Vec<MemberVariable>,
     pub metadata: HashMap<String, String>,
+    /// Generic type parameters (Go `type Set[T comparable] ...`, C++ class templates)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub type_params: Vec<TypeParameter>,
+    /// Interfaces this type implements: method-set matches (Go), declared conformances (Swift, PHP)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub implements: Vec<String>,
+    /// Concrete types implementing this interface (Go, Swift)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub implementors: Vec<String>,
+    /// Embedded types (Go, `*T` for pointer embedding) and traits used by a PHP class
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub embeds: Vec<String>,
+    /// Enclosing package / namespace / module (Go `main`, Java `com.acme.app`, Rust `crate::core`)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub namespace: Option<String>,
+    /// Decorators / annotations / attributes on the type (Python, Java, C#, Rust)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub annotations: Vec<Annotation>,
+    /// `class`, `struct`, `interface`, `enum`, `trait` or `module`
+    #[serde(default = "default_class_kind")]
+    pub kind: SymbolKind,
+    /// The language's term (`protocol`, `object`, `impl`)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub native_kind: String,
+    /// Hash of namespace and name, `#2`.. for repeats (diff / baseline key)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub stable_id: String,
 }
 
 impl ClassInfo {
@@ -231,16 +763,36 @@ impl ClassInfo {
             parent_class: None,
             start_line: 0,
             end_line: 0,
+            span: Span::default(),
             methods: Vec::new(),
             properties: Vec::new(),
             member_variables: Vec::new(),
             metadata: HashMap::new(),
+            type_params: Vec::new(),
+            implements: Vec::new(),
+            implementors: Vec::new(),
+            embeds: Vec::new(),
+            namespace: None,
+            annotations: Vec::new(),
+            kind: SymbolKind::Class,
+            native_kind: String::new(),
+            stable_id: String::new(),
         }
     }
 }
 
+/// Helper: serde default of `ClassInfo::kind`
+fn default_class_kind() -> SymbolKind {
+    SymbolKind::Class
+}
+
+/// Helper: serde default of `MemberVariable::kind`
+fn default_field_kind() -> SymbolKind {
+    SymbolKind::Field
+}
+
 /// Import types
-#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
 pub enum ImportType {
     #[serde(rename = "es6_import")]
     ES6Import,      // import ... from
@@ -260,10 +812,28 @@ pub enum ImportType {
     GoImport,       // import "package"
     #[serde(rename = "rust_use")]
     RustUse,        // use crate::module
+    #[serde(rename = "ruby_require")]
+    RubyRequire,    // require / require_relative
+    #[serde(rename = "java_import")]
+    JavaImport,     // import a.b.C / import static a.b.C.m
+    #[serde(rename = "kotlin_import")]
+    KotlinImport,   // import a.b.C / import a.b.C as D / import a.b.*
+    #[serde(rename = "swift_import")]
+    SwiftImport,    // import Foundation / import struct Module.Type
+    #[serde(rename = "php_use")]
+    PhpUse,         // use App\Models\User / use function App\helper
+    #[serde(rename = "lua_require")]
+    LuaRequire,     // local json = require("json")
+    #[serde(rename = "dart_import")]
+    DartImport,     // import 'package:a/b.dart' as b show C
+    #[serde(rename = "scala_import")]
+    ScalaImport,    // import a.b.C / import a.b._ / import a.b.{C, D => E}
+    #[serde(rename = "zig_import")]
+    ZigImport,      // const std = @import("std")
 }
 
 /// Export types  
-#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
 pub enum ExportType {
     #[serde(rename = "es6_export")]
     ES6Export,      // export ...
@@ -284,7 +854,7 @@ pub enum ExportType {
 }
 
 /// Import information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct ImportInfo {
     #[serde(rename = "type")]
     pub import_type: ImportType,
@@ -309,7 +879,7 @@ impl ImportInfo {
 }
 
 /// Export information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct ExportInfo {
     #[serde(rename = "type")]
     pub export_type: ExportType,
@@ -330,12 +900,116 @@ impl ExportInfo {
 }
 
 /// Function call information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct FunctionCall {
     pub function_name: String,
     pub object_name: Option<String>,
     pub line_number: u32,
     pub is_method_call: bool,
+    /// Inferred type of the receiver variable (`DataProcessor` for `processor.Run()`)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub receiver_type: Option<String>,
+    /// Module the callee was imported from (Python `from app.utils import slugify`)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub module: Option<String>,
+    /// Results thrown away at the call site (Go); `None` when the call's value is used
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub discarded_results: Option<DiscardedResults>,
+}
+
+/// How a Go call site drops results
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub enum DiscardedResults {
+    /// Call statement `f()`: every result is dropped
+    All,
+    /// `v, _ := f()`: blank positions among `arity` assigned values
+    Blank { positions: Vec<u32>, arity: u32 },
+}
+
+/// Call dropping the trailing `error` of a function from the analyzed set (Go)
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct UncheckedError {
+    pub line_number: u32,
+    /// Called symbol: `Load`, `Store.Save` or `config.Load`
+    pub callee: String,
+}
+
+/// Function declared without a body: a C++ prototype in a header or class body
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct DeclarationInfo {
+    /// Stable name, as on the definition (`area`, `~Shape`, `operator==`)
+    pub name: String,
+    /// Qualified name and parameter types (`geo::Shape::area() const`), shared with the definition
+    pub signature: String,
+    pub line_number: u32,
+    /// Template parameters (`template <typename T>`)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub type_params: Vec<TypeParameter>,
+    /// `path:line` of the definition, once linked
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub defined_at: Option<String>,
+}
+
+/// Go `init` function: runs once at package initialization, after every package-level variable
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct InitFunction {
+    pub line_number: u32,
+    pub end_line: u32,
+    /// Position among the file's `init` functions (1-based); they run in source order
+    pub order: u32,
+    /// Functions and methods called in the body (`pkg.Func`, `obj.Method`), first call first
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub calls: Vec<String>,
+}
+
+/// Variable or constant declared outside any function (Go `var` / `const`)
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct VariableInfo {
+    pub name: String,
+    /// Declared type; empty when inferred from the initializer
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub var_type: String,
+    pub line_number: u32,
+    pub is_const: bool,
+    /// Declared at package level: initialized before any `init` function runs
+    pub package_level: bool,
+    /// Initialization expression as written (a `const` without one repeats the previous);
+    /// empty for zero-value variables
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub initializer: String,
+    /// The initializer runs code at import time: a function call or a channel receive
+    pub non_trivial_initializer: bool,
+    /// Names the initializer refers to, in source order, then the file's package-level names
+    /// read by the functions it refers to
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub depends_on: Vec<String>,
+    /// Position in the package's variable initialization order (1-based): dependencies
+    /// first, then declaration order; `None` for constants
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub init_order: Option<u32>,
+}
+
+/// One step of a Go package's initialization, in run order
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct InitStep {
+    /// Variable name, or `init`
+    pub name: String,
+    pub file: PathBuf,
+    pub line_number: u32,
+    /// A non-trivial variable initializer, or an `init` function that calls something
+    pub runs_code: bool,
+}
+
+/// Initialization of one Go package across its analyzed files
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct PackageInit {
+    /// Package clause name
+    pub package: String,
+    pub directory: PathBuf,
+    /// Package-level variables in initialization order
+    pub variables: Vec<InitStep>,
+    /// `init` functions in run order: files by name, then source order
+    pub init_functions: Vec<InitStep>,
 }
 
 impl FunctionCall {
@@ -345,6 +1019,17 @@ impl FunctionCall {
             object_name: None,
             line_number,
             is_method_call: false,
+            receiver_type: None,
+            module: None,
+            discarded_results: None,
+        }
+    }
+    
+    /// `Type.method` when the receiver type is known, otherwise `full_name`
+    pub fn qualified_name(&self) -> String {
+        match &self.receiver_type {
+            Some(receiver_type) => format!("{}.{}", receiver_type, self.function_name),
+            None => self.full_name(),
         }
     }
     
@@ -362,7 +1047,7 @@ impl FunctionCall {
 }
 
 /// Comment information
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct CommentInfo {
     pub line_start: u32,
     pub line_end: u32,
@@ -384,8 +1069,40 @@ impl CommentInfo {
     }
 }
 
+/// Tech-debt marker comment (`// TODO: ...`)
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
+pub struct MarkerInfo {
+    /// Tag as written (`TODO`, `FIXME`, ...)
+    #[serde(rename = "type")]
+    pub kind: String,
+    pub line: u32,
+    /// Rest of the comment line after the tag
+    pub text: String,
+    /// Innermost enclosing function or method (`Class.method`)
+    #[serde(skip_serializing_if = "Option::is_none")]
+    pub function: Option<String>,
+}
+
+/// Code smell of one function
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+pub struct SmellInfo {
+    /// `magic_number` or `long_parameter_list`
+    #[serde(rename = "type")]
+    pub kind: String,
+    pub line: u32,
+    /// The literal as written, or the parameter count
+    pub value: String,
+    pub message: String,
+}
+
+impl SmellInfo {
+    pub const MAGIC_NUMBER: &'static str = "magic_number";
+    pub const LONG_PARAMETER_LIST: &'static str = "long_parameter_list";
+    pub const LONG_FUNCTION: &'static str = "long_function";
+}
+
 /// Analysis statistics
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct Statistics {
     pub class_count: u32,
     pub function_count: u32,
@@ -411,7 +1128,7 @@ impl Default for Statistics {
 }
 
 /// Complete analysis result
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct AnalysisResult {
     // Basic information
     pub file_info: FileInfo,
@@ -434,6 +1151,9 @@ pub struct AnalysisResult {
     
     // Comments
     pub commented_lines: Vec<CommentInfo>,
+    /// Tech-debt markers (TODO, FIXME, ...) found in comments
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub markers: Vec<MarkerInfo>,
     
     // Extension metadata
     pub metadata: HashMap<String, String>,
@@ -445,6 +1165,43 @@ pub struct AnalysisResult {
     pub ast_root: Option<ASTNode>,
     pub ast_statistics: Option<ASTStatistics>,
     
+    /// Non-fatal analyzer errors; symbols of the failed step are left empty
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub errors: Vec<String>,
+    
+    /// Hash of the analyzed file content (set by the analysis session)
+    #[serde(default, skip_serializing_if = "String::is_empty")]
+    pub content_hash: String,
+    
+    /// Calls ignoring a returned `error` (Go, set by the cross-file pass)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub unchecked_errors: Vec<UncheckedError>,
+    
+    /// Functions declared without a body (C++ prototypes), linked to their definitions
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub declarations: Vec<DeclarationInfo>,
+    
+    /// `init` functions, in source order (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub init_functions: Vec<InitFunction>,
+    
+    /// Package-level variables and constants, in source order (Go)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub variables: Vec<VariableInfo>,
+    
+    /// Tree-sitter parse cost and tree shape (`--diagnostics` only)
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub diagnostics: Option<ParseDiagnostics>,
+    
+    /// The syntax tree has ERROR / MISSING nodes: symbols come from the parts the
+    /// parser recovered, and those overlapping `parse_errors` may be incomplete
+    #[serde(default)]
+    pub partial: bool,
+    
+    /// Ranges of the outermost ERROR / MISSING nodes, in source order
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub parse_errors: Vec<SyntaxError>,
+    
     // Generation timestamp
     pub generated_at: DateTime<Utc>,
 }
@@ -462,10 +1219,20 @@ impl AnalysisResult {
             call_frequency: HashMap::new(),
             complexity: ComplexityInfo::new(),
             commented_lines: Vec::new(),
+            markers: Vec::new(),
             metadata: HashMap::new(),
             stats: Statistics::default(),
             ast_root: None,
             ast_statistics: None,
+            errors: Vec::new(),
+            content_hash: String::new(),
+            unchecked_errors: Vec::new(),
+            declarations: Vec::new(),
+            init_functions: Vec::new(),
+            variables: Vec::new(),
+            diagnostics: None,
+            partial: false,
+            parse_errors: Vec::new(),
             generated_at: Utc::now(),
         }
     }
@@ -482,16 +1249,45 @@ impl AnalysisResult {
 }
 
 /// Directory analysis summary
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct DirectoryAnalysis {
     pub directory_path: PathBuf,
     pub files: Vec<AnalysisResult>,
     pub summary: DirectorySummary,
     pub generated_at: DateTime<Utc>,
+    /// Files that failed to analyze (the rest of the batch is still reported)
+    #[serde(default)]
+    pub errors: Vec<AnalysisError>,
+    /// Files deliberately left out, e.g. over `max_file_size`
+    #[serde(default)]
+    pub warnings: Vec<AnalysisWarning>,
+    /// Commit the files were read at (`analyze --rev`); paths carry it as `@<commit>`
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub revision: Option<String>,
+    /// Paths analyzed together as one project (`analyze dir1 dir2 file.go`)
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub roots: Vec<PathBuf>,
+    /// Go packages with package-level variables or `init` functions, by directory
+    #[serde(default, skip_serializing_if = "Vec::is_empty")]
+    pub package_inits: Vec<PackageInit>,
+}
+
+/// Per-file analysis failure
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
+pub struct AnalysisError {
+    pub file_path: PathBuf,
+    pub message: String,
+}
+
+/// Per-file notice for a file that was skipped rather than failed
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
+pub struct AnalysisWarning {
+    pub file_path: PathBuf,
+    pub message: String,
 }
 
 /// Directory analysis summary statistics
-#[derive(Debug, Clone, Serialize, Deserialize)]
+#[derive(Debug, Clone, Serialize, Deserialize, JsonSchema)]
 pub struct DirectorySummary {
     pub total_files: u32,
     pub total_lines: u32,
@@ -508,6 +1304,18 @@ pub struct DirectorySummary {
     pub average_complexity: f64,
     pub max_complexity: u32,
     pub most_complex_file: String,
+    
+    /// Comment lines / (comment + code lines) over all files
+    #[serde(default)]
+    pub comment_density: f64,
+    
+    /// Functions above the configured `max_cyclomatic` / `max_cognitive`
+    #[serde(default)]
+    pub functions_over_threshold: u32,
+    
+    /// Files analyzed from a syntax tree with errors (`partial` results)
+    #[serde(default)]
+    pub partial_files: u32,
 }
 
 impl Default for DirectorySummary {
@@ -524,6 +1332,9 @@ impl Default for DirectorySummary {
             average_complexity: 0.0,
             max_complexity: 0,
             most_complex_file: String::new(),
+            comment_density: 0.0,
+            functions_over_threshold: 0,
+            partial_files: 0,
         }
     }
 }
@@ -535,42 +1346,93 @@ impl DirectoryAnalysis {
             files: Vec::new(),
             summary: DirectorySummary::default(),
             generated_at: Utc::now(),
+            errors: Vec::new(),
+            warnings: Vec::new(),
+            revision: None,
+            roots: Vec::new(),
+            package_inits: Vec::new(),
         }
     }
     
     pub fn update_summary(&mut self) {
-        let mut summary = DirectorySummary::default();
-        summary.total_files = self.files.len() as u32;
-        
+        let mut builder = SummaryBuilder::default();
         for file in &self.files {
-            summary.total_lines += file.file_info.total_lines;
-            summary.total_size += file.file_info.size_bytes;
-            summary.total_classes += file.stats.class_count;
-            summary.total_functions += file.stats.function_count;
-            
-            if file.file_info.total_lines > 500 {
-                summary.large_files += 1;
-            }
-            
-            if matches!(file.complexity.rating, ComplexityRating::Complex | ComplexityRating::VeryComplex) {
-                summary.complex_files += 1;
-            }
-            
-            summary.total_complexity += file.complexity.cyclomatic_complexity;
-            
-            if file.complexity.cyclomatic_complexity > summary.max_complexity {
-                summary.max_complexity = file.complexity.cyclomatic_complexity;
-                summary.most_complex_file = file.file_info.name.clone();
-            }
+            builder.add(file);
+        }
+        self.summary = builder.finish();
+    }
+}
+
+/// Incremental `DirectorySummary`, for callers that do not keep every file in memory
+#[derive(Debug, Default)]
+pub struct SummaryBuilder {
+    summary: DirectorySummary,
+    comment_lines: u32,
+    code_lines: u32,
+}
+
+impl SummaryBuilder {
+    pub fn add(&mut self, file: &AnalysisResult) {
+        let summary = &mut self.summary;
+        summary.total_files += 1;
+        self.comment_lines += file.file_info.comment_lines;
+        self.code_lines += file.file_info.code_lines;
+        summary.total_lines += file.file_info.total_lines;
+        summary.total_size += file.file_info.size_bytes;
+        summary.total_classes += file.stats.class_count;
+        summary.total_functions += file.stats.function_count;
+        
+        if file.file_info.total_lines > 500 {
+            summary.large_files += 1;
         }
         
+        if file.partial {
+            summary.partial_files += 1;
+        }
+        
+        if matches!(file.complexity.rating, ComplexityRating::Complex | ComplexityRating::VeryComplex) {
+            summary.complex_files += 1;
+        }
+        
+        summary.total_complexity += file.complexity.cyclomatic_complexity;
+        
+        if file.complexity.cyclomatic_complexity > summary.max_complexity {
+            summary.max_complexity = file.complexity.cyclomatic_complexity;
+            summary.most_complex_file = file.file_info.name.clone();
+        }
+    }
+    
+    pub fn finish(self) -> DirectorySummary {
+        let mut summary = self.summary;
         summary.average_complexity = if summary.total_files > 0 {
             summary.total_complexity as f64 / summary.total_files as f64
         } else {
             0.0
         };
-        
-        self.summary = summary;
+        summary.comment_density = crate::metrics::loc::comment_density(self.comment_lines, self.code_lines);
+        summary
+    }
+}
+
+/// `--lang-map GLOB=LANG`: files matching `glob` are analyzed as `language`
+///
+/// A glob without `/` matches the file name (`*.tmpl`); one with `/` matches
+/// the path from `glob_root`, or the path as walked (`templates/**/*.tmpl`).
+#[derive(Debug, Clone, PartialEq, Eq, Serialize, Deserialize)]
+pub struct LanguageOverride {
+    pub glob: String,
+    pub language: Language,
+}
+
+impl LanguageOverride {
+    /// Parse `GLOB=LANG` (`*.tmpl=go`)
+    pub fn parse(value: &str) -> Option<Self> {
+        let (glob, language) = value.rsplit_once('=')?;
+        let glob = glob.trim();
+        if glob.is_empty() {
+            return None;
+        }
+        Some(Self { glob: glob.to_string(), language: Language::from_name(language)? })
     }
 }
 
@@ -585,11 +1447,94 @@ pub struct AnalysisConfig {
     pub include_test_files: bool,
     pub complete_analysis: bool,
     pub enable_parallel_processing: bool,
+    /// Files analyzed concurrently (0 = number of logical CPUs)
     pub max_threads: usize,
     pub verbose_output: bool,
     pub include_line_numbers: bool,
     /// 🚀 Parser type: "pest" (default) or "tree-sitter" (100x faster!)
     pub parser_type: String,
+    /// Reuse results for unchanged files from the on-disk cache
+    pub cache_enabled: bool,
+    /// Cache directory (default: `.nekocode-cache` next to the analyzed path)
+    pub cache_dir: Option<PathBuf>,
+    /// Honor .gitignore and .nekocodeignore files during directory walks
+    pub respect_ignore_files: bool,
+    /// Only analyze files matching one of these globs (empty = all)
+    #[serde(default)]
+    pub include_globs: Vec<String>,
+    /// Skip files matching one of these globs
+    #[serde(default)]
+    pub exclude_globs: Vec<String>,
+    /// Directory the globs are relative to (default: the analyzed path)
+    #[serde(default)]
+    pub glob_root: Option<PathBuf>,
+    /// Languages picked up by directory walks (empty = all supported)
+    #[serde(default)]
+    pub enabled_languages: Vec<Language>,
+    /// Languages forced for matching globs, ahead of extension / shebang detection
+    #[serde(default)]
+    pub language_overrides: Vec<LanguageOverride>,
+    /// Cyclomatic complexity above which a function counts as over threshold
+    #[serde(default)]
+    pub max_cyclomatic: Option<u32>,
+    /// Cognitive complexity above which a function counts as over threshold
+    #[serde(default)]
+    pub max_cognitive: Option<u32>,
+    /// Symbols kept in file results (exported, internal or all)
+    #[serde(default)]
+    pub visibility: Visibility,
+    /// Comment marker tags reported in file results (`TODO`, `FIXME`, ...)
+    #[serde(default = "default_markers")]
+    pub markers: Vec<String>,
+    /// Restrict directory walks to these files (canonical paths; `--since`)
+    #[serde(default)]
+    pub only_files: Option<Vec<PathBuf>>,
+    /// Descend into directory symlinks during walks (each real directory once)
+    #[serde(default)]
+    pub follow_symlinks: bool,
+    /// Go build tags; Go files whose build constraints they do not satisfy are skipped
+    #[serde(default)]
+    pub build_tags: Option<Vec<String>>,
+    /// Files-processed counter on stderr during directory runs (silent by default)
+    #[serde(default)]
+    pub progress: ProgressMode,
+    /// Symbols kept in file results, selected by a `--query` expression
+    #[serde(default)]
+    pub query: Option<SymbolQuery>,
+    /// Files larger than this many bytes are skipped with a warning, unread (None = no limit)
+    #[serde(default = "default_max_file_size")]
+    pub max_file_size: Option<u64>,
+    /// Only this many leading bytes of a file are parsed, cut at a line end (None = all)
+    #[serde(default)]
+    pub max_parse_bytes: Option<u64>,
+    /// Skip files whose header marks them as generated instead of flagging them
+    #[serde(default)]
+    pub exclude_generated: bool,
+    /// Fail on files that are not valid UTF-8 instead of decoding them lossily
+    #[serde(default)]
+    pub strict_utf8: bool,
+    /// Re-parse each file to record parse time, node count and tree depth
+    #[serde(default)]
+    pub diagnostics: bool,
+    /// Which smells are reported, and their thresholds
+    #[serde(default)]
+    pub smells: SmellConfig,
+    /// Weight of each decision-point construct in cyclomatic complexity
+    #[serde(default)]
+    pub complexity_weights: ComplexityWeights,
+}
+
+/// Default `max_file_size`: generated files beyond this size are rarely worth their parse trees
+pub const DEFAULT_MAX_FILE_SIZE: u64 = 5 * 1024 * 1024;
+
+/// Helper: serde default of `AnalysisConfig::markers`
+fn default_markers() -> Vec<String> {
+    DEFAULT_MARKERS.iter().map(|m| m.to_string()).collect()
+}
+
+/// Helper: serde default of `AnalysisConfig::max_file_size`
+fn default_max_file_size() -> Option<u64> {
+    Some(DEFAULT_MAX_FILE_SIZE)
 }
 
 impl Default for AnalysisConfig {
@@ -605,13 +1550,34 @@ impl Default for AnalysisConfig {
                 // C
                 ".c".to_string(), ".h".to_string(),
                 // Python
-                ".py".to_string(), ".pyw".to_string(), ".pyi".to_string(),
+                ".py".to_string(), ".pyw".to_string(), ".pyi".to_string(), ".ipynb".to_string(),
                 // C#
                 ".cs".to_string(),
                 // Go
                 ".go".to_string(),
                 // Rust
                 ".rs".to_string(),
+                // Ruby
+                ".rb".to_string(),
+                // Java
+                ".java".to_string(),
+                // Kotlin
+                ".kt".to_string(),
+                ".kts".to_string(),
+                // Swift
+                ".swift".to_string(),
+                // PHP
+                ".php".to_string(),
+                ".phtml".to_string(),
+                // Lua
+                ".lua".to_string(),
+                // Dart
+                ".dart".to_string(),
+                // Scala
+                ".scala".to_string(),
+                ".sc".to_string(),
+                // Zig
+                ".zig".to_string(),
             ],
             excluded_patterns: vec![
                 "node_modules".to_string(), ".git".to_string(), "dist".to_string(), 
@@ -627,6 +1593,30 @@ impl Default for AnalysisConfig {
             verbose_output: false,
             include_line_numbers: true,
             parser_type: "pest".to_string(), // Default to PEST for backward compatibility
+            cache_enabled: false,
+            cache_dir: None,
+            respect_ignore_files: true,
+            include_globs: Vec::new(),
+            exclude_globs: Vec::new(),
+            glob_root: None,
+            enabled_languages: Vec::new(),
+            language_overrides: Vec::new(),
+            max_cyclomatic: None,
+            max_cognitive: None,
+            visibility: Visibility::All,
+            markers: default_markers(),
+            only_files: None,
+            follow_symlinks: false,
+            build_tags: None,
+            progress: ProgressMode::Never,
+            query: None,
+            max_file_size: default_max_file_size(),
+            max_parse_bytes: None,
+            exclude_generated: false,
+            strict_utf8: false,
+            diagnostics: false,
+            smells: SmellConfig::default(),
+            complexity_weights: ComplexityWeights::default(),
         }
     }
 }
\ No newline at end of file
@@ -0,0 +1,262 @@
+//! 👁️ Symbol visibility (export status)
+//!
+//! Each language signals "visible outside its module" differently: Go by
+//! capitalization, Python and Dart by the leading-underscore convention, Rust,
+//! C#, Java, Kotlin, Scala, Swift and PHP by modifiers, JavaScript by `export`,
+//! Lua by `local`, Zig by `pub`. This module turns those signals into one public / private
+//! answer so `--visibility` filters every analyzer's output the same way.
+
+use schemars::JsonSchema;
+use serde::{Deserialize, Serialize};
+
+use crate::core::types::{AnalysisResult, ClassInfo, FunctionInfo, Language, MemberVariable};
+
+/// Which symbols to report
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, Serialize, Deserialize, JsonSchema)]
+#[serde(rename_all = "lowercase")]
+pub enum Visibility {
+    /// Exported symbols only
+    Public,
+    /// Module-internal symbols only
+    Private,
+    /// Everything (no filtering)
+    #[default]
+    All,
+}
+
+impl Visibility {
+    /// Parse a `--visibility` value
+    pub fn parse(value: &str) -> Option<Self> {
+        match value.to_lowercase().as_str() {
+            "public" => Some(Self::Public),
+            "private" => Some(Self::Private),
+            "all" => Some(Self::All),
+            _ => None,
+        }
+    }
+    
+    /// Whether a symbol with the given export status is reported
+    pub fn admits(self, is_public: bool) -> bool {
+        match self {
+            Self::Public => is_public,
+            Self::Private => !is_public,
+            Self::All => true,
+        }
+    }
+    
+    /// Drop the functions, classes and members of a file result this filter rejects
+    ///
+    /// A private class goes away under `public` together with its methods. Under
+    /// `private`, a public class is kept as long as it still holds private
+    /// methods or members, so those stay attributed to their owner.
+    pub fn filter(self, result: &mut AnalysisResult) {
+        if self == Self::All {
+            return;
+        }
+        
+        let exported_names = exported_names(result);
+        let language = result.language;
+        
+        result.functions.retain(|func| self.admits(is_public_function(language, func, exported_names.as_deref())));
+        result.classes.retain_mut(|class| {
+            let class_public = is_public_class(language, class, exported_names.as_deref());
+            if self == Self::Public && !class_public {
+                return false;
+            }
+            class.methods.retain(|method| self.admits(is_public_method(language, method)));
+            class.member_variables.retain(|member| self.admits(is_public_member(language, member)));
+            self.admits(class_public) || !class.methods.is_empty() || !class.member_variables.is_empty()
+        });
+        
+        result.update_statistics();
+    }
+}
+
+/// Whether a function or method is visible outside its package / module
+///
+/// `exported_names` lists the names of a JavaScript module's `export`s;
+/// `None` (no exports at all, e.g. a script) makes every function public.
+pub fn is_public_function(language: Language, func: &FunctionInfo, exported_names: Option<&[String]>) -> bool {
+    match language {
+        Language::JavaScript | Language::TypeScript => {
+            exported_names.map_or(true, |names| names.iter().any(|name| name == &func.name))
+        }
+        _ => is_public_method(language, func),
+    }
+}
+
+/// Whether a class / struct / interface is visible outside its package / module
+pub fn is_public_class(language: Language, class: &ClassInfo, exported_names: Option<&[String]>) -> bool {
+    let modifiers = class.metadata.get("modifiers").map(|m| m.as_str()).unwrap_or("");
+    
+    match language {
+        Language::Go => is_capitalized(&class.name),
+        Language::Python | Language::Dart => !class.name.starts_with('_'),
+        Language::Rust => modifiers.split_whitespace().any(|m| m.starts_with("pub")),
+        Language::CSharp | Language::Java => modifiers.split_whitespace().any(|m| m == "public"),
+        Language::Kotlin | Language::Scala => class.metadata.get("visibility").map_or(true, |v| v == "public"),
+        Language::Swift => is_swift_public(class.metadata.get("visibility")),
+        Language::Lua => class.metadata.get("visibility").map_or(true, |v| v != "local"),
+        Language::Zig => class.metadata.get("visibility").map_or(false, |v| v == "public"),
+        Language::JavaScript | Language::TypeScript => {
+            exported_names.map_or(true, |names| names.iter().any(|name| name == &class.name))
+        }
+        // No visibility information: everything is public
+        _ => true,
+    }
+}
+
+/// Whether a member variable is visible outside its type
+pub fn is_public_member(language: Language, member: &MemberVariable) -> bool {
+    match language {
+        Language::Go => is_capitalized(&member.name),
+        Language::Python | Language::Dart => !member.name.starts_with('_'),
+        Language::Swift => is_swift_public(Some(&member.access_modifier)),
+        // PHP `var $x` and promoted parameters without a modifier are public
+        Language::Php => member.access_modifier != "private" && member.access_modifier != "protected",
+        _ => member.access_modifier == "public",
+    }
+}
+
+/// Export status from the symbol's own declaration (methods carry no `export`)
+pub fn is_public_method(language: Language, func: &FunctionInfo) -> bool {
+    let modifiers = func.metadata.get("modifiers").map(|m| m.as_str()).unwrap_or("");
+    
+    match language {
+        Language::Go => is_capitalized(&func.name),
+        Language::Python => !func.name.starts_with('_'),
+        Language::Rust => {
+            func.metadata.get("is_public").map_or(false, |v| v == "true")
+                || modifiers.split_whitespace().any(|m| m.starts_with("pub"))
+        }
+        Language::CSharp | Language::Java => modifiers.split_whitespace().any(|m| m == "public"),
+        // Kotlin and Scala declarations are public unless marked otherwise
+        Language::Kotlin | Language::Scala => func.metadata.get("visibility").map_or(true, |v| v == "public"),
+        Language::Swift => is_swift_public(func.metadata.get("visibility")),
+        // PHP methods are public unless marked otherwise
+        Language::Php => func.metadata.get("visibility").map_or(true, |v| v == "public"),
+        // Lua `local` functions stay in their chunk
+        Language::Lua => func.metadata.get("visibility").map_or(true, |v| v != "local"),
+        // Zig declarations are private to their file unless marked `pub`
+        Language::Zig => func.metadata.get("visibility").map_or(false, |v| v == "public"),
+        // Dart `_name` members are library-private, `Counter._internal` included
+        Language::Dart => func.metadata.get("visibility").map_or(true, |v| v == "public"),
+        // No visibility information: every symbol is public
+        _ => true,
+    }
+}
+
+/// Names exported by a JavaScript / TypeScript module, `None` without exports
+pub fn exported_names(result: &AnalysisResult) -> Option<Vec<String>> {
+    if !matches!(result.language, Language::JavaScript | Language::TypeScript) || result.exports.is_empty() {
+        return None;
+    }
+    Some(result.exports.iter().flat_map(|export| export.exported_names.iter().cloned()).collect())
+}
+
+/// Helper: Swift declarations are `internal` (module-only) unless `public` or `open`
+fn is_swift_public(visibility: Option<&String>) -> bool {
+    visibility.map_or(false, |v| v == "public" || v == "open")
+}
+
+/// Helper: Go export rule
+fn is_capitalized(name: &str) -> bool {
+    name.chars().next().map_or(false, |c| c.is_uppercase())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{ExportInfo, ExportType, FileInfo};
+    use std::path::PathBuf;
+    
+    fn go_result() -> AnalysisResult {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("server.go")), Language::Go);
+        result.functions.push(FunctionInfo::new("Serve".to_string()));
+        result.functions.push(FunctionInfo::new("handle".to_string()));
+        
+        let mut server = ClassInfo::new("Server".to_string());
+        server.methods.push(FunctionInfo::new("Start".to_string()));
+        server.methods.push(FunctionInfo::new("loop".to_string()));
+        server.member_variables.push(MemberVariable::new("Addr".to_string(), "string".to_string(), 3));
+        server.member_variables.push(MemberVariable::new("conns".to_string(), "int".to_string(), 4));
+        result.classes.push(server);
+        result.classes.push(ClassInfo::new("state".to_string()));
+        result
+    }
+    
+    fn names<'a>(items: impl Iterator<Item = &'a String>) -> Vec<&'a str> {
+        items.map(|s| s.as_str()).collect()
+    }
+    
+    #[test]
+    fn test_parse() {
+        assert_eq!(Visibility::parse("public"), Some(Visibility::Public));
+        assert_eq!(Visibility::parse("PRIVATE"), Some(Visibility::Private));
+        assert_eq!(Visibility::parse("all"), Some(Visibility::All));
+        assert_eq!(Visibility::parse("exported"), None);
+    }
+    
+    #[test]
+    fn test_filter_public_go() {
+        let mut result = go_result();
+        Visibility::Public.filter(&mut result);
+        
+        assert_eq!(names(result.functions.iter().map(|f| &f.name)), vec!["Serve"]);
+        assert_eq!(names(result.classes.iter().map(|c| &c.name)), vec!["Server"]);
+        let server = &result.classes[0];
+        assert_eq!(names(server.methods.iter().map(|m| &m.name)), vec!["Start"]);
+        assert_eq!(names(server.member_variables.iter().map(|m| &m.name)), vec!["Addr"]);
+        assert_eq!(result.stats.function_count, 1);
+    }
+    
+    #[test]
+    fn test_filter_private_go() {
+        let mut result = go_result();
+        Visibility::Private.filter(&mut result);
+        
+        assert_eq!(names(result.functions.iter().map(|f| &f.name)), vec!["handle"]);
+        // Server stays as the owner of its private method and field
+        assert_eq!(names(result.classes.iter().map(|c| &c.name)), vec!["Server", "state"]);
+        let server = &result.classes[0];
+        assert_eq!(names(server.methods.iter().map(|m| &m.name)), vec!["loop"]);
+        assert_eq!(names(server.member_variables.iter().map(|m| &m.name)), vec!["conns"]);
+    }
+    
+    #[test]
+    fn test_modifier_languages() {
+        let mut public = FunctionInfo::new("run".to_string());
+        public.metadata.insert("modifiers".to_string(), "public static".to_string());
+        let package_private = FunctionInfo::new("helper".to_string());
+        assert!(is_public_function(Language::Java, &public, None));
+        assert!(!is_public_function(Language::Java, &package_private, None));
+        
+        let mut rust = FunctionInfo::new("parse".to_string());
+        rust.metadata.insert("modifiers".to_string(), "pub(crate)".to_string());
+        assert!(is_public_function(Language::Rust, &rust, None));
+        assert!(!is_public_function(Language::Rust, &package_private, None));
+        
+        let mut internal = FunctionInfo::new("load".to_string());
+        internal.metadata.insert("visibility".to_string(), "internal".to_string());
+        assert!(!is_public_function(Language::Kotlin, &internal, None));
+        assert!(is_public_function(Language::Kotlin, &package_private, None));
+    }
+    
+    #[test]
+    fn test_javascript_exports() {
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("util.js")), Language::JavaScript);
+        result.functions.push(FunctionInfo::new("format".to_string()));
+        result.functions.push(FunctionInfo::new("pad".to_string()));
+        
+        // A script without exports exposes everything
+        let mut script = result.clone();
+        Visibility::Public.filter(&mut script);
+        assert_eq!(script.functions.len(), 2);
+        
+        let mut export = ExportInfo::new(ExportType::ES6Export);
+        export.exported_names.push("format".to_string());
+        result.exports.push(export);
+        Visibility::Public.filter(&mut result);
+        assert_eq!(names(result.functions.iter().map(|f| &f.name)), vec!["format"]);
+    }
+}
@@ -2,13 +2,62 @@
 //! 
 //! This is a complete Rust port of the NekoCode C++ analyzer, providing
 //! fast, accurate analysis of source code for multiple programming languages.
+//! 
+//! The analysis engine is usable as a library (`nekocode_core`); the
+//! `nekocode-rust` CLI is a thin binary on top of it:
+//! 
+//! ```no_run
+//! # async fn example() -> anyhow::Result<()> {
+//! use nekocode_core::{analyze_dir, analyze_file, AnalysisConfig};
+//!
+//! let file = analyze_file("src/server.go", None).await?;
+//! println!("{} functions", file.functions.len());
+//!
+//! let project = analyze_dir("src", &AnalysisConfig::default()).await?;
+//! println!("{}", serde_json::to_string(&project.summary)?);
+//! # Ok(())
+//! # }
+//! ```
 
 pub mod core;
 pub mod analyzers;
 pub mod commands;
+pub mod metrics;
+
+use anyhow::{Context, Result};
+use std::path::Path;
 
 pub use core::types::*;
 pub use core::session::AnalysisSession;
 pub use core::commands::{Command, CommandProcessor, CommandResult};
+pub use core::visibility::Visibility;
 pub use analyzers::traits::LanguageAnalyzer;
-pub use analyzers::javascript::JavaScriptAnalyzer;
\ No newline at end of file
+pub use analyzers::javascript::JavaScriptAnalyzer;
+pub use metrics::plugin::{register_metric_plugin, FunctionNode, MetricPlugin, MetricValue};
+
+/// Analyze one source file
+///
+/// `language` overrides detection from the file extension (and shebang);
+/// `None` detects it. Uses the default [`AnalysisConfig`].
+pub async fn analyze_file(path: impl AsRef<Path>, language: Option<Language>) -> Result<AnalysisResult> {
+    let path = path.as_ref();
+    let session = AnalysisSession::new();
+    
+    match language {
+        Some(language) => {
+            let content = tokio::fs::read_to_string(path).await
+                .with_context(|| format!("Failed to read file: {}", path.display()))?;
+            session.analyze_source(&content, path, language).await
+        }
+        None => session.analyze_file(path).await,
+    }
+}
+
+/// Analyze every supported file under a directory (or a single file)
+///
+/// `options` controls discovery and output exactly as the CLI flags do
+/// (`include_test_files`, `exclude_globs`, `visibility`, ...).
+pub async fn analyze_dir(path: impl AsRef<Path>, options: &AnalysisConfig) -> Result<DirectoryAnalysis> {
+    let mut session = AnalysisSession::with_config(options.clone());
+    session.analyze_path(path.as_ref(), options.include_test_files).await
+}
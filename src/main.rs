@@ -1,17 +1,44 @@
-mod core;
-mod analyzers;
-mod commands;
+use nekocode_core::{commands, core};
 
 use anyhow::Result;
 use clap::{Parser, Subcommand};
-use std::path::PathBuf;
+use std::path::{Path, PathBuf};
 
 use crate::core::session::{AnalysisSession, SessionManager};
-use crate::core::types::{AnalysisConfig, DirectoryAnalysis};
+use crate::core::types::{AnalysisConfig, DirectoryAnalysis, Language, LanguageOverride};
 use crate::core::config::ConfigManager;
 use crate::core::memory::{MemoryManager, MemoryType};
 use crate::core::preview::PreviewManager;
 use crate::core::impact::{ImpactAnalyzer, ImpactConfig, OutputFormatter, RiskLevel};
+use crate::core::callgraph::{coupling_to_text, cycles_to_text, CallGraph};
+use crate::core::importgraph::{import_cycles_to_text, ImportGraph};
+use crate::core::hierarchy::TypeHierarchy;
+use crate::core::deadcode::DeadCodeReport;
+use crate::core::references::ReferenceReport;
+use crate::core::diff::SnapshotDiff;
+use crate::core::duplicates::DuplicateReport;
+use crate::core::schema::{output_schema, SchemaRoot};
+use crate::core::stats::ProjectStats;
+use crate::core::ranking::{Ranking, SortKey};
+use crate::core::report::{render_html, DEFAULT_REPORT_THRESHOLD};
+use crate::core::redact::Redaction;
+use crate::core::csv::functions_to_csv;
+use crate::core::diagnostics::{slowest_entries_to_text, slowest_to_text, ParseDiagnostics};
+use crate::core::gate::{violations_to_text, ComplexityGate, GateViolation};
+use crate::core::baseline::{Baseline, BaselineMode};
+use crate::core::markers::MarkerReport;
+use crate::core::heatmap::ComplexityHeatmap;
+use crate::core::validate::ValidationReport;
+use crate::core::git::ChangedFiles;
+use crate::core::churn::annotate_churn;
+use crate::core::visibility::Visibility;
+use crate::core::query::SymbolQuery;
+use crate::core::progress::ProgressMode;
+use crate::core::cache::{default_cache_dir, AnalysisCache};
+use crate::core::project_config::{load_analysis_config, LanguageMap};
+
+/// Synthetic file name reported for source read from stdin
+const STDIN_FILE_NAME: &str = "<stdin>";
 
 #[derive(Parser)]
 #[command(name = "nekocode-rust")]
@@ -26,11 +53,32 @@ struct Cli {
 enum Commands {
     /// Analyze source code files (powered by ultra-fast Tree-sitter)
     Analyze {
-        /// Path to analyze (file or directory)
-        #[arg(value_name = "PATH")]
-        path: PathBuf,
+        /// Paths to analyze (files, directories, or a .zip / .tar.gz archive); `-` reads source from stdin.
+        /// Several paths are analyzed as one project
+        #[arg(value_name = "PATH", required_unless_present_any = ["stdin", "files_from"])]
+        paths: Vec<PathBuf>,
+        
+        /// Analyze exactly the files listed in FILE, one path per line (`-` for stdin), as one
+        /// project; no directory is walked and missing files are reported as errors
+        #[arg(long, value_name = "FILE", conflicts_with_all = ["paths", "stdin", "rev", "since"])]
+        files_from: Option<PathBuf>,
+        
+        /// Read a single file's source from stdin (requires --lang)
+        #[arg(long)]
+        stdin: bool,
+        
+        /// Language of the stdin source (go, rust, python, ...); for paths, the languages
+        /// analyzed (e.g. go,python), other files are skipped
+        #[arg(long, value_name = "LANG")]
+        lang: Option<String>,
         
-        /// Output format
+        /// Analyze files matching GLOB as LANG, ahead of extension / shebang detection
+        /// (e.g. '*.tmpl=go'; repeatable, the first matching glob wins)
+        #[arg(long, value_name = "GLOB=LANG")]
+        lang_map: Vec<String>,
+        
+        /// Output format (json, ndjson: one file result per line, then a summary line, csv: one row per function,
+        /// summary: one line of totals for prompts and hooks)
         #[arg(short, long, default_value = "json")]
         format: String,
         
@@ -49,6 +97,132 @@ enum Commands {
         /// Number of worker threads (default: 16)
         #[arg(short, long, default_value = "16")]
         threads: usize,
+        
+        /// Number of files analyzed in parallel (default: logical CPUs)
+        #[arg(short, long, value_name = "N")]
+        jobs: Option<usize>,
+        
+        /// Reuse results for unchanged files from the on-disk cache
+        #[arg(long, overrides_with = "no_cache")]
+        cache: bool,
+        
+        /// Disable the analysis cache
+        #[arg(long)]
+        no_cache: bool,
+        
+        /// Cache directory (default: .nekocode-cache next to PATH)
+        #[arg(long, value_name = "DIR")]
+        cache_dir: Option<PathBuf>,
+        
+        /// Do not honor .gitignore / .nekocodeignore files
+        #[arg(long)]
+        no_ignore: bool,
+        
+        /// Descend into symlinked directories (each real directory is walked once)
+        #[arg(long)]
+        follow_symlinks: bool,
+        
+        /// Skip Go files whose build constraints these tags do not satisfy (e.g. linux,amd64)
+        #[arg(long, value_name = "TAGS", value_delimiter = ',')]
+        build_tags: Option<Vec<String>>,
+        
+        /// Report only exported symbols, only internal ones, or all (public, private, all)
+        #[arg(long, default_value = "all")]
+        visibility: String,
+        
+        /// Comment marker tags to report (default: TODO,FIXME,HACK,XXX)
+        #[arg(long, value_name = "TAGS", value_delimiter = ',')]
+        markers: Option<Vec<String>>,
+        
+        /// Report only symbols matching an expression (e.g. 'complexity > 10 and name ~= "^Get"')
+        #[arg(long, value_name = "EXPR")]
+        query: Option<String>,
+        
+        /// Report functions with more than N parameters as a smell (default: [smells] in nekocode.toml, else 5)
+        #[arg(long, value_name = "N")]
+        max_params: Option<usize>,
+        
+        /// Report functions spanning more than N lines, doc comments excluded, as a `long_function` smell
+        #[arg(long, value_name = "N")]
+        max_function_loc: Option<u32>,
+        
+        /// Only analyze files changed relative to a git reference (branch, commit, tag)
+        #[arg(long, value_name = "REF")]
+        since: Option<String>,
+        
+        /// Analyze PATH as it was at a commit, read from git without a checkout (reported as PATH@COMMIT)
+        #[arg(long, value_name = "COMMIT", conflicts_with = "since")]
+        rev: Option<String>,
+        
+        /// Add `churn` (commits touching each function's lines, from git log -L) and
+        /// `risk` (cyclomatic complexity x churn) to every function; one git call per function
+        #[arg(long, conflicts_with = "rev")]
+        with_churn: bool,
+        
+        /// Count churn only over commits since DATE (git log --since, e.g. 6.months; default: all history)
+        #[arg(long, value_name = "DATE", requires = "with_churn")]
+        churn_since: Option<String>,
+        
+        /// Exit non-zero if any function's cyclomatic complexity exceeds N
+        #[arg(long, value_name = "N")]
+        fail_on_complexity: Option<u32>,
+        
+        /// Exit non-zero if any function's cognitive complexity exceeds N
+        #[arg(long, value_name = "N")]
+        fail_on_cognitive: Option<u32>,
+        
+        /// Exit non-zero if any function spans more lines than --max-function-loc
+        #[arg(long, requires = "max_function_loc")]
+        fail_on_long_functions: bool,
+        
+        /// Fail only on gate findings not recorded in this baseline file
+        #[arg(long, value_name = "FILE", conflicts_with = "write_baseline")]
+        baseline: Option<PathBuf>,
+        
+        /// Record the current gate findings to this file instead of failing on them
+        #[arg(long, value_name = "FILE")]
+        write_baseline: Option<PathBuf>,
+        
+        /// Show files processed / total on stderr (always, never, auto = only on a terminal)
+        #[arg(long, value_name = "MODE", default_value = "auto")]
+        progress: String,
+        
+        /// Skip files larger than this many bytes with a warning (default: 5 MiB, 0 = no limit)
+        #[arg(long, value_name = "BYTES")]
+        max_file_size: Option<u64>,
+        
+        /// Parse only the first BYTES of each file, cut at a line end (0 = no limit)
+        #[arg(long, value_name = "BYTES")]
+        max_file_bytes_parse: Option<u64>,
+        
+        /// Skip generated files (`// Code generated ... DO NOT EDIT.` headers) instead of marking them
+        #[arg(long)]
+        exclude_generated: bool,
+        
+        /// Fail on files that are not valid UTF-8 instead of decoding them lossily with an encoding warning
+        #[arg(long)]
+        strict_utf8: bool,
+        
+        /// Record parse time, node count, tree depth and parse errors per file; list the slowest on stderr
+        #[arg(long)]
+        diagnostics: bool,
+        
+        /// Order functions worst first by complexity, loc, fan_in or name (ties: file, then line)
+        #[arg(long, value_name = "KEY")]
+        sort_by: Option<String>,
+        
+        /// Keep only the first N functions (by complexity unless --sort-by is given)
+        #[arg(long, value_name = "N")]
+        top: Option<usize>,
+        
+        /// Strip source text from the output (comments, markers, literals, code snippets),
+        /// keeping names, kinds, metrics and relations
+        #[arg(long)]
+        redact: bool,
+        
+        /// Also replace symbol names with short hashes, the same for every occurrence (implies --redact)
+        #[arg(long)]
+        redact_names: bool,
     },
     
     /// Analyze code changes and show their impact across the codebase
@@ -57,7 +231,7 @@ enum Commands {
         #[arg(value_name = "PATH")]
         path: PathBuf,
         
-        /// Output format (plain, json, github-comment)
+        /// Output format (plain, json, github-comment, sarif)
         #[arg(short, long, default_value = "plain")]
         format: String,
         
@@ -70,7 +244,7 @@ enum Commands {
         include_tests: bool,
         
         /// Compare against specific git reference (branch, commit, tag)
-        #[arg(long)]
+        #[arg(long, visible_alias = "since", value_name = "REF")]
         compare_ref: Option<String>,
         
         /// Skip circular dependency analysis
@@ -80,9 +254,308 @@ enum Commands {
         /// Risk threshold for reporting (low, medium, high)
         #[arg(long, default_value = "low")]
         risk_threshold: String,
+        
+        /// Minimum risk reported as SARIF "error" (low, medium, high)
+        #[arg(long, default_value = "high")]
+        sarif_error_threshold: String,
+        
+        /// Strip source text from the output (comments, markers, literals, code snippets),
+        /// keeping names, kinds, metrics and relations
+        #[arg(long)]
+        redact: bool,
+        
+        /// Also replace symbol names with short hashes, the same for every occurrence (implies --redact)
+        #[arg(long)]
+        redact_names: bool,
+    },
+    
+    /// Build a call graph (who calls whom) for a file or directory
+    Callgraph {
+        /// Path to analyze (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Output format (json, dot, text = fan-in / fan-out hotspots)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+        
+        /// Functions listed per ranking with --format text
+        #[arg(long, value_name = "N", default_value = "10")]
+        top: usize,
+        
+        /// Root symbol to expand from (e.g. "main" or "DataProcessor.ProcessData")
+        #[arg(long)]
+        root: Option<String>,
+        
+        /// Maximum call depth to expand from the root symbol
+        #[arg(long, requires = "root")]
+        depth: Option<usize>,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+    },
+    
+    /// Build the class hierarchy (extends / implements) for a file or directory
+    Hierarchy {
+        /// Path to analyze (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Output format (json, dot)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+    },
+    
+    /// List mutually recursive functions (cycles in the call graph)
+    Cycles {
+        /// Path to analyze (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Output format (json, text)
+        #[arg(short, long, default_value = "text")]
+        format: String,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+    },
+    
+    /// List cyclic imports between packages / modules (Go, Python, JavaScript / TypeScript, Zig)
+    ImportCycles {
+        /// Path to analyze (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Output format (json, text)
+        #[arg(short, long, default_value = "text")]
+        format: String,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+    },
+    
+    /// Write a shareable report of files, functions and complexity
+    Report {
+        /// Path to analyze (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Report format (html)
+        #[arg(short, long, default_value = "html")]
+        format: String,
+        
+        /// Output file (default: stdout)
+        #[arg(short, long, value_name = "FILE")]
+        output: Option<PathBuf>,
+        
+        /// Complexity above which functions are highlighted (default: max_cyclomatic from .nekocode.toml, else 10)
+        #[arg(long, value_name = "N")]
+        threshold: Option<u32>,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+        
+        /// Strip source text from the output (comments, markers, literals, code snippets),
+        /// keeping names, kinds, metrics and relations
+        #[arg(long)]
+        redact: bool,
+        
+        /// Also replace symbol names with short hashes, the same for every occurrence (implies --redact)
+        #[arg(long)]
+        redact_names: bool,
+    },
+    
+    /// Print the JSON Schema (draft 2020-12) of the analysis output
+    Schema {
+        /// Top-level type: file (AnalysisResult) or directory (DirectoryAnalysis)
+        #[arg(long, default_value = "file")]
+        root: String,
+    },
+    
+    /// Report exported functions/methods that are never referenced
+    Deadcode {
+        /// Path to analyze (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Output format (json)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+        
+        /// Symbols checked for references (public, private, all)
+        #[arg(long, default_value = "public")]
+        visibility: String,
+    },
+    
+    /// List every reference to one function, method or class (file, line, column, snippet)
+    FindReferences {
+        /// Project to search (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Symbol to look up: name, Owner.name or namespace.name (e.g. "DataProcessor.Run", "proc.New")
+        #[arg(long)]
+        symbol: String,
+        
+        /// Definition to use when several match, as FILE:LINE (e.g. "pkg/util.go:12")
+        #[arg(long, value_name = "FILE:LINE")]
+        definition: Option<String>,
+        
+        /// Output format (json, text)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+    },
+    
+    /// Compare two saved `analyze` JSON snapshots symbol by symbol
+    Diff {
+        /// Older snapshot (JSON output of `analyze`)
+        #[arg(value_name = "OLD")]
+        old: PathBuf,
+        
+        /// Newer snapshot (JSON output of `analyze`)
+        #[arg(value_name = "NEW")]
+        new: PathBuf,
+        
+        /// Output format (json, text)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+    },
+    
+    /// Find copy-pasted functions (identical or near-identical normalized bodies)
+    Duplicates {
+        /// Target path (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Minimum token-shingle similarity for near-identical clones (0.0-1.0, 1.0 = identical only)
+        #[arg(long, default_value = "0.9")]
+        min_similarity: f64,
+        
+        /// Ignore functions with fewer normalized tokens than this
+        #[arg(long, default_value = "40")]
+        min_tokens: usize,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+        
+        /// Output format (json, text)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+        
+        /// Order clone groups by complexity, loc, fan_in or name instead of size (ties: file, then line)
+        #[arg(long, value_name = "KEY")]
+        sort_by: Option<String>,
+        
+        /// Keep only the first N clone groups
+        #[arg(long, value_name = "N")]
+        top: Option<usize>,
+    },
+    
+    /// Project-wide totals: files per language, functions, classes, complexity, SLOC
+    Stats {
+        /// Target path (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+        
+        /// Output format (json, text)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+        
+        /// Count only exported symbols, only internal ones, or all (public, private, all)
+        #[arg(long, default_value = "all")]
+        visibility: String,
+        
+        /// Order the function list by complexity, loc, fan_in or name (ties: file, then line)
+        #[arg(long, value_name = "KEY")]
+        sort_by: Option<String>,
+        
+        /// Length of the function list (default: 10)
+        #[arg(long, value_name = "N")]
+        top: Option<usize>,
+    },
+    
+    /// Count functions per cyclomatic complexity band (1-5, 6-10, 11-20, 21+), overall and per file
+    ComplexityHeatmap {
+        /// Target path (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+        
+        /// Output format (text, json)
+        #[arg(short, long, default_value = "text")]
+        format: String,
+    },
+    
+    /// List TODO / FIXME / HACK / XXX comments grouped by tag
+    Markers {
+        /// Target path (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Comment marker tags to report (default: TODO,FIXME,HACK,XXX)
+        #[arg(long, value_name = "TAGS", value_delimiter = ',')]
+        markers: Option<Vec<String>>,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+        
+        /// Output format (json, text)
+        #[arg(short, long, default_value = "json")]
+        format: String,
+    },
+    
+    /// Check that every file parses cleanly, without analyzing it (exits non-zero on errors)
+    Validate {
+        /// Target path (file or directory); test files are checked too
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Output format (text, json)
+        #[arg(short, long, default_value = "text")]
+        format: String,
+        
+        /// Number of files parsed in parallel (default: logical CPUs)
+        #[arg(short, long, value_name = "N")]
+        jobs: Option<usize>,
+        
+        /// Do not honor .gitignore / .nekocodeignore files
+        #[arg(long)]
+        no_ignore: bool,
+        
+        /// Only check files changed relative to a git reference (branch, commit, tag)
+        #[arg(long, value_name = "REF")]
+        since: Option<String>,
     },
     
     // SESSION MODE
+    /// Index a project once, then answer find / refs / callers / complexity / impact at a prompt
+    Session {
+        /// Project root to index
+        #[arg(value_name = "PATH", default_value = ".")]
+        path: PathBuf,
+        
+        /// Include test files in the index
+        #[arg(long)]
+        include_tests: bool,
+    },
+    
     /// Create a new analysis session
     SessionCreate {
         /// Path to analyze
@@ -121,6 +594,51 @@ enum Commands {
     },
 
     // FILE WATCHING SYSTEM
+    /// Watch a path and print added/removed/modified symbols on every change
+    Watch {
+        /// Path to watch (file or directory)
+        #[arg(value_name = "PATH")]
+        path: PathBuf,
+        
+        /// Quiet period before re-analyzing, in milliseconds
+        #[arg(long, default_value = "200")]
+        debounce_ms: u64,
+        
+        /// Include test files
+        #[arg(long)]
+        include_tests: bool,
+    },
+    
+    /// Serve the Model Context Protocol over stdio (tools for editors and agents)
+    Mcp {
+        /// Project root to index
+        #[arg(value_name = "PATH", default_value = ".")]
+        path: PathBuf,
+        
+        /// Include test files in the index
+        #[arg(long)]
+        include_tests: bool,
+        
+        /// Quiet period before re-indexing changed files, in milliseconds
+        #[arg(long, default_value = "200")]
+        debounce_ms: u64,
+    },
+
+    /// Serve a Language Server Protocol subset over stdio (document/workspace symbols, references)
+    Lsp {
+        /// Project root to index when the client sends none
+        #[arg(value_name = "PATH", default_value = ".")]
+        path: PathBuf,
+        
+        /// Include test files in the index
+        #[arg(long)]
+        include_tests: bool,
+        
+        /// Quiet period before re-indexing changed files, in milliseconds
+        #[arg(long, default_value = "200")]
+        debounce_ms: u64,
+    },
+
     /// Start file watching for a session
     WatchStart {
         /// Session ID to watch
@@ -322,10 +840,30 @@ enum Commands {
         operation: ConfigOperation,
     },
     
+    /// Analysis cache management
+    Cache {
+        #[command(subcommand)]
+        operation: CacheOperation,
+    },
+    
     /// List supported languages
     Languages,
 }
 
+#[derive(Subcommand)]
+enum CacheOperation {
+    /// Remove all cached analysis results
+    Clear {
+        /// Path whose cache to clear, as given to `analyze`
+        #[arg(default_value = ".")]
+        path: PathBuf,
+        
+        /// Cache directory (default: .nekocode-cache next to PATH)
+        #[arg(long, value_name = "DIR")]
+        cache_dir: Option<PathBuf>,
+    },
+}
+
 #[derive(Subcommand)]
 enum MemoryOperation {
     /// Save content to memory
@@ -401,6 +939,20 @@ fn extract_summary(result: &DirectoryAnalysis) -> String {
     // ファイル統計
     let total_files = result.files.len();
     summary.push(format!("📄 総ファイル数: {}", total_files));
+    if !result.errors.is_empty() {
+        summary.push(format!("⚠️ 解析エラー: {} files", result.errors.len()));
+    }
+    if !result.warnings.is_empty() {
+        summary.push(format!("⏭️ スキップ (サイズ上限): {} files", result.warnings.len()));
+    }
+    let generated = result.files.iter().filter(|f| f.file_info.generated).count();
+    if generated > 0 {
+        summary.push(format!("🧬 生成コード: {} files", generated));
+    }
+    let partial = result.files.iter().filter(|f| f.partial).count();
+    if partial > 0 {
+        summary.push(format!("🩹 構文エラーあり (部分解析): {} files", partial));
+    }
     
     // 言語別統計と総計
     let mut lang_counts = std::collections::HashMap::new();
@@ -441,11 +993,216 @@ fn extract_summary(result: &DirectoryAnalysis) -> String {
     summary.join("\n")
 }
 
-fn main() -> Result<()> {
-    // Parse CLI to get thread count first
-    let cli: Cli = clap::Parser::parse();
-    let threads = match &cli.command {
-        Commands::Analyze { threads, .. } => *threads,
+/// `analyze --format ndjson`: one `AnalysisResult` per line, then a `"type": "summary"` line
+async fn write_ndjson(session: &mut AnalysisSession, path: &Path, include_tests: bool, gate: ComplexityGate, redaction: Redaction) -> Result<Vec<GateViolation>> {
+    use std::io::Write;
+    
+    // The sink runs on this task only, so lines from parallel workers never interleave
+    let stdout = std::io::stdout();
+    let mut out = std::io::BufWriter::new(stdout.lock());
+    let root = if path.is_dir() { path.to_path_buf() } else { path.parent().unwrap_or(path).to_path_buf() };
+    let mut violations = Vec::new();
+    let mut measured = Vec::new();
+    let streamed = session.analyze_path_streaming(path, include_tests, |mut result| {
+        redaction.apply_to_file(&mut result);
+        violations.extend(gate.check(&result, &root));
+        if let Some(diag) = &result.diagnostics {
+            measured.push((result.file_info.path.clone(), diag.clone()));
+        }
+        serde_json::to_writer(&mut out, &result)?;
+        out.write_all(b"\n")?;
+        out.flush()?;
+        Ok(())
+    }).await?;
+    
+    let summary = serde_json::json!({
+        "type": "summary",
+        "directory_path": streamed.directory_path,
+        "summary": streamed.summary,
+        "errors": streamed.errors,
+        "warnings": streamed.warnings,
+    });
+    serde_json::to_writer(&mut out, &summary)?;
+    out.write_all(b"\n")?;
+    out.flush()?;
+    
+    if !measured.is_empty() {
+        let mut entries: Vec<(&Path, &ParseDiagnostics)> = measured.iter().map(|(p, d)| (p.as_path(), d)).collect();
+        eprint!("{}", slowest_entries_to_text(&mut entries, &root));
+    }
+    Ok(violations)
+}
+
+/// `analyze --stdin --lang <LANG>` / `analyze -`: one `AnalysisResult` for the piped source
+async fn analyze_stdin(lang: Option<&str>, format: &str, visibility: Visibility, query: Option<SymbolQuery>, markers: Option<Vec<String>>, max_params: Option<usize>, max_function_loc: Option<u32>, gate: ComplexityGate, baseline: &BaselineMode, ranking: Ranking, redaction: Redaction) -> Result<()> {
+    use std::io::Read;
+    
+    // Without a filename there is nothing to detect the language from
+    let lang = lang.ok_or_else(|| anyhow::anyhow!("--lang is required when reading source from stdin"))?;
+    let language = Language::from_name(lang)
+        .ok_or_else(|| anyhow::anyhow!("Unsupported language: {}. Run `languages` for the list", lang))?;
+    
+    // Read verbatim so reported lines and columns match the editor buffer
+    let mut content = String::new();
+    std::io::stdin().read_to_string(&mut content)
+        .map_err(|e| anyhow::anyhow!("Failed to read source from stdin: {}", e))?;
+    
+    let mut config = AnalysisConfig::default();
+    config.visibility = visibility;
+    config.query = query;
+    if let Some(markers) = markers {
+        config.markers = marker_tags(markers);
+    }
+    if let Some(max_params) = max_params {
+        config.smells.max_params = max_params;
+    }
+    if max_function_loc.is_some() {
+        config.smells.max_function_loc = max_function_loc;
+    }
+    let session = AnalysisSession::with_config(config);
+    let mut result = session.analyze_source(&content, Path::new(STDIN_FILE_NAME), language).await?;
+    redaction.apply_to_file(&mut result);
+    let violations = gate.check(&result, Path::new(""));
+    let summary_line = (format == "summary").then(|| {
+        let mut analysis = DirectoryAnalysis::new(PathBuf::new());
+        analysis.files.push(result.clone());
+        ProjectStats::build(&analysis).to_summary_line(session.functions_over_threshold(&result))
+    });
+    if ranking.is_active() {
+        ranking.apply_to_file(&mut result);
+    }
+    
+    match format {
+        "json" => {
+            println!("{}", serde_json::to_string_pretty(&result)?);
+        }
+        "ndjson" => {
+            println!("{}", serde_json::to_string(&result)?);
+        }
+        "csv" => {
+            print!("{}", functions_to_csv(std::slice::from_ref(&result), Path::new("")));
+        }
+        "summary" => {
+            println!("{}", summary_line.unwrap_or_default());
+        }
+        _ => {
+            anyhow::bail!("Unsupported output format: {}", format);
+        }
+    }
+    enforce_gate(violations, baseline)
+}
+
+/// `--fail-on-complexity` / `--fail-on-cognitive`: list the offending functions on stderr and fail
+/// (`--baseline`: only those not in the baseline; `--write-baseline`: record them instead)
+fn enforce_gate(violations: Vec<GateViolation>, baseline: &BaselineMode) -> Result<()> {
+    let violations = match baseline {
+        BaselineMode::Off => violations,
+        BaselineMode::Suppress(baseline) => {
+            let (violations, suppressed) = baseline.filter(violations);
+            if suppressed > 0 {
+                eprintln!("📌 {} known finding(s) suppressed by the baseline", suppressed);
+            }
+            violations
+        }
+        BaselineMode::Write(path) => {
+            let baseline = Baseline::new(violations);
+            baseline.save(path)?;
+            eprintln!("📌 Baseline written: {} finding(s) in {}", baseline.findings.len(), path.display());
+            return Ok(());
+        }
+    };
+    if violations.is_empty() {
+        return Ok(());
+    }
+    
+    eprint!("{}", violations_to_text(&violations));
+    anyhow::bail!("Complexity gate failed: {} function(s) over the limit", violations.len())
+}
+
+/// `--baseline` / `--write-baseline`, read up front so a bad file fails before the analysis
+fn parse_baseline(gate: &ComplexityGate, baseline: Option<PathBuf>, write_baseline: Option<PathBuf>) -> Result<BaselineMode> {
+    if (baseline.is_some() || write_baseline.is_some()) && !gate.is_enabled() {
+        anyhow::bail!("--baseline / --write-baseline need --fail-on-complexity, --fail-on-cognitive or --fail-on-long-functions");
+    }
+    Ok(match (baseline, write_baseline) {
+        (Some(path), _) => BaselineMode::Suppress(Baseline::load(&path)?),
+        (None, Some(path)) => BaselineMode::Write(path),
+        (None, None) => BaselineMode::Off,
+    })
+}
+
+/// `--files-from` paths: one per line, blank lines skipped; `-` reads stdin
+fn read_file_list(source: &Path) -> Result<Vec<PathBuf>> {
+    let text = if source == Path::new("-") {
+        std::io::read_to_string(std::io::stdin())
+            .map_err(|e| anyhow::anyhow!("Failed to read the file list from stdin: {}", e))?
+    } else {
+        std::fs::read_to_string(source)
+            .map_err(|e| anyhow::anyhow!("Failed to read the file list {}: {}", source.display(), e))?
+    };
+    Ok(text.lines()
+        .map(str::trim)
+        .filter(|line| !line.is_empty())
+        .map(PathBuf::from)
+        .collect())
+}
+
+/// `--markers` tags, upper-cased like the markers they match
+fn marker_tags(markers: Vec<String>) -> Vec<String> {
+    markers.iter()
+        .map(|tag| tag.trim().to_uppercase())
+        .filter(|tag| !tag.is_empty())
+        .collect()
+}
+
+/// `--visibility` value
+fn parse_visibility(value: &str) -> Result<Visibility> {
+    Visibility::parse(value)
+        .ok_or_else(|| anyhow::anyhow!("Invalid visibility: {}. Use 'public', 'private', or 'all'", value))
+}
+
+/// `--lang` value for paths: comma-separated language names
+fn parse_languages(value: &str) -> Result<Vec<Language>> {
+    value.split(',')
+        .filter(|name| !name.trim().is_empty())
+        .map(|name| {
+            Language::from_name(name)
+                .ok_or_else(|| anyhow::anyhow!("Unsupported language: {}. Run `languages` for the list", name.trim()))
+        })
+        .collect()
+}
+
+/// `--lang-map` values
+fn parse_lang_map(values: &[String]) -> Result<Vec<LanguageOverride>> {
+    values.iter()
+        .map(|value| {
+            LanguageOverride::parse(value)
+                .ok_or_else(|| anyhow::anyhow!("Invalid --lang-map: {}. Use GLOB=LANG, e.g. '*.tmpl=go'", value))
+        })
+        .collect()
+}
+
+fn parse_ranking(sort_by: Option<&str>, top: Option<usize>) -> Result<Ranking> {
+    let sort_by = sort_by.map(|value| {
+        SortKey::parse(value)
+            .ok_or_else(|| anyhow::anyhow!("Invalid sort key: {}. Use 'complexity', 'loc', 'fan_in', or 'name'", value))
+    }).transpose()?;
+    if top == Some(0) {
+        anyhow::bail!("--top must be at least 1");
+    }
+    Ok(Ranking { sort_by, top })
+}
+
+fn parse_progress(value: &str) -> Result<ProgressMode> {
+    ProgressMode::parse(value)
+        .ok_or_else(|| anyhow::anyhow!("Invalid progress mode: {}. Use 'always', 'never', or 'auto'", value))
+}
+
+fn main() -> Result<()> {
+    // Parse CLI to get thread count first
+    let cli: Cli = clap::Parser::parse();
+    let threads = match &cli.command {
+        Commands::Analyze { threads, .. } => *threads,
         _ => 16, // Default for other commands
     };
     
@@ -464,22 +1221,159 @@ async fn async_main() -> Result<()> {
     let cli = Cli::parse();
     
     match cli.command {
-        Commands::Analyze { path, format, verbose, include_tests, stats_only, threads } => {
-            let mut config = AnalysisConfig::default();
+        Commands::Analyze { paths, files_from, stdin, lang, lang_map, format, verbose, include_tests, stats_only, threads, jobs, cache, no_cache, cache_dir, no_ignore, follow_symlinks, build_tags, visibility, markers, query, max_params, max_function_loc, since, rev, with_churn, churn_since, fail_on_complexity, fail_on_cognitive, fail_on_long_functions, baseline, write_baseline, progress, max_file_size, max_file_bytes_parse, exclude_generated, strict_utf8, diagnostics, sort_by, top, redact, redact_names } => {
+            let visibility = parse_visibility(&visibility)?;
+            let ranking = parse_ranking(sort_by.as_deref(), top)?;
+            let query = query.as_deref().map(SymbolQuery::parse).transpose()?;
+            let progress = parse_progress(&progress)?;
+            let gate = ComplexityGate {
+                max_cyclomatic: fail_on_complexity,
+                max_cognitive: fail_on_cognitive,
+                max_function_loc: max_function_loc.filter(|_| fail_on_long_functions),
+            };
+            let baseline = parse_baseline(&gate, baseline, write_baseline)?;
+            let redaction = Redaction { source: redact, names: redact_names };
+            if stdin || (paths.len() == 1 && paths[0] == Path::new("-")) {
+                return analyze_stdin(lang.as_deref(), &format, visibility, query, markers, max_params, max_function_loc, gate, &baseline, ranking, redaction).await;
+            }
+            if paths.iter().any(|path| path == Path::new("-")) {
+                anyhow::bail!("`-` (stdin) cannot be combined with other paths");
+            }
+            let file_list = files_from.as_deref().map(read_file_list).transpose()?;
+            // A file list takes nekocode.toml from the working directory
+            let path = paths.first().cloned().unwrap_or_else(|| PathBuf::from("."));
+            
+            // Built-in defaults < nekocode.toml < command-line flags
+            let mut config = load_analysis_config(&path)?;
             config.verbose_output = verbose;
-            config.include_test_files = include_tests;
+            if let Some(jobs) = jobs {
+                config.max_threads = jobs;
+            }
+            config.include_test_files |= include_tests;
+            if cache {
+                config.cache_enabled = true;
+            }
+            if no_cache {
+                config.cache_enabled = false;
+            }
+            if no_ignore {
+                config.respect_ignore_files = false;
+            }
+            if follow_symlinks {
+                config.follow_symlinks = true;
+            }
+            if exclude_generated {
+                config.exclude_generated = true;
+            }
+            config.strict_utf8 = strict_utf8;
+            if let Some(lang) = &lang {
+                config.enabled_languages = parse_languages(lang)?;
+            }
+            if !lang_map.is_empty() {
+                config.language_overrides = parse_lang_map(&lang_map)?;
+                // Reject bad globs before the walk
+                LanguageMap::new(&config)?;
+            }
+            config.diagnostics = diagnostics;
+            if build_tags.is_some() {
+                config.build_tags = build_tags;
+            }
+            if cache_dir.is_some() {
+                config.cache_dir = cache_dir;
+            }
+            if let Some(limit) = max_file_size {
+                config.max_file_size = (limit > 0).then_some(limit);
+            }
+            if let Some(limit) = max_file_bytes_parse {
+                config.max_parse_bytes = (limit > 0).then_some(limit);
+            }
+            config.visibility = visibility;
+            config.progress = progress;
+            if query.is_some() {
+                config.query = query;
+            }
+            if let Some(markers) = markers {
+                config.markers = marker_tags(markers);
+            }
+            if let Some(max_params) = max_params {
+                config.smells.max_params = max_params;
+            }
+            if max_function_loc.is_some() {
+                config.smells.max_function_loc = max_function_loc;
+            }
+            if let Some(git_ref) = since {
+                let mut files = Vec::new();
+                for path in &paths {
+                    let changed = ChangedFiles::since(path, &git_ref)?;
+                    files.extend(changed.existing_files()
+                        .iter()
+                        .filter_map(|file| std::fs::canonicalize(file).ok()));
+                }
+                files.sort();
+                files.dedup();
+                if verbose {
+                    eprintln!("🔀 {} files changed since {}", files.len(), git_ref);
+                }
+                config.only_files = Some(files);
+            }
+            let include_tests = config.include_test_files;
             
             // Create session for Tree-sitter analysis
             let mut session = AnalysisSession::with_config(config);
             
+            // Stream results as they complete; memory stays flat on large trees
+            if format == "ndjson" {
+                if rev.is_some() {
+                    anyhow::bail!("--rev is not streamed; use --format json or csv");
+                }
+                if paths.len() > 1 || file_list.is_some() {
+                    anyhow::bail!("Several paths are not streamed; use --format json or csv");
+                }
+                if with_churn {
+                    anyhow::bail!("--with-churn is not streamed; use --format json or csv");
+                }
+                if ranking.is_active() {
+                    anyhow::bail!("--sort-by / --top need every file before output; use --format json or csv");
+                }
+                let violations = write_ndjson(&mut session, &path, include_tests, gate, redaction).await?;
+                return enforce_gate(violations, &baseline);
+            }
+            
             if verbose {
                 println!("🦀 NekoCode Rust Analysis Starting...");
-                println!("📂 Target: {}", path.display());
+                for path in &paths {
+                    println!("📂 Target: {}", path.display());
+                }
+                if let Some(files) = &file_list {
+                    println!("📂 Target: {} listed files", files.len());
+                }
                 println!("⚡ Parser: TREE-SITTER 🚀");
                 println!("🧵 Worker Threads: {}", threads);
             }
             
-            let result = session.analyze_path(&path, include_tests).await?;
+            let mut result = match (&rev, &file_list) {
+                (Some(_), _) if paths.len() > 1 => anyhow::bail!("--rev takes a single path"),
+                (Some(rev), _) => session.analyze_revision(&path, rev, include_tests).await?,
+                (None, Some(files)) => session.analyze_file_list(files).await?,
+                (None, None) => session.analyze_paths(&paths, include_tests).await?,
+            };
+            if with_churn {
+                annotate_churn(&mut result, churn_since.as_deref())?;
+            }
+            // Before the gate, so findings and baselines carry no more than the output
+            redaction.apply(&mut result);
+            
+            // The gate and diagnostics look at every file, not just the ranked ones
+            let violations: Vec<GateViolation> = result.files.iter()
+                .flat_map(|file| gate.check(file, &result.directory_path))
+                .collect();
+            let slowest = diagnostics.then(|| slowest_to_text(&result.files, &result.directory_path));
+            // Totals and the maximum cover every file, whatever --top keeps
+            let summary_line = (format == "summary")
+                .then(|| ProjectStats::build(&result).to_summary_line(result.summary.functions_over_threshold));
+            if ranking.is_active() {
+                ranking.apply(&mut result);
+            }
             
             // Check if stats_only mode is enabled
             if stats_only {
@@ -491,6 +1385,12 @@ async fn async_main() -> Result<()> {
                         let json = serde_json::to_string_pretty(&result)?;
                         println!("{}", json);
                     }
+                    "csv" => {
+                        print!("{}", functions_to_csv(&result.files, &result.directory_path));
+                    }
+                    "summary" => {
+                        println!("{}", summary_line.unwrap_or_default());
+                    }
                     _ => {
                         anyhow::bail!("Unsupported output format: {}", format);
                     }
@@ -500,24 +1400,28 @@ async fn async_main() -> Result<()> {
             if verbose {
                 println!("✅ Analysis completed!");
             }
+            
+            // stderr keeps the JSON / CSV on stdout machine-readable
+            if let Some(slowest) = slowest {
+                eprint!("{}", slowest);
+            }
+            
+            // After the report, so CI keeps the artifact even when the gate fails
+            enforce_gate(violations, &baseline)?;
         }
         
-        Commands::AnalyzeImpact { path, format, verbose, include_tests, compare_ref, skip_circular, risk_threshold } => {
+        Commands::AnalyzeImpact { path, format, verbose, include_tests, compare_ref, skip_circular, risk_threshold, sarif_error_threshold, redact, redact_names } => {
             if verbose {
                 println!("🔍 NekoCode Impact Analysis Starting...");
                 println!("📂 Target: {}", path.display());
                 println!("📊 Format: {}", format);
             }
             
-            // Parse risk threshold
-            let risk_level = match risk_threshold.as_str() {
-                "low" => RiskLevel::Low,
-                "medium" => RiskLevel::Medium,
-                "high" => RiskLevel::High,
-                _ => {
-                    anyhow::bail!("Invalid risk threshold: {}. Use 'low', 'medium', or 'high'", risk_threshold);
-                }
-            };
+            // Parse risk thresholds
+            let risk_level = RiskLevel::parse(&risk_threshold)
+                .ok_or_else(|| anyhow::anyhow!("Invalid risk threshold: {}. Use 'low', 'medium', or 'high'", risk_threshold))?;
+            let sarif_level = RiskLevel::parse(&sarif_error_threshold)
+                .ok_or_else(|| anyhow::anyhow!("Invalid SARIF error threshold: {}. Use 'low', 'medium', or 'high'", sarif_error_threshold))?;
             
             // Create impact configuration
             let config = ImpactConfig {
@@ -530,7 +1434,8 @@ async fn async_main() -> Result<()> {
             
             // Create analyzer and run analysis
             let analyzer = ImpactAnalyzer::new(config);
-            let result = analyzer.analyze_impact(&path).await?;
+            let mut result = analyzer.analyze_impact(&path).await?;
+            Redaction { source: redact, names: redact_names }.apply_to_impact(&mut result);
             
             // Format and output results
             match format.as_str() {
@@ -544,8 +1449,11 @@ async fn async_main() -> Result<()> {
                 "github-comment" => {
                     println!("{}", OutputFormatter::format_github_comment(&result));
                 }
+                "sarif" => {
+                    println!("{}", OutputFormatter::format_sarif(&result, sarif_level)?);
+                }
                 _ => {
-                    anyhow::bail!("Unsupported output format: {}. Use 'plain', 'json', or 'github-comment'", format);
+                    anyhow::bail!("Unsupported output format: {}. Use 'plain', 'json', 'github-comment', or 'sarif'", format);
                 }
             }
             
@@ -554,7 +1462,322 @@ async fn async_main() -> Result<()> {
             }
         }
         
+        Commands::Callgraph { path, format, top, root, depth, include_tests } => {
+            let mut session = AnalysisSession::with_config(load_analysis_config(&path)?);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let mut graph = CallGraph::build(&analysis);
+            if let Some(root) = root {
+                graph = graph.subgraph(&root, depth)?;
+            }
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&graph)?);
+                }
+                "dot" => {
+                    print!("{}", graph.to_dot());
+                }
+                "text" => {
+                    print!("{}", coupling_to_text(&graph, top));
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json', 'dot' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::Hierarchy { path, format, include_tests } => {
+            let mut session = AnalysisSession::with_config(load_analysis_config(&path)?);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let hierarchy = TypeHierarchy::build(&analysis);
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&hierarchy)?);
+                }
+                "dot" => {
+                    print!("{}", hierarchy.to_dot());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'dot'", format);
+                }
+            }
+        }
+        
+        Commands::Cycles { path, format, include_tests } => {
+            let mut session = AnalysisSession::with_config(load_analysis_config(&path)?);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let cycles = CallGraph::build(&analysis).cycles();
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&cycles)?);
+                }
+                "text" => {
+                    print!("{}", cycles_to_text(&cycles));
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::ImportCycles { path, format, include_tests } => {
+            let mut session = AnalysisSession::with_config(load_analysis_config(&path)?);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let cycles = ImportGraph::build(&analysis).cycles();
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&cycles)?);
+                }
+                "text" => {
+                    print!("{}", import_cycles_to_text(&cycles));
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::Report { path, format, output, threshold, include_tests, redact, redact_names } => {
+            let config = load_analysis_config(&path)?;
+            let threshold = threshold.or(config.max_cyclomatic).unwrap_or(DEFAULT_REPORT_THRESHOLD);
+            let mut session = AnalysisSession::with_config(config);
+            let mut analysis = session.analyze_path(&path, include_tests).await?;
+            Redaction { source: redact, names: redact_names }.apply(&mut analysis);
+            
+            let report = match format.as_str() {
+                "html" => render_html(&analysis, threshold)?,
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'html'", format);
+                }
+            };
+            
+            match output {
+                Some(output) => {
+                    std::fs::write(&output, report)
+                        .map_err(|e| anyhow::anyhow!("Failed to write report {}: {}", output.display(), e))?;
+                    eprintln!("📄 Report written to {}", output.display());
+                }
+                None => print!("{}", report),
+            }
+        }
+        
+        Commands::Schema { root } => {
+            let root = SchemaRoot::parse(&root)
+                .ok_or_else(|| anyhow::anyhow!("Unknown schema root: {}. Use 'file' or 'directory'", root))?;
+            println!("{}", serde_json::to_string_pretty(&output_schema(root))?);
+        }
+        
+        Commands::Deadcode { path, format, visibility } => {
+            let visibility = parse_visibility(&visibility)?;
+            
+            // Test files are analyzed so test-only references can be reported separately
+            let mut session = AnalysisSession::with_config(load_analysis_config(&path)?);
+            let analysis = session.analyze_path(&path, true).await?;
+            
+            let report = DeadCodeReport::build(&analysis, visibility);
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&report)?);
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json'", format);
+                }
+            }
+        }
+        
+        Commands::FindReferences { path, symbol, definition, format } => {
+            // References from tests count like any other
+            let mut session = AnalysisSession::with_config(load_analysis_config(&path)?);
+            let analysis = session.analyze_path(&path, true).await?;
+            
+            let report = ReferenceReport::build(&analysis, &symbol, definition.as_deref())?;
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&report)?);
+                }
+                "text" => {
+                    println!("{}", report.to_text());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::Diff { old, new, format } => {
+            let old_files = SnapshotDiff::load_snapshot(&old)?;
+            let new_files = SnapshotDiff::load_snapshot(&new)?;
+            let diff = SnapshotDiff::compare(&old_files, &new_files);
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&diff)?);
+                }
+                "text" => {
+                    println!("{}", diff.to_text());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::Duplicates { path, min_similarity, min_tokens, include_tests, format, sort_by, top } => {
+            let ranking = parse_ranking(sort_by.as_deref(), top)?;
+            if !(min_similarity > 0.0 && min_similarity <= 1.0) {
+                anyhow::bail!("--min-similarity must be in (0.0, 1.0], got {}", min_similarity);
+            }
+            
+            // Normalized tokens are not cached, so every file is parsed afresh
+            let mut config = load_analysis_config(&path)?;
+            config.cache_enabled = false;
+            let include_tests = include_tests || config.include_test_files;
+            let mut session = AnalysisSession::with_config(config);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let mut report = DuplicateReport::build(&analysis, min_similarity, min_tokens);
+            report.rank(&ranking);
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&report)?);
+                }
+                "text" => {
+                    println!("{}", report.to_text());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::Stats { path, include_tests, format, visibility, sort_by, top } => {
+            let ranking = parse_ranking(sort_by.as_deref(), top)?;
+            let mut config = load_analysis_config(&path)?;
+            config.visibility = parse_visibility(&visibility)?;
+            let include_tests = include_tests || config.include_test_files;
+            let mut session = AnalysisSession::with_config(config);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let stats = ProjectStats::build_ranked(&analysis, &ranking);
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&stats)?);
+                }
+                "text" => {
+                    println!("{}", stats.to_text());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::ComplexityHeatmap { path, include_tests, format } => {
+            let config = load_analysis_config(&path)?;
+            let include_tests = include_tests || config.include_test_files;
+            let mut session = AnalysisSession::with_config(config);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let heatmap = ComplexityHeatmap::build(&analysis);
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&heatmap)?);
+                }
+                "text" => {
+                    println!("{}", heatmap.to_text());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'text' or 'json'", format);
+                }
+            }
+        }
+        
+        Commands::Markers { path, markers, include_tests, format } => {
+            let mut config = load_analysis_config(&path)?;
+            if let Some(markers) = markers {
+                config.markers = marker_tags(markers);
+            }
+            let include_tests = include_tests || config.include_test_files;
+            let mut session = AnalysisSession::with_config(config);
+            let analysis = session.analyze_path(&path, include_tests).await?;
+            
+            let report = MarkerReport::build(&analysis);
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&report)?);
+                }
+                "text" => {
+                    println!("{}", report.to_text());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'json' or 'text'", format);
+                }
+            }
+        }
+        
+        Commands::Validate { path, format, jobs, no_ignore, since } => {
+            let mut config = load_analysis_config(&path)?;
+            config.include_test_files = true;
+            if let Some(jobs) = jobs {
+                config.max_threads = jobs;
+            }
+            if no_ignore {
+                config.respect_ignore_files = false;
+            }
+            if let Some(git_ref) = since {
+                let changed = ChangedFiles::since(&path, &git_ref)?;
+                config.only_files = Some(changed.existing_files()
+                    .iter()
+                    .filter_map(|file| std::fs::canonicalize(file).ok())
+                    .collect());
+            }
+            let session = AnalysisSession::with_config(config);
+            
+            let (root, files) = if path.is_file() {
+                (path.parent().unwrap_or_else(|| Path::new(".")).to_path_buf(), vec![path.clone()])
+            } else if path.is_dir() {
+                (path.clone(), session.discover_files(&path)?)
+            } else {
+                anyhow::bail!("Path does not exist or is not accessible: {}", path.display());
+            };
+            let report = ValidationReport::check(&files, &root, session.worker_count());
+            
+            match format.as_str() {
+                "json" => {
+                    println!("{}", serde_json::to_string_pretty(&report)?);
+                }
+                "text" => {
+                    println!("{}", report.to_text());
+                }
+                _ => {
+                    anyhow::bail!("Unsupported output format: {}. Use 'text' or 'json'", format);
+                }
+            }
+            
+            if !report.is_clean() {
+                anyhow::bail!("Validation failed: {} file(s) with parse errors", report.files.len());
+            }
+        }
+        
         // SESSION MODE
+        Commands::Session { path, include_tests } => {
+            use crate::commands::repl::handle_session;
+            handle_session(&path, include_tests).await?;
+        }
+        
         Commands::SessionCreate { path } => {
             let mut session_manager = SessionManager::new()?;
             let session_id = session_manager.create_session(&path).await?;
@@ -568,12 +1791,27 @@ async fn async_main() -> Result<()> {
         }
         
         Commands::SessionUpdate { session_id, verbose, dry_run } => {
-            use nekocode_rust::commands::session_update::handle_session_update;
+            use crate::commands::session_update::handle_session_update;
             let result = handle_session_update(&session_id, verbose, dry_run).await?;
             println!("{}", result);
         }
 
         // FILE WATCHING SYSTEM
+        Commands::Watch { path, debounce_ms, include_tests } => {
+            use crate::commands::watch::handle_watch_path;
+            handle_watch_path(&path, debounce_ms, include_tests).await?;
+        }
+
+        Commands::Mcp { path, include_tests, debounce_ms } => {
+            use crate::commands::mcp::handle_mcp;
+            handle_mcp(&path, include_tests, debounce_ms).await?;
+        }
+
+        Commands::Lsp { path, include_tests, debounce_ms } => {
+            use crate::commands::lsp::handle_lsp;
+            handle_lsp(&path, include_tests, debounce_ms).await?;
+        }
+
         Commands::WatchStart { session_id } => {
             use crate::commands::watch::handle_watch_start;
             let result = handle_watch_start(&session_id)?;
@@ -769,16 +2007,35 @@ async fn async_main() -> Result<()> {
             }
         }
         
+        Commands::Cache { operation } => {
+            match operation {
+                CacheOperation::Clear { path, cache_dir } => {
+                    let cache_dir = cache_dir.unwrap_or_else(|| default_cache_dir(&path));
+                    let removed = AnalysisCache::new(cache_dir.clone()).clear()?;
+                    println!("🧹 Cleared {} cached results from {}", removed, cache_dir.display());
+                }
+            }
+        }
+        
         Commands::Languages => {
             println!("Supported Languages:");
             println!("  🟨 JavaScript (.js, .mjs, .jsx, .cjs)");
             println!("  🔷 TypeScript (.ts, .tsx)");
             println!("  🔵 C++ (.cpp, .cxx, .cc, .hpp, .hxx, .hh)");
             println!("  🔵 C (.c, .h)");
-            println!("  🐍 Python (.py, .pyw, .pyi)");
+            println!("  🐍 Python (.py, .pyw, .pyi, .ipynb)");
             println!("  🟦 C# (.cs)");
             println!("  🐹 Go (.go)");
             println!("  🦀 Rust (.rs)");
+            println!("  💎 Ruby (.rb, #!/usr/bin/env ruby)");
+            println!("  ☕ Java (.java)");
+            println!("  🟣 Kotlin (.kt, .kts)");
+            println!("  🐦 Swift (.swift)");
+            println!("  🐘 PHP (.php, .phtml)");
+            println!("  🌙 Lua (.lua)");
+            println!("  🎯 Dart (.dart)");
+            println!("  🔺 Scala (.scala, .sc)");
+            println!("  ⚡ Zig (.zig)");
         }
     }
     
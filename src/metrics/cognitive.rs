@@ -0,0 +1,367 @@
+//! 🧠 Cognitive complexity (SonarSource style)
+//!
+//! - +1 per control structure, plus the current nesting depth
+//! - +1 (flat) for `else if` / `elif` / `else` continuations
+//! - +1 per sequence of like logical operators (`a && b && c` = 1, `a && b || c` = 2)
+//! - closures / lambdas / nested functions increase nesting without scoring
+
+use tree_sitter::Node;
+
+/// Node kinds that drive the cognitive complexity walk for one grammar
+///
+/// The same tables define nesting for [`max_nesting_depth`](super::nesting::max_nesting_depth).
+pub struct CognitiveRules {
+    /// `if` nodes, including chained forms (`elif_clause`, `elsif`)
+    pub if_kinds: &'static [&'static str],
+    /// Wrappers around an `else` branch (`else_clause`)
+    pub else_kinds: &'static [&'static str],
+    /// Wrappers around either branch body (Kotlin `control_structure_body`); the
+    /// one following the `else` keyword is the alternative
+    pub branch_kinds: &'static [&'static str],
+    /// Loops, switches, catches, ternaries: +1 + nesting, and nest their children
+    pub nesting_structures: &'static [&'static str],
+    /// Jumps that score +1 without nesting (`goto`)
+    pub flat_structures: &'static [&'static str],
+    /// Closures / lambdas / nested functions: nest their children only
+    pub nesting_only: &'static [&'static str],
+    /// Binary nodes that may carry logical operators
+    pub logical_kinds: &'static [&'static str],
+    pub logical_operators: &'static [&'static str],
+}
+
+pub const GO: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement"],
+    else_kinds: &[],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "expression_switch_statement", "type_switch_statement", "select_statement",
+    ],
+    flat_structures: &["goto_statement"],
+    nesting_only: &["func_literal"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["&&", "||"],
+};
+
+pub const JAVASCRIPT: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement"],
+    else_kinds: &["else_clause"],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "for_in_statement", "while_statement", "do_statement",
+        "switch_statement", "catch_clause", "ternary_expression",
+    ],
+    flat_structures: &[],
+    nesting_only: &["arrow_function", "function_expression", "function_declaration", "generator_function"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["&&", "||", "??"],
+};
+
+pub const PYTHON: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement", "elif_clause"],
+    else_kinds: &["else_clause"],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "while_statement", "except_clause", "match_statement", "conditional_expression",
+    ],
+    flat_structures: &[],
+    nesting_only: &["lambda", "function_definition"],
+    logical_kinds: &["boolean_operator"],
+    logical_operators: &["and", "or"],
+};
+
+pub const RUST: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_expression"],
+    else_kinds: &["else_clause"],
+    branch_kinds: &[],
+    nesting_structures: &["for_expression", "while_expression", "loop_expression", "match_expression"],
+    flat_structures: &[],
+    nesting_only: &["closure_expression", "function_item"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["&&", "||"],
+};
+
+pub const CPP: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement"],
+    else_kinds: &["else_clause"],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "for_range_loop", "while_statement", "do_statement",
+        "switch_statement", "catch_clause", "conditional_expression",
+    ],
+    flat_structures: &["goto_statement"],
+    nesting_only: &["lambda_expression"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["&&", "||", "and", "or"],
+};
+
+pub const CSHARP: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement"],
+    else_kinds: &[],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "foreach_statement", "while_statement", "do_statement",
+        "switch_statement", "switch_expression", "catch_clause", "conditional_expression",
+    ],
+    flat_structures: &["goto_statement"],
+    nesting_only: &["lambda_expression", "anonymous_method_expression", "local_function_statement"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["&&", "||", "??"],
+};
+
+pub const JAVA: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement"],
+    else_kinds: &[],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "enhanced_for_statement", "while_statement", "do_statement",
+        "switch_expression", "catch_clause", "ternary_expression",
+    ],
+    flat_structures: &[],
+    nesting_only: &["lambda_expression", "class_body"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["&&", "||"],
+};
+
+pub const KOTLIN: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_expression"],
+    else_kinds: &[],
+    branch_kinds: &["control_structure_body"],
+    nesting_structures: &[
+        "for_statement", "while_statement", "do_while_statement", "when_expression", "catch_block",
+    ],
+    flat_structures: &[],
+    nesting_only: &["lambda_literal", "anonymous_function", "function_declaration", "object_literal"],
+    logical_kinds: &["conjunction_expression", "disjunction_expression"],
+    logical_operators: &["&&", "||"],
+};
+
+pub const SWIFT: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement"],
+    else_kinds: &[],
+    // `else { ... }` is a bare `statements` block, `else if` a nested if_statement
+    branch_kinds: &["statements", "if_statement"],
+    nesting_structures: &[
+        "for_statement", "while_statement", "repeat_while_statement", "switch_statement",
+        "guard_statement", "catch_block", "ternary_expression",
+    ],
+    flat_structures: &[],
+    nesting_only: &["lambda_literal", "function_declaration"],
+    logical_kinds: &["conjunction_expression", "disjunction_expression"],
+    logical_operators: &["&&", "||"],
+};
+
+pub const PHP: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement", "else_if_clause"],
+    else_kinds: &["else_clause"],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "foreach_statement", "while_statement", "do_statement",
+        "switch_statement", "match_expression", "catch_clause", "conditional_expression",
+    ],
+    flat_structures: &["goto_statement"],
+    nesting_only: &["anonymous_function", "arrow_function"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["&&", "||", "and", "or", "??"],
+};
+
+pub const RUBY: CognitiveRules = CognitiveRules {
+    if_kinds: &["if", "unless", "elsif"],
+    else_kinds: &["else"],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "while", "until", "for", "case", "rescue", "conditional",
+        "if_modifier", "unless_modifier", "while_modifier", "until_modifier",
+    ],
+    flat_structures: &[],
+    nesting_only: &["block", "do_block", "lambda"],
+    logical_kinds: &["binary"],
+    logical_operators: &["&&", "||", "and", "or"],
+};
+
+pub const LUA: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement", "elseif_statement"],
+    else_kinds: &["else_statement"],
+    branch_kinds: &[],
+    nesting_structures: &["for_statement", "while_statement", "repeat_statement"],
+    flat_structures: &["goto_statement"],
+    nesting_only: &["function_definition", "function_declaration"],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["and", "or"],
+};
+
+pub const DART: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement"],
+    else_kinds: &[],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_statement", "while_statement", "do_statement", "switch_statement",
+        "switch_expression", "catch_clause", "conditional_expression",
+    ],
+    flat_structures: &[],
+    nesting_only: &["function_expression", "local_function_declaration"],
+    logical_kinds: &["logical_and_expression", "logical_or_expression", "if_null_expression"],
+    logical_operators: &["&&", "||", "??"],
+};
+
+pub const SCALA: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_expression"],
+    else_kinds: &[],
+    branch_kinds: &[],
+    nesting_structures: &[
+        "for_expression", "while_expression", "do_while_expression", "match_expression", "catch_clause",
+    ],
+    flat_structures: &[],
+    nesting_only: &["lambda_expression", "function_definition"],
+    logical_kinds: &["infix_expression"],
+    logical_operators: &["&&", "||"],
+};
+
+pub const ZIG: CognitiveRules = CognitiveRules {
+    if_kinds: &["if_statement", "if_expression"],
+    else_kinds: &[],
+    // `else if` / `else |err|` follow the `else` keyword directly
+    branch_kinds: &["block", "if_statement", "if_expression"],
+    nesting_structures: &[
+        "for_statement", "for_expression", "while_statement", "while_expression", "switch_expression",
+    ],
+    flat_structures: &[],
+    nesting_only: &[],
+    logical_kinds: &["binary_expression"],
+    logical_operators: &["and", "or"],
+};
+
+/// Cognitive complexity of a function node (its body, if the grammar has one)
+pub fn cognitive_complexity(node: Node, source: &str, rules: &CognitiveRules) -> u32 {
+    let body = node.child_by_field_name("body").unwrap_or(node);
+    walk_children(body, source, rules, 0)
+}
+
+/// Helper: Score all named children at the given nesting depth
+fn walk_children(node: Node, source: &str, rules: &CognitiveRules, nesting: u32) -> u32 {
+    let mut cursor = node.walk();
+    let children: Vec<Node> = node.named_children(&mut cursor).collect();
+    children.into_iter()
+        .map(|child| visit(child, source, rules, nesting))
+        .sum()
+}
+
+/// Helper: Score one node
+fn visit(node: Node, source: &str, rules: &CognitiveRules, nesting: u32) -> u32 {
+    let kind = node.kind();
+    
+    if rules.if_kinds.contains(&kind) {
+        return visit_if(node, source, rules, nesting, false);
+    }
+    
+    if rules.nesting_structures.contains(&kind) {
+        return 1 + nesting + walk_children(node, source, rules, nesting + 1);
+    }
+    
+    if rules.flat_structures.contains(&kind) {
+        return 1 + walk_children(node, source, rules, nesting);
+    }
+    
+    if rules.nesting_only.contains(&kind) {
+        return walk_children(node, source, rules, nesting + 1);
+    }
+    
+    if rules.logical_kinds.contains(&kind) {
+        if let Some(operator) = logical_operator(node, source, rules) {
+            // Only the first operator of a like-operator sequence scores
+            let continues_sequence = node.parent()
+                .filter(|parent| rules.logical_kinds.contains(&parent.kind()))
+                .and_then(|parent| logical_operator(parent, source, rules))
+                .map_or(false, |parent_operator| parent_operator == operator);
+            let score = if continues_sequence { 0 } else { 1 };
+            return score + walk_children(node, source, rules, nesting);
+        }
+    }
+    
+    walk_children(node, source, rules, nesting)
+}
+
+/// Helper: Score an `if`; chained `else if` branches score flat at the same nesting
+fn visit_if(node: Node, source: &str, rules: &CognitiveRules, nesting: u32, is_else_if: bool) -> u32 {
+    let mut score = if is_else_if { 1 } else { 1 + nesting };
+    
+    for (child, is_alternative) in if_branches(node, rules) {
+        score += if is_alternative {
+            visit_alternative(child, source, rules, nesting)
+        } else {
+            visit(child, source, rules, nesting + 1)
+        };
+    }
+    
+    score
+}
+
+/// Helper: Score an else / else-if branch
+fn visit_alternative(node: Node, source: &str, rules: &CognitiveRules, nesting: u32) -> u32 {
+    match chained_if(node, rules) {
+        Some(inner) => visit_if(inner, source, rules, nesting, true),
+        // Plain else
+        None => 1 + walk_children(node, source, rules, nesting + 1),
+    }
+}
+
+/// Named children of an `if` node, flagged when they are the else / else-if branch
+pub(crate) fn if_branches<'t>(node: Node<'t>, rules: &CognitiveRules) -> Vec<(Node<'t>, bool)> {
+    let mut cursor = node.walk();
+    let mut branches = Vec::new();
+    let mut after_else = false;
+    if cursor.goto_first_child() {
+        loop {
+            let child = cursor.node();
+            if child.is_named() {
+                let follows_else = after_else && rules.branch_kinds.contains(&child.kind());
+                let is_alternative = cursor.field_name() == Some("alternative")
+                    || follows_else
+                    || rules.else_kinds.contains(&child.kind());
+                branches.push((child, is_alternative));
+            } else if child.kind() == "else" {
+                after_else = true;
+            }
+            if !cursor.goto_next_sibling() {
+                break;
+            }
+        }
+    }
+    branches
+}
+
+/// The `if` an else branch continues with (`else if`), or `None` for a plain else
+pub(crate) fn chained_if<'t>(node: Node<'t>, rules: &CognitiveRules) -> Option<Node<'t>> {
+    if rules.if_kinds.contains(&node.kind()) {
+        return Some(node);
+    }
+    
+    // else_clause wrapping a single if: `else if`
+    if rules.else_kinds.contains(&node.kind()) || rules.branch_kinds.contains(&node.kind()) {
+        let mut cursor = node.walk();
+        let named: Vec<Node> = node.named_children(&mut cursor).collect();
+        if let [inner] = named.as_slice() {
+            if rules.if_kinds.contains(&inner.kind()) {
+                return Some(*inner);
+            }
+        }
+    }
+    
+    None
+}
+
+/// Helper: Logical operator of a binary node, if any
+fn logical_operator<'a>(node: Node, source: &'a str, rules: &CognitiveRules) -> Option<&'a str> {
+    // Grammars without an `operator` field (Kotlin `conjunction_expression`) keep it as the anonymous child
+    let operator = match node.child_by_field_name("operator") {
+        Some(operator) => operator,
+        None => {
+            let mut cursor = node.walk();
+            let operator = node.children(&mut cursor).find(|child| !child.is_named());
+            operator?
+        }
+    };
+    let operator = operator.utf8_text(source.as_bytes()).ok()?;
+    rules.logical_operators.iter()
+        .find(|op| **op == operator)
+        .map(|_| operator)
+}
@@ -0,0 +1,449 @@
+//! 🔀 Cyclomatic complexity: 1 plus the weighted decision points of a body
+//!
+//! Analyzers record how many decision points of each construct a function
+//! has (`decision_points` on its complexity) and set the cyclomatic number at
+//! the standard weight of 1 each. Weights are a per-run setting
+//! (`[metrics.weights]` in `nekocode.toml`), so the session re-weighs the
+//! counts after the cache, like smells; a cached result fits any weighting.
+//!
+//! Node kinds counted as each construct, per grammar:
+//!
+//! | Language | `if` | `for` | `case` | `and` / `or` | `select` | `ternary` | `catch` |
+//! |---|---|---|---|---|---|---|---|
+//! | Go | `if_statement` | `for_statement` | `expression_case`, `type_case` | `&&` / `\|\|` | `communication_case` | | |
+//! | Java | `if_statement` | `for_statement`, `enhanced_for_statement`, `while_statement`, `do_statement` | `switch_label` but `default` | `&&` / `\|\|` | | `ternary_expression` | `catch_clause` |
+//! | Kotlin | `if_expression` | `for_statement`, `while_statement`, `do_while_statement` | `when_entry` but `else ->` | `conjunction_expression` / `disjunction_expression` | | | `catch_block` |
+//! | Swift | `if_statement`, `guard_statement` | `for_statement`, `while_statement`, `repeat_while_statement` | `switch_entry` but `default:` | `conjunction_expression` / `disjunction_expression` | | `ternary_expression` | `catch_block` |
+//! | Ruby | `if`, `unless`, `elsif`, `if_modifier`, `unless_modifier` | `while`, `until`, `for`, `while_modifier`, `until_modifier` | `when` | `&&`, `and` / `\|\|`, `or` | | `conditional` | `rescue` |
+//! | PHP | `if_statement`, `else_if_clause` | `for_statement`, `foreach_statement`, `while_statement`, `do_statement` | `case_statement`, `match_conditional_expression` | `&&`, `and` / `\|\|`, `or`, `??` | | `conditional_expression` | `catch_clause` |
+//! | Lua | `if_statement`, `elseif_statement` | `for_statement`, `while_statement`, `repeat_statement` | | `and` / `or` | | | |
+//! | Dart | `if_statement` | `for_statement`, `while_statement`, `do_statement` | `switch_statement_case`, `switch_expression_case` | `logical_and_expression` / `logical_or_expression`, `if_null_expression` | | `conditional_expression` | `catch_clause` |
+//! | Scala | `if_expression`, `guard` | `for_expression`, `while_expression`, `do_while_expression` | `case_clause` but `case _ =>` | `&&` / `\|\|` | | | |
+//! | Zig | `if_statement`, `if_expression` | `for_statement`, `for_expression`, `while_statement`, `while_expression` | `switch_case` but `else =>` | `and` / `or`, `orelse` | | | `catch` |
+//!
+//! `for` stands for every loop. Operators are the `operator` of the binary
+//! node kind each grammar uses; null-coalescing (`??`, `orelse`) counts as
+//! `or`. Nested named functions and types are their own units and are not
+//! descended into; closures are, so their branches count for the enclosing
+//! function too. JavaScript / TypeScript, Python, C++, C# and Rust do not
+//! count decision points yet: their functions report cyclomatic complexity 1.
+
+use serde::{Deserialize, Serialize};
+use std::collections::BTreeMap;
+use tree_sitter::Node;
+
+use crate::core::types::{AnalysisResult, FunctionInfo};
+
+/// Kinds of decision point, each with its own weight
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Hash)]
+pub enum Construct {
+    If,
+    /// Any loop
+    For,
+    /// A non-default switch / match / when branch
+    Case,
+    And,
+    Or,
+    /// A Go `select` branch
+    Select,
+    Ternary,
+    Catch,
+}
+
+impl Construct {
+    pub const ALL: [Construct; 8] = [
+        Self::If, Self::For, Self::Case, Self::And, Self::Or, Self::Select, Self::Ternary, Self::Catch,
+    ];
+
+    /// Key in `decision_points` and `[metrics.weights]`
+    pub fn name(self) -> &'static str {
+        match self {
+            Self::If => "if",
+            Self::For => "for",
+            Self::Case => "case",
+            Self::And => "and",
+            Self::Or => "or",
+            Self::Select => "select",
+            Self::Ternary => "ternary",
+            Self::Catch => "catch",
+        }
+    }
+
+    pub fn parse(name: &str) -> Option<Self> {
+        Self::ALL.into_iter().find(|construct| construct.name() == name)
+    }
+}
+
+/// Node kinds that are decision points in one grammar
+pub struct CyclomaticRules {
+    /// Nodes that are one decision point each
+    pub decisions: &'static [(&'static str, Construct)],
+    /// Binary nodes whose `operator` field decides the construct
+    pub binary_kinds: &'static [&'static str],
+    pub operators: &'static [(&'static str, Construct)],
+    /// `case` nodes starting with one of these are the default branch (`default`, `else`)
+    pub default_prefixes: &'static [&'static str],
+    /// `case` nodes whose `pattern` field is one of these are the default branch (`_`)
+    pub default_patterns: &'static [&'static str],
+    /// Nested functions and types measured on their own
+    pub separate_units: &'static [&'static str],
+}
+
+pub const GO: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_statement", Construct::If),
+        ("for_statement", Construct::For),
+        ("expression_case", Construct::Case),
+        ("type_case", Construct::Case),
+        ("communication_case", Construct::Select),
+    ],
+    binary_kinds: &["binary_expression"],
+    operators: &[("&&", Construct::And), ("||", Construct::Or)],
+    default_prefixes: &[],
+    default_patterns: &[],
+    separate_units: &[],
+};
+
+pub const JAVA: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_statement", Construct::If),
+        ("for_statement", Construct::For),
+        ("enhanced_for_statement", Construct::For),
+        ("while_statement", Construct::For),
+        ("do_statement", Construct::For),
+        ("switch_label", Construct::Case),
+        ("ternary_expression", Construct::Ternary),
+        ("catch_clause", Construct::Catch),
+    ],
+    binary_kinds: &["binary_expression"],
+    operators: &[("&&", Construct::And), ("||", Construct::Or)],
+    default_prefixes: &["default"],
+    default_patterns: &[],
+    separate_units: &["lambda_expression", "class_body"],
+};
+
+pub const KOTLIN: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_expression", Construct::If),
+        ("for_statement", Construct::For),
+        ("while_statement", Construct::For),
+        ("do_while_statement", Construct::For),
+        ("when_entry", Construct::Case),
+        ("conjunction_expression", Construct::And),
+        ("disjunction_expression", Construct::Or),
+        ("catch_block", Construct::Catch),
+    ],
+    binary_kinds: &[],
+    operators: &[],
+    default_prefixes: &["else"],
+    default_patterns: &[],
+    // Trailing lambdas (`forEach { }`, `run { }`) are control flow of the enclosing function
+    separate_units: &["anonymous_function", "function_declaration", "object_literal", "class_body"],
+};
+
+pub const SWIFT: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_statement", Construct::If),
+        ("guard_statement", Construct::If),
+        ("for_statement", Construct::For),
+        ("while_statement", Construct::For),
+        ("repeat_while_statement", Construct::For),
+        ("switch_entry", Construct::Case),
+        ("conjunction_expression", Construct::And),
+        ("disjunction_expression", Construct::Or),
+        ("ternary_expression", Construct::Ternary),
+        ("catch_block", Construct::Catch),
+    ],
+    binary_kinds: &[],
+    operators: &[],
+    default_prefixes: &["default"],
+    default_patterns: &[],
+    separate_units: &["function_declaration", "class_declaration", "protocol_declaration"],
+};
+
+pub const RUBY: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if", Construct::If),
+        ("unless", Construct::If),
+        ("elsif", Construct::If),
+        ("if_modifier", Construct::If),
+        ("unless_modifier", Construct::If),
+        ("while", Construct::For),
+        ("until", Construct::For),
+        ("for", Construct::For),
+        ("while_modifier", Construct::For),
+        ("until_modifier", Construct::For),
+        ("when", Construct::Case),
+        ("conditional", Construct::Ternary),
+        ("rescue", Construct::Catch),
+    ],
+    binary_kinds: &["binary"],
+    operators: &[("&&", Construct::And), ("and", Construct::And), ("||", Construct::Or), ("or", Construct::Or)],
+    default_prefixes: &[],
+    default_patterns: &[],
+    separate_units: &[],
+};
+
+pub const PHP: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_statement", Construct::If),
+        ("else_if_clause", Construct::If),
+        ("for_statement", Construct::For),
+        ("foreach_statement", Construct::For),
+        ("while_statement", Construct::For),
+        ("do_statement", Construct::For),
+        ("case_statement", Construct::Case),
+        // match arms other than `default =>`
+        ("match_conditional_expression", Construct::Case),
+        ("conditional_expression", Construct::Ternary),
+        ("catch_clause", Construct::Catch),
+    ],
+    binary_kinds: &["binary_expression"],
+    operators: &[
+        ("&&", Construct::And), ("and", Construct::And), ("||", Construct::Or), ("or", Construct::Or), ("??", Construct::Or),
+    ],
+    default_prefixes: &[],
+    default_patterns: &[],
+    // Closures are control flow of the enclosing function
+    separate_units: &[
+        "function_definition", "class_declaration", "interface_declaration", "trait_declaration", "enum_declaration",
+    ],
+};
+
+pub const LUA: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_statement", Construct::If),
+        ("elseif_statement", Construct::If),
+        ("for_statement", Construct::For),
+        ("while_statement", Construct::For),
+        ("repeat_statement", Construct::For),
+    ],
+    binary_kinds: &["binary_expression"],
+    operators: &[("and", Construct::And), ("or", Construct::Or)],
+    default_prefixes: &[],
+    default_patterns: &[],
+    separate_units: &["function_declaration"],
+};
+
+pub const DART: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_statement", Construct::If),
+        ("for_statement", Construct::For),
+        ("while_statement", Construct::For),
+        ("do_statement", Construct::For),
+        ("switch_statement_case", Construct::Case),
+        ("switch_expression_case", Construct::Case),
+        ("logical_and_expression", Construct::And),
+        ("logical_or_expression", Construct::Or),
+        ("if_null_expression", Construct::Or),
+        ("conditional_expression", Construct::Ternary),
+        ("catch_clause", Construct::Catch),
+    ],
+    binary_kinds: &[],
+    operators: &[],
+    default_prefixes: &[],
+    default_patterns: &[],
+    separate_units: &[],
+};
+
+pub const SCALA: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_expression", Construct::If),
+        ("guard", Construct::If),
+        ("for_expression", Construct::For),
+        ("while_expression", Construct::For),
+        ("do_while_expression", Construct::For),
+        ("case_clause", Construct::Case),
+    ],
+    binary_kinds: &["infix_expression"],
+    operators: &[("&&", Construct::And), ("||", Construct::Or)],
+    default_prefixes: &[],
+    default_patterns: &["wildcard"],
+    // Lambdas are control flow of the enclosing def
+    separate_units: &[
+        "function_definition", "function_declaration",
+        "class_definition", "trait_definition", "object_definition", "enum_definition", "package_object",
+    ],
+};
+
+pub const ZIG: CyclomaticRules = CyclomaticRules {
+    decisions: &[
+        ("if_statement", Construct::If),
+        ("if_expression", Construct::If),
+        ("for_statement", Construct::For),
+        ("for_expression", Construct::For),
+        ("while_statement", Construct::For),
+        ("while_expression", Construct::For),
+        ("switch_case", Construct::Case),
+    ],
+    binary_kinds: &["binary_expression"],
+    operators: &[
+        ("and", Construct::And), ("or", Construct::Or), ("orelse", Construct::Or), ("catch", Construct::Catch),
+    ],
+    default_prefixes: &["else"],
+    default_patterns: &[],
+    separate_units: &[
+        "function_declaration", "struct_declaration", "union_declaration", "enum_declaration", "opaque_declaration",
+    ],
+};
+
+/// Decision points below `body`, by construct name; constructs that do not occur are absent
+pub fn decision_points(body: Node, source: &str, rules: &CyclomaticRules) -> BTreeMap<String, u32> {
+    let mut points = BTreeMap::new();
+    count(body, source, rules, &mut points);
+    points
+}
+
+/// Helper: Count `node` and its subtree into `points`
+fn count(node: Node, source: &str, rules: &CyclomaticRules, points: &mut BTreeMap<String, u32>) {
+    let kind = node.kind();
+    if rules.separate_units.contains(&kind) {
+        return;
+    }
+
+    if let Some(construct) = construct_of(node, source, rules) {
+        *points.entry(construct.name().to_string()).or_insert(0) += 1;
+    }
+
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        count(child, source, rules, points);
+    }
+}
+
+/// Helper: The construct `node` is a decision point of, if any
+fn construct_of(node: Node, source: &str, rules: &CyclomaticRules) -> Option<Construct> {
+    let kind = node.kind();
+    if rules.binary_kinds.contains(&kind) {
+        let operator = node.child_by_field_name("operator")?.utf8_text(source.as_bytes()).ok()?;
+        return rules.operators.iter()
+            .find(|(name, _)| name.eq_ignore_ascii_case(operator))
+            .map(|(_, construct)| *construct);
+    }
+
+    let (_, construct) = rules.decisions.iter().find(|(decision, _)| *decision == kind)?;
+    if *construct == Construct::Case && is_default_case(node, source, rules) {
+        return None;
+    }
+    Some(*construct)
+}
+
+/// Helper: `default:`, `else ->` or `case _ =>`
+fn is_default_case(node: Node, source: &str, rules: &CyclomaticRules) -> bool {
+    let text = node.utf8_text(source.as_bytes()).unwrap_or("").trim_start();
+    rules.default_prefixes.iter().any(|prefix| text.starts_with(prefix))
+        || node.child_by_field_name("pattern").map_or(false, |pattern| rules.default_patterns.contains(&pattern.kind()))
+}
+
+/// Weight of each construct in cyclomatic complexity (`[metrics.weights]`)
+#[derive(Debug, Clone, Copy, PartialEq, Eq, Serialize, Deserialize)]
+#[serde(default, deny_unknown_fields)]
+pub struct ComplexityWeights {
+    #[serde(rename = "if")]
+    pub if_: u32,
+    #[serde(rename = "for")]
+    pub for_: u32,
+    pub case: u32,
+    pub and: u32,
+    pub or: u32,
+    pub select: u32,
+    pub ternary: u32,
+    pub catch: u32,
+}
+
+impl ComplexityWeights {
+    /// +1 per decision point, as McCabe counts them
+    pub const STANDARD: Self = Self { if_: 1, for_: 1, case: 1, and: 1, or: 1, select: 1, ternary: 1, catch: 1 };
+
+    pub fn weight(&self, construct: Construct) -> u32 {
+        match construct {
+            Construct::If => self.if_,
+            Construct::For => self.for_,
+            Construct::Case => self.case,
+            Construct::And => self.and,
+            Construct::Or => self.or,
+            Construct::Select => self.select,
+            Construct::Ternary => self.ternary,
+            Construct::Catch => self.catch,
+        }
+    }
+
+    /// Mutable weight of one construct, for layering config values
+    pub fn weight_mut(&mut self, construct: Construct) -> &mut u32 {
+        match construct {
+            Construct::If => &mut self.if_,
+            Construct::For => &mut self.for_,
+            Construct::Case => &mut self.case,
+            Construct::And => &mut self.and,
+            Construct::Or => &mut self.or,
+            Construct::Select => &mut self.select,
+            Construct::Ternary => &mut self.ternary,
+            Construct::Catch => &mut self.catch,
+        }
+    }
+
+    /// 1 plus the weighted decision points
+    pub fn cyclomatic(&self, points: &BTreeMap<String, u32>) -> u32 {
+        let weighted: u32 = points.iter()
+            .map(|(name, count)| Construct::parse(name).map_or(1, |construct| self.weight(construct)) * count)
+            .sum();
+        1 + weighted
+    }
+
+    /// Re-weigh the cyclomatic complexity of every function, method and nested function
+    pub fn apply(&self, result: &mut AnalysisResult) {
+        // Analyzers already count at the standard weights
+        if *self == Self::STANDARD {
+            return;
+        }
+        let methods = result.classes.iter_mut().flat_map(|class| class.methods.iter_mut());
+        for func in result.functions.iter_mut().chain(methods) {
+            self.apply_to_function(func);
+        }
+    }
+
+    /// Helper: One function and its children
+    fn apply_to_function(&self, func: &mut FunctionInfo) {
+        func.complexity.cyclomatic_complexity = self.cyclomatic(&func.complexity.decision_points);
+        func.complexity.update_rating();
+        for child in &mut func.children {
+            self.apply_to_function(child);
+        }
+    }
+}
+
+impl Default for ComplexityWeights {
+    fn default() -> Self {
+        Self::STANDARD
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use tree_sitter::Parser;
+
+    #[test]
+    fn test_decision_points() {
+        let source = "package p\n\nfunc f(x int) int {\n\tswitch {\n\tcase x > 1 && x < 9:\n\t\treturn 1\n\tdefault:\n\t}\n\tfor i := 0; i < x || x > 3; i++ {\n\t\tif i == 2 {\n\t\t\treturn i\n\t\t}\n\t}\n\treturn 0\n}\n";
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_go::LANGUAGE.into()).unwrap();
+        let tree = parser.parse(source, None).unwrap();
+        let body = tree.root_node().named_child(1).unwrap().child_by_field_name("body").unwrap();
+
+        let points = decision_points(body, source, &GO);
+        let counts: Vec<(&str, u32)> = points.iter().map(|(name, count)| (name.as_str(), *count)).collect();
+        assert_eq!(counts, vec![("and", 1), ("case", 1), ("for", 1), ("if", 1), ("or", 1)]);
+        assert_eq!(ComplexityWeights::STANDARD.cyclomatic(&points), 6);
+
+        let weights = ComplexityWeights { and: 0, or: 0, case: 2, ..ComplexityWeights::STANDARD };
+        assert_eq!(weights.cyclomatic(&points), 5);
+    }
+
+    #[test]
+    fn test_weights_from_toml() {
+        let weights: ComplexityWeights = toml::from_str("if = 2\nfor = 3\n").unwrap();
+        assert_eq!((weights.if_, weights.for_, weights.case), (2, 3, 1));
+        assert!(toml::from_str::<ComplexityWeights>("while = 2").is_err());
+    }
+}
@@ -0,0 +1,151 @@
+//! 🔑 Function body fingerprints
+//!
+//! A body hash identifies an implementation independently of the function's
+//! name, position and formatting, so a renamed or moved function can be
+//! recognised when two analysis snapshots are compared, and reformatting or
+//! re-commenting a body does not count as a change. The raw hash covers the
+//! exact bytes. Clone tokens go one step further and also ignore identifier
+//! names and literal values (type-2 clones).
+
+use tree_sitter::Node;
+
+/// Hash of the token stream of a function body (the `body` field, or the whole node)
+///
+/// Every leaf token is hashed by its text with comments skipped, so whitespace,
+/// line breaks and comments do not affect the result.
+pub fn body_hash(node: Node, source: &str) -> String {
+    let body = node.child_by_field_name("body").unwrap_or(node);
+    
+    let mut hasher = blake3::Hasher::new();
+    hash_tokens(body, source, &mut hasher);
+    hasher.finalize().to_hex()[..16].to_string()
+}
+
+/// Hash of the exact bytes of a function body (the `body` field, or the whole node)
+pub fn raw_hash(node: Node, source: &str) -> String {
+    let body = node.child_by_field_name("body").unwrap_or(node);
+    let bytes = source.as_bytes().get(body.start_byte()..body.end_byte()).unwrap_or(&[]);
+    blake3::hash(bytes).to_hex()[..16].to_string()
+}
+
+/// Hash of a whole file's content
+pub fn content_hash(content: &str) -> String {
+    blake3::hash(content.as_bytes()).to_hex()[..16].to_string()
+}
+
+/// Helper: Feed the non-comment leaf tokens of `node` to the hasher in source order
+fn hash_tokens(node: Node, source: &str, hasher: &mut blake3::Hasher) {
+    if node.kind().contains("comment") {
+        return;
+    }
+    
+    if node.child_count() == 0 {
+        let text = node.utf8_text(source.as_bytes()).unwrap_or("");
+        // Tokens are separated so `a b` and `ab` stay distinct
+        hasher.update(text.trim().as_bytes());
+        hasher.update(b" ");
+        return;
+    }
+    
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        hash_tokens(child, source, hasher);
+    }
+}
+
+/// Normalized token stream of a function body for clone detection
+///
+/// Comments are dropped, identifiers and literals become placeholders and
+/// every other leaf is represented by its node kind (keyword / operator),
+/// so two bodies differing only in naming produce the same stream.
+pub fn clone_tokens(node: Node) -> Vec<u32> {
+    let body = node.child_by_field_name("body").unwrap_or(node);
+    let mut tokens = Vec::new();
+    collect_tokens(body, &mut tokens);
+    tokens
+}
+
+/// Helper: Append the normalized leaves of `node` in source order
+fn collect_tokens(node: Node, tokens: &mut Vec<u32>) {
+    let kind = node.kind();
+    if kind.contains("comment") {
+        return;
+    }
+    
+    if node.child_count() == 0 {
+        let token = if kind.contains("identifier") {
+            "$id"
+        } else if is_literal_kind(kind) {
+            "$lit"
+        } else {
+            kind
+        };
+        tokens.push(fnv1a(token));
+        return;
+    }
+    
+    // Literal nodes with inner structure (strings with escapes) collapse to one token
+    if node.is_named() && is_literal_kind(kind) {
+        tokens.push(fnv1a("$lit"));
+        return;
+    }
+    
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        collect_tokens(child, tokens);
+    }
+}
+
+/// Helper: Node kinds holding literal values across the supported grammars
+fn is_literal_kind(kind: &str) -> bool {
+    kind.contains("string")
+        || kind.contains("literal")
+        || kind.contains("number")
+        || kind.contains("integer")
+        || kind.contains("float")
+        || kind.contains("char")
+}
+
+/// Helper: 32-bit FNV-1a, enough to intern the small set of token kinds
+fn fnv1a(text: &str) -> u32 {
+    let mut hash: u32 = 0x811c9dc5;
+    for byte in text.bytes() {
+        hash ^= byte as u32;
+        hash = hash.wrapping_mul(0x01000193);
+    }
+    hash
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use tree_sitter::Parser;
+    
+    /// Hashes of the first function in a Go source
+    fn hashes(source: &str) -> (String, String) {
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_go::LANGUAGE.into()).unwrap();
+        let tree = parser.parse(source, None).unwrap();
+        let func = tree.root_node().named_child(0).unwrap();
+        (body_hash(func, source), raw_hash(func, source))
+    }
+    
+    #[test]
+    fn test_body_hash_ignores_formatting_and_comments() {
+        let (body, raw) = hashes("func f(a int) int {\n\treturn a + 1\n}\n");
+        let (reformatted_body, reformatted_raw) = hashes("func f(a int) int {\n  // increment\n  return a+1\n}\n");
+        
+        assert_eq!(body, reformatted_body);
+        assert_ne!(raw, reformatted_raw);
+        
+        let (edited_body, _) = hashes("func f(a int) int {\n\treturn a + 2\n}\n");
+        assert_ne!(body, edited_body);
+    }
+    
+    #[test]
+    fn test_content_hash() {
+        assert_eq!(content_hash("package main\n"), content_hash("package main\n"));
+        assert_ne!(content_hash("package main\n"), content_hash("package main \n"));
+        assert_eq!(content_hash("").len(), 16);
+    }
+}
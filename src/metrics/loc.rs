@@ -0,0 +1,153 @@
+//! 📏 Line-of-code metrics
+//!
+//! Every physical line is classified from the syntax tree rather than by
+//! prefix matching: a line is a comment line when it only contains comment
+//! text, a source line when it contains anything outside a comment node.
+//! Doc comments directly above a function (no blank line in between) count
+//! towards that function instead of the file.
+
+use tree_sitter::{Node, Tree};
+
+use crate::core::types::{AnalysisResult, FunctionInfo};
+
+/// Classification of one physical line
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum LineKind {
+    Blank,
+    Comment,
+    Code,
+}
+
+/// Per-line classification of a whole file (index 0 = line 1)
+pub struct LineMap {
+    lines: Vec<LineKind>,
+}
+
+impl LineMap {
+    pub fn new(tree: &Tree, source: &str) -> Self {
+        let mut in_comment = vec![false; source.len()];
+        mark_comments(tree.root_node(), &mut in_comment);
+        
+        let mut lines = Vec::new();
+        let mut offset = 0;
+        for line in source.split_inclusive('\n') {
+            let mut has_code = false;
+            let mut has_comment = false;
+            for (i, byte) in line.bytes().enumerate() {
+                if byte.is_ascii_whitespace() {
+                    continue;
+                }
+                if in_comment[offset + i] {
+                    has_comment = true;
+                } else {
+                    has_code = true;
+                }
+            }
+            lines.push(if has_code {
+                LineKind::Code
+            } else if has_comment {
+                LineKind::Comment
+            } else {
+                LineKind::Blank
+            });
+            offset += line.len();
+        }
+        
+        Self { lines }
+    }
+    
+    /// Helper: Kind of a 1-based line
+    fn kind(&self, line: u32) -> Option<LineKind> {
+        line.checked_sub(1).and_then(|i| self.lines.get(i as usize)).copied()
+    }
+    
+    /// Number of lines of the given kind in a 1-based inclusive range
+    pub fn count(&self, start_line: u32, end_line: u32, kind: LineKind) -> u32 {
+        (start_line..=end_line)
+            .filter(|line| self.kind(*line) == Some(kind))
+            .count() as u32
+    }
+    
+    /// First line of the comment block directly above `start_line`, if any
+    pub fn doc_comment_start(&self, start_line: u32) -> Option<u32> {
+        let mut first = None;
+        let mut line = start_line.saturating_sub(1);
+        while self.kind(line) == Some(LineKind::Comment) {
+            first = Some(line);
+            line -= 1;
+        }
+        first
+    }
+    
+    /// Fill `loc`, `sloc` and `comment_lines` of a function and its nested functions
+    pub fn apply_to_function(&self, func: &mut FunctionInfo) {
+        if func.start_line == 0 || func.end_line < func.start_line {
+            return;
+        }
+        
+        func.loc = func.end_line - func.start_line + 1;
+        func.sloc = self.count(func.start_line, func.end_line, LineKind::Code);
+        func.comment_lines = self.count(func.start_line, func.end_line, LineKind::Comment);
+        if let Some(doc_start) = self.doc_comment_start(func.start_line) {
+            func.comment_lines += func.start_line - doc_start;
+        }
+        for child in &mut func.children {
+            self.apply_to_function(child);
+        }
+    }
+    
+    /// Annotate every function / method and the file totals of a result
+    pub fn apply(&self, result: &mut AnalysisResult) {
+        for func in &mut result.functions {
+            self.apply_to_function(func);
+        }
+        for class in &mut result.classes {
+            for method in &mut class.methods {
+                self.apply_to_function(method);
+            }
+        }
+        
+        let total = self.lines.len() as u32;
+        let info = &mut result.file_info;
+        info.empty_lines = self.count(1, total, LineKind::Blank);
+        info.comment_lines = self.count(1, total, LineKind::Comment);
+        info.code_lines = self.count(1, total, LineKind::Code);
+        info.code_ratio = if info.total_lines > 0 {
+            info.code_lines as f64 / info.total_lines as f64
+        } else {
+            0.0
+        };
+        info.comment_density = comment_density(info.comment_lines, info.code_lines);
+    }
+}
+
+/// Comment lines as a share of all non-blank lines
+pub fn comment_density(comment_lines: u32, code_lines: u32) -> f64 {
+    let total = comment_lines + code_lines;
+    if total > 0 {
+        comment_lines as f64 / total as f64
+    } else {
+        0.0
+    }
+}
+
+/// Annotate a result with line metrics computed from its syntax tree
+pub fn annotate_line_metrics(tree: &Tree, source: &str, result: &mut AnalysisResult) {
+    LineMap::new(tree, source).apply(result);
+}
+
+/// Helper: Mark the bytes covered by comment nodes (`comment`, `line_comment`, ...)
+fn mark_comments(node: Node, in_comment: &mut [bool]) {
+    if node.kind().contains("comment") {
+        let end = node.end_byte().min(in_comment.len());
+        for flag in &mut in_comment[node.start_byte().min(end)..end] {
+            *flag = true;
+        }
+        return;
+    }
+    
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        mark_comments(child, in_comment);
+    }
+}
@@ -0,0 +1,270 @@
+//! 🔢 Magic numbers (numeric literals used inline)
+//!
+//! Every numeric literal in a function body other than 0 and 1 is recorded as
+//! a `magic_number` smell of that function, unless it is the value of a named
+//! constant or an enum member: Go `const`, JavaScript `const`, Rust `const` /
+//! `static`, Java `final`, Python and Ruby UPPER_CASE assignments, and so on.
+//! Literals in closures count for the enclosing function.
+//!
+//! Which further values are tolerated is a per-run setting (`[smells]` in
+//! `nekocode.toml`), so analyzers keep every literal and the session narrows
+//! the list; cached results stay valid for any setting.
+
+use tree_sitter::{Node, Tree};
+
+use crate::core::types::{AnalysisResult, FunctionInfo, SmellInfo};
+use crate::metrics::plugin::function_node;
+
+/// Node kinds that matter for magic numbers in one grammar
+pub struct MagicNumberRules {
+    /// Numeric literal nodes
+    pub number_kinds: &'static [&'static str],
+    /// Declarations whose literals are named; with a keyword, only declarations
+    /// that use it before their `=` (`const`, `final`, `let`)
+    pub constant_declarations: &'static [(&'static str, &'static str)],
+    /// Assignments that declare a constant when the target is UPPER_CASE
+    pub upper_case_assignments: &'static [&'static str],
+}
+
+pub const GO: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["int_literal", "float_literal", "imaginary_literal"],
+    constant_declarations: &[("const_declaration", "")],
+    upper_case_assignments: &[],
+};
+
+pub const JAVASCRIPT: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["number"],
+    constant_declarations: &[("lexical_declaration", "const"), ("enum_declaration", "")],
+    upper_case_assignments: &[],
+};
+
+pub const PYTHON: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer", "float"],
+    constant_declarations: &[],
+    upper_case_assignments: &["assignment"],
+};
+
+pub const RUST: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer_literal", "float_literal"],
+    constant_declarations: &[("const_item", ""), ("static_item", ""), ("enum_item", "")],
+    upper_case_assignments: &[],
+};
+
+pub const CPP: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["number_literal"],
+    constant_declarations: &[
+        ("declaration", "const"), ("declaration", "constexpr"), ("enum_specifier", ""), ("preproc_def", ""),
+    ],
+    upper_case_assignments: &[],
+};
+
+pub const CSHARP: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer_literal", "real_literal"],
+    constant_declarations: &[
+        ("local_declaration_statement", "const"), ("field_declaration", "const"), ("enum_declaration", ""),
+    ],
+    upper_case_assignments: &[],
+};
+
+pub const JAVA: MagicNumberRules = MagicNumberRules {
+    number_kinds: &[
+        "decimal_integer_literal", "hex_integer_literal", "octal_integer_literal", "binary_integer_literal",
+        "decimal_floating_point_literal", "hex_floating_point_literal",
+    ],
+    constant_declarations: &[
+        ("local_variable_declaration", "final"), ("field_declaration", "final"), ("enum_declaration", ""),
+    ],
+    upper_case_assignments: &[],
+};
+
+pub const KOTLIN: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer_literal", "long_literal", "hex_literal", "bin_literal", "real_literal", "unsigned_literal"],
+    constant_declarations: &[("property_declaration", "val"), ("enum_class_body", "")],
+    upper_case_assignments: &[],
+};
+
+pub const SWIFT: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer_literal", "real_literal", "hex_literal", "oct_literal", "bin_literal"],
+    constant_declarations: &[("property_declaration", "let"), ("enum_entry", "")],
+    upper_case_assignments: &[],
+};
+
+pub const RUBY: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer", "float"],
+    constant_declarations: &[],
+    upper_case_assignments: &["assignment"],
+};
+
+pub const PHP: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer", "float"],
+    constant_declarations: &[("const_declaration", ""), ("enum_declaration", "")],
+    upper_case_assignments: &[],
+};
+
+pub const LUA: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["number"],
+    constant_declarations: &[("variable_declaration", "const")],
+    upper_case_assignments: &[],
+};
+
+pub const DART: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["decimal_integer_literal", "hex_integer_literal", "decimal_floating_point_literal"],
+    constant_declarations: &[
+        ("local_variable_declaration", "const"), ("local_variable_declaration", "final"), ("enum_declaration", ""),
+    ],
+    upper_case_assignments: &[],
+};
+
+pub const SCALA: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer_literal", "floating_point_literal"],
+    constant_declarations: &[("val_definition", ""), ("enum_definition", "")],
+    upper_case_assignments: &[],
+};
+
+pub const ZIG: MagicNumberRules = MagicNumberRules {
+    number_kinds: &["integer", "float"],
+    constant_declarations: &[("variable_declaration", "const"), ("enum_declaration", "")],
+    upper_case_assignments: &[],
+};
+
+/// Record the magic numbers of every function and method; call after functions are extracted
+pub fn collect_magic_numbers(tree: &Tree, source: &str, result: &mut AnalysisResult, rules: &MagicNumberRules) {
+    let functions = result.functions.iter_mut()
+        .chain(result.classes.iter_mut().flat_map(|class| class.methods.iter_mut()));
+    for func in functions {
+        let Some(node) = function_node(tree, func) else { continue };
+        let body = node.child_by_field_name("body").unwrap_or(node);
+        let mut literals = Vec::new();
+        find_literals(body, source, rules, &mut literals);
+        push_smells(func, source, literals);
+    }
+}
+
+/// Helper: Numeric literals below `node`, skipping constant declarations
+fn find_literals<'t>(node: Node<'t>, source: &str, rules: &MagicNumberRules, literals: &mut Vec<Node<'t>>) {
+    if rules.number_kinds.contains(&node.kind()) {
+        literals.push(node);
+        return;
+    }
+    if is_constant_declaration(node, source, rules) {
+        return;
+    }
+
+    let mut cursor = node.walk();
+    for child in node.named_children(&mut cursor) {
+        find_literals(child, source, rules, literals);
+    }
+}
+
+/// Helper: Whether `node` declares a named constant or an enum
+fn is_constant_declaration(node: Node, source: &str, rules: &MagicNumberRules) -> bool {
+    let kind = node.kind();
+    let Ok(text) = node.utf8_text(source.as_bytes()) else { return false };
+
+    if rules.upper_case_assignments.contains(&kind) {
+        let target = node.child_by_field_name("left")
+            .and_then(|left| left.utf8_text(source.as_bytes()).ok())
+            .unwrap_or("");
+        return is_upper_case(target);
+    }
+
+    let head = text.split('=').next().unwrap_or("");
+    rules.constant_declarations.iter().any(|(declaration, keyword)| {
+        *declaration == kind
+            && (keyword.is_empty() || head.split(|c: char| !c.is_alphanumeric() && c != '_').any(|word| word == *keyword))
+    })
+}
+
+/// Helper: `MAX_RETRIES`, not `Max` or `_`
+fn is_upper_case(name: &str) -> bool {
+    name.chars().any(|c| c.is_ascii_uppercase())
+        && name.chars().all(|c| c.is_ascii_uppercase() || c.is_ascii_digit() || c == '_')
+}
+
+/// Helper: One smell per literal whose value is not 0 or 1
+fn push_smells(func: &mut FunctionInfo, source: &str, literals: Vec<Node>) {
+    for literal in literals {
+        let Ok(text) = literal.utf8_text(source.as_bytes()) else { continue };
+        if matches!(literal_value(text), Some(value) if value == 0.0 || value == 1.0) {
+            continue;
+        }
+        func.smells.push(SmellInfo {
+            kind: SmellInfo::MAGIC_NUMBER.to_string(),
+            line: literal.start_position().row as u32 + 1,
+            value: text.to_string(),
+            message: format!("magic number {}", text),
+        });
+    }
+}
+
+/// Numeric value of a literal as written in any supported language
+///
+/// Digit separators (`1_000`, `1'000`), radix prefixes (`0x`, `0o`, `0b`) and
+/// type suffixes (`10L`, `2.5f`, `8u32`, `3j`) are understood.
+pub fn literal_value(text: &str) -> Option<f64> {
+    let digits: String = text.chars().filter(|c| *c != '_' && *c != '\'').collect::<String>().to_ascii_lowercase();
+
+    for (prefix, radix) in [("0x", 16), ("0o", 8), ("0b", 2)] {
+        if let Some(rest) = digits.strip_prefix(prefix) {
+            let end = rest.find(|c: char| !c.is_digit(radix)).unwrap_or(rest.len());
+            return u64::from_str_radix(&rest[..end], radix).ok().map(|value| value as f64);
+        }
+    }
+
+    // Longest leading run that parses, so suffixes fall away
+    let end = digits.find(|c: char| !(c.is_ascii_digit() || matches!(c, '.' | 'e' | '+' | '-'))).unwrap_or(digits.len());
+    (1..=end).rev().find_map(|len| digits[..len].parse::<f64>().ok())
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use crate::core::types::{FileInfo, Language};
+    use std::path::PathBuf;
+    use tree_sitter::Parser;
+
+    #[test]
+    fn test_literal_value() {
+        assert_eq!(literal_value("42"), Some(42.0));
+        assert_eq!(literal_value("1_000"), Some(1000.0));
+        assert_eq!(literal_value("0xFF"), Some(255.0));
+        assert_eq!(literal_value("0b101"), Some(5.0));
+        assert_eq!(literal_value("2.5f"), Some(2.5));
+        assert_eq!(literal_value("8u32"), Some(8.0));
+        assert_eq!(literal_value("1e3"), Some(1000.0));
+        assert_eq!(literal_value("10L"), Some(10.0));
+        assert_eq!(literal_value("1.0"), Some(1.0));
+    }
+
+    #[test]
+    fn test_collect_magic_numbers() {
+        let source = "package p\n\nfunc f(x int) int {\n\tconst limit = 100\n\tif x > 7 {\n\t\treturn x * 0\n\t}\n\treturn limit + 1 + 2.5\n}\n";
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_go::LANGUAGE.into()).unwrap();
+        let tree = parser.parse(source, None).unwrap();
+        let node = tree.root_node().named_child(1).unwrap();
+
+        let mut func = FunctionInfo::new("f".to_string());
+        func.span.start_byte = node.start_byte() as u32;
+        func.span.end_byte = node.end_byte() as u32;
+        let mut result = AnalysisResult::new(FileInfo::new(PathBuf::from("p.go")), Language::Go);
+        result.functions.push(func);
+        collect_magic_numbers(&tree, source, &mut result, &GO);
+
+        let smells: Vec<(u32, &str)> = result.functions[0].smells.iter().map(|s| (s.line, s.value.as_str())).collect();
+        assert_eq!(smells, vec![(5, "7"), (8, "2.5")]);
+    }
+
+    #[test]
+    fn test_upper_case_constants() {
+        let source = "def f(x):\n    MAX_SIZE = 64\n    size = 32\n    return x % MAX_SIZE\n";
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_python::LANGUAGE.into()).unwrap();
+        let tree = parser.parse(source, None).unwrap();
+        let mut literals = Vec::new();
+        find_literals(tree.root_node(), source, &PYTHON, &mut literals);
+
+        let values: Vec<&str> = literals.iter().map(|n| n.utf8_text(source.as_bytes()).unwrap()).collect();
+        assert_eq!(values, vec!["32"]);
+    }
+}
@@ -0,0 +1,124 @@
+//! 📌 Tech-debt comment markers (`TODO`, `FIXME`, ...)
+//!
+//! Every comment line whose first word is an upper-case tag (`TODO:`,
+//! `FIXME(alice):`, `XXX`) is recorded with the rest of the line and the
+//! innermost function around it. Which tags are reported is a per-run
+//! setting, so analyzers keep every tag and the session narrows the list;
+//! cached results stay valid for any `--markers` value.
+
+use tree_sitter::{Node, Tree};
+
+use crate::core::types::{AnalysisResult, FunctionInfo, MarkerInfo};
+
+/// Tags reported when `--markers` is not given
+pub const DEFAULT_MARKERS: &[&str] = &["TODO", "FIXME", "HACK", "XXX"];
+
+/// Record the marker comments of a file; call after functions are extracted
+pub fn collect_markers(tree: &Tree, source: &str, result: &mut AnalysisResult) {
+    let mut comments = Vec::new();
+    find_comments(tree.root_node(), &mut comments);
+    
+    for comment in comments {
+        let Ok(text) = comment.utf8_text(source.as_bytes()) else { continue };
+        let first_line = comment.start_position().row as u32 + 1;
+        for (offset, line) in text.lines().enumerate() {
+            if let Some((kind, text)) = parse_marker(line) {
+                let line = first_line + offset as u32;
+                result.markers.push(MarkerInfo {
+                    kind,
+                    line,
+                    text,
+                    function: enclosing_function(result, line),
+                });
+            }
+        }
+    }
+}
+
+/// Helper: Outermost comment nodes (Rust doc comments nest a `doc_comment` child)
+fn find_comments<'a>(node: Node<'a>, comments: &mut Vec<Node<'a>>) {
+    if node.kind().contains("comment") {
+        comments.push(node);
+        return;
+    }
+    
+    let mut cursor = node.walk();
+    for child in node.children(&mut cursor) {
+        find_comments(child, comments);
+    }
+}
+
+/// Helper: `// TODO(alice): text` -> ("TODO", "text")
+fn parse_marker(line: &str) -> Option<(String, String)> {
+    // Comment punctuation of every supported language: //, ///, //!, /*, *, #, --
+    let body = line.trim_start().trim_start_matches(['/', '*', '#', '!', '-', ';']).trim_start();
+    
+    let tag_len = body.find(|c: char| !(c.is_ascii_uppercase() || c.is_ascii_digit() || c == '_')).unwrap_or(body.len());
+    let tag = &body[..tag_len];
+    if tag.len() < 2 || !tag.starts_with(|c: char| c.is_ascii_uppercase()) {
+        return None;
+    }
+    
+    // The tag must stand alone: `TODO:`, `TODO(owner)`, `TODO text`, not `HTTPServer`
+    let mut rest = &body[tag_len..];
+    if !rest.is_empty() && !rest.starts_with([':', '(', ' ', '\t', '-', '!']) {
+        return None;
+    }
+    if rest.starts_with('(') {
+        rest = rest.find(')').map_or("", |close| &rest[close + 1..]);
+    }
+    let text = rest.trim_start().trim_start_matches([':', '-', '!']).trim();
+    let text = text.strip_suffix("*/").unwrap_or(text).trim_end();
+    
+    Some((tag.to_string(), text.to_string()))
+}
+
+/// Helper: Innermost function or method whose span covers `line`, as `Class.method`
+fn enclosing_function(result: &AnalysisResult, line: u32) -> Option<String> {
+    let methods = result.classes.iter()
+        .flat_map(|class| class.methods.iter().map(move |m| (m, Some(class.name.as_str()))));
+    let functions = result.functions.iter().map(|f| (f, None));
+    
+    methods.chain(functions)
+        .filter(|(func, _)| func.start_line <= line && line <= func.end_line)
+        .min_by_key(|(func, _)| func.end_line - func.start_line)
+        .map(|(func, class_name)| qualified_name(func, class_name))
+}
+
+/// Helper: `Class.method` for methods, the plain name for functions
+fn qualified_name(func: &FunctionInfo, class_name: Option<&str>) -> String {
+    let owner = class_name
+        .or_else(|| func.metadata.get("class_name").map(|s| s.as_str()))
+        .or_else(|| func.metadata.get("receiver_type").map(|s| s.as_str()));
+    match owner {
+        Some(owner) => format!("{}.{}", owner, func.name),
+        None => func.name.clone(),
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    fn marker(kind: &str, text: &str) -> Option<(String, String)> {
+        Some((kind.to_string(), text.to_string()))
+    }
+    
+    #[test]
+    fn test_parse_marker() {
+        assert_eq!(parse_marker("// TODO: handle the empty case"), marker("TODO", "handle the empty case"));
+        assert_eq!(parse_marker("    # FIXME(alice): retry on timeout"), marker("FIXME", "retry on timeout"));
+        assert_eq!(parse_marker("/* HACK work around the parser bug */"), marker("HACK", "work around the parser bug"));
+        assert_eq!(parse_marker(" * XXX"), marker("XXX", ""));
+        assert_eq!(parse_marker("-- NOTE - needs an index"), marker("NOTE", "needs an index"));
+        assert_eq!(parse_marker("/// TODO: document"), marker("TODO", "document"));
+    }
+    
+    #[test]
+    fn test_non_markers() {
+        assert_eq!(parse_marker("// HTTPServer is created lazily"), None);
+        assert_eq!(parse_marker("// Todo: lowercase is prose"), None);
+        assert_eq!(parse_marker("// A single letter"), None);
+        assert_eq!(parse_marker("// the TODO is not first"), None);
+    }
+}
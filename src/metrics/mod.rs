@@ -0,0 +1,26 @@
+//! Language-independent metrics computed from Tree-sitter syntax trees
+//!
+//! Each metric takes a per-grammar rule table describing which node kinds
+//! matter, so every language analyzer can share one implementation.
+
+pub mod cognitive;
+pub mod cyclomatic;
+pub mod fingerprint;
+pub mod loc;
+pub mod magic_numbers;
+pub mod markers;
+pub mod nested;
+pub mod nesting;
+pub mod plugin;
+pub mod span;
+
+pub use cognitive::cognitive_complexity;
+pub use cyclomatic::{decision_points, ComplexityWeights, Construct, CyclomaticRules};
+pub use fingerprint::{body_hash, clone_tokens, content_hash, raw_hash};
+pub use loc::annotate_line_metrics;
+pub use magic_numbers::collect_magic_numbers;
+pub use markers::collect_markers;
+pub use nested::{anonymous_name, is_nested, nested_function_nodes};
+pub use nesting::max_nesting_depth;
+pub use plugin::apply_metric_plugins;
+pub use span::node_span;
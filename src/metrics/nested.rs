@@ -0,0 +1,76 @@
+//! 🪆 Nested functions (closures, lambdas, local functions)
+//!
+//! A function defined inside another function's body is reported as one of
+//! the enclosing function's `children` instead of on its own. Anonymous ones
+//! are named after the enclosing function and their position, the way the Go
+//! runtime names closures: the second closure in `ProcessData` is
+//! `ProcessData$func2`.
+//!
+//! A child's complexity is measured on its own body. The enclosing function's
+//! walk descends into its closures, so their branches count there as well.
+
+use tree_sitter::Node;
+
+/// Function nodes directly inside `node`: in its subtree, but not inside a deeper function
+pub fn nested_function_nodes<'t>(node: Node<'t>, kinds: &[&str]) -> Vec<Node<'t>> {
+    let mut nested = Vec::new();
+    let mut cursor = node.walk();
+    for child in node.named_children(&mut cursor) {
+        collect(child, kinds, &mut nested);
+    }
+    nested
+}
+
+/// Helper: `node` if it is a function, else the functions below it
+fn collect<'t>(node: Node<'t>, kinds: &[&str], nested: &mut Vec<Node<'t>>) {
+    if kinds.contains(&node.kind()) {
+        nested.push(node);
+        return;
+    }
+
+    let mut cursor = node.walk();
+    for child in node.named_children(&mut cursor) {
+        collect(child, kinds, nested);
+    }
+}
+
+/// Whether `node` is defined inside another function of `kinds`
+pub fn is_nested(node: Node, kinds: &[&str]) -> bool {
+    let mut current = node.parent();
+    while let Some(ancestor) = current {
+        if kinds.contains(&ancestor.kind()) {
+            return true;
+        }
+        current = ancestor.parent();
+    }
+    false
+}
+
+/// Synthetic name of the `index`-th (1-based) anonymous function of `parent`
+pub fn anonymous_name(parent: &str, index: usize) -> String {
+    format!("{}$func{}", parent, index)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use tree_sitter::Parser;
+
+    #[test]
+    fn test_nested_function_nodes() {
+        let source = "package p\nfunc f() {\n\tg := func() { h := func() {}; h() }\n\tgo func() {}()\n\tg()\n}\n";
+        let mut parser = Parser::new();
+        parser.set_language(&tree_sitter_go::LANGUAGE.into()).unwrap();
+        let tree = parser.parse(source, None).unwrap();
+        let function = tree.root_node().named_child(1).unwrap();
+
+        // Only the two closures of f, not the one inside the first closure
+        let nested = nested_function_nodes(function, &["func_literal"]);
+        assert_eq!(nested.len(), 2);
+        assert!(is_nested(nested[0], &["func_literal", "function_declaration"]));
+        assert!(!is_nested(function, &["func_literal", "function_declaration"]));
+        assert_eq!(nested_function_nodes(nested[0], &["func_literal"]).len(), 1);
+
+        assert_eq!(anonymous_name("ProcessData", 1), "ProcessData$func1");
+    }
+}
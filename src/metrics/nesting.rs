@@ -0,0 +1,55 @@
+//! 🪆 Maximum nesting depth
+//!
+//! Depth of the deepest chain of block-introducing constructs in a function
+//! body: `if`, loops, `switch` / `select` / `match`, `catch`, ternaries and
+//! closures each add one level. An `else if` continues its `if` at the same
+//! depth, so a flat `if / else if / else` chain is depth 1 however long it is.
+//!
+//! The constructs come from the grammar's [`CognitiveRules`], which keeps the
+//! depth consistent with the nesting cognitive complexity charges for, and
+//! comparable between languages.
+
+use tree_sitter::Node;
+
+use super::cognitive::{chained_if, if_branches, CognitiveRules};
+
+/// Maximum nesting depth of a function node (its body, if the grammar has one)
+pub fn max_nesting_depth(node: Node, rules: &CognitiveRules) -> u32 {
+    let body = node.child_by_field_name("body").unwrap_or(node);
+    deepest_child(body, rules, 0)
+}
+
+/// Helper: Deepest level below `node`, whose children sit at `depth`
+fn deepest_child(node: Node, rules: &CognitiveRules, depth: u32) -> u32 {
+    let mut cursor = node.walk();
+    let children: Vec<Node> = node.named_children(&mut cursor).collect();
+    children.into_iter()
+        .map(|child| deepest(child, rules, depth))
+        .fold(depth, u32::max)
+}
+
+/// Helper: Deepest level reached by one node at `depth`
+fn deepest(node: Node, rules: &CognitiveRules, depth: u32) -> u32 {
+    let kind = node.kind();
+    
+    if rules.if_kinds.contains(&kind) {
+        return deepest_if(node, rules, depth);
+    }
+    
+    if rules.nesting_structures.contains(&kind) || rules.nesting_only.contains(&kind) {
+        return deepest_child(node, rules, depth + 1);
+    }
+    
+    deepest_child(node, rules, depth)
+}
+
+/// Helper: An `if` and its branches; `else if` stays at the `if`'s depth
+fn deepest_if(node: Node, rules: &CognitiveRules, depth: u32) -> u32 {
+    if_branches(node, rules).into_iter()
+        .map(|(child, is_alternative)| match (is_alternative, chained_if(child, rules)) {
+            (true, Some(inner)) => deepest_if(inner, rules, depth),
+            (true, None) => deepest_child(child, rules, depth + 1),
+            (false, _) => deepest(child, rules, depth + 1),
+        })
+        .fold(depth + 1, u32::max)
+}
@@ -0,0 +1,217 @@
+//! 🧩 Custom metric plugins
+//!
+//! A [`MetricPlugin`] sees every function and method an analyzer found, as the
+//! Tree-sitter node the analyzer recorded plus the file source, and returns
+//! named values. They are merged into the function's `custom` object:
+//!
+//! ```json
+//! { "name": "parse", "custom": { "parameter_count": 2 } }
+//! ```
+//!
+//! Plugins are registered once at startup with [`register_metric_plugin`] and
+//! run for every language; use [`FunctionNode::language`] to skip the others.
+//! A value wins over an earlier plugin's value of the same name.
+//!
+//! # Function nodes
+//!
+//! The node kinds handed to plugins come from each language's grammar:
+//!
+//! | Language | Node kinds |
+//! |----------|------------|
+//! | JavaScript / TypeScript | `function_declaration`, `function_expression`, `arrow_function`, `method_definition` |
+//! | Python | `function_definition` |
+//! | Go | `function_declaration`, `method_declaration` |
+//! | Rust | `function_item` |
+//! | C / C++ | `function_definition` |
+//! | C# | `method_declaration`, `constructor_declaration`, `local_function_statement` |
+//! | Java | `method_declaration`, `constructor_declaration` |
+//! | Kotlin | `function_declaration` |
+//! | Swift | `function_declaration`, `init_declaration` |
+//! | Ruby | `method`, `singleton_method` |
+//! | PHP | `function_definition`, `method_declaration` |
+//! | Lua | `function_declaration`, `function_definition` |
+//!
+//! Walk them with the usual Tree-sitter API (`child_by_field_name("body")`,
+//! `walk()`, ...). Results are cached with the registered plugin names in the
+//! cache fingerprint, so registering a different set re-analyzes files.
+
+use schemars::JsonSchema;
+use serde::{Deserialize, Serialize};
+use std::sync::{Arc, RwLock};
+use tree_sitter::{Node, Tree};
+
+use crate::core::types::{AnalysisResult, FunctionInfo, Language};
+
+/// A custom metric value
+#[derive(Debug, Clone, PartialEq, Serialize, Deserialize, JsonSchema)]
+#[serde(untagged)]
+pub enum MetricValue {
+    Number(f64),
+    Text(String),
+}
+
+impl From<f64> for MetricValue {
+    fn from(value: f64) -> Self {
+        Self::Number(value)
+    }
+}
+
+impl From<u32> for MetricValue {
+    fn from(value: u32) -> Self {
+        Self::Number(value as f64)
+    }
+}
+
+impl From<usize> for MetricValue {
+    fn from(value: usize) -> Self {
+        Self::Number(value as f64)
+    }
+}
+
+impl From<String> for MetricValue {
+    fn from(value: String) -> Self {
+        Self::Text(value)
+    }
+}
+
+impl From<&str> for MetricValue {
+    fn from(value: &str) -> Self {
+        Self::Text(value.to_string())
+    }
+}
+
+/// A function as handed to plugins
+pub struct FunctionNode<'a> {
+    /// Declaration node (see the table in the module docs)
+    pub node: Node<'a>,
+    /// Full file source; node byte ranges index into it
+    pub source: &'a str,
+    pub language: Language,
+    /// What the analyzer extracted (name, parameters, complexity, lines, ...)
+    pub function: &'a FunctionInfo,
+}
+
+impl FunctionNode<'_> {
+    /// Source text of a node of this file
+    pub fn text(&self, node: Node) -> &str {
+        self.source.get(node.start_byte()..node.end_byte()).unwrap_or("")
+    }
+}
+
+/// A custom per-function metric
+pub trait MetricPlugin: Send + Sync {
+    /// Plugin name (part of the cache fingerprint)
+    fn name(&self) -> &str;
+    
+    /// Named values for one function; an empty list adds nothing
+    fn measure(&self, function: &FunctionNode) -> Vec<(String, MetricValue)>;
+}
+
+/// Registered plugins, in registration order
+static PLUGINS: RwLock<Vec<Arc<dyn MetricPlugin>>> = RwLock::new(Vec::new());
+
+/// Register a plugin for all later analyses in this process
+pub fn register_metric_plugin(plugin: impl MetricPlugin + 'static) {
+    PLUGINS.write().unwrap_or_else(|e| e.into_inner()).push(Arc::new(plugin));
+}
+
+/// Names of the registered plugins
+pub fn registered_plugins() -> Vec<String> {
+    PLUGINS.read().unwrap_or_else(|e| e.into_inner())
+        .iter()
+        .map(|plugin| plugin.name().to_string())
+        .collect()
+}
+
+/// Run the registered plugins over every function and method of a result
+pub fn apply_metric_plugins(tree: &Tree, source: &str, result: &mut AnalysisResult) {
+    let plugins = PLUGINS.read().unwrap_or_else(|e| e.into_inner()).clone();
+    if plugins.is_empty() {
+        return;
+    }
+    
+    let language = result.language;
+    let functions = result.functions.iter_mut()
+        .chain(result.classes.iter_mut().flat_map(|class| class.methods.iter_mut()));
+    for func in functions {
+        let Some(node) = function_node(tree, func) else { continue };
+        let context = FunctionNode { node, source, language, function: func };
+        let values: Vec<(String, MetricValue)> = plugins.iter()
+            .flat_map(|plugin| plugin.measure(&context))
+            .collect();
+        func.custom.extend(values);
+    }
+}
+
+/// Helper: Outermost node spanning exactly the function's recorded byte range
+pub(crate) fn function_node<'t>(tree: &'t Tree, func: &FunctionInfo) -> Option<Node<'t>> {
+    let (start, end) = (func.span.start_byte as usize, func.span.end_byte as usize);
+    if end <= start {
+        return None;
+    }
+    
+    let mut node = tree.root_node().descendant_for_byte_range(start, end)?;
+    if node.start_byte() != start || node.end_byte() != end {
+        return None;
+    }
+    while let Some(parent) = node.parent() {
+        if parent.start_byte() != start || parent.end_byte() != end {
+            break;
+        }
+        node = parent;
+    }
+    Some(node)
+}
+
+/// Example plugin: number of declared parameters (`parameter_count`)
+///
+/// Counts the declarations under the `parameters` field (`formal_parameters`,
+/// `parameter_list`, ...), each name of a multi-name Go declaration once;
+/// falls back to the parameters the analyzer extracted when the grammar has
+/// no such field.
+pub struct ParameterCountPlugin;
+
+impl MetricPlugin for ParameterCountPlugin {
+    fn name(&self) -> &str {
+        "parameter_count"
+    }
+    
+    fn measure(&self, function: &FunctionNode) -> Vec<(String, MetricValue)> {
+        let count = match function.node.child_by_field_name("parameters") {
+            Some(parameters) => {
+                let mut cursor = parameters.walk();
+                let declarations: Vec<Node> = parameters.named_children(&mut cursor)
+                    .filter(|child| !child.kind().contains("comment"))
+                    .collect();
+                // Go declares several names at once (`v, lo, hi int`)
+                declarations.iter()
+                    .map(|declaration| {
+                        let mut cursor = declaration.walk();
+                        declaration.children_by_field_name("name", &mut cursor).count().max(1)
+                    })
+                    .sum()
+            }
+            None => function.function.parameters.len(),
+        };
+        vec![("parameter_count".to_string(), count.into())]
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    #[test]
+    fn test_metric_value_json() {
+        let mut func = FunctionInfo::new("parse".to_string());
+        assert!(serde_json::to_value(&func).unwrap().get("custom").is_none());
+        
+        func.custom.insert("parameter_count".to_string(), 2u32.into());
+        func.custom.insert("owner".to_string(), "parsing-team".into());
+        let json = serde_json::to_value(&func).unwrap();
+        assert_eq!(json["custom"], serde_json::json!({ "owner": "parsing-team", "parameter_count": 2.0 }));
+        
+        let back: FunctionInfo = serde_json::from_value(json).unwrap();
+        assert_eq!(back.custom, func.custom);
+    }
+}
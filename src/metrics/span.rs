@@ -0,0 +1,54 @@
+//! 📐 Symbol spans
+//!
+//! Tree-sitter reports columns in UTF-8 bytes. Editors speaking LSP default
+//! to UTF-16 code units, so both are emitted and clients can use whichever
+//! position encoding they negotiated without re-reading the file.
+
+use tree_sitter::Node;
+
+use crate::core::types::Span;
+
+/// Byte range and UTF-8 / UTF-16 columns of a syntax node
+pub fn node_span(node: Node, source: &str) -> Span {
+    let start = node.start_position();
+    let end = node.end_position();
+    
+    Span {
+        start_byte: node.start_byte() as u32,
+        end_byte: node.end_byte() as u32,
+        start_col: start.column as u32,
+        end_col: end.column as u32,
+        start_col_utf16: utf16_column(source, node.start_byte(), start.column),
+        end_col_utf16: utf16_column(source, node.end_byte(), end.column),
+    }
+}
+
+/// Helper: UTF-16 length of the line prefix ending at `byte` (`column` bytes long)
+fn utf16_column(source: &str, byte: usize, column: usize) -> u32 {
+    let line_start = byte.saturating_sub(column);
+    source.get(line_start..byte)
+        .map(|prefix| prefix.encode_utf16().count() as u32)
+        .unwrap_or(column as u32)
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    
+    #[test]
+    fn test_utf16_column() {
+        let source = "fn a() {}\nlet s = \"猫\"; fn b() {}\n";
+        let line_start = source.find("let").unwrap();
+        let byte = source.find("fn b").unwrap();
+        let column = byte - line_start;
+        
+        // 猫 is 3 bytes in UTF-8 but a single UTF-16 code unit
+        assert_eq!(column, 15);
+        assert_eq!(utf16_column(source, byte, column), 13);
+        
+        // Astral characters take two UTF-16 code units
+        let source = "🐱 x";
+        assert_eq!(utf16_column(source, 5, 5), 3);
+        assert_eq!(utf16_column(source, 0, 0), 0);
+    }
+}
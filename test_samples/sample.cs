@@ -0,0 +1,44 @@
+using System;
+using System.Collections.Generic;
+using static System.Math;
+using Json = System.Text.Json;
+
+namespace Neko.Samples
+{
+    public interface IGreeter
+    {
+        string Greet(string name);
+    }
+
+    public class Greeter : IGreeter
+    {
+        private readonly string prefix;
+        public int Count { get; private set; }
+
+        public Greeter(string prefix)
+        {
+            this.prefix = prefix;
+        }
+
+        public string Greet(string name)
+        {
+            Count++;
+            return prefix + name;
+        }
+
+        public static async Task<int> RunAsync(List<string> names)
+        {
+            var greeter = new Greeter("neko: ");
+            foreach (var name in names)
+            {
+                if (name.Length > 0)
+                {
+                    Console.WriteLine(greeter.Greet(name));
+                }
+            }
+            return Max(names.Count, 0);
+        }
+    }
+
+    public record Point(int X, int Y);
+}
@@ -0,0 +1,103 @@
+// Widget-style class hierarchy for the counter screen
+import 'dart:async';
+import 'package:flutter/foundation.dart' as foundation show kDebugMode;
+import 'src/theme.dart' hide DarkTheme;
+
+mixin Diagnosticable {
+  String toStringShort() => runtimeType.toString();
+}
+
+abstract class Widget with Diagnosticable {
+  final Key? key;
+
+  const Widget({this.key});
+
+  Element createElement();
+}
+
+abstract class StatelessWidget extends Widget {
+  const StatelessWidget({super.key});
+
+  Widget build(BuildContext context);
+}
+
+abstract class StatefulWidget extends Widget implements Comparable<StatefulWidget> {
+  const StatefulWidget({super.key});
+
+  State createState();
+
+  @override
+  int compareTo(StatefulWidget other) => 0;
+}
+
+class Counter extends StatefulWidget {
+  static const int maxValue = 99;
+  static int instances = 0;
+
+  final int initial;
+  final int step;
+
+  const Counter({super.key, required this.initial, this.step = 1});
+
+  factory Counter.fromJson(Map<String, dynamic> json) {
+    instances++;
+    return Counter(initial: json['initial'] ?? 0);
+  }
+
+  @override
+  State createState() => _CounterState();
+}
+
+class _CounterState extends State<Counter> with SingleTickerProviderStateMixin, Diagnosticable {
+  int _value = 0;
+
+  int get value => _value;
+
+  set value(int next) {
+    _value = clamp(next, min: 0, max: Counter.maxValue);
+  }
+
+  void increment([int by = 1]) {
+    if (by <= 0) {
+      return;
+    } else if (by > widget.step && foundation.kDebugMode) {
+      print('large step');
+    }
+    setState(() {
+      value = _value + by;
+    });
+    this.notify();
+  }
+
+  void notify() {}
+
+  Future<void> _load() async {
+    await Future.delayed(const Duration(milliseconds: 10));
+  }
+}
+
+enum Status {
+  idle,
+  running,
+  done;
+
+  bool get isFinal => this == Status.done;
+}
+
+extension StatusLabel on Status {
+  String label() => name.toUpperCase();
+}
+
+extension type UserId(int value) {
+  bool get isValid => value > 0;
+}
+
+int clamp(int value, {required int min, int max = 100}) {
+  if (value < min) return min;
+  return value > max ? max : value;
+}
+
+void main() {
+  final counter = Counter.fromJson({'initial': 3});
+  print(counter.initial);
+}
@@ -0,0 +1,48 @@
+// Package main is a small Go sample used by the analyzer tests.
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Processor transforms a batch of items.
+type Processor interface {
+	ProcessData(data []string) []string
+}
+
+// DataProcessor is a concurrency-safe Processor implementation.
+type DataProcessor struct {
+	mu     sync.Mutex
+	prefix string
+	count  int
+}
+
+// NewDataProcessor creates a DataProcessor with the given prefix.
+func NewDataProcessor(prefix string) *DataProcessor {
+	return &DataProcessor{prefix: prefix}
+}
+
+// ProcessData prefixes every item in data.
+func (dp *DataProcessor) ProcessData(data []string) []string {
+	dp.mu.Lock()
+	defer dp.mu.Unlock()
+
+	results := make([]string, 0, len(data))
+	for _, item := range data {
+		results = append(results, dp.processItem(item))
+	}
+	return results
+}
+
+func (dp *DataProcessor) processItem(item string) string {
+	dp.count++
+	return dp.prefix + strings.TrimSpace(item)
+}
+
+func main() {
+	processor := NewDataProcessor("neko: ")
+	results := processor.ProcessData([]string{"a", "b"})
+	fmt.Println(results)
+}
@@ -0,0 +1,88 @@
+// Small Java sample used by the analyzer tests.
+package com.example.neko;
+
+import java.util.ArrayList;
+import java.util.List;
+import static java.lang.Math.max;
+
+/** Transforms a batch of items. */
+public interface Processor {
+    List<String> processData(List<String> data);
+
+    default int priority() {
+        return 0;
+    }
+}
+
+@Deprecated
+public abstract class BaseProcessor implements Processor {
+    protected int count;
+
+    protected abstract String processItem(String item);
+
+    public void reset() {
+        count = 0;
+    }
+}
+
+public final class DataProcessor extends BaseProcessor {
+    private final String prefix;
+    private static int instances = 0;
+
+    public DataProcessor(String prefix) {
+        this.prefix = prefix;
+        instances++;
+    }
+
+    @Override
+    public List<String> processData(List<String> data) {
+        List<String> results = new ArrayList<>();
+        for (String item : data) {
+            if (item != null && !item.isEmpty()) {
+                results.add(this.processItem(item));
+            }
+        }
+        return results;
+    }
+
+    @Override
+    protected String processItem(String item) {
+        super.reset();
+        count = max(count, 1);
+        return prefix + item;
+    }
+
+    public static DataProcessor create(String... prefixes) {
+        return new DataProcessor(String.join("-", prefixes));
+    }
+
+    static class Stats {
+        private int processed;
+
+        int processed() {
+            return processed;
+        }
+    }
+
+    class Cursor {
+        void advance() {
+            Helpers.log("advance");
+        }
+    }
+
+    enum Mode {
+        FAST, SAFE;
+
+        boolean isFast() {
+            return this == FAST;
+        }
+    }
+
+    record Pair(String key, int value) {
+    }
+
+    public static void main(String[] args) {
+        DataProcessor processor = DataProcessor.create("neko");
+        System.out.println(processor.processData(List.of(args)));
+    }
+}
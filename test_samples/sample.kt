@@ -0,0 +1,77 @@
+package com.example.shapes
+
+import kotlin.math.sqrt
+import com.example.util.Logger as Log
+import com.example.model.*
+
+/** Anything with an area */
+interface Shape {
+    val name: String
+    fun area(): Double
+    fun describe(): String = "$name with area ${area()}"
+}
+
+data class Point(val x: Double, var y: Double, label: String)
+
+sealed class Figure(open val origin: Point) : Shape
+
+class Circle(override val origin: Point, private val radius: Double) : Figure(origin), Comparable<Circle> {
+    override val name: String = "circle"
+    private var scale = 1.0
+
+    override fun area(): Double = Math.PI * radius * radius * scale
+
+    override fun compareTo(other: Circle): Int {
+        if (radius > other.radius) {
+            return 1
+        } else if (radius < other.radius) {
+            return -1
+        }
+        return 0
+    }
+
+    companion object {
+        const val UNIT = 1.0
+        fun unit(): Circle = Circle(Point(0.0, 0.0, "origin"), UNIT)
+    }
+
+    inner class Handle(val index: Int)
+}
+
+enum class Color {
+    RED, GREEN, BLUE
+}
+
+object Registry {
+    private val shapes = mutableListOf<Shape>()
+
+    fun register(shape: Shape) {
+        shapes.add(shape)
+    }
+}
+
+val DEFAULT_ORIGIN = Point(0.0, 0.0, "origin")
+
+fun Point.distanceTo(other: Point): Double {
+    val dx = x - other.x
+    val dy = y - other.y
+    return sqrt(dx * dx + dy * dy)
+}
+
+fun Shape?.describeOrEmpty(): String = this?.describe() ?: ""
+
+internal suspend fun classify(shapes: List<Shape>, threshold: Double): Map<String, Int> {
+    val counts = mutableMapOf<String, Int>()
+    shapes.forEach { shape ->
+        val bucket = when {
+            shape.area() > threshold && threshold > 0 -> "large"
+            shape.area() > threshold / 2 -> "medium"
+            else -> "small"
+        }
+        counts[bucket] = (counts[bucket] ?: 0) + 1
+    }
+    run {
+        Log.info("classified")
+    }
+    return counts
+}
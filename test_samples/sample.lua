@@ -0,0 +1,59 @@
+-- Inventory module for the scripting layer
+local json = require("cjson")
+local log = require "engine.log"
+
+local Inventory = {
+    version = "1.2",
+    max_slots = 32,
+}
+Inventory.__index = Inventory
+
+local DEFAULT_WEIGHT = 1
+
+local function clamp(value, low, high)
+    if value < low then
+        return low
+    elseif value > high then
+        return high
+    end
+    return value
+end
+
+function Inventory.new(owner, ...)
+    local self = setmetatable({}, Inventory)
+    self.owner = owner
+    self.items = {}
+    return self
+end
+
+function Inventory:add(item, count)
+    count = clamp(count or 1, 1, self.max_slots)
+    for i = 1, count do
+        if item.stackable and #self.items < self.max_slots then
+            table.insert(self.items, item)
+        end
+    end
+    self:notify("add", item)
+    log.info("added " .. item.name)
+end
+
+function Inventory:notify(event, item)
+    -- TODO: route through the event bus
+    return json.encode({ event = event, item = item.name })
+end
+
+Inventory.weight = function(item)
+    return item.weight or DEFAULT_WEIGHT
+end
+
+function debug_dump(inventory)
+    print(json.encode(inventory.items))
+end
+
+local Helpers = {
+    describe = function(item)
+        return item.name
+    end,
+}
+
+return Inventory
@@ -0,0 +1,141 @@
+<?php
+
+declare(strict_types=1);
+
+namespace App\Models;
+
+use App\Contracts\Repository as Repo;
+use Psr\Log\LoggerInterface;
+use App\Support\{Cache, Clock as SystemClock};
+use function App\Support\slugify;
+use const App\Support\VERSION;
+
+interface Identifiable extends \JsonSerializable, Repo
+{
+    public function id(): int;
+}
+
+trait HasTimestamps
+{
+    protected ?int $createdAt = null;
+
+    public function touch(): void
+    {
+        $this->createdAt = SystemClock::now();
+    }
+}
+
+trait Loggable
+{
+    public function log(string $message, string ...$context): void
+    {
+        // TODO: route through the logger
+        echo $message;
+    }
+}
+
+abstract class Model implements Identifiable
+{
+    public const TABLE = 'models';
+
+    protected static int $count = 0;
+
+    abstract protected function table(): string;
+
+    public function save(): bool
+    {
+        static::$count++;
+        return $this->validate() && $this->persist();
+    }
+
+    private function persist(): bool
+    {
+        return true;
+    }
+
+    protected function validate(): bool
+    {
+        return true;
+    }
+}
+
+final class User extends Model
+{
+    use HasTimestamps, Loggable;
+
+    private array $roles = [];
+
+    public function __construct(private string $name, public readonly int $age = 0)
+    {
+        $this->touch();
+    }
+
+    public function id(): int
+    {
+        return self::hash($this->name);
+    }
+
+    protected function table(): string
+    {
+        return parent::TABLE;
+    }
+
+    public function save(): bool
+    {
+        if ($this->age < 0) {
+            return false;
+        }
+        foreach ($this->roles as $role) {
+            if ($role === 'admin' || $role === 'root') {
+                $this->log("saving admin", $role);
+            }
+        }
+        return parent::save();
+    }
+
+    public static function create(string $name): static
+    {
+        $user = new static($name);
+        return static::register($user) ?? new User($name);
+    }
+
+    private static function register(User $user): ?User
+    {
+        return $user;
+    }
+
+    private static function hash(string $value): int
+    {
+        return crc32(slugify($value));
+    }
+
+    public function jsonSerialize(): mixed
+    {
+        return ['name' => $this->name];
+    }
+}
+
+enum Status: string implements \JsonSerializable
+{
+    case Active = 'active';
+    case Banned = 'banned';
+
+    public function label(): string
+    {
+        return match ($this) {
+            Status::Active => 'Active',
+            Status::Banned => 'Banned',
+        };
+    }
+
+    public function jsonSerialize(): mixed
+    {
+        return $this->value;
+    }
+}
+
+function format_user(User $user, ?LoggerInterface $logger = null): string
+{
+    $logger?->info('formatting');
+    return sprintf('%s (%d)', $user->id(), User::create('x')->id());
+}
@@ -0,0 +1,38 @@
+#!/usr/bin/env ruby
+# Small Ruby sample used by the analyzer tests.
+
+require 'set'
+require_relative 'helpers'
+
+module Neko
+  # Processes batches of items.
+  class DataProcessor < BaseProcessor
+    attr_accessor :prefix, :count
+    attr_reader :seen
+
+    def self.create(prefix)
+      new(prefix)
+    end
+
+    def initialize(prefix)
+      @prefix = prefix
+      @count = 0
+      @seen = Set.new
+    end
+
+    def process_data(data)
+      data.map do |item|
+        process_item(item)
+      end
+    end
+
+    def process_item(item)
+      @count += 1
+      return item if item.nil? || item.empty?
+      "#{@prefix}#{item.strip}"
+    end
+  end
+end
+
+processor = Neko::DataProcessor.create("neko: ")
+puts processor.process_data(["a", "b"])
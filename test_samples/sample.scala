@@ -0,0 +1,69 @@
+package com.example.spark
+
+import org.apache.spark.sql.{DataFrame, SparkSession => Session}
+import org.apache.spark.sql.functions._
+import scala.collection.mutable
+
+/** Anything that rewrites a dataset */
+trait Transformer[T] {
+  def name: String
+  def transform(input: T): T
+}
+
+trait Logging {
+  protected val logPrefix: String = "job"
+  def log(message: String): Unit = println(s"$logPrefix: $message")
+}
+
+abstract class Job(val id: String) extends Logging {
+  def run(spark: Session): Unit
+}
+
+case class Record(key: String, value: Int)
+
+class WordCount(id: String, minCount: Int = 1) extends Job(id) with Transformer[DataFrame] with Serializable {
+  private var processed: Long = 0L
+  lazy val threshold = minCount * 2
+
+  override def name: String = "word-count"
+
+  override def transform(input: DataFrame): DataFrame = {
+    val counts = input.groupBy("word").count()
+    counts.filter(col("count") >= minCount)
+  }
+
+  def run(spark: Session): Unit = {
+    val df = spark.read.text("input.txt")
+    val result = transform(df)
+    processed += result.count()
+    this.log("done")
+  }
+
+  def classify(count: Int, verbose: Boolean): String = count match {
+    case 0 => "none"
+    case n if n < minCount => "rare"
+    case n if verbose && n > threshold => "frequent"
+    case _ => "common"
+  }
+}
+
+object WordCount {
+  val DefaultMin = 1
+
+  def apply(id: String): WordCount = new WordCount(id, DefaultMin)
+
+  def keys(records: Seq[Record]): Seq[String] = records map keyOf
+
+  def banner(): String = Defaults.describe()
+
+  private def keyOf(record: Record): String = record.key
+
+  object Defaults {
+    val partitions = 8
+    def describe(): String = s"partitions=$partitions"
+  }
+}
+
+enum Mode {
+  case Batch, Streaming
+}
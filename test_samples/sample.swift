@@ -0,0 +1,114 @@
+import Foundation
+import struct Shapes.Point
+
+/// Anything that can draw itself
+protocol Drawable {
+    var title: String { get }
+    var scale: Double { get set }
+    func draw() -> String
+}
+
+protocol Sizable: Drawable {
+    func area() -> Double
+}
+
+class Shape {
+    let id: Int
+
+    init(id: Int) {
+        self.id = id
+    }
+
+    deinit {
+        print("released")
+    }
+}
+
+public final class Circle: Shape, Sizable {
+    public static let unit = 1.0
+    private(set) var scale: Double = 1.0
+    var radius: Double
+
+    public var title: String {
+        return "circle"
+    }
+
+    var diameter: Double {
+        get { radius * 2 }
+        set { radius = newValue / 2 }
+    }
+
+    init(id: Int, radius: Double) {
+        self.radius = radius
+        super.init(id: id)
+    }
+
+    public func draw() -> String {
+        return title
+    }
+
+    func area() -> Double {
+        return Double.pi * radius * radius
+    }
+
+    class func make(_ values: Double...) -> Circle {
+        return Circle(id: 0, radius: values.first ?? Circle.unit)
+    }
+
+    func compare(to other: Circle) -> Int {
+        if radius > other.radius {
+            return 1
+        } else if radius < other.radius {
+            return -1
+        }
+        return 0
+    }
+}
+
+struct Tag: Hashable {
+    var name: String
+
+    static func parse(_ text: String) throws -> Tag {
+        guard !text.isEmpty && text.count < 32 else {
+            throw TagError.invalid
+        }
+        return Tag(name: text)
+    }
+
+    mutating func rename(to name: String) {
+        self.name = name
+    }
+}
+
+enum Direction: String, CaseIterable {
+    case north, south
+    case east = "E"
+
+    struct Offset {
+        var dx: Int
+    }
+}
+
+enum TagError: Error {
+    case invalid
+    case tooLong(Int)
+}
+
+extension Tag: Drawable {
+    var title: String { name }
+    var scale: Double {
+        get { 1.0 }
+        set { }
+    }
+
+    func draw() -> String {
+        return "#" + name
+    }
+}
+
+func render(_ items: [Drawable], separator: String = ", ") async -> String {
+    let parts = items.map { item in
+        item.draw()
+    }
+    return parts.joined(separator: separator)
+}
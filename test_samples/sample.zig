@@ -0,0 +1,85 @@
+const std = @import("std");
+const geo = @import("geo.zig");
+const print = @import("std").debug.print;
+
+pub const Point = struct {
+    x: f32,
+    y: f32 = 0,
+
+    const Self = @This();
+
+    pub fn init(x: f32, y: f32) Point {
+        return .{ .x = x, .y = y };
+    }
+
+    pub fn length(self: Self) f32 {
+        return std.math.sqrt(self.x * self.x + self.y * self.y);
+    }
+
+    fn scale(self: *Self, factor: f32) void {
+        self.x *= factor;
+        self.y *= factor;
+    }
+
+    pub const Polar = struct {
+        radius: f32,
+        angle: f32,
+    };
+};
+
+const Shape = union(enum) {
+    circle: f32,
+    square: f32,
+
+    pub fn area(shape: Shape) f32 {
+        return switch (shape) {
+            .circle => |r| geo.circleArea(r),
+            .square => |s| s * s,
+        };
+    }
+};
+
+pub const Color = enum {
+    red,
+    green,
+    blue,
+};
+
+pub fn List(comptime T: type) type {
+    return struct {
+        items: []T,
+
+        pub fn first(self: @This()) ?T {
+            if (self.items.len == 0) return null;
+            return self.items[0];
+        }
+    };
+}
+
+pub fn max(comptime T: type, a: T, b: T) T {
+    if (a > b and a != 0) {
+        return a;
+    } else {
+        return b;
+    }
+}
+
+fn helper(items: []const f32) f32 {
+    var total: f32 = 0;
+    for (items) |item| {
+        if (item > 0) {
+            total += item;
+        }
+    }
+    return total;
+}
+
+pub fn main() void {
+    var p = Point.init(3, 4);
+    p.scale(2);
+    const shape = Shape{ .circle = 1 };
+    print("{d} {d}\n", .{ p.length(), shape.area() });
+    _ = geo.Rect.init(1, 2);
+    _ = helper(&[_]f32{ 1, 2 });
+    _ = max(f32, 1, 2);
+}
@@ -0,0 +1,167 @@
+//! Tests for decorators, annotations, attributes and struct tags (`annotations`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::csharp::TreeSitterCSharpAnalyzer;
+    use nekocode_core::analyzers::go::TreeSitterGoAnalyzer;
+    use nekocode_core::analyzers::java::TreeSitterJavaAnalyzer;
+    use nekocode_core::analyzers::python::TreeSitterPythonAnalyzer;
+    use nekocode_core::analyzers::rust::TreeSitterRustAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, Annotation, ClassInfo, FunctionInfo};
+    
+    fn function<'a>(result: &'a AnalysisResult, name: &str) -> &'a FunctionInfo {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+    
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("class {} not found", name))
+    }
+    
+    /// (name, arguments) pairs
+    fn pairs(annotations: &[Annotation]) -> Vec<(&str, &str)> {
+        annotations.iter().map(|a| (a.name.as_str(), a.arguments.as_str())).collect()
+    }
+    
+    #[test]
+    fn test_arguments_lose_their_parentheses() {
+        assert_eq!(Annotation::new("route", "(\"/users\", methods=[\"GET\"])", 1).arguments, "\"/users\", methods=[\"GET\"]");
+        assert_eq!(Annotation::new("json", "name,omitempty", 1).arguments, "name,omitempty");
+        assert_eq!(Annotation::new("Override", "", 1).arguments, "");
+    }
+    
+    #[tokio::test]
+    async fn test_python_decorators() {
+        let source = r#"@dataclass(frozen=True)
+class Point:
+    x: int
+
+    @property
+    def norm(self):
+        return self.x
+
+@app.route("/users", methods=["GET"])
+@login_required
+def users():
+    return []
+"#;
+        let mut analyzer = TreeSitterPythonAnalyzer::new().unwrap();
+        let result = analyzer.analyze(source, "app.py").await.unwrap();
+        
+        let users = function(&result, "users");
+        assert_eq!(pairs(&users.annotations), vec![("app.route", "\"/users\", methods=[\"GET\"]"), ("login_required", "")]);
+        assert_eq!(users.annotations[0].line_number, 9);
+        
+        let point = class(&result, "Point");
+        assert_eq!(pairs(&point.annotations), vec![("dataclass", "frozen=True")]);
+        let norm = point.methods.iter().find(|m| m.name == "norm").unwrap();
+        assert_eq!(pairs(&norm.annotations), vec![("property", "")]);
+    }
+    
+    #[tokio::test]
+    async fn test_java_annotations() {
+        let source = r#"@RestController
+public class UserController {
+    @Autowired
+    private UserService service;
+
+    @GetMapping(value = "/users/{id}")
+    @Override
+    public User get(long id) { return service.find(id); }
+}
+"#;
+        let mut analyzer = TreeSitterJavaAnalyzer::new().unwrap();
+        let result = analyzer.analyze(source, "UserController.java").await.unwrap();
+        
+        let controller = class(&result, "UserController");
+        assert_eq!(pairs(&controller.annotations), vec![("RestController", "")]);
+        assert_eq!(pairs(&controller.member_variables[0].annotations), vec![("Autowired", "")]);
+        let get = controller.methods.iter().find(|m| m.name == "get").unwrap();
+        assert_eq!(pairs(&get.annotations), vec![("GetMapping", "value = \"/users/{id}\""), ("Override", "")]);
+        // The name list stays in metadata
+        assert_eq!(get.metadata.get("annotations").map(String::as_str), Some("GetMapping,Override"));
+    }
+    
+    #[tokio::test]
+    async fn test_csharp_attributes() {
+        let source = r#"[ApiController, Route("api/[controller]")]
+public class UsersController
+{
+    [JsonIgnore]
+    private int cache;
+
+    [HttpGet("{id}")]
+    [Authorize]
+    public User Get(int id) { return null; }
+}
+"#;
+        let mut analyzer = TreeSitterCSharpAnalyzer::new().unwrap();
+        let result = analyzer.analyze(source, "UsersController.cs").await.unwrap();
+        
+        let controller = class(&result, "UsersController");
+        assert_eq!(pairs(&controller.annotations), vec![("ApiController", ""), ("Route", "\"api/[controller]\"")]);
+        assert_eq!(pairs(&controller.member_variables[0].annotations), vec![("JsonIgnore", "")]);
+        let get = controller.methods.iter().find(|m| m.name == "Get").unwrap();
+        assert_eq!(pairs(&get.annotations), vec![("HttpGet", "\"{id}\""), ("Authorize", "")]);
+        assert_eq!(get.annotations[1].line_number, 8);
+    }
+    
+    #[tokio::test]
+    async fn test_rust_attributes() {
+        let source = r#"/// A point
+#[derive(Debug, Clone)]
+#[serde(rename_all = "camelCase")]
+pub struct Point {
+    x: i32,
+}
+
+#[inline]
+/// Doc comment between attributes
+#[must_use]
+fn double(x: i32) -> i32 {
+    x * 2
+}
+
+fn plain() {}
+"#;
+        let mut analyzer = TreeSitterRustAnalyzer::new().unwrap();
+        let result = analyzer.analyze(source, "point.rs").await.unwrap();
+        
+        assert_eq!(pairs(&class(&result, "Point").annotations), vec![("derive", "Debug, Clone"), ("serde", "rename_all = \"camelCase\"")]);
+        assert_eq!(pairs(&function(&result, "double").annotations), vec![("inline", ""), ("must_use", "")]);
+        assert!(function(&result, "plain").annotations.is_empty());
+    }
+    
+    #[tokio::test]
+    async fn test_go_struct_tags() {
+        let source = "package api
+
+type User struct {
+\tID    int    `json:\"id\" db:\"user_id\"`
+\tName  string `json:\"name,omitempty\"`
+\tX, Y  int
+\tnotes string \"json:\\\"-\\\"\"
+}
+";
+        let mut analyzer = TreeSitterGoAnalyzer::new().unwrap();
+        let result = analyzer.analyze(source, "user.go").await.unwrap();
+        
+        let user = class(&result, "User");
+        let names: Vec<&str> = user.member_variables.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(names, vec!["ID", "Name", "X", "Y", "notes"]);
+        
+        let field = |name: &str| user.member_variables.iter().find(|m| m.name == name).unwrap();
+        assert_eq!(pairs(&field("ID").annotations), vec![("json", "id"), ("db", "user_id")]);
+        assert_eq!(pairs(&field("Name").annotations), vec![("json", "name,omitempty")]);
+        assert_eq!(field("Name").annotations[0].line_number, 5);
+        assert!(field("X").annotations.is_empty());
+        assert_eq!(field("Y").var_type, "int");
+        assert_eq!(pairs(&field("notes").annotations), vec![("json", "-")]);
+        assert_eq!(field("ID").access_modifier, "public");
+        assert_eq!(field("notes").access_modifier, "private");
+    }
+}
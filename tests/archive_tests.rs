@@ -0,0 +1,81 @@
+//! Tests for analyzing .zip / .tar.gz archives in memory
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use std::fs::File;
+    use std::io::Write;
+    use std::path::Path;
+    use tempfile::TempDir;
+
+    /// Go entries calling across files, plus a binary blob and an unsupported file
+    const ENTRIES: &[(&str, &[u8])] = &[
+        ("pkg/util.go", b"package pkg\n\nfunc helper(x int) int {\n\treturn x + 1\n}\n"),
+        ("pkg/main.go", b"package pkg\n\nfunc run() int {\n\treturn helper(1)\n}\n"),
+        ("pkg/blob.go", b"\x00\x01\x02binary"),
+        ("pkg/README.md", b"# Readme\n"),
+        ("pkg/util_test.go", b"package pkg\n\nfunc TestHelper() {}\n"),
+    ];
+
+    fn write_tar_gz(path: &Path) {
+        let encoder = flate2::write::GzEncoder::new(File::create(path).unwrap(), flate2::Compression::default());
+        let mut builder = tar::Builder::new(encoder);
+        for (name, content) in ENTRIES {
+            let mut header = tar::Header::new_gnu();
+            header.set_size(content.len() as u64);
+            header.set_mode(0o644);
+            header.set_cksum();
+            builder.append_data(&mut header, name, *content).unwrap();
+        }
+        builder.into_inner().unwrap().finish().unwrap();
+    }
+
+    fn write_zip(path: &Path) {
+        let mut writer = zip::ZipWriter::new(File::create(path).unwrap());
+        for (name, content) in ENTRIES {
+            writer.start_file(*name, zip::write::SimpleFileOptions::default()).unwrap();
+            writer.write_all(content).unwrap();
+        }
+        writer.finish().unwrap();
+    }
+
+    async fn check_archive(archive: &Path) {
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(archive, false).await.unwrap();
+
+        assert_eq!(analysis.directory_path, archive);
+        let paths: Vec<String> = analysis.files.iter().map(|f| f.file_info.path.display().to_string()).collect();
+        assert_eq!(paths, vec![
+            format!("{}!pkg/util.go", archive.display()),
+            format!("{}!pkg/main.go", archive.display()),
+        ]);
+        assert!(analysis.errors.is_empty(), "{:?}", analysis.errors);
+        assert_eq!(analysis.warnings.len(), 1);
+        assert!(analysis.warnings[0].file_path.display().to_string().ends_with("!pkg/blob.go"));
+
+        // The archive is one project: `run` in main.go resolves `helper` in util.go
+        let helper = analysis.files[0].functions.iter().find(|f| f.name == "helper").unwrap();
+        assert_eq!(helper.fan_in, 1);
+
+        let mut session = AnalysisSession::new();
+        let streamed = session.analyze_path_streaming(archive, false, |_| Ok(())).await.unwrap();
+        assert_eq!(streamed.summary.total_files, 2);
+        assert_eq!(streamed.warnings.len(), 1);
+    }
+
+    #[tokio::test]
+    async fn test_tar_gz_archive() {
+        let temp_dir = TempDir::new().unwrap();
+        let archive = temp_dir.path().join("src.tar.gz");
+        write_tar_gz(&archive);
+        check_archive(&archive).await;
+    }
+
+    #[tokio::test]
+    async fn test_zip_archive() {
+        let temp_dir = TempDir::new().unwrap();
+        let archive = temp_dir.path().join("src.zip");
+        write_zip(&archive);
+        check_archive(&archive).await;
+    }
+}
@@ -0,0 +1,63 @@
+//! Tests for the content-keyed analysis cache (`analyze --cache`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::{AnalysisConfig, DirectoryAnalysis, FunctionInfo};
+    use std::path::Path;
+    use tempfile::TempDir;
+    
+    const MODULE: &str = "def helper():\n    return 1\n";
+    
+    /// `a/util.py` and `b/util.py` with identical content, in packages `a` and `b`
+    fn project() -> TempDir {
+        let dir = TempDir::new().unwrap();
+        for package in ["a", "b"] {
+            let package_dir = dir.path().join("src").join(package);
+            std::fs::create_dir_all(&package_dir).unwrap();
+            std::fs::write(package_dir.join("__init__.py"), "").unwrap();
+            std::fs::write(package_dir.join("util.py"), MODULE).unwrap();
+        }
+        dir
+    }
+    
+    async fn analyze(root: &Path) -> DirectoryAnalysis {
+        let mut config = AnalysisConfig::default();
+        config.cache_enabled = true;
+        config.cache_dir = Some(root.join("cache"));
+        let mut session = AnalysisSession::with_config(config);
+        session.analyze_path(&root.join("src"), false).await.unwrap()
+    }
+    
+    fn helper<'a>(analysis: &'a DirectoryAnalysis, package: &str) -> &'a FunctionInfo {
+        analysis.files.iter()
+            .find(|f| f.file_info.path.ends_with(Path::new(package).join("util.py")))
+            .and_then(|f| f.functions.iter().find(|func| func.name == "helper"))
+            .unwrap_or_else(|| panic!("helper in {} not found", package))
+    }
+    
+    #[tokio::test]
+    async fn test_cache_hit_keeps_path_namespace() {
+        let dir = project();
+        let first = analyze(dir.path()).await;
+        // Every file is a cache hit now, both util.py from the same entry
+        let second = analyze(dir.path()).await;
+        
+        for analysis in [&first, &second] {
+            assert_eq!(helper(analysis, "a").namespace.as_deref(), Some("a.util"));
+            assert_eq!(helper(analysis, "b").namespace.as_deref(), Some("b.util"));
+        }
+    }
+    
+    /// Stable ids include the namespace, so the copies stay different symbols
+    #[tokio::test]
+    async fn test_cache_hit_keeps_stable_ids_apart() {
+        let dir = project();
+        let first = analyze(dir.path()).await;
+        let second = analyze(dir.path()).await;
+        
+        assert_ne!(helper(&second, "a").stable_id, helper(&second, "b").stable_id);
+        assert_eq!(helper(&first, "a").stable_id, helper(&second, "a").stable_id);
+        assert_eq!(helper(&first, "b").stable_id, helper(&second, "b").stable_id);
+    }
+}
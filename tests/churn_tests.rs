@@ -0,0 +1,110 @@
+//! Tests for function churn from git history (`analyze --with-churn`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::churn::annotate_churn;
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::{DirectoryAnalysis, FunctionInfo};
+    use std::path::Path;
+    use std::process::Command;
+    use tempfile::TempDir;
+    
+    const FIRST: &str = "package pkg
+
+func a(x int) int {
+\treturn x
+}
+
+func b(x int) int {
+\tif x > 0 {
+\t\treturn 1
+\t}
+\treturn 0
+}
+";
+
+    fn git(dir: &Path, args: &[&str]) {
+        let output = Command::new("git")
+            .args(["-c", "user.name=test", "-c", "user.email=test@example.com"])
+            .args(args)
+            .current_dir(dir)
+            .output()
+            .unwrap();
+        assert!(output.status.success(), "git {:?}: {}", args, String::from_utf8_lossy(&output.stderr));
+    }
+    
+    fn commit(root: &Path, content: &str, message: &str) {
+        std::fs::write(root.join("pkg/code.go"), content).unwrap();
+        git(root, &["add", "."]);
+        git(root, &["commit", "-q", "-m", message]);
+    }
+    
+    /// Three commits: both functions added; lines inserted above `a` and `b`
+    /// edited; `b` edited again
+    fn repository() -> TempDir {
+        let dir = TempDir::new().unwrap();
+        let root = dir.path();
+        git(root, &["init", "-q"]);
+        std::fs::create_dir(root.join("pkg")).unwrap();
+        
+        commit(root, FIRST, "first");
+        let shifted = FIRST.replacen("package pkg\n", "package pkg\n\n// Limit is the cap\nvar Limit = 1\n", 1);
+        commit(root, &shifted.replace("return 1", "return 2"), "second");
+        commit(root, &shifted.replace("return 1", "return 2").replace("return 0", "return -1"), "third");
+        dir
+    }
+    
+    fn function<'a>(analysis: &'a DirectoryAnalysis, name: &str) -> &'a FunctionInfo {
+        analysis.files.iter()
+            .flat_map(|f| f.functions.iter())
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+    
+    #[tokio::test]
+    async fn test_churn_follows_shifted_lines() {
+        let dir = repository();
+        std::fs::write(dir.path().join("pkg/new.go"), "package pkg\n\nfunc fresh() {}\n").unwrap();
+        
+        let mut session = AnalysisSession::new();
+        let mut analysis = session.analyze_path(&dir.path().join("pkg"), false).await.unwrap();
+        annotate_churn(&mut analysis, None).unwrap();
+        
+        // Moving down by three lines is not a change to `a`
+        let a = function(&analysis, "a");
+        assert_eq!(a.start_line, 6);
+        assert_eq!((a.churn, a.risk), (Some(1), Some(1)));
+        
+        let b = function(&analysis, "b");
+        assert_eq!(b.complexity.cyclomatic_complexity, 2);
+        assert_eq!((b.churn, b.risk), (Some(3), Some(6)));
+        
+        // Untracked
+        assert_eq!(function(&analysis, "fresh").churn, None);
+    }
+    
+    #[tokio::test]
+    async fn test_churn_window() {
+        let dir = repository();
+        let mut session = AnalysisSession::new();
+        let mut analysis = session.analyze_path(&dir.path().join("pkg"), false).await.unwrap();
+        
+        // No commit is newer than a date in the future
+        annotate_churn(&mut analysis, Some("2090-01-01")).unwrap();
+        assert_eq!(function(&analysis, "b").churn, Some(0));
+        assert_eq!(function(&analysis, "b").risk, Some(0));
+        
+        annotate_churn(&mut analysis, Some("2000-01-01")).unwrap();
+        assert_eq!(function(&analysis, "b").churn, Some(3));
+    }
+    
+    #[tokio::test]
+    async fn test_churn_outside_a_repository() {
+        let dir = TempDir::new().unwrap();
+        std::fs::write(dir.path().join("main.go"), "package main\n\nfunc main() {}\n").unwrap();
+        
+        let mut session = AnalysisSession::new();
+        let mut analysis = session.analyze_path(dir.path(), false).await.unwrap();
+        assert!(annotate_churn(&mut analysis, None).is_err());
+    }
+}
@@ -0,0 +1,168 @@
+//! Tests for the tree-sitter based C++ analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::cpp::{link_declarations, TreeSitterCppAnalyzer};
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, TypeParameter};
+    
+    const HEADER: &str = r#"#ifndef GEO_SHAPE_HPP
+#define GEO_SHAPE_HPP
+
+namespace geo {
+
+template <typename T, int N = 2>
+class Shape {
+public:
+    Shape(T scale);
+    ~Shape();
+    double area() const;
+    bool operator==(const Shape& other) const;
+    explicit operator bool() const { return true; }
+    T scale() const { return scale_; }
+private:
+    T scale_;
+};
+
+int count(const char* label, int limit = 10);
+
+}  // namespace geo
+
+#endif
+"#;
+    
+    const SOURCE: &str = r#"#include "shape.hpp"
+
+namespace geo {
+
+int count(const char* name, int limit) {
+    return limit;
+}
+
+}
+
+namespace geo::detail {
+void log(int level) {}
+}
+
+double geo::Shape::area() const {
+    geo::detail::log(1);
+    this->scale();
+    return 0.0;
+}
+
+void draw(geo::Shape* shapes, Canvas& canvas) {
+    shapes->area();
+    canvas.paint();
+    std::sort(shapes, shapes);
+    Registry::instance();
+    helper();
+}
+"#;
+    
+    async fn analyze(content: &str, filename: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterCppAnalyzer::new().unwrap();
+        analyzer.analyze(content, filename).await.unwrap()
+    }
+    
+    fn function<'a>(functions: &'a [FunctionInfo], name: &str) -> &'a FunctionInfo {
+        functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+    
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("class {} not found", name))
+    }
+    
+    fn meta<'a>(func: &'a FunctionInfo, key: &str) -> Option<&'a str> {
+        func.metadata.get(key).map(String::as_str)
+    }
+    
+    #[tokio::test]
+    async fn test_templates_and_namespaces() {
+        let result = analyze(HEADER, "shape.hpp").await;
+    
+        let shape = class(&result, "Shape");
+        assert_eq!(shape.metadata.get("namespace").map(String::as_str), Some("geo"));
+        assert_eq!(shape.type_params, vec![
+            TypeParameter { name: "T".to_string(), constraint: String::new() },
+            TypeParameter { name: "N".to_string(), constraint: "int".to_string() },
+        ]);
+    
+        // Inline definitions are methods only; prototypes are declarations
+        let names: Vec<&str> = shape.methods.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(names, vec!["operator bool", "scale"]);
+        assert_eq!(meta(&shape.methods[0], "type"), Some("operator"));
+        assert_eq!(meta(&shape.methods[1], "qualified_name"), Some("geo::Shape::scale"));
+        assert!(result.functions.is_empty());
+        assert_eq!(result.stats.function_count, 0);
+    
+        let declared: Vec<(&str, &str)> = result.declarations.iter()
+            .map(|d| (d.name.as_str(), d.signature.as_str()))
+            .collect();
+        assert_eq!(declared, vec![
+            ("Shape", "geo::Shape::Shape(T)"),
+            ("~Shape", "geo::Shape::~Shape()"),
+            ("area", "geo::Shape::area() const"),
+            ("operator==", "geo::Shape::operator==(const Shape&) const"),
+            ("count", "geo::count(const char*,int)"),
+        ]);
+    }
+    
+    #[tokio::test]
+    async fn test_definitions_and_calls() {
+        let result = analyze(SOURCE, "shape.cpp").await;
+    
+        let count = function(&result.functions, "count");
+        assert_eq!(meta(count, "namespace"), Some("geo"));
+        assert_eq!(meta(count, "signature"), Some("geo::count(const char*,int)"));
+    
+        let log = function(&result.functions, "log");
+        assert_eq!(meta(log, "namespace"), Some("geo::detail"));
+    
+        let area = function(&result.functions, "area");
+        assert_eq!(meta(area, "class_name"), Some("Shape"));
+        assert_eq!(meta(area, "type"), Some("method"));
+        assert_eq!(meta(area, "signature"), Some("geo::Shape::area() const"));
+    
+        let calls: Vec<(&str, Option<&str>, bool)> = result.function_calls.iter()
+            .map(|c| (c.function_name.as_str(), c.object_name.as_deref(), c.is_method_call))
+            .collect();
+        assert_eq!(calls, vec![
+            ("log", Some("geo::detail"), false),
+            ("scale", Some("this"), true),
+            ("area", Some("shapes"), true),
+            ("paint", Some("canvas"), true),
+            ("sort", Some("std"), false),
+            ("instance", Some("Registry"), true),
+            ("helper", None, false),
+        ]);
+        let this_call = result.function_calls.iter().find(|c| c.function_name == "scale").unwrap();
+        assert_eq!(this_call.receiver_type.as_deref(), Some("Shape"));
+    }
+    
+    #[tokio::test]
+    async fn test_header_declarations_link_to_source() {
+        let header = analyze(HEADER, "shape.hpp").await;
+        let source = analyze(SOURCE, "shape.cpp").await;
+        let mut files = vec![header, source];
+    
+        link_declarations(&mut files);
+    
+        let defined: Vec<(&str, Option<&str>)> = files[0].declarations.iter()
+            .map(|d| (d.name.as_str(), d.defined_at.as_deref()))
+            .collect();
+        assert_eq!(defined, vec![
+            ("Shape", None),
+            ("~Shape", None),
+            ("area", Some("shape.cpp:15")),
+            ("operator==", None),
+            ("count", Some("shape.cpp:5")),
+        ]);
+        assert_eq!(meta(function(&files[1].functions, "area"), "declared_at"), Some("shape.hpp:11"));
+        assert_eq!(meta(function(&files[1].functions, "count"), "declared_at"), Some("shape.hpp:19"));
+    }
+}
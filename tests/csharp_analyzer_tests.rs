@@ -0,0 +1,80 @@
+//! Tests for the tree-sitter based C# analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::csharp::TreeSitterCSharpAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, ImportType};
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.cs");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterCSharpAnalyzer::new().unwrap();
+        analyzer.analyze(content, "Program.cs").await.unwrap()
+    }
+    
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("class {} not found", name))
+    }
+    
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+    
+    /// Types, members and namespaces of a minimal program
+    #[tokio::test]
+    async fn test_classes_methods_properties() {
+        let result = analyze(SAMPLE).await;
+        assert!(result.errors.is_empty(), "{:?}", result.errors);
+        
+        assert_eq!(meta(&class(&result, "IGreeter").metadata, "type"), Some("interface"));
+        assert_eq!(meta(&class(&result, "Point").metadata, "type"), Some("record"));
+        
+        let greeter = class(&result, "Greeter");
+        assert_eq!(meta(&greeter.metadata, "type"), Some("class"));
+        assert_eq!(meta(&greeter.metadata, "namespace"), Some("Neko.Samples"));
+        assert_eq!(greeter.parent_class.as_deref(), Some("IGreeter"));
+        assert_eq!(greeter.properties, vec!["Count"]);
+        assert_eq!(greeter.member_variables.len(), 1);
+        assert_eq!(greeter.member_variables[0].name, "prefix");
+        assert!(greeter.member_variables[0].is_const);
+        
+        let names: Vec<&str> = greeter.methods.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(names, vec!["Greeter", "Greet", "RunAsync"]);
+        
+        let run = greeter.methods.iter().find(|m| m.name == "RunAsync").unwrap();
+        assert!(run.is_async);
+        assert_eq!(meta(&run.metadata, "modifiers"), Some("public static async"));
+        assert_eq!(run.parameters, vec!["List<string> names"]);
+        
+        let greet = result.functions.iter().find(|f| f.name == "Greet" && f.start_line > 12).unwrap();
+        assert_eq!(meta(&greet.metadata, "class_name"), Some("Greeter"));
+        assert_eq!(meta(&greet.metadata, "return_type"), Some("string"));
+        
+        assert_eq!(meta(&result.metadata, "namespaces"), Some("Neko.Samples"));
+    }
+    
+    /// Plain, static and alias using directives
+    #[tokio::test]
+    async fn test_using_directives() {
+        let result = analyze(SAMPLE).await;
+        
+        let paths: Vec<&str> = result.imports.iter().map(|i| i.module_path.as_str()).collect();
+        assert_eq!(paths, vec!["System", "System.Collections.Generic", "System.Math", "System.Text.Json"]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::CSharpUsing));
+        
+        assert_eq!(meta(&result.imports[2].metadata, "is_static"), Some("true"));
+        assert_eq!(result.imports[3].alias.as_deref(), Some("Json"));
+    }
+    
+    /// File-scoped namespaces apply to the declarations that follow them
+    #[tokio::test]
+    async fn test_file_scoped_namespace() {
+        let result = analyze("namespace App.Core;\n\npublic class Service\n{\n    public void Run() { }\n}\n").await;
+        
+        assert_eq!(meta(&class(&result, "Service").metadata, "namespace"), Some("App.Core"));
+        assert_eq!(class(&result, "Service").methods.len(), 1);
+    }
+}
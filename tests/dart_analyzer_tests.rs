@@ -0,0 +1,185 @@
+//! Tests for the tree-sitter based Dart analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::dart::TreeSitterDartAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language};
+
+    const SAMPLE: &str = include_str!("../test_samples/sample.dart");
+
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterDartAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.dart").await.unwrap()
+    }
+
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("class {} not found", name))
+    }
+
+    fn function<'a>(result: &'a AnalysisResult, name: &str) -> &'a FunctionInfo {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".dart"), Language::Dart);
+        assert_eq!(Language::from_name("dart"), Some(Language::Dart));
+        assert_eq!(Language::from_shebang("#!/usr/bin/env dart"), Language::Dart);
+    }
+
+    /// Widget hierarchy: `extends` is the parent, `implements` the interfaces, `with` the embeds
+    #[tokio::test]
+    async fn test_class_hierarchy() {
+        let result = analyze(SAMPLE).await;
+
+        let kinds: Vec<(&str, &str)> = result.classes.iter()
+            .map(|c| (c.name.as_str(), meta(&c.metadata, "type").unwrap_or("")))
+            .collect();
+        assert_eq!(kinds, vec![
+            ("Diagnosticable", "mixin"),
+            ("Widget", "class"),
+            ("StatelessWidget", "class"),
+            ("StatefulWidget", "class"),
+            ("Counter", "class"),
+            ("_CounterState", "class"),
+            ("Status", "enum"),
+            ("StatusLabel", "extension"),
+            ("UserId", "extension_type"),
+        ]);
+
+        let widget = class(&result, "Widget");
+        assert_eq!(meta(&widget.metadata, "is_abstract"), Some("true"));
+        assert_eq!(widget.parent_class, None);
+        assert_eq!(widget.embeds, vec!["Diagnosticable"]);
+
+        let stateful = class(&result, "StatefulWidget");
+        assert_eq!(stateful.parent_class.as_deref(), Some("Widget"));
+        assert_eq!(stateful.implements, vec!["Comparable<StatefulWidget>"]);
+
+        let state = class(&result, "_CounterState");
+        assert_eq!(state.parent_class.as_deref(), Some("State<Counter>"));
+        assert_eq!(state.embeds, vec!["SingleTickerProviderStateMixin", "Diagnosticable"]);
+        assert_eq!(meta(&state.metadata, "visibility"), Some("private"));
+
+        assert_eq!(meta(&class(&result, "StatusLabel").metadata, "on"), Some("Status"));
+        let constants: Vec<&str> = class(&result, "Status").member_variables.iter()
+            .filter(|m| meta(&m.metadata, "enum_constant").is_some())
+            .map(|m| m.name.as_str())
+            .collect();
+        assert_eq!(constants, vec!["idle", "running", "done"]);
+    }
+
+    /// Generative, const and factory constructors with named / optional parameters
+    #[tokio::test]
+    async fn test_constructors_and_parameters() {
+        let result = analyze(SAMPLE).await;
+        let counter = class(&result, "Counter");
+
+        let methods: Vec<(&str, &str)> = counter.methods.iter()
+            .map(|m| (m.name.as_str(), meta(&m.metadata, "type").unwrap_or("")))
+            .collect();
+        assert_eq!(methods, vec![
+            ("Counter", "constructor"),
+            ("Counter.fromJson", "factory_constructor"),
+            ("createState", "method"),
+        ]);
+
+        let constructor = &counter.methods[0];
+        assert_eq!(meta(&constructor.metadata, "is_const"), Some("true"));
+        assert_eq!(meta(&constructor.metadata, "named_parameters"), Some("key,initial,step"));
+        assert_eq!(meta(&constructor.metadata, "required_named_parameters"), Some("initial"));
+        assert_eq!(meta(&constructor.metadata, "field_parameters"), Some("initial,step"));
+
+        let factory = &counter.methods[1];
+        assert_eq!(meta(&factory.metadata, "is_factory"), Some("true"));
+        assert_eq!(factory.params[0].name, "json");
+        assert_eq!(factory.params[0].param_type, "Map<String, dynamic>");
+        assert_eq!(meta(&counter.methods[2].metadata, "annotations"), Some("override"));
+
+        let fields: Vec<(&str, bool)> = counter.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.is_static))
+            .collect();
+        assert_eq!(fields, vec![("maxValue", true), ("instances", true), ("initial", false), ("step", false)]);
+
+        let clamp = function(&result, "clamp");
+        assert_eq!(meta(&clamp.metadata, "type"), Some("function"));
+        assert_eq!(meta(&clamp.metadata, "return_type"), Some("int"));
+        assert_eq!(meta(&clamp.metadata, "named_parameters"), Some("min,max"));
+        assert_eq!(meta(&clamp.metadata, "required_named_parameters"), Some("min"));
+        assert_eq!(clamp.parameters, vec!["int value", "required int min", "int max = 100"]);
+
+        assert_eq!(meta(&function(&result, "increment").metadata, "optional_parameters"), Some("by"));
+    }
+
+    /// Getters, setters, async members and library-private names
+    #[tokio::test]
+    async fn test_accessors_and_visibility() {
+        let result = analyze(SAMPLE).await;
+        let state = class(&result, "_CounterState");
+
+        let methods: Vec<(&str, &str, &str)> = state.methods.iter()
+            .map(|m| (m.name.as_str(), meta(&m.metadata, "type").unwrap_or(""), meta(&m.metadata, "visibility").unwrap_or("")))
+            .collect();
+        assert_eq!(methods, vec![
+            ("value", "getter", "public"),
+            ("value", "setter", "public"),
+            ("increment", "method", "public"),
+            ("notify", "method", "public"),
+            ("_load", "method", "private"),
+        ]);
+        assert!(state.methods[4].is_async);
+        assert_eq!(meta(&state.methods[0].metadata, "expression_body"), Some("true"));
+
+        // Abstract members have no body
+        let widget = class(&result, "Widget");
+        assert_eq!(meta(&widget.methods[1].metadata, "is_abstract"), Some("true"));
+    }
+
+    /// if / else if, && and ?: each add a path
+    #[tokio::test]
+    async fn test_complexity() {
+        let result = analyze(SAMPLE).await;
+
+        assert_eq!(function(&result, "clamp").complexity.cyclomatic_complexity, 3);
+        assert_eq!(function(&result, "increment").complexity.cyclomatic_complexity, 4);
+    }
+
+    /// Imports with prefixes and combinators; `this.` and `Type.` calls resolve their receiver
+    #[tokio::test]
+    async fn test_references_and_imports() {
+        let result = analyze(SAMPLE).await;
+
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+
+        let notify = call("notify");
+        assert!(notify.is_method_call);
+        assert_eq!(notify.object_name.as_deref(), Some("this"));
+        assert_eq!(notify.receiver_type.as_deref(), Some("_CounterState"));
+
+        let from_json = call("fromJson");
+        assert_eq!(from_json.receiver_type.as_deref(), Some("Counter"));
+        assert!(!call("clamp").is_method_call);
+
+        let imports: Vec<(&str, Option<&str>, Vec<String>)> = result.imports.iter()
+            .map(|i| (i.module_path.as_str(), i.alias.as_deref(), i.imported_names.clone()))
+            .collect();
+        assert_eq!(imports, vec![
+            ("dart:async", None, vec![]),
+            ("package:flutter/foundation.dart", Some("foundation"), vec!["kDebugMode".to_string()]),
+            ("src/theme.dart", None, vec![]),
+        ]);
+        assert_eq!(meta(&result.imports[2].metadata, "hide"), Some("DarkTheme"));
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::DartImport));
+    }
+}
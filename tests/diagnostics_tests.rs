@@ -0,0 +1,32 @@
+//! Tests for per-file parse diagnostics (--diagnostics)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::AnalysisConfig;
+    use std::fs;
+    use tempfile::TempDir;
+
+    #[tokio::test]
+    async fn test_diagnostics_per_file() {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("ok.go"), "package main\n\nfunc main() {}\n").unwrap();
+        fs::write(temp_dir.path().join("broken.go"), "package main\n\nfunc main( {\n").unwrap();
+
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+        assert!(analysis.files.iter().all(|f| f.diagnostics.is_none()));
+
+        let mut config = AnalysisConfig::default();
+        config.diagnostics = true;
+        let mut session = AnalysisSession::with_config(config);
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+
+        let errors: Vec<(&str, bool)> = analysis.files.iter()
+            .map(|f| (f.file_info.name.as_str(), f.diagnostics.as_ref().unwrap().has_error))
+            .collect();
+        assert_eq!(errors, vec![("broken.go", true), ("ok.go", false)]);
+        let ok = analysis.files[1].diagnostics.as_ref().unwrap();
+        assert!(ok.node_count > 0 && ok.max_depth > 1);
+    }
+}
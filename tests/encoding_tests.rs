@@ -0,0 +1,66 @@
+//! Tests for byte order marks and non-UTF-8 source files
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::{AnalysisConfig, AnalysisResult, DirectoryAnalysis};
+    use std::fs;
+    use tempfile::TempDir;
+
+    /// bom.py with a UTF-8 byte order mark, latin1.py with a Latin-1 comment, plain.py
+    fn sample_tree() -> TempDir {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("bom.py"), b"\xEF\xBB\xBFdef greet():\n    return 1\n").unwrap();
+        fs::write(temp_dir.path().join("latin1.py"), b"# caf\xE9\ndef order(x):\n    return x\n").unwrap();
+        fs::write(temp_dir.path().join("plain.py"), "def plain():\n    pass\n").unwrap();
+        temp_dir
+    }
+
+    async fn analyze(temp_dir: &TempDir, strict_utf8: bool) -> DirectoryAnalysis {
+        let mut config = AnalysisConfig::default();
+        config.strict_utf8 = strict_utf8;
+        let mut session = AnalysisSession::with_config(config);
+        session.analyze_path(temp_dir.path(), false).await.unwrap()
+    }
+
+    fn file<'a>(analysis: &'a DirectoryAnalysis, name: &str) -> &'a AnalysisResult {
+        analysis.files.iter()
+            .find(|f| f.file_info.name == name)
+            .unwrap_or_else(|| panic!("file {} not found", name))
+    }
+
+    #[tokio::test]
+    async fn test_bom_and_latin1_are_decoded() {
+        let temp_dir = sample_tree();
+        let analysis = analyze(&temp_dir, false).await;
+        assert!(analysis.errors.is_empty());
+        assert_eq!(analysis.files.len(), 3);
+
+        // The byte order mark is not part of the first line
+        let bom = file(&analysis, "bom.py");
+        assert!(bom.file_info.encoding_warning.is_none());
+        assert_eq!(bom.functions[0].name, "greet");
+        assert_eq!(bom.functions[0].start_line, 1);
+
+        let latin1 = file(&analysis, "latin1.py");
+        assert!(latin1.file_info.encoding_warning.as_deref().unwrap().contains("offset 5"));
+        assert_eq!(latin1.functions[0].name, "order");
+        assert_eq!(latin1.functions[0].start_line, 2);
+
+        assert!(file(&analysis, "plain.py").file_info.encoding_warning.is_none());
+    }
+
+    /// Under strict_utf8 the bad file is an error; the rest of the batch is analyzed
+    #[tokio::test]
+    async fn test_strict_utf8_reports_the_file() {
+        let temp_dir = sample_tree();
+        let analysis = analyze(&temp_dir, true).await;
+
+        let mut names: Vec<&str> = analysis.files.iter().map(|f| f.file_info.name.as_str()).collect();
+        names.sort();
+        assert_eq!(names, vec!["bom.py", "plain.py"]);
+        assert_eq!(analysis.errors.len(), 1);
+        assert!(analysis.errors[0].file_path.ends_with("latin1.py"));
+        assert!(analysis.errors[0].message.contains("Not valid UTF-8"));
+    }
+}
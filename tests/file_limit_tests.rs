@@ -0,0 +1,74 @@
+//! Tests for the file size and parse size limits
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::{AnalysisConfig, DEFAULT_MAX_FILE_SIZE};
+    use std::fs;
+    use tempfile::TempDir;
+
+    /// `small.py` plus a generated `huge.py` of about 4 KB
+    fn sample_tree() -> TempDir {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("small.py"), "def helper(x):\n    return x + 1\n").unwrap();
+        let generated: String = (0..200).map(|i| format!("def gen_{}():\n    return {}\n", i, i)).collect();
+        fs::write(temp_dir.path().join("huge.py"), generated).unwrap();
+        temp_dir
+    }
+
+    fn config(max_file_size: Option<u64>, max_parse_bytes: Option<u64>) -> AnalysisConfig {
+        let mut config = AnalysisConfig::default();
+        config.max_file_size = max_file_size;
+        config.max_parse_bytes = max_parse_bytes;
+        config
+    }
+
+    #[test]
+    fn test_default_limit() {
+        assert_eq!(AnalysisConfig::default().max_file_size, Some(DEFAULT_MAX_FILE_SIZE));
+        assert_eq!(AnalysisConfig::default().max_parse_bytes, None);
+    }
+
+    /// Oversized files become warnings; the rest of the batch is analyzed
+    #[tokio::test]
+    async fn test_oversized_files_are_skipped_with_warning() {
+        let temp_dir = sample_tree();
+
+        let mut session = AnalysisSession::with_config(config(Some(1024), None));
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+        let names: Vec<&str> = analysis.files.iter().map(|f| f.file_info.name.as_str()).collect();
+        assert_eq!(names, vec!["small.py"]);
+        assert!(analysis.errors.is_empty());
+        assert_eq!(analysis.warnings.len(), 1);
+        assert!(analysis.warnings[0].file_path.ends_with("huge.py"));
+        assert!(analysis.warnings[0].message.contains("1024"), "{}", analysis.warnings[0].message);
+
+        let mut session = AnalysisSession::with_config(config(Some(1024), None));
+        let streamed = session.analyze_path_streaming(temp_dir.path(), false, |_| Ok(())).await.unwrap();
+        assert_eq!(streamed.summary.total_files, 1);
+        assert_eq!(streamed.warnings.len(), 1);
+
+        // Naming the file directly is not an error either
+        let mut session = AnalysisSession::with_config(config(Some(1024), None));
+        let analysis = session.analyze_path(&temp_dir.path().join("huge.py"), false).await.unwrap();
+        assert!(analysis.files.is_empty());
+        assert_eq!(analysis.warnings.len(), 1);
+    }
+
+    /// Past `max_parse_bytes` only whole leading lines are parsed, and the result says so
+    #[tokio::test]
+    async fn test_parse_truncation() {
+        let temp_dir = sample_tree();
+
+        let session = AnalysisSession::with_config(config(None, Some(100)));
+        let result = session.analyze_file(&temp_dir.path().join("huge.py")).await.unwrap();
+        let names: Vec<&str> = result.functions.iter().map(|f| f.name.as_str()).collect();
+        assert_eq!(names, vec!["gen_0", "gen_1", "gen_2", "gen_3"]);
+        assert_eq!(result.errors.len(), 1);
+        assert!(result.errors[0].contains("Parsed only the first"), "{}", result.errors[0]);
+        assert!(result.file_info.size_bytes > 100);
+
+        let result = session.analyze_file(&temp_dir.path().join("small.py")).await.unwrap();
+        assert!(result.errors.is_empty());
+    }
+}
@@ -0,0 +1,64 @@
+//! Tests for analyzing an explicit file list (`analyze --files-from`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::AnalysisConfig;
+    use std::fs;
+    use std::path::{Path, PathBuf};
+    use tempfile::TempDir;
+    
+    fn write(root: &Path, relative: &str, content: &str) -> PathBuf {
+        let path = root.join(relative);
+        fs::create_dir_all(path.parent().unwrap()).unwrap();
+        fs::write(&path, content).unwrap();
+        path
+    }
+    
+    #[tokio::test]
+    async fn test_only_listed_files_are_analyzed() {
+        let temp_dir = TempDir::new().unwrap();
+        let root = temp_dir.path();
+        let util = write(root, "lib/util.py", "def util():\n    return 1\n");
+        let main = write(root, "app/main.py", "def main():\n    return util()\n");
+        // Neither listed nor walked
+        write(root, "app/other.py", "def other():\n    pass\n");
+        let notes = write(root, "app/notes.txt", "not code\n");
+        // Listed test files are kept
+        let test = write(root, "app/test_main.py", "def test_main():\n    pass\n");
+        
+        let files = vec![main.clone(), util.clone(), notes, test, root.join("lib/../lib/util.py")];
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let analysis = session.analyze_file_list(&files).await.unwrap();
+        
+        assert_eq!(analysis.directory_path, root);
+        let paths: Vec<&Path> = analysis.files.iter().map(|f| f.file_info.path.as_path()).collect();
+        assert_eq!(paths, vec![main.as_path(), util.as_path(), root.join("app/test_main.py").as_path()]);
+        assert!(analysis.errors.is_empty());
+        
+        // Calls still resolve across the listed files
+        let util = analysis.files.iter()
+            .flat_map(|f| f.functions.iter())
+            .find(|f| f.name == "util")
+            .unwrap();
+        assert_eq!(util.fan_in, 1);
+    }
+    
+    #[tokio::test]
+    async fn test_missing_files_are_reported() {
+        let temp_dir = TempDir::new().unwrap();
+        let root = temp_dir.path();
+        let present = write(root, "src/present.py", "def present():\n    pass\n");
+        
+        let files = vec![root.join("src/missing.py"), present, root.join("src")];
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let analysis = session.analyze_file_list(&files).await.unwrap();
+        
+        assert_eq!(analysis.files.len(), 1);
+        assert_eq!(analysis.summary.total_files, 1);
+        assert_eq!(analysis.errors.len(), 2);
+        assert_eq!(analysis.errors[0].file_path, root.join("src/missing.py"));
+        assert_eq!(analysis.errors[0].message, "File not found");
+        assert_eq!(analysis.errors[1].message, "Not a regular file");
+    }
+}
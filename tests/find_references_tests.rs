@@ -0,0 +1,58 @@
+//! Tests for `find-references`: exact symbol targeting over a project
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::references::ReferenceReport;
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::DirectoryAnalysis;
+    use tempfile::TempDir;
+
+    /// Two packages, each with its own `helper`
+    async fn project() -> (TempDir, DirectoryAnalysis) {
+        let dir = TempDir::new().unwrap();
+        let root = dir.path();
+        std::fs::create_dir(root.join("other")).unwrap();
+        std::fs::write(root.join("util.go"), "package app\n\nfunc helper(x int) int {\n\treturn x + 1\n}\n").unwrap();
+        std::fs::write(root.join("main.go"), "package app\n\nfunc run() int {\n\ta := helper(1)\n\treturn helper(a)\n}\n").unwrap();
+        std::fs::write(root.join("other/util.go"), "package other\n\n// helper of another package\n\nfunc helper() {}\n").unwrap();
+        std::fs::write(root.join("other/use.go"), "package other\n\nfunc use() {\n\thelper()\n}\n").unwrap();
+
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(root, true).await.unwrap();
+        (dir, analysis)
+    }
+
+    #[tokio::test]
+    async fn test_same_named_symbols_need_a_definition() {
+        let (_dir, analysis) = project().await;
+
+        let err = ReferenceReport::build(&analysis, "helper", None).unwrap_err().to_string();
+        assert!(err.contains("2 definitions"), "{}", err);
+        assert!(err.contains("util.go:3") && err.contains("other/util.go:5"), "{}", err);
+
+        assert!(ReferenceReport::build(&analysis, "missing", None).is_err());
+        assert!(ReferenceReport::build(&analysis, "helper", Some("util.go:40")).is_err());
+    }
+
+    #[tokio::test]
+    async fn test_references_of_picked_definition() {
+        let (_dir, analysis) = project().await;
+
+        let report = ReferenceReport::build(&analysis, "helper", Some("util.go:3")).unwrap();
+        assert_eq!(report.definition.file, "util.go");
+        assert_eq!(report.definition.namespace.as_deref(), Some("app"));
+        let sites: Vec<(&str, u32, u32, &str)> = report.references.iter()
+            .map(|r| (r.file.as_str(), r.line, r.column, r.snippet.as_str()))
+            .collect();
+        assert_eq!(sites, vec![("main.go", 4, 7, "a := helper(1)"), ("main.go", 5, 9, "return helper(a)")]);
+        assert_eq!(report.references[0].caller.as_deref(), Some("run"));
+        assert_eq!(report.references[0].context, vec!["func run() int {", "\ta := helper(1)", "\treturn helper(a)"]);
+
+        // Package-qualified names pick the definition too
+        let report = ReferenceReport::build(&analysis, "other.helper", None).unwrap();
+        assert_eq!(report.definition.file, "other/util.go");
+        let sites: Vec<(&str, u32)> = report.references.iter().map(|r| (r.file.as_str(), r.line)).collect();
+        assert_eq!(sites, vec![("other/use.go", 4)]);
+        assert!(report.to_text().contains("other/use.go:4:2: helper()"));
+    }
+}
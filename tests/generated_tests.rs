@@ -0,0 +1,51 @@
+//! Tests for generated-file marking and --exclude-generated
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::AnalysisConfig;
+    use std::fs;
+    use tempfile::TempDir;
+
+    /// A hand-written Go file next to a protoc-style generated one
+    fn sample_tree() -> TempDir {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("main.go"), "package main\n\nfunc main() {}\n").unwrap();
+        fs::write(
+            temp_dir.path().join("api.pb.go"),
+            "// Code generated by protoc-gen-go. DO NOT EDIT.\n\npackage main\n\nfunc (x *Req) GetName() string { return x.Name }\n",
+        ).unwrap();
+        temp_dir
+    }
+
+    #[tokio::test]
+    async fn test_generated_files_are_marked() {
+        let temp_dir = sample_tree();
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+
+        let marks: Vec<(&str, bool)> = analysis.files.iter()
+            .map(|f| (f.file_info.name.as_str(), f.file_info.generated))
+            .collect();
+        assert_eq!(marks, vec![("api.pb.go", true), ("main.go", false)]);
+    }
+
+    #[tokio::test]
+    async fn test_exclude_generated() {
+        let temp_dir = sample_tree();
+        let mut config = AnalysisConfig::default();
+        config.exclude_generated = true;
+
+        let mut session = AnalysisSession::with_config(config.clone());
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+        let names: Vec<&str> = analysis.files.iter().map(|f| f.file_info.name.as_str()).collect();
+        assert_eq!(names, vec!["main.go"]);
+        assert!(analysis.errors.is_empty());
+        assert!(analysis.warnings.is_empty());
+
+        let mut session = AnalysisSession::with_config(config);
+        let streamed = session.analyze_path_streaming(temp_dir.path(), false, |_| Ok(())).await.unwrap();
+        assert_eq!(streamed.summary.total_files, 1);
+        assert!(streamed.errors.is_empty());
+    }
+}
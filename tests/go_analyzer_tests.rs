@@ -0,0 +1,1011 @@
+//! Tests for the tree-sitter based Go analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::go::{order_initialization, TreeSitterGoAnalyzer};
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::query::SymbolQuery;
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::{AnalysisConfig, AnalysisResult, ChannelOperationType, FunctionInfo, Language, SymbolKind, TypeAssertionKind, TypeParameter};
+    use nekocode_core::metrics::ComplexityWeights;
+    use std::path::Path;
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.go");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterGoAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.go").await.unwrap()
+    }
+    
+    fn complexity_of(result: &AnalysisResult, name: &str) -> u32 {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+            .complexity.cyclomatic_complexity
+    }
+    
+    /// A single for loop adds one branch to the base complexity
+    #[tokio::test]
+    async fn test_cyclomatic_complexity_sample() {
+        let result = analyze(SAMPLE).await;
+        
+        assert_eq!(complexity_of(&result, "ProcessData"), 2);
+        assert_eq!(complexity_of(&result, "main"), 1);
+        assert_eq!(complexity_of(&result, "NewDataProcessor"), 1);
+    }
+    
+    /// JSON output exposes `"complexity": N`, with the other metrics under `complexity_detail`
+    #[tokio::test]
+    async fn test_complexity_json_field() {
+        let result = analyze(SAMPLE).await;
+        let process = result.functions.iter().find(|f| f.name == "ProcessData").unwrap();
+        let json = serde_json::to_value(process).unwrap();
+        
+        assert_eq!(json["complexity"], 2);
+        assert_eq!(json["complexity_detail"]["rating"], "simple");
+        assert!(json["complexity_detail"].get("cognitive_complexity").is_some());
+        
+        // And reads back
+        let parsed: FunctionInfo = serde_json::from_value(json).unwrap();
+        assert_eq!(parsed.complexity.cyclomatic_complexity, 2);
+    }
+    
+    /// Every decision point kind is counted, default branches are not
+    #[tokio::test]
+    async fn test_cyclomatic_complexity_decision_points() {
+        let source = r#"
+package main
+
+func classify(n int, ch chan int) string {
+	if n > 0 && n < 10 || n == 100 {
+		return "small"
+	}
+	switch n {
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	default:
+		return "other"
+	}
+	select {
+	case v := <-ch:
+		_ = v
+	default:
+	}
+	return ""
+}
+"#;
+        let result = analyze(source).await;
+        
+        // 1 base + if + && + || + 2 cases + 1 select case
+        assert_eq!(complexity_of(&result, "classify"), 7);
+    }
+    
+    /// Configured weights re-weigh the decision points per construct
+    #[tokio::test]
+    async fn test_cyclomatic_complexity_weights() {
+        let source = "package main\n\nfunc pick(n int) int {\n\tswitch n {\n\tcase 1:\n\t\treturn 1\n\tcase 2, 3:\n\t\treturn 2\n\t}\n\tif n > 9 && n < 20 {\n\t\treturn 3\n\t}\n\treturn 0\n}\n";
+        let result = analyze(source).await;
+        let points: Vec<(&str, u32)> = result.functions[0].complexity.decision_points.iter()
+            .map(|(name, count)| (name.as_str(), *count))
+            .collect();
+        assert_eq!(points, vec![("and", 1), ("case", 2), ("if", 1)]);
+        assert_eq!(complexity_of(&result, "pick"), 5);
+        
+        let mut config = AnalysisConfig::default();
+        config.complexity_weights = ComplexityWeights { case: 0, if_: 2, ..ComplexityWeights::STANDARD };
+        let session = AnalysisSession::with_config(config);
+        let result = session.analyze_source(source, Path::new("pick.go"), Language::Go).await.unwrap();
+        assert_eq!(complexity_of(&result, "pick"), 4);
+    }
+    
+    /// `--query` compares the weighted complexity, the same value that is output
+    #[tokio::test]
+    async fn test_query_uses_weighted_complexity() {
+        let source = "package main\n\nfunc a(n int) int {\n\tif n > 1 {\n\t\treturn 1\n\t}\n\tif n > 2 {\n\t\treturn 2\n\t}\n\treturn 0\n}\n\nfunc b(n int) int {\n\tswitch n {\n\tcase 1:\n\t\treturn 1\n\tcase 2:\n\t\treturn 2\n\tcase 3:\n\t\treturn 3\n\t}\n\treturn 0\n}\n";
+        
+        // Standard weights: a = 3, b = 4; weighted: a = 1 + 2 * 3 = 7, b = 1
+        let mut config = AnalysisConfig::default();
+        config.complexity_weights = ComplexityWeights { if_: 3, case: 0, ..ComplexityWeights::STANDARD };
+        config.query = Some(SymbolQuery::parse("complexity > 3").unwrap());
+        let session = AnalysisSession::with_config(config);
+        let result = session.analyze_source(source, Path::new("pick.go"), Language::Go).await.unwrap();
+        
+        let kept: Vec<(&str, u32)> = result.functions.iter()
+            .map(|f| (f.name.as_str(), f.complexity.cyclomatic_complexity))
+            .collect();
+        assert_eq!(kept, vec![("a", 7)]);
+    }
+    
+    /// Funcs, methods, structs and interfaces map onto the normalized kinds
+    #[tokio::test]
+    async fn test_symbol_kinds() {
+        let source = "package main\n\ntype Server struct {\n\taddr string\n}\n\ntype Handler interface {\n\tServe()\n}\n\nfunc (s *Server) Serve() {}\n\nfunc main() {}\n";
+        let session = AnalysisSession::new();
+        let result = session.analyze_source(source, Path::new("main.go"), Language::Go).await.unwrap();
+        
+        let kind_of = |name: &str| {
+            let func = result.functions.iter().find(|f| f.name == name).unwrap();
+            (func.kind, func.native_kind.as_str())
+        };
+        assert_eq!(kind_of("Serve"), (SymbolKind::Method, "method"));
+        assert_eq!(kind_of("main"), (SymbolKind::Function, "func"));
+        let classes: Vec<(&str, SymbolKind)> = result.classes.iter().map(|c| (c.name.as_str(), c.kind)).collect();
+        assert!(classes.contains(&("Server", SymbolKind::Struct)), "{:?}", classes);
+        assert!(classes.contains(&("Handler", SymbolKind::Interface)), "{:?}", classes);
+    }
+    
+    /// Named types of `x.(T)` and type switch arms; predeclared types and literals are skipped
+    #[tokio::test]
+    async fn test_type_assertions() {
+        let source = r#"
+package main
+
+func describe(v any) string {
+	if s, ok := v.(*Server); ok {
+		return s.addr
+	}
+	_ = v.(error)
+	switch x := v.(type) {
+	case proc.Job, *Queue[int]:
+		return "work"
+	case string, interface{ Close() error }, nil:
+		return x.(fmt.Stringer).String()
+	}
+	return ""
+}
+"#;
+        let result = analyze(source).await;
+        let func = result.functions.iter().find(|f| f.name == "describe").unwrap();
+        
+        let asserted: Vec<(String, TypeAssertionKind, u32)> = func.type_assertions.iter()
+            .map(|a| (a.type_text(), a.kind, a.line_number))
+            .collect();
+        assert_eq!(asserted, vec![
+            ("*Server".to_string(), TypeAssertionKind::Assertion, 5),
+            ("proc.Job".to_string(), TypeAssertionKind::TypeSwitch, 10),
+            ("*Queue".to_string(), TypeAssertionKind::TypeSwitch, 10),
+            ("fmt.Stringer".to_string(), TypeAssertionKind::Assertion, 13),
+        ]);
+    }
+    
+    /// Goroutines (named and anonymous) and channel operations, including select cases
+    #[tokio::test]
+    async fn test_goroutines_and_channels() {
+        let source = r#"
+package main
+
+func fanOut(jobs []int) {
+	results := make(chan int)
+	done := make(chan struct{})
+	go worker(results)
+	go func() {
+		results <- 1
+		close(done)
+	}()
+	select {
+	case v := <-results:
+		_ = v
+	case <-done:
+	}
+}
+"#;
+        let result = analyze(source).await;
+        let func = result.functions.iter().find(|f| f.name == "fanOut").unwrap();
+        
+        assert_eq!(func.goroutines.len(), 2);
+        assert_eq!(func.goroutines[0].expression, "worker");
+        assert!(!func.goroutines[0].is_anonymous);
+        assert!(func.goroutines[1].is_anonymous);
+        
+        let ops: Vec<(ChannelOperationType, &str, bool)> = func.channels.iter()
+            .map(|op| (op.operation, op.channel.as_str(), op.in_select))
+            .collect();
+        assert_eq!(ops, vec![
+            (ChannelOperationType::Make, "chan int", false),
+            (ChannelOperationType::Make, "chan struct{}", false),
+            (ChannelOperationType::Send, "results", false),
+            (ChannelOperationType::Close, "done", false),
+            (ChannelOperationType::Receive, "results", true),
+            (ChannelOperationType::Receive, "done", true),
+        ]);
+    }
+    
+    fn type_param(name: &str, constraint: &str) -> TypeParameter {
+        TypeParameter { name: name.to_string(), constraint: constraint.to_string() }
+    }
+    
+    /// Type parameter lists are recorded without leaking into the symbol name
+    #[tokio::test]
+    async fn test_generic_type_params() {
+        let source = r#"
+package main
+
+type Set[T comparable] map[T]struct{}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type ID string
+
+func Map[T any, U comparable](items []T, f func(T) U) []U {
+	return nil
+}
+
+func Keys[K, V any](m map[K]V) []K {
+	return nil
+}
+"#;
+        let result = analyze(source).await;
+        
+        let map = result.functions.iter().find(|f| f.name == "Map").unwrap();
+        assert_eq!(map.type_params, vec![type_param("T", "any"), type_param("U", "comparable")]);
+        
+        let keys = result.functions.iter().find(|f| f.name == "Keys").unwrap();
+        assert_eq!(keys.type_params, vec![type_param("K", "any"), type_param("V", "any")]);
+        
+        let set = result.classes.iter().find(|c| c.name == "Set").unwrap();
+        assert_eq!(set.type_params, vec![type_param("T", "comparable")]);
+        assert_eq!(set.metadata.get("type").map(|t| t.as_str()), Some("type"));
+        
+        let pair = result.classes.iter().find(|c| c.name == "Pair").unwrap();
+        assert_eq!(pair.type_params, vec![type_param("K", "comparable"), type_param("V", "any")]);
+        assert_eq!(result.classes.iter().filter(|c| c.name == "Pair").count(), 1);
+        
+        // Non-generic named types are not reported as classes
+        assert!(result.classes.iter().all(|c| c.name != "ID"));
+    }
+    
+    fn cognitive_of(result: &AnalysisResult, name: &str) -> u32 {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+            .complexity.cognitive_complexity
+    }
+    
+    /// Nesting adds to cognitive complexity, else-if chains do not
+    #[tokio::test]
+    async fn test_cognitive_complexity() {
+        let source = r#"
+package main
+
+func nested(items [][]int, ok bool) {
+	if ok {
+		for _, row := range items {
+			for _, v := range row {
+				_ = v
+			}
+		}
+	}
+}
+
+func siblings(items [][]int, ok bool) {
+	if ok {
+	}
+	for _, row := range items {
+		_ = row
+	}
+	for _, v := range items {
+		_ = v
+	}
+}
+
+func chain(n int, a, b, c bool) string {
+	if n == 1 {
+		return "one"
+	} else if n == 2 {
+		return "two"
+	} else {
+		return "many"
+	}
+	if a && b && c || a {
+		return ""
+	}
+	go func() {
+		if a {
+		}
+	}()
+	return ""
+}
+"#;
+        let result = analyze(source).await;
+        
+        // if(1) + for(1+1) + for(1+2)
+        assert_eq!(cognitive_of(&result, "nested"), 6);
+        // three top-level structures
+        assert_eq!(cognitive_of(&result, "siblings"), 3);
+        // if + else if + else, if + &&-sequence + ||-sequence, if nested in func literal (1+1)
+        assert_eq!(cognitive_of(&result, "chain"), 3 + 3 + 2);
+    }
+    
+    fn nesting_of(result: &AnalysisResult, name: &str) -> u32 {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+            .complexity.max_nesting_depth
+    }
+    
+    /// Deepest chain of if / for / switch / select / closures
+    #[tokio::test]
+    async fn test_max_nesting_depth() {
+        let source = r#"
+package main
+
+func grid(rows [][]int) {
+	for _, row := range rows {
+		for _, v := range row {
+			if v > 0 {
+				_ = v
+			}
+		}
+	}
+}
+
+func chain(n int) string {
+	if n == 1 {
+		return "one"
+	} else if n == 2 {
+		return "two"
+	} else if n == 3 {
+		return "three"
+	}
+	return "many"
+}
+
+func worker(jobs chan int, done chan bool) {
+	go func() {
+		for {
+			select {
+			case j := <-jobs:
+				switch j {
+				case 0:
+					done <- true
+				}
+			}
+		}
+	}()
+}
+
+func flat() int {
+	return 1
+}
+"#;
+        let result = analyze(source).await;
+        
+        assert_eq!(nesting_of(&result, "grid"), 3);
+        // else-if continues the chain at the same depth
+        assert_eq!(nesting_of(&result, "chain"), 1);
+        // func literal > for > select > switch
+        assert_eq!(nesting_of(&result, "worker"), 4);
+        assert_eq!(nesting_of(&result, "flat"), 0);
+    }
+    
+    /// *DataProcessor satisfies Processor through a pointer receiver method
+    #[tokio::test]
+    async fn test_interface_implementations() {
+        let result = analyze(SAMPLE).await;
+        
+        let processor = result.classes.iter().find(|c| c.name == "Processor").unwrap();
+        assert_eq!(processor.implementors, vec!["DataProcessor"]);
+        assert_eq!(processor.methods[0].metadata.get("signature").map(|s| s.as_str()), Some("([]string) []string"));
+        
+        let data_processor = result.classes.iter().find(|c| c.name == "DataProcessor").unwrap();
+        assert_eq!(data_processor.implements, vec!["Processor"]);
+        assert_eq!(data_processor.metadata.get("pointer_receiver_implements").map(|s| s.as_str()), Some("Processor"));
+    }
+    
+    /// Value receivers count for T, a mismatched signature does not satisfy the interface
+    #[tokio::test]
+    async fn test_interface_signature_matching() {
+        let source = r#"
+package main
+
+type Namer interface {
+	Name(prefix string, n int) (string, error)
+}
+
+type Good struct{}
+
+func (g Good) Name(p string, count int) (s string, err error) { return "", nil }
+
+type Bad struct{}
+
+func (b Bad) Name(p string) (string, error) { return "", nil }
+"#;
+        let result = analyze(source).await;
+        
+        let namer = result.classes.iter().find(|c| c.name == "Namer").unwrap();
+        assert_eq!(namer.implementors, vec!["Good"]);
+        
+        let good = result.classes.iter().find(|c| c.name == "Good").unwrap();
+        assert_eq!(good.implements, vec!["Namer"]);
+        assert!(good.metadata.get("pointer_receiver_implements").is_none());
+    }
+    
+    /// Doc comments above a function belong to it, not to the file
+    #[tokio::test]
+    async fn test_line_metrics_sample() {
+        let result = analyze(SAMPLE).await;
+        let function = |name: &str| result.functions.iter().find(|f| f.name == name).unwrap();
+        
+        let constructor = function("NewDataProcessor");
+        assert_eq!((constructor.loc, constructor.sloc, constructor.comment_lines), (3, 3, 1));
+        
+        // One blank line inside the body
+        let process = function("ProcessData");
+        assert_eq!((process.loc, process.sloc, process.comment_lines), (10, 9, 1));
+        
+        let helper = function("processItem");
+        assert_eq!(helper.comment_lines, 0);
+    }
+    
+    /// Comment-only lines inside a body count, trailing comments do not
+    #[tokio::test]
+    async fn test_line_metrics_comments() {
+        let source = r#"package main
+
+// sum adds numbers.
+//
+// It is documented over several lines.
+func sum(xs []int) int {
+	/* running
+	   total */
+	total := 0
+
+	for _, x := range xs {
+		total += x // trailing
+	}
+	return total
+}
+"#;
+        let result = analyze(source).await;
+        let sum = &result.functions[0];
+        
+        assert_eq!(sum.loc, 10);
+        assert_eq!(sum.sloc, 7);
+        assert_eq!(sum.comment_lines, 3 + 2);
+        
+        // package, func body: 8 code lines; 5 comment lines
+        assert_eq!(result.file_info.code_lines, 8);
+        assert_eq!(result.file_info.comment_lines, 5);
+        assert!((result.file_info.comment_density - 5.0 / 13.0).abs() < 1e-9);
+    }
+    
+    /// Method calls through local variables resolve to the variable's type
+    #[tokio::test]
+    async fn test_receiver_variable_types() {
+        let source = r#"package main
+
+type Queue struct{}
+type Pool struct{}
+
+func (q *Queue) Push(v int) {}
+func (p *Pool) Push(v int) {}
+
+func makePool() (*Pool, error) { return &Pool{}, nil }
+
+func run(q *Queue) {
+	pool, err := makePool()
+	worker := NewWorker()
+	literal := &Queue{}
+	var typed Pool
+	q.Push(1)
+	pool.Push(2)
+	worker.Start()
+	literal.Push(3)
+	typed.Push(4)
+	fmt.Println(err)
+}
+"#;
+        let result = analyze(source).await;
+        let receiver_of = |object: &str| {
+            result.function_calls.iter()
+                .find(|c| c.object_name.as_deref() == Some(object))
+                .unwrap_or_else(|| panic!("call on {} not found", object))
+                .receiver_type.clone()
+        };
+        
+        assert_eq!(receiver_of("q").as_deref(), Some("Queue"));
+        assert_eq!(receiver_of("pool").as_deref(), Some("Pool"));
+        assert_eq!(receiver_of("worker").as_deref(), Some("Worker"));
+        assert_eq!(receiver_of("literal").as_deref(), Some("Queue"));
+        assert_eq!(receiver_of("typed").as_deref(), Some("Pool"));
+        // Package selectors are not variables
+        assert_eq!(receiver_of("fmt"), None);
+        
+        let push = result.function_calls.iter().find(|c| c.object_name.as_deref() == Some("pool")).unwrap();
+        assert_eq!(push.qualified_name(), "Pool.Push");
+    }
+    
+    /// Embedded fields are recorded and promote their methods for interface satisfaction
+    #[tokio::test]
+    async fn test_struct_embedding() {
+        let source = r#"
+package main
+
+import "io"
+
+type Logger interface {
+	Log(msg string)
+}
+
+type LogCloser interface {
+	interface {
+		Close() error
+	}
+	Logger
+}
+
+type base struct{}
+
+func (b *base) Log(msg string) {}
+
+type Service struct {
+	base
+	io.Closer
+	name string
+}
+
+type conn struct{}
+
+func (c conn) Close() error { return nil }
+
+type Handler struct {
+	*base
+	conn
+}
+"#;
+        let result = analyze(source).await;
+        let class = |name: &str| result.classes.iter().find(|c| c.name == name).unwrap();
+        
+        assert_eq!(class("Service").embeds, vec!["base", "io.Closer"]);
+        assert_eq!(class("Handler").embeds, vec!["*base", "conn"]);
+        assert_eq!(class("LogCloser").embeds, vec!["Logger"]);
+        assert!(class("LogCloser").methods.iter().any(|m| m.name == "Close"));
+        
+        // Log has a pointer receiver on base: promoted to *Service only, to Handler through *base
+        assert_eq!(class("Service").implements, vec!["Logger"]);
+        assert_eq!(class("Service").metadata.get("pointer_receiver_implements").map(|s| s.as_str()), Some("Logger"));
+        assert_eq!(class("Handler").implements, vec!["LogCloser", "Logger"]);
+        assert!(class("Handler").metadata.get("pointer_receiver_implements").is_none());
+    }
+    
+    #[tokio::test]
+    async fn test_symbol_spans() {
+        let source = "package main\n\n// 猫\nfunc Add(a, b int) int { return a + b }\n\ntype Point struct { X int }\n\nvar s = \"猫\"; func Cat() {}\n";
+        let result = analyze(source).await;
+        
+        let add = result.functions.iter().find(|f| f.name == "Add").unwrap();
+        let start = source.find("func Add").unwrap();
+        assert_eq!(add.span.start_byte as usize, start);
+        assert_eq!(&source[add.span.start_byte as usize..add.span.end_byte as usize], "func Add(a, b int) int { return a + b }");
+        assert_eq!((add.span.start_col, add.span.start_col_utf16), (0, 0));
+        assert_eq!(add.span.end_col, 39);
+        
+        let point = result.classes.iter().find(|c| c.name == "Point").unwrap();
+        assert!(source[point.span.start_byte as usize..point.span.end_byte as usize].contains("Point struct"));
+        
+        // The multibyte literal shifts UTF-8 columns further than UTF-16 ones
+        let cat = result.functions.iter().find(|f| f.name == "Cat").unwrap();
+        assert_eq!(cat.span.start_col, 15);
+        assert_eq!(cat.span.start_col_utf16, 13);
+        
+        let json = serde_json::to_value(add).unwrap();
+        assert_eq!(json["start_byte"], start);
+        assert!(json.get("end_col_utf16").is_some());
+    }
+    
+    #[tokio::test]
+    async fn test_comment_markers() {
+        let source = r#"package main
+
+// TODO: split into smaller files
+type Server struct{}
+
+func (s *Server) Start() {
+	// FIXME(bob): leaks the listener on error
+	/* HACK work around the old API */
+}
+
+func helper() {
+	x := 1 // XXX magic number
+	_ = x
+}
+"#;
+        let result = analyze(source).await;
+        let markers: Vec<_> = result.markers.iter()
+            .map(|m| (m.kind.as_str(), m.line, m.text.as_str(), m.function.as_deref()))
+            .collect();
+        
+        assert_eq!(markers, vec![
+            ("TODO", 3, "split into smaller files", None),
+            ("FIXME", 7, "leaks the listener on error", Some("Server.Start")),
+            ("HACK", 8, "work around the old API", Some("Server.Start")),
+            ("XXX", 12, "magic number", Some("helper")),
+        ]);
+    }
+    
+    #[tokio::test]
+    async fn test_params_and_returns() {
+        let source = r#"package main
+
+func getUserById(id int, opts ...Option) (*User, error) { return nil, nil }
+
+func (s *Store) Split(a, b string, m map[string]int) (n int, err error) { return }
+
+func Log(string, ...interface{}) {}
+
+type Reader interface {
+	Read(p []byte) (int, error)
+}
+"#;
+        let result = analyze(source).await;
+        let params = |func: &nekocode_core::core::types::FunctionInfo| {
+            func.params.iter().map(|p| (p.name.clone(), p.param_type.clone(), p.variadic)).collect::<Vec<_>>()
+        };
+        let param = |name: &str, ty: &str, variadic: bool| (name.to_string(), ty.to_string(), variadic);
+        
+        let get_user = result.functions.iter().find(|f| f.name == "getUserById").unwrap();
+        assert_eq!(params(get_user), vec![param("id", "int", false), param("opts", "...Option", true)]);
+        assert_eq!(get_user.returns, vec!["*User", "error"]);
+        assert!(get_user.return_names.is_empty());
+        assert_eq!(get_user.parameters, vec!["id int", "opts ...Option"]);
+        
+        // Grouped names share a type; named results keep their names
+        let split = result.functions.iter().find(|f| f.name == "Split").unwrap();
+        assert_eq!(params(split), vec![param("a", "string", false), param("b", "string", false), param("m", "map[string]int", false)]);
+        assert_eq!(split.returns, vec!["int", "error"]);
+        assert_eq!(split.return_names, vec!["n", "err"]);
+        
+        let log = result.functions.iter().find(|f| f.name == "Log").unwrap();
+        assert_eq!(params(log), vec![param("", "string", false), param("", "...interface{}", true)]);
+        assert!(log.returns.is_empty());
+        
+        let reader = result.classes.iter().find(|c| c.name == "Reader").unwrap();
+        let read = reader.methods.iter().find(|m| m.name == "Read").unwrap();
+        assert_eq!(params(read), vec![param("p", "[]byte", false)]);
+        assert_eq!(read.returns, vec!["int", "error"]);
+        
+        let json = serde_json::to_value(get_user).unwrap();
+        assert_eq!(json["params"][1]["type"], "...Option");
+        assert_eq!(json["returns"][0], "*User");
+    }
+    
+    /// The file's build constraint is recorded, legacy `+build` lines converted
+    #[tokio::test]
+    async fn test_build_tags() {
+        let source = "// Copyright 2024\n\n//go:build linux && (amd64 || arm64)\n\npackage net\n\nfunc poll() {}\n";
+        let result = analyze(source).await;
+        assert_eq!(result.metadata.get("build_tags").map(String::as_str), Some("linux && (amd64 || arm64)"));
+        
+        let legacy = analyze("// +build linux,cgo darwin\n\npackage net\n").await;
+        assert_eq!(legacy.metadata.get("build_tags").map(String::as_str), Some("(linux && cgo) || darwin"));
+        
+        assert!(analyze(SAMPLE).await.metadata.get("build_tags").is_none());
+    }
+    
+    /// Package clause and import aliases feed cross-package reference resolution
+    #[tokio::test]
+    async fn test_package_and_import_aliases() {
+        let source = "package main\n\nimport (\n\t\"fmt\"\n\tfoo \"example.com/app/proc\"\n\t. \"example.com/app/util\"\n\t_ \"example.com/app/driver\"\n)\n\nfunc main() { fmt.Println(foo.NewDataProcessor()) }\n";
+        let result = analyze(source).await;
+        assert_eq!(result.metadata.get("package").map(String::as_str), Some("main"));
+        
+        let aliases: Vec<(&str, Option<&str>)> = result.imports.iter()
+            .map(|i| (i.module_path.as_str(), i.alias.as_deref()))
+            .collect();
+        assert_eq!(aliases, vec![
+            ("fmt", None),
+            ("example.com/app/proc", Some("foo")),
+            ("example.com/app/util", Some(".")),
+            ("example.com/app/driver", Some("_")),
+        ]);
+    }
+    
+    /// Unread locals and parameters; shadowing, writes and `_` are handled per scope
+    #[tokio::test]
+    async fn test_unused_locals() {
+        let source = r#"package main
+
+func process(items []int, limit int, _ string) (total int) {
+	count := 0
+	x := 1
+	if x := 2; x > 0 {
+		total += x
+	}
+	for i, item := range items {
+		total += item
+	}
+	var err error
+	err = validate()
+	count++
+	go func(id int) {
+		total++
+	}(limit)
+	return
+}
+
+func (s *Server) Handle(req Request) {
+	s.count += req.Size
+}
+"#;
+        let result = analyze(source).await;
+        let unused = |name: &str| -> Vec<(String, u32)> {
+            result.functions.iter()
+                .find(|f| f.name == name)
+                .unwrap_or_else(|| panic!("function {} not found", name))
+                .unused_locals.iter()
+                .map(|u| (u.name.clone(), u.line))
+                .collect()
+        };
+        
+        // The outer `x` stays unused although the `if` scope reads its own `x`;
+        // `err` and `count` are only written, the closure's `id` is never read
+        assert_eq!(unused("process"), vec![
+            ("count".to_string(), 4),
+            ("x".to_string(), 5),
+            ("i".to_string(), 9),
+            ("err".to_string(), 12),
+            ("id".to_string(), 15),
+        ]);
+        assert!(unused("Handle").is_empty());
+    }
+    
+    /// Defers are listed per function; a goroutine path panicking without `recover` is flagged
+    #[tokio::test]
+    async fn test_defer_panic_recover() {
+        let sample = analyze(SAMPLE).await;
+        let process = sample.functions.iter().find(|f| f.name == "ProcessData").unwrap();
+        let defers: Vec<(&str, u32)> = process.defers.iter().map(|d| (d.expression.as_str(), d.line_number)).collect();
+        assert_eq!(defers, vec![("dp.mu.Unlock", 30)]);
+        assert!(!process.has_panic && !process.has_recover && !process.unrecovered_panic);
+        
+        let source = r#"package main
+
+func mustParse(s string) int {
+	if s == "" {
+		panic("empty")
+	}
+	return len(s)
+}
+
+func safeRun(f func()) {
+	defer func() {
+		if r := recover(); r != nil {
+			log(r)
+		}
+	}()
+	f()
+	panic("done")
+}
+
+func spawn() {
+	defer func() { recover() }()
+	go func() {
+		panic("worker")
+	}()
+}
+"#;
+        let result = analyze(source).await;
+        let function = |name: &str| result.functions.iter().find(|f| f.name == name).unwrap();
+        
+        let must_parse = function("mustParse");
+        assert!(must_parse.has_panic && !must_parse.has_recover && must_parse.unrecovered_panic);
+        
+        let safe_run = function("safeRun");
+        assert!(safe_run.has_panic && safe_run.has_recover && !safe_run.unrecovered_panic);
+        assert_eq!(safe_run.defers.len(), 1);
+        assert!(safe_run.defers[0].is_anonymous);
+        assert_eq!(safe_run.defers[0].expression, "func literal");
+        
+        // The outer recover cannot stop a panic on the spawned goroutine
+        let spawn = function("spawn");
+        assert!(spawn.has_panic && spawn.has_recover && spawn.unrecovered_panic);
+    }
+    
+    #[tokio::test]
+    async fn test_unchecked_errors() {
+        let source = r#"package main
+
+type Store struct{}
+
+func (s *Store) Save(v string) error { return nil }
+
+func (s *Store) Flush() error {
+	s.Save("pending")
+	return nil
+}
+
+func Load(name string) (string, error) { return name, nil }
+
+func Count() int { return 0 }
+
+func main() {
+	store := &Store{}
+	store.Save("a")
+	if err := store.Save("b"); err != nil {
+		return
+	}
+	v, _ := Load("x")
+	_, err := Load("y")
+	_ = err
+	_, _ = Load("z")
+	Count()
+	println(v)
+}
+"#;
+        let result = analyze(source).await;
+        let unchecked: Vec<(u32, &str)> = result.unchecked_errors.iter()
+            .map(|u| (u.line_number, u.callee.as_str()))
+            .collect();
+        assert_eq!(unchecked, vec![
+            (8, "Store.Save"),
+            (18, "Store.Save"),
+            (22, "Load"),
+            (25, "Load"),
+        ]);
+    }
+    
+    /// Closures are children named `Parent$funcN`, measured on their own and within the parent
+    #[tokio::test]
+    async fn test_nested_closures() {
+        let source = r#"
+package main
+
+func ProcessData(items []int) []int {
+	keep := func(n int) bool {
+		if n > 0 && n < 10 {
+			return true
+		}
+		return false
+	}
+	go func() {
+		for range items {
+			defer func() {}()
+		}
+	}()
+	return filter(items, keep)
+}
+"#;
+        let result = analyze(source).await;
+        let process = result.functions.iter().find(|f| f.name == "ProcessData").unwrap();
+        assert!(!process.nested);
+        
+        let children: Vec<(&str, u32, bool)> = process.children.iter()
+            .map(|c| (c.name.as_str(), c.start_line, c.nested))
+            .collect();
+        assert_eq!(children, vec![("ProcessData$func1", 5, true), ("ProcessData$func2", 11, true)]);
+        
+        // if + && in the first closure, for in the second
+        let keep = &process.children[0];
+        assert_eq!(keep.complexity.cyclomatic_complexity, 3);
+        assert_eq!(keep.parameters, vec!["n int"]);
+        assert_eq!(keep.returns, vec!["bool"]);
+        assert_eq!(process.children[1].complexity.cyclomatic_complexity, 2);
+        assert_eq!(process.complexity.cyclomatic_complexity, 4);
+        
+        let inner: Vec<&str> = process.children[1].children.iter().map(|c| c.name.as_str()).collect();
+        assert_eq!(inner, vec!["ProcessData$func2$func1"]);
+        assert_eq!(result.functions.len(), 1);
+    }
+    
+    /// Inline literals other than 0 / 1 are smells; constants are named
+    #[tokio::test]
+    async fn test_magic_numbers() {
+        let source = r#"
+package main
+
+const Retries = 3
+
+func Backoff(attempt int) int {
+	const base = 100
+	if attempt > Retries {
+		return 0
+	}
+	return base * 2 << attempt + 1
+}
+"#;
+        let result = analyze(source).await;
+        let backoff = result.functions.iter().find(|f| f.name == "Backoff").unwrap();
+        let smells: Vec<(&str, u32, &str)> = backoff.smells.iter()
+            .map(|s| (s.kind.as_str(), s.line, s.value.as_str()))
+            .collect();
+        assert_eq!(smells, vec![("magic_number", 11, "2")]);
+    }
+    
+    /// Package-level vars and consts with their initializers; `init` functions in source order
+    #[tokio::test]
+    async fn test_package_variables_and_init() {
+        let source = r#"
+package config
+
+import "os"
+
+const (
+	A = iota
+	B
+)
+
+var (
+	Home     = os.Getenv("HOME")
+	Path     = Home + "/.app"
+	Limit    int
+	sizes    = []byte("abc")
+	readings = len(sizes)
+)
+
+func init() {
+	os.Setenv("APP", Path)
+	load()
+}
+
+func load() {}
+
+func init() {}
+"#;
+        let result = analyze(source).await;
+        let summary: Vec<(&str, bool, bool, &str)> = result.variables.iter()
+            .map(|v| (v.name.as_str(), v.is_const, v.non_trivial_initializer, v.initializer.as_str()))
+            .collect();
+        assert_eq!(summary, vec![
+            ("A", true, false, "iota"),
+            ("B", true, false, "iota"),
+            ("Home", false, true, "os.Getenv(\"HOME\")"),
+            ("Path", false, false, "Home + \"/.app\""),
+            ("Limit", false, false, ""),
+            ("sizes", false, false, "[]byte(\"abc\")"),
+            ("readings", false, false, "len(sizes)"),
+        ]);
+        assert!(result.variables.iter().all(|v| v.package_level));
+        
+        let path = result.variables.iter().find(|v| v.name == "Path").unwrap();
+        assert_eq!(path.depends_on, vec!["Home"]);
+        assert_eq!(path.line_number, 13);
+        assert_eq!(result.variables.iter().find(|v| v.name == "Limit").unwrap().var_type, "int");
+        
+        // Imported packages are not dependencies
+        assert!(result.variables.iter().find(|v| v.name == "Home").unwrap().depends_on.is_empty());
+        
+        let inits: Vec<(u32, u32, &[String])> = result.init_functions.iter()
+            .map(|i| (i.line_number, i.order, i.calls.as_slice()))
+            .collect();
+        assert_eq!(inits.len(), 2);
+        assert_eq!((inits[0].0, inits[0].1), (19, 1));
+        assert_eq!(inits[0].2, ["os.Setenv".to_string(), "load".to_string()]);
+        assert_eq!((inits[1].0, inits[1].1), (26, 2));
+        assert!(inits[1].2.is_empty());
+    }
+    
+    /// Dependencies initialize first, also through functions and across files sorted by name
+    #[tokio::test]
+    async fn test_initialization_order_across_files() {
+        let first = "package app\n\nvar total = count() + base\n\nvar items = []int{base}\n\nfunc count() int { return len(items) }\n\nfunc init() { setup() }\n";
+        let second = "package app\n\nvar base = 10\n\nfunc init() {}\n";
+        let mut analyzer = TreeSitterGoAnalyzer::new().unwrap();
+        let mut files = vec![
+            analyzer.analyze(second, "app/b.go").await.unwrap(),
+            analyzer.analyze(first, "app/a.go").await.unwrap(),
+        ];
+        
+        let packages = order_initialization(&mut files);
+        assert_eq!(packages.len(), 1);
+        assert_eq!(packages[0].package, "app");
+        
+        let order: Vec<&str> = packages[0].variables.iter().map(|s| s.name.as_str()).collect();
+        assert_eq!(order, vec!["base", "items", "total"]);
+        assert_eq!(files[1].variables[0].init_order, Some(3));
+        assert!(packages[0].variables[2].runs_code);
+        
+        // `items` is read by `count`, not named in the initializer
+        assert_eq!(files[1].variables[0].depends_on, vec!["count", "items", "base"]);
+        
+        // a.go before b.go
+        let inits: Vec<(&Path, bool)> = packages[0].init_functions.iter()
+            .map(|s| (s.file.as_path(), s.runs_code))
+            .collect();
+        assert_eq!(inits, vec![(Path::new("app/a.go"), true), (Path::new("app/b.go"), false)]);
+    }
+}
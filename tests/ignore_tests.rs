@@ -0,0 +1,62 @@
+//! Tests for .gitignore / .nekocodeignore handling during directory walks
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::AnalysisConfig;
+    use std::fs;
+    use std::path::Path;
+    use tempfile::TempDir;
+    
+    fn write(root: &Path, relative: &str, content: &str) {
+        let path = root.join(relative);
+        fs::create_dir_all(path.parent().unwrap()).unwrap();
+        fs::write(path, content).unwrap();
+    }
+    
+    fn sample_tree() -> TempDir {
+        let temp_dir = TempDir::new().unwrap();
+        let root = temp_dir.path();
+        
+        write(root, ".gitignore", "vendor/\n");
+        write(root, "vendor/lib.py", "def vendored():\n    pass\n");
+        write(root, "app/main.py", "def main():\n    pass\n");
+        write(root, "app/.nekocodeignore", "generated_*.py\n");
+        write(root, "app/generated_models.py", "def model():\n    pass\n");
+        // Nested ignore rules only apply to their own subtree
+        write(root, "other/generated_client.py", "def client():\n    pass\n");
+        
+        temp_dir
+    }
+    
+    async fn analyzed_files(root: &Path, respect_ignore_files: bool) -> Vec<String> {
+        let mut config = AnalysisConfig::default();
+        config.respect_ignore_files = respect_ignore_files;
+        
+        let mut session = AnalysisSession::with_config(config);
+        let analysis = session.analyze_path(root, false).await.unwrap();
+        
+        let mut files: Vec<String> = analysis.files.iter()
+            .map(|f| f.file_info.path.strip_prefix(root).unwrap().to_string_lossy().replace('\\', "/"))
+            .collect();
+        files.sort();
+        files
+    }
+    
+    #[tokio::test]
+    async fn test_ignore_files_are_honored() {
+        let temp_dir = sample_tree();
+        
+        assert_eq!(analyzed_files(temp_dir.path(), true).await, vec![
+            "app/main.py".to_string(),
+            "other/generated_client.py".to_string(),
+        ]);
+    }
+    
+    #[tokio::test]
+    async fn test_no_ignore_walks_everything() {
+        let temp_dir = sample_tree();
+        
+        assert_eq!(analyzed_files(temp_dir.path(), false).await.len(), 4);
+    }
+}
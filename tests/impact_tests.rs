@@ -0,0 +1,23 @@
+//! Regression tests for analyze-impact over mixed-language directories
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::impact::{ImpactAnalyzer, ImpactConfig};
+    use std::fs;
+    use tempfile::TempDir;
+    
+    /// A C# file next to a Go file must not abort the impact analysis
+    #[tokio::test]
+    async fn test_impact_with_csharp_and_go() {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("Program.cs"), include_str!("../test_samples/sample.cs")).unwrap();
+        fs::write(temp_dir.path().join("main.go"), include_str!("../test_samples/sample.go")).unwrap();
+        
+        let analyzer = ImpactAnalyzer::new(ImpactConfig::default());
+        let result = analyzer.analyze_impact(temp_dir.path()).await.unwrap();
+        
+        assert!(!result.changed_symbols.is_empty());
+        assert!(result.changed_symbols.iter().any(|s| s.file_path.ends_with("Program.cs")));
+        assert!(result.changed_symbols.iter().any(|s| s.file_path.ends_with("main.go")));
+    }
+}
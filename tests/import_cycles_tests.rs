@@ -0,0 +1,41 @@
+//! Tests for `import-cycles`: package-level import cycles over a project
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::importgraph::ImportGraph;
+    use nekocode_core::core::session::AnalysisSession;
+    use std::path::PathBuf;
+    use tempfile::TempDir;
+
+    #[tokio::test]
+    async fn test_go_package_cycle() {
+        let dir = TempDir::new().unwrap();
+        let root = dir.path();
+        for package in ["a", "b", "c"] {
+            std::fs::create_dir(root.join(package)).unwrap();
+        }
+        std::fs::write(root.join("go.mod"), "module example.com/app\n\ngo 1.21\n").unwrap();
+        std::fs::write(root.join("a/a.go"), "package a\n\nimport (\n\t\"fmt\"\n\t\"example.com/app/b\"\n)\n\nfunc A() { fmt.Println(b.B()) }\n").unwrap();
+        std::fs::write(root.join("b/b.go"), "package b\n\nimport \"example.com/app/a\"\n\nfunc B() int { a.A(); return 1 }\n").unwrap();
+        std::fs::write(root.join("c/c.go"), "package c\n\nimport \"example.com/app/a\"\n\nfunc C() { a.A() }\n").unwrap();
+        // An external test package importing its own directory is not a cycle
+        std::fs::write(root.join("c/c_test.go"), "package c_test\n\nimport \"example.com/app/c\"\n\nfunc use() { c.C() }\n").unwrap();
+
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(root, true).await.unwrap();
+        let cycles = ImportGraph::build(&analysis).cycles();
+
+        assert_eq!(cycles.len(), 1);
+        assert_eq!(cycles[0].language, "go");
+        let mut modules = cycles[0].modules.clone();
+        modules.sort();
+        assert_eq!(modules, vec!["example.com/app/a", "example.com/app/b"]);
+
+        let sites: Vec<(&str, &str, PathBuf, u32)> = cycles[0].edges.iter()
+            .map(|e| (e.from.as_str(), e.to.as_str(), e.sites[0].file.clone(), e.sites[0].line))
+            .collect();
+        assert!(sites.contains(&("example.com/app/a", "example.com/app/b", PathBuf::from("a/a.go"), 3)), "{:?}", sites);
+        assert!(sites.contains(&("example.com/app/b", "example.com/app/a", PathBuf::from("b/b.go"), 3)), "{:?}", sites);
+        assert_eq!(sites.len(), 2);
+    }
+}
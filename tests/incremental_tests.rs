@@ -9,9 +9,9 @@ mod tests {
     use std::fs;
     use std::path::PathBuf;
     
-    use nekocode_rust::core::incremental::{ChangeDetector, ChangeType, IncrementalSummary};
-    use nekocode_rust::core::session::SessionManager;
-    use nekocode_rust::commands::session_update::handle_session_update;
+    use nekocode_core::core::incremental::{ChangeDetector, ChangeType, IncrementalSummary};
+    use nekocode_core::core::session::SessionManager;
+    use nekocode_core::commands::session_update::handle_session_update;
     
     /// Test basic change detection functionality
     #[tokio::test]
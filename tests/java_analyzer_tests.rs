@@ -0,0 +1,135 @@
+//! Tests for the tree-sitter based Java analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::java::TreeSitterJavaAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language};
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.java");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterJavaAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.java").await.unwrap()
+    }
+    
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("class {} not found", name))
+    }
+    
+    fn method<'a>(class: &'a ClassInfo, name: &str) -> &'a FunctionInfo {
+        class.methods.iter()
+            .find(|m| m.name == name)
+            .unwrap_or_else(|| panic!("method {} not found in {}", name, class.name))
+    }
+    
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+    
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".java"), Language::Java);
+    }
+    
+    /// Type kinds, inheritance and qualified names of nested types
+    #[tokio::test]
+    async fn test_type_declarations() {
+        let result = analyze(SAMPLE).await;
+        
+        let kind = |name: &str| meta(&class(&result, name).metadata, "type").unwrap_or("").to_string();
+        assert_eq!(kind("Processor"), "interface");
+        assert_eq!(kind("BaseProcessor"), "class");
+        assert_eq!(kind("DataProcessor.Mode"), "enum");
+        assert_eq!(kind("DataProcessor.Pair"), "record");
+        
+        let base = class(&result, "BaseProcessor");
+        assert_eq!(meta(&base.metadata, "interfaces"), Some("Processor"));
+        assert_eq!(meta(&base.metadata, "is_abstract"), Some("true"));
+        assert_eq!(meta(&base.metadata, "annotations"), Some("Deprecated"));
+        
+        let data_processor = class(&result, "DataProcessor");
+        assert_eq!(data_processor.parent_class.as_deref(), Some("BaseProcessor"));
+        assert_eq!(meta(&data_processor.metadata, "modifiers"), Some("public final"));
+        
+        // Static nested vs inner classes
+        let stats = class(&result, "DataProcessor.Stats");
+        assert_eq!(meta(&stats.metadata, "outer_class"), Some("DataProcessor"));
+        assert!(stats.metadata.get("is_inner").is_none());
+        assert_eq!(meta(&class(&result, "DataProcessor.Cursor").metadata, "is_inner"), Some("true"));
+        
+        let mode = class(&result, "DataProcessor.Mode");
+        let constants: Vec<&str> = mode.member_variables.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(constants, vec!["FAST", "SAFE"]);
+        assert_eq!(meta(&method(mode, "isFast").metadata, "visibility"), Some("package"));
+        
+        let pair = class(&result, "DataProcessor.Pair");
+        let components: Vec<(&str, &str)> = pair.member_variables.iter().map(|m| (m.name.as_str(), m.var_type.as_str())).collect();
+        assert_eq!(components, vec![("key", "String"), ("value", "int")]);
+    }
+    
+    /// Modifiers, visibility, annotations and parameters of methods
+    #[tokio::test]
+    async fn test_methods_and_modifiers() {
+        let result = analyze(SAMPLE).await;
+        
+        let interface = class(&result, "Processor");
+        assert_eq!(meta(&method(interface, "processData").metadata, "is_abstract"), Some("true"));
+        assert!(method(interface, "priority").metadata.get("is_abstract").is_none());
+        
+        let data_processor = class(&result, "DataProcessor");
+        let constructor = method(data_processor, "DataProcessor");
+        assert_eq!(meta(&constructor.metadata, "type"), Some("constructor"));
+        assert_eq!(constructor.parameters, vec!["String prefix"]);
+        
+        let process = method(data_processor, "processData");
+        assert_eq!(meta(&process.metadata, "annotations"), Some("Override"));
+        assert_eq!(meta(&process.metadata, "visibility"), Some("public"));
+        assert_eq!(meta(&process.metadata, "return_type"), Some("List<String>"));
+        // for + if + &&
+        assert_eq!(process.complexity.cyclomatic_complexity, 4);
+        
+        let item = method(data_processor, "processItem");
+        assert_eq!(meta(&item.metadata, "visibility"), Some("protected"));
+        
+        let create = method(data_processor, "create");
+        assert_eq!(meta(&create.metadata, "is_static"), Some("true"));
+        assert_eq!(create.parameters, vec!["String... prefixes"]);
+        
+        let stats = class(&result, "DataProcessor.Stats");
+        assert_eq!(meta(&method(stats, "processed").metadata, "visibility"), Some("package"));
+        
+        let fields: Vec<(&str, bool, bool)> = data_processor.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.is_static, m.is_const))
+            .collect();
+        assert_eq!(fields, vec![("prefix", false, true), ("instances", true, false)]);
+        
+        // Top-level function list carries the qualified owner
+        let advance = result.functions.iter().find(|f| f.name == "advance").unwrap();
+        assert_eq!(meta(&advance.metadata, "class_name"), Some("DataProcessor.Cursor"));
+    }
+    
+    /// this / super / static / constructor call references and imports
+    #[tokio::test]
+    async fn test_references_and_imports() {
+        let result = analyze(SAMPLE).await;
+        
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+        
+        assert_eq!(call("processItem").object_name.as_deref(), Some("this"));
+        assert_eq!(call("reset").object_name.as_deref(), Some("super"));
+        assert_eq!(call("log").object_name.as_deref(), Some("Helpers"));
+        assert_eq!(call("create").object_name.as_deref(), Some("DataProcessor"));
+        assert!(call("max").object_name.is_none());
+        assert!(result.function_calls.iter().any(|c| c.function_name == "ArrayList"));
+        
+        let imports: Vec<&str> = result.imports.iter().map(|i| i.module_path.as_str()).collect();
+        assert_eq!(imports, vec!["java.util.ArrayList", "java.util.List", "java.lang.Math.max"]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::JavaImport));
+        assert_eq!(result.imports[2].metadata.get("is_static").map(String::as_str), Some("true"));
+    }
+}
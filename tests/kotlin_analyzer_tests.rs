@@ -0,0 +1,177 @@
+//! Tests for the tree-sitter based Kotlin analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::kotlin::TreeSitterKotlinAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language};
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.kt");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterKotlinAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.kt").await.unwrap()
+    }
+    
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("class {} not found", name))
+    }
+    
+    fn method<'a>(class: &'a ClassInfo, name: &str) -> &'a FunctionInfo {
+        class.methods.iter()
+            .find(|m| m.name == name)
+            .unwrap_or_else(|| panic!("method {} not found in {}", name, class.name))
+    }
+    
+    fn function<'a>(result: &'a AnalysisResult, name: &str) -> &'a FunctionInfo {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+    
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+    
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".kt"), Language::Kotlin);
+        assert_eq!(Language::from_extension(".kts"), Language::Kotlin);
+        assert_eq!(Language::from_name("kotlin"), Some(Language::Kotlin));
+    }
+    
+    /// Type kinds, supertypes, nesting and constructor properties
+    #[tokio::test]
+    async fn test_type_declarations() {
+        let result = analyze(SAMPLE).await;
+        
+        let kind = |name: &str| meta(&class(&result, name).metadata, "type").unwrap_or("").to_string();
+        assert_eq!(kind("Shape"), "interface");
+        assert_eq!(kind("Circle"), "class");
+        assert_eq!(kind("Color"), "enum");
+        assert_eq!(kind("Registry"), "object");
+        
+        let point = class(&result, "Point");
+        assert_eq!(meta(&point.metadata, "is_data"), Some("true"));
+        // `label` has no val/var: a constructor parameter, not a property
+        let properties: Vec<(&str, &str, bool)> = point.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.var_type.as_str(), m.is_const))
+            .collect();
+        assert_eq!(properties, vec![("x", "Double", true), ("y", "Double", false)]);
+        
+        let figure = class(&result, "Figure");
+        assert_eq!(meta(&figure.metadata, "is_sealed"), Some("true"));
+        assert_eq!(meta(&figure.metadata, "interfaces"), Some("Shape"));
+        
+        let circle = class(&result, "Circle");
+        assert_eq!(circle.parent_class.as_deref(), Some("Figure"));
+        assert_eq!(meta(&circle.metadata, "interfaces"), Some("Comparable<Circle>"));
+        
+        let handle = class(&result, "Circle.Handle");
+        assert_eq!(meta(&handle.metadata, "outer_class"), Some("Circle"));
+        assert_eq!(meta(&handle.metadata, "is_inner"), Some("true"));
+        
+        let colors: Vec<&str> = class(&result, "Color").member_variables.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(colors, vec!["RED", "GREEN", "BLUE"]);
+    }
+    
+    /// Companion members are static; visibility defaults to public
+    #[tokio::test]
+    async fn test_companion_and_properties() {
+        let result = analyze(SAMPLE).await;
+        
+        let circle = class(&result, "Circle");
+        assert_eq!(meta(&circle.metadata, "companion_object"), Some("Companion"));
+        
+        let unit = method(circle, "unit");
+        assert_eq!(meta(&unit.metadata, "is_static"), Some("true"));
+        assert!(method(circle, "area").metadata.get("is_static").is_none());
+        assert_eq!(meta(&function(&result, "unit").metadata, "class_name"), Some("Circle"));
+        assert_eq!(meta(&function(&result, "unit").metadata, "is_static"), Some("true"));
+        
+        let members: Vec<(&str, &str, bool)> = circle.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.access_modifier.as_str(), m.is_static))
+            .collect();
+        assert_eq!(members, vec![
+            ("origin", "public", false),
+            ("radius", "private", false),
+            ("name", "public", false),
+            ("scale", "private", false),
+            ("UNIT", "public", true),
+        ]);
+        let scale = circle.member_variables.iter().find(|m| m.name == "scale").unwrap();
+        assert_eq!(meta(&scale.metadata, "binding"), Some("var"));
+        
+        assert_eq!(meta(&result.metadata, "top_level_properties"), Some("DEFAULT_ORIGIN"));
+        assert_eq!(meta(&result.metadata, "package"), Some("com.example.shapes"));
+    }
+    
+    /// Extension functions record their receiver type
+    #[tokio::test]
+    async fn test_extension_functions() {
+        let result = analyze(SAMPLE).await;
+        
+        let distance = function(&result, "distanceTo");
+        assert_eq!(meta(&distance.metadata, "is_extension"), Some("true"));
+        assert_eq!(meta(&distance.metadata, "receiver_type"), Some("Point"));
+        assert_eq!(meta(&distance.metadata, "return_type"), Some("Double"));
+        assert_eq!(distance.parameters, vec!["other: Point"]);
+        assert!(distance.metadata.get("class_name").is_none());
+        
+        let describe = function(&result, "describeOrEmpty");
+        assert_eq!(meta(&describe.metadata, "receiver_type"), Some("Shape?"));
+        
+        // Plain functions have no receiver
+        let classify = function(&result, "classify");
+        assert!(classify.metadata.get("receiver_type").is_none());
+        assert_eq!(meta(&classify.metadata, "visibility"), Some("internal"));
+        assert_eq!(meta(&classify.metadata, "is_suspend"), Some("true"));
+        assert_eq!(meta(&classify.metadata, "return_type"), Some("Map<String, Int>"));
+    }
+    
+    /// Interface members, complexity of if / else-if and when
+    #[tokio::test]
+    async fn test_methods_and_complexity() {
+        let result = analyze(SAMPLE).await;
+        
+        let shape = class(&result, "Shape");
+        assert_eq!(meta(&method(shape, "area").metadata, "is_abstract"), Some("true"));
+        assert!(method(shape, "describe").metadata.get("is_abstract").is_none());
+        
+        let compare = method(class(&result, "Circle"), "compareTo");
+        assert_eq!(compare.complexity.cyclomatic_complexity, 3);
+        // if (+1), else if (+1 flat)
+        assert_eq!(compare.complexity.cognitive_complexity, 2);
+        assert_eq!(meta(&compare.metadata, "modifiers"), Some("override"));
+        
+        // Two non-else when entries and one && inside the trailing lambda
+        assert_eq!(function(&result, "classify").complexity.cyclomatic_complexity, 4);
+    }
+    
+    /// Calls with receivers, trailing lambdas and imports
+    #[tokio::test]
+    async fn test_references_and_imports() {
+        let result = analyze(SAMPLE).await;
+        
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+        
+        assert_eq!(call("add").object_name.as_deref(), Some("shapes"));
+        // Trailing-lambda calls, with and without a receiver
+        assert_eq!(call("forEach").object_name.as_deref(), Some("shapes"));
+        assert!(call("run").object_name.is_none());
+        assert_eq!(call("info").object_name.as_deref(), Some("Log"));
+        assert!(call("sqrt").object_name.is_none());
+        assert!(call("Circle").object_name.is_none());
+        assert!(call("describe").is_method_call);
+        
+        let imports: Vec<&str> = result.imports.iter().map(|i| i.module_path.as_str()).collect();
+        assert_eq!(imports, vec!["kotlin.math.sqrt", "com.example.util.Logger", "com.example.model"]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::KotlinImport));
+        assert_eq!(meta(&result.imports[1].metadata, "alias"), Some("Log"));
+        assert_eq!(result.imports[2].imported_names, vec!["*"]);
+    }
+}
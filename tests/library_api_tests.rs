@@ -0,0 +1,54 @@
+//! Tests for the embedding API (`nekocode_core::analyze_file` / `analyze_dir`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::{analyze_dir, analyze_file, AnalysisConfig, AnalysisResult, Language};
+    
+    #[tokio::test]
+    async fn test_analyze_file_detects_language() {
+        let result = analyze_file("test_samples/sample.go", None).await.unwrap();
+        
+        assert_eq!(result.language, Language::Go);
+        assert!(!result.functions.is_empty());
+    }
+    
+    /// An explicit language wins over the extension
+    #[tokio::test]
+    async fn test_analyze_file_language_hint() {
+        let result = analyze_file("test_samples/sample.go", Some(Language::Go)).await.unwrap();
+        let detected = analyze_file("test_samples/sample.go", None).await.unwrap();
+        
+        let names = |result: &AnalysisResult| result.functions.iter().map(|f| f.name.clone()).collect::<Vec<_>>();
+        assert_eq!(names(&result), names(&detected));
+    }
+    
+    #[tokio::test]
+    async fn test_analyze_dir_respects_options() {
+        let mut options = AnalysisConfig::default();
+        options.enabled_languages = vec![Language::Go];
+        let analysis = analyze_dir("test_samples", &options).await.unwrap();
+        
+        assert!(!analysis.files.is_empty());
+        assert!(analysis.files.iter().all(|file| file.language == Language::Go));
+        
+        // Results stay serializable for callers
+        let json = serde_json::to_value(&analysis.files[0]).unwrap();
+        assert_eq!(json["language"], "go");
+    }
+    
+    /// Every symbol carries the package it was declared in
+    #[tokio::test]
+    async fn test_symbols_carry_namespace() {
+        let result = analyze_file("test_samples/sample.go", None).await.unwrap();
+        
+        assert!(result.functions.iter().all(|f| f.namespace.as_deref() == Some("main")));
+        assert!(result.classes.iter().all(|c| c.namespace.as_deref() == Some("main")));
+        let json = serde_json::to_value(&result.functions[0]).unwrap();
+        assert_eq!(json["namespace"], "main");
+    }
+    
+    #[tokio::test]
+    async fn test_analyze_file_missing() {
+        assert!(analyze_file("test_samples/does_not_exist.go", Some(Language::Go)).await.is_err());
+    }
+}
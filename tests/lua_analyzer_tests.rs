@@ -0,0 +1,138 @@
+//! Tests for the tree-sitter based Lua analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::lua::TreeSitterLuaAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language};
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.lua");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterLuaAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.lua").await.unwrap()
+    }
+    
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("table {} not found", name))
+    }
+    
+    fn function<'a>(result: &'a AnalysisResult, name: &str) -> &'a FunctionInfo {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+    
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+    
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".lua"), Language::Lua);
+        assert_eq!(Language::from_name("lua"), Some(Language::Lua));
+        assert_eq!(Language::from_shebang("#!/usr/bin/env lua5.4"), Language::Lua);
+    }
+    
+    /// Every function form, with the scope recorded as visibility
+    #[tokio::test]
+    async fn test_functions_and_visibility() {
+        let result = analyze(SAMPLE).await;
+        
+        let functions: Vec<(&str, Option<&str>, &str)> = result.functions.iter()
+            .map(|f| (f.name.as_str(), meta(&f.metadata, "class_name"), meta(&f.metadata, "visibility").unwrap_or("")))
+            .collect();
+        assert_eq!(functions, vec![
+            ("clamp", None, "local"),
+            ("new", Some("Inventory"), "module"),
+            ("add", Some("Inventory"), "module"),
+            ("notify", Some("Inventory"), "module"),
+            ("weight", Some("Inventory"), "module"),
+            ("debug_dump", None, "global"),
+            ("describe", Some("Helpers"), "local"),
+        ]);
+        
+        // `:` methods take an implicit self
+        let add = function(&result, "add");
+        assert_eq!(meta(&add.metadata, "is_method"), Some("true"));
+        assert_eq!(meta(&add.metadata, "implicit_self"), Some("true"));
+        assert_eq!(add.parameters, vec!["self", "item", "count"]);
+        
+        let new = function(&result, "new");
+        assert_eq!(meta(&new.metadata, "is_static"), Some("true"));
+        assert_eq!(new.parameters, vec!["owner", "..."]);
+        assert!(new.params[1].variadic);
+        
+        assert_eq!(meta(&function(&result, "weight").metadata, "is_assigned"), Some("true"));
+    }
+    
+    /// Tables holding functions are reported like classes; the returned one is the module
+    #[tokio::test]
+    async fn test_module_tables() {
+        let result = analyze(SAMPLE).await;
+        
+        let inventory = class(&result, "Inventory");
+        assert_eq!(meta(&inventory.metadata, "type"), Some("module"));
+        assert_eq!(meta(&inventory.metadata, "visibility"), Some("module"));
+        assert_eq!(meta(&inventory.metadata, "metatable"), Some("true"));
+        let methods: Vec<&str> = inventory.methods.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(methods, vec!["new", "add", "notify", "weight"]);
+        let fields: Vec<(&str, &str)> = inventory.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.var_type.as_str()))
+            .collect();
+        assert_eq!(fields, vec![("version", "string"), ("max_slots", "number"), ("__index", "")]);
+        
+        let helpers = class(&result, "Helpers");
+        assert_eq!(meta(&helpers.metadata, "type"), Some("table"));
+        assert_eq!(meta(&helpers.metadata, "visibility"), Some("local"));
+    }
+    
+    /// if + elseif, loops and `and` each add a path
+    #[tokio::test]
+    async fn test_complexity() {
+        let result = analyze(SAMPLE).await;
+        
+        let clamp = function(&result, "clamp");
+        assert_eq!(clamp.complexity.cyclomatic_complexity, 3);
+        // if (+1), elseif (+1 flat)
+        assert_eq!(clamp.complexity.cognitive_complexity, 2);
+        
+        let add = function(&result, "add");
+        // `count or 1` (+1), for (+1), if (+1), and (+1)
+        assert_eq!(add.complexity.cyclomatic_complexity, 5);
+        assert_eq!(add.complexity.max_nesting_depth, 2);
+    }
+    
+    /// `module.func()` and `obj:method()` calls, `require` imports
+    #[tokio::test]
+    async fn test_references_and_imports() {
+        let result = analyze(SAMPLE).await;
+        
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+        
+        // self:notify() inside Inventory:add resolves to the Inventory table
+        let notify = call("notify");
+        assert!(notify.is_method_call);
+        assert_eq!(notify.object_name.as_deref(), Some("self"));
+        assert_eq!(notify.receiver_type.as_deref(), Some("Inventory"));
+        
+        let info = call("info");
+        assert!(!info.is_method_call);
+        assert_eq!(info.object_name.as_deref(), Some("log"));
+        assert_eq!(call("insert").object_name.as_deref(), Some("table"));
+        assert!(call("clamp").object_name.is_none());
+        
+        let imports: Vec<(&str, Option<&str>, Vec<String>)> = result.imports.iter()
+            .map(|i| (i.module_path.as_str(), i.alias.as_deref(), i.imported_names.clone()))
+            .collect();
+        assert_eq!(imports, vec![
+            ("cjson", Some("json"), vec!["json".to_string()]),
+            ("engine.log", None, vec!["log".to_string()]),
+        ]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::LuaRequire));
+    }
+}
@@ -0,0 +1,57 @@
+//! Tests for custom metric plugins (`custom` on function results)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::go::TreeSitterGoAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::metrics::plugin::ParameterCountPlugin;
+    use nekocode_core::{register_metric_plugin, FunctionNode, Language, MetricPlugin, MetricValue};
+    
+    /// Counts `return` statements by walking the function node
+    struct ReturnCount;
+    
+    impl MetricPlugin for ReturnCount {
+        fn name(&self) -> &str {
+            "return_count"
+        }
+        
+        fn measure(&self, function: &FunctionNode) -> Vec<(String, MetricValue)> {
+            if function.language != Language::Go {
+                return Vec::new();
+            }
+            let mut count = 0;
+            let mut stack = vec![function.node];
+            while let Some(node) = stack.pop() {
+                count += (node.kind() == "return_statement") as usize;
+                let mut cursor = node.walk();
+                stack.extend(node.children(&mut cursor));
+            }
+            vec![
+                ("return_count".to_string(), count.into()),
+                ("kind".to_string(), function.node.kind().into()),
+            ]
+        }
+    }
+    
+    #[tokio::test]
+    async fn test_plugins_fill_custom() {
+        register_metric_plugin(ParameterCountPlugin);
+        register_metric_plugin(ReturnCount);
+        
+        let source = "package main\n\ntype Box struct{}\n\nfunc clamp(v, lo, hi int) int {\n\tif v < lo {\n\t\treturn lo\n\t}\n\treturn v\n}\n\nfunc (b *Box) Open() {}\n";
+        let mut analyzer = TreeSitterGoAnalyzer::new().unwrap();
+        let result = analyzer.analyze(source, "main.go").await.unwrap();
+        
+        let custom = |name: &str, key: &str| result.functions.iter()
+            .find(|f| f.name == name)
+            .and_then(|f| f.custom.get(key).cloned());
+        assert_eq!(custom("clamp", "parameter_count"), Some(MetricValue::Number(3.0)));
+        assert_eq!(custom("Open", "parameter_count"), Some(MetricValue::Number(0.0)));
+        assert_eq!(custom("clamp", "return_count"), Some(MetricValue::Number(2.0)));
+        assert_eq!(custom("clamp", "kind"), Some(MetricValue::Text("function_declaration".to_string())));
+        assert_eq!(custom("Open", "kind"), Some(MetricValue::Text("method_declaration".to_string())));
+        
+        let json = serde_json::to_value(&result.functions[0]).unwrap();
+        assert!(json["custom"]["return_count"].is_number());
+    }
+}
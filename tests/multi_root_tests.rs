@@ -0,0 +1,90 @@
+//! Tests for analyzing several root paths as one project
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::AnalysisConfig;
+    use std::fs;
+    use std::path::{Path, PathBuf};
+    use tempfile::TempDir;
+    
+    fn write(root: &Path, relative: &str, content: &str) {
+        let path = root.join(relative);
+        fs::create_dir_all(path.parent().unwrap()).unwrap();
+        fs::write(path, content).unwrap();
+    }
+    
+    /// lib/util.py, app/main.py calling util(), scripts/run.py
+    fn sample_tree() -> TempDir {
+        let temp_dir = TempDir::new().unwrap();
+        let root = temp_dir.path();
+        
+        write(root, "lib/util.py", "def util():\n    return 1\n");
+        write(root, "app/main.py", "def main():\n    return util()\n");
+        write(root, "scripts/run.py", "def run():\n    pass\n");
+        
+        temp_dir
+    }
+    
+    fn relative_files(root: &Path, files: impl Iterator<Item = PathBuf>) -> Vec<String> {
+        let mut files: Vec<String> = files
+            .map(|f| f.strip_prefix(root).unwrap().to_string_lossy().into_owned())
+            .collect();
+        files.sort();
+        files
+    }
+    
+    #[tokio::test]
+    async fn test_roots_analyzed_as_one_project() {
+        let temp_dir = sample_tree();
+        let root = temp_dir.path();
+        let paths = vec![root.join("app"), root.join("lib")];
+        
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let analysis = session.analyze_paths(&paths, false).await.unwrap();
+        
+        assert_eq!(analysis.roots, paths);
+        assert_eq!(analysis.directory_path, root);
+        assert_eq!(
+            relative_files(root, analysis.files.iter().map(|f| f.file_info.path.clone())),
+            vec!["app/main.py".to_string(), "lib/util.py".to_string()]
+        );
+        
+        // The call from app/ resolves to the function under lib/
+        let util = analysis.files.iter()
+            .flat_map(|f| f.functions.iter())
+            .find(|f| f.name == "util")
+            .unwrap();
+        assert_eq!(util.fan_in, 1);
+    }
+    
+    #[tokio::test]
+    async fn test_overlapping_roots_deduplicated() {
+        let temp_dir = sample_tree();
+        let root = temp_dir.path();
+        // The whole tree, one of its directories, and a file inside it
+        let paths = vec![root.to_path_buf(), root.join("lib"), root.join("app/../lib/util.py")];
+        
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let analysis = session.analyze_paths(&paths, false).await.unwrap();
+        
+        assert_eq!(
+            relative_files(root, analysis.files.iter().map(|f| f.file_info.path.clone())),
+            vec!["app/main.py".to_string(), "lib/util.py".to_string(), "scripts/run.py".to_string()]
+        );
+        assert_eq!(analysis.summary.total_files, 3);
+    }
+    
+    #[tokio::test]
+    async fn test_single_root_unchanged() {
+        let temp_dir = sample_tree();
+        let root = temp_dir.path();
+        
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let analysis = session.analyze_paths(&[root.join("lib")], false).await.unwrap();
+        
+        assert!(analysis.roots.is_empty());
+        assert_eq!(analysis.directory_path, root.join("lib"));
+        assert_eq!(analysis.files.len(), 1);
+    }
+}
@@ -0,0 +1,71 @@
+//! Tests for Jupyter notebooks analyzed as Python
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::Language;
+    use std::fs;
+    use tempfile::TempDir;
+    
+    const NOTEBOOK: &str = r##"{
+ "cells": [
+  {"cell_type": "markdown", "metadata": {}, "source": ["# Exploration\n", "def in_markdown(): pass\n"]},
+  {"cell_type": "code", "execution_count": 1, "metadata": {},
+   "outputs": [{"output_type": "stream", "name": "stdout", "text": ["def in_output(): pass\n"]}],
+   "source": ["!pip install pandas\n", "import pandas as pd\n", "from helpers import clean\n"]},
+  {"cell_type": "code", "execution_count": 2, "metadata": {}, "outputs": [],
+   "source": ["class Loader:\n", "    def load(self, path):\n", "        return clean(pd.read_csv(path))\n"]},
+  {"cell_type": "markdown", "metadata": {}, "source": "Scoring"},
+  {"cell_type": "code", "execution_count": 3, "metadata": {}, "outputs": [],
+   "source": ["def score(x):\n", "    if x > 0:\n", "        return 1\n", "    return 0\n"]}
+ ],
+ "metadata": {"kernelspec": {"display_name": "Python 3", "language": "python", "name": "python3"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}"##;
+    
+    #[tokio::test]
+    async fn test_notebook_code_cells_are_analyzed() {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("explore.ipynb"), NOTEBOOK).unwrap();
+        fs::write(temp_dir.path().join("helpers.py"), "def clean(frame):\n    return frame\n").unwrap();
+        
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+        assert!(analysis.errors.is_empty(), "{:?}", analysis.errors);
+        assert_eq!(analysis.files.len(), 2);
+        
+        let notebook = analysis.files.iter().find(|f| f.file_info.name == "explore.ipynb").unwrap();
+        assert_eq!(notebook.language, Language::Python);
+        assert!(!notebook.partial, "{:?}", notebook.parse_errors);
+        assert_eq!(notebook.metadata.get("notebook_code_cells").map(String::as_str), Some("3"));
+        
+        // Markdown and outputs are not code
+        let names: Vec<&str> = notebook.functions.iter().map(|f| f.name.as_str()).collect();
+        assert!(!names.contains(&"in_markdown") && !names.contains(&"in_output"), "{:?}", names);
+        
+        let score = notebook.functions.iter().find(|f| f.name == "score").unwrap();
+        assert_eq!(score.start_line, 7);
+        assert_eq!(score.complexity.cyclomatic_complexity, 2);
+        assert_eq!(score.metadata.get("notebook_location").map(String::as_str), Some("explore.ipynb#cell5:line1"));
+        
+        let loader = notebook.classes.iter().find(|c| c.name == "Loader").unwrap();
+        assert_eq!(loader.metadata.get("notebook_location").map(String::as_str), Some("explore.ipynb#cell3:line1"));
+        let load = loader.methods.iter().find(|m| m.name == "load").unwrap();
+        assert_eq!(load.metadata.get("notebook_location").map(String::as_str), Some("explore.ipynb#cell3:line2"));
+        
+        assert!(notebook.imports.iter().any(|i| i.module_path == "pandas"));
+    }
+    
+    #[tokio::test]
+    async fn test_invalid_notebook_is_reported() {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("broken.ipynb"), "{\"cells\": [").unwrap();
+        
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+        assert!(analysis.files.is_empty());
+        assert_eq!(analysis.errors.len(), 1);
+        assert!(analysis.errors[0].message.contains("Invalid notebook JSON"));
+    }
+}
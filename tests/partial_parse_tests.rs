@@ -0,0 +1,58 @@
+//! Tests for files analyzed from a syntax tree with errors (`partial`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use std::fs;
+    use tempfile::TempDir;
+    
+    const BROKEN: &str = "def good():
+    return 1
+
+def broken(:
+    pass
+
+def also_good():
+    return 2
+";
+    
+    #[tokio::test]
+    async fn test_broken_function_keeps_the_rest() {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("broken.py"), BROKEN).unwrap();
+        fs::write(temp_dir.path().join("clean.py"), "def clean():\n    pass\n").unwrap();
+        
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_path(temp_dir.path(), false).await.unwrap();
+        assert!(analysis.errors.is_empty());
+        assert_eq!(analysis.summary.partial_files, 1);
+        
+        let broken = analysis.files.iter().find(|f| f.file_info.name == "broken.py").unwrap();
+        assert!(broken.partial);
+        assert!(!broken.parse_errors.is_empty());
+        assert!(broken.parse_errors.iter().all(|e| (4..=5).contains(&e.line)));
+        
+        // Functions outside the error range are still extracted
+        let names: Vec<&str> = broken.functions.iter().map(|f| f.name.as_str()).collect();
+        assert!(names.contains(&"good"), "{:?}", names);
+        assert!(names.contains(&"also_good"), "{:?}", names);
+        
+        let clean = analysis.files.iter().find(|f| f.file_info.name == "clean.py").unwrap();
+        assert!(!clean.partial);
+        assert!(clean.parse_errors.is_empty());
+    }
+    
+    #[tokio::test]
+    async fn test_partial_flag_is_serialized() {
+        let temp_dir = TempDir::new().unwrap();
+        let path = temp_dir.path().join("broken.py");
+        fs::write(&path, BROKEN).unwrap();
+        
+        let session = AnalysisSession::new();
+        let result = session.analyze_file(&path).await.unwrap();
+        let json = serde_json::to_value(&result).unwrap();
+        assert_eq!(json["partial"], true);
+        assert!(json["parse_errors"][0]["line"].as_u64().is_some());
+        assert!(json["parse_errors"][0]["end_line"].as_u64().is_some());
+    }
+}
@@ -0,0 +1,211 @@
+//! Tests for the tree-sitter based PHP analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::php::TreeSitterPhpAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language, MemberVariable};
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.php");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterPhpAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.php").await.unwrap()
+    }
+    
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("{} not found", name))
+    }
+    
+    fn method<'a>(class: &'a ClassInfo, name: &str) -> &'a FunctionInfo {
+        class.methods.iter()
+            .find(|m| m.name == name)
+            .unwrap_or_else(|| panic!("method {} not found in {}", name, class.name))
+    }
+    
+    fn member<'a>(class: &'a ClassInfo, name: &str) -> &'a MemberVariable {
+        class.member_variables.iter()
+            .find(|m| m.name == name)
+            .unwrap_or_else(|| panic!("member {} not found in {}", name, class.name))
+    }
+    
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+    
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".php"), Language::Php);
+        assert_eq!(Language::from_name("php"), Some(Language::Php));
+    }
+    
+    /// Namespace-qualified names, type kinds and resolved supertypes
+    #[tokio::test]
+    async fn test_type_declarations() {
+        let result = analyze(SAMPLE).await;
+        
+        let kinds: Vec<(&str, &str)> = result.classes.iter()
+            .map(|c| (c.name.as_str(), meta(&c.metadata, "type").unwrap_or("")))
+            .collect();
+        assert_eq!(kinds, vec![
+            ("App\\Models\\Identifiable", "interface"),
+            ("App\\Models\\HasTimestamps", "trait"),
+            ("App\\Models\\Loggable", "trait"),
+            ("App\\Models\\Model", "class"),
+            ("App\\Models\\User", "class"),
+            ("App\\Models\\Status", "enum"),
+        ]);
+        assert_eq!(meta(&result.metadata, "namespaces"), Some("App\\Models"));
+        
+        // Aliased and absolute names resolve to their fully qualified form
+        let identifiable = class(&result, "App\\Models\\Identifiable");
+        assert_eq!(meta(&identifiable.metadata, "interfaces"), Some("JsonSerializable, App\\Contracts\\Repository"));
+        assert_eq!(meta(&identifiable.metadata, "simple_name"), Some("Identifiable"));
+        assert_eq!(meta(&identifiable.metadata, "namespace"), Some("App\\Models"));
+        
+        let model = class(&result, "App\\Models\\Model");
+        assert_eq!(meta(&model.metadata, "is_abstract"), Some("true"));
+        assert_eq!(model.implements, vec!["App\\Models\\Identifiable"]);
+        
+        let user = class(&result, "App\\Models\\User");
+        assert_eq!(user.parent_class.as_deref(), Some("App\\Models\\Model"));
+        assert_eq!(meta(&user.metadata, "is_final"), Some("true"));
+        
+        let status = class(&result, "App\\Models\\Status");
+        assert_eq!(meta(&status.metadata, "backing_type"), Some("string"));
+        assert_eq!(status.implements, vec!["JsonSerializable"]);
+        let cases: Vec<&str> = status.member_variables.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(cases, vec!["Active", "Banned"]);
+        assert_eq!(meta(&member(status, "Banned").metadata, "raw_value"), Some("'banned'"));
+    }
+    
+    /// Trait `use` inside a class body is reported as an embedding
+    #[tokio::test]
+    async fn test_trait_use() {
+        let result = analyze(SAMPLE).await;
+        
+        let user = class(&result, "App\\Models\\User");
+        assert_eq!(user.embeds, vec!["App\\Models\\HasTimestamps", "App\\Models\\Loggable"]);
+        // Trait `use` is not a namespace import
+        assert!(result.imports.iter().all(|i| !i.module_path.ends_with("Loggable")));
+        
+        let touch = result.functions.iter().find(|f| f.name == "touch").unwrap();
+        assert_eq!(meta(&touch.metadata, "class_name"), Some("App\\Models\\HasTimestamps"));
+    }
+    
+    /// Properties, constants and promoted constructor parameters
+    #[tokio::test]
+    async fn test_members() {
+        let result = analyze(SAMPLE).await;
+        
+        let model = class(&result, "App\\Models\\Model");
+        let members: Vec<(&str, &str, &str, bool, bool)> = model.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.var_type.as_str(), m.access_modifier.as_str(), m.is_static, m.is_const))
+            .collect();
+        assert_eq!(members, vec![
+            ("TABLE", "", "public", true, true),
+            ("count", "int", "protected", true, false),
+        ]);
+        
+        let user = class(&result, "App\\Models\\User");
+        let members: Vec<(&str, &str, &str)> = user.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.var_type.as_str(), m.access_modifier.as_str()))
+            .collect();
+        assert_eq!(members, vec![
+            ("roles", "array", "private"),
+            ("name", "string", "private"),
+            ("age", "int", "public"),
+        ]);
+        let age = member(user, "age");
+        assert_eq!(meta(&age.metadata, "promoted"), Some("true"));
+        assert_eq!(meta(&age.metadata, "is_readonly"), Some("true"));
+    }
+    
+    /// Visibility and modifiers, parameters and complexity
+    #[tokio::test]
+    async fn test_methods_and_complexity() {
+        let result = analyze(SAMPLE).await;
+        
+        let model = class(&result, "App\\Models\\Model");
+        let visibility: Vec<(&str, &str)> = model.methods.iter()
+            .map(|m| (m.name.as_str(), meta(&m.metadata, "visibility").unwrap_or("")))
+            .collect();
+        assert_eq!(visibility, vec![
+            ("table", "protected"),
+            ("save", "public"),
+            ("persist", "private"),
+            ("validate", "protected"),
+        ]);
+        assert_eq!(meta(&method(model, "table").metadata, "is_abstract"), Some("true"));
+        
+        let id = method(class(&result, "App\\Models\\Identifiable"), "id");
+        assert_eq!(meta(&id.metadata, "is_interface_method"), Some("true"));
+        assert_eq!(meta(&id.metadata, "is_abstract"), Some("true"));
+        assert_eq!(id.returns, vec!["int"]);
+        
+        let user = class(&result, "App\\Models\\User");
+        assert_eq!(meta(&method(user, "__construct").metadata, "type"), Some("constructor"));
+        let create = method(user, "create");
+        assert_eq!(meta(&create.metadata, "is_static"), Some("true"));
+        assert_eq!(create.parameters, vec!["string $name"]);
+        assert_eq!(create.params[0].name, "name");
+        
+        let log = method(class(&result, "App\\Models\\Loggable"), "log");
+        assert!(log.params[1].variadic);
+        assert_eq!(log.params[1].param_type, "...string");
+        
+        // if, foreach, nested if and || each add a path
+        let save = method(user, "save");
+        assert_eq!(save.complexity.cyclomatic_complexity, 5);
+        assert_eq!(save.complexity.cognitive_complexity, 5);
+        assert_eq!(save.complexity.max_nesting_depth, 2);
+        
+        let format = result.functions.iter().find(|f| f.name == "format_user").unwrap();
+        assert!(format.metadata.get("class_name").is_none());
+        assert_eq!(meta(&format.metadata, "qualified_name"), Some("App\\Models\\format_user"));
+        assert_eq!(format.parameters, vec!["User $user", "?LoggerInterface $logger = null"]);
+    }
+    
+    /// `$this->`, `self::`, `static::` and `parent::` calls resolve to the right class
+    #[tokio::test]
+    async fn test_references_and_imports() {
+        let result = analyze(SAMPLE).await;
+        
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+        
+        assert_eq!(call("touch").receiver_type.as_deref(), Some("App\\Models\\User"));
+        assert_eq!(call("touch").object_name.as_deref(), Some("$this"));
+        assert_eq!(call("hash").receiver_type.as_deref(), Some("App\\Models\\User"));
+        assert_eq!(call("register").receiver_type.as_deref(), Some("App\\Models\\User"));
+        assert_eq!(call("now").receiver_type.as_deref(), Some("App\\Support\\Clock"));
+        assert_eq!(call("info").object_name.as_deref(), Some("$logger"));
+        assert!(call("info").receiver_type.is_none());
+        assert!(call("slugify").object_name.is_none());
+        
+        // parent::save() inside User targets Model
+        let parent_save = result.function_calls.iter()
+            .find(|c| c.function_name == "save" && c.object_name.as_deref() == Some("parent"))
+            .unwrap();
+        assert_eq!(parent_save.receiver_type.as_deref(), Some("App\\Models\\Model"));
+        
+        let imports: Vec<(&str, Option<&str>)> = result.imports.iter()
+            .map(|i| (i.module_path.as_str(), i.alias.as_deref()))
+            .collect();
+        assert_eq!(imports, vec![
+            ("App\\Contracts\\Repository", Some("Repo")),
+            ("Psr\\Log\\LoggerInterface", None),
+            ("App\\Support\\Cache", None),
+            ("App\\Support\\Clock", Some("SystemClock")),
+            ("App\\Support\\slugify", None),
+            ("App\\Support\\VERSION", None),
+        ]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::PhpUse));
+        assert_eq!(result.imports[3].imported_names, vec!["SystemClock"]);
+        assert_eq!(meta(&result.imports[4].metadata, "import_kind"), Some("function"));
+        assert_eq!(meta(&result.imports[5].metadata, "import_kind"), Some("const"));
+    }
+}
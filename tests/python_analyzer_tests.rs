@@ -0,0 +1,155 @@
+//! Tests for the tree-sitter based Python analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::python::TreeSitterPythonAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::AnalysisResult;
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterPythonAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.py").await.unwrap()
+    }
+    
+    fn nesting_of(result: &AnalysisResult, name: &str) -> u32 {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+            .complexity.max_nesting_depth
+    }
+    
+    /// Same rules as Go: if in for in for is 3, elif chains stay flat
+    #[tokio::test]
+    async fn test_max_nesting_depth() {
+        let source = r#"
+def grid(rows):
+    for row in rows:
+        for v in row:
+            if v > 0:
+                print(v)
+
+
+def chain(n):
+    if n == 1:
+        return "one"
+    elif n == 2:
+        return "two"
+    else:
+        return "many"
+
+
+def handler(items):
+    try:
+        sorted(items, key=lambda item: item.size if item else 0)
+    except ValueError:
+        while items:
+            items.pop()
+
+
+def flat():
+    return 1
+"#;
+        let result = analyze(source).await;
+        
+        assert_eq!(nesting_of(&result, "grid"), 3);
+        assert_eq!(nesting_of(&result, "chain"), 1);
+        // except > while; lambda > conditional expression
+        assert_eq!(nesting_of(&result, "handler"), 2);
+        assert_eq!(nesting_of(&result, "flat"), 0);
+    }
+    
+    /// `self.m()`, `ClassName.m()` and imported names resolve; decorators are recorded
+    #[tokio::test]
+    async fn test_call_resolution_and_decorators() {
+        let source = r#"from app.utils import slugify as slug, normalize
+import app.models as models
+
+
+class Article:
+    @staticmethod
+    def build(title):
+        return Article.clean(title)
+
+    @classmethod
+    def clean(cls, title):
+        return cls.strip(normalize(title))
+
+    @property
+    def url(self):
+        return self.path() + slug(self.title)
+
+    def path(self):
+        return models.prefix()
+
+
+@login_required
+def view(request):
+    return Article.build(request.title).url
+"#;
+        let result = analyze(source).await;
+        
+        let calls: Vec<(String, Option<&str>, Option<&str>)> = result.function_calls.iter()
+            .map(|c| (c.full_name(), c.receiver_type.as_deref(), c.module.as_deref()))
+            .collect();
+        assert_eq!(calls, vec![
+            ("Article.clean".to_string(), Some("Article"), None),
+            ("cls.strip".to_string(), Some("Article"), None),
+            ("normalize".to_string(), None, Some("app.utils")),
+            ("self.path".to_string(), Some("Article"), None),
+            // `slug` is `slugify` of app.utils
+            ("slugify".to_string(), None, Some("app.utils")),
+            ("prefix".to_string(), None, Some("app.models")),
+            ("Article.build".to_string(), Some("Article"), None),
+        ]);
+        
+        let function = |name: &str| result.functions.iter().find(|f| f.name == name).unwrap();
+        assert_eq!(function("build").metadata.get("class_name").map(String::as_str), Some("Article"));
+        assert_eq!(function("build").metadata.get("is_static").map(String::as_str), Some("true"));
+        assert_eq!(function("clean").metadata.get("is_classmethod").map(String::as_str), Some("true"));
+        assert_eq!(function("url").metadata.get("is_property").map(String::as_str), Some("true"));
+        assert_eq!(function("view").metadata.get("decorators").map(String::as_str), Some("@login_required"));
+        assert!(function("view").metadata.get("class_name").is_none());
+        
+        let imports: Vec<(&str, Vec<&str>, Option<&str>)> = result.imports.iter()
+            .map(|i| (i.module_path.as_str(), i.imported_names.iter().map(String::as_str).collect(), i.alias.as_deref()))
+            .collect();
+        assert_eq!(imports, vec![
+            ("app.utils", vec!["slugify", "normalize"], None),
+            ("app.models", vec![], Some("models")),
+        ]);
+    }
+    
+    /// Local functions keep their name, lambdas become `Parent$funcN`; neither is listed at top level
+    #[tokio::test]
+    async fn test_nested_functions() {
+        let source = r#"
+def process_data(items):
+    def valid(item):
+        if item and item.size > 0:
+            return True
+        return False
+
+    ordered = sorted(items, key=lambda item: item.size)
+    return [i for i in filter(lambda i: valid(i), ordered)]
+"#;
+        let result = analyze(source).await;
+        let names: Vec<&str> = result.functions.iter().map(|f| f.name.as_str()).collect();
+        assert_eq!(names, vec!["process_data"]);
+        
+        let process = &result.functions[0];
+        let children: Vec<(&str, u32, bool)> = process.children.iter()
+            .map(|c| (c.name.as_str(), c.start_line, c.nested))
+            .collect();
+        assert_eq!(children, vec![
+            ("valid", 3, true),
+            ("process_data$func1", 8, true),
+            ("process_data$func2", 9, true),
+        ]);
+        assert_eq!(process.children[0].parameters, vec!["item"]);
+        assert_eq!(process.children[1].metadata.get("is_lambda").map(String::as_str), Some("true"));
+        
+        // The if and its `and` score in the local function, and nested one deeper in the parent
+        assert_eq!(process.children[0].complexity.cognitive_complexity, 2);
+        assert_eq!(process.complexity.cognitive_complexity, 3);
+    }
+}
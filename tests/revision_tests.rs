@@ -0,0 +1,97 @@
+//! Tests for analyzing a path at a git revision (`analyze --rev`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::diff::SnapshotDiff;
+    use nekocode_core::core::session::AnalysisSession;
+    use std::path::Path;
+    use std::process::Command;
+    use tempfile::TempDir;
+
+    fn git(dir: &Path, args: &[&str]) -> String {
+        let output = Command::new("git")
+            .args(["-c", "user.name=test", "-c", "user.email=test@example.com"])
+            .args(args)
+            .current_dir(dir)
+            .output()
+            .unwrap();
+        assert!(output.status.success(), "git {:?}: {}", args, String::from_utf8_lossy(&output.stderr));
+        String::from_utf8_lossy(&output.stdout).trim().to_string()
+    }
+
+    /// A repository whose first commit has `pkg/util.go` and `pkg/main.go`; the
+    /// working tree has since deleted util.go and rewritten main.go
+    fn repository() -> (TempDir, String) {
+        let dir = TempDir::new().unwrap();
+        let root = dir.path();
+        git(root, &["init", "-q"]);
+        std::fs::create_dir(root.join("pkg")).unwrap();
+        std::fs::write(root.join("pkg/util.go"), "package pkg\n\nfunc helper(x int) int {\n\treturn x + 1\n}\n").unwrap();
+        std::fs::write(root.join("pkg/main.go"), "package pkg\n\nfunc run() int {\n\treturn helper(1)\n}\n").unwrap();
+        git(root, &["add", "."]);
+        git(root, &["commit", "-q", "-m", "first"]);
+        let commit = git(root, &["rev-parse", "--short", "HEAD"]);
+
+        std::fs::remove_file(root.join("pkg/util.go")).unwrap();
+        std::fs::write(root.join("pkg/main.go"), "package pkg\n\nfunc start() {}\n").unwrap();
+        git(root, &["commit", "-q", "-am", "second"]);
+        (dir, commit)
+    }
+
+    #[tokio::test]
+    async fn test_analyze_tree_at_revision() {
+        let (dir, commit) = repository();
+        let pkg = dir.path().join("pkg");
+
+        let mut session = AnalysisSession::new();
+        let analysis = session.analyze_revision(&pkg, &commit, false).await.unwrap();
+
+        assert_eq!(analysis.directory_path, pkg);
+        assert_eq!(analysis.revision.as_deref(), Some(commit.as_str()));
+        let mut paths: Vec<String> = analysis.files.iter().map(|f| f.file_info.path.display().to_string()).collect();
+        paths.sort();
+        assert_eq!(paths, vec![
+            format!("{}@{}", pkg.join("main.go").display(), commit),
+            format!("{}@{}", pkg.join("util.go").display(), commit),
+        ]);
+
+        // Historical content, not the working tree
+        let main = analysis.files.iter().find(|f| f.file_info.name.starts_with("main.go")).unwrap();
+        assert_eq!(main.functions[0].name, "run");
+        let helper = analysis.files.iter().flat_map(|f| &f.functions).find(|f| f.name == "helper").unwrap();
+        assert_eq!(helper.fan_in, 1);
+    }
+
+    #[tokio::test]
+    async fn test_deleted_file_and_diff() {
+        let (dir, commit) = repository();
+        let util = dir.path().join("pkg/util.go");
+
+        // Deleted from the working tree, still readable at the old commit
+        let mut session = AnalysisSession::new();
+        let old = session.analyze_revision(&util, &commit, false).await.unwrap();
+        assert_eq!(old.files.len(), 1);
+        assert_eq!(old.files[0].functions[0].name, "helper");
+
+        // Did not exist at the new commit
+        let err = AnalysisSession::new().analyze_revision(&util, "HEAD", false).await.unwrap_err();
+        assert!(format!("{:#}", err).contains("does not exist at revision HEAD"), "{:#}", err);
+
+        let err = AnalysisSession::new().analyze_revision(&util, "no-such-ref", false).await.unwrap_err();
+        assert!(format!("{:#}", err).contains("Unknown revision `no-such-ref`"), "{:#}", err);
+
+        // Snapshots of two revisions line up by path in `diff`
+        let pkg = dir.path().join("pkg");
+        let snapshots = TempDir::new().unwrap();
+        for (name, rev) in [("old.json", commit.as_str()), ("new.json", "HEAD")] {
+            let analysis = AnalysisSession::new().analyze_revision(&pkg, rev, false).await.unwrap();
+            std::fs::write(snapshots.path().join(name), serde_json::to_string(&analysis).unwrap()).unwrap();
+        }
+        let old = SnapshotDiff::load_snapshot(&snapshots.path().join("old.json")).unwrap();
+        let new = SnapshotDiff::load_snapshot(&snapshots.path().join("new.json")).unwrap();
+        assert!(old.iter().chain(&new).any(|f| f.file_info.path == Path::new("main.go")));
+        let diff = SnapshotDiff::compare(&old, &new);
+        let text = diff.to_text();
+        assert!(text.contains("helper"), "{}", text);
+    }
+}
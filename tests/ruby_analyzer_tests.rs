@@ -0,0 +1,85 @@
+//! Tests for the tree-sitter based Ruby analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::ruby::TreeSitterRubyAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ImportType, Language};
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.rb");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterRubyAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.rb").await.unwrap()
+    }
+    
+    /// Detection by extension and shebang
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".rb"), Language::Ruby);
+        assert_eq!(Language::from_shebang("#!/usr/bin/env ruby"), Language::Ruby);
+        assert_eq!(Language::from_shebang("#!/usr/bin/ruby -w"), Language::Ruby);
+        assert_eq!(Language::from_shebang("#!/bin/sh"), Language::Unknown);
+        assert_eq!(Language::from_shebang("puts 'hi'"), Language::Unknown);
+    }
+    
+    /// Classes, modules, superclass and attr_* members
+    #[tokio::test]
+    async fn test_classes_and_modules() {
+        let result = analyze(SAMPLE).await;
+        
+        let module = result.classes.iter().find(|c| c.name == "Neko").unwrap();
+        assert_eq!(module.metadata.get("type").map(String::as_str), Some("module"));
+        
+        let class = result.classes.iter().find(|c| c.name == "DataProcessor").unwrap();
+        assert_eq!(class.metadata.get("type").map(String::as_str), Some("class"));
+        assert_eq!(class.parent_class.as_deref(), Some("BaseProcessor"));
+        
+        let members: Vec<&str> = class.member_variables.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(members, vec!["prefix", "count", "seen"]);
+        
+        let methods: Vec<&str> = class.methods.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(methods, vec!["create", "initialize", "process_data", "process_item"]);
+    }
+    
+    /// `def self.foo` is reported as a static method
+    #[tokio::test]
+    async fn test_static_methods() {
+        let result = analyze(SAMPLE).await;
+        
+        let create = result.functions.iter().find(|f| f.name == "create").unwrap();
+        assert_eq!(create.metadata.get("is_static").map(String::as_str), Some("true"));
+        assert_eq!(create.metadata.get("class_name").map(String::as_str), Some("DataProcessor"));
+        
+        let process_data = result.functions.iter().find(|f| f.name == "process_data").unwrap();
+        assert!(process_data.metadata.get("is_static").is_none());
+        assert_eq!(process_data.parameters, vec!["data".to_string()]);
+    }
+    
+    /// require / require_relative and call references
+    #[tokio::test]
+    async fn test_imports_and_calls() {
+        let result = analyze(SAMPLE).await;
+        
+        let paths: Vec<&str> = result.imports.iter().map(|i| i.module_path.as_str()).collect();
+        assert_eq!(paths, vec!["set", "helpers"]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::RubyRequire));
+        
+        let calls: Vec<String> = result.function_calls.iter().map(|c| c.full_name()).collect();
+        assert!(calls.contains(&"process_item".to_string()));
+        assert!(calls.contains(&"processor.process_data".to_string()));
+        assert!(!calls.iter().any(|c| c == "attr_accessor" || c == "require"));
+    }
+    
+    /// Complexity counts the `if` modifier and the `||`
+    #[tokio::test]
+    async fn test_cyclomatic_complexity() {
+        let result = analyze(SAMPLE).await;
+        
+        let process_item = result.functions.iter().find(|f| f.name == "process_item").unwrap();
+        assert_eq!(process_item.complexity.cyclomatic_complexity, 3);
+        
+        let initialize = result.functions.iter().find(|f| f.name == "initialize").unwrap();
+        assert_eq!(initialize.complexity.cyclomatic_complexity, 1);
+    }
+}
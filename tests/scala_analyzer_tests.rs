@@ -0,0 +1,194 @@
+//! Tests for the tree-sitter based Scala analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::scala::TreeSitterScalaAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::hierarchy::TypeHierarchy;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language};
+
+    const SAMPLE: &str = include_str!("../test_samples/sample.scala");
+
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterScalaAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.scala").await.unwrap()
+    }
+
+    fn class<'a>(result: &'a AnalysisResult, name: &str, kind: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name && meta(&c.metadata, "type") == Some(kind))
+            .unwrap_or_else(|| panic!("{} {} not found", kind, name))
+    }
+
+    fn function<'a>(result: &'a AnalysisResult, name: &str) -> &'a FunctionInfo {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".scala"), Language::Scala);
+        assert_eq!(Language::from_extension(".sc"), Language::Scala);
+        assert_eq!(Language::from_name("scala"), Some(Language::Scala));
+        assert_eq!(Language::from_shebang("#!/usr/bin/env scala"), Language::Scala);
+    }
+
+    /// Traits, case classes, companions and nested objects as `Outer.Inner`
+    #[tokio::test]
+    async fn test_type_definitions() {
+        let result = analyze(SAMPLE).await;
+        assert_eq!(meta(&result.metadata, "package"), Some("com.example.spark"));
+
+        let kinds: Vec<(&str, &str)> = result.classes.iter()
+            .map(|c| (c.name.as_str(), meta(&c.metadata, "type").unwrap_or("")))
+            .collect();
+        assert_eq!(kinds, vec![
+            ("Transformer", "trait"),
+            ("Logging", "trait"),
+            ("Job", "class"),
+            ("Record", "case_class"),
+            ("WordCount", "class"),
+            ("WordCount", "object"),
+            ("WordCount.Defaults", "object"),
+            ("Mode", "enum"),
+        ]);
+
+        let defaults = class(&result, "WordCount.Defaults", "object");
+        assert_eq!(meta(&defaults.metadata, "outer_class"), Some("WordCount"));
+        assert_eq!(meta(&defaults.metadata, "simple_name"), Some("Defaults"));
+
+        assert_eq!(meta(&class(&result, "WordCount", "object").metadata, "is_companion"), Some("true"));
+        assert_eq!(meta(&class(&result, "WordCount", "class").metadata, "companion_object"), Some("WordCount"));
+        assert_eq!(meta(&class(&result, "Job", "class").metadata, "is_abstract"), Some("true"));
+
+        let constants: Vec<&str> = class(&result, "Mode", "enum").member_variables.iter()
+            .map(|m| m.name.as_str())
+            .collect();
+        assert_eq!(constants, vec!["Batch", "Streaming"]);
+    }
+
+    /// `extends` is the parent, `with` types are implemented; both reach the hierarchy graph
+    #[tokio::test]
+    async fn test_supertypes_and_hierarchy() {
+        let result = analyze(SAMPLE).await;
+
+        let word_count = class(&result, "WordCount", "class");
+        assert_eq!(word_count.parent_class.as_deref(), Some("Job"));
+        assert_eq!(word_count.implements, vec!["Transformer[DataFrame]", "Serializable"]);
+        assert_eq!(class(&result, "Job", "class").parent_class.as_deref(), Some("Logging"));
+
+        let hierarchy = TypeHierarchy::from_files(&[result]);
+        let supertypes_of = |id_prefix: &str| -> Vec<(String, String)> {
+            hierarchy.edges.iter()
+                .filter(|e| e.from.starts_with(id_prefix))
+                .map(|e| (e.to.clone(), e.relation.clone()))
+                .collect()
+        };
+        // The companion object shares the class name, so ids carry the file
+        assert_eq!(supertypes_of("WordCount@"), vec![
+            ("Job".to_string(), "extends".to_string()),
+            ("Transformer".to_string(), "implements".to_string()),
+            ("Serializable".to_string(), "implements".to_string()),
+        ]);
+        assert_eq!(supertypes_of("Job"), vec![("Logging".to_string(), "extends".to_string())]);
+        assert_eq!(hierarchy.node("Logging").unwrap().descendants, 2);
+    }
+
+    /// Constructor vals, case class parameters, `val` / `var` members and their modifiers
+    #[tokio::test]
+    async fn test_members() {
+        let result = analyze(SAMPLE).await;
+        let members = |class: &ClassInfo| -> Vec<(String, String, bool)> {
+            class.member_variables.iter()
+                .map(|m| (m.name.clone(), m.access_modifier.clone(), m.is_const))
+                .collect()
+        };
+
+        assert_eq!(members(class(&result, "Job", "class")), vec![("id".to_string(), "public".to_string(), true)]);
+        assert_eq!(members(class(&result, "Record", "case_class")), vec![
+            ("key".to_string(), "public".to_string(), true),
+            ("value".to_string(), "public".to_string(), true),
+        ]);
+        // Plain constructor parameters are not members
+        assert_eq!(members(class(&result, "WordCount", "class")), vec![
+            ("processed".to_string(), "private".to_string(), false),
+            ("threshold".to_string(), "public".to_string(), true),
+        ]);
+        let threshold = &class(&result, "WordCount", "class").member_variables[1];
+        assert_eq!(meta(&threshold.metadata, "lazy"), Some("true"));
+        assert_eq!(members(class(&result, "Logging", "trait")), vec![("logPrefix".to_string(), "protected".to_string(), true)]);
+    }
+
+    /// Methods carry their qualified owner; abstract `def`s have no body
+    #[tokio::test]
+    async fn test_methods() {
+        let result = analyze(SAMPLE).await;
+
+        let methods: Vec<&str> = class(&result, "WordCount", "class").methods.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(methods, vec!["name", "transform", "run", "classify"]);
+        let abstract_methods: Vec<&str> = class(&result, "Transformer", "trait").methods.iter()
+            .filter(|m| meta(&m.metadata, "is_abstract") == Some("true"))
+            .map(|m| m.name.as_str())
+            .collect();
+        assert_eq!(abstract_methods, vec!["name", "transform"]);
+
+        let describe = function(&result, "describe");
+        assert_eq!(meta(&describe.metadata, "class_name"), Some("WordCount.Defaults"));
+        assert_eq!(meta(&function(&result, "keyOf").metadata, "visibility"), Some("private"));
+
+        let classify = function(&result, "classify");
+        let params: Vec<(&str, &str)> = classify.params.iter().map(|p| (p.name.as_str(), p.param_type.as_str())).collect();
+        assert_eq!(params, vec![("count", "Int"), ("verbose", "Boolean")]);
+        assert_eq!(meta(&classify.metadata, "return_type"), Some("String"));
+        let word_count = class(&result, "WordCount", "class");
+        assert_eq!(meta(&word_count.methods[1].metadata, "modifiers"), Some("override"));
+        assert_eq!(meta(&word_count.methods[2].metadata, "modifiers"), None);
+    }
+
+    /// Cases (but not `case _`), guards and && each add a path
+    #[tokio::test]
+    async fn test_complexity() {
+        let result = analyze(SAMPLE).await;
+
+        assert_eq!(class(&result, "WordCount", "class").methods[3].complexity.cyclomatic_complexity, 7);
+        assert_eq!(class(&result, "WordCount", "class").methods[1].complexity.cyclomatic_complexity, 1);
+    }
+
+    /// Method, constructor and infix calls; `this.` and object receivers resolve their type
+    #[tokio::test]
+    async fn test_references_and_imports() {
+        let result = analyze(SAMPLE).await;
+
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+
+        assert!(!call("transform").is_method_call);
+        assert_eq!(call("text").object_name.as_deref(), Some("spark.read"));
+        assert_eq!(call("log").receiver_type.as_deref(), Some("WordCount"));
+        assert_eq!(call("describe").receiver_type.as_deref(), Some("WordCount.Defaults"));
+        assert_eq!(call("WordCount").line_number, 53);
+
+        // records map keyOf
+        let map = call("map");
+        assert!(map.is_method_call);
+        assert_eq!(map.object_name.as_deref(), Some("records"));
+        assert!(result.function_calls.iter().all(|c| c.function_name != ">="));
+
+        let imports: Vec<(&str, Vec<String>)> = result.imports.iter()
+            .map(|i| (i.module_path.as_str(), i.imported_names.clone()))
+            .collect();
+        assert_eq!(imports, vec![
+            ("org.apache.spark.sql", vec!["DataFrame".to_string(), "SparkSession".to_string()]),
+            ("org.apache.spark.sql.functions", vec!["*".to_string()]),
+            ("scala.collection.mutable", vec!["mutable".to_string()]),
+        ]);
+        assert_eq!(meta(&result.imports[0].metadata, "aliases"), Some("SparkSession=>Session"));
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::ScalaImport));
+    }
+}
@@ -0,0 +1,48 @@
+//! Tests for analyzing in-memory source (`analyze --stdin --lang ...`)
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::{AnalysisConfig, Language};
+    use std::path::Path;
+    
+    /// The buffer is analyzed as-is under the synthetic name, at the same lines as on disk
+    #[tokio::test]
+    async fn test_source_matches_file_analysis() {
+        let source = include_str!("../test_samples/sample.go");
+        let session = AnalysisSession::with_config(AnalysisConfig::default());
+        
+        let from_buffer = session.analyze_source(source, Path::new("<stdin>"), Language::Go).await.unwrap();
+        let from_file = session.analyze_file(Path::new("test_samples/sample.go")).await.unwrap();
+        
+        assert_eq!(from_buffer.file_info.name, "<stdin>");
+        assert_eq!(from_buffer.language, Language::Go);
+        assert_eq!(from_buffer.file_info.size_bytes, source.len() as u64);
+        
+        let lines = |result: &nekocode_core::core::types::AnalysisResult| {
+            result.functions.iter().map(|f| (f.name.clone(), f.start_line, f.end_line)).collect::<Vec<_>>()
+        };
+        assert!(!from_buffer.functions.is_empty());
+        assert_eq!(lines(&from_buffer), lines(&from_file));
+    }
+    
+    /// Unsaved buffers keep leading blank lines, so offsets do not shift
+    #[tokio::test]
+    async fn test_leading_blank_lines_preserved() {
+        let source = "\n\ndef helper(x):\n    return x + 1\n";
+        let session = AnalysisSession::with_config(AnalysisConfig::default());
+        let result = session.analyze_source(source, Path::new("<stdin>"), Language::Python).await.unwrap();
+        
+        let helper = result.functions.iter().find(|f| f.name == "helper").unwrap();
+        assert_eq!(helper.start_line, 3);
+    }
+    
+    #[test]
+    fn test_language_names() {
+        assert_eq!(Language::from_name("go"), Some(Language::Go));
+        assert_eq!(Language::from_name("Golang"), Some(Language::Go));
+        assert_eq!(Language::from_name("c#"), Some(Language::CSharp));
+        assert_eq!(Language::from_name("ts"), Some(Language::TypeScript));
+        assert_eq!(Language::from_name("cobol"), None);
+    }
+}
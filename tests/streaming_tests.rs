@@ -0,0 +1,53 @@
+//! Tests for streaming (NDJSON) analysis
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::AnalysisConfig;
+    use std::fs;
+    use tempfile::TempDir;
+    
+    fn sample_tree() -> TempDir {
+        let temp_dir = TempDir::new().unwrap();
+        fs::write(temp_dir.path().join("main.go"), include_str!("../test_samples/sample.go")).unwrap();
+        fs::write(temp_dir.path().join("util.py"), "def helper(x):\n    return x + 1\n").unwrap();
+        fs::write(temp_dir.path().join("app.rb"), include_str!("../test_samples/sample.rb")).unwrap();
+        temp_dir
+    }
+    
+    /// Every file reaches the sink once and the summary matches a collected run
+    #[tokio::test]
+    async fn test_streamed_summary_matches_collected() {
+        let temp_dir = sample_tree();
+        
+        let mut streamed_files = Vec::new();
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let streamed = session.analyze_path_streaming(temp_dir.path(), false, |result| {
+            streamed_files.push(result.file_info.name.clone());
+            Ok(())
+        }).await.unwrap();
+        streamed_files.sort();
+        
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let collected = session.analyze_path(temp_dir.path(), false).await.unwrap();
+        
+        assert_eq!(streamed_files, vec!["app.rb", "main.go", "util.py"]);
+        assert!(streamed.errors.is_empty());
+        assert_eq!(streamed.summary.total_files, 3);
+        assert_eq!(streamed.summary.total_functions, collected.summary.total_functions);
+        assert_eq!(streamed.summary.total_lines, collected.summary.total_lines);
+    }
+    
+    /// An error from the sink stops the run
+    #[tokio::test]
+    async fn test_sink_error_aborts() {
+        let temp_dir = sample_tree();
+        
+        let mut session = AnalysisSession::with_config(AnalysisConfig::default());
+        let outcome = session.analyze_path_streaming(temp_dir.path(), false, |_| {
+            anyhow::bail!("broken pipe")
+        }).await;
+        
+        assert!(outcome.is_err());
+    }
+}
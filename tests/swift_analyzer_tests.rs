@@ -0,0 +1,212 @@
+//! Tests for the tree-sitter based Swift analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::swift::TreeSitterSwiftAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language, MemberVariable};
+    
+    const SAMPLE: &str = include_str!("../test_samples/sample.swift");
+    
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterSwiftAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.swift").await.unwrap()
+    }
+    
+    /// First declaration (or extension) of a type
+    fn class<'a>(result: &'a AnalysisResult, name: &str, kind: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name && meta(&c.metadata, "type") == Some(kind))
+            .unwrap_or_else(|| panic!("{} {} not found", kind, name))
+    }
+    
+    fn method<'a>(class: &'a ClassInfo, name: &str) -> &'a FunctionInfo {
+        class.methods.iter()
+            .find(|m| m.name == name)
+            .unwrap_or_else(|| panic!("method {} not found in {}", name, class.name))
+    }
+    
+    fn member<'a>(class: &'a ClassInfo, name: &str) -> &'a MemberVariable {
+        class.member_variables.iter()
+            .find(|m| m.name == name)
+            .unwrap_or_else(|| panic!("member {} not found in {}", name, class.name))
+    }
+    
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+    
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".swift"), Language::Swift);
+        assert_eq!(Language::from_name("swift"), Some(Language::Swift));
+    }
+    
+    /// Type kinds, supertypes, raw types and nesting
+    #[tokio::test]
+    async fn test_type_declarations() {
+        let result = analyze(SAMPLE).await;
+        
+        let kinds: Vec<(&str, &str)> = result.classes.iter()
+            .map(|c| (c.name.as_str(), meta(&c.metadata, "type").unwrap_or("")))
+            .collect();
+        assert_eq!(kinds, vec![
+            ("Drawable", "protocol"),
+            ("Sizable", "protocol"),
+            ("Shape", "class"),
+            ("Circle", "class"),
+            ("Tag", "struct"),
+            ("Direction", "enum"),
+            ("Direction.Offset", "struct"),
+            ("TagError", "enum"),
+            ("Tag", "extension"),
+        ]);
+        
+        let circle = class(&result, "Circle", "class");
+        assert_eq!(circle.parent_class.as_deref(), Some("Shape"));
+        assert_eq!(circle.implements, vec!["Sizable"]);
+        assert_eq!(meta(&circle.metadata, "visibility"), Some("public"));
+        assert_eq!(meta(&circle.metadata, "is_final"), Some("true"));
+        
+        assert_eq!(meta(&class(&result, "Sizable", "protocol").metadata, "interfaces"), Some("Drawable"));
+        
+        let direction = class(&result, "Direction", "enum");
+        assert_eq!(meta(&direction.metadata, "raw_type"), Some("String"));
+        assert_eq!(direction.implements, vec!["CaseIterable"]);
+        let cases: Vec<&str> = direction.member_variables.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(cases, vec!["north", "south", "east"]);
+        assert_eq!(meta(&member(direction, "east").metadata, "raw_value"), Some("\"E\""));
+        
+        let offset = class(&result, "Direction.Offset", "struct");
+        assert_eq!(meta(&offset.metadata, "outer_class"), Some("Direction"));
+        
+        let error = class(&result, "TagError", "enum");
+        assert_eq!(meta(&member(error, "tooLong").metadata, "associated_values"), Some("(Int)"));
+    }
+    
+    /// `extension Tag: Drawable` credits the conformance to Tag itself
+    #[tokio::test]
+    async fn test_extension_conformance() {
+        let result = analyze(SAMPLE).await;
+        
+        let extension = class(&result, "Tag", "extension");
+        assert_eq!(meta(&extension.metadata, "extended_type"), Some("Tag"));
+        assert_eq!(extension.implements, vec!["Drawable"]);
+        assert_eq!(meta(&member(extension, "title").metadata, "is_computed"), Some("true"));
+        
+        let tag = class(&result, "Tag", "struct");
+        assert_eq!(tag.implements, vec!["Hashable", "Drawable"]);
+        
+        assert_eq!(class(&result, "Drawable", "protocol").implementors, vec!["Tag"]);
+        assert_eq!(class(&result, "Sizable", "protocol").implementors, vec!["Circle"]);
+        
+        // Methods of an extension belong to the extended type
+        let draw = result.functions.iter()
+            .find(|f| f.name == "draw" && f.start_line > extension.start_line)
+            .unwrap();
+        assert_eq!(meta(&draw.metadata, "class_name"), Some("Tag"));
+    }
+    
+    /// Stored, computed and static properties; protocol requirements
+    #[tokio::test]
+    async fn test_properties() {
+        let result = analyze(SAMPLE).await;
+        
+        let circle = class(&result, "Circle", "class");
+        let members: Vec<(&str, &str, &str, bool)> = circle.member_variables.iter()
+            .map(|m| (m.name.as_str(), m.var_type.as_str(), m.access_modifier.as_str(), m.is_static))
+            .collect();
+        assert_eq!(members, vec![
+            ("unit", "", "public", true),
+            ("scale", "Double", "internal", false),
+            ("radius", "Double", "internal", false),
+            ("title", "String", "public", false),
+            ("diameter", "Double", "internal", false),
+        ]);
+        
+        let unit = member(circle, "unit");
+        assert!(unit.is_const);
+        assert_eq!(meta(&unit.metadata, "binding"), Some("let"));
+        // private(set) restricts only the setter
+        assert_eq!(meta(&member(circle, "scale").metadata, "modifiers"), Some("private(set)"));
+        
+        let title = member(circle, "title");
+        assert_eq!(meta(&title.metadata, "accessors"), Some("get"));
+        assert!(title.is_const);
+        let diameter = member(circle, "diameter");
+        assert_eq!(meta(&diameter.metadata, "accessors"), Some("get set"));
+        assert!(!diameter.is_const);
+        
+        let drawable = class(&result, "Drawable", "protocol");
+        let requirements: Vec<(&str, &str)> = drawable.member_variables.iter()
+            .map(|m| (m.name.as_str(), meta(&m.metadata, "accessors").unwrap_or("")))
+            .collect();
+        assert_eq!(requirements, vec![("title", "get"), ("scale", "get set")]);
+        let draw = method(drawable, "draw");
+        assert_eq!(meta(&draw.metadata, "is_abstract"), Some("true"));
+        assert_eq!(draw.returns, vec!["String"]);
+    }
+    
+    /// Initializers, static / class methods, parameters and complexity
+    #[tokio::test]
+    async fn test_methods_and_complexity() {
+        let result = analyze(SAMPLE).await;
+        
+        let shape = class(&result, "Shape", "class");
+        let names: Vec<&str> = shape.methods.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(names, vec!["init", "deinit"]);
+        assert_eq!(meta(&method(shape, "init").metadata, "type"), Some("constructor"));
+        
+        let circle = class(&result, "Circle", "class");
+        let make = method(circle, "make");
+        assert_eq!(meta(&make.metadata, "static_kind"), Some("class"));
+        assert_eq!(make.parameters, vec!["_ values: Double..."]);
+        assert!(make.params[0].variadic);
+        assert_eq!(make.params[0].name, "values");
+        assert!(method(circle, "area").metadata.get("is_static").is_none());
+        assert_eq!(meta(&method(circle, "draw").metadata, "visibility"), Some("public"));
+        
+        let compare = method(circle, "compare");
+        assert_eq!(compare.complexity.cyclomatic_complexity, 3);
+        // if (+1), else if (+1 flat)
+        assert_eq!(compare.complexity.cognitive_complexity, 2);
+        assert_eq!(compare.parameters, vec!["to other: Circle"]);
+        
+        let tag = class(&result, "Tag", "struct");
+        let parse = method(tag, "parse");
+        assert_eq!(meta(&parse.metadata, "static_kind"), Some("static"));
+        assert_eq!(meta(&parse.metadata, "throws"), Some("true"));
+        // guard (+1) and && (+1)
+        assert_eq!(parse.complexity.cyclomatic_complexity, 3);
+        assert_eq!(parse.complexity.cognitive_complexity, 2);
+        assert_eq!(meta(&method(tag, "rename").metadata, "is_mutating"), Some("true"));
+        
+        let render = result.functions.iter().find(|f| f.name == "render").unwrap();
+        assert!(render.is_async);
+        assert_eq!(render.parameters, vec!["_ items: [Drawable]", "separator: String"]);
+        assert_eq!(render.returns, vec!["String"]);
+        assert!(render.metadata.get("class_name").is_none());
+    }
+    
+    /// Calls with receivers, trailing closures and imports
+    #[tokio::test]
+    async fn test_references_and_imports() {
+        let result = analyze(SAMPLE).await;
+        
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+        
+        assert_eq!(call("map").object_name.as_deref(), Some("items"));
+        assert_eq!(call("draw").object_name.as_deref(), Some("item"));
+        assert_eq!(call("joined").object_name.as_deref(), Some("parts"));
+        assert!(call("Circle").object_name.is_none());
+        assert!(call("print").object_name.is_none());
+        
+        let imports: Vec<&str> = result.imports.iter().map(|i| i.module_path.as_str()).collect();
+        assert_eq!(imports, vec!["Foundation", "Shapes.Point"]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::SwiftImport));
+        assert_eq!(result.imports[1].imported_names, vec!["Point"]);
+        assert_eq!(meta(&result.imports[1].metadata, "import_kind"), Some("struct"));
+    }
+}
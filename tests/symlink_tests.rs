@@ -0,0 +1,68 @@
+//! Tests for symlink handling during directory walks
+
+#[cfg(all(test, unix))]
+mod tests {
+    use nekocode_core::core::session::AnalysisSession;
+    use nekocode_core::core::types::AnalysisConfig;
+    use std::fs;
+    use std::os::unix::fs::symlink;
+    use std::path::Path;
+    use tempfile::TempDir;
+    
+    fn write(root: &Path, relative: &str, content: &str) {
+        let path = root.join(relative);
+        fs::create_dir_all(path.parent().unwrap()).unwrap();
+        fs::write(path, content).unwrap();
+    }
+    
+    /// app/main.py, app/loop -> app, shared -> outside/lib, alias.py -> app/main.py
+    fn sample_tree() -> (TempDir, TempDir) {
+        let temp_dir = TempDir::new().unwrap();
+        let outside = TempDir::new().unwrap();
+        let root = temp_dir.path();
+        
+        write(root, "app/main.py", "def main():\n    pass\n");
+        write(outside.path(), "lib/util.py", "def util():\n    pass\n");
+        // A directory symlink pointing back at its parent
+        symlink(root.join("app"), root.join("app/loop")).unwrap();
+        symlink(outside.path().join("lib"), root.join("shared")).unwrap();
+        symlink(root.join("app/main.py"), root.join("alias.py")).unwrap();
+        symlink(outside.path().join("lib/util.py"), root.join("util_link.py")).unwrap();
+        
+        (temp_dir, outside)
+    }
+    
+    fn discovered_files(root: &Path, follow_symlinks: bool) -> Vec<String> {
+        let mut config = AnalysisConfig::default();
+        config.follow_symlinks = follow_symlinks;
+        
+        let session = AnalysisSession::with_config(config);
+        let mut files: Vec<String> = session.discover_files(root).unwrap().iter()
+            .map(|f| f.strip_prefix(root).unwrap().to_string_lossy().into_owned())
+            .collect();
+        files.sort();
+        files
+    }
+    
+    #[test]
+    fn test_directory_symlinks_not_followed_by_default() {
+        let (temp_dir, _outside) = sample_tree();
+        
+        // alias.py duplicates app/main.py; util_link.py is the only path to util.py
+        assert_eq!(discovered_files(temp_dir.path(), false), vec![
+            "app/main.py".to_string(),
+            "util_link.py".to_string(),
+        ]);
+    }
+    
+    #[test]
+    fn test_follow_symlinks_visits_each_directory_once() {
+        let (temp_dir, _outside) = sample_tree();
+        
+        // Terminates despite app/loop, and util.py is reported once
+        let files = discovered_files(temp_dir.path(), true);
+        assert_eq!(files.len(), 2);
+        assert!(files.contains(&"app/main.py".to_string()));
+        assert!(files.iter().any(|f| f.ends_with("util.py")));
+    }
+}
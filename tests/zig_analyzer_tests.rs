@@ -0,0 +1,190 @@
+//! Tests for the tree-sitter based Zig analyzer
+
+#[cfg(test)]
+mod tests {
+    use nekocode_core::analyzers::zig::TreeSitterZigAnalyzer;
+    use nekocode_core::analyzers::traits::LanguageAnalyzer;
+    use nekocode_core::core::types::{AnalysisResult, ClassInfo, FunctionInfo, ImportType, Language};
+    use nekocode_core::core::visibility::{is_public_class, is_public_method};
+
+    const SAMPLE: &str = include_str!("../test_samples/sample.zig");
+
+    async fn analyze(content: &str) -> AnalysisResult {
+        let mut analyzer = TreeSitterZigAnalyzer::new().unwrap();
+        analyzer.analyze(content, "sample.zig").await.unwrap()
+    }
+
+    fn class<'a>(result: &'a AnalysisResult, name: &str) -> &'a ClassInfo {
+        result.classes.iter()
+            .find(|c| c.name == name)
+            .unwrap_or_else(|| panic!("container {} not found", name))
+    }
+
+    fn function<'a>(result: &'a AnalysisResult, name: &str) -> &'a FunctionInfo {
+        result.functions.iter()
+            .find(|f| f.name == name)
+            .unwrap_or_else(|| panic!("function {} not found", name))
+    }
+
+    fn meta<'a>(metadata: &'a std::collections::HashMap<String, String>, key: &str) -> Option<&'a str> {
+        metadata.get(key).map(String::as_str)
+    }
+
+    #[test]
+    fn test_language_detection() {
+        assert_eq!(Language::from_extension(".zig"), Language::Zig);
+        assert_eq!(Language::from_name("zig"), Some(Language::Zig));
+    }
+
+    /// Every `fn`, container functions with their owner and `pub` as visibility
+    #[tokio::test]
+    async fn test_functions_and_visibility() {
+        let result = analyze(SAMPLE).await;
+
+        let functions: Vec<(&str, Option<&str>, &str)> = result.functions.iter()
+            .map(|f| (f.name.as_str(), meta(&f.metadata, "class_name"), meta(&f.metadata, "visibility").unwrap_or("")))
+            .collect();
+        assert_eq!(functions, vec![
+            ("init", Some("Point"), "public"),
+            ("length", Some("Point"), "public"),
+            ("scale", Some("Point"), "private"),
+            ("area", Some("Shape"), "public"),
+            ("List", None, "public"),
+            ("first", Some("List"), "public"),
+            ("max", None, "public"),
+            ("helper", None, "private"),
+            ("main", None, "public"),
+        ]);
+
+        assert!(is_public_method(Language::Zig, function(&result, "init")));
+        assert!(!is_public_method(Language::Zig, function(&result, "helper")));
+    }
+
+    /// A container function taking the container first is a method, any other is static
+    #[tokio::test]
+    async fn test_methods() {
+        let result = analyze(SAMPLE).await;
+
+        let init = function(&result, "init");
+        assert_eq!(meta(&init.metadata, "is_static"), Some("true"));
+        assert_eq!(meta(&init.metadata, "is_method"), None);
+        assert_eq!(init.returns, vec!["Point"]);
+
+        // self: Self / self: *Self / shape: Shape / self: @This()
+        for name in ["length", "scale", "area", "first"] {
+            assert_eq!(meta(&function(&result, name).metadata, "is_method"), Some("true"), "{}", name);
+        }
+        assert_eq!(meta(&function(&result, "area").metadata, "receiver_name"), Some("shape"));
+
+        let methods: Vec<&str> = class(&result, "Point").methods.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(methods, vec!["init", "length", "scale"]);
+    }
+
+    /// struct / union / enum containers, nested ones as `Outer.Inner`, with their fields
+    #[tokio::test]
+    async fn test_containers() {
+        let result = analyze(SAMPLE).await;
+
+        let containers: Vec<(&str, &str, &str)> = result.classes.iter()
+            .map(|c| (c.name.as_str(), meta(&c.metadata, "type").unwrap_or(""), meta(&c.metadata, "visibility").unwrap_or("")))
+            .collect();
+        assert_eq!(containers, vec![
+            ("Point", "struct", "public"),
+            ("Point.Polar", "struct", "public"),
+            ("Shape", "union", "private"),
+            ("Color", "enum", "public"),
+            ("List", "struct", "public"),
+        ]);
+        assert!(!is_public_class(Language::Zig, class(&result, "Shape"), None));
+
+        let polar = class(&result, "Point.Polar");
+        assert_eq!(meta(&polar.metadata, "outer_class"), Some("Point"));
+        assert_eq!(meta(&polar.metadata, "simple_name"), Some("Polar"));
+
+        let fields: Vec<(&str, &str)> = class(&result, "Point").member_variables.iter()
+            .map(|m| (m.name.as_str(), m.var_type.as_str()))
+            .collect();
+        assert_eq!(fields, vec![("x", "f32"), ("y", "f32")]);
+        assert_eq!(meta(&class(&result, "Point").member_variables[1].metadata, "has_default"), Some("true"));
+
+        let tags: Vec<&str> = class(&result, "Color").member_variables.iter().map(|m| m.name.as_str()).collect();
+        assert_eq!(tags, vec!["red", "green", "blue"]);
+
+        // The struct returned by a type function is named after it
+        assert_eq!(meta(&class(&result, "List").metadata, "type_function"), Some("true"));
+    }
+
+    /// comptime parameters and type-returning functions
+    #[tokio::test]
+    async fn test_comptime() {
+        let result = analyze(SAMPLE).await;
+
+        let max = function(&result, "max");
+        assert_eq!(meta(&max.metadata, "comptime"), Some("true"));
+        assert_eq!(meta(&max.metadata, "comptime_params"), Some("T"));
+        assert_eq!(max.params[0].param_type, "type");
+        assert_eq!(max.parameters, vec!["comptime T: type", "a: T", "b: T"]);
+
+        let list = function(&result, "List");
+        assert_eq!(meta(&list.metadata, "returns_type"), Some("true"));
+        assert_eq!(meta(&list.metadata, "comptime"), Some("true"));
+
+        assert_eq!(meta(&function(&result, "helper").metadata, "comptime"), None);
+    }
+
+    /// `@import` bindings, module-qualified calls and calls on containers
+    #[tokio::test]
+    async fn test_imports_and_calls() {
+        let result = analyze(SAMPLE).await;
+
+        let imports: Vec<(&str, Vec<String>)> = result.imports.iter()
+            .map(|i| (i.module_path.as_str(), i.imported_names.clone()))
+            .collect();
+        assert_eq!(imports, vec![
+            ("std", vec!["std".to_string()]),
+            ("geo.zig", vec!["geo".to_string()]),
+            ("std", vec!["print".to_string()]),
+        ]);
+        assert!(result.imports.iter().all(|i| i.import_type == ImportType::ZigImport));
+        assert_eq!(meta(&result.imports[2].metadata, "member"), Some("debug.print"));
+
+        let call = |name: &str| result.function_calls.iter()
+            .find(|c| c.function_name == name)
+            .unwrap_or_else(|| panic!("call {} not found", name));
+
+        // geo.circleArea(r): a function of geo.zig, not a method
+        let circle_area = call("circleArea");
+        assert_eq!(circle_area.module.as_deref(), Some("geo.zig"));
+        assert!(!circle_area.is_method_call);
+        assert_eq!(call("sqrt").module.as_deref(), Some("std"));
+
+        // geo.Rect.init(): a container function of the imported file
+        let rect_init = result.function_calls.iter()
+            .find(|c| c.function_name == "init" && c.module.is_some())
+            .unwrap();
+        assert_eq!(rect_init.receiver_type.as_deref(), Some("Rect"));
+
+        let point_init = result.function_calls.iter()
+            .find(|c| c.function_name == "init" && c.module.is_none())
+            .unwrap();
+        assert_eq!(point_init.receiver_type.as_deref(), Some("Point"));
+
+        assert!(!call("helper").is_method_call);
+        assert_eq!(call("scale").object_name.as_deref(), Some("p"));
+    }
+
+    #[tokio::test]
+    async fn test_complexity() {
+        let result = analyze(SAMPLE).await;
+
+        // for + if
+        let helper = function(&result, "helper");
+        assert_eq!(helper.complexity.cyclomatic_complexity, 3);
+        assert_eq!(helper.complexity.cognitive_complexity, 3);
+
+        // if / else with `and`
+        let max = function(&result, "max");
+        assert_eq!(max.complexity.cyclomatic_complexity, 3);
+        assert_eq!(max.complexity.cognitive_complexity, 3);
+    }
+}